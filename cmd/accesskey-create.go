@@ -0,0 +1,144 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var accesskeyCreateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "access-key",
+		Usage: "set an access key instead of a random one",
+	},
+	cli.StringFlag{
+		Name:  "secret-key",
+		Usage: "set a secret key instead of a random one",
+	},
+	cli.StringFlag{
+		Name:  "policy",
+		Usage: "path to a JSON policy file restricting the access key further",
+	},
+	cli.StringFlag{
+		Name:  "name",
+		Usage: "friendly name for the access key",
+	},
+	cli.StringFlag{
+		Name:  "description",
+		Usage: "description for the access key",
+	},
+	cli.StringFlag{
+		Name:  "expiry",
+		Usage: "time of expiration for the access key",
+	},
+}
+
+var accesskeyCreateCmd = cli.Command{
+	Name:         "create",
+	Usage:        "create a new access key for the current user",
+	Action:       mainAccesskeyCreate,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(accesskeyCreateFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS
+
+  The access key inherits the permissions of the user configured for ALIAS,
+  no admin privileges are needed.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Create a new access key for an application, with a name and description.
+     {{.Prompt}} {{.HelpName}} myminio --name uploader --description "nightly upload scripts"
+
+  2. Create a new access key which is restricted by a policy and expires at the end of the year.
+     {{.Prompt}} {{.HelpName}} myminio --policy /tmp/read-only-reports.json --expiry 2023-12-31
+`,
+}
+
+// checkAccesskeyCreateSyntax - validate all the passed arguments
+func checkAccesskeyCreateSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+}
+
+// accesskeyCredentials returns the keys passed, random ones for those
+// which are empty.
+func accesskeyCredentials(accessKey, secretKey string) (string, string, error) {
+	if accessKey != "" && secretKey != "" {
+		return accessKey, secretKey, nil
+	}
+	randomAccessKey, randomSecretKey, e := generateCredentials()
+	if e != nil {
+		return "", "", e
+	}
+	if accessKey == "" {
+		accessKey = randomAccessKey
+	}
+	if secretKey == "" {
+		secretKey = randomSecretKey
+	}
+	return accessKey, secretKey, nil
+}
+
+// mainAccesskeyCreate is the handle for "mc accesskey create" command.
+func mainAccesskeyCreate(ctx *cli.Context) error {
+	checkAccesskeyCreateSyntax(ctx)
+
+	console.SetColor("AccMessage", color.New(color.FgGreen))
+
+	aliasedURL := ctx.Args().Get(0)
+
+	accessKey, secretKey, e := accesskeyCredentials(ctx.String("access-key"), ctx.String("secret-key"))
+	fatalIf(probe.NewError(e), "Unable to create a new access key.")
+
+	// Service accounts of the requesting user are managed through the
+	// admin API without admin privileges.
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	creds, e := client.AddServiceAccount(globalContext, madmin.AddServiceAccountReq{
+		Policy:      mustReadSvcAcctPolicy(ctx.String("policy")),
+		AccessKey:   accessKey,
+		SecretKey:   secretKey,
+		Name:        ctx.String("name"),
+		Description: ctx.String("description"),
+		Expiration:  mustParseSvcAcctExpiry(ctx.String("expiry")),
+	})
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to create a new access key.")
+
+	printMsg(acctMessage{
+		op:            svcAccOpAdd,
+		AccessKey:     creds.AccessKey,
+		SecretKey:     creds.SecretKey,
+		Expiration:    &creds.Expiration,
+		AccountStatus: "enabled",
+	})
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccesskeyCredentials(t *testing.T) {
+	testCases := []struct {
+		accessKey, secretKey string
+	}{
+		{"", ""},
+		{"uploader", ""},
+		{"", "uploader-secret"},
+		{"uploader", "uploader-secret"},
+	}
+	for i, testCase := range testCases {
+		accessKey, secretKey, e := accesskeyCredentials(testCase.accessKey, testCase.secretKey)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		// Keys passed are kept, the others are random.
+		if testCase.accessKey != "" && accessKey != testCase.accessKey || accessKey == "" {
+			t.Errorf("Test %d: unexpected access key %q", i+1, accessKey)
+		}
+		if testCase.secretKey != "" && secretKey != testCase.secretKey || secretKey == "" {
+			t.Errorf("Test %d: unexpected secret key %q", i+1, secretKey)
+		}
+	}
+
+	first, _, _ := accesskeyCredentials("", "")
+	second, _, _ := accesskeyCredentials("", "")
+	if first == second {
+		t.Fatal("expected random access keys to differ")
+	}
+}
+
+func TestReadSvcAcctPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if e := os.WriteFile(path, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		return path
+	}
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":["arn:aws:s3:::reports/*"]}]}`
+
+	testCases := []struct {
+		path  string
+		valid bool
+		empty bool
+	}{
+		{"", true, true},
+		{write("read-only.json", policy), true, false},
+		{write("empty.json", `{"Version":"2012-10-17","Statement":[]}`), false, true},
+		{write("invalid.json", `{"Statement":`), false, true},
+		{filepath.Join(dir, "missing.json"), false, true},
+	}
+	for i, testCase := range testCases {
+		policyBytes, err := readSvcAcctPolicy(testCase.path)
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+		if empty := len(policyBytes) == 0; empty != testCase.empty {
+			t.Errorf("Test %d: expected empty policy %t, got %s", i+1, testCase.empty, policyBytes)
+		}
+	}
+}
+
+func TestParseSvcAcctExpiry(t *testing.T) {
+	testCases := []struct {
+		expiry   string
+		expected time.Time
+		valid    bool
+	}{
+		{"", time.Time{}, true},
+		{"2023-12-31", time.Date(2023, 12, 31, 0, 0, 0, 0, time.Local), true},
+		{"2023-12-31T18:30", time.Date(2023, 12, 31, 18, 30, 0, 0, time.Local), true},
+		{"2023-12-31T18:30:15", time.Date(2023, 12, 31, 18, 30, 15, 0, time.Local), true},
+		{"2023-12-31T18:30:15Z", time.Date(2023, 12, 31, 18, 30, 15, 0, time.UTC), true},
+		{"31/12/2023", time.Time{}, false},
+		{"tomorrow", time.Time{}, false},
+	}
+	for i, testCase := range testCases {
+		expiry, err := parseSvcAcctExpiry(testCase.expiry)
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+		if testCase.expected.IsZero() {
+			if expiry != nil {
+				t.Errorf("Test %d: expected no expiry, got %v", i+1, expiry)
+			}
+			continue
+		}
+		if expiry == nil || !expiry.Equal(testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, expiry)
+		}
+	}
+}
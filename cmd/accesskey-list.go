@@ -0,0 +1,98 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var accesskeyListCmd = cli.Command{
+	Name:         "list",
+	ShortName:    "ls",
+	Usage:        "list access keys of the current user",
+	Action:       mainAccesskeyList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all access keys of the user configured for 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio
+`,
+}
+
+// checkAccesskeyListSyntax - validate all the passed arguments
+func checkAccesskeyListSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+}
+
+// mainAccesskeyList is the handle for "mc accesskey list" command.
+func mainAccesskeyList(ctx *cli.Context) error {
+	checkAccesskeyListSyntax(ctx)
+
+	console.SetColor("AccessKeyHeader", color.New(color.Bold, color.FgBlue))
+	console.SetColor("ExpirationHeader", color.New(color.Bold, color.FgCyan))
+
+	aliasedURL := ctx.Args().Get(0)
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	// An empty user lists the access keys of the requesting user.
+	svcList, e := client.ListServiceAccounts(globalContext, "")
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to list access keys.")
+
+	if len(svcList.Accounts) == 0 {
+		if !globalJSON {
+			console.Println("No access keys found")
+		}
+		return nil
+	}
+
+	if !globalJSON {
+		console.Println(console.Colorize("Headers", newPrettyTable(" | ",
+			Field{"AccessKeyHeader", accessFieldMaxLen},
+			Field{"ExpirationHeader", expirationMaxLen},
+		).buildRow("   Access Key", "Expiry")))
+	}
+	for _, svc := range svcList.Accounts {
+		expiration := svc.Expiration
+		if expiration.Equal(timeSentinel) {
+			expiration = nil
+		}
+		printMsg(acctMessage{
+			op:         svcAccOpList,
+			AccessKey:  svc.AccessKey,
+			Expiration: expiration,
+		})
+	}
+	return nil
+}
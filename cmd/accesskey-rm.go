@@ -0,0 +1,83 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var accesskeyRemoveCmd = cli.Command{
+	Name:         "rm",
+	ShortName:    "remove",
+	Usage:        "remove access keys of the current user",
+	Action:       mainAccesskeyRemove,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} ALIAS ACCESSKEY [ACCESSKEY...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the access key 'J123C4ZXEQN8RK6ND35I' of the user configured for 'myminio'.
+     {{.Prompt}} {{.HelpName}} myminio J123C4ZXEQN8RK6ND35I
+`,
+}
+
+// checkAccesskeyRemoveSyntax - validate all the passed arguments
+func checkAccesskeyRemoveSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) < 2 {
+		showCommandHelpAndExit(ctx, 1)
+	}
+}
+
+// mainAccesskeyRemove is the handle for "mc accesskey rm" command.
+func mainAccesskeyRemove(ctx *cli.Context) error {
+	checkAccesskeyRemoveSyntax(ctx)
+
+	console.SetColor("AccMessage", color.New(color.FgGreen))
+
+	args := ctx.Args()
+	aliasedURL := args.Get(0)
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	var rmErr error
+	for _, accessKey := range args.Tail() {
+		e := client.DeleteServiceAccount(globalContext, accessKey)
+		if e != nil {
+			errorIf(probe.NewError(e).Trace(aliasedURL, accessKey), "Unable to remove access key `"+accessKey+"`.")
+			rmErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(acctMessage{
+			op:        svcAccOpRemove,
+			AccessKey: accessKey,
+		})
+	}
+	return rmErr
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/wildcard"
+)
+
+// deleteProtectionSid identifies the bucket policy statement which
+// denies removing a bucket.
+const deleteProtectionSid = "MCDenyDeleteBucket"
+
+// overrideProtectionFlag lets rb and rm remove protected buckets.
+var overrideProtectionFlag = cli.BoolFlag{
+	Name:  "override-protection",
+	Usage: "remove buckets protected with 'mc admin cluster bucket protect'",
+}
+
+var adminClusterBucketProtectFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "server",
+		Usage: "also deny removing matching buckets with a bucket policy on the server",
+	},
+	cli.BoolFlag{
+		Name:  "remove",
+		Usage: "remove the protection instead of adding it",
+	},
+	cli.BoolFlag{
+		Name:  "list",
+		Usage: "list the protected bucket patterns of an alias",
+	},
+}
+
+var adminClusterBucketProtectCmd = cli.Command{
+	Name:            "protect",
+	Usage:           "protect buckets from accidental removal",
+	Action:          mainClusterBucketProtect,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(adminClusterBucketProtectFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET/PATTERN
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Protected bucket patterns are saved in the mc configuration, 'mc rb' and 'mc rm --recursive'
+  refuse to remove whole buckets matching them unless --override-protection is given.
+
+  With --server, a statement denying 's3:DeleteBucket' is also added to the bucket policy of
+  every existing bucket matching the pattern, which protects them from all clients. Note that
+  the root user of a MinIO server is not subject to bucket policies.
+
+EXAMPLES:
+  1. Protect all buckets starting with "prod-" from removal with mc.
+     {{.Prompt}} {{.HelpName}} myminio/prod-*
+
+  2. Protect a bucket from removal with mc and with a bucket policy on the server.
+     {{.Prompt}} {{.HelpName}} --server myminio/finance
+
+  3. List the protected bucket patterns and their buckets protected on the server.
+     {{.Prompt}} {{.HelpName}} --list --server myminio
+
+  4. Remove the protection of a bucket, including its bucket policy statement.
+     {{.Prompt}} {{.HelpName}} --remove --server myminio/finance
+`,
+}
+
+// bucketProtectMessage container for bucket protection changes.
+type bucketProtectMessage struct {
+	Status  string   `json:"status"`
+	Op      string   `json:"op"`
+	Pattern string   `json:"pattern,omitempty"`
+	Server  []string `json:"server,omitempty"`
+}
+
+func (m bucketProtectMessage) String() string {
+	var b strings.Builder
+	switch m.Op {
+	case "protect":
+		fmt.Fprintf(&b, "Buckets matching %s are now protected from removal.", console.Colorize("Pattern", m.Pattern))
+	case "unprotect":
+		fmt.Fprintf(&b, "Buckets matching %s are no longer protected from removal.", console.Colorize("Pattern", m.Pattern))
+	case "list":
+		fmt.Fprintf(&b, "%s", console.Colorize("Pattern", m.Pattern))
+	}
+	for _, bucket := range m.Server {
+		fmt.Fprintf(&b, "\n  %s %s", console.Colorize("Server", "server policy:"), bucket)
+	}
+	return b.String()
+}
+
+func (m bucketProtectMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func checkClusterBucketProtectSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+	if ctx.Bool("list") && ctx.Bool("remove") {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--list and --remove cannot be used together.")
+	}
+	alias, _, ok := parseBucketProtectPattern(ctx.Args().Get(0), ctx.Bool("list"))
+	if mustGetHostConfig(alias) == nil {
+		fatalIf(errInvalidAliasedURL(ctx.Args().Get(0)).Trace(ctx.Args()...), "No such alias `"+alias+"`.")
+	}
+	if !ok {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "Please provide a bucket name or pattern, e.g. `"+alias+"/prod-*`.")
+	}
+}
+
+// parseBucketProtectPattern returns the alias and the 'alias/bucket'
+// pattern of an argument, it is only valid without a bucket pattern
+// when listing.
+func parseBucketProtectPattern(arg string, list bool) (alias, pattern string, ok bool) {
+	alias, pattern = url2Alias(filepath.ToSlash(arg))
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" || strings.Contains(pattern, "/") {
+		return alias, "", list
+	}
+	return alias, alias + "/" + pattern, true
+}
+
+// deleteProtectionPattern returns the protected pattern matching the
+// bucket of an aliased URL. URLs of an alias match if any bucket of
+// the alias is protected.
+func deleteProtectionPattern(aliasedURL string) (string, bool) {
+	mcCfg, err := loadMcConfig()
+	if err != nil {
+		return "", false
+	}
+	return matchDeleteProtection(mcCfg.ProtectedBuckets, aliasedURL)
+}
+
+// matchDeleteProtection returns the first of the protected patterns
+// matching the bucket of an aliased URL.
+func matchDeleteProtection(patterns []string, aliasedURL string) (string, bool) {
+	alias, urlPath := url2Alias(filepath.ToSlash(aliasedURL))
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(urlPath, "/"), "/")
+	for _, pattern := range patterns {
+		if bucket == "" && strings.HasPrefix(pattern, alias+"/") {
+			return pattern, true
+		}
+		if bucket != "" && wildcard.Match(pattern, alias+"/"+bucket) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// checkDeleteProtection refuses to go on with removing protected buckets.
+func checkDeleteProtection(cliCtx *cli.Context, aliasedURL string) {
+	if cliCtx.Bool("override-protection") {
+		return
+	}
+	if pattern, ok := deleteProtectionPattern(aliasedURL); ok {
+		fatalIf(errDummy().Trace(aliasedURL), "`"+aliasedURL+"` is protected from removal by `"+pattern+"`. Retry this command with ‘--override-protection’ flag if you really want to remove it.")
+	}
+}
+
+// bucketPolicyStatements returns the bucket policy document of a bucket
+// and its statements.
+func bucketPolicyStatements(ctx context.Context, clnt Client) (map[string]interface{}, []interface{}, *probe.Error) {
+	_, policyStr, err := clnt.GetAccess(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc := map[string]interface{}{"Version": "2012-10-17"}
+	if policyStr != "" {
+		if e := json.Unmarshal([]byte(policyStr), &doc); e != nil {
+			return nil, nil, probe.NewError(e)
+		}
+	}
+	statements, _ := doc["Statement"].([]interface{})
+	return doc, statements, nil
+}
+
+func isDeleteProtectionStatement(statement interface{}) bool {
+	s, ok := statement.(map[string]interface{})
+	return ok && s["Sid"] == deleteProtectionSid
+}
+
+// hasServerDeleteProtection returns true if the bucket policy of a bucket
+// denies removing it.
+func hasServerDeleteProtection(ctx context.Context, bucketURL string) (bool, *probe.Error) {
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return false, err
+	}
+	_, statements, err := bucketPolicyStatements(ctx, clnt)
+	if err != nil {
+		return false, err
+	}
+	for _, statement := range statements {
+		if isDeleteProtectionStatement(statement) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// setServerDeleteProtection adds or removes the bucket policy statement
+// denying removing a bucket, it returns true if the policy was changed.
+func setServerDeleteProtection(ctx context.Context, bucketURL string, protect bool) (bool, *probe.Error) {
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return false, err
+	}
+	doc, statements, err := bucketPolicyStatements(ctx, clnt)
+	if err != nil {
+		return false, err
+	}
+
+	var found bool
+	kept := make([]interface{}, 0, len(statements)+1)
+	for _, statement := range statements {
+		if isDeleteProtectionStatement(statement) {
+			found = true
+			continue
+		}
+		kept = append(kept, statement)
+	}
+	if found == protect {
+		return false, nil
+	}
+	if protect {
+		_, bucket := url2Alias(bucketURL)
+		kept = append(kept, map[string]interface{}{
+			"Sid":       deleteProtectionSid,
+			"Effect":    "Deny",
+			"Principal": map[string]interface{}{"AWS": []string{"*"}},
+			"Action":    []string{"s3:DeleteBucket"},
+			"Resource":  []string{"arn:aws:s3:::" + strings.Trim(bucket, "/")},
+		})
+	}
+	if len(kept) == 0 {
+		return true, clnt.SetAccess(ctx, "", true)
+	}
+	doc["Statement"] = kept
+	policyBytes, e := json.Marshal(doc)
+	if e != nil {
+		return false, probe.NewError(e)
+	}
+	return true, clnt.SetAccess(ctx, string(policyBytes), true)
+}
+
+// mainClusterBucketProtect - bucket protection command
+func mainClusterBucketProtect(cliCtx *cli.Context) error {
+	ctx, cancelProtect := context.WithCancel(globalContext)
+	defer cancelProtect()
+
+	checkClusterBucketProtectSyntax(cliCtx)
+
+	console.SetColor("Pattern", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Server", color.New(color.FgYellow))
+
+	alias, pattern, _ := parseBucketProtectPattern(cliCtx.Args().Get(0), cliCtx.Bool("list"))
+	isServer := cliCtx.Bool("server")
+
+	mcCfg, err := loadMcConfig()
+	fatalIf(err.Trace(alias), "Unable to load config `"+mustGetMcConfigPath()+"`.")
+
+	if cliCtx.Bool("list") {
+		for _, p := range mcCfg.ProtectedBuckets {
+			if strings.HasPrefix(p, alias+"/") {
+				printMsg(bucketProtectMessage{Op: "list", Pattern: p})
+			}
+		}
+		if !isServer {
+			return nil
+		}
+		buckets, err := listBucketsURLs(ctx, alias)
+		fatalIf(err.Trace(alias), "Unable to list buckets of `"+alias+"`.")
+		msg := bucketProtectMessage{Op: "list"}
+		for _, bucketURL := range buckets {
+			protected, err := hasServerDeleteProtection(ctx, bucketURL)
+			if err != nil {
+				errorIf(err.Trace(bucketURL), "Unable to get the bucket policy of `"+bucketURL+"`.")
+				continue
+			}
+			if protected {
+				msg.Server = append(msg.Server, bucketURL)
+			}
+		}
+		if len(msg.Server) > 0 {
+			printMsg(msg)
+		}
+		return nil
+	}
+
+	msg := bucketProtectMessage{Op: "protect", Pattern: pattern}
+	kept := make([]string, 0, len(mcCfg.ProtectedBuckets)+1)
+	for _, p := range mcCfg.ProtectedBuckets {
+		if p != pattern {
+			kept = append(kept, p)
+		}
+	}
+	if cliCtx.Bool("remove") {
+		msg.Op = "unprotect"
+	} else {
+		kept = append(kept, pattern)
+	}
+	mcCfg.ProtectedBuckets = kept
+	fatalIf(saveMcConfig(mcCfg).Trace(alias), "Unable to update config `"+mustGetMcConfigPath()+"`.")
+
+	if isServer {
+		buckets, err := listBucketsURLs(ctx, alias)
+		fatalIf(err.Trace(alias), "Unable to list buckets of `"+alias+"`.")
+		for _, bucketURL := range buckets {
+			if !wildcard.Match(pattern, filepath.ToSlash(bucketURL)) {
+				continue
+			}
+			changed, err := setServerDeleteProtection(ctx, bucketURL, !cliCtx.Bool("remove"))
+			fatalIf(err.Trace(bucketURL), "Unable to update the bucket policy of `"+bucketURL+"`.")
+			if changed {
+				msg.Server = append(msg.Server, bucketURL)
+			}
+		}
+	}
+	printMsg(msg)
+	return nil
+}
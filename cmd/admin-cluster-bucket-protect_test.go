@@ -0,0 +1,140 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseBucketProtectPattern(t *testing.T) {
+	testCases := []struct {
+		arg     string
+		list    bool
+		alias   string
+		pattern string
+		ok      bool
+	}{
+		{"myminio/prod-*", false, "myminio", "myminio/prod-*", true},
+		{"myminio/mybucket/", false, "myminio", "myminio/mybucket", true},
+		{"myminio", true, "myminio", "", true},
+		// Protecting needs a bucket, never a prefix.
+		{"myminio", false, "myminio", "", false},
+		{"myminio/mybucket/prefix", false, "myminio", "", false},
+	}
+	for i, testCase := range testCases {
+		alias, pattern, ok := parseBucketProtectPattern(testCase.arg, testCase.list)
+		if alias != testCase.alias || pattern != testCase.pattern || ok != testCase.ok {
+			t.Errorf("Test %d: expected %s %s %t, got %s %s %t", i+1,
+				testCase.alias, testCase.pattern, testCase.ok, alias, pattern, ok)
+		}
+	}
+}
+
+func TestMatchDeleteProtection(t *testing.T) {
+	patterns := []string{"myminio/prod-*", "other/logs"}
+	testCases := []struct {
+		aliasedURL string
+		pattern    string
+		protected  bool
+	}{
+		{"myminio/prod-data", "myminio/prod-*", true},
+		{"myminio/prod-data/object", "myminio/prod-*", true},
+		{"myminio/staging", "", false},
+		{"other/logs", "other/logs", true},
+		{"other/logs-archive", "", false},
+		// Removing all buckets of an alias removes the protected ones.
+		{"myminio", "myminio/prod-*", true},
+		{"myminio/", "myminio/prod-*", true},
+		{"play", "", false},
+	}
+	for i, testCase := range testCases {
+		pattern, protected := matchDeleteProtection(patterns, testCase.aliasedURL)
+		if pattern != testCase.pattern || protected != testCase.protected {
+			t.Errorf("Test %d: expected %s %t, got %s %t", i+1, testCase.pattern, testCase.protected, pattern, protected)
+		}
+	}
+}
+
+func TestServerDeleteProtection(t *testing.T) {
+	readOnly := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`
+	testCases := []struct {
+		policy string
+	}{
+		{""},
+		// Statements of the bucket policy are kept.
+		{readOnly},
+	}
+	for i, testCase := range testCases {
+		handler := &bucketConfigHandler{configs: map[string]string{}}
+		if testCase.policy != "" {
+			handler.configs["policy"] = testCase.policy
+		}
+		server := httptest.NewServer(handler)
+		t.Setenv(mcEnvHostPrefix+"protect", strings.Replace(server.URL, "://", "://access:secret@", 1))
+
+		statements := func() []interface{} {
+			handler.mu.Lock()
+			defer handler.mu.Unlock()
+			var doc map[string]interface{}
+			if policy, ok := handler.configs["policy"]; ok {
+				if e := json.Unmarshal([]byte(policy), &doc); e != nil {
+					t.Fatalf("Test %d: %v", i+1, e)
+				}
+			}
+			s, _ := doc["Statement"].([]interface{})
+			return s
+		}
+		initial := len(statements())
+
+		for _, step := range []struct {
+			protect, changed bool
+		}{
+			{true, true},
+			{true, false},
+			{false, true},
+			{false, false},
+		} {
+			changed, err := setServerDeleteProtection(context.Background(), "protect/bucket", step.protect)
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			if changed != step.changed {
+				t.Errorf("Test %d: expected changed %t, got %t", i+1, step.changed, changed)
+			}
+			protected, err := hasServerDeleteProtection(context.Background(), "protect/bucket")
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			if protected != step.protect {
+				t.Errorf("Test %d: expected protected %t, got %t", i+1, step.protect, protected)
+			}
+			expected := initial
+			if step.protect {
+				expected++
+			}
+			if n := len(statements()); n != expected {
+				t.Errorf("Test %d: expected %d statements, got %d", i+1, expected, n)
+			}
+		}
+		server.Close()
+	}
+}
@@ -22,6 +22,7 @@ import "github.com/minio/cli"
 var adminClusterBucketSubcommands = []cli.Command{
 	adminClusterBucketImportCmd,
 	adminClusterBucketExportCmd,
+	adminClusterBucketProtectCmd,
 }
 
 var adminClusterBucketCmd = cli.Command{
@@ -38,5 +39,5 @@ var adminClusterBucketCmd = cli.Command{
 func mainAdminClusterBucket(ctx *cli.Context) error {
 	commandNotFound(ctx, adminClusterBucketSubcommands)
 	return nil
-	// Sub-commands like "export", "import" have their own main.
+	// Sub-commands like "export", "import", "protect" have their own main.
 }
@@ -0,0 +1,571 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/klauspost/compress/zip"
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// IAM entity kinds accepted by --include.
+const (
+	iamEntityUsers    = "users"
+	iamEntityGroups   = "groups"
+	iamEntityPolicies = "policies"
+	iamEntitySvcAcct  = "svcacct"
+)
+
+// Strategies for entities which already exist on the destination.
+const (
+	iamConflictSkip      = "skip"
+	iamConflictOverwrite = "overwrite"
+	iamConflictRename    = "rename"
+)
+
+// Files of the zip archive produced by the server side IAM export.
+const (
+	iamExportPoliciesFile      = "iam-assets/policies.json"
+	iamExportUsersFile         = "iam-assets/users.json"
+	iamExportGroupsFile        = "iam-assets/groups.json"
+	iamExportSvcAcctsFile      = "iam-assets/svcaccts.json"
+	iamExportUserMappingsFile  = "iam-assets/user_mappings.json"
+	iamExportGroupMappingsFile = "iam-assets/group_mappings.json"
+)
+
+// Policies every deployment comes with, they are never migrated.
+var iamBuiltinPolicies = map[string]bool{
+	"consoleAdmin": true,
+	"diagnostics":  true,
+	"readonly":     true,
+	"readwrite":    true,
+	"writeonly":    true,
+}
+
+var adminIAMMigrateFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "include",
+		Usage: "comma separated list of IAM entities to migrate, any of 'users', 'groups', 'policies' and 'svcacct'",
+		Value: strings.Join([]string{iamEntityUsers, iamEntityGroups, iamEntityPolicies, iamEntitySvcAcct}, ","),
+	},
+	cli.StringFlag{
+		Name:  "conflict",
+		Usage: "what to do with entities already present on the destination, one of 'skip', 'overwrite' or 'rename'",
+		Value: iamConflictSkip,
+	},
+}
+
+var adminIAMMigrateCmd = cli.Command{
+	Name:            "migrate",
+	Usage:           "copy IAM entities from one MinIO deployment to another",
+	Action:          mainAdminIAMMigrate,
+	OnUsageError:    onUsageError,
+	Before:          setGlobalsFromContext,
+	Flags:           append(adminIAMMigrateFlags, globalFlags...),
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+CONFLICTS:
+  skip       leave entities already present on TARGET untouched (default)
+  overwrite  replace entities already present on TARGET
+  rename     create a copy with a '-migrated' suffix, references to it are updated
+
+EXAMPLES:
+  1. Copy all users, groups, policies and service accounts from 'oldminio' to 'newminio'.
+     {{.Prompt}} {{.HelpName}} oldminio newminio
+
+  2. Copy only policies and groups, replacing the ones already present on 'newminio'.
+     {{.Prompt}} {{.HelpName}} --include policies,groups --conflict overwrite oldminio newminio
+
+  3. Copy users, keeping both versions of those which already exist on 'newminio'.
+     {{.Prompt}} {{.HelpName}} --include users --conflict rename oldminio newminio
+`,
+}
+
+// iamMigrateMessage is the result of migrating a single IAM entity.
+type iamMigrateMessage struct {
+	Status string `json:"status"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Target string `json:"target,omitempty"`
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+}
+
+var iamMigrateTable = newPrettyTable(" | ",
+	Field{"Type", 8},
+	Field{"Name", 32},
+	Field{"Result", 11},
+	Field{"Detail", -1},
+)
+
+func (m iamMigrateMessage) String() string {
+	detail := m.Error
+	if detail == "" && m.Target != "" && m.Target != m.Name {
+		detail = "as " + m.Target
+	}
+	row := iamMigrateTable.buildRow(m.Type, m.Name, m.Result, detail)
+	if m.Status == "error" {
+		return console.Colorize("IAMMigrateFailed", row)
+	}
+	return console.Colorize("IAMMigrate", row)
+}
+
+func (m iamMigrateMessage) JSON() string {
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// iamExportGroup is a group as stored in the IAM export.
+type iamExportGroup struct {
+	Status  string   `json:"status"`
+	Members []string `json:"members"`
+}
+
+// iamExportMappedPolicy is a policy mapping as stored in the IAM
+// export, the policy names are comma separated.
+type iamExportMappedPolicy struct {
+	Policies string `json:"policy"`
+}
+
+// iamExport holds the IAM entities read from an export archive.
+type iamExport struct {
+	policies      map[string]json.RawMessage
+	users         map[string]madmin.AddOrUpdateUserReq
+	groups        map[string]iamExportGroup
+	svcAccts      map[string]madmin.SRSvcAccCreate
+	userPolicies  map[string]iamExportMappedPolicy
+	groupPolicies map[string]iamExportMappedPolicy
+}
+
+// readIAMExport decodes the IAM export archive in data.
+func readIAMExport(data []byte) (*iamExport, *probe.Error) {
+	zr, e := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	export := &iamExport{}
+	files := map[string]interface{}{
+		iamExportPoliciesFile:      &export.policies,
+		iamExportUsersFile:         &export.users,
+		iamExportGroupsFile:        &export.groups,
+		iamExportSvcAcctsFile:      &export.svcAccts,
+		iamExportUserMappingsFile:  &export.userPolicies,
+		iamExportGroupMappingsFile: &export.groupPolicies,
+	}
+	for _, file := range zr.File {
+		v, ok := files[file.Name]
+		if !ok {
+			continue
+		}
+		r, e := file.Open()
+		if e != nil {
+			return nil, probe.NewError(e).Trace(file.Name)
+		}
+		e = json.NewDecoder(r).Decode(v)
+		r.Close()
+		if e != nil {
+			return nil, probe.NewError(e).Trace(file.Name)
+		}
+	}
+	return export, nil
+}
+
+// parseIAMMigrateOptions returns the IAM entities passed to --include
+// and validates the strategy passed to --conflict.
+func parseIAMMigrateOptions(entities, conflict string) (map[string]bool, *probe.Error) {
+	include := make(map[string]bool)
+	for _, entity := range strings.Split(entities, ",") {
+		entity = strings.TrimSpace(entity)
+		switch entity {
+		case iamEntityUsers, iamEntityGroups, iamEntityPolicies, iamEntitySvcAcct:
+			include[entity] = true
+		case "":
+		default:
+			return nil, probe.NewError(fmt.Errorf("unknown IAM entity `%s` passed to --include", entity))
+		}
+	}
+	if len(include) == 0 {
+		return nil, probe.NewError(errors.New("--include needs at least one IAM entity"))
+	}
+
+	switch conflict {
+	case iamConflictSkip, iamConflictOverwrite, iamConflictRename:
+	default:
+		return nil, probe.NewError(fmt.Errorf("unknown conflict strategy `%s`, use one of 'skip', 'overwrite' or 'rename'", conflict))
+	}
+	return include, nil
+}
+
+func checkAdminIAMMigrateSyntax(ctx *cli.Context) (include map[string]bool, conflict string) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	conflict = ctx.String("conflict")
+	include, err := parseIAMMigrateOptions(ctx.String("include"), conflict)
+	fatalIf(err, "Invalid arguments.")
+	return include, conflict
+}
+
+// iamMigrator copies IAM entities read from an export to a destination.
+type iamMigrator struct {
+	ctx      context.Context
+	client   *madmin.AdminClient
+	conflict string
+	failed   bool
+
+	// Names present on the destination, per entity type.
+	existing map[string]map[string]bool
+	// Names under which source entities exist on the destination.
+	renames map[string]map[string]string
+}
+
+// targetName returns the name a source entity gets on the destination
+// and whether it should be created at all.
+func (m *iamMigrator) targetName(kind, name string) (string, bool) {
+	if !m.existing[kind][name] {
+		return name, true
+	}
+	switch m.conflict {
+	case iamConflictOverwrite:
+		return name, true
+	case iamConflictRename:
+		renamed := name + "-migrated"
+		for i := 2; m.existing[kind][renamed]; i++ {
+			renamed = fmt.Sprintf("%s-migrated-%d", name, i)
+		}
+		return renamed, true
+	}
+	return name, false
+}
+
+// resolve maps a source entity name to its name on the destination.
+func (m *iamMigrator) resolve(kind, name string) string {
+	if renamed, ok := m.renames[kind][name]; ok {
+		return renamed
+	}
+	return name
+}
+
+// resolvePolicies maps comma separated source policy names to the
+// names used on the destination.
+func (m *iamMigrator) resolvePolicies(policies string) string {
+	var names []string
+	for _, name := range strings.Split(policies, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, m.resolve(iamEntityPolicies, name))
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// migrate creates one entity with create and reports the outcome.
+func (m *iamMigrator) migrate(kind, name string, create func(target string, exists bool) error) {
+	msg := iamMigrateMessage{Status: "success", Type: kind, Name: name}
+	target, ok := m.targetName(kind, name)
+	exists := m.existing[kind][target]
+	switch {
+	case !ok:
+		msg.Result = "skipped"
+		msg.Error = "already exists"
+	default:
+		msg.Target = target
+		if e := create(target, exists); e != nil {
+			msg.Status = "error"
+			msg.Result = "failed"
+			msg.Error = e.Error()
+			m.failed = true
+			break
+		}
+		switch {
+		case target != name:
+			msg.Result = "renamed"
+		case exists:
+			msg.Result = "overwritten"
+		default:
+			msg.Result = "created"
+		}
+		m.existing[kind][target] = true
+		m.renames[kind][name] = target
+	}
+	printMsg(msg)
+}
+
+func (m *iamMigrator) migratePolicies(policies map[string]json.RawMessage) {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if iamBuiltinPolicies[name] {
+			continue
+		}
+		m.migrate(iamEntityPolicies, name, func(target string, _ bool) error {
+			return m.client.AddCannedPolicy(m.ctx, target, policies[name])
+		})
+	}
+}
+
+func (m *iamMigrator) migrateUsers(users map[string]madmin.AddOrUpdateUserReq, mappings map[string]iamExportMappedPolicy) {
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m.migrate(iamEntityUsers, name, func(target string, _ bool) error {
+			user := users[name]
+			user.Policy = ""
+			if e := m.client.SetUserReq(m.ctx, target, user); e != nil {
+				return e
+			}
+			if policies := m.resolvePolicies(mappings[name].Policies); policies != "" {
+				return m.client.SetPolicy(m.ctx, policies, target, false)
+			}
+			return nil
+		})
+	}
+}
+
+func (m *iamMigrator) migrateGroups(groups map[string]iamExportGroup, mappings map[string]iamExportMappedPolicy) {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m.migrate(iamEntityGroups, name, func(target string, exists bool) error {
+			group := groups[name]
+			members := make([]string, 0, len(group.Members))
+			isMember := make(map[string]bool, len(group.Members))
+			for _, member := range group.Members {
+				member = m.resolve(iamEntityUsers, member)
+				members = append(members, member)
+				isMember[member] = true
+			}
+			if exists {
+				// Drop the members the source group does not have.
+				desc, e := m.client.GetGroupDescription(m.ctx, target)
+				if e != nil {
+					return e
+				}
+				var stale []string
+				for _, member := range desc.Members {
+					if !isMember[member] {
+						stale = append(stale, member)
+					}
+				}
+				if len(stale) > 0 {
+					if e = m.client.UpdateGroupMembers(m.ctx, madmin.GroupAddRemove{
+						Group:    target,
+						Members:  stale,
+						IsRemove: true,
+					}); e != nil {
+						return e
+					}
+				}
+			}
+			if e := m.client.UpdateGroupMembers(m.ctx, madmin.GroupAddRemove{
+				Group:   target,
+				Members: members,
+			}); e != nil {
+				return e
+			}
+			if group.Status == string(madmin.GroupDisabled) {
+				if e := m.client.SetGroupStatus(m.ctx, target, madmin.GroupDisabled); e != nil {
+					return e
+				}
+			}
+			if policies := m.resolvePolicies(mappings[name].Policies); policies != "" {
+				return m.client.SetPolicy(m.ctx, policies, target, true)
+			}
+			return nil
+		})
+	}
+}
+
+// svcAcctExists returns true if a service account with the access key
+// exists on the destination, any other error than a missing service
+// account is returned.
+func (m *iamMigrator) svcAcctExists(accessKey string) (bool, error) {
+	if _, e := m.client.InfoServiceAccount(m.ctx, accessKey); e != nil {
+		if madmin.ToErrorResponse(e).Code == "XMinioAdminServiceAccountNotFound" {
+			return false, nil
+		}
+		return false, e
+	}
+	return true, nil
+}
+
+// lookupSvcAcct records whether the service account and, when it is
+// renamed, the names it could be renamed to exist on the destination.
+func (m *iamMigrator) lookupSvcAcct(name string) error {
+	for {
+		target, ok := m.targetName(iamEntitySvcAcct, name)
+		if !ok || m.existing[iamEntitySvcAcct][target] {
+			return nil
+		}
+		exists, e := m.svcAcctExists(target)
+		if e != nil || !exists {
+			return e
+		}
+		m.existing[iamEntitySvcAcct][target] = true
+	}
+}
+
+func (m *iamMigrator) migrateSvcAccts(svcAccts map[string]madmin.SRSvcAccCreate) {
+	names := make([]string, 0, len(svcAccts))
+	for name := range svcAccts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		svcAcct := svcAccts[name]
+		if e := m.lookupSvcAcct(name); e != nil {
+			m.failed = true
+			printMsg(iamMigrateMessage{Status: "error", Type: iamEntitySvcAcct, Name: name, Result: "failed", Error: e.Error()})
+			continue
+		}
+		m.migrate(iamEntitySvcAcct, name, func(target string, exists bool) error {
+			if exists {
+				if e := m.client.DeleteServiceAccount(m.ctx, target); e != nil {
+					return e
+				}
+			}
+			req := madmin.AddServiceAccountReq{
+				TargetUser:  m.resolve(iamEntityUsers, svcAcct.Parent),
+				AccessKey:   target,
+				SecretKey:   svcAcct.SecretKey,
+				Name:        svcAcct.Name,
+				Description: svcAcct.Description,
+				Expiration:  svcAcct.Expiration,
+			}
+			if len(svcAcct.SessionPolicy) > 0 && string(svcAcct.SessionPolicy) != "null" {
+				req.Policy = svcAcct.SessionPolicy
+			}
+			if _, e := m.client.AddServiceAccount(m.ctx, req); e != nil {
+				return e
+			}
+			if svcAcct.Status == "off" {
+				return m.client.UpdateServiceAccount(m.ctx, target, madmin.UpdateServiceAccountReq{NewStatus: "off"})
+			}
+			return nil
+		})
+	}
+}
+
+// mainAdminIAMMigrate is the handle for "mc admin iam migrate" command.
+func mainAdminIAMMigrate(ctx *cli.Context) error {
+	include, conflict := checkAdminIAMMigrateSyntax(ctx)
+
+	console.SetColor("IAMMigrate", color.New(color.FgGreen))
+	console.SetColor("IAMMigrateFailed", color.New(color.FgRed, color.Bold))
+	console.SetColor("IAMMigrateHeader", color.New(color.Bold))
+
+	args := ctx.Args()
+	srcAlias, dstAlias := args.Get(0), args.Get(1)
+
+	srcClient, err := newAdminClient(srcAlias)
+	fatalIf(err.Trace(srcAlias), "Unable to initialize admin client.")
+	dstClient, err := newAdminClient(dstAlias)
+	fatalIf(err.Trace(dstAlias), "Unable to initialize admin client.")
+
+	// The export is the only place secret keys of users and service
+	// accounts can be read from.
+	r, e := srcClient.ExportIAM(globalContext)
+	fatalIf(probe.NewError(e).Trace(srcAlias), "Unable to export IAM info.")
+	data, e := io.ReadAll(r)
+	r.Close()
+	fatalIf(probe.NewError(e).Trace(srcAlias), "Unable to export IAM info.")
+	export, err := readIAMExport(data)
+	fatalIf(err.Trace(srcAlias), "Unable to read exported IAM info.")
+
+	m := &iamMigrator{
+		ctx:      globalContext,
+		client:   dstClient,
+		conflict: conflict,
+		existing: make(map[string]map[string]bool),
+		renames:  make(map[string]map[string]string),
+	}
+	for _, kind := range []string{iamEntityPolicies, iamEntityUsers, iamEntityGroups, iamEntitySvcAcct} {
+		m.existing[kind] = make(map[string]bool)
+		m.renames[kind] = make(map[string]string)
+	}
+
+	policies, e := dstClient.ListCannedPolicies(globalContext)
+	fatalIf(probe.NewError(e).Trace(dstAlias), "Unable to list policies.")
+	for name := range policies {
+		m.existing[iamEntityPolicies][name] = true
+	}
+	users, e := dstClient.ListUsers(globalContext)
+	fatalIf(probe.NewError(e).Trace(dstAlias), "Unable to list users.")
+	for name := range users {
+		m.existing[iamEntityUsers][name] = true
+	}
+	groups, e := dstClient.ListGroups(globalContext)
+	fatalIf(probe.NewError(e).Trace(dstAlias), "Unable to list groups.")
+	for _, name := range groups {
+		m.existing[iamEntityGroups][name] = true
+	}
+
+	if !globalJSON {
+		console.Println(console.Colorize("IAMMigrateHeader", iamMigrateTable.buildRow("Type", "Name", "Result", "Detail")))
+	}
+
+	// Policies come first so that users and groups can be mapped to
+	// them, users before the groups and service accounts using them.
+	if include[iamEntityPolicies] {
+		m.migratePolicies(export.policies)
+	}
+	if include[iamEntityUsers] {
+		m.migrateUsers(export.users, export.userPolicies)
+	}
+	if include[iamEntityGroups] {
+		m.migrateGroups(export.groups, export.groupPolicies)
+	}
+	if include[iamEntitySvcAcct] {
+		m.migrateSvcAccts(export.svcAccts)
+	}
+
+	if m.failed {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseIAMMigrateOptions(t *testing.T) {
+	testCases := []struct {
+		include  string
+		conflict string
+		expected map[string]bool
+	}{
+		{"users,groups,policies,svcacct", "skip", map[string]bool{"users": true, "groups": true, "policies": true, "svcacct": true}},
+		{"policies, groups", "overwrite", map[string]bool{"policies": true, "groups": true}},
+		{"users,", "rename", map[string]bool{"users": true}},
+		// Invalid entities and strategies.
+		{"buckets", "skip", nil},
+		{"", "skip", nil},
+		{" , ", "skip", nil},
+		{"users", "merge", nil},
+		{"users", "", nil},
+	}
+	for i, testCase := range testCases {
+		include, err := parseIAMMigrateOptions(testCase.include, testCase.conflict)
+		if testCase.expected == nil {
+			if err == nil {
+				t.Errorf("Test %d: expected an error", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(include, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, include)
+		}
+	}
+}
+
+func newTestIAMMigrator(conflict string, existing ...string) *iamMigrator {
+	m := &iamMigrator{
+		conflict: conflict,
+		existing: make(map[string]map[string]bool),
+		renames:  make(map[string]map[string]string),
+	}
+	for _, kind := range []string{iamEntityPolicies, iamEntityUsers, iamEntityGroups, iamEntitySvcAcct} {
+		m.existing[kind] = make(map[string]bool)
+		m.renames[kind] = make(map[string]string)
+	}
+	for _, name := range existing {
+		m.existing[iamEntityPolicies][name] = true
+	}
+	return m
+}
+
+func TestIAMMigratorTargetName(t *testing.T) {
+	testCases := []struct {
+		conflict string
+		existing []string
+		name     string
+		target   string
+		create   bool
+	}{
+		{iamConflictSkip, nil, "audit", "audit", true},
+		{iamConflictSkip, []string{"audit"}, "audit", "audit", false},
+		{iamConflictOverwrite, []string{"audit"}, "audit", "audit", true},
+		{iamConflictRename, []string{"audit"}, "audit", "audit-migrated", true},
+		{iamConflictRename, []string{"audit", "audit-migrated"}, "audit", "audit-migrated-2", true},
+		{iamConflictRename, []string{"audit", "audit-migrated", "audit-migrated-2"}, "audit", "audit-migrated-3", true},
+	}
+	for i, testCase := range testCases {
+		m := newTestIAMMigrator(testCase.conflict, testCase.existing...)
+		target, create := m.targetName(iamEntityPolicies, testCase.name)
+		if target != testCase.target || create != testCase.create {
+			t.Errorf("Test %d: expected %s %t, got %s %t", i+1, testCase.target, testCase.create, target, create)
+		}
+	}
+}
+
+func TestIAMMigratorMigrate(t *testing.T) {
+	testCases := []struct {
+		conflict string
+		// Name the policy "audit" is created with, empty if skipped.
+		created  string
+		existed  bool
+		policies string
+	}{
+		{iamConflictSkip, "", false, "audit,readonly"},
+		{iamConflictOverwrite, "audit", true, "audit,readonly"},
+		// References to renamed entities are updated.
+		{iamConflictRename, "audit-migrated", false, "audit-migrated,readonly"},
+	}
+	for i, testCase := range testCases {
+		m := newTestIAMMigrator(testCase.conflict, "audit")
+		var created string
+		var existed bool
+		m.migrate(iamEntityPolicies, "audit", func(target string, exists bool) error {
+			created, existed = target, exists
+			return nil
+		})
+		if created != testCase.created || existed != testCase.existed {
+			t.Errorf("Test %d: expected %q created over existing %t, got %q %t", i+1, testCase.created, testCase.existed, created, existed)
+		}
+		if policies := m.resolvePolicies(" audit, readonly,"); policies != testCase.policies {
+			t.Errorf("Test %d: expected policies %s, got %s", i+1, testCase.policies, policies)
+		}
+		if m.failed {
+			t.Errorf("Test %d: unexpected failure", i+1)
+		}
+	}
+
+	// Entities which fail to be created are reported and not mapped.
+	m := newTestIAMMigrator(iamConflictSkip)
+	m.migrate(iamEntityUsers, "alice", func(string, bool) error {
+		return errors.New("access denied")
+	})
+	if !m.failed || m.existing[iamEntityUsers]["alice"] {
+		t.Fatal("expected the failed user to be reported and not mapped")
+	}
+}
+
+func TestReadIAMExport(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range map[string]string{
+		iamExportPoliciesFile:      `{"audit":{"Version":"2012-10-17","Statement":[]}}`,
+		iamExportUsersFile:         `{"alice":{"secretKey":"secret","status":"enabled"}}`,
+		iamExportGroupsFile:        `{"auditors":{"status":"disabled","members":["alice"]}}`,
+		iamExportSvcAcctsFile:      `{}`,
+		iamExportUserMappingsFile:  `{"alice":{"policy":"audit,readonly"}}`,
+		iamExportGroupMappingsFile: `{"auditors":{"policy":"audit"}}`,
+		"iam-assets/unknown.json":  `[]`,
+	} {
+		w, e := zw.Create(name)
+		if e != nil {
+			t.Fatal(e)
+		}
+		w.Write([]byte(data))
+	}
+	if e := zw.Close(); e != nil {
+		t.Fatal(e)
+	}
+
+	export, err := readIAMExport(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := export.policies["audit"]; !ok {
+		t.Error("expected the policy audit")
+	}
+	if export.users["alice"].SecretKey != "secret" {
+		t.Errorf("unexpected users %v", export.users)
+	}
+	if group := export.groups["auditors"]; group.Status != "disabled" || !reflect.DeepEqual(group.Members, []string{"alice"}) {
+		t.Errorf("unexpected groups %v", export.groups)
+	}
+	if export.userPolicies["alice"].Policies != "audit,readonly" || export.groupPolicies["auditors"].Policies != "audit" {
+		t.Errorf("unexpected policy mappings %v %v", export.userPolicies, export.groupPolicies)
+	}
+
+	if _, err = readIAMExport([]byte("not a zip archive")); err == nil {
+		t.Fatal("expected an invalid archive to be rejected")
+	}
+}
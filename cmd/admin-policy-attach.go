@@ -40,14 +40,17 @@ var adminPolicyAttachCmd = cli.Command{
 	Action:       mainAdminPolicyAttach,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(adminAttachPolicyFlags, globalFlags...),
+	Flags:        append(append(adminAttachPolicyFlags, adminPolicyBulkFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} [FLAGS] TARGET POLICY [POLICY...] [--user USER | --group GROUP]
+  {{.HelpName}} [FLAGS] TARGET --csv FILE
 
-  Exactly one of --user or --group is required.
+  Exactly one of --user or --group is required, unless the associations
+  are read from a CSV file with one 'user|group,NAME,POLICY[,POLICY...]'
+  row per user or group.
 
 POLICY:
   Name of the policy on the MinIO server.
@@ -60,6 +63,10 @@ EXAMPLES:
      {{.Prompt}} {{.HelpName}} myminio readonly --user james
   2. Attach the "audit-policy" and "acct-policy" policies to group "legal".
      {{.Prompt}} {{.HelpName}} myminio audit-policy acct-policy --group legal
+  3. Show the policies which would be attached to the users and groups listed in "mappings.csv".
+     {{.Prompt}} {{.HelpName}} myminio --csv mappings.csv --dry-run
+  4. Attach the policies to the users and groups listed in "mappings.csv".
+     {{.Prompt}} {{.HelpName}} myminio --csv mappings.csv
 `,
 }
 
@@ -69,6 +76,12 @@ func mainAdminPolicyAttach(ctx *cli.Context) error {
 }
 
 func userAttachOrDetachPolicy(ctx *cli.Context, attach bool) error {
+	if msg := checkPolicyBulkSyntax(ctx); msg != "" {
+		fatalIf(errInvalidArgument(), msg)
+	}
+	if ctx.IsSet("csv") {
+		return bulkAttachOrDetachPolicy(ctx, attach)
+	}
 	if len(ctx.Args()) < 2 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
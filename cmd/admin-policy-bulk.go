@@ -0,0 +1,296 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// adminPolicyBulkFlags are shared by attach and detach to read the
+// associations from a CSV file instead of the command line.
+var adminPolicyBulkFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "csv",
+		Usage: "read 'user|group,NAME,POLICY[,POLICY...]' rows from a CSV file, '-' for stdin",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "only show the changes to the current associations read from --csv",
+	},
+}
+
+// policyMappingRow is a row of a --csv file, associating policies with
+// a user or a group.
+type policyMappingRow struct {
+	line     int
+	user     string
+	group    string
+	policies []string
+}
+
+// parsePolicyMappingCSV reads the rows of a --csv file, empty lines and
+// lines starting with '#' are skipped as is a 'type,name,policy' header.
+func parsePolicyMappingCSV(r io.Reader) ([]policyMappingRow, *probe.Error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []policyMappingRow
+	for {
+		record, e := reader.Read()
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "type") {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, probe.NewError(fmt.Errorf("line %d: expected 'user|group,NAME,POLICY[,POLICY...]'", line))
+		}
+		row := policyMappingRow{line: line}
+		name := strings.TrimSpace(record[1])
+		if name == "" {
+			return nil, probe.NewError(fmt.Errorf("line %d: missing user or group name", line))
+		}
+		switch strings.ToLower(strings.TrimSpace(record[0])) {
+		case "user":
+			row.user = name
+		case "group":
+			row.group = name
+		default:
+			return nil, probe.NewError(fmt.Errorf("line %d: unknown entity type `%s`, expected 'user' or 'group'", line, record[0]))
+		}
+		for _, policy := range record[2:] {
+			if policy = strings.TrimSpace(policy); policy != "" {
+				row.policies = append(row.policies, policy)
+			}
+		}
+		if len(row.policies) == 0 {
+			return nil, probe.NewError(fmt.Errorf("line %d: missing policy", line))
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// diffPolicyMapping splits the policies of a row into the ones to
+// attach or detach and the ones already in the requested state.
+func diffPolicyMapping(policies, current []string, attach bool) (changed, unchanged []string) {
+	attached := make(map[string]bool, len(current))
+	for _, policy := range current {
+		attached[policy] = true
+	}
+	seen := make(map[string]bool, len(policies))
+	for _, policy := range policies {
+		if seen[policy] {
+			continue
+		}
+		seen[policy] = true
+		if attached[policy] == attach {
+			unchanged = append(unchanged, policy)
+		} else {
+			changed = append(changed, policy)
+		}
+	}
+	return changed, unchanged
+}
+
+// policyBulkMessage is the outcome of a --csv row.
+type policyBulkMessage struct {
+	attach           bool
+	Status           string   `json:"status"`
+	Line             int      `json:"line"`
+	User             string   `json:"user,omitempty"`
+	Group            string   `json:"group,omitempty"`
+	PoliciesAttached []string `json:"policiesAttached,omitempty"`
+	PoliciesDetached []string `json:"policiesDetached,omitempty"`
+	Unchanged        []string `json:"unchanged,omitempty"`
+	DryRun           bool     `json:"dryRun,omitempty"`
+	Error            string   `json:"error,omitempty"`
+}
+
+func (m policyBulkMessage) String() string {
+	entity := "user `" + m.User + "`"
+	if m.Group != "" {
+		entity = "group `" + m.Group + "`"
+	}
+	prefix := fmt.Sprintf("line %d: %s:", m.Line, entity)
+	if m.DryRun {
+		prefix = "(dry-run) " + prefix
+	}
+	if m.Error != "" {
+		return console.Colorize("PolicyBulkFailed", prefix+" "+m.Error)
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	for _, policy := range m.PoliciesAttached {
+		b.WriteString(console.Colorize("PolicyBulkAttach", " +"+policy))
+	}
+	for _, policy := range m.PoliciesDetached {
+		b.WriteString(console.Colorize("PolicyBulkDetach", " -"+policy))
+	}
+	if len(m.Unchanged) > 0 {
+		state := "attached"
+		if !m.attach {
+			state = "detached"
+		}
+		fmt.Fprintf(&b, " (already %s: %s)", state, strings.Join(m.Unchanged, ", "))
+	}
+	return b.String()
+}
+
+func (m policyBulkMessage) JSON() string {
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// checkPolicyBulkSyntax returns why --csv and --dry-run cannot be
+// used as given, or an empty string.
+func checkPolicyBulkSyntax(ctx *cli.Context) string {
+	if !ctx.IsSet("csv") {
+		if ctx.Bool("dry-run") {
+			return "--dry-run requires --csv."
+		}
+		return ""
+	}
+	switch {
+	case ctx.String("csv") == "":
+		return "--csv requires a file, '-' for stdin."
+	case len(ctx.Args()) != 1 || ctx.String("user") != "" || ctx.String("group") != "":
+		return "--csv takes only TARGET, policies, users and groups are read from the file."
+	}
+	return ""
+}
+
+// bulkAttachOrDetachPolicy attaches or detaches the policies of every
+// row of a --csv file, only the associations which are not yet in the
+// requested state are changed.
+func bulkAttachOrDetachPolicy(ctx *cli.Context, attach bool) error {
+	aliasedURL := ctx.Args().Get(0)
+	dryRun := ctx.Bool("dry-run")
+
+	console.SetColor("PolicyBulkAttach", color.New(color.FgGreen))
+	console.SetColor("PolicyBulkDetach", color.New(color.FgYellow))
+	console.SetColor("PolicyBulkFailed", color.New(color.FgRed, color.Bold))
+
+	csvFile := ctx.String("csv")
+	var r io.Reader = os.Stdin
+	if csvFile != "-" {
+		f, e := os.Open(csvFile)
+		fatalIf(probe.NewError(e).Trace(csvFile), "Unable to open CSV file.")
+		defer f.Close()
+		r = f
+	}
+	rows, err := parsePolicyMappingCSV(r)
+	fatalIf(err.Trace(csvFile), "Unable to read CSV file.")
+
+	client, err := newAdminClient(aliasedURL)
+	fatalIf(err, "Unable to initialize admin connection.")
+
+	// Fetch the current associations of every user and group at once.
+	var query madmin.PolicyEntitiesQuery
+	for _, row := range rows {
+		if row.user != "" {
+			query.Users = append(query.Users, row.user)
+		} else {
+			query.Groups = append(query.Groups, row.group)
+		}
+	}
+	res, e := client.GetPolicyEntities(globalContext, query)
+	fatalIf(probe.NewError(e).Trace(aliasedURL), "Unable to fetch policy entities")
+	userPolicies := make(map[string][]string, len(res.UserMappings))
+	for _, mapping := range res.UserMappings {
+		userPolicies[mapping.User] = mapping.Policies
+	}
+	groupPolicies := make(map[string][]string, len(res.GroupMappings))
+	for _, mapping := range res.GroupMappings {
+		groupPolicies[mapping.Group] = mapping.Policies
+	}
+
+	var retErr error
+	for _, row := range rows {
+		current := userPolicies[row.user]
+		if row.group != "" {
+			current = groupPolicies[row.group]
+		}
+		changed, unchanged := diffPolicyMapping(row.policies, current, attach)
+
+		msg := policyBulkMessage{
+			attach:    attach,
+			Status:    "success",
+			Line:      row.line,
+			User:      row.user,
+			Group:     row.group,
+			Unchanged: unchanged,
+			DryRun:    dryRun,
+		}
+		if len(changed) > 0 && !dryRun {
+			req := madmin.PolicyAssociationReq{
+				User:     row.user,
+				Group:    row.group,
+				Policies: changed,
+			}
+			if attach {
+				_, e = client.AttachPolicy(globalContext, req)
+			} else {
+				_, e = client.DetachPolicy(globalContext, req)
+			}
+			if e != nil {
+				msg.Status = "error"
+				msg.Error = e.Error()
+				printMsg(msg)
+				retErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+		}
+		// Keep the state of later rows naming the same entity in sync.
+		if attach {
+			msg.PoliciesAttached = changed
+			current = append(current, changed...)
+		} else {
+			msg.PoliciesDetached = changed
+			current, _ = diffPolicyMapping(current, changed, true)
+		}
+		if row.user != "" {
+			userPolicies[row.user] = current
+		} else {
+			groupPolicies[row.group] = current
+		}
+		printMsg(msg)
+	}
+	return retErr
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/madmin-go/v3"
+)
+
+func TestCheckPolicyBulkSyntax(t *testing.T) {
+	flags := append(append([]cli.Flag{}, adminAttachPolicyFlags...), adminPolicyBulkFlags...)
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--user", "james", "myminio", "readonly"}, ""},
+		{[]string{"--csv", "mappings.csv", "myminio"}, ""},
+		{[]string{"--csv", "-", "--dry-run", "myminio"}, ""},
+		{[]string{"--dry-run", "--user", "james", "myminio", "readonly"}, "--dry-run requires --csv."},
+		{[]string{"--csv", "", "myminio"}, "--csv requires a file, '-' for stdin."},
+		{[]string{"--csv", "mappings.csv", "myminio", "readonly"}, "--csv takes only TARGET, policies, users and groups are read from the file."},
+		{[]string{"--csv", "mappings.csv", "--group", "legal", "myminio"}, "--csv takes only TARGET, policies, users and groups are read from the file."},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, flags, testCase.args...)
+		if msg := checkPolicyBulkSyntax(cliCtx); msg != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, msg)
+		}
+	}
+}
+
+func TestParsePolicyMappingCSV(t *testing.T) {
+	testCases := []struct {
+		csv      string
+		expected []policyMappingRow
+		err      string
+	}{
+		{
+			"type,name,policy\nuser,james,readonly\n# comment\n\ngroup, legal, audit, acct\n",
+			[]policyMappingRow{
+				{line: 2, user: "james", policies: []string{"readonly"}},
+				{line: 5, group: "legal", policies: []string{"audit", "acct"}},
+			},
+			"",
+		},
+		{"USER,james,readonly,", []policyMappingRow{{line: 1, user: "james", policies: []string{"readonly"}}}, ""},
+		{"user,james\n", nil, "line 1: expected 'user|group,NAME,POLICY[,POLICY...]'"},
+		{"user,,readonly\n", nil, "line 1: missing user or group name"},
+		{"role,james,readonly\n", nil, "line 1: unknown entity type `role`, expected 'user' or 'group'"},
+		{"user,james,readonly\nuser,jane, ,\n", nil, "line 2: missing policy"},
+	}
+	for i, testCase := range testCases {
+		rows, err := parsePolicyMappingCSV(strings.NewReader(testCase.csv))
+		if testCase.err != "" {
+			if err == nil || err.ToGoError().Error() != testCase.err {
+				t.Errorf("Test %d: expected error %q, got %v", i+1, testCase.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(rows, testCase.expected) {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, rows)
+		}
+	}
+}
+
+func TestDiffPolicyMapping(t *testing.T) {
+	testCases := []struct {
+		policies, current  []string
+		attach             bool
+		changed, unchanged []string
+	}{
+		{[]string{"a", "b"}, nil, true, []string{"a", "b"}, nil},
+		{[]string{"a", "b", "a"}, []string{"b"}, true, []string{"a"}, []string{"b"}},
+		{[]string{"a", "b"}, []string{"b"}, false, []string{"b"}, []string{"a"}},
+		{[]string{"a"}, []string{"a"}, true, nil, []string{"a"}},
+	}
+	for i, testCase := range testCases {
+		changed, unchanged := diffPolicyMapping(testCase.policies, testCase.current, testCase.attach)
+		if !reflect.DeepEqual(changed, testCase.changed) || !reflect.DeepEqual(unchanged, testCase.unchanged) {
+			t.Errorf("Test %d: expected %v and %v, got %v and %v", i+1, testCase.changed, testCase.unchanged, changed, unchanged)
+		}
+	}
+}
+
+// policyAdminHandler is an http.Handler serving the policy associations
+// of the builtin identity provider with the MinIO admin API.
+type policyAdminHandler struct {
+	mu       sync.Mutex
+	users    map[string][]string
+	groups   map[string][]string
+	requests []string
+}
+
+func (h *policyAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/idp/builtin/policy-entities"):
+		var res madmin.PolicyEntitiesResult
+		for _, user := range r.URL.Query()["user"] {
+			res.UserMappings = append(res.UserMappings, madmin.UserPolicyEntities{User: user, Policies: h.users[user]})
+		}
+		for _, group := range r.URL.Query()["group"] {
+			res.GroupMappings = append(res.GroupMappings, madmin.GroupPolicyEntities{Group: group, Policies: h.groups[group]})
+		}
+		data, _ := json.Marshal(res)
+		data, _ = madmin.EncryptData("secret", data)
+		w.Write(data)
+	case strings.Contains(r.URL.Path, "/idp/builtin/policy/"):
+		data, e := madmin.DecryptData("secret", r.Body)
+		var req madmin.PolicyAssociationReq
+		if e == nil {
+			e = json.Unmarshal(data, &req)
+		}
+		if e != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		h.requests = append(h.requests, filepath.Base(r.URL.Path)+" "+req.User+req.Group+" "+strings.Join(req.Policies, ","))
+		// Older servers reply without a result.
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestBulkAttachOrDetachPolicy(t *testing.T) {
+	handler := &policyAdminHandler{
+		users:  map[string][]string{"james": {"readonly"}},
+		groups: map[string][]string{"legal": {"audit"}},
+	}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	t.Setenv(mcEnvHostPrefix+"myminio", strings.Replace(server.URL, "://", "://access:secret@", 1))
+
+	csvFile := filepath.Join(t.TempDir(), "mappings.csv")
+	csv := "type,name,policy\nuser,james,readonly,audit\ngroup,legal,audit,acct\nuser,james,audit\n"
+	if e := os.WriteFile(csvFile, []byte(csv), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	flags := append(append([]cli.Flag{}, adminAttachPolicyFlags...), adminPolicyBulkFlags...)
+
+	// A dry run changes nothing.
+	cliCtx := newTestCLIContext(t, flags, "--csv", csvFile, "--dry-run", "myminio")
+	if e := bulkAttachOrDetachPolicy(cliCtx, true); e != nil {
+		t.Fatal(e)
+	}
+	if len(handler.requests) != 0 {
+		t.Fatalf("expected no changes, got %v", handler.requests)
+	}
+
+	// Only the policies not yet attached are, once.
+	cliCtx = newTestCLIContext(t, flags, "--csv", csvFile, "myminio")
+	if e := bulkAttachOrDetachPolicy(cliCtx, true); e != nil {
+		t.Fatal(e)
+	}
+	expected := []string{"attach james audit", "attach legal acct"}
+	if !reflect.DeepEqual(handler.requests, expected) {
+		t.Fatalf("expected %v, got %v", expected, handler.requests)
+	}
+
+	handler.requests = nil
+	cliCtx = newTestCLIContext(t, flags, "--csv", csvFile, "myminio")
+	if e := bulkAttachOrDetachPolicy(cliCtx, false); e != nil {
+		t.Fatal(e)
+	}
+	expected = []string{"detach james readonly", "detach legal audit"}
+	if !reflect.DeepEqual(handler.requests, expected) {
+		t.Fatalf("expected %v, got %v", expected, handler.requests)
+	}
+}
@@ -38,14 +38,17 @@ var adminPolicyDetachCmd = cli.Command{
 	Action:       mainAdminPolicyDetach,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(adminDetachPolicyFlags, globalFlags...),
+	Flags:        append(append(adminDetachPolicyFlags, adminPolicyBulkFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} [FLAGS] TARGET POLICY [POLICY...] [--user USER | --group GROUP]
+  {{.HelpName}} [FLAGS] TARGET --csv FILE
 
-  Exactly one of --user or --group is required.
+  Exactly one of --user or --group is required, unless the associations
+  are read from a CSV file with one 'user|group,NAME,POLICY[,POLICY...]'
+  row per user or group.
 
 POLICY:
   Name of the policy on the MinIO server.
@@ -58,6 +61,10 @@ EXAMPLES:
      {{.Prompt}} {{.HelpName}} myminio readonly --user james
   2. Detach the "audit-policy" and "acct-policy" policies from group "legal".
      {{.Prompt}} {{.HelpName}} myminio audit-policy acct-policy --group legal
+  3. Show the policies which would be detached from the users and groups listed in "mappings.csv".
+     {{.Prompt}} {{.HelpName}} myminio --csv mappings.csv --dry-run
+  4. Detach the policies from the users and groups listed in "mappings.csv".
+     {{.Prompt}} {{.HelpName}} myminio --csv mappings.csv
 `,
 }
 
@@ -70,6 +70,15 @@ var adminReplicateStatusFlags = []cli.Flag{
 		Name:  "group",
 		Usage: "display group sync status",
 	},
+	cli.BoolFlag{
+		Name:  "watch",
+		Usage: "refresh the status periodically",
+	},
+	cli.IntFlag{
+		Name:  "interval",
+		Usage: "interval between refreshes in seconds with --watch",
+		Value: 5,
+	},
 }
 
 // Some cell values
@@ -109,6 +118,9 @@ EXAMPLES:
 
     4. Drill down and view site replication status of user "foo"
        {{.Prompt}} {{.HelpName}} minio1 --user foo
+
+    5. Watch the unsynced items and errors per peer of bucket "bucket", refreshing every 10 seconds
+       {{.Prompt}} {{.HelpName}} minio1 --bucket bucket --watch --interval 10
 `,
 }
 
@@ -118,7 +130,14 @@ type srStatus struct {
 }
 
 func (i srStatus) JSON() string {
-	bs, e := json.MarshalIndent(madmin.SRStatusInfo(i.SRStatusInfo), "", " ")
+	status := struct {
+		madmin.SRStatusInfo
+		Unsynced map[string][]string `json:"unsynced,omitempty"`
+	}{
+		SRStatusInfo: i.SRStatusInfo,
+		Unsynced:     i.unsyncedItems(),
+	}
+	bs, e := json.MarshalIndent(status, "", " ")
 	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
 	return string(bs)
 }
@@ -324,6 +343,9 @@ func (i srStatus) String() string {
 		messages = append(messages, i.getGroupStatusSummary(siteNames, nameIDMap, "Group")...)
 
 	}
+	if i.opts.Entity != madmin.Unspecified {
+		messages = append(messages, i.getEntityLagDetails(siteNames, nameIDMap)...)
+	}
 	if i.opts.Metrics {
 		uiFn := func(theme string) func(string) string {
 			return func(s string) string {
@@ -716,6 +738,112 @@ func (i srStatus) getGroupStatusSummary(siteNames []string, nameIDMap map[string
 	return messages
 }
 
+// unsyncedItems returns the items of the requested entity which are
+// missing or out of sync, keyed by site name. It returns nil if no
+// entity was requested or it does not exist on any site.
+func (i srStatus) unsyncedItems() map[string][]string {
+	items := make(map[string][]string)
+	var found bool
+	add := func(dID string, mismatch bool, item string) {
+		if mismatch {
+			name := i.Sites[dID].Name
+			items[name] = append(items[name], item)
+		}
+	}
+	for dID := range i.Sites {
+		switch i.opts.Entity {
+		case madmin.SRBucketEntity:
+			ss := i.BucketStats[i.opts.EntityValue][dID]
+			found = found || ss.HasBucket
+			add(dID, !ss.HasBucket, "bucket missing")
+			add(dID, ss.HasBucket && ss.TagMismatch, "tags")
+			add(dID, ss.HasBucket && ss.VersioningConfigMismatch, "versioning")
+			add(dID, ss.HasBucket && ss.PolicyMismatch, "policy")
+			add(dID, ss.HasBucket && ss.QuotaCfgMismatch, "quota")
+			add(dID, ss.HasBucket && ss.OLockConfigMismatch, "retention")
+			add(dID, ss.HasBucket && ss.SSEConfigMismatch, "encryption")
+			add(dID, ss.HasBucket && ss.ReplicationCfgMismatch, "replication")
+		case madmin.SRPolicyEntity:
+			ss := i.PolicyStats[i.opts.EntityValue][dID]
+			found = found || ss.HasPolicy
+			add(dID, !ss.HasPolicy, "policy missing")
+			add(dID, ss.HasPolicy && ss.PolicyMismatch, "policy")
+		case madmin.SRUserEntity:
+			ss := i.UserStats[i.opts.EntityValue][dID]
+			found = found || ss.HasUser
+			add(dID, !ss.HasUser, "user missing")
+			add(dID, ss.HasUser && ss.UserInfoMismatch, "info")
+			add(dID, ss.HasUser && ss.PolicyMismatch, "policy mapping")
+		case madmin.SRGroupEntity:
+			ss := i.GroupStats[i.opts.EntityValue][dID]
+			found = found || ss.HasGroup
+			add(dID, !ss.HasGroup, "group missing")
+			add(dID, ss.HasGroup && ss.GroupDescMismatch, "info")
+			add(dID, ss.HasGroup && ss.PolicyMismatch, "policy mapping")
+		default:
+			return nil
+		}
+	}
+	if !found {
+		return nil
+	}
+	return items
+}
+
+// getEntityLagDetails lists the unsynced items of the requested entity and
+// the replication errors seen by each peer.
+func (i srStatus) getEntityLagDetails(siteNames []string, nameIDMap map[string]string) []string {
+	unsynced := i.unsyncedItems()
+	if unsynced == nil {
+		return nil
+	}
+	messages := []string{"", console.Colorize("SummaryHdr", "Unsynced items:")}
+	if len(unsynced) == 0 {
+		messages = append(messages, console.Colorize("UserMessage", "  all sites are in sync"))
+	}
+	for _, sname := range siteNames {
+		if items, ok := unsynced[i.Sites[nameIDMap[sname]].Name]; ok {
+			messages = append(messages, fmt.Sprintf("  %-15s %s", sname, console.Colorize("WarningMessage", strings.Join(items, ", "))))
+		}
+	}
+
+	if len(i.Metrics.Metrics) == 0 {
+		return messages
+	}
+	messages = append(messages, "", console.Colorize("SummaryHdr", "Errors per peer:"))
+	for _, sname := range siteNames {
+		m, ok := i.Metrics.Metrics[nameIDMap[sname]]
+		if !ok {
+			continue
+		}
+		theme := "UserMessage"
+		if m.Failed.LastHour.Count > 0 || !m.Online {
+			theme = "WarningMessage"
+		}
+		msg := fmt.Sprintf("%s in last 1 minute; %s in last 1hr; %s since uptime",
+			humanize.Comma(int64(m.Failed.LastMinute.Count)), humanize.Comma(int64(m.Failed.LastHour.Count)),
+			humanize.Comma(int64(m.Failed.Totals.Count)))
+		if !m.Online {
+			msg += fmt.Sprintf("; offline since %s", humanize.Time(m.LastOnline))
+		}
+		messages = append(messages, fmt.Sprintf("  %-15s %s", sname, console.Colorize(theme, msg)))
+		if len(m.Failed.ErrCounts) > 0 {
+			var codes []string
+			for code, count := range m.Failed.ErrCounts {
+				codes = append(codes, fmt.Sprintf("%s (%d)", code, count))
+			}
+			sort.Strings(codes)
+			messages = append(messages, fmt.Sprintf("  %-15s last errors: %s", "", strings.Join(codes, ", ")))
+		}
+		if m.MRFStats.LastFailedCount > 0 || m.MRFStats.TotalDroppedCount > 0 {
+			messages = append(messages, fmt.Sprintf("  %-15s retry backlog: %s failed in last 5 minutes, %s dropped (%s)", "",
+				humanize.Comma(int64(m.MRFStats.LastFailedCount)), humanize.Comma(int64(m.MRFStats.TotalDroppedCount)),
+				humanize.IBytes(m.MRFStats.TotalDroppedBytes)))
+		}
+	}
+	return messages
+}
+
 // Calculate srstatus options for command line flags
 func srStatusOpts(ctx *cli.Context) (opts madmin.SRStatusOptions) {
 	if !(ctx.IsSet("buckets") ||
@@ -803,15 +931,40 @@ func mainAdminReplicationStatus(ctx *cli.Context) error {
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 	opts := srStatusOpts(ctx)
-	info, e := client.SRStatusInfo(globalContext, opts)
-	fatalIf(probe.NewError(e).Trace(args...), "Unable to get cluster replication status")
+	watch := ctx.Bool("watch")
+	interval := time.Duration(ctx.Int("interval")) * time.Second
+	if interval <= 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.String("interval")), "--interval must be positive.")
+	}
+
+	var lines int
+	for {
+		info, e := client.SRStatusInfo(globalContext, opts)
+		fatalIf(probe.NewError(e).Trace(args...), "Unable to get cluster replication status")
 
-	printMsg(srStatus{
-		SRStatusInfo: info,
-		opts:         opts,
-	})
+		status := srStatus{
+			SRStatusInfo: info,
+			opts:         opts,
+		}
+		if watch && !globalJSON {
+			// Redraw the status in place.
+			console.RewindLines(lines)
+			s := status.String()
+			lines = strings.Count(s, "\n") + 1
+			console.Println(s)
+		} else {
+			printMsg(status)
+		}
+		if !watch {
+			return nil
+		}
 
-	return nil
+		select {
+		case <-globalContext.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
 }
 
 func syncStatus(mismatch, set bool) (string, string) {
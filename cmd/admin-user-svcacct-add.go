@@ -308,52 +308,14 @@ func mainAdminUserSvcAcctAdd(ctx *cli.Context) error {
 	client, err := newAdminClient(aliasedURL)
 	fatalIf(err, "Unable to initialize admin connection.")
 
-	var policyBytes []byte
-	if policyPath != "" {
-		// Validate the policy document and ensure it has at least when statement
-		var e error
-		policyBytes, e = os.ReadFile(policyPath)
-		fatalIf(probe.NewError(e), "Unable to open the policy document.")
-		p, e := iampolicy.ParseConfig(bytes.NewReader(policyBytes))
-		fatalIf(probe.NewError(e), "Unable to parse the policy document.")
-		if p.IsEmpty() {
-			fatalIf(errInvalidArgument(), "Empty policy documents are not allowed.")
-		}
-	}
-
-	var expiryTime time.Time
-	var expiryPointer *time.Time
-
-	if expiry != "" {
-		location, e := time.LoadLocation("Local")
-		if e != nil {
-			fatalIf(probe.NewError(e), "Unable to parse the expiry argument.")
-		}
-
-		patternMatched := false
-		for _, format := range supportedTimeFormats {
-			t, e := time.ParseInLocation(format, expiry, location)
-			if e == nil {
-				patternMatched = true
-				expiryTime = t
-				expiryPointer = &expiryTime
-				break
-			}
-		}
-
-		if !patternMatched {
-			fatalIf(probe.NewError(fmt.Errorf("expiry argument is not matching any of the supported patterns")), "unable to parse the expiry argument.")
-		}
-	}
-
 	opts := madmin.AddServiceAccountReq{
-		Policy:      policyBytes,
+		Policy:      mustReadSvcAcctPolicy(policyPath),
 		AccessKey:   accessKey,
 		SecretKey:   secretKey,
 		Name:        name,
 		Description: description,
 		TargetUser:  user,
-		Expiration:  expiryPointer,
+		Expiration:  mustParseSvcAcctExpiry(expiry),
 	}
 
 	creds, e := client.AddServiceAccount(globalContext, opts)
@@ -369,3 +331,60 @@ func mainAdminUserSvcAcctAdd(ctx *cli.Context) error {
 
 	return nil
 }
+
+// readSvcAcctPolicy reads and validates the policy document of a new
+// service account, an empty path means no policy.
+func readSvcAcctPolicy(policyPath string) ([]byte, *probe.Error) {
+	if policyPath == "" {
+		return nil, nil
+	}
+	// Validate the policy document and ensure it has at least when statement
+	policyBytes, e := os.ReadFile(policyPath)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(policyPath)
+	}
+	p, e := iampolicy.ParseConfig(bytes.NewReader(policyBytes))
+	if e != nil {
+		return nil, probe.NewError(e).Trace(policyPath)
+	}
+	if p.IsEmpty() {
+		return nil, errInvalidArgument().Trace(policyPath)
+	}
+	return policyBytes, nil
+}
+
+// mustReadSvcAcctPolicy reads and validates the policy document of a
+// new service account, an empty path means no policy.
+func mustReadSvcAcctPolicy(policyPath string) []byte {
+	policyBytes, err := readSvcAcctPolicy(policyPath)
+	fatalIf(err, "Unable to read the policy document, empty policy documents are not allowed.")
+	return policyBytes
+}
+
+// parseSvcAcctExpiry parses the expiry of a new service account in
+// local time, an empty expiry means the account does not expire.
+func parseSvcAcctExpiry(expiry string) (*time.Time, *probe.Error) {
+	if expiry == "" {
+		return nil, nil
+	}
+	location, e := time.LoadLocation("Local")
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+
+	for _, format := range supportedTimeFormats {
+		t, e := time.ParseInLocation(format, expiry, location)
+		if e == nil {
+			return &t, nil
+		}
+	}
+	return nil, probe.NewError(fmt.Errorf("expiry argument is not matching any of the supported patterns")).Trace(expiry)
+}
+
+// mustParseSvcAcctExpiry parses the expiry of a new service account in
+// local time, an empty expiry means the account does not expire.
+func mustParseSvcAcctExpiry(expiry string) *time.Time {
+	expiration, err := parseSvcAcctExpiry(expiry)
+	fatalIf(err, "Unable to parse the expiry argument.")
+	return expiration
+}
@@ -101,7 +101,7 @@ func checkCredentialsSyntax(credentials aliasConfigV10) {
 
 	if credentials.API != "" && !isValidAPI(credentials.API) { // Empty value set to default "S3v4".
 		fatalIf(errInvalidArgument().Trace(credentials.API),
-			"Unrecognized API signature. Valid options are `[S3v4, S3v2]`.")
+			"Unrecognized API signature. Valid options are `[S3v4, S3v2, azure, gcs]`.")
 	}
 	if !isValidPath(credentials.Path) {
 		fatalIf(errInvalidArgument().Trace(credentials.Path),
@@ -124,12 +124,15 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 	if alias != "" {
 		if v, ok := conf.Aliases[alias]; ok {
 			aliasMsg := aliasMessage{
-				prettyPrint: false,
-				Alias:       alias,
-				URL:         v.URL,
-				AccessKey:   v.AccessKey,
-				SecretKey:   v.SecretKey,
-				API:         v.API,
+				prettyPrint:     false,
+				Alias:           alias,
+				URL:             v.URL,
+				AccessKey:       v.AccessKey,
+				SecretKey:       v.SecretKey,
+				API:             v.API,
+				RequestPayer:    v.RequestPayer,
+				CredentialsFile: v.CredentialsFile,
+				TLS:             v.TLS,
 			}
 
 			if deprecated {
@@ -145,12 +148,15 @@ func listAliases(alias string, deprecated bool) (aliases []aliasMessage) {
 
 	for k, v := range conf.Aliases {
 		aliasMsg := aliasMessage{
-			prettyPrint: true,
-			Alias:       k,
-			URL:         v.URL,
-			AccessKey:   v.AccessKey,
-			SecretKey:   v.SecretKey,
-			API:         v.API,
+			prettyPrint:     true,
+			Alias:           k,
+			URL:             v.URL,
+			AccessKey:       v.AccessKey,
+			SecretKey:       v.SecretKey,
+			API:             v.API,
+			RequestPayer:    v.RequestPayer,
+			CredentialsFile: v.CredentialsFile,
+			TLS:             v.TLS,
 		}
 
 		if deprecated {
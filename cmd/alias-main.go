@@ -42,6 +42,8 @@ var aliasSubcommands = []cli.Command{
 	aliasListCmd,
 	aliasRemoveCmd,
 	aliasImportCmd,
+	aliasStatsCmd,
+	aliasTempCmd,
 }
 
 var aliasCmd = cli.Command{
@@ -72,6 +74,12 @@ type aliasMessage struct {
 	SecretKey   string `json:"secretKey,omitempty"`
 	API         string `json:"api,omitempty"`
 	Path        string `json:"path,omitempty"`
+	// Request payer sent by default, only set for Requester Pays aliases
+	RequestPayer string `json:"requestPayer,omitempty"`
+	// Service account key file, only set for GCS aliases
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+	// TLS baseline, only set for aliases with one
+	TLS *aliasTLSConfigV10 `json:"tls,omitempty"`
 	// Deprecated field, replaced by Path
 	Lookup string `json:"lookup,omitempty"`
 }
@@ -27,6 +27,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -48,7 +49,23 @@ var aliasSetFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:  "api",
-		Usage: "API signature. Valid options are '[S3v4, S3v2]'",
+		Usage: "API signature. Valid options are '[S3v4, S3v2, azure, gcs]'",
+	},
+	cli.StringFlag{
+		Name:  "request-payer",
+		Usage: "confirm paying for requests to Requester Pays buckets by default. Valid option is '[requester]'",
+	},
+	cli.StringFlag{
+		Name:  "tls-min-version",
+		Usage: "minimum TLS version. Valid options are '[1.2, 1.3]'",
+	},
+	cli.StringFlag{
+		Name:  "tls-ciphers",
+		Usage: "comma separated TLS 1.2 cipher suites in order of preference, e.g. 'TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384'",
+	},
+	cli.StringFlag{
+		Name:  "tls-curves",
+		Usage: "comma separated key exchange curves in order of preference. Valid options are '[X25519, P256, P384, P521]'",
 	},
 }
 
@@ -96,6 +113,21 @@ EXAMPLES:
      {{.Prompt}} echo -e "BKIKJAA5BMMU2RHO6IBB\nV8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12" | \
                  {{.HelpName}} mys3 https://s3.amazonaws.com --api "s3v4" --path "off"
      {{.EnableHistory}}
+  6. Add Amazon S3 storage service under "mys3" alias, paying for requests to Requester Pays buckets by default.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} mys3 https://s3.amazonaws.com \
+                 BKIKJAA5BMMU2RHO6IBB V8f1CwQqAcwo80UEIJEjc5gVQUSSx5ohQ9GSrr12 --request-payer requester
+     {{.EnableHistory}}
+  7. Add MinIO service under "secure" alias, only speaking TLS 1.3 with X25519 or P-384 key exchanges.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} secure https://minio.example.com minio minio123 --tls-min-version 1.3 --tls-curves X25519,P384
+     {{.EnableHistory}}
+  8. Add Azure Blob Storage account "myaccount" under "az" alias, with the account key as secret key.
+     {{.DisableHistory}}
+     {{.Prompt}} {{.HelpName}} az https://myaccount.blob.core.windows.net myaccount ACCOUNT-KEY
+     {{.EnableHistory}}
+  9. Add Google Cloud Storage project "my-project" under "gcs" alias, through the JSON API with a service account key.
+     {{.Prompt}} {{.HelpName}} gcs https://storage.googleapis.com my-project ~/.config/gcloud/my-project-key.json --api gcs
 `,
 }
 
@@ -127,19 +159,38 @@ func checkAliasSetSyntax(ctx *cli.Context, accessKey, secretKey string, deprecat
 		fatalIf(errInvalidURL(url), "Invalid URL.")
 	}
 
-	if !isValidAccessKey(accessKey) {
+	isSFTP := strings.HasPrefix(url, "sftp://")
+	if isSFTP && api != "" {
+		fatalIf(errInvalidArgument().Trace(api), "--api is not supported for sftp URLs.")
+	}
+
+	// SFTP users and passwords are not limited to the lengths of S3 keys.
+	if !isSFTP && !isValidAccessKey(accessKey) {
 		fatalIf(errInvalidArgument().Trace(accessKey),
 			"Invalid access key `"+accessKey+"`.")
 	}
 
-	if !isValidSecretKey(secretKey) {
+	if !isSFTP && !isValidSecretKey(secretKey) {
 		fatalIf(errInvalidArgument().Trace(secretKey),
 			"Invalid secret key `"+secretKey+"`.")
 	}
 
 	if api != "" && !isValidAPI(api) { // Empty value set to default "S3v4".
 		fatalIf(errInvalidArgument().Trace(api),
-			"Unrecognized API signature. Valid options are `[S3v4, S3v2]`.")
+			"Unrecognized API signature. Valid options are `[S3v4, S3v2, azure, gcs]`.")
+	}
+
+	if requestPayer := ctx.String("request-payer"); requestPayer != "" && !isValidRequestPayer(requestPayer) {
+		fatalIf(errInvalidArgument().Trace(requestPayer),
+			"Unrecognized request payer. Valid option is `[requester]`.")
+	}
+
+	tlsConfig := newAliasTLSConfig(ctx.String("tls-min-version"), ctx.String("tls-ciphers"), ctx.String("tls-curves"))
+	if _, e := parseAliasTLSConfig(tlsConfig); e != nil {
+		fatalIf(probe.NewError(e), "Invalid TLS settings.")
+	}
+	if tlsConfig != nil && !strings.HasPrefix(url, "https://") {
+		fatalIf(errInvalidArgument().Trace(url), "TLS settings require an https URL.")
 	}
 
 	if deprecated {
@@ -167,12 +218,14 @@ func setAlias(alias string, aliasCfgV10 aliasConfigV10) aliasMessage {
 	fatalIf(err.Trace(alias), "Unable to update hosts in config version `"+mustGetMcConfigPath()+"`.")
 
 	return aliasMessage{
-		Alias:     alias,
-		URL:       aliasCfgV10.URL,
-		AccessKey: aliasCfgV10.AccessKey,
-		SecretKey: aliasCfgV10.SecretKey,
-		API:       aliasCfgV10.API,
-		Path:      aliasCfgV10.Path,
+		Alias:           alias,
+		URL:             aliasCfgV10.URL,
+		AccessKey:       aliasCfgV10.AccessKey,
+		SecretKey:       aliasCfgV10.SecretKey,
+		API:             aliasCfgV10.API,
+		Path:            aliasCfgV10.Path,
+		RequestPayer:    aliasCfgV10.RequestPayer,
+		CredentialsFile: aliasCfgV10.CredentialsFile,
 	}
 }
 
@@ -188,10 +241,14 @@ func probeS3Signature(ctx context.Context, accessKey, secretKey, url string, pee
 		SecretKey:         secretKey,
 		HostURL:           urlJoinPath(url, probeBucketName),
 		Debug:             globalDebug,
+		DebugLite:         globalDebugLite,
 		ConnReadDeadline:  globalConnReadDeadline,
 		ConnWriteDeadline: globalConnWriteDeadline,
 		UploadLimit:       int64(globalLimitUpload),
 		DownloadLimit:     int64(globalLimitDownload),
+		OpsLimit:          int64(globalOpsLimit),
+		CustomHeaders:     globalCustomHeaders,
+		CustomQuery:       globalCustomQuery,
 	}
 	if peerCert != nil {
 		configurePeerCertificate(s3Config, peerCert)
@@ -331,6 +388,27 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 	accessKey, secretKey := fetchAliasKeys(args)
 	checkAliasSetSyntax(cli, accessKey, secretKey, deprecated)
 
+	if strings.HasPrefix(url, "sftp://") {
+		// SFTP servers are reached over SSH, the user may be given in the URL.
+		user, host := splitSFTPUser(strings.TrimPrefix(url, "sftp://"))
+		if accessKey == "" {
+			accessKey = user
+		}
+		msg := setAlias(alias, aliasConfigV10{
+			URL:       "sftp://" + host,
+			AccessKey: accessKey,
+			SecretKey: secretKey,
+			API:       sftpAPI,
+			Path:      path,
+		})
+		msg.op = "set"
+		if deprecated {
+			msg.op = "add"
+		}
+		printMsg(msg)
+		return nil
+	}
+
 	ctx, cancelAliasAdd := context.WithCancel(globalContext)
 	defer cancelAliasAdd()
 
@@ -339,15 +417,33 @@ func mainAliasSet(cli *cli.Context, deprecated bool) error {
 		fatalIf(err.Trace(alias, url, accessKey), "Unable to initialize new alias from the provided credentials.")
 	}
 
+	// Azure Blob Storage is not probed for an S3 signature, the
+	// account name and key are used as access and secret keys.
+	if api == "" && isAzureBlob(newClientURL(url).Host) {
+		api = azureAPI
+	}
+
+	// The service account key file of GCS is given in place of the
+	// secret key, it is kept apart and referred to from any directory.
+	var credentialsFile string
+	if strings.EqualFold(api, gcsAPI) && secretKey != "" {
+		keyFile, e := filepath.Abs(secretKey)
+		fatalIf(probe.NewError(e).Trace(secretKey), "Unable to locate the service account key.")
+		credentialsFile, secretKey = keyFile, ""
+	}
+
 	s3Config, err := BuildS3Config(ctx, url, accessKey, secretKey, api, path, peerCert)
 	fatalIf(err.Trace(alias, url, accessKey), "Unable to initialize new alias from the provided credentials.")
 
 	msg := setAlias(alias, aliasConfigV10{
-		URL:       s3Config.HostURL,
-		AccessKey: s3Config.AccessKey,
-		SecretKey: s3Config.SecretKey,
-		API:       s3Config.Signature,
-		Path:      path,
+		URL:             s3Config.HostURL,
+		AccessKey:       s3Config.AccessKey,
+		SecretKey:       s3Config.SecretKey,
+		API:             s3Config.Signature,
+		Path:            path,
+		RequestPayer:    strings.ToLower(cli.String("request-payer")),
+		CredentialsFile: credentialsFile,
+		TLS:             newAliasTLSConfig(cli.String("tls-min-version"), cli.String("tls-ciphers"), cli.String("tls-curves")),
 	}) // Add an alias with specified credentials.
 
 	msg.op = "set"
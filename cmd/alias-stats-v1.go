@@ -0,0 +1,264 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/env"
+	"github.com/minio/pkg/quick"
+)
+
+// Environment variable enabling the request accounting per alias, a
+// comma separated list of 'print' to print the accounting of the run
+// on exit and 'save' to add it to the stats file.
+const envAliasStats = "MC_ALIAS_STATS"
+
+// aliasStatsEntry is the request accounting of an alias.
+type aliasStatsEntry struct {
+	Requests     int64 `json:"requests"`
+	Errors       int64 `json:"errors"`
+	ClientErrors int64 `json:"clientErrors"`
+	BytesUp      int64 `json:"bytesUp"`
+	BytesDown    int64 `json:"bytesDown"`
+}
+
+// add adds the counters of o to e.
+func (e *aliasStatsEntry) add(o aliasStatsEntry) {
+	e.Requests += o.Requests
+	e.Errors += o.Errors
+	e.ClientErrors += o.ClientErrors
+	e.BytesUp += o.BytesUp
+	e.BytesDown += o.BytesDown
+}
+
+// aliasRequestStats counts the requests of an alias during a run, it
+// is safe for concurrent use.
+type aliasRequestStats struct {
+	requests     int64
+	errors       int64
+	clientErrors int64
+	bytesUp      int64
+	bytesDown    int64
+}
+
+func (s *aliasRequestStats) entry() aliasStatsEntry {
+	return aliasStatsEntry{
+		Requests:     atomic.LoadInt64(&s.requests),
+		Errors:       atomic.LoadInt64(&s.errors),
+		ClientErrors: atomic.LoadInt64(&s.clientErrors),
+		BytesUp:      atomic.LoadInt64(&s.bytesUp),
+		BytesDown:    atomic.LoadInt64(&s.bytesDown),
+	}
+}
+
+// globalAliasStats holds the *aliasRequestStats of every alias used
+// during the run.
+var globalAliasStats sync.Map
+
+// getAliasRequestStats returns the request accounting of an alias.
+func getAliasRequestStats(alias string) *aliasRequestStats {
+	stats, _ := globalAliasStats.LoadOrStore(alias, &aliasRequestStats{})
+	return stats.(*aliasRequestStats)
+}
+
+// aliasStatsOfRun returns the request accounting of the run so far.
+func aliasStatsOfRun() map[string]aliasStatsEntry {
+	entries := make(map[string]aliasStatsEntry)
+	globalAliasStats.Range(func(alias, stats interface{}) bool {
+		if entry := stats.(*aliasRequestStats).entry(); entry.Requests > 0 {
+			entries[alias.(string)] = entry
+		}
+		return true
+	})
+	return entries
+}
+
+// aliasStatsTransport counts the requests, the bytes sent and received
+// and the failed requests of an alias.
+type aliasStatsTransport struct {
+	stats     *aliasRequestStats
+	transport http.RoundTripper
+}
+
+func (t aliasStatsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&t.stats.requests, 1)
+	if req.ContentLength > 0 {
+		atomic.AddInt64(&t.stats.bytesUp, req.ContentLength)
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		atomic.AddInt64(&t.stats.errors, 1)
+		return resp, e
+	}
+	switch {
+	case resp.StatusCode >= 500:
+		atomic.AddInt64(&t.stats.errors, 1)
+	case resp.StatusCode >= 400:
+		atomic.AddInt64(&t.stats.clientErrors, 1)
+	}
+	if resp.Body != nil {
+		resp.Body = &countingReadCloser{ReadCloser: resp.Body, n: &t.stats.bytesDown}
+	}
+	return resp, e
+}
+
+// countingReadCloser adds the bytes read to a counter.
+type countingReadCloser struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, e := r.ReadCloser.Read(p)
+	atomic.AddInt64(r.n, int64(n))
+	return n, e
+}
+
+// aliasStatsModes returns whether the accounting of the run is printed
+// and saved, as set by MC_ALIAS_STATS.
+func aliasStatsModes() (printStats, saveStats bool) {
+	for _, mode := range strings.Split(env.Get(envAliasStats, ""), ",") {
+		switch strings.ToLower(strings.TrimSpace(mode)) {
+		case "print":
+			printStats = true
+		case "save", "on":
+			saveStats = true
+		}
+	}
+	return printStats, saveStats
+}
+
+// flushAliasStats prints and saves the request accounting of the run
+// as set by MC_ALIAS_STATS, it is registered as an exit hook.
+func flushAliasStats() {
+	printStats, saveStats := aliasStatsModes()
+	if !printStats && !saveStats {
+		return
+	}
+	entries := aliasStatsOfRun()
+	if len(entries) == 0 {
+		return
+	}
+	if printStats {
+		// Reports go to stderr so that they never mix with the output
+		// of the command.
+		msg := aliasStatsMessage{Status: "success", Aliases: aliasStatsList(entries)}
+		if globalJSON {
+			console.Debugln(msg.JSON())
+		} else {
+			console.Debugln(msg.String())
+		}
+	}
+	if saveStats {
+		errorIf(saveAliasStats(entries), "Unable to save alias stats.")
+	}
+}
+
+// JSON file to accumulate the request accounting of aliases across
+// runs, when MC_ALIAS_STATS contains 'save'.
+type aliasStatsV1 struct {
+	Version string    `json:"version"`
+	Since   time.Time `json:"since"`
+
+	// key is the alias.
+	Aliases map[string]aliasStatsEntry `json:"aliases"`
+}
+
+// Instantiate a new alias stats structure for persistence.
+func newAliasStatsV1() *aliasStatsV1 {
+	return &aliasStatsV1{
+		Version: "1",
+		Since:   time.Now().UTC(),
+		Aliases: make(map[string]aliasStatsEntry),
+	}
+}
+
+// loadAliasStats loads the alias stats file, a missing file is empty.
+func loadAliasStats() (*aliasStatsV1, *probe.Error) {
+	filename := mustGetAliasStatsFile()
+	if _, e := os.Stat(filename); os.IsNotExist(e) {
+		return newAliasStatsV1(), nil
+	}
+	qs, e := quick.NewConfig(newAliasStatsV1(), nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Load(filename); e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	stats := qs.Data().(*aliasStatsV1)
+	if stats.Aliases == nil {
+		stats.Aliases = make(map[string]aliasStatsEntry)
+	}
+	return stats, nil
+}
+
+// save persists the alias stats to disk.
+func (s *aliasStatsV1) save() *probe.Error {
+	filename := mustGetAliasStatsFile()
+	qs, e := quick.NewConfig(s, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Save(filename); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// saveAliasStats adds the accounting of a run to the alias stats file.
+func saveAliasStats(entries map[string]aliasStatsEntry) *probe.Error {
+	stats, err := loadAliasStats()
+	if err != nil {
+		return err.Trace()
+	}
+	for alias, entry := range entries {
+		total := stats.Aliases[alias]
+		total.add(entry)
+		stats.Aliases[alias] = total
+	}
+	return stats.save().Trace()
+}
+
+// aliasStatsList returns the accounting of every alias sorted by alias.
+func aliasStatsList(entries map[string]aliasStatsEntry) []aliasStatsItem {
+	items := make([]aliasStatsItem, 0, len(entries))
+	for alias, entry := range entries {
+		items = append(items, aliasStatsItem{Alias: alias, aliasStatsEntry: entry})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Alias < items[j].Alias
+	})
+	return items
+}
+
+// Get alias stats file name or die. (NOTE: This `Die` approach is only OK for mc like tools.).
+func mustGetAliasStatsFile() string {
+	return filepath.Join(mustGetMcConfigDir(), globalMCAliasStatsFile)
+}
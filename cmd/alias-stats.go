@@ -0,0 +1,175 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var aliasStatsFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "reset",
+		Usage: "clear the saved stats of all aliases, or of the given alias",
+	},
+}
+
+var aliasStatsCmd = cli.Command{
+	Name:  "stats",
+	Usage: "show requests, bytes and errors per alias",
+	Action: func(ctx *cli.Context) error {
+		return mainAliasStats(ctx)
+	},
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasStatsFlags, globalFlags...),
+	HideHelpCommand: true,
+	OnUsageError:    onUsageError,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [ALIAS]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ALIAS_STATS:  comma separated list of 'print' to print the requests of each alias when a command
+                   exits and 'save' to add them to the stats shown by this command
+
+DESCRIPTION:
+  Every request sent to an alias is counted with the bytes uploaded and downloaded,
+  the errors of the server or network and the requests rejected by the server.
+  Set MC_ALIAS_STATS=save in the environment of a workflow to accumulate its stats.
+
+EXAMPLES:
+  1. Show the saved stats of all aliases.
+     {{.Prompt}} {{.HelpName}}
+
+  2. Show the saved stats of alias 's3'.
+     {{.Prompt}} {{.HelpName}} s3
+
+  3. Measure the egress of a nightly backup, starting from zero.
+     {{.Prompt}} {{.HelpName}} --reset s3
+     {{.Prompt}} MC_ALIAS_STATS=save mc mirror s3/data/ ~/backup/
+     {{.Prompt}} {{.HelpName}} s3
+
+  4. Print the requests sent to each alias by a single command.
+     {{.Prompt}} MC_ALIAS_STATS=print mc cp --recursive s3/data/ gcs/data/
+`,
+}
+
+// aliasStatsItem is the request accounting of one alias.
+type aliasStatsItem struct {
+	Alias string `json:"alias"`
+	aliasStatsEntry
+}
+
+// aliasStatsMessage container for the request accounting of aliases.
+type aliasStatsMessage struct {
+	Status  string           `json:"status"`
+	Since   *time.Time       `json:"since,omitempty"`
+	Aliases []aliasStatsItem `json:"aliases"`
+}
+
+func (m aliasStatsMessage) String() string {
+	var b strings.Builder
+	if m.Since != nil {
+		b.WriteString(console.Colorize("AliasStatsSince", "Since "+m.Since.Local().Format(printDate)))
+	}
+	if len(m.Aliases) == 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("No requests recorded.")
+	}
+	for _, item := range m.Aliases {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		errorRate := 0.0
+		if item.Requests > 0 {
+			errorRate = 100 * float64(item.Errors) / float64(item.Requests)
+		}
+		b.WriteString(console.Colorize("Alias", fmt.Sprintf("%-10s", item.Alias)))
+		fmt.Fprintf(&b, " requests: %d, up: %s, down: %s, errors: %d (%.2f%%), rejected: %d",
+			item.Requests, humanize.IBytes(uint64(item.BytesUp)), humanize.IBytes(uint64(item.BytesDown)),
+			item.Errors, errorRate, item.ClientErrors)
+	}
+	return b.String()
+}
+
+func (m aliasStatsMessage) JSON() string {
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkAliasStatsSyntax - verifies input arguments to 'alias stats'.
+func checkAliasStatsSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) > 1 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for alias stats command.")
+	}
+}
+
+// mainAliasStats is the handle for "mc alias stats" command.
+func mainAliasStats(ctx *cli.Context) error {
+	checkAliasStatsSyntax(ctx)
+
+	console.SetColor("Alias", color.New(color.FgCyan, color.Bold))
+	console.SetColor("AliasStatsSince", color.New(color.FgYellow))
+
+	alias := cleanAlias(ctx.Args().Get(0))
+
+	stats, err := loadAliasStats()
+	fatalIf(err.Trace(), "Unable to load alias stats.")
+
+	if ctx.Bool("reset") {
+		if alias == "" {
+			stats = newAliasStatsV1()
+		} else {
+			delete(stats.Aliases, alias)
+		}
+		fatalIf(stats.save().Trace(alias), "Unable to reset alias stats.")
+	}
+
+	entries := stats.Aliases
+	if alias != "" {
+		entries = map[string]aliasStatsEntry{}
+		if entry, ok := stats.Aliases[alias]; ok {
+			entries[alias] = entry
+		}
+	}
+
+	printMsg(aliasStatsMessage{
+		Status:  "success",
+		Since:   &stats.Since,
+		Aliases: aliasStatsList(entries),
+	})
+	return nil
+}
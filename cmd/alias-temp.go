@@ -0,0 +1,251 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	jsoncolor "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/pkg/console"
+)
+
+const (
+	// Bounds of the lifetime of STS credentials.
+	aliasTempMinExpiry = 15 * time.Minute
+	aliasTempMaxExpiry = 365 * 24 * time.Hour
+)
+
+var aliasTempFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "prefix",
+		Usage: "restrict access to BUCKET[/PREFIX], can be repeated",
+	},
+	cli.BoolFlag{
+		Name:  "read-only",
+		Usage: "only allow listing and downloading objects",
+	},
+	cli.StringFlag{
+		Name:  "policy",
+		Usage: "path to a JSON session policy, instead of --prefix and --read-only",
+	},
+	cli.DurationFlag{
+		Name:  "expiry",
+		Usage: "lifetime of the credentials, from 15m",
+		Value: time.Hour,
+	},
+}
+
+var aliasTempCmd = cli.Command{
+	Name:            "temp",
+	Usage:           "add an alias with temporary credentials narrowed down from an existing alias",
+	Action:          mainAliasTemp,
+	Before:          setGlobalsFromContext,
+	Flags:           append(aliasTempFlags, globalFlags...),
+	HideHelpCommand: true,
+	OnUsageError:    onUsageError,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS NEW_ALIAS
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Temporary credentials are requested with the credentials of ALIAS from its STS API,
+  narrowed down by a session policy: they can never do more than ALIAS itself.
+  NEW_ALIAS stops working once the credentials expire, remove it with 'mc alias remove'.
+
+EXAMPLES:
+  1. Add an alias 'reports' allowed to read 'mybucket/reports/' for 2 hours.
+     {{.Prompt}} {{.HelpName}} --prefix mybucket/reports/ --read-only --expiry 2h myminio reports
+
+  2. Hand the temporary credentials of an alias restricted to two buckets to a script.
+     {{.Prompt}} {{.HelpName}} --json --prefix logs --prefix metrics myminio ingest
+
+  3. Add an alias whose access is set by a custom session policy.
+     {{.Prompt}} {{.HelpName}} --policy ./session-policy.json myminio scoped
+`,
+}
+
+// aliasTempMessage container for temporary alias messages.
+type aliasTempMessage struct {
+	Status       string    `json:"status"`
+	Alias        string    `json:"alias"`
+	SourceAlias  string    `json:"sourceAlias"`
+	URL          string    `json:"URL"`
+	AccessKey    string    `json:"accessKey"`
+	SecretKey    string    `json:"secretKey"`
+	SessionToken string    `json:"sessionToken"`
+	Expiration   time.Time `json:"expiration"`
+}
+
+func (m aliasTempMessage) String() string {
+	return console.Colorize("AliasMessage", "Added `"+m.Alias+"` with temporary credentials of `"+
+		m.SourceAlias+"` expiring at "+m.Expiration.Local().Format(printDate)+".")
+}
+
+func (m aliasTempMessage) JSON() string {
+	jsonMessageBytes, e := jsoncolor.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// checkAliasTempSyntax - verifies input arguments to 'alias temp'.
+func checkAliasTempSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for alias temp command.")
+	}
+	if !isValidAlias(cleanAlias(args.Get(1))) {
+		fatalIf(errInvalidAlias(args.Get(1)).Trace(args.Get(1)), "Invalid alias.")
+	}
+	if ctx.String("policy") != "" && (len(ctx.StringSlice("prefix")) > 0 || ctx.Bool("read-only")) {
+		fatalIf(errInvalidArgument().Trace(ctx.String("policy")),
+			"--policy cannot be used with --prefix or --read-only.")
+	}
+	if expiry := ctx.Duration("expiry"); expiry < aliasTempMinExpiry || expiry > aliasTempMaxExpiry {
+		fatalIf(errInvalidArgument().Trace(expiry.String()),
+			"--expiry must be between "+aliasTempMinExpiry.String()+" and "+aliasTempMaxExpiry.String()+".")
+	}
+}
+
+// aliasTempPolicyStatement is a statement of a session policy.
+type aliasTempPolicyStatement struct {
+	Effect    string                         `json:"Effect"`
+	Action    []string                       `json:"Action"`
+	Resource  []string                       `json:"Resource"`
+	Condition map[string]map[string][]string `json:"Condition,omitempty"`
+}
+
+// aliasTempPolicy returns a session policy allowing access to prefixes,
+// BUCKET[/PREFIX], or to every bucket if there are none.
+func aliasTempPolicy(prefixes []string, readOnly bool) string {
+	objectActions := []string{"s3:*"}
+	bucketActions := []string{"s3:*"}
+	if readOnly {
+		objectActions = []string{"s3:GetObject", "s3:GetObjectVersion", "s3:GetObjectTagging", "s3:GetObjectRetention", "s3:GetObjectLegalHold"}
+		bucketActions = []string{"s3:ListBucket", "s3:ListBucketVersions", "s3:GetBucketLocation"}
+	}
+
+	if len(prefixes) == 0 {
+		prefixes = []string{"*"}
+	}
+	var statements []aliasTempPolicyStatement
+	for _, prefix := range prefixes {
+		bucket, objectPrefix, _ := strings.Cut(strings.TrimPrefix(prefix, "/"), "/")
+		statements = append(statements, aliasTempPolicyStatement{
+			Effect:   "Allow",
+			Action:   objectActions,
+			Resource: []string{"arn:aws:s3:::" + bucket + "/" + objectPrefix + "*"},
+		})
+		bucketStatement := aliasTempPolicyStatement{
+			Effect:   "Allow",
+			Action:   bucketActions,
+			Resource: []string{"arn:aws:s3:::" + bucket},
+		}
+		if objectPrefix != "" {
+			bucketStatement.Condition = map[string]map[string][]string{
+				"StringLike": {"s3:prefix": {objectPrefix + "*"}},
+			}
+		}
+		statements = append(statements, bucketStatement)
+	}
+
+	policy, _ := json.Marshal(struct {
+		Version   string                     `json:"Version"`
+		Statement []aliasTempPolicyStatement `json:"Statement"`
+	}{"2012-10-17", statements})
+	return string(policy)
+}
+
+// mainAliasTemp is the handle for "mc alias temp" command.
+func mainAliasTemp(ctx *cli.Context) error {
+	checkAliasTempSyntax(ctx)
+
+	console.SetColor("AliasMessage", color.New(color.FgGreen))
+
+	sourceAlias := cleanAlias(ctx.Args().Get(0))
+	alias := cleanAlias(ctx.Args().Get(1))
+	expiry := ctx.Duration("expiry")
+
+	sourceCfg := mustGetHostConfig(sourceAlias)
+	if sourceCfg == nil {
+		fatalIf(errInvalidAliasedURL(sourceAlias).Trace(sourceAlias), "No such alias `"+sourceAlias+"` found.")
+	}
+	if sourceCfg.SessionToken != "" {
+		fatalIf(errInvalidArgument().Trace(sourceAlias),
+			"Alias `"+sourceAlias+"` already has temporary credentials, they cannot be narrowed down further.")
+	}
+
+	policy := aliasTempPolicy(ctx.StringSlice("prefix"), ctx.Bool("read-only"))
+	if policyFile := ctx.String("policy"); policyFile != "" {
+		policyBytes, e := os.ReadFile(policyFile)
+		fatalIf(probe.NewError(e).Trace(policyFile), "Unable to read the session policy.")
+		policy = string(policyBytes)
+	}
+
+	stsClient := httpClient(30 * time.Second)
+	stsClient.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify = globalInsecure
+	expiration := time.Now().Add(expiry)
+	creds := credentials.New(&credentials.STSAssumeRole{
+		Client:      stsClient,
+		STSEndpoint: sourceCfg.URL,
+		Options: credentials.STSAssumeRoleOptions{
+			AccessKey:       sourceCfg.AccessKey,
+			SecretKey:       sourceCfg.SecretKey,
+			Policy:          policy,
+			DurationSeconds: int(expiry.Seconds()),
+		},
+	})
+	value, e := creds.Get()
+	fatalIf(probe.NewError(e).Trace(sourceAlias), "Unable to request temporary credentials.")
+
+	setAlias(alias, aliasConfigV10{
+		URL:          sourceCfg.URL,
+		AccessKey:    value.AccessKeyID,
+		SecretKey:    value.SecretAccessKey,
+		SessionToken: value.SessionToken,
+		API:          sourceCfg.API,
+		Path:         sourceCfg.Path,
+		RequestPayer: sourceCfg.RequestPayer,
+		TLS:          sourceCfg.TLS,
+	})
+
+	printMsg(aliasTempMessage{
+		Status:       "success",
+		Alias:        alias,
+		SourceAlias:  sourceAlias,
+		URL:          sourceCfg.URL,
+		AccessKey:    value.AccessKeyID,
+		SecretKey:    value.SecretAccessKey,
+		SessionToken: value.SessionToken,
+		Expiration:   expiration,
+	})
+	return nil
+}
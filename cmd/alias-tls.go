@@ -0,0 +1,136 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// aliasTLSConfigV10 is the TLS baseline of an alias, stricter than the
+// default one of mc.
+type aliasTLSConfigV10 struct {
+	// Minimum TLS version, '1.2' or '1.3'.
+	MinVersion string `json:"minVersion,omitempty"`
+	// TLS 1.2 cipher suites in order of preference, TLS 1.3 suites are
+	// not configurable.
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+	// Key exchange curves in order of preference.
+	Curves []string `json:"curves,omitempty"`
+}
+
+// tlsCurves are the curves accepted by --tls-curves.
+var tlsCurves = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+// tlsSettings is the parsed TLS baseline of an alias.
+type tlsSettings struct {
+	minVersion   uint16
+	cipherSuites []uint16
+	curves       []tls.CurveID
+}
+
+// parseAliasTLSConfig parses the TLS baseline of an alias, a nil config
+// keeps the defaults.
+func parseAliasTLSConfig(c *aliasTLSConfigV10) (s tlsSettings, e error) {
+	if c == nil {
+		return s, nil
+	}
+	switch c.MinVersion {
+	case "":
+	case "1.2":
+		s.minVersion = tls.VersionTLS12
+	case "1.3":
+		s.minVersion = tls.VersionTLS13
+	default:
+		return s, fmt.Errorf("unsupported minimum TLS version `%s`, valid options are `[1.2, 1.3]`", c.MinVersion)
+	}
+
+	for _, name := range c.CipherSuites {
+		id, ok := tlsCipherSuiteID(name)
+		if !ok {
+			return s, fmt.Errorf("unsupported or insecure TLS cipher suite `%s`", name)
+		}
+		s.cipherSuites = append(s.cipherSuites, id)
+	}
+
+	for _, name := range c.Curves {
+		id, ok := tlsCurves[strings.ToUpper(strings.ReplaceAll(name, "-", ""))]
+		if !ok {
+			return s, fmt.Errorf("unsupported TLS curve `%s`, valid options are `[X25519, P256, P384, P521]`", name)
+		}
+		s.curves = append(s.curves, id)
+	}
+	return s, nil
+}
+
+// tlsCipherSuiteID returns the ID of a secure cipher suite by name.
+func tlsCipherSuiteID(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if strings.EqualFold(suite.Name, name) {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// apply restricts a TLS client config to the baseline, it never lowers
+// the minimum version of mc.
+func (s tlsSettings) apply(tlsConfig *tls.Config) {
+	if s.minVersion > tlsConfig.MinVersion {
+		tlsConfig.MinVersion = s.minVersion
+	}
+	if len(s.cipherSuites) > 0 {
+		tlsConfig.CipherSuites = s.cipherSuites
+	}
+	if len(s.curves) > 0 {
+		tlsConfig.CurvePreferences = s.curves
+	}
+}
+
+// String describes the baseline to tell clients apart.
+func (s tlsSettings) String() string {
+	return fmt.Sprint(s.minVersion, s.cipherSuites, s.curves)
+}
+
+// newAliasTLSConfig returns the TLS baseline set by the flags of 'alias
+// set', nil if none is set.
+func newAliasTLSConfig(minVersion, cipherSuites, curves string) *aliasTLSConfigV10 {
+	splitList := func(s string) (l []string) {
+		for _, v := range strings.Split(s, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				l = append(l, v)
+			}
+		}
+		return l
+	}
+	c := &aliasTLSConfigV10{
+		MinVersion:   minVersion,
+		CipherSuites: splitList(cipherSuites),
+		Curves:       splitList(curves),
+	}
+	if c.MinVersion == "" && len(c.CipherSuites) == 0 && len(c.Curves) == 0 {
+		return nil
+	}
+	return c
+}
@@ -20,9 +20,11 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -56,6 +58,8 @@ USAGE:
   {{.HelpName}} [FLAGS] get TARGET
   {{.HelpName}} [FLAGS] get-json TARGET
   {{.HelpName}} [FLAGS] list TARGET
+  {{.HelpName}} [FLAGS] links TARGET
+  {{.HelpName}} [FLAGS] audit TARGET
 {{if .VisibleFlags}}
 FLAGS:
   {{range .VisibleFlags}}{{.}}
@@ -93,6 +97,12 @@ EXAMPLES:
 
   9. List public object URLs recursively.
      {{.Prompt}} {{.HelpName}} --recursive links s3/shared/
+
+  10. Audit all buckets of an alias for prefixes which are publicly readable or writable.
+     {{.Prompt}} {{.HelpName}} audit s3
+
+  11. Audit a bucket and enumerate every object which can be downloaded anonymously.
+     {{.Prompt}} {{.HelpName}} --recursive audit s3/shared
 `,
 }
 
@@ -173,6 +183,70 @@ func (s anonymousLinksMessage) JSON() string {
 	return string(anonymousJSONBytes)
 }
 
+// anonymousAuditMessage is container for a publicly accessible
+// prefix or object found by the anonymous audit command.
+type anonymousAuditMessage struct {
+	Status     string      `json:"status"`
+	Resource   string      `json:"resource"`
+	Object     string      `json:"object,omitempty"`
+	Permission accessPerms `json:"permission"`
+	Read       bool        `json:"read"`
+	Write      bool        `json:"write"`
+}
+
+// String colorized audit message.
+func (s anonymousAuditMessage) String() string {
+	if s.Object != "" {
+		return console.Colorize("Anonymous", "  "+s.Object)
+	}
+	var access []string
+	if s.Read {
+		access = append(access, "read")
+	}
+	if s.Write {
+		access = append(access, "write")
+	}
+	return console.Colorize("AnonymousAccess", fmt.Sprintf("%-11s", strings.Join(access, ","))) +
+		console.Colorize("Anonymous", s.Resource)
+}
+
+// JSON jsonified audit message.
+func (s anonymousAuditMessage) JSON() string {
+	anonymousJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(anonymousJSONBytes)
+}
+
+// anonymousAuditSummaryMessage is container for the outcome of
+// the anonymous audit command.
+type anonymousAuditSummaryMessage struct {
+	Status   string `json:"status"`
+	Buckets  int    `json:"buckets"`
+	Readable int    `json:"readablePrefixes"`
+	Writable int    `json:"writablePrefixes"`
+	Objects  int64  `json:"publicObjects"`
+	Listed   bool   `json:"-"`
+}
+
+// String colorized audit summary message.
+func (s anonymousAuditSummaryMessage) String() string {
+	msg := fmt.Sprintf("Audited %d bucket(s): %d publicly readable and %d publicly writable prefix(es)",
+		s.Buckets, s.Readable, s.Writable)
+	if s.Listed {
+		msg += fmt.Sprintf(", %d publicly readable object(s)", s.Objects)
+	}
+	return console.Colorize("AnonymousSummary", msg+".")
+}
+
+// JSON jsonified audit summary message.
+func (s anonymousAuditSummaryMessage) JSON() string {
+	anonymousJSONBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(anonymousJSONBytes)
+}
+
 // checkAnonymousSyntax check for incoming syntax.
 func checkAnonymousSyntax(ctx *cli.Context) {
 	argsLength := len(ctx.Args())
@@ -224,6 +298,11 @@ func checkAnonymousSyntax(ctx *cli.Context) {
 		if argsLength != 2 {
 			showCommandHelpAndExit(ctx, 1)
 		}
+	case "audit":
+		// Always expect an alias, bucket or prefix after audit cmd
+		if argsLength != 2 {
+			showCommandHelpAndExit(ctx, 1)
+		}
 	default:
 		showCommandHelpAndExit(ctx, 1)
 	}
@@ -420,6 +499,126 @@ func runAnonymousLinksCmd(args cli.Args, recursive bool) {
 	}
 }
 
+// Run anonymous audit command
+func runAnonymousAuditCmd(args cli.Args, recursive bool) {
+	ctx, cancelAnonymousAudit := context.WithCancel(globalContext)
+	defer cancelAnonymousAudit()
+
+	targetURL := args.First()
+	clnt, err := newClient(targetURL)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+	if clnt.GetURL().Type != objectStorage {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Unable to audit anonymous access of a non S3 url `"+targetURL+"`.")
+	}
+
+	alias, path := url2Alias(targetURL)
+	path = strings.TrimPrefix(path, "/")
+
+	// An alias is audited bucket by bucket.
+	var bucketURLs []string
+	if path == "" {
+		buckets, err := clnt.ListBuckets(ctx)
+		fatalIf(err.Trace(targetURL), "Unable to list buckets of `"+targetURL+"`.")
+		for _, bucket := range buckets {
+			bucketURLs = append(bucketURLs, alias+"/"+bucket.BucketName)
+		}
+	} else {
+		bucketURLs = append(bucketURLs, targetURL)
+	}
+
+	summary := anonymousAuditSummaryMessage{Status: "success", Listed: recursive}
+	for _, bucketURL := range bucketURLs {
+		summary.Buckets++
+
+		policies, err := doGetAccessRules(ctx, bucketURL)
+		if err != nil {
+			errorIf(err.Trace(bucketURL), "Unable to get policies of `"+bucketURL+"`.")
+			continue
+		}
+
+		_, bucketPath := url2Alias(bucketURL)
+		resources := make([]string, 0, len(policies))
+		for resource := range policies {
+			// Only report rules related to the url passed by the user.
+			prefix := strings.TrimSuffix(resource, "*")
+			if !strings.HasPrefix(prefix, bucketPath) && !strings.HasPrefix(bucketPath, prefix) {
+				continue
+			}
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+
+		var readablePrefixes []string
+		for _, resource := range resources {
+			perm := stringToAccessPerm(policies[resource])
+			msg := anonymousAuditMessage{
+				Status:     "success",
+				Resource:   alias + "/" + resource,
+				Permission: perm,
+				Read:       perm == accessDownload || perm == accessPublic,
+				Write:      perm == accessUpload || perm == accessPublic,
+			}
+			if !msg.Read && !msg.Write {
+				continue
+			}
+			if msg.Read {
+				summary.Readable++
+				readablePrefixes = append(readablePrefixes, strings.TrimSuffix(resource, "*"))
+			}
+			if msg.Write {
+				summary.Writable++
+			}
+			printMsg(msg)
+
+			if !recursive || !msg.Read {
+				continue
+			}
+
+			// Objects below a broader readable prefix were already reported.
+			prefix := strings.TrimSuffix(resource, "*")
+			var covered bool
+			for _, readable := range readablePrefixes[:len(readablePrefixes)-1] {
+				if strings.HasPrefix(prefix, readable) {
+					covered = true
+					break
+				}
+			}
+			if covered {
+				continue
+			}
+			if len(prefix) < len(bucketPath) {
+				prefix = bucketPath
+			}
+
+			prefixURL := alias + "/" + prefix
+			prefixClnt, err := newClient(prefixURL)
+			if err != nil {
+				errorIf(err.Trace(prefixURL), "Unable to initialize target `"+prefixURL+"`.")
+				continue
+			}
+			for content := range prefixClnt.List(ctx, ListOptions{Recursive: true}) {
+				if content.Err != nil {
+					errorIf(content.Err.Trace(prefixURL), "Unable to list folder.")
+					continue
+				}
+				if content.Type.IsDir() {
+					continue
+				}
+				summary.Objects++
+				printMsg(anonymousAuditMessage{
+					Status:     "success",
+					Resource:   msg.Resource,
+					Object:     content.URL.String(),
+					Permission: perm,
+					Read:       msg.Read,
+					Write:      msg.Write,
+				})
+			}
+		}
+	}
+	printMsg(summary)
+}
+
 // Run anonymous cmd to fetch set permission
 func runAnonymousCmd(args cli.Args) {
 	ctx, cancelAnonymous := context.WithCancel(globalContext)
@@ -477,6 +676,8 @@ func mainAnonymous(ctx *cli.Context) error {
 
 	// Additional command speific theme customization.
 	console.SetColor("Anonymous", color.New(color.FgGreen, color.Bold))
+	console.SetColor("AnonymousAccess", color.New(color.FgRed, color.Bold))
+	console.SetColor("AnonymousSummary", color.New(color.Bold))
 
 	switch ctx.Args().First() {
 	case "set", "set-json", "get", "get-json":
@@ -491,6 +692,9 @@ func mainAnonymous(ctx *cli.Context) error {
 	case "links":
 		// anonymous links alias/bucket/prefix
 		runAnonymousLinksCmd(ctx.Args().Tail(), ctx.Bool("recursive"))
+	case "audit":
+		// anonymous audit alias[/bucket/prefix]
+		runAnonymousAuditCmd(ctx.Args().Tail(), ctx.Bool("recursive"))
 	default:
 		// Shows command example and exit
 		showCommandHelpAndExit(ctx, 1)
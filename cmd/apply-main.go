@@ -0,0 +1,270 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// apply specific flags.
+var (
+	applyFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "show the changes of the plan without applying them",
+		},
+	}
+)
+
+// Apply the change plan written by 'mc diff --output-plan'.
+var applyCmd = cli.Command{
+	Name:         "apply",
+	Usage:        "apply a change plan written by diff",
+	Action:       mainApply,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(applyFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] PLAN
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Apply copies and removes the objects listed by a change plan, written by 'mc diff --output-plan',
+  so that the changes can be reviewed before they are made. The whole plan is validated before the
+  first change. Actions which fail are reported and the others still applied.
+
+EXAMPLES:
+  1. Review the changes of a plan.
+     {{.Prompt}} {{.HelpName}} --dry-run plan.json
+
+  2. Apply a plan written by diff.
+     {{.Prompt}} mc diff --output-plan plan.json s3/mybucket backup/mybucket
+     {{.Prompt}} {{.HelpName}} plan.json
+`,
+}
+
+// changePlanVersion is the version of the change plans written.
+const changePlanVersion = 1
+
+// Operations of a change plan.
+const (
+	planOpCopy   = "copy"
+	planOpDelete = "delete"
+)
+
+// changePlan is a list of changes making the target of a diff a copy
+// of its source.
+type changePlan struct {
+	Version int          `json:"version"`
+	Source  string       `json:"source"`
+	Target  string       `json:"target"`
+	Actions []planAction `json:"actions"`
+}
+
+// planAction is a change of a plan, the URLs are aliased.
+type planAction struct {
+	Op     string `json:"op"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// checkDiffPlanSyntax returns why 'diff --output-plan' cannot be used,
+// if so.
+func checkDiffPlanSyntax(cliCtx *cli.Context) string {
+	if cliCtx.String("output-plan") != "" && (cliCtx.Bool("content") || cliCtx.IsSet("base")) {
+		return "--output-plan cannot be used with --content or --base."
+	}
+	return ""
+}
+
+// diffPlanAction returns the action making the target of a diff match
+// its source for an object, firstURL and secondURL are the compared
+// folders as listed and firstAliased and secondAliased as given.
+func diffPlanAction(d diffMessage, firstURL, firstAliased, secondURL, secondAliased string) (planAction, bool) {
+	switch d.Diff {
+	case differInNone, differInUnknown:
+		return planAction{}, false
+	case differInSecond:
+		key := strings.TrimPrefix(d.SecondURL, secondURL)
+		return planAction{Op: planOpDelete, Target: urlJoinPath(secondAliased, key), Reason: d.Diff.String()}, true
+	}
+	key := strings.TrimPrefix(d.FirstURL, firstURL)
+	return planAction{
+		Op:     planOpCopy,
+		Source: urlJoinPath(firstAliased, key),
+		Target: urlJoinPath(secondAliased, key),
+		Reason: d.Diff.String(),
+	}, true
+}
+
+// writeChangePlan writes a plan to a file.
+func writeChangePlan(fileName string, plan changePlan) *probe.Error {
+	plan.Version = changePlanVersion
+	data, e := json.MarshalIndent(plan, "", "  ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	return probe.NewError(os.WriteFile(fileName, append(data, '\n'), 0o644))
+}
+
+// readChangePlan reads and validates a plan.
+func readChangePlan(r io.Reader) (changePlan, *probe.Error) {
+	var plan changePlan
+	if e := json.NewDecoder(r).Decode(&plan); e != nil {
+		return plan, probe.NewError(e)
+	}
+	if plan.Version != changePlanVersion {
+		return plan, probe.NewError(fmt.Errorf("unsupported plan version %d", plan.Version))
+	}
+	for i, action := range plan.Actions {
+		switch {
+		case action.Op != planOpCopy && action.Op != planOpDelete:
+			return plan, probe.NewError(fmt.Errorf("action %d: unknown operation `%s`", i+1, action.Op))
+		case action.Target == "":
+			return plan, probe.NewError(fmt.Errorf("action %d: missing target", i+1))
+		case action.Op == planOpCopy && action.Source == "":
+			return plan, probe.NewError(fmt.Errorf("action %d: missing source", i+1))
+		}
+	}
+	return plan, nil
+}
+
+// applyMessage container for an applied action.
+type applyMessage struct {
+	Status string `json:"status"`
+	planAction
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// String colorized apply message.
+func (a applyMessage) String() string {
+	switch {
+	case a.Op == planOpCopy && a.DryRun:
+		return console.Colorize("ApplyCopy", fmt.Sprintf("Would copy `%s` to `%s` (%s).", a.Source, a.Target, a.Reason))
+	case a.Op == planOpCopy:
+		return console.Colorize("ApplyCopy", fmt.Sprintf("Copied `%s` to `%s`.", a.Source, a.Target))
+	case a.DryRun:
+		return console.Colorize("ApplyDelete", fmt.Sprintf("Would remove `%s` (%s).", a.Target, a.Reason))
+	}
+	return console.Colorize("ApplyDelete", fmt.Sprintf("Removed `%s`.", a.Target))
+}
+
+// JSON jsonified apply message.
+func (a applyMessage) JSON() string {
+	a.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(a, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// applyCopy copies the source of an action to its target.
+func applyCopy(ctx context.Context, action planAction, encKeyDB encryptionConfig) *probe.Error {
+	sourceAlias, _, _ := mustExpandAlias(action.Source)
+	targetAlias, targetURL, _ := mustExpandAlias(action.Target)
+	_, sourceContent, err := url2Stat(ctx, action.Source, "", false, encKeyDB, time.Time{}, false)
+	if err != nil {
+		return err.Trace(action.Source)
+	}
+	urls := makeCopyContentTypeA(sourceAlias, sourceContent, targetAlias, targetURL)
+	return uploadSourceToTargetURL(ctx, urls, nil, encKeyDB, false, false).Error
+}
+
+// applyDelete removes the target of an action.
+func applyDelete(ctx context.Context, action planAction) *probe.Error {
+	alias, urlStr, _ := mustExpandAlias(action.Target)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(action.Target)
+	}
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: *newClientURL(urlStr)}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			return result.Err.Trace(action.Target)
+		}
+	}
+	return nil
+}
+
+// applyChangePlan applies the actions of a plan in order and returns
+// the number of failed actions.
+func applyChangePlan(ctx context.Context, plan changePlan, dryRun bool, encKeyDB encryptionConfig) (failed int) {
+	for _, action := range plan.Actions {
+		if !dryRun {
+			var err *probe.Error
+			if action.Op == planOpCopy {
+				err = applyCopy(ctx, action, encKeyDB)
+			} else {
+				err = applyDelete(ctx, action)
+			}
+			if err != nil {
+				errorIf(err, "Unable to %s `%s`.", action.Op, action.Target)
+				failed++
+				continue
+			}
+		}
+		printMsg(applyMessage{planAction: action, DryRun: dryRun})
+	}
+	return failed
+}
+
+// mainApply is the handle for "mc apply" command.
+func mainApply(cliCtx *cli.Context) error {
+	ctx, cancelApply := context.WithCancel(globalContext)
+	defer cancelApply()
+
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+
+	console.SetColor("ApplyCopy", color.New(color.FgGreen))
+	console.SetColor("ApplyDelete", color.New(color.FgRed))
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	planFile := cliCtx.Args().First()
+	f, e := os.Open(planFile)
+	fatalIf(probe.NewError(e).Trace(planFile), "Unable to open the plan.")
+	defer f.Close()
+	plan, err := readChangePlan(f)
+	fatalIf(err.Trace(planFile), "Unable to read the plan.")
+
+	if applyChangePlan(ctx, plan, cliCtx.Bool("dry-run"), encKeyDB) > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
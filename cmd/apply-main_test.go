@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckDiffPlanSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"--output-plan", "plan.json", "first", "second"}, true},
+		{[]string{"--output-plan", "plan.json", "--checksum", "first", "second"}, true},
+		{[]string{"--output-plan", "plan.json", "--content", "first", "second"}, false},
+		{[]string{"--output-plan", "plan.json", "--base", "base.json", "first", "second"}, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, diffFlags, testCase.args...)
+		if msg := checkDiffPlanSyntax(cliCtx); (msg == "") != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %q", i+1, testCase.valid, msg)
+		}
+	}
+}
+
+func TestDiffPlanAction(t *testing.T) {
+	firstURL, secondURL := "http://localhost:9000/bucket/", "http://localhost:9001/backup/"
+	testCases := []struct {
+		msg      diffMessage
+		expected planAction
+		ok       bool
+	}{
+		{diffMessage{FirstURL: firstURL + "a", Diff: differInFirst}, planAction{Op: planOpCopy, Source: "play/bucket/a", Target: "backup/backup/a", Reason: "only-in-first"}, true},
+		{diffMessage{FirstURL: firstURL + "dir/b", SecondURL: secondURL + "dir/b", Diff: differInSize}, planAction{Op: planOpCopy, Source: "play/bucket/dir/b", Target: "backup/backup/dir/b", Reason: "size"}, true},
+		{diffMessage{FirstURL: firstURL + "c", SecondURL: secondURL + "c", Diff: differInChecksum}, planAction{Op: planOpCopy, Source: "play/bucket/c", Target: "backup/backup/c", Reason: "checksum"}, true},
+		{diffMessage{SecondURL: secondURL + "d", Diff: differInSecond}, planAction{Op: planOpDelete, Target: "backup/backup/d", Reason: "only-in-second"}, true},
+		{diffMessage{FirstURL: firstURL + "e", SecondURL: secondURL + "e", Diff: differInNone}, planAction{}, false},
+	}
+	for i, testCase := range testCases {
+		action, ok := diffPlanAction(testCase.msg, firstURL, "play/bucket/", secondURL, "backup/backup/")
+		if ok != testCase.ok || action != testCase.expected {
+			t.Errorf("Test %d: expected %+v %t, got %+v %t", i+1, testCase.expected, testCase.ok, action, ok)
+		}
+	}
+}
+
+func TestReadChangePlan(t *testing.T) {
+	testCases := []struct {
+		plan  string
+		valid bool
+	}{
+		{`{"version":1,"actions":[{"op":"copy","source":"a","target":"b"},{"op":"delete","target":"c"}]}`, true},
+		{`{"version":1,"actions":[]}`, true},
+		{`{"version":2,"actions":[]}`, false},
+		{`{"version":1,"actions":[{"op":"move","source":"a","target":"b"}]}`, false},
+		{`{"version":1,"actions":[{"op":"copy","target":"b"}]}`, false},
+		{`{"version":1,"actions":[{"op":"delete"}]}`, false},
+		{`not json`, false},
+	}
+	for i, testCase := range testCases {
+		if _, err := readChangePlan(strings.NewReader(testCase.plan)); (err == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+	}
+}
+
+func TestApplyChangePlan(t *testing.T) {
+	source, target := t.TempDir(), t.TempDir()
+	write := func(fpath, data string) {
+		if e := os.WriteFile(fpath, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	write(filepath.Join(source, "new"), "hello")
+	write(filepath.Join(target, "stale"), "old")
+
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	plan := changePlan{Source: source, Target: target, Actions: []planAction{
+		{Op: planOpCopy, Source: filepath.Join(source, "new"), Target: filepath.Join(target, "new"), Reason: "only-in-first"},
+		{Op: planOpDelete, Target: filepath.Join(target, "stale"), Reason: "only-in-second"},
+	}}
+	if err := writeChangePlan(planFile, plan); err != nil {
+		t.Fatal(err)
+	}
+	f, e := os.Open(planFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer f.Close()
+	read, err := readChangePlan(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(read.Actions, plan.Actions) {
+		t.Fatalf("expected %+v, got %+v", plan.Actions, read.Actions)
+	}
+
+	ctx := context.Background()
+	// A dry run changes nothing.
+	if failed := applyChangePlan(ctx, read, true, nil); failed != 0 {
+		t.Fatalf("expected no failures, got %d", failed)
+	}
+	if _, e := os.Stat(filepath.Join(target, "new")); !os.IsNotExist(e) {
+		t.Fatal("the dry run copied an object")
+	}
+
+	if failed := applyChangePlan(ctx, read, false, nil); failed != 0 {
+		t.Fatalf("expected no failures, got %d", failed)
+	}
+	if data, e := os.ReadFile(filepath.Join(target, "new")); e != nil || string(data) != "hello" {
+		t.Errorf("expected the copy, got %q %v", data, e)
+	}
+	if _, e := os.Stat(filepath.Join(target, "stale")); !os.IsNotExist(e) {
+		t.Error("expected stale to be removed")
+	}
+
+	// Failed actions are counted and do not stop the others.
+	read.Actions = append([]planAction{{Op: planOpCopy, Source: filepath.Join(source, "missing"), Target: filepath.Join(target, "missing")}}, read.Actions[0])
+	if failed := applyChangePlan(ctx, read, false, nil); failed != 1 {
+		t.Errorf("expected 1 failure, got %d", failed)
+	}
+}
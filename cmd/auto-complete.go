@@ -242,10 +242,12 @@ var completeCmds = map[string]complete.Predictor{
 	"/mirror":    complete.PredictOr(s3Completer, fsCompleter),
 	"/pipe":      complete.PredictOr(s3Completer, fsCompleter),
 	"/stat":      complete.PredictOr(s3Completer, fsCompleter),
+	"/checksum":  complete.PredictOr(s3Completer, fsCompleter),
 	"/watch":     complete.PredictOr(s3Completer, fsCompleter),
 	"/anonymous": complete.PredictOr(s3Completer, fsCompleter),
 	"/tree":      complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
 	"/du":        complete.PredictOr(s3Complete{deepLevel: 2}, fsCompleter),
+	"/keycheck":  s3Completer,
 
 	"/retention/set":   s3Completer,
 	"/retention/clear": s3Completer,
@@ -279,6 +281,9 @@ var completeCmds = map[string]complete.Predictor{
 	"/replicate/resync/start":  s3Complete{deepLevel: 3},
 	"/replicate/resync/status": s3Complete{deepLevel: 3},
 
+	"/bucket/export": s3Complete{deepLevel: 2},
+	"/bucket/import": s3Complete{deepLevel: 2},
+
 	"/tag/list":   s3Completer,
 	"/tag/remove": s3Completer,
 	"/tag/set":    s3Completer,
@@ -314,6 +319,9 @@ var completeCmds = map[string]complete.Predictor{
 
 	"/undo": s3Completer,
 
+	"/trash/restore": s3Completer,
+	"/trash/purge":   s3Completer,
+
 	// Admin API commands MinIO only.
 	"/admin/heal": s3Completer,
 
@@ -458,6 +466,17 @@ var completeCmds = map[string]complete.Predictor{
 	"/admin/cluster/bucket/import": aliasCompleter,
 	"/admin/cluster/iam/export":    aliasCompleter,
 	"/admin/cluster/iam/import":    aliasCompleter,
+	"/admin/iam/migrate":           aliasCompleter,
+
+	"/admin/cluster/bucket/protect": s3Completer,
+	"/event/export":                 s3Complete{deepLevel: 2},
+	"/event/import":                 s3Complete{deepLevel: 2},
+	"/alias/stats":                  aliasCompleter,
+	"/alias/temp":                   aliasCompleter,
+	"/context/set":                  aliasCompleter,
+	"/context/use":                  nil,
+	"/context/list":                 nil,
+	"/context/remove":               nil,
 
 	"/alias/set":    nil,
 	"/alias/list":   aliasCompleter,
@@ -488,6 +507,7 @@ var completeCmds = map[string]complete.Predictor{
 	"/update":         nil,
 	"/ready":          aliasCompleter,
 	"/ping":           aliasCompleter,
+	"/features":       s3Completer,
 	"/od":             nil,
 	"/batch/generate": aliasCompleter,
 	"/batch/start":    aliasCompleter,
@@ -496,6 +516,12 @@ var completeCmds = map[string]complete.Predictor{
 	"/batch/describe": aliasCompleter,
 	"/batch/cancel":   aliasCompleter,
 
+	"/accesskey/create": aliasCompleter,
+	"/accesskey/list":   aliasCompleter,
+	"/accesskey/rm":     aliasCompleter,
+
+	"/website/sync": complete.PredictOr(fsCompleter, s3Completer),
+
 	"/quota/set":   aliasCompleter,
 	"/quota/info":  aliasCompleter,
 	"/quota/clear": aliasCompleter,
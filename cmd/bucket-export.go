@@ -0,0 +1,232 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var bucketExportCmd = cli.Command{
+	Name:         "export",
+	Usage:        "export bucket configurations to a folder of JSON files",
+	Action:       mainBucketExport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append([]cli.Flag{bucketConfigAllFlag}, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET DIRECTORY
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Exports the lifecycle, replication, encryption, policy, tags and notification
+  configurations of buckets, one folder per bucket with one JSON file per configuration.
+  Files of configurations which are no longer set are removed, so that the folder
+  can be kept under version control.
+
+EXAMPLES:
+  1. Export the configurations of every bucket on 'myminio' to the 'backup' folder.
+     {{.Prompt}} {{.HelpName}} --all myminio backup/
+
+  2. Export the configurations of 'mybucket' to the 'backup' folder.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket backup/
+`,
+}
+
+// checkBucketConfigExportSyntax - validate arguments passed by user
+func checkBucketConfigExportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+}
+
+// writeBucketConfigFile saves a configuration as indented JSON, a nil
+// configuration removes a previously exported file.
+func writeBucketConfigFile(dir, name string, config interface{}) *probe.Error {
+	filename := filepath.Join(dir, name)
+	if config == nil {
+		if e := os.Remove(filename); e != nil && !os.IsNotExist(e) {
+			return probe.NewError(e).Trace(filename)
+		}
+		return nil
+	}
+	data, e := json.MarshalIndent(config, "", " ")
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e = os.WriteFile(filename, append(data, '\n'), 0o644); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// getBucketConfigs returns the configurations of a bucket which are
+// set, by the name of the file they are exported to.
+func getBucketConfigs(ctx context.Context, bucketURL string) (map[string]interface{}, *probe.Error) {
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return nil, err.Trace(bucketURL)
+	}
+	s3Client, ok := clnt.(*S3Client)
+	if !ok {
+		return nil, probe.NewError(APINotImplemented{API: "bucket export", APIType: "filesystem"}).Trace(bucketURL)
+	}
+
+	configs := make(map[string]interface{})
+
+	_, policyJSON, err := clnt.GetAccess(ctx)
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(bucketURL)
+	}
+	if err == nil && policyJSON != "" {
+		var policy map[string]interface{}
+		if e := json.Unmarshal([]byte(policyJSON), &policy); e != nil {
+			return nil, probe.NewError(e).Trace(bucketURL)
+		}
+		configs[bucketPolicyFile] = policy
+	}
+
+	tags, err := clnt.GetTags(ctx, "")
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(bucketURL)
+	}
+	if err == nil && len(tags) > 0 {
+		configs[bucketTagsFile] = tags
+	}
+
+	algorithm, keyID, err := clnt.GetEncryption(ctx)
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(bucketURL)
+	}
+	if err == nil && algorithm != "" {
+		encryption := bucketEncryptionConfig{Algorithm: "sse-s3"}
+		if algorithm == "aws:kms" {
+			encryption = bucketEncryptionConfig{Algorithm: "sse-kms", KMSKeyID: keyID}
+		}
+		configs[bucketEncryptionFile] = encryption
+	}
+
+	lifecycle, _, err := clnt.GetLifecycle(ctx)
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(bucketURL)
+	}
+	if err == nil && lifecycle != nil && len(lifecycle.Rules) > 0 {
+		configs[bucketLifecycleFile] = lifecycle
+	}
+
+	notification, err := s3Client.GetNotificationConfig(ctx)
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(bucketURL)
+	}
+	if err == nil && len(notification.LambdaConfigs)+len(notification.TopicConfigs)+len(notification.QueueConfigs) > 0 {
+		configs[bucketNotificationFile] = notification
+	}
+
+	replication, err := clnt.GetReplication(ctx)
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(bucketURL)
+	}
+	if err == nil && !replication.Empty() {
+		configs[bucketReplicationFile] = replication
+	}
+	return configs, nil
+}
+
+// exportBucketConfigs saves all configurations of a bucket in dir,
+// it returns the names of the configurations which are set.
+func exportBucketConfigs(ctx context.Context, bucketURL, dir string) ([]string, *probe.Error) {
+	configs, err := getBucketConfigs(ctx, bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	if e := os.MkdirAll(dir, 0o755); e != nil {
+		return nil, probe.NewError(e).Trace(dir)
+	}
+
+	var exported []string
+	for _, name := range []string{
+		bucketPolicyFile, bucketTagsFile, bucketEncryptionFile,
+		bucketLifecycleFile, bucketNotificationFile, bucketReplicationFile,
+	} {
+		if err = writeBucketConfigFile(dir, name, configs[name]); err != nil {
+			return exported, err
+		}
+		if configs[name] != nil {
+			exported = append(exported, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	return exported, nil
+}
+
+func mainBucketExport(cliCtx *cli.Context) error {
+	ctx, cancelBucketExport := context.WithCancel(globalContext)
+	defer cancelBucketExport()
+
+	checkBucketConfigExportSyntax(cliCtx)
+	console.SetColor("BucketConfig", color.New(color.FgGreen))
+
+	args := cliCtx.Args()
+	aliasedURL, dir := args.Get(0), args.Get(1)
+
+	alias, bucket, err := parseBucketConfigTarget(aliasedURL, cliCtx.Bool("all"))
+	fatalIf(err.Trace(aliasedURL), "Invalid target `"+aliasedURL+"`.")
+	buckets := []string{bucket}
+	if bucket == "" {
+		clnt, err := newClient(aliasedURL)
+		fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+		contents, err := clnt.ListBuckets(ctx)
+		fatalIf(err.Trace(aliasedURL), "Unable to list buckets of `"+aliasedURL+"`.")
+		buckets = buckets[:0]
+		for _, content := range contents {
+			buckets = append(buckets, content.BucketName)
+		}
+	}
+
+	var retErr error
+	for _, bucket := range buckets {
+		bucketURL := alias + "/" + bucket
+		bucketDir := filepath.Join(dir, bucket)
+		exported, err := exportBucketConfigs(ctx, bucketURL, bucketDir)
+		if err != nil {
+			errorIf(err.Trace(bucketURL), "Unable to export configurations of `"+bucketURL+"`.")
+			retErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(bucketConfigMessage{
+			Op:        "export",
+			Status:    "success",
+			URL:       bucketURL,
+			Directory: bucketDir,
+			Configs:   exported,
+		})
+	}
+	return retErr
+}
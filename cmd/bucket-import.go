@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/minio/minio-go/v7/pkg/tags"
+	"github.com/minio/pkg/console"
+)
+
+var bucketImportCmd = cli.Command{
+	Name:         "import",
+	Usage:        "import bucket configurations from a folder of JSON files",
+	Action:       mainBucketImport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append([]cli.Flag{bucketConfigAllFlag, eventArnMapFlag}, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET DIRECTORY
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Imports bucket configurations saved by 'mc bucket export'. Buckets must exist,
+  with versioning enabled when a replication configuration is imported. The ARNs of
+  notification targets are rewritten with '--arn-map', see 'mc event import'.
+
+EXAMPLES:
+  1. Import the configurations of every bucket saved in the 'backup' folder to 'myminio'.
+     {{.Prompt}} {{.HelpName}} --all myminio backup/
+
+  2. Import the configurations of 'mybucket' saved in the 'backup' folder.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket backup/
+
+  3. Import the configurations of every bucket to a DR cluster, where the webhook target has another ID.
+     {{.Prompt}} {{.HelpName}} --all --arn-map 'arn:minio:sqs::primary:webhook=arn:minio:sqs::dr:webhook' dr backup/
+`,
+}
+
+// checkBucketConfigImportSyntax - validate arguments passed by user
+func checkBucketConfigImportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 2 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+}
+
+// readBucketConfigFile decodes an exported configuration, it returns
+// false when the configuration was not exported.
+func readBucketConfigFile(dir, name string, config interface{}) (bool, *probe.Error) {
+	filename := filepath.Join(dir, name)
+	data, e := os.ReadFile(filename)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return false, nil
+		}
+		return false, probe.NewError(e).Trace(filename)
+	}
+	if e = json.Unmarshal(data, config); e != nil {
+		return false, probe.NewError(e).Trace(filename)
+	}
+	return true, nil
+}
+
+// bucketConfigReader decodes a configuration of a bucket by the name
+// of its file, it returns false when the configuration is not set.
+type bucketConfigReader func(name string, config interface{}) (bool, *probe.Error)
+
+// importBucketConfigs applies all configurations saved in dir to a
+// bucket, it returns the names of the imported configurations.
+func importBucketConfigs(ctx context.Context, bucketURL, dir string, arnRules []eventArnRule) ([]string, *probe.Error) {
+	return setBucketConfigs(ctx, bucketURL, func(name string, config interface{}) (bool, *probe.Error) {
+		return readBucketConfigFile(dir, name, config)
+	}, arnRules)
+}
+
+// setBucketConfigs applies all configurations read to a bucket, it
+// returns the names of the configurations applied.
+func setBucketConfigs(ctx context.Context, bucketURL string, read bucketConfigReader, arnRules []eventArnRule) ([]string, *probe.Error) {
+	clnt, err := newClient(bucketURL)
+	if err != nil {
+		return nil, err.Trace(bucketURL)
+	}
+	s3Client, ok := clnt.(*S3Client)
+	if !ok {
+		return nil, probe.NewError(APINotImplemented{API: "bucket import", APIType: "filesystem"}).Trace(bucketURL)
+	}
+
+	var imported []string
+	importConfig := func(name string, config interface{}, apply func() *probe.Error) *probe.Error {
+		found, err := read(name, config)
+		if err != nil || !found {
+			return err
+		}
+		if err = apply(); err != nil {
+			return err.Trace(bucketURL, name)
+		}
+		imported = append(imported, strings.TrimSuffix(name, ".json"))
+		return nil
+	}
+
+	var policy map[string]interface{}
+	err = importConfig(bucketPolicyFile, &policy, func() *probe.Error {
+		policyJSON, e := json.Marshal(policy)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		return clnt.SetAccess(ctx, string(policyJSON), true)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	var tagMap map[string]string
+	err = importConfig(bucketTagsFile, &tagMap, func() *probe.Error {
+		t, e := tags.MapToBucketTags(tagMap)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		return clnt.SetTags(ctx, "", t.String())
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	var encryption bucketEncryptionConfig
+	err = importConfig(bucketEncryptionFile, &encryption, func() *probe.Error {
+		return clnt.SetEncryption(ctx, encryption.Algorithm, encryption.KMSKeyID)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	var lifecycleCfg lifecycle.Configuration
+	err = importConfig(bucketLifecycleFile, &lifecycleCfg, func() *probe.Error {
+		return clnt.SetLifecycle(ctx, &lifecycleCfg)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	var notificationCfg notification.Configuration
+	err = importConfig(bucketNotificationFile, &notificationCfg, func() *probe.Error {
+		if _, err := remapNotificationConfig(&notificationCfg, arnRules); err != nil {
+			return err
+		}
+		return s3Client.SetNotificationConfig(ctx, notificationCfg)
+	})
+	if err != nil {
+		return imported, err
+	}
+
+	var replicationCfg replication.Config
+	err = importConfig(bucketReplicationFile, &replicationCfg, func() *probe.Error {
+		return clnt.SetReplication(ctx, &replicationCfg, replication.Options{Op: replication.ImportOption})
+	})
+	return imported, err
+}
+
+func mainBucketImport(cliCtx *cli.Context) error {
+	ctx, cancelBucketImport := context.WithCancel(globalContext)
+	defer cancelBucketImport()
+
+	checkBucketConfigImportSyntax(cliCtx)
+	console.SetColor("BucketConfig", color.New(color.FgGreen))
+
+	args := cliCtx.Args()
+	aliasedURL, dir := args.Get(0), args.Get(1)
+
+	arnRules, err := parseEventArnMap(cliCtx.StringSlice("arn-map"))
+	fatalIf(err, "Unable to parse --arn-map.")
+
+	alias, bucket, err := parseBucketConfigTarget(aliasedURL, cliCtx.Bool("all"))
+	fatalIf(err.Trace(aliasedURL), "Invalid target `"+aliasedURL+"`.")
+	buckets := []string{bucket}
+	if bucket == "" {
+		entries, e := os.ReadDir(dir)
+		fatalIf(probe.NewError(e).Trace(dir), "Unable to read bucket configurations from `"+dir+"`.")
+		buckets = buckets[:0]
+		for _, entry := range entries {
+			if entry.IsDir() {
+				buckets = append(buckets, entry.Name())
+			}
+		}
+	}
+
+	var retErr error
+	for _, bucket := range buckets {
+		bucketURL := alias + "/" + bucket
+		bucketDir := filepath.Join(dir, bucket)
+		imported, err := importBucketConfigs(ctx, bucketURL, bucketDir, arnRules)
+		if err != nil {
+			errorIf(err.Trace(bucketURL), "Unable to import configurations to `"+bucketURL+"`.")
+			retErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(bucketConfigMessage{
+			Op:        "import",
+			Status:    "success",
+			URL:       bucketURL,
+			Directory: bucketDir,
+			Configs:   imported,
+		})
+	}
+	return retErr
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+)
+
+var bucketSubcommands = []cli.Command{
+	bucketExportCmd,
+	bucketImportCmd,
+}
+
+var bucketCmd = cli.Command{
+	Name:            "bucket",
+	Usage:           "export and import bucket configurations",
+	Action:          mainBucket,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	Subcommands:     bucketSubcommands,
+}
+
+func mainBucket(ctx *cli.Context) error {
+	commandNotFound(ctx, bucketSubcommands)
+	return nil
+}
+
+var bucketConfigAllFlag = cli.BoolFlag{
+	Name:  "all",
+	Usage: "include every bucket of the alias",
+}
+
+// Files of a bucket folder, in the order they are imported.
+const (
+	bucketPolicyFile       = "policy.json"
+	bucketTagsFile         = "tags.json"
+	bucketEncryptionFile   = "encryption.json"
+	bucketLifecycleFile    = "lifecycle.json"
+	bucketNotificationFile = "notification.json"
+	bucketReplicationFile  = "replication.json"
+)
+
+// bucketEncryptionConfig is the exported form of a bucket default
+// encryption, as accepted by `mc encrypt set`.
+type bucketEncryptionConfig struct {
+	Algorithm string `json:"algorithm"`
+	KMSKeyID  string `json:"kmsKeyID,omitempty"`
+}
+
+// bucketConfigMessage container for bucket export and import messages,
+// and for the configurations copied by mirror --with-metadata.
+type bucketConfigMessage struct {
+	Op        string   `json:"op"`
+	Status    string   `json:"status"`
+	URL       string   `json:"url"`
+	Source    string   `json:"source,omitempty"`
+	Directory string   `json:"directory,omitempty"`
+	Configs   []string `json:"configs"`
+}
+
+func (b bucketConfigMessage) String() string {
+	configs := "no configuration"
+	if len(b.Configs) > 0 {
+		configs = strings.Join(b.Configs, ", ")
+	}
+	switch b.Op {
+	case "import":
+		return console.Colorize("BucketConfig", "Imported "+configs+" from `"+b.Directory+"` to `"+b.URL+"`.")
+	case "mirror":
+		return console.Colorize("BucketConfig", "Copied "+configs+" of `"+b.Source+"` to `"+b.URL+"`.")
+	}
+	return console.Colorize("BucketConfig", "Exported "+configs+" of `"+b.URL+"` to `"+b.Directory+"`.")
+}
+
+func (b bucketConfigMessage) JSON() string {
+	b.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(b, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// isBucketConfigNotSet returns true when the error tells that a bucket
+// configuration is not set, or not supported by the server.
+func isBucketConfigNotSet(err *probe.Error) bool {
+	if _, ok := err.ToGoError().(APINotImplemented); ok {
+		return true
+	}
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "NoSuchLifecycleConfiguration", "ReplicationConfigurationNotFoundError",
+		"ServerSideEncryptionConfigurationNotFoundError", "NoSuchTagSet",
+		"NoSuchBucketPolicy", "NotImplemented":
+		return true
+	}
+	return false
+}
+
+// parseBucketConfigTarget returns the alias and bucket a bucket export
+// or import works on, bucket is empty when all buckets are requested.
+func parseBucketConfigTarget(aliasedURL string, all bool) (alias, bucket string, err *probe.Error) {
+	alias, path := url2Alias(aliasedURL)
+	bucket = strings.Trim(filepath.ToSlash(path), "/")
+	if bucket != "" {
+		if strings.Contains(bucket, "/") {
+			return "", "", probe.NewError(errors.New("bucket configurations apply to whole buckets, not prefixes"))
+		}
+		return alias, bucket, nil
+	}
+	if !all {
+		return "", "", probe.NewError(errors.New("please pass a bucket, or --all to include every bucket of `" + alias + "`"))
+	}
+	return alias, "", nil
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// bucketConfigHandler is an http.Handler serving the configurations of
+// the bucket "bucket", keyed by their sub-resource.
+type bucketConfigHandler struct {
+	mu      sync.Mutex
+	configs map[string]string
+}
+
+// Error codes of the configurations which are not set.
+var bucketConfigNotSetCodes = map[string]string{
+	"policy":       "NoSuchBucketPolicy",
+	"tagging":      "NoSuchTagSet",
+	"encryption":   "ServerSideEncryptionConfigurationNotFoundError",
+	"lifecycle":    "NoSuchLifecycleConfiguration",
+	"replication":  "ReplicationConfigurationNotFoundError",
+	"notification": "",
+	"versioning":   "",
+}
+
+func (h *bucketConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	query := r.URL.Query()
+	if _, ok := query["location"]; ok {
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></LocationConstraint>`)
+		return
+	}
+	for resource, code := range bucketConfigNotSetCodes {
+		if _, ok := query[resource]; !ok {
+			continue
+		}
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			h.configs[resource] = string(data)
+		case http.MethodDelete:
+			delete(h.configs, resource)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			config, ok := h.configs[resource]
+			switch {
+			case ok:
+				fmt.Fprint(w, config)
+			case resource == "versioning":
+				fmt.Fprint(w, `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></VersioningConfiguration>`)
+			case code == "":
+				fmt.Fprint(w, `<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"></NotificationConfiguration>`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message><BucketName>bucket</BucketName></Error>", code, code)
+			}
+		}
+		return
+	}
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+func TestParseBucketConfigTarget(t *testing.T) {
+	testCases := []struct {
+		aliasedURL string
+		all        bool
+		alias      string
+		bucket     string
+		valid      bool
+	}{
+		{"myminio/mybucket", false, "myminio", "mybucket", true},
+		{"myminio/mybucket/", true, "myminio", "mybucket", true},
+		{"myminio", true, "myminio", "", true},
+		{"myminio/", true, "myminio", "", true},
+		// The whole alias needs --all.
+		{"myminio", false, "", "", false},
+		// Configurations are per bucket, never per prefix.
+		{"myminio/mybucket/prefix", false, "", "", false},
+	}
+	for i, testCase := range testCases {
+		alias, bucket, err := parseBucketConfigTarget(testCase.aliasedURL, testCase.all)
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+		if alias != testCase.alias || bucket != testCase.bucket {
+			t.Errorf("Test %d: expected %s %s, got %s %s", i+1, testCase.alias, testCase.bucket, alias, bucket)
+		}
+	}
+}
+
+func TestBucketConfigExportImport(t *testing.T) {
+	policy := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":["s3:GetObject"],"Resource":["arn:aws:s3:::bucket/*"]}]}`
+	source := &bucketConfigHandler{configs: map[string]string{
+		"policy":  policy,
+		"tagging": `<Tagging><TagSet><Tag><Key>team</Key><Value>data</Value></Tag></TagSet></Tagging>`,
+	}}
+	sourceServer := httptest.NewServer(source)
+	defer sourceServer.Close()
+	target := &bucketConfigHandler{configs: map[string]string{}}
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+
+	t.Setenv(mcEnvHostPrefix+"source", strings.Replace(sourceServer.URL, "://", "://access:secret@", 1))
+	t.Setenv(mcEnvHostPrefix+"target", strings.Replace(targetServer.URL, "://", "://access:secret@", 1))
+
+	// Files of configurations which are no longer set are removed.
+	dir := filepath.Join(t.TempDir(), "bucket")
+	if e := os.MkdirAll(dir, 0o755); e != nil {
+		t.Fatal(e)
+	}
+	if e := os.WriteFile(filepath.Join(dir, bucketLifecycleFile), []byte("{}"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	exported, err := exportBucketConfigs(context.Background(), "source/bucket", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"policy", "tags"}; !reflect.DeepEqual(exported, expected) {
+		t.Fatalf("expected %v exported, got %v", expected, exported)
+	}
+	for name, exists := range map[string]bool{
+		bucketPolicyFile: true, bucketTagsFile: true, bucketLifecycleFile: false,
+		bucketEncryptionFile: false, bucketNotificationFile: false, bucketReplicationFile: false,
+	} {
+		if _, e := os.Stat(filepath.Join(dir, name)); (e == nil) != exists {
+			t.Errorf("expected %s to exist %t", name, exists)
+		}
+	}
+
+	imported, err := importBucketConfigs(context.Background(), "target/bucket", dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(imported, exported) {
+		t.Fatalf("expected %v imported, got %v", exported, imported)
+	}
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	var expectedPolicy, importedPolicy interface{}
+	json.Unmarshal([]byte(policy), &expectedPolicy)
+	if e := json.Unmarshal([]byte(target.configs["policy"]), &importedPolicy); e != nil {
+		t.Fatal(e)
+	}
+	if !reflect.DeepEqual(importedPolicy, expectedPolicy) {
+		t.Errorf("expected policy %v, got %v", expectedPolicy, importedPolicy)
+	}
+	if !strings.Contains(target.configs["tagging"], "<Key>team</Key><Value>data</Value>") {
+		t.Errorf("unexpected tags %s", target.configs["tagging"])
+	}
+	if _, ok := target.configs["lifecycle"]; ok {
+		t.Error("expected no lifecycle to be imported")
+	}
+}
+
+func TestCopyBucketConfigs(t *testing.T) {
+	source := &bucketConfigHandler{configs: map[string]string{
+		"versioning":  `<VersioningConfiguration><Status>Enabled</Status></VersioningConfiguration>`,
+		"tagging":     `<Tagging><TagSet><Tag><Key>team</Key><Value>data</Value></Tag></TagSet></Tagging>`,
+		"replication": `<ReplicationConfiguration><Role>arn:minio:replication::id:bucket</Role><Rule><ID>r1</ID><Status>Enabled</Status><Priority>1</Priority><DeleteMarkerReplication><Status>Disabled</Status></DeleteMarkerReplication><Destination><Bucket>arn:minio:replication::id:bucket</Bucket></Destination></Rule></ReplicationConfiguration>`,
+	}}
+	sourceServer := httptest.NewServer(source)
+	defer sourceServer.Close()
+	target := &bucketConfigHandler{configs: map[string]string{}}
+	targetServer := httptest.NewServer(target)
+	defer targetServer.Close()
+
+	t.Setenv(mcEnvHostPrefix+"source", strings.Replace(sourceServer.URL, "://", "://access:secret@", 1))
+	t.Setenv(mcEnvHostPrefix+"target", strings.Replace(targetServer.URL, "://", "://access:secret@", 1))
+
+	copied, err := copyBucketConfigs(context.Background(), "source/bucket", "target/bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"versioning", "tags"}; !reflect.DeepEqual(copied, expected) {
+		t.Fatalf("expected %v copied, got %v", expected, copied)
+	}
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if !strings.Contains(target.configs["versioning"], "<Status>Enabled</Status>") {
+		t.Errorf("unexpected versioning %s", target.configs["versioning"])
+	}
+	if !strings.Contains(target.configs["tagging"], "<Key>team</Key><Value>data</Value>") {
+		t.Errorf("unexpected tags %s", target.configs["tagging"])
+	}
+	// Replication points to the remote targets of the source.
+	if _, ok := target.configs["replication"]; ok {
+		t.Error("expected no replication to be copied")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// statsFlag asks bulk commands for a summary once they are done.
+var statsFlag = cli.BoolFlag{
+	Name:  "stats",
+	Usage: "print a summary of objects, bytes, throughput, duration, retries, skipped and failed at the end",
+}
+
+// globalRetries counts the requests the S3 client sent again after
+// an error it retries on, across all aliases.
+var globalRetries int64
+
+// retryCountingTransport counts requests which are sent again after a
+// response or an error which made the S3 client retry, it mirrors the
+// rules of minio-go. Failures of the last attempt are not retries.
+type retryCountingTransport struct {
+	transport http.RoundTripper
+
+	// Method and URL of the requests whose last attempt failed.
+	failed sync.Map
+}
+
+func (t *retryCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.String()
+	if _, ok := t.failed.LoadAndDelete(key); ok {
+		atomic.AddInt64(&globalRetries, 1)
+	}
+	resp, e := t.transport.RoundTrip(req)
+	if isRetriedResponse(resp, e) {
+		t.failed.Store(key, struct{}{})
+	}
+	return resp, e
+}
+
+// isRetriedResponse returns true if the S3 client retries a request
+// which failed with the response or the error.
+func isRetriedResponse(resp *http.Response, e error) bool {
+	if e != nil {
+		return !errors.Is(e, context.Canceled) && !errors.Is(e, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, 499, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// bulkStats keeps tabs on the outcome of every object handled by
+// a bulk command, it is safe for concurrent use.
+type bulkStats struct {
+	startTime    time.Time
+	startRetries int64
+
+	objects int64
+	bytes   int64
+	skipped int64
+	failed  int64
+}
+
+func newBulkStats() *bulkStats {
+	return &bulkStats{
+		startTime:    time.Now(),
+		startRetries: atomic.LoadInt64(&globalRetries),
+	}
+}
+
+// Succeeded records an object of size bytes as processed.
+func (s *bulkStats) Succeeded(size int64) {
+	atomic.AddInt64(&s.objects, 1)
+	atomic.AddInt64(&s.bytes, size)
+}
+
+// AddBytes records bytes of an object whose outcome is reported
+// separately, for results which do not carry the object size.
+func (s *bulkStats) AddBytes(size int64) {
+	atomic.AddInt64(&s.bytes, size)
+}
+
+// Skipped records an object which was left untouched.
+func (s *bulkStats) Skipped() {
+	atomic.AddInt64(&s.skipped, 1)
+}
+
+// Failed records an object which could not be processed.
+func (s *bulkStats) Failed() {
+	atomic.AddInt64(&s.failed, 1)
+}
+
+// Message returns the stats captured so far.
+func (s *bulkStats) Message() bulkStatsMessage {
+	duration := time.Since(s.startTime)
+	msg := bulkStatsMessage{
+		Status:   "success",
+		Objects:  atomic.LoadInt64(&s.objects),
+		Bytes:    atomic.LoadInt64(&s.bytes),
+		Duration: duration.Round(time.Millisecond).String(),
+		Retries:  atomic.LoadInt64(&globalRetries) - s.startRetries,
+		Skipped:  atomic.LoadInt64(&s.skipped),
+		Failed:   atomic.LoadInt64(&s.failed),
+	}
+	if duration > 0 {
+		msg.Throughput = float64(msg.Bytes) / duration.Seconds()
+	}
+	return msg
+}
+
+// bulkStatsMessage container for the summary of a bulk command.
+type bulkStatsMessage struct {
+	Status     string  `json:"status"`
+	Type       string  `json:"type"`
+	Objects    int64   `json:"objects"`
+	Bytes      int64   `json:"bytes"`
+	Throughput float64 `json:"throughput"`
+	Duration   string  `json:"duration"`
+	Retries    int64   `json:"retries"`
+	Skipped    int64   `json:"skipped"`
+	Failed     int64   `json:"failed"`
+}
+
+func (s bulkStatsMessage) String() string {
+	return console.Colorize("Stats", fmt.Sprintf("Objects: %d, Size: %s, Duration: %s, Throughput: %s/s, Retries: %d, Skipped: %d, Failed: %d",
+		s.Objects, humanize.IBytes(uint64(s.Bytes)), s.Duration, humanize.IBytes(uint64(s.Throughput)),
+		s.Retries, s.Skipped, s.Failed))
+}
+
+func (s bulkStatsMessage) JSON() string {
+	s.Type = "stats"
+	statsMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(statsMessageBytes)
+}
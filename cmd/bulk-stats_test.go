@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRetryCountingTransport(t *testing.T) {
+	errReset := errors.New("connection reset by peer")
+	testCases := []struct {
+		// Outcome of each attempt of the same request, nil is a
+		// response with the status code.
+		statusCodes []int
+		errs        []error
+		retries     int64
+	}{
+		{[]int{http.StatusOK}, []error{nil}, 0},
+		{[]int{http.StatusNotFound}, []error{nil}, 0},
+		{[]int{http.StatusServiceUnavailable, http.StatusOK}, []error{nil, nil}, 1},
+		{[]int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK}, []error{nil, nil, nil}, 2},
+		{[]int{0, http.StatusOK}, []error{errReset, nil}, 1},
+		// The failure of the last attempt is not a retry.
+		{[]int{http.StatusServiceUnavailable}, []error{nil}, 0},
+		// Canceled requests are never retried.
+		{[]int{0, http.StatusOK}, []error{context.Canceled, nil}, 0},
+	}
+	for i, testCase := range testCases {
+		attempt := 0
+		transport := &retryCountingTransport{transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			defer func() { attempt++ }()
+			if e := testCase.errs[attempt]; e != nil {
+				return nil, e
+			}
+			return &http.Response{StatusCode: testCase.statusCodes[attempt], Request: req}, nil
+		})}
+		start := atomic.LoadInt64(&globalRetries)
+		for range testCase.statusCodes {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:9000/bucket/object", nil)
+			transport.RoundTrip(req)
+		}
+		if retries := atomic.LoadInt64(&globalRetries) - start; retries != testCase.retries {
+			t.Errorf("Test %d: expected %d retries, got %d", i+1, testCase.retries, retries)
+		}
+	}
+
+	// Failed requests to other objects are not retries.
+	transport := &retryCountingTransport{transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Request: req}, nil
+	})}
+	start := atomic.LoadInt64(&globalRetries)
+	for _, object := range []string{"a", "b", "c"} {
+		transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://localhost:9000/bucket/"+object, nil))
+	}
+	if retries := atomic.LoadInt64(&globalRetries) - start; retries != 0 {
+		t.Errorf("expected no retries, got %d", retries)
+	}
+}
+
+func TestBulkStats(t *testing.T) {
+	stats := newBulkStats()
+	stats.Succeeded(10)
+	stats.Succeeded(20)
+	stats.AddBytes(5)
+	stats.Skipped()
+	stats.Failed()
+	stats.Failed()
+
+	msg := stats.Message()
+	if msg.Objects != 2 || msg.Bytes != 35 || msg.Skipped != 1 || msg.Failed != 2 {
+		t.Fatalf("unexpected stats %+v", msg)
+	}
+	if msg.Status != "success" || msg.Duration == "" {
+		t.Fatalf("unexpected stats %+v", msg)
+	}
+	if msg.JSON() == "" || msg.String() == "" {
+		t.Fatal("expected the stats to be printable")
+	}
+}
@@ -18,12 +18,14 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -55,6 +57,28 @@ var catFlags = []cli.Flag{
 		Name:  "tail",
 		Usage: "tail number of bytes at ending of file",
 	},
+	cli.Int64Flag{
+		Name:  "length",
+		Usage: "number of bytes displayed from --offset or --tail",
+	},
+	cli.StringFlag{
+		Name:  "range",
+		Usage: "display the bytes START-END of an object, both included, or from START to its end with START-",
+	},
+	cli.StringFlag{
+		Name:  "lambda-arn",
+		Usage: "read objects through the object lambda (transform) function of this ARN (MinIO servers only)",
+	},
+	cli.StringFlag{
+		Name:  "cse-key",
+		Usage: "decrypt objects encrypted on the client with the local key or the OpenPGP secret keys of FILE",
+	},
+	prettyTableFlag,
+	cli.Int64Flag{
+		Name:  "records",
+		Usage: "number of records rendered by --pretty-table",
+		Value: 20,
+	},
 }
 
 // Display contents of a file.
@@ -64,7 +88,7 @@ var catCmd = cli.Command{
 	Action:       mainCat,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(catFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(catFlags, getConditionFlags...), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -75,7 +99,8 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT_KEY:     list of comma delimited prefix=secret values
+  MC_CSE_PASSPHRASE:  passphrase of the OpenPGP secret keys of --cse-key
 
 EXAMPLES:
   1. Stream an object from Amazon S3 cloud storage to mplayer standard input.
@@ -99,6 +124,27 @@ EXAMPLES:
 
   7. Display the content of a particular object version
      {{.Prompt}} {{.HelpName}} --vid "3ddac055-89a7-40fa-8cd3-530a5581b6b8" play/my-bucket/my-object
+
+  8. Display the content of an object transformed by a MinIO object lambda function.
+     {{.Prompt}} {{.HelpName}} --lambda-arn "arn:minio:s3-object-lambda::redact:webhook" myminio/my-bucket/customers.csv
+
+  9. Display the content of an object through an Amazon S3 Object Lambda access point, using its alias.
+     {{.Prompt}} {{.HelpName}} s3/my-olap-a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6--ol-s3/customers.csv
+
+  10. Display the content of an object only if it changed since it was last read, given its ETag then.
+     {{.Prompt}} {{.HelpName}} --if-none-match "5d41402abc4b2a76b9719d911017c592" play/my-bucket/my-object
+
+  11. Display the first 50 records of a CSV object as an aligned table.
+     {{.Prompt}} {{.HelpName}} --pretty-table --records 50 s3/data-lake/customers.csv
+
+  12. Display the 1KiB header of a large object, without downloading the rest of it.
+     {{.Prompt}} {{.HelpName}} --range 0-1023 s3/mysql-backups/backups-201810.gz | xxd
+
+  13. Display 100 bytes of an object from the offset 4096.
+     {{.Prompt}} {{.HelpName}} --offset 4096 --length 100 s3/mysql-backups/backups-201810.gz | xxd
+
+  14. Display an object encrypted on the client with a local key.
+     {{.Prompt}} {{.HelpName}} --cse-key ~/.mc/backup.key s3/documents/notes.txt
 `,
 }
 
@@ -159,13 +205,20 @@ func (s prettyStdout) Write(input []byte) (int, error) {
 }
 
 type catOpts struct {
-	args      []string
-	versionID string
-	timeRef   time.Time
-	startO    int64
-	tailO     int64
-	isZip     bool
-	stdinMode bool
+	args       []string
+	versionID  string
+	timeRef    time.Time
+	startO     int64
+	tailO      int64
+	lengthO    int64
+	isZip      bool
+	stdinMode  bool
+	lambdaArn  string
+	conditions GetConditions
+	cseKey     *cseKey
+
+	prettyTable bool
+	records     int64
 }
 
 // parseCatSyntax performs command-line input validation for cat command.
@@ -199,30 +252,94 @@ func parseCatSyntax(ctx *cli.Context) catOpts {
 	o.isZip = ctx.Bool("zip")
 	o.startO = ctx.Int64("offset")
 	o.tailO = ctx.Int64("tail")
+	o.lengthO = ctx.Int64("length")
 	if o.tailO != 0 && o.startO != 0 {
 		fatalIf(errInvalidArgument().Trace(), "You cannot specify both --tail and --offset")
 	}
-	if o.tailO < 0 || o.startO < 0 {
-		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --tail or --offset")
+	if o.tailO < 0 || o.startO < 0 || o.lengthO < 0 {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --tail, --offset or --length")
+	}
+	if byteRange := ctx.String("range"); byteRange != "" {
+		if o.startO != 0 || o.tailO != 0 || o.lengthO != 0 {
+			fatalIf(errInvalidArgument().Trace(), "You cannot combine --range with --tail, --offset or --length")
+		}
+		var err *probe.Error
+		o.startO, o.lengthO, err = parseByteRange(byteRange)
+		fatalIf(err.Trace(byteRange), "Unable to parse --range.")
+	}
+	isRanged := o.startO != 0 || o.tailO != 0 || o.lengthO != 0
+	if o.isZip && isRanged {
+		fatalIf(errInvalidArgument().Trace(), "You cannot combine --zip with --tail, --offset, --length or --range")
 	}
-	if o.isZip && (o.tailO != 0 || o.startO != 0) {
-		fatalIf(errInvalidArgument().Trace(), "You cannot combine --zip with --tail or --offset")
+	if o.stdinMode && (o.isZip || isRanged) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot use --zip --tail, --offset, --length or --range with stdin")
 	}
-	if o.stdinMode && (o.isZip || o.startO != 0 || o.tailO != 0) {
-		fatalIf(errInvalidArgument().Trace(), "You cannot use --zip --tail or --offset with stdin")
+	o.prettyTable = ctx.Bool("pretty-table")
+	o.records = ctx.Int64("records")
+	if o.prettyTable && isRanged {
+		fatalIf(errInvalidArgument().Trace(), "You cannot combine --pretty-table with --tail, --offset, --length or --range")
+	}
+	if o.records <= 0 {
+		fatalIf(errInvalidArgument().Trace(), "--records must be greater than zero")
+	}
+	o.lambdaArn = ctx.String("lambda-arn")
+	if o.lambdaArn != "" {
+		fatalIf(checkLambdaArn(o.lambdaArn).Trace(o.lambdaArn), "Unable to validate --lambda-arn.")
+		if o.isZip || isRanged {
+			fatalIf(errInvalidArgument().Trace(), "You cannot combine --lambda-arn with --zip, --tail, --offset, --length or --range")
+		}
+	}
+	if keyFile := ctx.String("cse-key"); keyFile != "" {
+		if isRanged {
+			fatalIf(errInvalidArgument().Trace(), "You cannot combine --cse-key with --tail, --offset, --length or --range")
+		}
+		var err *probe.Error
+		o.cseKey, err = loadCSEKey(keyFile)
+		fatalIf(err, "Unable to load the client-side encryption key.")
+	}
+	var err *probe.Error
+	o.conditions, err = parseGetConditions(ctx)
+	fatalIf(err, "Unable to parse conditional read flags.")
+	if o.stdinMode && o.conditions.IsSet() {
+		fatalIf(errInvalidArgument().Trace(), "You cannot use conditional read flags with stdin")
 	}
 
 	return o
 }
 
+// parseByteRange parses a --range, START-END for the bytes START to END
+// both included or START- for the bytes from START to the end. It
+// returns the start and the length of the range, zero up to the end.
+func parseByteRange(byteRange string) (start, length int64, err *probe.Error) {
+	startStr, endStr, ok := strings.Cut(byteRange, "-")
+	start, e := strconv.ParseInt(startStr, 10, 64)
+	if !ok || e != nil || start < 0 {
+		return 0, 0, probe.NewError(fmt.Errorf("invalid range `%s`, expected START-END or START-", byteRange))
+	}
+	if endStr == "" {
+		return start, 0, nil
+	}
+	end, e := strconv.ParseInt(endStr, 10, 64)
+	if e != nil || end < start {
+		return 0, 0, probe.NewError(fmt.Errorf("invalid range `%s`, END must be a number not less than START", byteRange))
+	}
+	return start, end - start + 1, nil
+}
+
 // catURL displays contents of a URL to stdout.
-func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixSSEPair, o catOpts) *probe.Error {
+func catURL(ctx context.Context, sourceURL string, encKeyDB encryptionConfig, o catOpts) *probe.Error {
 	var reader io.ReadCloser
 	size := int64(-1)
 	switch sourceURL {
 	case "-":
 		reader = os.Stdin
 	default:
+		if o.prettyTable && tableFormatOf(sourceURL, "") == tableFormatParquet && o.lambdaArn == "" {
+			if o.versionID != "" || !o.timeRef.IsZero() {
+				return probe.NewError(errors.New("Parquet objects can only be previewed at their latest version")).Trace(sourceURL)
+			}
+			return previewParquetTable(ctx, sourceURL, encKeyDB, o.records).Trace(sourceURL)
+		}
 		versionID := o.versionID
 		var err *probe.Error
 		// Try to stat the object, the purpose is to:
@@ -243,27 +360,62 @@ func catURL(ctx context.Context, sourceURL string, encKeyDB map[string][]prefixS
 				}
 			}
 
-			if client.GetURL().Type == objectStorage {
+			// Transformed objects don't have the size of the stored object.
+			transformed := o.lambdaArn != "" || isObjectLambdaAlias(client.GetURL())
+			if client.GetURL().Type == objectStorage && !transformed {
 				size = content.Size - o.startO
 				if size < 0 {
 					err := probe.NewError(fmt.Errorf("specified offset (%d) bigger than file (%d)", o.startO, content.Size))
 					return err.Trace(sourceURL)
 				}
+				if o.lengthO > 0 && o.lengthO < size {
+					size = o.lengthO
+				}
 			}
 		} else {
 			return err.Trace(sourceURL)
 		}
-		gopts := GetOptions{VersionID: versionID, Zip: o.isZip, RangeStart: o.startO}
+		gopts := GetOptions{VersionID: versionID, Zip: o.isZip, RangeStart: o.startO, RangeLength: o.lengthO, LambdaArn: o.lambdaArn, Conditions: o.conditions}
 		if reader, err = getSourceStreamFromURL(ctx, sourceURL, encKeyDB, getSourceOpts{
 			GetOptions: gopts,
 			fetchStat:  false,
 			preserve:   false,
 		}); err != nil {
+			if _, ok := err.ToGoError().(ObjectNotModified); ok {
+				// Unchanged objects are not an error, stdout is
+				// left empty unless a JSON result is requested.
+				if globalJSON {
+					printMsg(notModifiedMessage{Source: sourceURL})
+				}
+				return nil
+			}
 			return err.Trace(sourceURL)
 		}
 		defer reader.Close()
 	}
-	return catOut(reader, size).Trace(sourceURL)
+	var plaintext io.Reader = reader
+	if o.cseKey != nil {
+		var err *probe.Error
+		if plaintext, err = decryptReader(reader, o.cseKey); err != nil {
+			return err.Trace(sourceURL)
+		}
+		size = -1
+	}
+	if o.prettyTable {
+		return catTableOut(plaintext, o.records).Trace(sourceURL)
+	}
+	return catOut(plaintext, size).Trace(sourceURL)
+}
+
+// catTableOut displays the first records of tabular data as a table,
+// other content is displayed as is. The format is always sniffed since
+// cat does not decompress objects.
+func catTableOut(r io.Reader, nrecords int64) *probe.Error {
+	br := bufio.NewReader(r)
+	if ok, err := previewTable(br, "", nrecords); ok || err != nil {
+		return err
+	}
+	return catOut(br, -1)
 }
 
 // catOut reads from reader stream and writes to stdout. Also check the length of the
@@ -322,12 +474,13 @@ func mainCat(cliCtx *cli.Context) error {
 
 	// check 'cat' cli arguments.
 	o := parseCatSyntax(cliCtx)
+	setTablePreviewColors()
 
 	// Set command flags from context.
 
 	// handle std input data.
 	if o.stdinMode {
-		fatalIf(catOut(os.Stdin, -1).Trace(), "Unable to read from standard input.")
+		fatalIf(catURL(ctx, "-", encKeyDB, o).Trace(), "Unable to read from standard input.")
 		return nil
 	}
 
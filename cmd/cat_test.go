@@ -18,8 +18,11 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"io"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -62,3 +65,104 @@ func TestPrettyStdout(t *testing.T) {
 		}
 	}
 }
+
+func TestSniffTableFormat(t *testing.T) {
+	testCases := []struct {
+		content string
+		format  string
+	}{
+		{"", ""},
+		{"plain text\nwithout any delimiter\n", ""},
+		{"name,age\nalice,30\nbob,25\n", tableFormatCSV},
+		{"name;age\nalice;30\n", tableFormatCSV},
+		{"name\tage\nalice\t30\n", tableFormatTSV},
+		{"name,age\nalice\n", ""},
+		{"{\"name\":\"alice\"}\n{\"name\":\"bob\"}\n", tableFormatJSON},
+		// A JSON document is not a sequence of records.
+		{"{\n  \"name\": \"alice\"\n}\n", ""},
+	}
+	for i, testCase := range testCases {
+		if format := sniffTableFormat([]byte(testCase.content)); format != testCase.format {
+			t.Fatalf("Test %d: expected format `%s`, found `%s`", i+1, testCase.format, format)
+		}
+	}
+}
+
+func TestReadJSONTable(t *testing.T) {
+	content := `{"id":1,"name":"alice","tags":["a","b"]}
+
+{"name":"bob","id":2,"city":"Berlin"}
+{"id":3}
+`
+	columns, rows, e := readJSONTable(strings.NewReader(content), 2)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if expected := []string{"id", "name", "tags", "city"}; !reflect.DeepEqual(columns, expected) {
+		t.Fatalf("expected columns %v, found %v", expected, columns)
+	}
+	expected := [][]string{{"1", "alice", `["a","b"]`, ""}, {"2", "bob", "", "Berlin"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("expected rows %v, found %v", expected, rows)
+	}
+
+	if _, _, e = readJSONTable(strings.NewReader("[1,2]\n"), 10); e == nil {
+		t.Fatal("expected an error for a record which is not an object")
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	var b bytes.Buffer
+	renderTable(&b, []string{"name", "city"}, [][]string{
+		{"alice", "Berlin"},
+		{"bob\nsmith", strings.Repeat("x", 50)},
+		{"carol"},
+	})
+	expected := `name       city
+---------  ----------------------------------------
+alice      Berlin
+bob smith  xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx...
+carol
+`
+	if b.String() != expected {
+		t.Fatalf("expected table\n%s\nfound\n%s", expected, b.String())
+	}
+}
+
+func TestPreviewTableFallback(t *testing.T) {
+	br := bufio.NewReader(strings.NewReader("not tabular\n"))
+	ok, err := previewTable(br, "", 10)
+	if ok || err != nil {
+		t.Fatalf("expected no table and no error, found %v, %v", ok, err)
+	}
+	// The content is left to be displayed as is.
+	if rest, _ := io.ReadAll(br); string(rest) != "not tabular\n" {
+		t.Fatalf("expected the content to be left unread, found `%s`", rest)
+	}
+}
+
+func TestParseByteRange(t *testing.T) {
+	testCases := []struct {
+		byteRange string
+		start     int64
+		length    int64
+		valid     bool
+	}{
+		{"0-1023", 0, 1024, true},
+		{"100-100", 100, 1, true},
+		{"4096-", 4096, 0, true},
+		{"100-99", 0, 0, false},
+		{"-100", 0, 0, false},
+		{"100", 0, 0, false},
+		{"a-b", 0, 0, false},
+	}
+	for i, testCase := range testCases {
+		start, length, err := parseByteRange(testCase.byteRange)
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, found %v", i+1, testCase.valid, err)
+		}
+		if start != testCase.start || length != testCase.length {
+			t.Errorf("Test %d: expected %d+%d, found %d+%d", i+1, testCase.start, testCase.length, start, length)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var checksumFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "algo, algorithm",
+		Value: "SHA256",
+		Usage: "checksum algorithm, one of CRC32C, SHA1, SHA256 or CRC64NVME",
+	},
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "checksum all objects under the prefix recursively",
+	},
+	cli.StringFlag{
+		Name:  "check",
+		Usage: "compare the objects against a manifest of checksums at FILE, as printed by this command or sha256sum",
+	},
+}
+
+// Print or verify the checksums of objects.
+var checksumCmd = cli.Command{
+	Name:         "checksum",
+	Usage:        "print or verify checksums of objects",
+	Action:       mainChecksum,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        joinFlags(checksumFlags, ioFlags, globalFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+NOTE:
+  Checksums stored by the server with the objects are used when present, the other objects are downloaded
+  and hashed. Checksums are printed in hex, with the keys relative to the folder of TARGET.
+
+EXAMPLES:
+  1. Print the SHA256 checksums of all objects under a prefix.
+     {{.Prompt}} {{.HelpName}} --recursive s3/records/2023/
+
+  2. Save the CRC32C checksums of the objects of a bucket to a manifest.
+     {{.Prompt}} {{.HelpName}} --recursive --algo crc32c s3/records/ > records.crc32c
+
+  3. Verify the objects of a prefix against a manifest written by sha256sum, reporting mismatches.
+     {{.Prompt}} cd /srv/records/2023 && sha256sum */* > ~/records.sha256
+     {{.Prompt}} {{.HelpName}} --recursive --check ~/records.sha256 s3/records/2023/
+
+  4. Print the SHA1 checksum of an object in JSON.
+     {{.Prompt}} {{.HelpName}} --algo sha1 --json s3/records/2023/report.pdf
+`,
+}
+
+// Results of comparing an object against a checksum manifest.
+const (
+	checksumOK       = "OK"
+	checksumFailed   = "FAILED"
+	checksumNotFound = "MISSING"
+)
+
+// checksumMessage container for the checksum of an object
+type checksumMessage struct {
+	Status    string `json:"status"`
+	Key       string `json:"key"`
+	URL       string `json:"url"`
+	Algorithm string `json:"algorithm"`
+	Checksum  string `json:"checksum,omitempty"`
+	Expected  string `json:"expected,omitempty"`
+	Computed  bool   `json:"computed"`
+	Result    string `json:"result,omitempty"`
+}
+
+// String prints the checksum in the format of sha256sum, or the result
+// of the comparison against a manifest.
+func (c checksumMessage) String() string {
+	switch c.Result {
+	case "":
+		return c.Checksum + "  " + c.Key
+	case checksumOK:
+		return console.Colorize("ChecksumOK", c.Key+": "+c.Result)
+	}
+	return console.Colorize("ChecksumFailed", c.Key+": "+c.Result)
+}
+
+// JSON jsonified checksum message
+func (c checksumMessage) JSON() string {
+	c.Status = "success"
+	checksumMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(checksumMessageBytes)
+}
+
+// checksumOpts holds the options of the checksum command.
+type checksumOpts struct {
+	algorithm string
+	recursive bool
+	manifest  map[string]string // Expected checksums by key, with --check.
+}
+
+// parseChecksumSyntax performs command-line input validation for checksum command.
+func parseChecksumSyntax(ctx *cli.Context) checksumOpts {
+	if !ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	algorithm, err := parseChecksumAlgorithm(ctx.String("algo"))
+	fatalIf(err.Trace(ctx.String("algo")), "Unable to validate --algo.")
+	o := checksumOpts{algorithm: algorithm, recursive: ctx.Bool("recursive")}
+
+	if manifestFile := ctx.String("check"); manifestFile != "" {
+		f, e := os.Open(manifestFile)
+		fatalIf(probe.NewError(e).Trace(manifestFile), "Unable to open the checksum manifest.")
+		defer f.Close()
+		o.manifest, err = parseChecksumManifest(f)
+		fatalIf(err.Trace(manifestFile), "Unable to read the checksum manifest.")
+	}
+	return o
+}
+
+// parseChecksumManifest reads the checksums of a manifest in the format
+// of sha256sum, a hex checksum and a key per line separated by a space
+// and a space or an asterisk.
+func parseChecksumManifest(r io.Reader) (map[string]string, *probe.Error) {
+	manifest := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		checksum, key, ok := strings.Cut(text, " ")
+		if ok && (strings.HasPrefix(key, " ") || strings.HasPrefix(key, "*")) {
+			key = key[1:]
+		}
+		if _, e := hex.DecodeString(checksum); !ok || e != nil || checksum == "" || key == "" {
+			return nil, probe.NewError(fmt.Errorf("line %d is not a checksum followed by a key", line))
+		}
+		manifest[strings.TrimPrefix(key, "./")] = strings.ToLower(checksum)
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return manifest, nil
+}
+
+// checksumBase returns the folder of a URL path, which the keys of the
+// printed checksums are relative to.
+func checksumBase(urlPath, separator string) string {
+	return urlPath[:strings.LastIndex(urlPath, separator)+1]
+}
+
+// objectChecksum returns the hex checksum of an object, retrieved from
+// the server when it stores one for the whole object, computed from the
+// content of the object otherwise.
+func objectChecksum(ctx context.Context, alias, urlStr, algorithm string, opts GetOptions) (checksum string, computed bool, err *probe.Error) {
+	value := serverChecksums(ctx, alias, urlStr, opts)[algorithm]
+	if value == "" {
+		if value, err = hashURL(ctx, alias, urlStr, algorithm, opts); err != nil {
+			return "", false, err
+		}
+		computed = true
+	}
+	sum, e := base64.StdEncoding.DecodeString(value)
+	if e != nil {
+		return "", false, probe.NewError(e)
+	}
+	return hex.EncodeToString(sum), computed, nil
+}
+
+// checksumURL prints or verifies the checksums of an object, or of the
+// objects of a folder. The keys of the manifest found are added to seen.
+func checksumURL(ctx context.Context, targetURL string, encKeyDB encryptionConfig, o checksumOpts, seen map[string]bool) (failed int, err *probe.Error) {
+	alias, _ := url2Alias(targetURL)
+	clnt, err := newClient(targetURL)
+	if err != nil {
+		return 0, err
+	}
+	targetPath := clnt.GetURL().Path
+	separator := string(clnt.GetURL().Separator)
+
+	checksumContent := func(content *ClientContent, base string) {
+		key := strings.TrimPrefix(content.URL.Path, base)
+		expected, inManifest := o.manifest[key]
+		if o.manifest != nil && !inManifest {
+			return
+		}
+		objectPath := filepath.ToSlash(filepath.Join(alias, content.URL.Path))
+		opts := GetOptions{SSE: getSSE(objectPath, encKeyDB[alias]), VersionID: content.VersionID}
+		checksum, computed, err := objectChecksum(ctx, alias, content.URL.String(), o.algorithm, opts)
+		if err != nil {
+			errorIf(err.Trace(content.URL.String()), "Unable to checksum `"+key+"`.")
+			failed++
+			return
+		}
+		msg := checksumMessage{
+			Key:       key,
+			URL:       content.URL.String(),
+			Algorithm: o.algorithm,
+			Checksum:  checksum,
+			Computed:  computed,
+		}
+		if o.manifest != nil {
+			seen[key] = true
+			msg.Expected = expected
+			msg.Result = checksumOK
+			if checksum != expected {
+				msg.Result = checksumFailed
+				failed++
+			}
+		}
+		printMsg(msg)
+	}
+
+	base := checksumBase(targetPath, separator)
+	if !o.recursive && !strings.HasSuffix(targetPath, separator) {
+		content, err := clnt.Stat(ctx, StatOptions{sse: getSSE(targetURL, encKeyDB[alias])})
+		if err != nil {
+			return 0, err
+		}
+		if !content.Type.IsDir() {
+			checksumContent(content, base)
+			return failed, nil
+		}
+		// Without --recursive, a folder checksums its own objects.
+		if clnt, err = newClient(targetURL + separator); err != nil {
+			return 0, err
+		}
+		base = targetPath + separator
+	}
+
+	for content := range clnt.List(ctx, ListOptions{Recursive: o.recursive, ShowDir: DirNone}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
+			failed++
+			continue
+		}
+		if !content.Type.IsDir() {
+			checksumContent(content, base)
+		}
+	}
+	return failed, nil
+}
+
+// mainChecksum is the main entry point for checksum command.
+func mainChecksum(cliCtx *cli.Context) error {
+	ctx, cancelChecksum := context.WithCancel(globalContext)
+	defer cancelChecksum()
+
+	console.SetColor("ChecksumOK", color.New(color.FgGreen, color.Bold))
+	console.SetColor("ChecksumFailed", color.New(color.FgRed, color.Bold))
+
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	o := parseChecksumSyntax(cliCtx)
+	var failed int
+	seen := make(map[string]bool, len(o.manifest))
+	for _, url := range cliCtx.Args() {
+		n, err := checksumURL(ctx, url, encKeyDB, o, seen)
+		fatalIf(err.Trace(url), "Unable to checksum `"+url+"`.")
+		failed += n
+	}
+
+	// Keys of the manifest which were not found are reported last.
+	var missing []string
+	for key := range o.manifest {
+		if !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		printMsg(checksumMessage{Key: key, Algorithm: o.algorithm, Expected: o.manifest[key], Result: checksumNotFound})
+	}
+	failed += len(missing)
+
+	if failed > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
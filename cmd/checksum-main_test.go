@@ -0,0 +1,104 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksumManifest(t *testing.T) {
+	testCases := []struct {
+		manifest string
+		expected map[string]string
+		success  bool
+	}{
+		{"", map[string]string{}, true},
+		{"0A0B  dir/a.txt\n", map[string]string{"dir/a.txt": "0a0b"}, true},
+		// Binary mode of sha256sum, paths starting with ./ and blank lines.
+		{"0a0b *a.txt\r\n\n0c0d  ./b c.txt\n", map[string]string{"a.txt": "0a0b", "b c.txt": "0c0d"}, true},
+		{"0a0b\n", nil, false},
+		{"0a0b  \n", nil, false},
+		{"xyz  a.txt\n", nil, false},
+	}
+	for i, testCase := range testCases {
+		manifest, err := parseChecksumManifest(strings.NewReader(testCase.manifest))
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if testCase.success && !reflect.DeepEqual(manifest, testCase.expected) {
+			t.Fatalf("Test %d: Expected %v, got %v", i+1, testCase.expected, manifest)
+		}
+	}
+}
+
+func TestChecksumURL(t *testing.T) {
+	root := t.TempDir()
+	for name, data := range map[string]string{"a.txt": "123456789", "dir/b.txt": "", "dir/c.txt": "c"} {
+		fpath := filepath.Join(root, name)
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	sumA := "15e2b0d3c33891ebb0f1ef609ec419420c20e320ce94c65fbc8c3312448eb225"
+	sumB := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	rootManifest := map[string]string{"a.txt": sumA, "dir/b.txt": sumB, "dir/c.txt": "0000", "d.txt": "0000"}
+	testCases := []struct {
+		url       string
+		recursive bool
+		manifest  map[string]string
+		failed    int
+		seen      []string
+	}{
+		{root + string(os.PathSeparator), true, rootManifest, 1, []string{"a.txt", "dir/b.txt", "dir/c.txt"}},
+		{root + string(os.PathSeparator), false, rootManifest, 0, []string{"a.txt"}},
+		{filepath.Join(root, "a.txt"), false, rootManifest, 0, []string{"a.txt"}},
+		// Keys are relative to the folder of the target.
+		{filepath.Join(root, "dir"), true, rootManifest, 1, []string{"dir/b.txt", "dir/c.txt"}},
+		{filepath.Join(root, "dir"), false, map[string]string{"b.txt": sumB, "c.txt": "0000"}, 1, []string{"b.txt", "c.txt"}},
+	}
+	for i, testCase := range testCases {
+		seen := make(map[string]bool)
+		o := checksumOpts{algorithm: "SHA256", recursive: testCase.recursive, manifest: testCase.manifest}
+		failed, err := checksumURL(context.Background(), testCase.url, nil, o, seen)
+		if err != nil {
+			t.Fatalf("Test %d: %s", i+1, err)
+		}
+		if failed != testCase.failed {
+			t.Fatalf("Test %d: Expected %d failures, got %d", i+1, testCase.failed, failed)
+		}
+		for _, key := range testCase.seen {
+			if !seen[filepath.FromSlash(key)] {
+				t.Fatalf("Test %d: Expected %s to be checked, got %v", i+1, key, seen)
+			}
+		}
+		if len(seen) != len(testCase.seen) {
+			t.Fatalf("Test %d: Expected %v to be checked, got %v", i+1, testCase.seen, seen)
+		}
+	}
+}
@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"flag"
 	"testing"
 
 	"github.com/minio/cli"
@@ -47,3 +48,17 @@ func TestCLIOnUsageError(t *testing.T) {
 		checkOnUsageError(cmd, "")
 	}
 }
+
+// newTestCLIContext returns the context of a command with flags
+// invoked with args.
+func newTestCLIContext(t *testing.T, flags []cli.Flag, args ...string) *cli.Context {
+	t.Helper()
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range flags {
+		f.Apply(set)
+	}
+	if e := set.Parse(args); e != nil {
+		t.Fatalf("unable to parse %v: %v", args, e)
+	}
+	return cli.NewContext(nil, set, nil)
+}
@@ -58,7 +58,7 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey))
+		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.TLS.String()))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -91,6 +91,7 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 			if config.Insecure {
 				tlsConfig.InsecureSkipVerify = true
 			}
+			config.TLS.apply(tlsConfig)
 
 			var transport http.RoundTripper = &http.Transport{
 				Proxy:                 ieproxy.GetProxyFunc(),
@@ -102,6 +103,9 @@ func NewAdminFactory() func(config *Config) (*madmin.AdminClient, *probe.Error)
 				TLSClientConfig:       tlsConfig,
 				DisableCompression:    true,
 			}
+			if config.DebugLite {
+				transport = tlsInfoTransport{transport: transport}
+			}
 			transport = gzhttp.Transport(transport)
 
 			if config.Debug {
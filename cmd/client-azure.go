@@ -0,0 +1,889 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+)
+
+// azureAPI is the API of aliases served by Azure Blob Storage.
+const azureAPI = "azure"
+
+// Azure client, containers are listed as buckets and blobs as objects.
+type azureClient struct {
+	targetURL *ClientURL
+	api       *azblob.Client
+}
+
+// isAzureBlob returns true if host is an Azure Blob Storage endpoint.
+func isAzureBlob(host string) bool {
+	return strings.HasSuffix(host, ".blob.core.windows.net")
+}
+
+// isAzureAlias returns true if the alias is served by Azure Blob Storage.
+func isAzureAlias(hostCfg *aliasConfigV10) bool {
+	return strings.EqualFold(hostCfg.API, azureAPI)
+}
+
+// newAzureFactory encloses azureNew with a cache of the clients of
+// each account.
+func newAzureFactory() func(urlStr string, hostCfg *aliasConfigV10) (Client, *probe.Error) {
+	clientCache := make(map[uint32]*azblob.Client)
+	var mutex sync.Mutex
+
+	return func(urlStr string, hostCfg *aliasConfigV10) (Client, *probe.Error) {
+		targetURL := newClientURL(urlStr)
+		serviceURL := targetURL.Scheme + "://" + targetURL.Host + "/"
+
+		// The account name and key are the access and secret keys of the alias.
+		confHash := fnv.New32a()
+		confHash.Write([]byte(serviceURL + hostCfg.AccessKey + hostCfg.SecretKey))
+		confSum := confHash.Sum32()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		api, found := clientCache[confSum]
+		if !found {
+			var e error
+			if hostCfg.AccessKey == "" && hostCfg.SecretKey == "" {
+				api, e = azblob.NewClientWithNoCredential(serviceURL, nil)
+			} else {
+				var cred *azblob.SharedKeyCredential
+				cred, e = azblob.NewSharedKeyCredential(hostCfg.AccessKey, hostCfg.SecretKey)
+				if e == nil {
+					api, e = azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+				}
+			}
+			if e != nil {
+				return nil, probe.NewError(e).Trace(urlStr)
+			}
+			clientCache[confSum] = api
+		}
+		return &azureClient{targetURL: targetURL, api: api}, nil
+	}
+}
+
+// azureNew returns an initialized azureClient structure.
+var azureNew = newAzureFactory()
+
+// GetURL get url.
+func (c *azureClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+func (c *azureClient) url2BucketAndObject() (bucketName, objectName string) {
+	return url2BucketAndObject(c.targetURL)
+}
+
+// toClientError constructs a typed client error for known Azure errors.
+func (c *azureClient) toClientError(e error, bucket string) *probe.Error {
+	var respErr *azcore.ResponseError
+	switch {
+	case bloberror.HasCode(e, bloberror.ContainerNotFound):
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case bloberror.HasCode(e, bloberror.ContainerAlreadyExists):
+		return probe.NewError(BucketExists{Bucket: bucket})
+	case bloberror.HasCode(e, bloberror.InvalidResourceName):
+		return probe.NewError(BucketInvalid{Bucket: bucket})
+	case bloberror.HasCode(e, bloberror.BlobNotFound):
+		return probe.NewError(ObjectMissing{})
+	case errors.As(e, &respErr) && respErr.StatusCode == http.StatusNotModified:
+		return probe.NewError(ObjectNotModified{})
+	}
+	return probe.NewError(e)
+}
+
+func (c *azureClient) content(bucket, key string) *ClientContent {
+	url := c.targetURL.Clone()
+	url.Path = string(c.targetURL.Separator) + bucket
+	if key != "" {
+		url.Path += string(c.targetURL.Separator) + key
+	}
+	return &ClientContent{URL: url, BucketName: bucket}
+}
+
+func (c *azureClient) containerContent(name string, lastModified *time.Time) *ClientContent {
+	content := c.content(name, "")
+	content.Type = os.ModeDir
+	if lastModified != nil {
+		content.Time = *lastModified
+	}
+	return content
+}
+
+func (c *azureClient) prefixContent(bucket, prefix string) *ClientContent {
+	content := c.content(bucket, prefix)
+	content.Type = os.ModeDir
+	content.Time = time.Now()
+	return content
+}
+
+func (c *azureClient) blobContent(bucket string, item *container.BlobItem) *ClientContent {
+	content := c.content(bucket, *item.Name)
+	content.Type = os.FileMode(0o664)
+	if props := item.Properties; props != nil {
+		content.Size = to.Deref(props.ContentLength)
+		content.ETag = strings.Trim(string(to.Deref(props.ETag)), "\"")
+		content.Time = to.Deref(props.LastModified)
+		if props.AccessTier != nil {
+			content.StorageClass = string(*props.AccessTier)
+		}
+	}
+	content.UserMetadata = azureToUserMetadata(item.Metadata)
+	if strings.HasSuffix(*item.Name, string(c.targetURL.Separator)) {
+		content.Type = os.ModeDir
+	}
+	return content
+}
+
+// azureToUserMetadata converts the metadata of a blob to S3 user metadata.
+func azureToUserMetadata(metadata map[string]*string) map[string]string {
+	userMetadata := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		userMetadata[http.CanonicalHeaderKey("X-Amz-Meta-"+k)] = to.Deref(v)
+	}
+	return userMetadata
+}
+
+// Stat - get metadata of a container, blob or prefix.
+func (c *azureClient) Stat(ctx context.Context, opts StatOptions) (*ClientContent, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if opts.incomplete || opts.versionID != "" || !opts.timeRef.IsZero() {
+		return nil, probe.NewError(APINotImplemented{
+			API:     "Stat of incomplete uploads and versions",
+			APIType: azureAPI,
+		})
+	}
+	if bucket == "" {
+		url := c.targetURL.Clone()
+		url.Path = string(c.targetURL.Separator)
+		return &ClientContent{URL: url, Type: os.ModeDir}, nil
+	}
+	if object == "" {
+		props, e := c.api.ServiceClient().NewContainerClient(bucket).GetProperties(ctx, nil)
+		if e != nil {
+			return nil, c.toClientError(e, bucket).Trace(bucket)
+		}
+		return c.containerContent(bucket, props.LastModified), nil
+	}
+
+	if !strings.HasSuffix(object, string(c.targetURL.Separator)) {
+		props, e := c.api.ServiceClient().NewContainerClient(bucket).NewBlobClient(object).GetProperties(ctx, nil)
+		if e == nil {
+			content := c.content(bucket, object)
+			content.Type = os.FileMode(0o664)
+			content.Size = to.Deref(props.ContentLength)
+			content.ETag = strings.Trim(string(to.Deref(props.ETag)), "\"")
+			content.Time = to.Deref(props.LastModified)
+			content.StorageClass = to.Deref(props.AccessTier)
+			content.Metadata = map[string]string{
+				"Content-Type": to.Deref(props.ContentType),
+			}
+			for k, v := range map[string]*string{
+				"Cache-Control":       props.CacheControl,
+				"Content-Encoding":    props.ContentEncoding,
+				"Content-Disposition": props.ContentDisposition,
+				"Content-Language":    props.ContentLanguage,
+			} {
+				if v != nil {
+					content.Metadata[k] = *v
+				}
+			}
+			content.UserMetadata = azureToUserMetadata(props.Metadata)
+			return content, nil
+		}
+		if !bloberror.HasCode(e, bloberror.BlobNotFound) {
+			return nil, c.toClientError(e, bucket).Trace(c.targetURL.String())
+		}
+		// The blob is not found, look for a prefix.
+		object += string(c.targetURL.Separator)
+	}
+
+	pager := c.api.ServiceClient().NewContainerClient(bucket).NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Prefix:     to.Ptr(object),
+		MaxResults: to.Ptr(int32(1)),
+	})
+	page, e := pager.NextPage(ctx)
+	if e != nil {
+		return nil, c.toClientError(e, bucket).Trace(c.targetURL.String())
+	}
+	if len(page.Segment.BlobItems) > 0 {
+		return c.prefixContent(bucket, object), nil
+	}
+	return nil, probe.NewError(ObjectMissing{})
+}
+
+// List - list at delimited path, if not recursive.
+func (c *azureClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		if opts.Incomplete || opts.WithOlderVersions || !opts.TimeRef.IsZero() || opts.ListZip {
+			contentCh <- &ClientContent{
+				Err: probe.NewError(APINotImplemented{
+					API:     "List of incomplete uploads, versions and zip files",
+					APIType: azureAPI,
+				}),
+			}
+			return
+		}
+		if opts.Recursive {
+			c.listRecursiveInRoutine(ctx, contentCh, opts)
+		} else {
+			c.listInRoutine(ctx, contentCh, opts)
+		}
+	}()
+	return contentCh
+}
+
+// listContainers sends the containers of the account, sorted with
+// a trailing '/' as S3 buckets.
+func (c *azureClient) listContainers(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	var containers []*ClientContent
+	pager := c.api.NewListContainersPager(nil)
+	for pager.More() {
+		page, e := pager.NextPage(ctx)
+		if e != nil {
+			return nil, c.toClientError(e, "")
+		}
+		for _, item := range page.ContainerItems {
+			var lastModified *time.Time
+			if item.Properties != nil {
+				lastModified = item.Properties.LastModified
+			}
+			containers = append(containers, c.containerContent(*item.Name, lastModified))
+		}
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].BucketName+"/" < containers[j].BucketName+"/"
+	})
+	return containers, nil
+}
+
+func (c *azureClient) listInRoutine(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
+	b, o := c.url2BucketAndObject()
+	switch {
+	case b == "" && o == "":
+		containers, err := c.listContainers(ctx)
+		if err != nil {
+			contentCh <- &ClientContent{Err: err}
+			return
+		}
+		for _, content := range containers {
+			contentCh <- content
+		}
+	case b != "" && !strings.HasSuffix(c.targetURL.Path, string(c.targetURL.Separator)) && o == "":
+		content, err := c.Stat(ctx, StatOptions{})
+		if err != nil {
+			contentCh <- &ClientContent{Err: err.Trace(b)}
+			return
+		}
+		contentCh <- content
+	default:
+		pager := c.api.ServiceClient().NewContainerClient(b).NewListBlobsHierarchyPager(string(c.targetURL.Separator), &container.ListBlobsHierarchyOptions{
+			Prefix:  to.Ptr(o),
+			Include: container.ListBlobsInclude{Metadata: opts.WithMetadata},
+		})
+		for pager.More() {
+			page, e := pager.NextPage(ctx)
+			if e != nil {
+				contentCh <- &ClientContent{Err: c.toClientError(e, b)}
+				return
+			}
+			// Blobs and prefixes are returned apart, merge them in lexical order.
+			contents := make([]*ClientContent, 0, len(page.Segment.BlobItems)+len(page.Segment.BlobPrefixes))
+			for _, item := range page.Segment.BlobItems {
+				contents = append(contents, c.blobContent(b, item))
+			}
+			for _, prefix := range page.Segment.BlobPrefixes {
+				contents = append(contents, c.prefixContent(b, *prefix.Name))
+			}
+			sort.Slice(contents, func(i, j int) bool {
+				return contents[i].URL.Path < contents[j].URL.Path
+			})
+			for _, content := range contents {
+				contentCh <- content
+			}
+		}
+	}
+}
+
+func (c *azureClient) listRecursiveInRoutine(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
+	b, o := c.url2BucketAndObject()
+	listBlobs := func(bucket string) bool {
+		pager := c.api.ServiceClient().NewContainerClient(bucket).NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+			Prefix:  to.Ptr(o),
+			Include: container.ListBlobsInclude{Metadata: opts.WithMetadata},
+		})
+		for pager.More() {
+			page, e := pager.NextPage(ctx)
+			if e != nil {
+				contentCh <- &ClientContent{Err: c.toClientError(e, bucket)}
+				return false
+			}
+			for _, item := range page.Segment.BlobItems {
+				contentCh <- c.blobContent(bucket, item)
+			}
+		}
+		return true
+	}
+
+	if b != "" {
+		listBlobs(b)
+		return
+	}
+	containers, err := c.listContainers(ctx)
+	if err != nil {
+		contentCh <- &ClientContent{Err: err}
+		return
+	}
+	for _, content := range containers {
+		if opts.ShowDir == DirFirst {
+			contentCh <- content
+		}
+		if !listBlobs(content.BucketName) {
+			return
+		}
+		if opts.ShowDir == DirLast {
+			contentCh <- content
+		}
+	}
+}
+
+// ListBuckets - list the containers of the account.
+func (c *azureClient) ListBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	return c.listContainers(ctx)
+}
+
+// Get - get a reader of a blob.
+func (c *azureClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if opts.VersionID != "" || opts.Zip || opts.LambdaArn != "" || opts.SSE != nil {
+		return nil, probe.NewError(APINotImplemented{
+			API:     "Get of versions, zip files, lambdas and encrypted objects",
+			APIType: azureAPI,
+		})
+	}
+	o := &azblob.DownloadStreamOptions{}
+	if opts.RangeStart != 0 || opts.RangeLength > 0 {
+		o.Range = azblob.HTTPRange{Offset: opts.RangeStart, Count: opts.RangeLength}
+	}
+	if cond := opts.Conditions; cond.IsSet() {
+		modified := &blob.ModifiedAccessConditions{}
+		if cond.IfMatch != "" {
+			modified.IfMatch = to.Ptr(azcore.ETag(cond.IfMatch))
+		}
+		if cond.IfNoneMatch != "" {
+			modified.IfNoneMatch = to.Ptr(azcore.ETag(cond.IfNoneMatch))
+		}
+		if !cond.IfModifiedSince.IsZero() {
+			modified.IfModifiedSince = to.Ptr(cond.IfModifiedSince)
+		}
+		if !cond.IfUnmodifiedSince.IsZero() {
+			modified.IfUnmodifiedSince = to.Ptr(cond.IfUnmodifiedSince)
+		}
+		o.AccessConditions = &blob.AccessConditions{ModifiedAccessConditions: modified}
+	}
+
+	resp, e := c.api.DownloadStream(ctx, bucket, object, o)
+	if e != nil {
+		return nil, c.toClientError(e, bucket).Trace(c.targetURL.String())
+	}
+	return resp.Body, nil
+}
+
+// Put - upload a blob, metadata is limited to the standard headers
+// and the user metadata.
+func (c *azureClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	if opts.sse != nil {
+		return 0, probe.NewError(APINotImplemented{
+			API:     "Put of encrypted objects",
+			APIType: azureAPI,
+		})
+	}
+
+	headers := &blob.HTTPHeaders{BlobContentType: to.Ptr("application/octet-stream")}
+	metadata := map[string]*string{}
+	for k, v := range opts.metadata {
+		v := v
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Type":
+			headers.BlobContentType = &v
+		case "Cache-Control":
+			headers.BlobCacheControl = &v
+		case "Content-Encoding":
+			headers.BlobContentEncoding = &v
+		case "Content-Disposition":
+			headers.BlobContentDisposition = &v
+		case "Content-Language":
+			headers.BlobContentLanguage = &v
+		default:
+			// Azure metadata names are C# identifiers.
+			if name, ok := cutPrefixFold(k, "X-Amz-Meta-"); ok {
+				metadata[strings.ReplaceAll(strings.ToLower(name), "-", "_")] = &v
+			}
+		}
+	}
+
+	uploadOpts := &azblob.UploadStreamOptions{
+		HTTPHeaders: headers,
+		Metadata:    metadata,
+	}
+	if opts.multipartSize > 0 {
+		uploadOpts.BlockSize = int64(opts.multipartSize)
+	}
+	if opts.multipartThreads > 0 {
+		uploadOpts.Concurrency = int(opts.multipartThreads)
+	}
+	if _, e := c.api.UploadStream(ctx, bucket, object, hookreader.NewHook(reader, progress), uploadOpts); e != nil {
+		return 0, c.toClientError(e, bucket).Trace(c.targetURL.String())
+	}
+	if size < 0 {
+		content, err := c.Stat(ctx, StatOptions{})
+		if err != nil {
+			return 0, err.Trace(c.targetURL.String())
+		}
+		size = content.Size
+	}
+	return size, nil
+}
+
+// cutPrefixFold slices a case insensitive prefix off a string.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// PutPart - upload a blob of N bytes.
+func (c *azureClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	if size < 0 {
+		return c.Put(ctx, reader, size, progress, opts)
+	}
+	return c.Put(ctx, io.LimitReader(reader, size), size, progress, opts)
+}
+
+// Copy - copy a blob of the same account, the data is streamed through
+// the client as server side copies of Azure are asynchronous.
+func (c *azureClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	tokens := splitStr(filepath.ToSlash(source), string(c.targetURL.Separator), 3)
+	resp, e := c.api.DownloadStream(ctx, tokens[1], tokens[2], nil)
+	if e != nil {
+		return c.toClientError(e, tokens[1]).Trace(source)
+	}
+	defer resp.Body.Close()
+
+	putOpts := PutOptions{
+		metadata:   opts.metadata,
+		isPreserve: opts.isPreserve,
+	}
+	if _, err := c.Put(ctx, resp.Body, opts.size, progress, putOpts); err != nil {
+		return err.Trace(source)
+	}
+	return nil
+}
+
+// Remove - remove blobs, prefixes have no blob of their own. Containers
+// are removed once their blobs are, when removing buckets.
+func (c *azureClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		if isIncomplete {
+			resultCh <- RemoveResult{
+				Err: probe.NewError(APINotImplemented{
+					API:     "Remove of incomplete uploads",
+					APIType: azureAPI,
+				}),
+			}
+			return
+		}
+
+		removeContainer := func(bucket string) {
+			if !isRemoveBucket || bucket == "" {
+				return
+			}
+			if _, e := c.api.DeleteContainer(ctx, bucket, nil); e != nil {
+				resultCh <- RemoveResult{Err: c.toClientError(e, bucket)}
+				return
+			}
+			resultCh <- RemoveResult{BucketName: bucket}
+		}
+
+		prevBucket := ""
+		for content := range contentCh {
+			if content.Err != nil {
+				resultCh <- RemoveResult{Err: content.Err}
+				continue
+			}
+			bucket, object := url2BucketAndObject(&content.URL)
+			if bucket != prevBucket {
+				removeContainer(prevBucket)
+				prevBucket = bucket
+			}
+			if object == "" {
+				continue
+			}
+			if _, e := c.api.DeleteBlob(ctx, bucket, object, nil); e != nil {
+				if bloberror.HasCode(e, bloberror.BlobNotFound) && content.Type.IsDir() {
+					continue
+				}
+				resultCh <- RemoveResult{Err: c.toClientError(e, bucket).Trace(content.URL.String())}
+				continue
+			}
+			res := RemoveResult{BucketName: bucket}
+			res.ObjectName = object
+			resultCh <- res
+		}
+		removeContainer(prevBucket)
+	}()
+	return resultCh
+}
+
+// MakeBucket - create a container, folders are only prefixes on Azure.
+func (c *azureClient) MakeBucket(ctx context.Context, _ string, ignoreExisting, withLock bool) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if withLock {
+		return probe.NewError(APINotImplemented{
+			API:     "MakeBucket with object lock",
+			APIType: azureAPI,
+		})
+	}
+	if _, e := c.api.CreateContainer(ctx, bucket, nil); e != nil {
+		if ignoreExisting && bloberror.HasCode(e, bloberror.ContainerAlreadyExists) {
+			return nil
+		}
+		return c.toClientError(e, bucket)
+	}
+	return nil
+}
+
+// RemoveBucket - remove a container, Azure removes its blobs as well.
+func (c *azureClient) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		return probe.NewError(BucketInvalid{bucket + string(c.targetURL.Separator) + object})
+	}
+	if !forceRemove {
+		pager := c.api.NewListBlobsFlatPager(bucket, &azblob.ListBlobsFlatOptions{MaxResults: to.Ptr(int32(1))})
+		page, e := pager.NextPage(ctx)
+		if e != nil {
+			return c.toClientError(e, bucket)
+		}
+		if len(page.Segment.BlobItems) > 0 {
+			return probe.NewError(errors.New("The bucket you tried to delete is not empty"))
+		}
+	}
+	if _, e := c.api.DeleteContainer(ctx, bucket, nil); e != nil {
+		return c.toClientError(e, bucket)
+	}
+	return nil
+}
+
+func (c *azureClient) AddUserAgent(_, _ string) {
+}
+
+// Select - not implemented.
+func (c *azureClient) Select(_ context.Context, _ string, _ encrypt.ServerSide, _ SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "Select",
+		APIType: azureAPI,
+	})
+}
+
+// Watch - not implemented.
+func (c *azureClient) Watch(_ context.Context, _ WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "Watch",
+		APIType: azureAPI,
+	})
+}
+
+// ShareDownload - not implemented.
+func (c *azureClient) ShareDownload(_ context.Context, _ string, _ time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "ShareDownload",
+		APIType: azureAPI,
+	})
+}
+
+// ShareUpload - not implemented.
+func (c *azureClient) ShareUpload(_ context.Context, _ bool, _ time.Duration, _ string) (string, map[string]string, *probe.Error) {
+	return "", nil, probe.NewError(APINotImplemented{
+		API:     "ShareUpload",
+		APIType: azureAPI,
+	})
+}
+
+// SetObjectLockConfig - not implemented.
+func (c *azureClient) SetObjectLockConfig(_ context.Context, _ minio.RetentionMode, _ uint64, _ minio.ValidityUnit) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetObjectLockConfig",
+		APIType: azureAPI,
+	})
+}
+
+// GetObjectLockConfig - not implemented.
+func (c *azureClient) GetObjectLockConfig(_ context.Context) (status string, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, err *probe.Error) {
+	return "", "", 0, "", probe.NewError(APINotImplemented{
+		API:     "GetObjectLockConfig",
+		APIType: azureAPI,
+	})
+}
+
+// GetAccess - not implemented.
+func (c *azureClient) GetAccess(_ context.Context) (access, policyJSON string, err *probe.Error) {
+	return "", "", probe.NewError(APINotImplemented{
+		API:     "GetAccess",
+		APIType: azureAPI,
+	})
+}
+
+// GetAccessRules - not implemented.
+func (c *azureClient) GetAccessRules(_ context.Context) (map[string]string, *probe.Error) {
+	return map[string]string{}, probe.NewError(APINotImplemented{
+		API:     "GetBucketPolicy",
+		APIType: azureAPI,
+	})
+}
+
+// SetAccess - not implemented.
+func (c *azureClient) SetAccess(_ context.Context, _ string, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetAccess",
+		APIType: azureAPI,
+	})
+}
+
+// PutObjectRetention - not implemented.
+func (c *azureClient) PutObjectRetention(_ context.Context, _ string, _ minio.RetentionMode, _ time.Time, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectRetention",
+		APIType: azureAPI,
+	})
+}
+
+// GetObjectRetention - not implemented.
+func (c *azureClient) GetObjectRetention(_ context.Context, _ string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, probe.NewError(APINotImplemented{
+		API:     "GetObjectRetention",
+		APIType: azureAPI,
+	})
+}
+
+// PutObjectLegalHold - not implemented.
+func (c *azureClient) PutObjectLegalHold(_ context.Context, _ string, _ minio.LegalHoldStatus) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectLegalHold",
+		APIType: azureAPI,
+	})
+}
+
+// GetObjectLegalHold - not implemented.
+func (c *azureClient) GetObjectLegalHold(_ context.Context, _ string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "GetObjectLegalHold",
+		APIType: azureAPI,
+	})
+}
+
+// GetTags - not implemented.
+func (c *azureClient) GetTags(_ context.Context, _ string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetObjectTagging",
+		APIType: azureAPI,
+	})
+}
+
+// SetTags - not implemented.
+func (c *azureClient) SetTags(_ context.Context, _, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetObjectTagging",
+		APIType: azureAPI,
+	})
+}
+
+// DeleteTags - not implemented.
+func (c *azureClient) DeleteTags(_ context.Context, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "DeleteObjectTagging",
+		APIType: azureAPI,
+	})
+}
+
+// GetLifecycle - not implemented.
+func (c *azureClient) GetLifecycle(_ context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	return nil, time.Time{}, probe.NewError(APINotImplemented{
+		API:     "GetLifecycle",
+		APIType: azureAPI,
+	})
+}
+
+// SetLifecycle - not implemented.
+func (c *azureClient) SetLifecycle(_ context.Context, _ *lifecycle.Configuration) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetLifecycle",
+		APIType: azureAPI,
+	})
+}
+
+// GetVersion - not implemented.
+func (c *azureClient) GetVersion(_ context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, probe.NewError(APINotImplemented{
+		API:     "GetVersion",
+		APIType: azureAPI,
+	})
+}
+
+// SetVersion - not implemented.
+func (c *azureClient) SetVersion(_ context.Context, _ string, _ []string, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetVersion",
+		APIType: azureAPI,
+	})
+}
+
+// GetReplication - not implemented.
+func (c *azureClient) GetReplication(_ context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, probe.NewError(APINotImplemented{
+		API:     "GetReplication",
+		APIType: azureAPI,
+	})
+}
+
+// SetReplication - not implemented.
+func (c *azureClient) SetReplication(_ context.Context, _ *replication.Config, _ replication.Options) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetReplication",
+		APIType: azureAPI,
+	})
+}
+
+// RemoveReplication - not implemented.
+func (c *azureClient) RemoveReplication(_ context.Context) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "RemoveReplication",
+		APIType: azureAPI,
+	})
+}
+
+// GetReplicationMetrics - not implemented.
+func (c *azureClient) GetReplicationMetrics(_ context.Context) (replication.MetricsV2, *probe.Error) {
+	return replication.MetricsV2{}, probe.NewError(APINotImplemented{
+		API:     "GetReplicationMetrics",
+		APIType: azureAPI,
+	})
+}
+
+// ResetReplication - not implemented.
+func (c *azureClient) ResetReplication(_ context.Context, _ time.Duration, _ string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	return rinfo, probe.NewError(APINotImplemented{
+		API:     "ResetReplication",
+		APIType: azureAPI,
+	})
+}
+
+// ReplicationResyncStatus - not implemented.
+func (c *azureClient) ReplicationResyncStatus(_ context.Context, _ string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	return rinfo, probe.NewError(APINotImplemented{
+		API:     "ReplicationResyncStatus",
+		APIType: azureAPI,
+	})
+}
+
+// GetEncryption - not implemented.
+func (c *azureClient) GetEncryption(_ context.Context) (string, string, *probe.Error) {
+	return "", "", probe.NewError(APINotImplemented{
+		API:     "GetEncryption",
+		APIType: azureAPI,
+	})
+}
+
+// SetEncryption - not implemented.
+func (c *azureClient) SetEncryption(_ context.Context, _, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetEncryption",
+		APIType: azureAPI,
+	})
+}
+
+// DeleteEncryption - not implemented.
+func (c *azureClient) DeleteEncryption(_ context.Context) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "DeleteEncryption",
+		APIType: azureAPI,
+	})
+}
+
+// GetBucketInfo - not implemented.
+func (c *azureClient) GetBucketInfo(_ context.Context) (BucketInfo, *probe.Error) {
+	return BucketInfo{}, probe.NewError(APINotImplemented{
+		API:     "GetBucketInfo",
+		APIType: azureAPI,
+	})
+}
+
+// Restore - not implemented.
+func (c *azureClient) Restore(_ context.Context, _ string, _ int) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "Restore",
+		APIType: azureAPI,
+	})
+}
+
+// GetPart - not implemented.
+func (c *azureClient) GetPart(_ context.Context, _ int) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetPart",
+		APIType: azureAPI,
+	})
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+var azureTestTime = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// azureHandler is an http.Handler serving the containers and blobs of
+// an account with the Azure Blob Storage REST API.
+type azureHandler map[string]map[string]string
+
+func (h azureHandler) error(w http.ResponseWriter, r *http.Request, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(http.StatusNotFound)
+	if r.Method != http.MethodHead {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="utf-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+	}
+}
+
+func (h azureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	name, blobName, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if name == "" {
+		// List containers.
+		var names []string
+		for name := range h {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Containers>`)
+		for _, name := range names {
+			fmt.Fprintf(w, "<Container><Name>%s</Name><Properties><Last-Modified>%s</Last-Modified><Etag>0x1</Etag></Properties></Container>", name, azureTestTime.Format(http.TimeFormat))
+		}
+		fmt.Fprint(w, "</Containers><NextMarker /></EnumerationResults>")
+		return
+	}
+	blobs, ok := h[name]
+	if !ok {
+		h.error(w, r, "ContainerNotFound")
+		return
+	}
+	w.Header().Set("Last-Modified", azureTestTime.Format(http.TimeFormat))
+	switch {
+	case blobName == "" && query.Get("comp") == "list":
+		prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+		var names []string
+		for name := range blobs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+		prefixes := make(map[string]bool)
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if i := strings.Index(name[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				if p := name[:len(prefix)+i+1]; !prefixes[p] {
+					prefixes[p] = true
+					fmt.Fprintf(w, "<BlobPrefix><Name>%s</Name></BlobPrefix>", p)
+				}
+				continue
+			}
+			fmt.Fprintf(w, "<Blob><Name>%s</Name><Properties><Last-Modified>%s</Last-Modified><Etag>\"0x2\"</Etag><Content-Length>%d</Content-Length><AccessTier>Hot</AccessTier></Properties></Blob>",
+				name, azureTestTime.Format(http.TimeFormat), len(blobs[name]))
+		}
+		fmt.Fprint(w, "</Blobs><NextMarker /></EnumerationResults>")
+	case blobName == "":
+		// Container properties.
+		w.WriteHeader(http.StatusOK)
+	default:
+		data, ok := blobs[blobName]
+		if !ok {
+			h.error(w, r, "BlobNotFound")
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", "\"0x2\"")
+		w.Header().Set("x-ms-access-tier", "Cool")
+		w.Header().Set("x-ms-meta-owner", "mc")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(data))
+		}
+	}
+}
+
+func newTestAzureClient(c *C, serverURL, path string) *azureClient {
+	client, err := azureNew(serverURL+path, &aliasConfigV10{URL: serverURL, API: azureAPI})
+	c.Assert(err, IsNil)
+	return client.(*azureClient)
+}
+
+func (s *TestSuite) TestAzureURLMapping(c *C) {
+	c.Assert(isAzureBlob("account.blob.core.windows.net"), Equals, true)
+	c.Assert(isAzureBlob("account.s3.amazonaws.com"), Equals, false)
+	c.Assert(isAzureAlias(&aliasConfigV10{API: "Azure"}), Equals, true)
+	c.Assert(isAzureAlias(&aliasConfigV10{API: "s3v4"}), Equals, false)
+
+	client := &azureClient{targetURL: newClientURL("https://account.blob.core.windows.net/container/dir/blob")}
+	bucket, object := client.url2BucketAndObject()
+	c.Assert(bucket, Equals, "container")
+	c.Assert(object, Equals, "dir/blob")
+	c.Assert(client.content("container", "dir/blob").URL.String(), Equals, "https://account.blob.core.windows.net/container/dir/blob")
+	c.Assert(client.content("container", "").URL.Path, Equals, "/container")
+
+	owner := "mc"
+	userMetadata := azureToUserMetadata(map[string]*string{"owner": &owner})
+	c.Assert(userMetadata, DeepEquals, map[string]string{"X-Amz-Meta-Owner": "mc"})
+}
+
+func (s *TestSuite) TestAzureStat(c *C) {
+	server := httptest.NewServer(azureHandler{"container": {"blob.txt": "hello", "dir/blob": "world"}})
+	defer server.Close()
+
+	content, err := newTestAzureClient(c, server.URL, "/container/blob.txt").Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(5))
+	c.Assert(content.ETag, Equals, "0x2")
+	c.Assert(content.StorageClass, Equals, "Cool")
+	c.Assert(content.Time.Equal(azureTestTime), Equals, true)
+	c.Assert(content.Metadata["Content-Type"], Equals, "text/plain")
+	c.Assert(content.UserMetadata["X-Amz-Meta-Owner"], Equals, "mc")
+	c.Assert(content.Type.IsRegular(), Equals, true)
+
+	content, err = newTestAzureClient(c, server.URL, "/container").Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	c.Assert(content.BucketName, Equals, "container")
+
+	// A missing blob which is the prefix of other blobs is a folder.
+	content, err = newTestAzureClient(c, server.URL, "/container/dir").Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	c.Assert(content.URL.Path, Equals, "/container/dir/")
+
+	_, err = newTestAzureClient(c, server.URL, "/container/missing").Stat(context.Background(), StatOptions{})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, Equals, true)
+
+	_, err = newTestAzureClient(c, server.URL, "/missing").Stat(context.Background(), StatOptions{})
+	c.Assert(err, NotNil)
+	c.Assert(err.ToGoError(), Equals, BucketDoesNotExist{Bucket: "missing"})
+}
+
+func (s *TestSuite) TestAzureList(c *C) {
+	server := httptest.NewServer(azureHandler{
+		"container": {"a": "1", "dir/b": "22", "dir/sub/c": "333", "dirt": "4444"},
+		"other":     {},
+	})
+	defer server.Close()
+
+	testCases := []struct {
+		path      string
+		recursive bool
+		expected  []string
+	}{
+		{"/", false, []string{"/container", "/other"}},
+		{"/container/", false, []string{"/container/a", "/container/dir/", "/container/dirt"}},
+		{"/container/dir", false, []string{"/container/dir/", "/container/dirt"}},
+		{"/container/dir/", false, []string{"/container/dir/b", "/container/dir/sub/"}},
+		{"/container/", true, []string{"/container/a", "/container/dir/b", "/container/dir/sub/c", "/container/dirt"}},
+		{"/container/dir/", true, []string{"/container/dir/b", "/container/dir/sub/c"}},
+	}
+	for i, testCase := range testCases {
+		client := newTestAzureClient(c, server.URL, testCase.path)
+		var listed []string
+		for content := range client.List(context.Background(), ListOptions{Recursive: testCase.recursive}) {
+			c.Assert(content.Err, IsNil, Commentf("Test %d", i+1))
+			listed = append(listed, content.URL.Path)
+		}
+		c.Assert(listed, DeepEquals, testCase.expected, Commentf("Test %d", i+1))
+	}
+
+	buckets, err := newTestAzureClient(c, server.URL, "/").ListBuckets(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(len(buckets), Equals, 2)
+
+	for content := range newTestAzureClient(c, server.URL, "/missing/").List(context.Background(), ListOptions{}) {
+		c.Assert(content.Err, NotNil)
+		c.Assert(content.Err.ToGoError(), Equals, BucketDoesNotExist{Bucket: "missing"})
+	}
+}
+
+func (s *TestSuite) TestAzureUnsupported(c *C) {
+	client := &azureClient{targetURL: newClientURL("https://account.blob.core.windows.net/container/blob")}
+
+	_, err := client.Stat(context.Background(), StatOptions{versionID: "v1"})
+	c.Assert(err, NotNil)
+	notImplemented, ok := err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+	c.Assert(notImplemented.APIType, Equals, azureAPI)
+
+	for content := range client.List(context.Background(), ListOptions{WithOlderVersions: true}) {
+		_, ok := content.Err.ToGoError().(APINotImplemented)
+		c.Assert(ok, Equals, true)
+	}
+
+	_, err = client.Get(context.Background(), GetOptions{VersionID: "v1"})
+	c.Assert(err, NotNil)
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+
+	_, err = client.GetTags(context.Background(), "")
+	c.Assert(err, NotNil)
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
@@ -176,6 +176,57 @@ func (e ObjectMissing) Error() string {
 	return "Object does not exist"
 }
 
+// ObjectNotModified - object did not change since the given
+// If-None-Match or If-Modified-Since condition.
+type ObjectNotModified struct{}
+
+func (e ObjectNotModified) Error() string {
+	return "Object not modified"
+}
+
+// ObjectSettling - object was modified within --settle-duration, it
+// may still be written.
+type ObjectSettling struct {
+	Object  string
+	ModTime time.Time
+}
+
+func (e ObjectSettling) Error() string {
+	return "Object `" + e.Object + "` was modified at " + e.ModTime.Format(time.RFC3339) + ", it may still be written"
+}
+
+// ObjectConflict - object was changed on both sides of a 'mirror --two-way'
+// since they were last synchronized.
+type ObjectConflict struct {
+	Source string
+	Target string
+}
+
+func (e ObjectConflict) Error() string {
+	return "Object `" + e.Source + "` and `" + e.Target + "` were both changed since the last synchronization"
+}
+
+// ObjectUpToDate - target of a copy is already a copy of its source.
+type ObjectUpToDate struct {
+	Object string
+}
+
+func (e ObjectUpToDate) Error() string {
+	return "Object `" + e.Object + "` is up to date"
+}
+
+// ObjectChecksumMismatch - copied object does not read back as its source.
+type ObjectChecksumMismatch struct {
+	Object    string
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e ObjectChecksumMismatch) Error() string {
+	return "Object `" + e.Object + "` failed verification, " + e.Algorithm + " checksum is `" + e.Actual + "`, expected `" + e.Expected + "`"
+}
+
 // ObjectIsDeleteMarker - object is a delete marker as latest
 type ObjectIsDeleteMarker struct{}
 
@@ -221,3 +272,23 @@ type SameFile struct {
 func (e SameFile) Error() string {
 	return fmt.Sprintf("'%s' and '%s' are the same file", e.Source, e.Destination)
 }
+
+// ServerSideCopyUnavailable - object cannot be copied by the server as
+// required by --server-side.
+type ServerSideCopyUnavailable struct {
+	Source, Target string
+}
+
+func (e ServerSideCopyUnavailable) Error() string {
+	return "`" + e.Source + "` cannot be copied to `" + e.Target + "` by the server"
+}
+
+// ObjectLockNotEnabled - target bucket cannot keep the object lock of
+// the source objects.
+type ObjectLockNotEnabled struct {
+	Bucket string
+}
+
+func (e ObjectLockNotEnabled) Error() string {
+	return "Object lock is not enabled on bucket `" + e.Bucket + "`, it cannot keep the retention or legal hold of the source"
+}
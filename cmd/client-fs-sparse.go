@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+)
+
+// sparseBlockSize is the granularity at which zero runs are detected,
+// it matches the block size of most local filesystems.
+const sparseBlockSize = 4096
+
+var sparseZeroBlock = make([]byte, sparseBlockSize)
+
+// sparseWriter writes to a freshly created file, skipping over blocks
+// which only hold zeros so that the filesystem leaves holes in their
+// place instead of allocating them.
+type sparseWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func newSparseWriter(file *os.File) *sparseWriter {
+	return &sparseWriter{file: file}
+}
+
+// Write implements io.Writer, blocks are aligned to the file offset
+// so that a zero run split across two writes is still detected.
+func (s *sparseWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := sparseBlockSize - int(s.offset%sparseBlockSize)
+		if n > len(p) {
+			n = len(p)
+		}
+		if !bytes.Equal(p[:n], sparseZeroBlock[:n]) {
+			if _, e := s.file.WriteAt(p[:n], s.offset); e != nil {
+				return written, e
+			}
+		}
+		s.offset += int64(n)
+		written += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Finish sets the file size to the number of bytes written, this
+// is needed when the content ends with a run of zeros.
+func (s *sparseWriter) Finish() error {
+	fi, e := s.file.Stat()
+	if e != nil {
+		return e
+	}
+	if fi.Size() < s.offset {
+		return s.file.Truncate(s.offset)
+	}
+	return nil
+}
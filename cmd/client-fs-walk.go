@@ -0,0 +1,173 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/env"
+)
+
+// Environment variable with the number of folders of a local tree read
+// in parallel by recursive copies, listings are serial by default.
+const envFSWalkWorkers = "MC_FS_WALK_WORKERS"
+
+// fsWalkWorkers returns the number of parallel walkers set by
+// MC_FS_WALK_WORKERS, 0 if unset or invalid.
+func fsWalkWorkers() int {
+	workers, e := strconv.Atoi(env.Get(envFSWalkWorkers, "0"))
+	if e != nil || workers < 0 {
+		return 0
+	}
+	return workers
+}
+
+// fsWalkQueue holds the folders left to read by a parallel walk, or the
+// prefixes left to list by a parallel S3 listing.
+type fsWalkQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	dirs   []string
+	active int
+}
+
+func newFSWalkQueue(root string) *fsWalkQueue {
+	q := &fsWalkQueue{dirs: []string{root}}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// pop returns the next folder to read, ok is false once every folder is
+// read.
+func (q *fsWalkQueue) pop() (dir string, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.dirs) == 0 && q.active > 0 {
+		q.cond.Wait()
+	}
+	if len(q.dirs) == 0 {
+		return "", false
+	}
+	dir = q.dirs[len(q.dirs)-1]
+	q.dirs = q.dirs[:len(q.dirs)-1]
+	q.active++
+	return dir, true
+}
+
+// push adds the subfolders found in a folder.
+func (q *fsWalkQueue) push(dirs ...string) {
+	q.mu.Lock()
+	q.dirs = append(q.dirs, dirs...)
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// done marks a folder popped from the queue as read.
+func (q *fsWalkQueue) done() {
+	q.mu.Lock()
+	q.active--
+	q.mu.Unlock()
+	q.cond.Broadcast()
+}
+
+// listRecursiveParallel lists the files of a folder tree like
+// listRecursiveInRoutine, reading folders with several workers. Files
+// are sent in no particular order. With direntOnly the files are not
+// stat'ed: their size is -1 and their modification time is unset, for
+// callers which stat files later anyway.
+func (f *fsClient) listRecursiveParallel(contentCh chan *ClientContent, workers int, direntOnly bool) {
+	defer close(contentCh)
+
+	root := f.PathURL.Path
+	if runtime.GOOS == "windows" {
+		root = filepath.FromSlash(root)
+	}
+	ignoreRules := newMCIgnoreRules()
+	ignoreRules.load(root)
+
+	queue := newFSWalkQueue(filepath.Clean(root))
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				dir, ok := queue.pop()
+				if !ok {
+					return
+				}
+				queue.push(f.listDirParallel(dir, ignoreRules, contentCh, direntOnly)...)
+				queue.done()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// listDirParallel sends the files of a folder and returns its subfolders.
+func (f *fsClient) listDirParallel(dir string, ignoreRules *mcIgnoreRules, contentCh chan<- *ClientContent, direntOnly bool) (subdirs []string) {
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		if os.IsPermission(e) {
+			contentCh <- &ClientContent{Err: probe.NewError(PathInsufficientPermission{Path: dir})}
+		} else {
+			contentCh <- &ClientContent{Err: probe.NewError(e)}
+		}
+		return nil
+	}
+	for _, entry := range entries {
+		// Ignore files from ignore list.
+		if isIgnoredFile(entry.Name()) {
+			continue
+		}
+		fp := filepath.Join(dir, entry.Name())
+		// Skip the entries excluded by .mcignore files.
+		if ignoreRules.isIgnored(fp, entry.IsDir()) {
+			continue
+		}
+		switch {
+		case entry.IsDir():
+			ignoreRules.load(fp)
+			subdirs = append(subdirs, fp)
+		case entry.Type()&os.ModeSymlink == os.ModeSymlink:
+			fi, e := os.Stat(fp)
+			if e != nil || !fi.Mode().IsRegular() {
+				// Ignore any errors for symlink
+				continue
+			}
+			contentCh <- &ClientContent{URL: *newClientURL(fp), Time: fi.ModTime(), Size: fi.Size(), Type: fi.Mode()}
+		case entry.Type().IsRegular():
+			if direntOnly {
+				contentCh <- &ClientContent{URL: *newClientURL(fp), Size: -1, Type: entry.Type()}
+				continue
+			}
+			fi, e := entry.Info()
+			if e != nil {
+				// The file was removed since the folder was read.
+				continue
+			}
+			contentCh <- &ClientContent{URL: *newClientURL(fp), Time: fi.ModTime(), Size: fi.Size(), Type: fi.Mode()}
+		}
+	}
+	return subdirs
+}
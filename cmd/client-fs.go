@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -57,6 +58,11 @@ const (
 	slashSeperator   = "/"
 	metadataKey      = "X-Amz-Meta-Mc-Attrs"
 	metadataKeyS3Cmd = "X-Amz-Meta-S3cmd-Attrs"
+
+	// Extended attributes and symlink targets, query escaped,
+	// preserved with --attr-preserve.
+	metadataXattrsKey  = "X-Amz-Meta-Mc-Xattrs"
+	metadataSymlinkKey = "X-Amz-Meta-Mc-Symlink"
 )
 
 // GOOS specific ignore list.
@@ -276,6 +282,61 @@ func preserveAttributes(fd *os.File, attr map[string]string) *probe.Error {
 	return nil
 }
 
+// getPosixAttributes returns the extended attributes and the symlink
+// target of a file as metadata, both are query escaped.
+func getPosixAttributes(fpath string) map[string]string {
+	metadata := make(map[string]string)
+	if names, e := xattr.List(fpath); e == nil {
+		xattrs := url.Values{}
+		for _, name := range names {
+			// filter out system specific xattr
+			if strings.HasPrefix(name, "system.") {
+				continue
+			}
+			if value, e := xattr.Get(fpath, name); e == nil {
+				xattrs.Set(name, string(value))
+			}
+		}
+		if len(xattrs) > 0 {
+			metadata[metadataXattrsKey] = xattrs.Encode()
+		}
+	}
+	if target, e := os.Readlink(fpath); e == nil {
+		metadata[metadataSymlinkKey] = url.PathEscape(target)
+	}
+	return metadata
+}
+
+// restorePosixAttributes replaces a file by the symlink it was copied
+// from, or sets its extended attributes.
+func restorePosixAttributes(fpath string, metadata map[string]string) *probe.Error {
+	if target, ok := metadata[metadataSymlinkKey]; ok {
+		target, e := url.PathUnescape(target)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		if e = os.Remove(fpath); e != nil {
+			return probe.NewError(e)
+		}
+		if e = os.Symlink(target, fpath); e != nil {
+			return probe.NewError(e)
+		}
+		return nil
+	}
+	if encoded, ok := metadata[metadataXattrsKey]; ok {
+		xattrs, e := url.ParseQuery(encoded)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		for name := range xattrs {
+			if e = xattr.Set(fpath, name, []byte(xattrs.Get(name))); e != nil && !isNotSupported(e) {
+				return probe.NewError(e)
+			}
+		}
+	}
+	return nil
+}
+
 /// Object operations.
 
 func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
@@ -326,12 +387,25 @@ func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress
 		}
 	}
 
-	totalWritten, e := io.Copy(tmpFile, hookreader.NewHook(reader, progress))
+	var writer io.Writer = tmpFile
+	if opts.sparse {
+		writer = newSparseWriter(tmpFile)
+	}
+
+	totalWritten, e := io.Copy(writer, hookreader.NewHook(reader, progress))
 	if e != nil {
 		tmpFile.Close()
 		return 0, probe.NewError(e)
 	}
 
+	// Trailing zeros were skipped, extend the file to its full length.
+	if sw, ok := writer.(*sparseWriter); ok {
+		if e = sw.Finish(); e != nil {
+			tmpFile.Close()
+			return 0, probe.NewError(e)
+		}
+	}
+
 	// Close the input reader as well, if possible.
 	closer, ok := reader.(io.Closer)
 	if ok {
@@ -384,6 +458,12 @@ func (f *fsClient) put(_ context.Context, reader io.Reader, size int64, progress
 		}
 	}
 
+	if opts.attrPreserve {
+		if err := restorePosixAttributes(objectPath, opts.metadata); err != nil {
+			return totalWritten, err.Trace(objectPath)
+		}
+	}
+
 	return totalWritten, nil
 }
 
@@ -440,12 +520,25 @@ func (f *fsClient) putN(_ context.Context, reader io.Reader, size int64, progres
 		}
 	}
 
-	totalWritten, e := io.CopyN(tmpFile, hookreader.NewHook(reader, progress), size)
+	var writer io.Writer = tmpFile
+	if opts.sparse {
+		writer = newSparseWriter(tmpFile)
+	}
+
+	totalWritten, e := io.CopyN(writer, hookreader.NewHook(reader, progress), size)
 	if e != nil {
 		tmpFile.Close()
 		return 0, probe.NewError(e)
 	}
 
+	// Trailing zeros were skipped, extend the file to its full length.
+	if sw, ok := writer.(*sparseWriter); ok {
+		if e = sw.Finish(); e != nil {
+			tmpFile.Close()
+			return 0, probe.NewError(e)
+		}
+	}
+
 	// Close the input reader as well, if possible.
 	closer, ok := reader.(io.Closer)
 	if ok {
@@ -491,6 +584,12 @@ func (f *fsClient) putN(_ context.Context, reader io.Reader, size int64, progres
 		}
 	}
 
+	if opts.attrPreserve {
+		if err := restorePosixAttributes(objectPath, opts.metadata); err != nil {
+			return totalWritten, err.Trace(objectPath)
+		}
+	}
+
 	return totalWritten, nil
 }
 
@@ -530,6 +629,7 @@ func (f *fsClient) Copy(ctx context.Context, source string, opts CopyOptions, pr
 	putOpts := PutOptions{
 		metadata:   opts.metadata,
 		isPreserve: opts.isPreserve,
+		sparse:     opts.sparse,
 	}
 
 	destination := f.PathURL.Path
@@ -546,6 +646,12 @@ func (f *fsClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *prob
 		err := f.toClientError(e, f.PathURL.Path)
 		return nil, err.Trace(f.PathURL.Path)
 	}
+	if opts.Conditions.IsSet() {
+		if err := f.checkGetConditions(fileData, opts.Conditions); err != nil {
+			fileData.Close()
+			return nil, err.Trace(f.PathURL.Path)
+		}
+	}
 	if opts.RangeStart != 0 {
 		_, e := fileData.Seek(opts.RangeStart, io.SeekStart)
 		if e != nil {
@@ -553,10 +659,35 @@ func (f *fsClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *prob
 			return nil, err.Trace(f.PathURL.Path)
 		}
 	}
+	if opts.RangeLength > 0 {
+		return limitReadCloser(fileData, opts.RangeLength), nil
+	}
 
 	return fileData, nil
 }
 
+// checkGetConditions evaluates the preconditions of a conditional GET
+// against the modification time, files have no ETag.
+func (f *fsClient) checkGetConditions(fileData *os.File, conditions GetConditions) *probe.Error {
+	if conditions.IfMatch != "" || conditions.IfNoneMatch != "" {
+		return probe.NewError(APINotImplemented{
+			API:     "GetObject with ETag conditions",
+			APIType: "filesystem",
+		})
+	}
+	st, e := fileData.Stat()
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if !conditions.IfUnmodifiedSince.IsZero() && st.ModTime().After(conditions.IfUnmodifiedSince) {
+		return probe.NewError(errors.New("at least one of the pre-conditions you specified did not hold"))
+	}
+	if !conditions.IfModifiedSince.IsZero() && !st.ModTime().After(conditions.IfModifiedSince) {
+		return probe.NewError(ObjectNotModified{})
+	}
+	return nil
+}
+
 // Check if the given error corresponds to ENOTEMPTY for unix
 // and ERROR_DIR_NOT_EMPTY for windows (directory not empty).
 func isSysErrNotEmpty(err error) bool {
@@ -642,17 +773,17 @@ func (f *fsClient) Remove(_ context.Context, isIncomplete, _, _, _ bool, content
 				// ignore if path already removed.
 				continue
 			}
+			res := RemoveResult{}
+			res.ObjectName = content.URL.Path
 			if os.IsPermission(e) {
 				// Ignore permission error.
-				resultCh <- RemoveResult{
-					Err: probe.NewError(PathInsufficientPermission{
-						Path: content.URL.Path,
-					}),
-				}
+				res.Err = probe.NewError(PathInsufficientPermission{
+					Path: content.URL.Path,
+				})
+				resultCh <- res
 			} else {
-				resultCh <- RemoveResult{
-					Err: probe.NewError(e),
-				}
+				res.Err = probe.NewError(e)
+				resultCh <- res
 				return
 			}
 		}
@@ -733,7 +864,9 @@ func (f *fsClient) List(_ context.Context, opts ListOptions) <-chan *ClientConte
 	}
 
 	if opts.Recursive {
-		if opts.ShowDir == DirNone {
+		if opts.ShowDir == DirNone && opts.WalkWorkers > 1 && strings.HasSuffix(f.PathURL.Path, string(f.PathURL.Separator)) {
+			go f.listRecursiveParallel(contentCh, opts.WalkWorkers, opts.DirentOnly)
+		} else if opts.ShowDir == DirNone {
 			go f.listRecursiveInRoutine(contentCh)
 		} else {
 			go f.listDirOpt(contentCh, opts.Incomplete, opts.WithMetadata, opts.ShowDir)
@@ -1013,6 +1146,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent) {
 		pathURL.Path = filepath.FromSlash(pathURL.Path)
 		pathURL.Separator = os.PathSeparator
 	}
+	ignoreRules := newMCIgnoreRules()
 	visitFS := func(fp string, fi os.FileInfo, e error) error {
 		// If file path ends with filepath.Separator and equals to root path, skip it.
 		if strings.HasSuffix(fp, string(pathURL.Separator)) {
@@ -1068,6 +1202,16 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent) {
 			}
 			return e
 		}
+		// Skip the entries excluded by .mcignore files.
+		if ignoreRules.isIgnored(fp, fi.IsDir()) {
+			if fi.IsDir() {
+				return xfilepath.ErrSkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() {
+			ignoreRules.load(fp)
+		}
 		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
 			fi, e = os.Stat(fp)
 			if e != nil {
@@ -1102,6 +1246,7 @@ func (f *fsClient) listRecursiveInRoutine(contentCh chan *ClientContent) {
 		// filePrefix is kept for filtering incoming contents through WalkFunc.
 		filePrefix = pathURL.Path
 	}
+	ignoreRules.load(dirName)
 	// walks invokes our custom function.
 	e := xfilepath.Walk(dirName, visitFS)
 	if e != nil {
@@ -1279,6 +1424,11 @@ func (f *fsClient) Stat(_ context.Context, opts StatOptions) (content *ClientCon
 			content.Metadata[k] = v
 		}
 		content.Metadata[metadataKey] = fileAttr
+		if opts.attrPreserve {
+			for k, v := range getPosixAttributes(path) {
+				content.Metadata[k] = v
+			}
+		}
 	}
 
 	return content, nil
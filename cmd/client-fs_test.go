@@ -24,6 +24,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"time"
 
 	. "gopkg.in/check.v1"
 )
@@ -194,6 +196,75 @@ func (s *TestSuite) TestList(c *C) {
 	}
 }
 
+// Test recursive listing skips the entries excluded by .mcignore files.
+func (s *TestSuite) TestListMCIgnore(c *C) {
+	root, e := os.MkdirTemp(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		".mcignore":          "# build output\nbuild/\n*.log\n!keep.log\n",
+		"a.txt":              "",
+		"x.log":              "",
+		"keep.log":           "",
+		"build/out.bin":      "",
+		"sub/.mcignore":      "/tmp-*\n",
+		"sub/b.txt":          "",
+		"sub/tmp-1":          "",
+		"sub/deep/tmp-2":     "",
+		"sub/deep/debug.log": "",
+	}
+	for name, data := range files {
+		fp := filepath.Join(root, filepath.FromSlash(name))
+		c.Assert(os.MkdirAll(filepath.Dir(fp), 0o755), IsNil)
+		c.Assert(os.WriteFile(fp, []byte(data), 0o644), IsNil)
+	}
+
+	fsClient, err := fsNew(root + string(filepath.Separator))
+	c.Assert(err, IsNil)
+
+	var names []string
+	for content := range fsClient.List(globalContext, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		c.Assert(content.Err, IsNil)
+		rel, e := filepath.Rel(root, content.URL.Path)
+		c.Assert(e, IsNil)
+		names = append(names, filepath.ToSlash(rel))
+	}
+	c.Assert(names, DeepEquals, []string{".mcignore", "a.txt", "keep.log", "sub/.mcignore", "sub/b.txt", "sub/deep/tmp-2"})
+}
+
+// Test parallel recursive listing returns the files of serial listing.
+func (s *TestSuite) TestListParallel(c *C) {
+	root, e := os.MkdirTemp(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	for _, name := range []string{"a", "b/c", "b/d/e", "b/d/f", "g/h/i/j", ".mcignore", "tmp/k"} {
+		fp := filepath.Join(root, filepath.FromSlash(name))
+		c.Assert(os.MkdirAll(filepath.Dir(fp), 0o755), IsNil)
+		c.Assert(os.WriteFile(fp, []byte("tmp/\n"), 0o644), IsNil)
+	}
+
+	fsClient, err := fsNew(root + string(filepath.Separator))
+	c.Assert(err, IsNil)
+
+	list := func(opts ListOptions) (names []string) {
+		for content := range fsClient.List(globalContext, opts) {
+			c.Assert(content.Err, IsNil)
+			if opts.DirentOnly {
+				c.Assert(content.Size, Equals, int64(-1))
+			}
+			names = append(names, content.URL.Path)
+		}
+		sort.Strings(names)
+		return names
+	}
+	serial := list(ListOptions{Recursive: true, ShowDir: DirNone})
+	c.Assert(serial, HasLen, 6)
+	c.Assert(list(ListOptions{Recursive: true, ShowDir: DirNone, WalkWorkers: 4}), DeepEquals, serial)
+	c.Assert(list(ListOptions{Recursive: true, ShowDir: DirNone, WalkWorkers: 4, DirentOnly: true}), DeepEquals, serial)
+}
+
 // Test put bucket aka 'mkdir()' operation.
 func (s *TestSuite) TestPutBucket(c *C) {
 	root, e := os.MkdirTemp(os.TempDir(), "fs-")
@@ -269,6 +340,59 @@ func (s *TestSuite) TestPut(c *C) {
 	c.Assert(n, Equals, int64(len(data)))
 }
 
+// Test writing a file with runs of zeros as a sparse file.
+func (s *TestSuite) TestPutSparse(c *C) {
+	root, e := os.MkdirTemp(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	var data []byte
+	data = append(data, "hello"...)
+	data = append(data, make([]byte, 3*sparseBlockSize)...)
+	data = append(data, "world"...)
+	data = append(data, make([]byte, 10000)...)
+
+	n, err := fsClient.Put(context.Background(), bytes.NewReader(data), int64(len(data)), nil, PutOptions{sparse: true})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+
+	results, e := os.ReadFile(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(results, DeepEquals, data)
+}
+
+// Test preserving a symlink through the metadata of a copy.
+func (s *TestSuite) TestPutAttrPreserveSymlink(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("symlinks are not preserved on windows")
+	}
+	root, e := os.MkdirTemp(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	linkPath := filepath.Join(root, "link")
+	c.Assert(os.Symlink("../target dir/file", linkPath), IsNil)
+	metadata := getPosixAttributes(linkPath)
+	c.Assert(metadata[metadataSymlinkKey], Equals, "..%2Ftarget%20dir%2Ffile")
+
+	objectPath := filepath.Join(root, "object")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+	_, err = fsClient.Put(context.Background(), bytes.NewReader(nil), 0, nil, PutOptions{
+		metadata:     metadata,
+		attrPreserve: true,
+	})
+	c.Assert(err, IsNil)
+
+	target, e := os.Readlink(objectPath)
+	c.Assert(e, IsNil)
+	c.Assert(target, Equals, "../target dir/file")
+}
+
 // Test read a file.
 func (s *TestSuite) TestGet(c *C) {
 	root, e := os.MkdirTemp(os.TempDir(), "fs-")
@@ -298,6 +422,42 @@ func (s *TestSuite) TestGet(c *C) {
 	c.Assert([]byte(data), DeepEquals, results.Bytes())
 }
 
+// Test conditional read of a file.
+func (s *TestSuite) TestGetConditions(c *C) {
+	root, e := os.MkdirTemp(os.TempDir(), "fs-")
+	c.Assert(e, IsNil)
+	defer os.RemoveAll(root)
+
+	objectPath := filepath.Join(root, "object")
+	fsClient, err := fsNew(objectPath)
+	c.Assert(err, IsNil)
+
+	data := "hello"
+	_, err = fsClient.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), nil, PutOptions{})
+	c.Assert(err, IsNil)
+
+	modTime := time.Now().Add(-time.Hour)
+	c.Assert(os.Chtimes(objectPath, modTime, modTime), IsNil)
+
+	reader, err := fsClient.Get(context.Background(), GetOptions{
+		Conditions: GetConditions{IfModifiedSince: modTime.Add(-time.Minute)},
+	})
+	c.Assert(err, IsNil)
+	reader.Close()
+
+	_, err = fsClient.Get(context.Background(), GetOptions{
+		Conditions: GetConditions{IfModifiedSince: modTime.Add(time.Minute)},
+	})
+	c.Assert(err, NotNil)
+	c.Assert(isErrNotModified(err), Equals, true)
+
+	_, err = fsClient.Get(context.Background(), GetOptions{
+		Conditions: GetConditions{IfUnmodifiedSince: modTime.Add(-time.Minute)},
+	})
+	c.Assert(err, NotNil)
+	c.Assert(isErrNotModified(err), Equals, false)
+}
+
 // Test get range in a file.
 func (s *TestSuite) TestGetRange(c *C) {
 	root, e := os.MkdirTemp(os.TempDir(), "fs-")
@@ -0,0 +1,865 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// gcsAPI is the API of aliases served by the JSON API of Google Cloud Storage.
+const gcsAPI = "gcs"
+
+// GCS client, the project ID is the access key of the alias and the
+// service account key file its credentials file.
+type gcsClient struct {
+	targetURL *ClientURL
+	projectID string
+	api       *storage.Client
+}
+
+// isGCSAlias returns true if the alias uses the JSON API of Google Cloud Storage.
+func isGCSAlias(hostCfg *aliasConfigV10) bool {
+	return strings.EqualFold(hostCfg.API, gcsAPI)
+}
+
+// newGCSFactory encloses gcsNew with a cache of the clients of each
+// service account.
+func newGCSFactory() func(urlStr string, hostCfg *aliasConfigV10) (Client, *probe.Error) {
+	clientCache := make(map[uint32]*storage.Client)
+	var mutex sync.Mutex
+
+	return func(urlStr string, hostCfg *aliasConfigV10) (Client, *probe.Error) {
+		targetURL := newClientURL(urlStr)
+
+		confHash := fnv.New32a()
+		confHash.Write([]byte(targetURL.Host + hostCfg.CredentialsFile))
+		confSum := confHash.Sum32()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		api, found := clientCache[confSum]
+		if !found {
+			var opts []option.ClientOption
+			// Without a key file the application default credentials are used.
+			if hostCfg.CredentialsFile != "" {
+				opts = append(opts, option.WithCredentialsFile(hostCfg.CredentialsFile))
+			}
+			var e error
+			if api, e = storage.NewClient(globalContext, opts...); e != nil {
+				return nil, probe.NewError(e).Trace(urlStr)
+			}
+			clientCache[confSum] = api
+		}
+		return &gcsClient{targetURL: targetURL, projectID: hostCfg.AccessKey, api: api}, nil
+	}
+}
+
+// gcsNew returns an initialized gcsClient structure.
+var gcsNew = newGCSFactory()
+
+// GetURL get url.
+func (c *gcsClient) GetURL() ClientURL {
+	return c.targetURL.Clone()
+}
+
+func (c *gcsClient) url2BucketAndObject() (bucketName, objectName string) {
+	return url2BucketAndObject(c.targetURL)
+}
+
+// toClientError constructs a typed client error for known GCS errors.
+func (c *gcsClient) toClientError(e error, bucket string) *probe.Error {
+	var apiErr *googleapi.Error
+	switch {
+	case errors.Is(e, storage.ErrBucketNotExist):
+		return probe.NewError(BucketDoesNotExist{Bucket: bucket})
+	case errors.Is(e, storage.ErrObjectNotExist):
+		return probe.NewError(ObjectMissing{})
+	case errors.As(e, &apiErr) && apiErr.Code == http.StatusConflict:
+		return probe.NewError(BucketExists{Bucket: bucket})
+	case errors.As(e, &apiErr) && apiErr.Code == http.StatusNotModified:
+		return probe.NewError(ObjectNotModified{})
+	}
+	return probe.NewError(e)
+}
+
+func (c *gcsClient) content(bucket, key string) *ClientContent {
+	url := c.targetURL.Clone()
+	url.Path = string(c.targetURL.Separator) + bucket
+	if key != "" {
+		url.Path += string(c.targetURL.Separator) + key
+	}
+	return &ClientContent{URL: url, BucketName: bucket}
+}
+
+func (c *gcsClient) bucketContent(attrs *storage.BucketAttrs) *ClientContent {
+	content := c.content(attrs.Name, "")
+	content.Type = os.ModeDir
+	content.Time = attrs.Created
+	return content
+}
+
+func (c *gcsClient) prefixContent(bucket, prefix string) *ClientContent {
+	content := c.content(bucket, prefix)
+	content.Type = os.ModeDir
+	content.Time = time.Now()
+	return content
+}
+
+func (c *gcsClient) objectContent(attrs *storage.ObjectAttrs) *ClientContent {
+	if attrs.Prefix != "" {
+		return c.prefixContent(attrs.Bucket, attrs.Prefix)
+	}
+	content := c.content(attrs.Bucket, attrs.Name)
+	content.Type = os.FileMode(0o664)
+	content.Size = attrs.Size
+	content.ETag = attrs.Etag
+	content.Time = attrs.Updated
+	content.StorageClass = attrs.StorageClass
+	content.Metadata = map[string]string{
+		"Content-Type": attrs.ContentType,
+	}
+	for k, v := range map[string]string{
+		"Cache-Control":       attrs.CacheControl,
+		"Content-Encoding":    attrs.ContentEncoding,
+		"Content-Disposition": attrs.ContentDisposition,
+		"Content-Language":    attrs.ContentLanguage,
+	} {
+		if v != "" {
+			content.Metadata[k] = v
+		}
+	}
+	content.UserMetadata = make(map[string]string, len(attrs.Metadata))
+	for k, v := range attrs.Metadata {
+		content.UserMetadata[http.CanonicalHeaderKey("X-Amz-Meta-"+k)] = v
+	}
+	if strings.HasSuffix(attrs.Name, string(c.targetURL.Separator)) {
+		content.Type = os.ModeDir
+	}
+	return content
+}
+
+// Stat - get metadata of a bucket, object or prefix.
+func (c *gcsClient) Stat(ctx context.Context, opts StatOptions) (*ClientContent, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if opts.incomplete || opts.versionID != "" || !opts.timeRef.IsZero() {
+		return nil, probe.NewError(APINotImplemented{
+			API:     "Stat of incomplete uploads and versions",
+			APIType: gcsAPI,
+		})
+	}
+	if bucket == "" {
+		url := c.targetURL.Clone()
+		url.Path = string(c.targetURL.Separator)
+		return &ClientContent{URL: url, Type: os.ModeDir}, nil
+	}
+	if object == "" {
+		attrs, e := c.api.Bucket(bucket).Attrs(ctx)
+		if e != nil {
+			return nil, c.toClientError(e, bucket).Trace(bucket)
+		}
+		return c.bucketContent(attrs), nil
+	}
+
+	if !strings.HasSuffix(object, string(c.targetURL.Separator)) {
+		attrs, e := c.api.Bucket(bucket).Object(object).Attrs(ctx)
+		if e == nil {
+			return c.objectContent(attrs), nil
+		}
+		if !errors.Is(e, storage.ErrObjectNotExist) {
+			return nil, c.toClientError(e, bucket).Trace(c.targetURL.String())
+		}
+		// The object is not found, look for a prefix.
+		object += string(c.targetURL.Separator)
+	}
+
+	it := c.api.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: object})
+	if _, e := it.Next(); e != nil {
+		if e == iterator.Done {
+			return nil, probe.NewError(ObjectMissing{})
+		}
+		return nil, c.toClientError(e, bucket).Trace(c.targetURL.String())
+	}
+	return c.prefixContent(bucket, object), nil
+}
+
+// List - list at delimited path, if not recursive.
+func (c *gcsClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		if opts.Incomplete || opts.WithOlderVersions || !opts.TimeRef.IsZero() || opts.ListZip {
+			contentCh <- &ClientContent{
+				Err: probe.NewError(APINotImplemented{
+					API:     "List of incomplete uploads, versions and zip files",
+					APIType: gcsAPI,
+				}),
+			}
+			return
+		}
+		if opts.Recursive {
+			c.listRecursiveInRoutine(ctx, contentCh, opts)
+		} else {
+			c.listInRoutine(ctx, contentCh)
+		}
+	}()
+	return contentCh
+}
+
+// listBuckets returns the buckets of the project, sorted with a
+// trailing '/' as S3 buckets.
+func (c *gcsClient) listBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	if c.projectID == "" {
+		return nil, probe.NewError(errors.New("listing buckets requires the project ID as access key of the alias"))
+	}
+	var buckets []*ClientContent
+	it := c.api.Buckets(ctx, c.projectID)
+	for {
+		attrs, e := it.Next()
+		if e == iterator.Done {
+			break
+		}
+		if e != nil {
+			return nil, c.toClientError(e, "")
+		}
+		buckets = append(buckets, c.bucketContent(attrs))
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].BucketName+"/" < buckets[j].BucketName+"/"
+	})
+	return buckets, nil
+}
+
+func (c *gcsClient) listInRoutine(ctx context.Context, contentCh chan *ClientContent) {
+	b, o := c.url2BucketAndObject()
+	switch {
+	case b == "" && o == "":
+		buckets, err := c.listBuckets(ctx)
+		if err != nil {
+			contentCh <- &ClientContent{Err: err}
+			return
+		}
+		for _, content := range buckets {
+			contentCh <- content
+		}
+	case b != "" && !strings.HasSuffix(c.targetURL.Path, string(c.targetURL.Separator)) && o == "":
+		content, err := c.Stat(ctx, StatOptions{})
+		if err != nil {
+			contentCh <- &ClientContent{Err: err.Trace(b)}
+			return
+		}
+		contentCh <- content
+	default:
+		it := c.api.Bucket(b).Objects(ctx, &storage.Query{
+			Prefix:    o,
+			Delimiter: string(c.targetURL.Separator),
+		})
+		pager := iterator.NewPager(it, 1000, "")
+		for {
+			var page []*storage.ObjectAttrs
+			token, e := pager.NextPage(&page)
+			if e != nil {
+				contentCh <- &ClientContent{Err: c.toClientError(e, b)}
+				return
+			}
+			// Objects and prefixes are returned apart, merge them in lexical order.
+			sort.Slice(page, func(i, j int) bool {
+				return page[i].Name+page[i].Prefix < page[j].Name+page[j].Prefix
+			})
+			for _, attrs := range page {
+				contentCh <- c.objectContent(attrs)
+			}
+			if token == "" {
+				return
+			}
+		}
+	}
+}
+
+func (c *gcsClient) listRecursiveInRoutine(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
+	b, o := c.url2BucketAndObject()
+	listObjects := func(bucket string) bool {
+		it := c.api.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: o})
+		for {
+			attrs, e := it.Next()
+			if e == iterator.Done {
+				return true
+			}
+			if e != nil {
+				contentCh <- &ClientContent{Err: c.toClientError(e, bucket)}
+				return false
+			}
+			contentCh <- c.objectContent(attrs)
+		}
+	}
+
+	if b != "" {
+		listObjects(b)
+		return
+	}
+	buckets, err := c.listBuckets(ctx)
+	if err != nil {
+		contentCh <- &ClientContent{Err: err}
+		return
+	}
+	for _, content := range buckets {
+		if opts.ShowDir == DirFirst {
+			contentCh <- content
+		}
+		if !listObjects(content.BucketName) {
+			return
+		}
+		if opts.ShowDir == DirLast {
+			contentCh <- content
+		}
+	}
+}
+
+// ListBuckets - list the buckets of the project.
+func (c *gcsClient) ListBuckets(ctx context.Context) ([]*ClientContent, *probe.Error) {
+	return c.listBuckets(ctx)
+}
+
+// Get - get a reader of an object.
+func (c *gcsClient) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if opts.VersionID != "" || opts.Zip || opts.LambdaArn != "" || opts.SSE != nil || opts.Conditions.IsSet() {
+		return nil, probe.NewError(APINotImplemented{
+			API:     "Get of versions, zip files, lambdas, encrypted objects and with conditions",
+			APIType: gcsAPI,
+		})
+	}
+	length := int64(-1)
+	if opts.RangeLength > 0 {
+		length = opts.RangeLength
+	}
+	reader, e := c.api.Bucket(bucket).Object(object).NewRangeReader(ctx, opts.RangeStart, length)
+	if e != nil {
+		return nil, c.toClientError(e, bucket).Trace(c.targetURL.String())
+	}
+	return reader, nil
+}
+
+// Put - upload an object, metadata is limited to the standard headers
+// and the user metadata.
+func (c *gcsClient) Put(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return 0, probe.NewError(BucketNameEmpty{})
+	}
+	if opts.sse != nil {
+		return 0, probe.NewError(APINotImplemented{
+			API:     "Put of encrypted objects",
+			APIType: gcsAPI,
+		})
+	}
+
+	// The upload is canceled, and not committed, on errors.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := c.api.Bucket(bucket).Object(object).NewWriter(ctx)
+	w.ContentType = "application/octet-stream"
+	w.StorageClass = opts.storageClass
+	w.Metadata = map[string]string{}
+	for k, v := range opts.metadata {
+		switch http.CanonicalHeaderKey(k) {
+		case "Content-Type":
+			w.ContentType = v
+		case "Cache-Control":
+			w.CacheControl = v
+		case "Content-Encoding":
+			w.ContentEncoding = v
+		case "Content-Disposition":
+			w.ContentDisposition = v
+		case "Content-Language":
+			w.ContentLanguage = v
+		default:
+			if name, ok := cutPrefixFold(k, "X-Amz-Meta-"); ok {
+				w.Metadata[name] = v
+			}
+		}
+	}
+	if opts.multipartSize > 0 {
+		w.ChunkSize = int(opts.multipartSize)
+	}
+	if opts.disableMultipart {
+		w.ChunkSize = 0
+	}
+
+	n, e := io.Copy(w, hookreader.NewHook(reader, progress))
+	if e != nil {
+		return n, probe.NewError(e)
+	}
+	if e = w.Close(); e != nil {
+		return n, c.toClientError(e, bucket).Trace(c.targetURL.String())
+	}
+	if size > 0 && n < size {
+		return n, probe.NewError(UnexpectedEOF{
+			TotalSize:    size,
+			TotalWritten: n,
+		})
+	}
+	return n, nil
+}
+
+// PutPart - upload an object of N bytes.
+func (c *gcsClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	if size < 0 {
+		return c.Put(ctx, reader, size, progress, opts)
+	}
+	return c.Put(ctx, io.LimitReader(reader, size), size, progress, opts)
+}
+
+// Copy - copy an object server side, within or across buckets.
+func (c *gcsClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	dstBucket, dstObject := c.url2BucketAndObject()
+	if dstBucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if opts.srcSSE != nil || opts.tgtSSE != nil || opts.versionID != "" {
+		return probe.NewError(APINotImplemented{
+			API:     "Copy of versions and encrypted objects",
+			APIType: gcsAPI,
+		})
+	}
+	tokens := splitStr(filepath.ToSlash(source), string(c.targetURL.Separator), 3)
+	src := c.api.Bucket(tokens[1]).Object(tokens[2])
+	copier := c.api.Bucket(dstBucket).Object(dstObject).CopierFrom(src)
+	if opts.storageClass != "" {
+		copier.StorageClass = opts.storageClass
+	}
+	if len(opts.metadata) > 0 {
+		copier.Metadata = map[string]string{}
+		for k, v := range opts.metadata {
+			if name, ok := cutPrefixFold(k, "X-Amz-Meta-"); ok {
+				copier.Metadata[name] = v
+			} else if http.CanonicalHeaderKey(k) == "Content-Type" {
+				copier.ContentType = v
+			}
+		}
+	}
+	var copied uint64
+	copier.ProgressFunc = func(copiedBytes, _ uint64) {
+		if progress != nil && copiedBytes > copied {
+			io.CopyN(io.Discard, progress, int64(copiedBytes-copied))
+		}
+		copied = copiedBytes
+	}
+	attrs, e := copier.Run(ctx)
+	if e != nil {
+		return c.toClientError(e, tokens[1]).Trace(source)
+	}
+	// Small objects are copied in a single call, without progress.
+	if progress != nil && uint64(attrs.Size) > copied {
+		io.CopyN(io.Discard, progress, attrs.Size-int64(copied))
+	}
+	return nil
+}
+
+// Remove - remove objects. Buckets are removed once their objects are,
+// when removing buckets.
+func (c *gcsClient) Remove(ctx context.Context, isIncomplete, isRemoveBucket, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		if isIncomplete {
+			resultCh <- RemoveResult{
+				Err: probe.NewError(APINotImplemented{
+					API:     "Remove of incomplete uploads",
+					APIType: gcsAPI,
+				}),
+			}
+			return
+		}
+
+		removeBucket := func(bucket string) {
+			if !isRemoveBucket || bucket == "" {
+				return
+			}
+			if e := c.api.Bucket(bucket).Delete(ctx); e != nil {
+				resultCh <- RemoveResult{Err: c.toClientError(e, bucket)}
+				return
+			}
+			resultCh <- RemoveResult{BucketName: bucket}
+		}
+
+		prevBucket := ""
+		for content := range contentCh {
+			if content.Err != nil {
+				resultCh <- RemoveResult{Err: content.Err}
+				continue
+			}
+			bucket, object := url2BucketAndObject(&content.URL)
+			if bucket != prevBucket {
+				removeBucket(prevBucket)
+				prevBucket = bucket
+			}
+			if object == "" {
+				continue
+			}
+			if e := c.api.Bucket(bucket).Object(object).Delete(ctx); e != nil {
+				if errors.Is(e, storage.ErrObjectNotExist) && content.Type.IsDir() {
+					continue
+				}
+				resultCh <- RemoveResult{Err: c.toClientError(e, bucket).Trace(content.URL.String())}
+				continue
+			}
+			res := RemoveResult{BucketName: bucket}
+			res.ObjectName = object
+			resultCh <- res
+		}
+		removeBucket(prevBucket)
+	}()
+	return resultCh
+}
+
+// MakeBucket - create a bucket in the project, folders are only prefixes.
+func (c *gcsClient) MakeBucket(ctx context.Context, region string, ignoreExisting, withLock bool) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if withLock {
+		return probe.NewError(APINotImplemented{
+			API:     "MakeBucket with object lock",
+			APIType: gcsAPI,
+		})
+	}
+	var attrs *storage.BucketAttrs
+	if region != "" {
+		attrs = &storage.BucketAttrs{Location: region}
+	}
+	if e := c.api.Bucket(bucket).Create(ctx, c.projectID, attrs); e != nil {
+		err := c.toClientError(e, bucket)
+		if ignoreExisting && errors.As(err.ToGoError(), &BucketExists{}) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveBucket - remove a bucket, with its objects if forced.
+func (c *gcsClient) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Error {
+	bucket, object := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if object != "" {
+		return probe.NewError(BucketInvalid{bucket + string(c.targetURL.Separator) + object})
+	}
+	if forceRemove {
+		it := c.api.Bucket(bucket).Objects(ctx, nil)
+		for {
+			attrs, e := it.Next()
+			if e == iterator.Done {
+				break
+			}
+			if e != nil {
+				return c.toClientError(e, bucket)
+			}
+			if e = c.api.Bucket(bucket).Object(attrs.Name).Delete(ctx); e != nil {
+				return c.toClientError(e, bucket)
+			}
+		}
+	}
+	if e := c.api.Bucket(bucket).Delete(ctx); e != nil {
+		return c.toClientError(e, bucket)
+	}
+	return nil
+}
+
+func (c *gcsClient) AddUserAgent(_, _ string) {
+}
+
+// Select - not implemented.
+func (c *gcsClient) Select(_ context.Context, _ string, _ encrypt.ServerSide, _ SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "Select",
+		APIType: gcsAPI,
+	})
+}
+
+// Watch - not implemented.
+func (c *gcsClient) Watch(_ context.Context, _ WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "Watch",
+		APIType: gcsAPI,
+	})
+}
+
+// ShareDownload - not implemented.
+func (c *gcsClient) ShareDownload(_ context.Context, _ string, _ time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "ShareDownload",
+		APIType: gcsAPI,
+	})
+}
+
+// ShareUpload - not implemented.
+func (c *gcsClient) ShareUpload(_ context.Context, _ bool, _ time.Duration, _ string) (string, map[string]string, *probe.Error) {
+	return "", nil, probe.NewError(APINotImplemented{
+		API:     "ShareUpload",
+		APIType: gcsAPI,
+	})
+}
+
+// SetObjectLockConfig - not implemented.
+func (c *gcsClient) SetObjectLockConfig(_ context.Context, _ minio.RetentionMode, _ uint64, _ minio.ValidityUnit) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetObjectLockConfig",
+		APIType: gcsAPI,
+	})
+}
+
+// GetObjectLockConfig - not implemented.
+func (c *gcsClient) GetObjectLockConfig(_ context.Context) (status string, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, err *probe.Error) {
+	return "", "", 0, "", probe.NewError(APINotImplemented{
+		API:     "GetObjectLockConfig",
+		APIType: gcsAPI,
+	})
+}
+
+// GetAccess - not implemented.
+func (c *gcsClient) GetAccess(_ context.Context) (access, policyJSON string, err *probe.Error) {
+	return "", "", probe.NewError(APINotImplemented{
+		API:     "GetAccess",
+		APIType: gcsAPI,
+	})
+}
+
+// GetAccessRules - not implemented.
+func (c *gcsClient) GetAccessRules(_ context.Context) (map[string]string, *probe.Error) {
+	return map[string]string{}, probe.NewError(APINotImplemented{
+		API:     "GetBucketPolicy",
+		APIType: gcsAPI,
+	})
+}
+
+// SetAccess - not implemented.
+func (c *gcsClient) SetAccess(_ context.Context, _ string, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetAccess",
+		APIType: gcsAPI,
+	})
+}
+
+// PutObjectRetention - not implemented.
+func (c *gcsClient) PutObjectRetention(_ context.Context, _ string, _ minio.RetentionMode, _ time.Time, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectRetention",
+		APIType: gcsAPI,
+	})
+}
+
+// GetObjectRetention - not implemented.
+func (c *gcsClient) GetObjectRetention(_ context.Context, _ string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, probe.NewError(APINotImplemented{
+		API:     "GetObjectRetention",
+		APIType: gcsAPI,
+	})
+}
+
+// PutObjectLegalHold - not implemented.
+func (c *gcsClient) PutObjectLegalHold(_ context.Context, _ string, _ minio.LegalHoldStatus) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectLegalHold",
+		APIType: gcsAPI,
+	})
+}
+
+// GetObjectLegalHold - not implemented.
+func (c *gcsClient) GetObjectLegalHold(_ context.Context, _ string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "GetObjectLegalHold",
+		APIType: gcsAPI,
+	})
+}
+
+// GetTags - not implemented.
+func (c *gcsClient) GetTags(_ context.Context, _ string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetObjectTagging",
+		APIType: gcsAPI,
+	})
+}
+
+// SetTags - not implemented.
+func (c *gcsClient) SetTags(_ context.Context, _, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetObjectTagging",
+		APIType: gcsAPI,
+	})
+}
+
+// DeleteTags - not implemented.
+func (c *gcsClient) DeleteTags(_ context.Context, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "DeleteObjectTagging",
+		APIType: gcsAPI,
+	})
+}
+
+// GetLifecycle - not implemented.
+func (c *gcsClient) GetLifecycle(_ context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	return nil, time.Time{}, probe.NewError(APINotImplemented{
+		API:     "GetLifecycle",
+		APIType: gcsAPI,
+	})
+}
+
+// SetLifecycle - not implemented.
+func (c *gcsClient) SetLifecycle(_ context.Context, _ *lifecycle.Configuration) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetLifecycle",
+		APIType: gcsAPI,
+	})
+}
+
+// GetVersion - not implemented.
+func (c *gcsClient) GetVersion(_ context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, probe.NewError(APINotImplemented{
+		API:     "GetVersion",
+		APIType: gcsAPI,
+	})
+}
+
+// SetVersion - not implemented.
+func (c *gcsClient) SetVersion(_ context.Context, _ string, _ []string, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetVersion",
+		APIType: gcsAPI,
+	})
+}
+
+// GetReplication - not implemented.
+func (c *gcsClient) GetReplication(_ context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, probe.NewError(APINotImplemented{
+		API:     "GetReplication",
+		APIType: gcsAPI,
+	})
+}
+
+// SetReplication - not implemented.
+func (c *gcsClient) SetReplication(_ context.Context, _ *replication.Config, _ replication.Options) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetReplication",
+		APIType: gcsAPI,
+	})
+}
+
+// RemoveReplication - not implemented.
+func (c *gcsClient) RemoveReplication(_ context.Context) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "RemoveReplication",
+		APIType: gcsAPI,
+	})
+}
+
+// GetReplicationMetrics - not implemented.
+func (c *gcsClient) GetReplicationMetrics(_ context.Context) (replication.MetricsV2, *probe.Error) {
+	return replication.MetricsV2{}, probe.NewError(APINotImplemented{
+		API:     "GetReplicationMetrics",
+		APIType: gcsAPI,
+	})
+}
+
+// ResetReplication - not implemented.
+func (c *gcsClient) ResetReplication(_ context.Context, _ time.Duration, _ string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	return rinfo, probe.NewError(APINotImplemented{
+		API:     "ResetReplication",
+		APIType: gcsAPI,
+	})
+}
+
+// ReplicationResyncStatus - not implemented.
+func (c *gcsClient) ReplicationResyncStatus(_ context.Context, _ string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	return rinfo, probe.NewError(APINotImplemented{
+		API:     "ReplicationResyncStatus",
+		APIType: gcsAPI,
+	})
+}
+
+// GetEncryption - not implemented.
+func (c *gcsClient) GetEncryption(_ context.Context) (string, string, *probe.Error) {
+	return "", "", probe.NewError(APINotImplemented{
+		API:     "GetEncryption",
+		APIType: gcsAPI,
+	})
+}
+
+// SetEncryption - not implemented.
+func (c *gcsClient) SetEncryption(_ context.Context, _, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetEncryption",
+		APIType: gcsAPI,
+	})
+}
+
+// DeleteEncryption - not implemented.
+func (c *gcsClient) DeleteEncryption(_ context.Context) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "DeleteEncryption",
+		APIType: gcsAPI,
+	})
+}
+
+// GetBucketInfo - not implemented.
+func (c *gcsClient) GetBucketInfo(_ context.Context) (BucketInfo, *probe.Error) {
+	return BucketInfo{}, probe.NewError(APINotImplemented{
+		API:     "GetBucketInfo",
+		APIType: gcsAPI,
+	})
+}
+
+// Restore - not implemented.
+func (c *gcsClient) Restore(_ context.Context, _ string, _ int) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "Restore",
+		APIType: gcsAPI,
+	})
+}
+
+// GetPart - not implemented.
+func (c *gcsClient) GetPart(_ context.Context, _ int) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetPart",
+		APIType: gcsAPI,
+	})
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+	. "gopkg.in/check.v1"
+)
+
+var gcsTestTime = time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// gcsHandler is an http.Handler serving the buckets and objects of a
+// project with the JSON API of Google Cloud Storage.
+type gcsHandler map[string]map[string]string
+
+func (h gcsHandler) object(bucket, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"bucket":       bucket,
+		"name":         name,
+		"size":         strconv.Itoa(len(h[bucket][name])),
+		"etag":         "CAE=",
+		"updated":      gcsTestTime.Format(time.RFC3339),
+		"contentType":  "text/plain",
+		"storageClass": "NEARLINE",
+		"metadata":     map[string]string{"owner": "mc"},
+	}
+}
+
+func (h gcsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	reply := func(code int, v interface{}) {
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(v)
+	}
+	notFound := map[string]interface{}{"error": map[string]interface{}{"code": http.StatusNotFound, "message": "Not Found"}}
+
+	path := strings.TrimPrefix(r.URL.Path, "/storage/v1/b")
+	if path == "" {
+		// List buckets.
+		var items []map[string]interface{}
+		for name := range h {
+			items = append(items, map[string]interface{}{"name": name, "timeCreated": gcsTestTime.Format(time.RFC3339)})
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i]["name"].(string) < items[j]["name"].(string) })
+		reply(http.StatusOK, map[string]interface{}{"items": items})
+		return
+	}
+	bucket, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	objects, ok := h[bucket]
+	if !ok {
+		reply(http.StatusNotFound, notFound)
+		return
+	}
+	switch {
+	case rest == "":
+		reply(http.StatusOK, map[string]interface{}{"name": bucket, "timeCreated": gcsTestTime.Format(time.RFC3339)})
+	case rest == "o":
+		prefix, delimiter := r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter")
+		var names []string
+		for name := range objects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		items := []map[string]interface{}{}
+		prefixes := []string{}
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			if i := strings.Index(name[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				if p := name[:len(prefix)+i+1]; !seen[p] {
+					seen[p] = true
+					prefixes = append(prefixes, p)
+				}
+				continue
+			}
+			items = append(items, h.object(bucket, name))
+		}
+		reply(http.StatusOK, map[string]interface{}{"items": items, "prefixes": prefixes})
+	default:
+		name := strings.TrimPrefix(rest, "o/")
+		if _, ok := objects[name]; !ok {
+			reply(http.StatusNotFound, notFound)
+			return
+		}
+		reply(http.StatusOK, h.object(bucket, name))
+	}
+}
+
+func newTestGCSClient(c *C, serverURL, path string) *gcsClient {
+	api, e := storage.NewClient(context.Background(), option.WithEndpoint(serverURL+"/storage/v1/"), option.WithoutAuthentication())
+	c.Assert(e, IsNil)
+	return &gcsClient{targetURL: newClientURL("https://storage.googleapis.com" + path), projectID: "project", api: api}
+}
+
+func (s *TestSuite) TestGCSURLMapping(c *C) {
+	c.Assert(isGCSAlias(&aliasConfigV10{API: "GCS"}), Equals, true)
+	c.Assert(isGCSAlias(&aliasConfigV10{API: "s3v4"}), Equals, false)
+
+	client := &gcsClient{targetURL: newClientURL("https://storage.googleapis.com/bucket/dir/object")}
+	bucket, object := client.url2BucketAndObject()
+	c.Assert(bucket, Equals, "bucket")
+	c.Assert(object, Equals, "dir/object")
+	c.Assert(client.content("bucket", "dir/object").URL.String(), Equals, "https://storage.googleapis.com/bucket/dir/object")
+	c.Assert(client.objectContent(&storage.ObjectAttrs{Bucket: "bucket", Prefix: "dir/"}).Type.IsDir(), Equals, true)
+
+	// The service account key is read from the credentials file of the alias.
+	_, err := gcsNew("https://storage.googleapis.com/bucket", &aliasConfigV10{
+		API:             gcsAPI,
+		SecretKey:       "ignored",
+		CredentialsFile: filepath.Join(c.MkDir(), "missing.json"),
+	})
+	c.Assert(err, NotNil)
+}
+
+func (s *TestSuite) TestGCSStat(c *C) {
+	server := httptest.NewServer(gcsHandler{"bucket": {"object.txt": "hello", "dir/object": "world"}})
+	defer server.Close()
+
+	content, err := newTestGCSClient(c, server.URL, "/bucket/object.txt").Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(5))
+	c.Assert(content.StorageClass, Equals, "NEARLINE")
+	c.Assert(content.Time.Equal(gcsTestTime), Equals, true)
+	c.Assert(content.Metadata["Content-Type"], Equals, "text/plain")
+	c.Assert(content.UserMetadata["X-Amz-Meta-Owner"], Equals, "mc")
+	c.Assert(content.Type.IsRegular(), Equals, true)
+
+	content, err = newTestGCSClient(c, server.URL, "/bucket").Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	c.Assert(content.BucketName, Equals, "bucket")
+
+	// A missing object which is the prefix of other objects is a folder.
+	content, err = newTestGCSClient(c, server.URL, "/bucket/dir").Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Type.IsDir(), Equals, true)
+	c.Assert(content.URL.Path, Equals, "/bucket/dir/")
+
+	_, err = newTestGCSClient(c, server.URL, "/bucket/missing").Stat(context.Background(), StatOptions{})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(ObjectMissing)
+	c.Assert(ok, Equals, true)
+
+	_, err = newTestGCSClient(c, server.URL, "/missing").Stat(context.Background(), StatOptions{})
+	c.Assert(err, NotNil)
+	c.Assert(err.ToGoError(), Equals, BucketDoesNotExist{Bucket: "missing"})
+}
+
+func (s *TestSuite) TestGCSList(c *C) {
+	server := httptest.NewServer(gcsHandler{
+		"bucket": {"a": "1", "dir/b": "22", "dir/sub/c": "333", "dirt": "4444"},
+		"other":  {},
+	})
+	defer server.Close()
+
+	testCases := []struct {
+		path      string
+		recursive bool
+		expected  []string
+	}{
+		{"/", false, []string{"/bucket", "/other"}},
+		{"/bucket/", false, []string{"/bucket/a", "/bucket/dir/", "/bucket/dirt"}},
+		{"/bucket/dir", false, []string{"/bucket/dir/", "/bucket/dirt"}},
+		{"/bucket/dir/", false, []string{"/bucket/dir/b", "/bucket/dir/sub/"}},
+		{"/bucket/", true, []string{"/bucket/a", "/bucket/dir/b", "/bucket/dir/sub/c", "/bucket/dirt"}},
+		{"/bucket/dir/", true, []string{"/bucket/dir/b", "/bucket/dir/sub/c"}},
+	}
+	for i, testCase := range testCases {
+		client := newTestGCSClient(c, server.URL, testCase.path)
+		var listed []string
+		for content := range client.List(context.Background(), ListOptions{Recursive: testCase.recursive}) {
+			c.Assert(content.Err, IsNil, Commentf("Test %d", i+1))
+			listed = append(listed, content.URL.Path)
+		}
+		c.Assert(listed, DeepEquals, testCase.expected, Commentf("Test %d", i+1))
+	}
+
+	for content := range newTestGCSClient(c, server.URL, "/missing/").List(context.Background(), ListOptions{}) {
+		c.Assert(content.Err, NotNil)
+		c.Assert(content.Err.ToGoError(), Equals, BucketDoesNotExist{Bucket: "missing"})
+	}
+
+	// Buckets are listed in the project of the alias.
+	client := newTestGCSClient(c, server.URL, "/")
+	client.projectID = ""
+	_, err := client.ListBuckets(context.Background())
+	c.Assert(err, NotNil)
+}
+
+func (s *TestSuite) TestGCSUnsupported(c *C) {
+	client := &gcsClient{targetURL: newClientURL("https://storage.googleapis.com/bucket/object")}
+
+	_, err := client.Stat(context.Background(), StatOptions{versionID: "1"})
+	c.Assert(err, NotNil)
+	notImplemented, ok := err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+	c.Assert(notImplemented.APIType, Equals, gcsAPI)
+
+	for content := range client.List(context.Background(), ListOptions{Incomplete: true}) {
+		_, ok := content.Err.ToGoError().(APINotImplemented)
+		c.Assert(ok, Equals, true)
+	}
+
+	_, err = client.Get(context.Background(), GetOptions{VersionID: "1"})
+	c.Assert(err, NotNil)
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+
+	_, err = client.GetTags(context.Background(), "")
+	c.Assert(err, NotNil)
+	_, ok = err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/env"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Environment variable with the address of a local Prometheus endpoint
+// reporting the requests and transfers of the running command, e.g.
+// localhost:8081.
+const envMetricsAddress = "MC_METRICS_ADDRESS"
+
+var (
+	clientRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mc_client_requests_total",
+		Help: "The total number of requests sent per alias, method and response code",
+	}, []string{"alias", "method", "code"})
+	clientRequestDurations = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mc_client_request_duration_seconds",
+		Help:    "Histogram of the time to the response headers of requests per alias and method",
+		Buckets: prometheus.ExponentialBuckets(0.005, 4, 8),
+	}, []string{"alias", "method"})
+	clientSentBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mc_client_sent_bytes_total",
+		Help: "The total number of bytes sent in request bodies per alias",
+	}, []string{"alias"})
+	clientReceivedBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mc_client_received_bytes_total",
+		Help: "The total number of bytes received in response bodies per alias",
+	}, []string{"alias"})
+	_ = promauto.NewCounterFunc(prometheus.CounterOpts{
+		Name: "mc_client_retries_total",
+		Help: "The total number of requests sent again after an error the client retries on",
+	}, func() float64 {
+		return float64(atomic.LoadInt64(&globalRetries))
+	})
+	transferQueuedTasks = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mc_transfer_queued_tasks",
+		Help: "The number of copy and mirror tasks queued or in progress",
+	})
+	transferWorkers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mc_transfer_workers",
+		Help: "The number of parallel copy and mirror workers",
+	})
+)
+
+// metricsTransport reports the requests, latencies and bytes of an
+// alias to Prometheus.
+type metricsTransport struct {
+	alias     string
+	transport http.RoundTripper
+}
+
+func (t metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if req.ContentLength > 0 {
+		clientSentBytes.WithLabelValues(t.alias).Add(float64(req.ContentLength))
+	}
+	resp, e := t.transport.RoundTrip(req)
+	clientRequestDurations.WithLabelValues(t.alias, req.Method).Observe(time.Since(start).Seconds())
+	if e != nil {
+		clientRequests.WithLabelValues(t.alias, req.Method, "error").Inc()
+		return resp, e
+	}
+	clientRequests.WithLabelValues(t.alias, req.Method, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.Body != nil {
+		resp.Body = &metricsReadCloser{ReadCloser: resp.Body, counter: clientReceivedBytes.WithLabelValues(t.alias)}
+	}
+	return resp, e
+}
+
+// metricsReadCloser adds the bytes read to a Prometheus counter.
+type metricsReadCloser struct {
+	io.ReadCloser
+	counter prometheus.Counter
+}
+
+func (r *metricsReadCloser) Read(p []byte) (int, error) {
+	n, e := r.ReadCloser.Read(p)
+	r.counter.Add(float64(n))
+	return n, e
+}
+
+// startMetricsServer serves the metrics on the address set by
+// MC_METRICS_ADDRESS, if any, for as long as mc runs.
+func startMetricsServer() {
+	address := env.Get(envMetricsAddress, "")
+	if address == "" {
+		return
+	}
+	// A mux of its own, 'mirror --monitoring-address' uses the default one.
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	go func() {
+		if e := http.ListenAndServe(address, mux); e != nil {
+			fatalIf(probe.NewError(e), "Unable to setup metrics endpoint.")
+		}
+	}()
+}
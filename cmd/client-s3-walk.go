@@ -0,0 +1,74 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// listRecursiveParallel lists the objects under a prefix like
+// listRecursiveInRoutine, listing the prefixes found at every level with
+// several workers. Objects are sent in no particular order.
+func (c *S3Client) listRecursiveParallel(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
+	bucket, object := c.url2BucketAndObject()
+
+	queue := newFSWalkQueue(object)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.WalkWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				prefix, ok := queue.pop()
+				if !ok {
+					return
+				}
+				queue.push(c.listPrefixParallel(ctx, bucket, prefix, contentCh, opts)...)
+				queue.done()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// listPrefixParallel sends the objects of one level of a prefix and
+// returns the prefixes below it.
+func (c *S3Client) listPrefixParallel(ctx context.Context, bucket, prefix string, contentCh chan<- *ClientContent, opts ListOptions) (prefixes []string) {
+	isRecursive := false
+	for object := range c.listObjectWrapper(ctx, bucket, prefix, isRecursive, time.Time{}, false, false, opts.WithMetadata, -1, false) {
+		if object.Err != nil {
+			contentCh <- &ClientContent{
+				Err: probe.NewError(object.Err),
+			}
+			return prefixes
+		}
+		// A folder object of the prefix itself is sent like the
+		// recursive listing does, common prefixes are listed next.
+		if object.Key != prefix && strings.HasSuffix(object.Key, "/") {
+			prefixes = append(prefixes, object.Key)
+			continue
+		}
+		contentCh <- c.objectInfo2ClientContent(bucket, object)
+	}
+	return prefixes
+}
@@ -21,9 +21,11 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"hash/fnv"
 	"io"
 	"net"
@@ -38,6 +40,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
@@ -88,6 +91,15 @@ const (
 	AmzObjectLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
 	// AmzObjectLockLegalHold sets object lock legal hold
 	AmzObjectLockLegalHold = "X-Amz-Object-Lock-Legal-Hold"
+
+	amzRequestPayer = "X-Amz-Request-Payer"
+
+	// Upload size limits, same as in minio-go.
+	minPartSize            = 16 * humanize.MiByte
+	maxSinglePutObjectSize = 5 * humanize.GiByte
+
+	// Maximum duration of looking up the region of a bucket.
+	regionDiscoveryTimeout = 5 * time.Second
 )
 
 type dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
@@ -147,9 +159,23 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			}
 		}
 
+		// Route requests to the region of the bucket, either set
+		// explicitly or discovered previously for Amazon S3 buckets.
+		// Endpoints of a region such as s3.eu-west-1.amazonaws.com
+		// need no discovery.
+		region := os.Getenv("MC_REGION")
+		bucket, _ := s3Clnt.url2BucketAndObject()
+		discoverRegion := region == "" && bucket != "" && isAmazon(hostName) &&
+			s3utils.GetRegionFromURL(url.URL{Host: hostName}) == ""
+		if discoverRegion {
+			var known bool
+			region, known = getRegionCache().Lookup(hostName, bucket)
+			discoverRegion = !known
+		}
+
 		// Generate a hash out of s3Conf.
 		confHash := fnv.New32a()
-		confHash.Write([]byte(hostName + config.AccessKey + config.SecretKey + config.SessionToken))
+		confHash.Write([]byte(config.Alias + hostName + config.AccessKey + config.SecretKey + config.SessionToken + region + config.RequestPayer + config.TLS.String()))
 		confSum := confHash.Sum32()
 
 		// Lookup previous cache by hash.
@@ -199,6 +225,7 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 					if config.Insecure {
 						tlsConfig.InsecureSkipVerify = true
 					}
+					config.TLS.apply(tlsConfig)
 					tr.TLSClientConfig = tlsConfig
 
 					// Because we create a custom TLSClientConfig, we have to opt-in to HTTP/2.
@@ -213,7 +240,17 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				transport = tr
 			}
 
+			if config.DebugLite {
+				transport = tlsInfoTransport{transport: transport}
+			}
+
 			transport = limiter.New(config.UploadLimit, config.DownloadLimit, transport)
+			transport = limiter.NewOps(config.OpsLimit, transport)
+			transport = &retryCountingTransport{transport: transport}
+			if config.Alias != "" {
+				transport = aliasStatsTransport{stats: getAliasRequestStats(config.Alias), transport: transport}
+			}
+			transport = metricsTransport{alias: config.Alias, transport: transport}
 
 			if config.Debug {
 				if strings.EqualFold(config.Signature, "S3v4") {
@@ -223,13 +260,27 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 				}
 			}
 
+			// Custom headers, query parameters and the request payer go
+			// first, so that they are traced.
+			if len(config.CustomHeaders) > 0 || len(config.CustomQuery) > 0 || config.RequestPayer != "" {
+				transport = customRequestTransport{
+					transport:    transport,
+					headers:      config.CustomHeaders,
+					query:        config.CustomQuery,
+					requestPayer: config.RequestPayer,
+					accessKey:    config.AccessKey,
+					secretKey:    config.SecretKey,
+					sessionToken: config.SessionToken,
+				}
+			}
+
 			// Not found. Instantiate a new MinIO
 			var e error
 
 			options := minio.Options{
 				Creds:        creds,
 				Secure:       useTLS,
-				Region:       os.Getenv("MC_REGION"),
+				Region:       region,
 				BucketLookup: config.Lookup,
 				Transport:    transport,
 			}
@@ -251,6 +302,23 @@ func newFactory() func(config *Config) (Client, *probe.Error) {
 			clientCache[confSum] = api
 		}
 
+		// Remember the bucket region for future clients and runs, failing
+		// to discover it is not fatal since requests are redirected anyway,
+		// failures are remembered too so that they are not retried for
+		// every client.
+		if discoverRegion {
+			ctx, cancel := context.WithTimeout(globalContext, regionDiscoveryTimeout)
+			location, e := api.GetBucketLocation(ctx, bucket)
+			cancel()
+			if e == nil && location != "" {
+				errorIf(getRegionCache().Set(hostName, bucket, location).Trace(bucket, location),
+					"Unable to save discovered bucket region.")
+			} else {
+				errorIf(getRegionCache().SetUnknown(hostName, bucket).Trace(bucket),
+					"Unable to save discovered bucket region.")
+			}
+		}
+
 		// Store the new api object.
 		s3Clnt.api = api
 
@@ -510,6 +578,31 @@ func (c *S3Client) ListNotificationConfigs(ctx context.Context, arn string) ([]N
 	return configs, nil
 }
 
+// GetNotificationConfig - Get the whole notification configuration of a bucket
+func (c *S3Client) GetNotificationConfig(ctx context.Context) (notification.Configuration, *probe.Error) {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return notification.Configuration{}, probe.NewError(BucketNameEmpty{})
+	}
+	mb, e := c.api.GetBucketNotification(ctx, bucket)
+	if e != nil {
+		return notification.Configuration{}, probe.NewError(e)
+	}
+	return mb, nil
+}
+
+// SetNotificationConfig - Replace the whole notification configuration of a bucket
+func (c *S3Client) SetNotificationConfig(ctx context.Context, config notification.Configuration) *probe.Error {
+	bucket, _ := c.url2BucketAndObject()
+	if bucket == "" {
+		return probe.NewError(BucketNameEmpty{})
+	}
+	if e := c.api.SetBucketNotification(ctx, bucket, config); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
 // Supported content types
 var supportedContentTypes = []string{
 	"csv",
@@ -861,16 +954,37 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *pr
 	if opts.Zip {
 		o.Set("x-minio-extract", "true")
 	}
-	if opts.RangeStart != 0 {
-		err := o.SetRange(opts.RangeStart, 0)
+	if opts.LambdaArn != "" {
+		// MinIO runs the object through the transform registered for this ARN.
+		o.SetReqParam("lambdaArn", opts.LambdaArn)
+	}
+	if opts.RangeStart != 0 || opts.RangeLength > 0 {
+		var end int64
+		if opts.RangeLength > 0 {
+			end = opts.RangeStart + opts.RangeLength - 1
+		}
+		err := o.SetRange(opts.RangeStart, end)
 		if err != nil {
 			return nil, probe.NewError(err)
 		}
 	}
+	if err := setGetConditions(&o, opts.Conditions); err != nil {
+		return nil, err
+	}
 
 	reader, e := c.api.GetObject(ctx, bucket, object, o)
+	if e == nil && opts.Conditions.IsSet() {
+		// The request is only sent on first read, surface
+		// unmet preconditions before handing out the reader.
+		if _, e = reader.Stat(); e != nil {
+			reader.Close()
+		}
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
+		if errResponse.StatusCode == http.StatusNotModified {
+			return nil, probe.NewError(ObjectNotModified{})
+		}
 		if errResponse.Code == "NoSuchBucket" {
 			return nil, probe.NewError(BucketDoesNotExist{
 				Bucket: bucket,
@@ -889,6 +1003,54 @@ func (c *S3Client) Get(ctx context.Context, opts GetOptions) (io.ReadCloser, *pr
 	return reader, nil
 }
 
+// setGetConditions adds the preconditions of a conditional GET.
+func setGetConditions(o *minio.GetObjectOptions, conditions GetConditions) *probe.Error {
+	if conditions.IfMatch != "" {
+		if e := o.SetMatchETag(conditions.IfMatch); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if conditions.IfNoneMatch != "" {
+		if e := o.SetMatchETagExcept(conditions.IfNoneMatch); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if !conditions.IfModifiedSince.IsZero() {
+		if e := o.SetModified(conditions.IfModifiedSince); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	if !conditions.IfUnmodifiedSince.IsZero() {
+		if e := o.SetUnmodified(conditions.IfUnmodifiedSince); e != nil {
+			return probe.NewError(e)
+		}
+	}
+	return nil
+}
+
+// checkLambdaArn verifies an object lambda ARN passed for reads.
+func checkLambdaArn(arn string) *probe.Error {
+	switch {
+	case strings.HasPrefix(arn, "arn:minio:s3-object-lambda:"):
+		return nil
+	case strings.HasPrefix(arn, "arn:aws:s3-object-lambda:"):
+		// Object Lambda access points on AWS are signed for a different
+		// service, they are reachable through their bucket style alias.
+		return probe.NewError(errors.New("object lambda access points on Amazon S3 are addressed by their alias, use `ALIAS/ACCESS-POINT-ALIAS--ol-s3/OBJECT` instead"))
+	}
+	return probe.NewError(fmt.Errorf("`%s` is not an object lambda ARN", arn))
+}
+
+// isObjectLambdaAlias returns true if the URL points into an Amazon S3
+// Object Lambda access point alias, which is addressed like a bucket.
+func isObjectLambdaAlias(u ClientURL) bool {
+	if u.Type != objectStorage {
+		return false
+	}
+	bucket, _ := url2BucketAndObject(&u)
+	return strings.HasSuffix(bucket, "--ol-s3")
+}
+
 // Copy - copy object, uses server side copy API. Also uses an abstracted API
 // such that large file sizes will be copied in multipart manner on server
 // side.
@@ -1082,7 +1244,22 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		opts.SendContentMd5 = true
 	}
 
-	ui, e := c.api.PutObject(ctx, bucket, object, reader, size, opts)
+	var (
+		ui minio.UploadInfo
+		e  error
+	)
+	if putOpts.checksum != nil && trailingUploadChecksum(reader, size, opts, putOpts.checksum) {
+		ui, e = c.putObjectTrailingChecksum(ctx, bucket, object, reader, size, opts, putOpts.checksum)
+	} else {
+		if putOpts.checksum != nil {
+			var err *probe.Error
+			reader, err = setUploadChecksum(reader, size, &opts, putOpts.checksum)
+			if err != nil {
+				return 0, err.Trace(c.targetURL.String())
+			}
+		}
+		ui, e = c.api.PutObject(ctx, bucket, object, reader, size, opts)
+	}
 	if e != nil {
 		errResponse := minio.ToErrorResponse(e)
 		if errResponse.Code == "UnexpectedEOF" || e == io.EOF {
@@ -1121,9 +1298,239 @@ func (c *S3Client) Put(ctx context.Context, reader io.Reader, size int64, progre
 		}
 		return ui.Size, probe.NewError(e)
 	}
+	if putOpts.checksum != nil && putOpts.checksum.Value == "" {
+		// Multipart uploads report the checksum of all part checksums.
+		putOpts.checksum.Value = uploadInfoChecksum(ui, putOpts.checksum.Algorithm)
+	}
 	return ui.Size, nil
 }
 
+// uploadInfoChecksum returns the checksum of an algorithm reported by
+// the server on completing a multipart upload.
+func uploadInfoChecksum(ui minio.UploadInfo, algorithm string) string {
+	switch algorithm {
+	case "CRC32C":
+		return ui.ChecksumCRC32C
+	case "SHA1":
+		return ui.ChecksumSHA1
+	case "SHA256":
+		return ui.ChecksumSHA256
+	}
+	return ""
+}
+
+// trailingUploadChecksum tells if the checksum of an upload can neither
+// be computed upfront nor be left to minio-go, which only streams CRC32C
+// checksums of multipart uploads. The checksum of every part is then
+// sent in the trailer of the part, computed while it is uploaded.
+func trailingUploadChecksum(reader io.Reader, size int64, opts minio.PutObjectOptions, checksum *uploadChecksum) bool {
+	if checksum.Algorithm == "CRC32C" && !uploadsSinglePart(size, opts) {
+		return false
+	}
+	_, ok := reader.(io.ReadSeeker)
+	return !ok || size < 0 || size > maxSinglePutObjectSize
+}
+
+// uploadsSinglePart tells if minio-go uploads an object of a size with
+// a single PUT.
+func uploadsSinglePart(size int64, opts minio.PutObjectOptions) bool {
+	partSize := int64(opts.PartSize)
+	if partSize == 0 {
+		partSize = minPartSize
+	}
+	return size >= 0 && (size < partSize || opts.DisableMultipart)
+}
+
+// setUploadChecksum arranges for the object to be uploaded along with
+// a checksum of the requested algorithm.
+func setUploadChecksum(reader io.Reader, size int64, opts *minio.PutObjectOptions, checksum *uploadChecksum) (io.Reader, *probe.Error) {
+	if checksum.Algorithm == "CRC32C" && !uploadsSinglePart(size, *opts) {
+		if opts.SendContentMd5 {
+			return nil, probe.NewError(errors.New("CRC32C checksums cannot be combined with md5 sums on multipart uploads"))
+		}
+		// minio-go sends a CRC32C for every part of streamed multipart
+		// uploads, hide ReadAt to avoid the parallel uploader which doesn't.
+		return struct{ io.Reader }{reader}, nil
+	}
+
+	// Single part uploads carry the checksum of the whole object in a
+	// header, compute it upfront and rewind the source.
+	seeker, ok := reader.(io.ReadSeeker)
+	if !ok || size < 0 || size > maxSinglePutObjectSize {
+		return nil, probe.NewError(fmt.Errorf("%s checksums require a seekable source of at most %s",
+			checksum.Algorithm, humanize.IBytes(maxSinglePutObjectSize)))
+	}
+	start, e := seeker.Seek(0, io.SeekCurrent)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	hasher := newChecksumHasher(checksum.Algorithm)
+	if _, e = io.CopyN(hasher, seeker, size); e != nil {
+		return nil, probe.NewError(e)
+	}
+	if _, e = seeker.Seek(start, io.SeekStart); e != nil {
+		return nil, probe.NewError(e)
+	}
+	checksum.Value = base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+
+	if opts.UserMetadata == nil {
+		opts.UserMetadata = make(map[string]string, 1)
+	}
+	opts.UserMetadata["X-Amz-Checksum-"+checksumHeaderSuffix(checksum.Algorithm)] = checksum.Value
+	opts.DisableMultipart = true
+	return reader, nil
+}
+
+// trailingChecksumReader computes the checksum of a part while it is
+// read and sets it in the trailer of the part at EOF.
+type trailingChecksumReader struct {
+	*bytes.Reader
+	hasher  hash.Hash
+	trailer http.Header
+	key     string
+}
+
+func newTrailingChecksumReader(data []byte, algorithm string) *trailingChecksumReader {
+	r := &trailingChecksumReader{
+		Reader:  bytes.NewReader(data),
+		hasher:  newChecksumHasher(algorithm),
+		trailer: make(http.Header, 1),
+		key:     "X-Amz-Checksum-" + checksumHeaderSuffix(algorithm),
+	}
+	// The length of the trailer is signed before the part is sent, set
+	// it to a placeholder of the same length.
+	r.trailer.Set(r.key, base64.StdEncoding.EncodeToString(r.hasher.Sum(nil)))
+	return r
+}
+
+func (r *trailingChecksumReader) Read(p []byte) (int, error) {
+	n, e := r.Reader.Read(p)
+	r.hasher.Write(p[:n])
+	if e == io.EOF {
+		r.trailer.Set(r.key, r.Value())
+	}
+	return n, e
+}
+
+// Seek rewinds the part when its upload is retried.
+func (r *trailingChecksumReader) Seek(offset int64, whence int) (int64, error) {
+	n, e := r.Reader.Seek(offset, whence)
+	if e == nil && n == 0 {
+		r.hasher.Reset()
+	}
+	return n, e
+}
+
+// Value returns the base64 encoded checksum of the part read so far.
+func (r *trailingChecksumReader) Value() string {
+	return base64.StdEncoding.EncodeToString(r.hasher.Sum(nil))
+}
+
+// setCompletePartChecksum sets the checksum of a part to be completed.
+func setCompletePartChecksum(part *minio.CompletePart, algorithm, value string) {
+	switch algorithm {
+	case "CRC32C":
+		part.ChecksumCRC32C = value
+	case "SHA1":
+		part.ChecksumSHA1 = value
+	case "SHA256":
+		part.ChecksumSHA256 = value
+	}
+}
+
+// putObjectTrailingChecksum uploads a stream with a multipart upload
+// sending the checksum of every part in its trailer. CRC64NVME objects
+// carry a checksum of the full object, computed along the way, others
+// a checksum of the checksums of their parts.
+func (c *S3Client) putObjectTrailingChecksum(ctx context.Context, bucket, object string, reader io.Reader, size int64, opts minio.PutObjectOptions, checksum *uploadChecksum) (minio.UploadInfo, error) {
+	_, partSize, _, e := minio.OptimalPartInfo(size, opts.PartSize)
+	if e != nil {
+		return minio.UploadInfo{}, e
+	}
+	if size >= 0 {
+		reader = io.LimitReader(reader, size)
+	}
+
+	if opts.UserMetadata == nil {
+		opts.UserMetadata = make(map[string]string, 2)
+	}
+	opts.UserMetadata["X-Amz-Checksum-Algorithm"] = checksum.Algorithm
+	fullObject := checksum.Algorithm == "CRC64NVME"
+	if fullObject {
+		opts.UserMetadata["X-Amz-Checksum-Type"] = "FULL_OBJECT"
+	}
+	core := &minio.Core{Client: c.api}
+	uploadID, e := core.NewMultipartUpload(ctx, bucket, object, opts)
+	if e != nil {
+		return minio.UploadInfo{}, e
+	}
+	abort := func(e error, uploaded int64) (minio.UploadInfo, error) {
+		core.AbortMultipartUpload(context.Background(), bucket, object, uploadID)
+		return minio.UploadInfo{Size: uploaded}, e
+	}
+
+	// Parts of SSE-C encrypted uploads are sent with the customer key.
+	var partOpts minio.PutObjectPartOptions
+	if opts.ServerSideEncryption != nil && opts.ServerSideEncryption.Type() == encrypt.SSEC {
+		partOpts.SSE = opts.ServerSideEncryption
+	}
+
+	objectHasher := newChecksumHasher(checksum.Algorithm)
+	buf := make([]byte, partSize)
+	var (
+		parts    []minio.CompletePart
+		uploaded int64
+	)
+	for partNumber := 1; ; partNumber++ {
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			return abort(e, uploaded)
+		}
+		// An empty stream is uploaded as a single empty part.
+		if n == 0 && partNumber > 1 {
+			break
+		}
+		objectHasher.Write(buf[:n])
+
+		partReader := newTrailingChecksumReader(buf[:n], checksum.Algorithm)
+		partOpts.Trailer = partReader.trailer
+		part, e := core.PutObjectPart(ctx, bucket, object, uploadID, partNumber, partReader, int64(n), partOpts)
+		if e != nil {
+			return abort(e, uploaded)
+		}
+		uploaded += int64(n)
+		if opts.Progress != nil {
+			io.CopyN(io.Discard, opts.Progress, int64(n))
+		}
+		completePart := minio.CompletePart{PartNumber: partNumber, ETag: part.ETag}
+		setCompletePartChecksum(&completePart, checksum.Algorithm, partReader.Value())
+		parts = append(parts, completePart)
+
+		if n < len(buf) {
+			break
+		}
+	}
+	if size >= 0 && uploaded != size {
+		// Reported as an unexpected EOF by Put.
+		return abort(io.EOF, uploaded)
+	}
+
+	completeOpts := minio.PutObjectOptions{ServerSideEncryption: opts.ServerSideEncryption}
+	if fullObject {
+		checksum.Value = base64.StdEncoding.EncodeToString(objectHasher.Sum(nil))
+		completeOpts.UserMetadata = map[string]string{
+			"X-Amz-Checksum-Type":      "FULL_OBJECT",
+			"X-Amz-Checksum-Crc64nvme": checksum.Value,
+		}
+	}
+	ui, e := core.CompleteMultipartUpload(ctx, bucket, object, uploadID, parts, completeOpts)
+	if e != nil {
+		return abort(e, uploaded)
+	}
+	ui.Size = uploaded
+	return ui, nil
+}
+
 // PutPart - upload an object with custom metadata. (Same as Put)
 func (c *S3Client) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, putOpts PutOptions) (int64, *probe.Error) {
 	return c.Put(ctx, reader, size, progress, putOpts)
@@ -1245,8 +1652,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 					for removeStatus := range statusCh {
 						if removeStatus.Err != nil {
 							resultCh <- RemoveResult{
-								BucketName: bucket,
-								Err:        probe.NewError(removeStatus.Err),
+								BucketName:         bucket,
+								RemoveObjectResult: removeStatus,
+								Err:                probe.NewError(removeStatus.Err),
 							}
 						} else {
 							resultCh <- RemoveResult{
@@ -1291,8 +1699,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 						case removeStatus := <-statusCh:
 							if removeStatus.Err != nil {
 								resultCh <- RemoveResult{
-									BucketName: bucket,
-									Err:        probe.NewError(removeStatus.Err),
+									BucketName:         bucket,
+									RemoveObjectResult: removeStatus,
+									Err:                probe.NewError(removeStatus.Err),
 								}
 							} else {
 								resultCh <- RemoveResult{
@@ -1331,7 +1740,9 @@ func (c *S3Client) Remove(ctx context.Context, isIncomplete, isRemoveBucket, isB
 					// it is too generic. We have the object's name and vid.
 					// Adding the object's name and version id into the error msg
 					resultCh <- RemoveResult{
-						Err: probe.NewError(removeStatus.Err),
+						BucketName:         prevBucket,
+						RemoveObjectResult: removeStatus,
+						Err:                probe.NewError(removeStatus.Err),
 					}
 				} else {
 					resultCh <- RemoveResult{
@@ -1423,6 +1834,13 @@ func (c *S3Client) RemoveBucket(ctx context.Context, forceRemove bool) *probe.Er
 	if e := c.api.RemoveBucketWithOptions(ctx, bucket, opts); e != nil {
 		return probe.NewError(e)
 	}
+	// A bucket with the same name may be created in another region later on.
+	if isAmazon(c.targetURL.Host) {
+		errorIf(getRegionCache().Delete(c.targetURL.Host, bucket).Trace(bucket),
+			"Unable to remove bucket region from cache.")
+	}
+	errorIf(getStatCache().Delete(statCacheBucketKey(c.targetURL.Host, bucket)).Trace(bucket),
+		"Unable to remove bucket from stat cache.")
 	return nil
 }
 
@@ -1612,7 +2030,7 @@ func (c *S3Client) Stat(ctx context.Context, opts StatOptions) (*ClientContent,
 	// Start with a HEAD request first to return object metadata information.
 	// If the object is not found, continue to look for a directory marker or a prefix
 	if !strings.HasSuffix(path, string(c.targetURL.Separator)) && opts.timeRef.IsZero() {
-		o := minio.StatObjectOptions{ServerSideEncryption: opts.sse, VersionID: opts.versionID}
+		o := minio.StatObjectOptions{ServerSideEncryption: opts.sse, VersionID: opts.versionID, Checksum: opts.checksum}
 		if opts.isZip {
 			o.Set("x-minio-extract", "true")
 		}
@@ -1780,13 +2198,14 @@ func (c *S3Client) listVersionsRoutine(ctx context.Context, b, o string, opts Li
 	}
 
 	for _, b := range buckets {
-		var skipKey string
-		for objectVersion := range c.api.ListObjects(ctx, b, minio.ListObjectsOptions{
+		var skipKey, deletedKey string
+		listOpts := minio.ListObjectsOptions{
 			Prefix:       o,
 			Recursive:    opts.Recursive,
 			WithVersions: true,
 			WithMetadata: opts.WithMetadata,
-		}) {
+		}
+		for objectVersion := range c.api.ListObjects(ctx, b, listOpts) {
 			if objectVersion.Err != nil {
 				select {
 				case <-ctx.Done():
@@ -1802,7 +2221,18 @@ func (c *S3Client) listVersionsRoutine(ctx context.Context, b, o string, opts Li
 				continue
 			}
 
+			if deletedKey == objectVersion.Key {
+				// Skip older versions of a deleted key
+				continue
+			}
+
 			if opts.TimeRef.IsZero() || objectVersion.LastModified.Before(opts.TimeRef) {
+				if opts.ExcludeDeleted && objectVersion.IsDeleteMarker && skipKey != objectVersion.Key {
+					// The latest version is a delete marker, the key
+					// is not visible to applications.
+					deletedKey = objectVersion.Key
+					continue
+				}
 				skipKey = objectVersion.Key
 
 				// Skip if this is a delete marker and we are not asked to list it
@@ -1945,9 +2375,13 @@ func (c *S3Client) unversionedList(ctx context.Context, contentCh chan *ClientCo
 			c.listIncompleteInRoutine(ctx, contentCh)
 		}
 	} else {
-		if opts.Recursive {
+		bucket, _ := c.url2BucketAndObject()
+		switch {
+		case opts.Recursive && opts.WalkWorkers > 1 && bucket != "" && opts.ShowDir == DirNone && !opts.ListZip:
+			c.listRecursiveParallel(ctx, contentCh, opts)
+		case opts.Recursive:
 			c.listRecursiveInRoutine(ctx, contentCh, opts)
-		} else {
+		default:
 			c.listInRoutine(ctx, contentCh, opts)
 		}
 	}
@@ -2235,6 +2669,16 @@ func (c *S3Client) objectInfo2ClientContent(bucket string, entry minio.ObjectInf
 
 // Returns bucket stat info of current bucket.
 func (c *S3Client) bucketStat(ctx context.Context, bucket string) (*ClientContent, *probe.Error) {
+	content := &ClientContent{
+		URL: c.targetURL.Clone(), BucketName: bucket, Time: time.Unix(0, 0), Type: os.ModeDir,
+	}
+	// Only existing buckets are cached, a missing bucket may be
+	// created any time.
+	cacheKey := statCacheBucketKey(c.targetURL.Host, bucket)
+	if exists, ok := getStatCache().Get(cacheKey); ok && exists {
+		return content, nil
+	}
+
 	exists, e := c.api.BucketExists(ctx, bucket)
 	if e != nil {
 		return nil, probe.NewError(e)
@@ -2242,9 +2686,8 @@ func (c *S3Client) bucketStat(ctx context.Context, bucket string) (*ClientConten
 	if !exists {
 		return nil, probe.NewError(BucketDoesNotExist{Bucket: bucket})
 	}
-	return &ClientContent{
-		URL: c.targetURL.Clone(), BucketName: bucket, Time: time.Unix(0, 0), Type: os.ModeDir,
-	}, nil
+	errorIf(getStatCache().Set(cacheKey, true).Trace(bucket), "Unable to save bucket to stat cache.")
+	return content, nil
 }
 
 func (c *S3Client) listInRoutine(ctx context.Context, contentCh chan *ClientContent, opts ListOptions) {
@@ -24,9 +24,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	minio "github.com/minio/minio-go/v7"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	. "gopkg.in/check.v1"
 )
 
@@ -264,3 +268,357 @@ func (s *TestSuite) TestSelectCompressionType(c *C) {
 		c.Assert(cType, DeepEquals, test.compressionType)
 	}
 }
+
+// TestSetUploadChecksum - tests checksums computed for single part uploads.
+func (s *TestSuite) TestSetUploadChecksum(c *C) {
+	for algorithm, expected := range map[string]string{
+		"CRC32C": "yZRlqg==",
+		"SHA256": "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=",
+	} {
+		opts := minio.PutObjectOptions{}
+		checksum := &uploadChecksum{Algorithm: algorithm}
+		reader, err := setUploadChecksum(bytes.NewReader([]byte("hello world")), 11, &opts, checksum)
+		c.Assert(err, IsNil)
+		c.Assert(checksum.Value, Equals, expected)
+		c.Assert(opts.UserMetadata["X-Amz-Checksum-"+checksumHeaderSuffix(algorithm)], Equals, expected)
+		c.Assert(opts.DisableMultipart, Equals, true)
+
+		// The source must be rewound for the upload.
+		data, e := io.ReadAll(reader)
+		c.Assert(e, IsNil)
+		c.Assert(string(data), Equals, "hello world")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TestCustomRequestTransport - tests headers and query parameters
+// added with --header and --query.
+func (s *TestSuite) TestCustomRequestTransport(c *C) {
+	headers, e := parseCustomHeaders([]string{"x-tenant: acme"})
+	c.Assert(e, IsNil)
+	query, e := parseCustomQuery([]string{"vendor=ext"})
+	c.Assert(e, IsNil)
+
+	_, e = parseCustomHeaders([]string{"Authorization: secret"})
+	c.Assert(e, NotNil)
+	_, e = parseCustomHeaders([]string{"no-colon"})
+	c.Assert(e, NotNil)
+	_, e = parseCustomQuery([]string{"=v"})
+	c.Assert(e, NotNil)
+
+	var sent *http.Request
+	transport := customRequestTransport{
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		headers:   headers,
+		query:     query,
+		accessKey: "minio",
+		secretKey: "minio123",
+	}
+
+	req, e := http.NewRequest(http.MethodGet, "http://localhost:9000/bucket/object?versionId=1", nil)
+	c.Assert(e, IsNil)
+	req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=minio/20230101/eu-west-1/s3/aws4_request, SignedHeaders=host, Signature=0")
+	_, e = transport.RoundTrip(req)
+	c.Assert(e, IsNil)
+
+	c.Assert(sent.Header.Get("X-Tenant"), Equals, "acme")
+	c.Assert(sent.URL.Query().Get("vendor"), Equals, "ext")
+	c.Assert(sent.URL.Query().Get("versionId"), Equals, "1")
+	c.Assert(strings.Contains(sent.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request"), Equals, true)
+	c.Assert(strings.HasSuffix(sent.Header.Get("Authorization"), "Signature=0"), Equals, false)
+	// The caller's request is left alone.
+	c.Assert(req.URL.RawQuery, Equals, "versionId=1")
+}
+
+// TestRequestPayerTransport - tests that the request payer of Requester
+// Pays buckets is signed into every kind of request.
+func (s *TestSuite) TestRequestPayerTransport(c *C) {
+	var sent *http.Request
+	transport := customRequestTransport{
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+		}),
+		requestPayer: "requester",
+		accessKey:    "minio",
+		secretKey:    "minio123",
+	}
+
+	testCases := []struct {
+		method, url string
+		headers     map[string]string
+	}{
+		// GetObject, StatObject, ListObjects and HEAD bucket.
+		{method: http.MethodGet, url: "https://s3.amazonaws.com/bucket/object"},
+		{method: http.MethodHead, url: "https://s3.amazonaws.com/bucket/object"},
+		{method: http.MethodGet, url: "https://s3.amazonaws.com/bucket/?list-type=2&prefix=a"},
+		{method: http.MethodHead, url: "https://s3.amazonaws.com/bucket/"},
+		// PutObject, CopyObject and UploadPart.
+		{method: http.MethodPut, url: "https://s3.amazonaws.com/bucket/object"},
+		{method: http.MethodPut, url: "https://s3.amazonaws.com/bucket/object", headers: map[string]string{"X-Amz-Copy-Source": "/src/object"}},
+		{method: http.MethodPut, url: "https://s3.amazonaws.com/bucket/object?partNumber=1&uploadId=abc"},
+		// NewMultipartUpload, CompleteMultipartUpload and DeleteObjects.
+		{method: http.MethodPost, url: "https://s3.amazonaws.com/bucket/object?uploads="},
+		{method: http.MethodPost, url: "https://s3.amazonaws.com/bucket/object?uploadId=abc"},
+		{method: http.MethodPost, url: "https://s3.amazonaws.com/bucket/?delete="},
+		// RemoveObject and AbortMultipartUpload.
+		{method: http.MethodDelete, url: "https://s3.amazonaws.com/bucket/object"},
+		{method: http.MethodDelete, url: "https://s3.amazonaws.com/bucket/object?uploadId=abc"},
+	}
+	for _, testCase := range testCases {
+		req, e := http.NewRequest(testCase.method, testCase.url, nil)
+		c.Assert(e, IsNil)
+		for k, v := range testCase.headers {
+			req.Header.Set(k, v)
+		}
+		req.Header.Set("X-Amz-Content-Sha256", "UNSIGNED-PAYLOAD")
+		req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=minio/20230101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=0")
+		_, e = transport.RoundTrip(req)
+		c.Assert(e, IsNil)
+
+		c.Assert(sent.Header.Get(amzRequestPayer), Equals, "requester")
+		c.Assert(strings.Contains(sent.Header.Get("Authorization"), "x-amz-request-payer"), Equals, true)
+		for k, v := range testCase.headers {
+			c.Assert(sent.Header.Get(k), Equals, v)
+		}
+		// The caller's request is left alone.
+		c.Assert(req.Header.Get(amzRequestPayer), Equals, "")
+	}
+
+	// Streaming signed uploads cannot be signed again.
+	req, e := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", nil)
+	c.Assert(e, IsNil)
+	req.Header.Set("X-Amz-Content-Sha256", "STREAMING-AWS4-HMAC-SHA256-PAYLOAD")
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=minio/20230101/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=0")
+	_, e = transport.RoundTrip(req)
+	c.Assert(e, NotNil)
+}
+
+// TestRequestPayerOperations - tests that object operations on a
+// Requester Pays bucket all carry the request payer.
+func (s *TestSuite) TestRequestPayerOperations(c *C) {
+	object := objectHandler{
+		resource: "/bucket/object",
+		data:     []byte("Hello, World"),
+	}
+	var mu sync.Mutex
+	var requests, payers int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		if r.Header.Get(amzRequestPayer) == "requester" {
+			payers++
+		}
+		mu.Unlock()
+		object.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + object.resource
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	conf.RequestPayer = "requester"
+	conf.Transport = server.Client().Transport.(*http.Transport)
+	s3c, err := S3New(conf)
+	c.Assert(err, IsNil)
+
+	_, err = s3c.Put(context.Background(), bytes.NewReader(object.data), int64(len(object.data)), nil, PutOptions{
+		metadata: map[string]string{"Content-Type": "application/octet-stream"},
+	})
+	c.Assert(err, IsNil)
+	_, err = s3c.Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	reader, err := s3c.Get(context.Background(), GetOptions{})
+	c.Assert(err, IsNil)
+	_, e := io.Copy(io.Discard, reader)
+	c.Assert(e, IsNil)
+	reader.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	c.Assert(requests > 0, Equals, true)
+	c.Assert(payers, Equals, requests)
+}
+
+// TestAliasStatsTransport - tests the request accounting per alias.
+func (s *TestSuite) TestAliasStatsTransport(c *C) {
+	stats := &aliasRequestStats{}
+	status := http.StatusOK
+	transport := aliasStatsTransport{
+		stats: stats,
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if status == 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("hello world"))}, nil
+		}),
+	}
+
+	for _, status = range []int{http.StatusOK, http.StatusNotFound, http.StatusServiceUnavailable, 0} {
+		req, e := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", strings.NewReader("12345"))
+		c.Assert(e, IsNil)
+		resp, e := transport.RoundTrip(req)
+		if status == 0 {
+			c.Assert(e, NotNil)
+			continue
+		}
+		c.Assert(e, IsNil)
+		_, e = io.Copy(io.Discard, resp.Body)
+		c.Assert(e, IsNil)
+	}
+
+	c.Assert(stats.entry(), DeepEquals, aliasStatsEntry{
+		Requests:     4,
+		Errors:       2,
+		ClientErrors: 1,
+		BytesUp:      20,
+		BytesDown:    33,
+	})
+}
+
+// TestMetricsTransport - tests the Prometheus metrics of requests.
+func (s *TestSuite) TestMetricsTransport(c *C) {
+	transport := metricsTransport{
+		alias: "metrics-test",
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("hello world"))}, nil
+		}),
+	}
+
+	req, e := http.NewRequest(http.MethodPut, "http://localhost:9000/bucket/object", strings.NewReader("12345"))
+	c.Assert(e, IsNil)
+	resp, e := transport.RoundTrip(req)
+	c.Assert(e, IsNil)
+	_, e = io.Copy(io.Discard, resp.Body)
+	c.Assert(e, IsNil)
+
+	c.Assert(testutil.ToFloat64(clientRequests.WithLabelValues("metrics-test", http.MethodPut, "200")), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(clientSentBytes.WithLabelValues("metrics-test")), Equals, 5.0)
+	c.Assert(testutil.ToFloat64(clientReceivedBytes.WithLabelValues("metrics-test")), Equals, 11.0)
+}
+
+func (s *TestSuite) TestFeatureProbeStatus(c *C) {
+	testCases := []struct {
+		e              error
+		supportedCodes []string
+		status         string
+	}{
+		{nil, nil, featureSupported},
+		{minio.ErrorResponse{Code: "NotImplemented"}, nil, featureUnsupported},
+		{minio.ErrorResponse{Code: "NoSuchTagSet"}, []string{"NoSuchTagSet"}, featureSupported},
+		{minio.ErrorResponse{Code: "AccessDenied"}, []string{"NoSuchTagSet"}, featureUnknown},
+	}
+	for _, testCase := range testCases {
+		c.Assert(featureProbeStatus(testCase.e, testCase.supportedCodes...), Equals, testCase.status)
+	}
+}
+
+// listHandler serves delimited listings of a fixed set of keys.
+type listHandler struct {
+	keys []string
+}
+
+func (h listHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["location"]; ok {
+		w.Write([]byte("<LocationConstraint xmlns=\"http://doc.s3.amazonaws.com/2006-03-01\"></LocationConstraint>"))
+		return
+	}
+	prefix, delimiter := r.URL.Query().Get("prefix"), r.URL.Query().Get("delimiter")
+	var contents, prefixes strings.Builder
+	seen := map[string]bool{}
+	for _, key := range h.keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+			commonPrefix := key[:len(prefix)+i+1]
+			if !seen[commonPrefix] {
+				seen[commonPrefix] = true
+				prefixes.WriteString("<CommonPrefixes><Prefix>" + commonPrefix + "</Prefix></CommonPrefixes>")
+			}
+			continue
+		}
+		contents.WriteString("<Contents><Key>" + key + "</Key><LastModified>2015-05-21T18:24:21.097Z</LastModified><Size>1</Size></Contents>")
+	}
+	w.Write([]byte("<ListBucketResult xmlns=\"http://s3.amazonaws.com/doc/2006-03-01/\"><Name>bucket</Name><Prefix>" + prefix +
+		"</Prefix><IsTruncated>false</IsTruncated>" + contents.String() + prefixes.String() + "</ListBucketResult>"))
+}
+
+// Test parallel recursive listing.
+func (s *TestSuite) TestListRecursiveParallel(c *C) {
+	keys := []string{"a.txt", "dir1/b.txt", "dir1/sub/c.txt", "dir1/sub/d.txt", "dir2/e.txt", "dir3/"}
+	server := httptest.NewServer(listHandler{keys: keys})
+	defer server.Close()
+
+	conf := new(Config)
+	conf.HostURL = server.URL + "/bucket/"
+	conf.AccessKey = "WLGDGYAQYIGI833EV05A"
+	conf.SecretKey = "BYvgJM101sHngl2uzjXS/OBF/aMxAN06JrJ3qJlF"
+	conf.Signature = "S3v4"
+	s3c, err := S3New(conf)
+	c.Assert(err, IsNil)
+
+	var listed []string
+	for content := range s3c.List(globalContext, ListOptions{Recursive: true, ShowDir: DirNone, WalkWorkers: 4}) {
+		c.Assert(content.Err, IsNil)
+		listed = append(listed, strings.TrimPrefix(content.URL.Path, "/bucket/"))
+	}
+	sort.Strings(listed)
+	c.Assert(listed, DeepEquals, keys)
+}
+
+func (s *TestSuite) TestTrailingUploadChecksum(c *C) {
+	opts := minio.PutObjectOptions{PartSize: minPartSize}
+	seekable := bytes.NewReader(nil)
+	stream := struct{ io.Reader }{seekable}
+	testCases := []struct {
+		reader    io.Reader
+		size      int64
+		algorithm string
+		expected  bool
+	}{
+		// Checksums of seekable sources are computed upfront.
+		{seekable, 10, "SHA256", false},
+		{seekable, minPartSize * 2, "SHA1", false},
+		{seekable, maxSinglePutObjectSize + 1, "SHA256", true},
+		// minio-go streams CRC32C checksums of multipart uploads.
+		{stream, -1, "CRC32C", false},
+		{stream, minPartSize * 2, "CRC32C", false},
+		{stream, 10, "CRC32C", true},
+		{stream, -1, "CRC64NVME", true},
+		{stream, 10, "SHA1", true},
+	}
+	for i, testCase := range testCases {
+		got := trailingUploadChecksum(testCase.reader, testCase.size, opts, &uploadChecksum{Algorithm: testCase.algorithm})
+		c.Assert(got, Equals, testCase.expected, Commentf("Test %d", i+1))
+	}
+}
+
+func (s *TestSuite) TestTrailingChecksumReader(c *C) {
+	r := newTrailingChecksumReader([]byte("123456789"), "SHA1")
+	placeholder := r.trailer.Get("X-Amz-Checksum-Sha1")
+	c.Assert(len(placeholder), Equals, len("98O8HYCOBHMq32eZZczDTKeuNEE="))
+
+	data, e := io.ReadAll(r)
+	c.Assert(e, IsNil)
+	c.Assert(string(data), Equals, "123456789")
+	c.Assert(r.trailer.Get("X-Amz-Checksum-Sha1"), Equals, "98O8HYCOBHMq32eZZczDTKeuNEE=")
+
+	// A retried part is hashed again from its start.
+	_, e = r.Seek(0, io.SeekStart)
+	c.Assert(e, IsNil)
+	_, e = io.ReadAll(r)
+	c.Assert(e, IsNil)
+	c.Assert(r.Value(), Equals, "98O8HYCOBHMq32eZZczDTKeuNEE=")
+}
@@ -0,0 +1,813 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sftpAPI is the API of aliases served over SFTP.
+const sftpAPI = "sftp"
+
+// sftp client, paths are absolute on the SFTP server.
+type sftpClient struct {
+	PathURL *ClientURL
+	sftp    *sftp.Client
+	conn    *ssh.Client
+}
+
+// sftpAuthMethods authenticates with the password of the alias, if any,
+// then with the keys of the SSH agent and the default SSH keys. The
+// connection to the SSH agent, if any, is returned to be closed once
+// authenticated.
+func sftpAuthMethods(password string) (methods []ssh.AuthMethod, agentConn net.Conn) {
+	if password != "" {
+		methods = append(methods, ssh.Password(password))
+	}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, e := net.Dial("unix", sock); e == nil {
+			agentConn = conn
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	home, e := os.UserHomeDir()
+	if e != nil {
+		return methods, agentConn
+	}
+	var signers []ssh.Signer
+	for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		key, e := os.ReadFile(filepath.Join(home, ".ssh", name))
+		if e != nil {
+			continue
+		}
+		// Keys protected by a passphrase are left to the SSH agent.
+		if signer, e := ssh.ParsePrivateKey(key); e == nil {
+			signers = append(signers, signer)
+		}
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	return methods, agentConn
+}
+
+// sftpHostKeyCallback verifies the host keys against ~/.ssh/known_hosts
+// unless --insecure is set.
+func sftpHostKeyCallback() (ssh.HostKeyCallback, *probe.Error) {
+	if globalInsecure {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	home, e := os.UserHomeDir()
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	knownHosts := filepath.Join(home, ".ssh", "known_hosts")
+	callback, e := knownhosts.New(knownHosts)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(knownHosts)
+	}
+	return callback, nil
+}
+
+// sftpConn is an SSH connection with its SFTP session.
+type sftpConn struct {
+	ssh  *ssh.Client
+	sftp *sftp.Client
+}
+
+// newSFTPFactory encloses sftpNew with a cache of the SSH connections,
+// which are shared by all clients of a host and user. A connection is
+// evicted once it drops so that the next client dials a new one.
+func newSFTPFactory() func(urlStr string, hostCfg *aliasConfigV10) (Client, *probe.Error) {
+	connCache := make(map[uint32]*sftpConn)
+	var mutex sync.Mutex
+
+	return func(urlStr string, hostCfg *aliasConfigV10) (Client, *probe.Error) {
+		targetURL := newClientURL(urlStr)
+		host := targetURL.Host
+		if _, _, e := net.SplitHostPort(host); e != nil {
+			host = net.JoinHostPort(host, "22")
+		}
+		username := hostCfg.AccessKey
+		if username == "" {
+			if u, e := user.Current(); e == nil {
+				username = u.Username
+			}
+		}
+
+		confHash := fnv.New32a()
+		confHash.Write([]byte(host + username + hostCfg.SecretKey))
+		confSum := confHash.Sum32()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		conn, found := connCache[confSum]
+		if !found {
+			hostKeyCallback, err := sftpHostKeyCallback()
+			if err != nil {
+				return nil, err.Trace(urlStr)
+			}
+			auth, agentConn := sftpAuthMethods(hostCfg.SecretKey)
+			sshConn, e := ssh.Dial("tcp", host, &ssh.ClientConfig{
+				User:            username,
+				Auth:            auth,
+				HostKeyCallback: hostKeyCallback,
+				Timeout:         10 * time.Second,
+			})
+			if agentConn != nil {
+				agentConn.Close()
+			}
+			if e != nil {
+				return nil, probe.NewError(e).Trace(urlStr)
+			}
+			sftpSession, e := sftp.NewClient(sshConn)
+			if e != nil {
+				sshConn.Close()
+				return nil, probe.NewError(e).Trace(urlStr)
+			}
+			conn = &sftpConn{ssh: sshConn, sftp: sftpSession}
+			connCache[confSum] = conn
+			go func() {
+				conn.ssh.Wait()
+				mutex.Lock()
+				if connCache[confSum] == conn {
+					delete(connCache, confSum)
+				}
+				mutex.Unlock()
+				conn.sftp.Close()
+			}()
+		}
+		return &sftpClient{PathURL: targetURL, sftp: conn.sftp, conn: conn.ssh}, nil
+	}
+}
+
+// sftpNew returns an initialized sftpClient structure.
+var sftpNew = newSFTPFactory()
+
+// URL get url.
+func (c *sftpClient) GetURL() ClientURL {
+	return *c.PathURL
+}
+
+// toClientError constructs a typed client error for known SFTP errors.
+func (c *sftpClient) toClientError(e error, fpath string) *probe.Error {
+	if errors.Is(e, sftp.ErrSSHFxConnectionLost) && c.conn != nil {
+		// Drop the connection so that the next client redials.
+		c.conn.Close()
+	}
+	if os.IsPermission(e) {
+		return probe.NewError(PathInsufficientPermission{Path: fpath})
+	}
+	if os.IsNotExist(e) {
+		return probe.NewError(PathNotFound{Path: fpath})
+	}
+	return probe.NewError(e)
+}
+
+func (c *sftpClient) content(fpath string, fi os.FileInfo) *ClientContent {
+	contentURL := *c.PathURL
+	contentURL.Path = fpath
+	return &ClientContent{
+		URL:  contentURL,
+		Time: fi.ModTime(),
+		Size: fi.Size(),
+		Type: fi.Mode(),
+	}
+}
+
+// Stat - get metadata from path.
+func (c *sftpClient) Stat(_ context.Context, opts StatOptions) (*ClientContent, *probe.Error) {
+	fpath := c.PathURL.Path
+	if opts.incomplete {
+		fpath += partSuffix
+	}
+	st, e := c.sftp.Stat(fpath)
+	if e != nil {
+		return nil, c.toClientError(e, fpath).Trace(c.PathURL.String())
+	}
+	content := c.content(c.PathURL.Path, st)
+	content.Metadata = map[string]string{
+		"Content-Type": guessURLContentType(c.PathURL.Path),
+	}
+	return content, nil
+}
+
+// List - list files and folders, in lexical order as the filesystem
+// client does.
+func (c *sftpClient) List(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent, 1)
+	if opts.ListZip {
+		contentCh <- &ClientContent{
+			Err: probe.NewError(APINotImplemented{API: "ListZip", APIType: sftpAPI}),
+		}
+		close(contentCh)
+		return contentCh
+	}
+
+	go func() {
+		defer close(contentCh)
+
+		fpath := c.PathURL.Path
+		if !strings.HasSuffix(fpath, "/") {
+			// A file is listed by itself, anything else as a prefix of
+			// the entries of its parent folder.
+			if st, e := c.sftp.Stat(fpath); e == nil && !st.IsDir() {
+				contentCh <- c.content(fpath, st)
+				return
+			}
+			c.walk(ctx, path.Dir(fpath), fpath, opts, contentCh)
+			return
+		}
+		dir := strings.TrimSuffix(fpath, "/")
+		if dir == "" {
+			dir = "/"
+		}
+		if opts.Recursive && opts.ShowDir == DirFirst {
+			contentCh <- &ClientContent{URL: *c.PathURL, Type: os.ModeDir}
+		}
+		c.walk(ctx, dir, "", opts, contentCh)
+		if opts.Recursive && opts.ShowDir == DirLast {
+			contentCh <- &ClientContent{URL: *c.PathURL, Type: os.ModeDir}
+		}
+	}()
+	return contentCh
+}
+
+// walk sends the entries of a folder matching a prefix, descending into
+// folders when listing recursively. It returns true if the listing was
+// canceled.
+func (c *sftpClient) walk(ctx context.Context, dir, prefix string, opts ListOptions, contentCh chan<- *ClientContent) bool {
+	files, e := c.sftp.ReadDir(dir)
+	if e != nil {
+		contentCh <- &ClientContent{Err: c.toClientError(e, dir)}
+		return false
+	}
+	sort.Sort(byDirName(files))
+	for _, fi := range files {
+		name := path.Join(dir, fi.Name())
+		if !strings.HasPrefix(name, prefix) && !(fi.IsDir() && strings.HasPrefix(prefix, name+"/")) {
+			continue
+		}
+		if isIgnoredFile(fi.Name()) {
+			continue
+		}
+		if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			st, e := c.sftp.Stat(name)
+			if e != nil {
+				// Ignore all errors on symlinks
+				continue
+			}
+			fi = st
+		}
+		select {
+		case <-ctx.Done():
+			return true
+		default:
+		}
+		switch {
+		case fi.IsDir() && !opts.Recursive:
+			contentCh <- c.content(name, fi)
+		case fi.IsDir():
+			matched := strings.HasPrefix(name, prefix)
+			if matched && opts.ShowDir == DirFirst {
+				contentCh <- c.content(name, fi)
+			}
+			if c.walk(ctx, name, prefix, opts, contentCh) {
+				return true
+			}
+			if matched && opts.ShowDir == DirLast {
+				contentCh <- c.content(name, fi)
+			}
+		case fi.Mode().IsRegular():
+			content := c.content(name, fi)
+			if opts.Incomplete != strings.HasSuffix(name, partSuffix) {
+				continue
+			}
+			content.URL.Path = strings.TrimSuffix(name, partSuffix)
+			contentCh <- content
+		}
+	}
+	return false
+}
+
+// ListBuckets returns the list of folders inside a base path.
+func (c *sftpClient) ListBuckets(_ context.Context) ([]*ClientContent, *probe.Error) {
+	fpath := c.PathURL.Path
+	files, e := c.sftp.ReadDir(fpath)
+	if e != nil {
+		return nil, c.toClientError(e, fpath)
+	}
+	sort.Sort(byDirName(files))
+	buckets := make([]*ClientContent, 0, len(files))
+	for _, fi := range files {
+		if fi.IsDir() && !isIgnoredFile(fi.Name()) {
+			buckets = append(buckets, c.content(path.Join(fpath, fi.Name()), fi))
+		}
+	}
+	return buckets, nil
+}
+
+// Get returns a reader of the file.
+func (c *sftpClient) Get(_ context.Context, opts GetOptions) (io.ReadCloser, *probe.Error) {
+	if opts.Conditions.IsSet() {
+		return nil, probe.NewError(APINotImplemented{
+			API:     "GetObject with conditions",
+			APIType: sftpAPI,
+		})
+	}
+	fpath := c.PathURL.Path
+	file, e := c.sftp.Open(fpath)
+	if e != nil {
+		return nil, c.toClientError(e, fpath).Trace(fpath)
+	}
+	if opts.RangeStart != 0 {
+		if _, e = file.Seek(opts.RangeStart, io.SeekStart); e != nil {
+			file.Close()
+			return nil, probe.NewError(e).Trace(fpath)
+		}
+	}
+	if opts.RangeLength > 0 {
+		return limitReadCloser(file, opts.RangeLength), nil
+	}
+	return file, nil
+}
+
+// Put - write a file through a temporary file, renamed once complete.
+func (c *sftpClient) Put(_ context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	objectPath := c.PathURL.Path
+	objectDir, objectName := path.Split(objectPath)
+	if objectDir != "" {
+		if e := c.sftp.MkdirAll(objectDir); e != nil {
+			return 0, c.toClientError(e, objectPath).Trace(objectPath)
+		}
+		// Check if object name is empty, it must be an empty directory
+		if objectName == "" {
+			return 0, nil
+		}
+	}
+
+	objectPartPath := objectPath + partSuffix
+	defer c.sftp.Remove(objectPartPath)
+
+	tmpFile, e := c.sftp.Create(objectPartPath)
+	if e != nil {
+		return 0, c.toClientError(e, objectPath).Trace(objectPath)
+	}
+	totalWritten, e := io.Copy(tmpFile, hookreader.NewHook(reader, progress))
+	if e != nil {
+		tmpFile.Close()
+		return 0, probe.NewError(e)
+	}
+	if e = tmpFile.Close(); e != nil {
+		return totalWritten, probe.NewError(e)
+	}
+
+	if size > 0 {
+		if totalWritten < size {
+			return totalWritten, probe.NewError(UnexpectedEOF{
+				TotalSize:    size,
+				TotalWritten: totalWritten,
+			})
+		}
+		if totalWritten > size {
+			return totalWritten, probe.NewError(UnexpectedExcessRead{
+				TotalSize:    size,
+				TotalWritten: totalWritten,
+			})
+		}
+	}
+
+	// Plain SFTP renames do not replace an existing file.
+	if e = c.sftp.PosixRename(objectPartPath, objectPath); e != nil {
+		c.sftp.Remove(objectPath)
+		if e = c.sftp.Rename(objectPartPath, objectPath); e != nil {
+			return totalWritten, c.toClientError(e, objectPath).Trace(objectPartPath, objectPath)
+		}
+	}
+
+	if _, ok := opts.metadata[metadataKey]; ok && opts.isPreserve {
+		attr, e := parseAttribute(opts.metadata)
+		if e != nil {
+			return totalWritten, probe.NewError(e)
+		}
+		atime, mtime, err := parseAtimeMtime(attr)
+		if err != nil {
+			return totalWritten, err.Trace()
+		}
+		if !atime.IsZero() && !mtime.IsZero() {
+			if e := c.sftp.Chtimes(objectPath, atime, mtime); e != nil {
+				return totalWritten, probe.NewError(e)
+			}
+		}
+	}
+	return totalWritten, nil
+}
+
+// PutPart - write a file, reading up to N bytes.
+func (c *sftpClient) PutPart(ctx context.Context, reader io.Reader, size int64, progress io.Reader, opts PutOptions) (int64, *probe.Error) {
+	if size < 0 {
+		return c.Put(ctx, reader, size, progress, opts)
+	}
+	return c.Put(ctx, io.LimitReader(reader, size), size, progress, opts)
+}
+
+// Copy - copy a file of the same server, the data is streamed through
+// the client as SFTP has no server side copy.
+func (c *sftpClient) Copy(ctx context.Context, source string, opts CopyOptions, progress io.Reader) *probe.Error {
+	sourcePath := filepath.ToSlash(source)
+	rc, e := c.sftp.Open(sourcePath)
+	if e != nil {
+		return c.toClientError(e, sourcePath).Trace(sourcePath)
+	}
+	defer rc.Close()
+
+	putOpts := PutOptions{
+		metadata:   opts.metadata,
+		isPreserve: opts.isPreserve,
+	}
+	if _, err := c.Put(ctx, rc, opts.size, progress, putOpts); err != nil {
+		return err.Trace(c.PathURL.Path, sourcePath)
+	}
+	return nil
+}
+
+// Remove - remove files and empty folders.
+func (c *sftpClient) Remove(_ context.Context, isIncomplete, _, _, _ bool, contentCh <-chan *ClientContent) <-chan RemoveResult {
+	resultCh := make(chan RemoveResult)
+	go func() {
+		defer close(resultCh)
+		for content := range contentCh {
+			if content.Err != nil {
+				resultCh <- RemoveResult{Err: content.Err}
+				continue
+			}
+			name := content.URL.Path
+			if isIncomplete {
+				name += partSuffix
+			}
+			var e error
+			if content.Type.IsDir() {
+				e = c.sftp.RemoveDirectory(strings.TrimSuffix(name, "/"))
+			} else {
+				e = c.sftp.Remove(name)
+			}
+			switch {
+			case e == nil:
+				res := RemoveResult{}
+				res.ObjectName = content.URL.Path
+				resultCh <- res
+			case os.IsNotExist(e):
+				// ignore if path already removed.
+			default:
+				resultCh <- RemoveResult{Err: c.toClientError(e, name)}
+			}
+		}
+	}()
+	return resultCh
+}
+
+// MakeBucket - create a folder and its parents.
+func (c *sftpClient) MakeBucket(_ context.Context, _ string, _, _ bool) *probe.Error {
+	if e := c.sftp.MkdirAll(c.PathURL.Path); e != nil {
+		return c.toClientError(e, c.PathURL.Path)
+	}
+	return nil
+}
+
+// RemoveBucket - remove a folder, with its content if forced.
+func (c *sftpClient) RemoveBucket(_ context.Context, forceRemove bool) *probe.Error {
+	var removeAll func(dir string) error
+	removeAll = func(dir string) error {
+		files, e := c.sftp.ReadDir(dir)
+		if e != nil {
+			return e
+		}
+		for _, fi := range files {
+			name := path.Join(dir, fi.Name())
+			if fi.IsDir() {
+				e = removeAll(name)
+			} else {
+				e = c.sftp.Remove(name)
+			}
+			if e != nil {
+				return e
+			}
+		}
+		return c.sftp.RemoveDirectory(dir)
+	}
+
+	dir := c.PathURL.Path
+	var e error
+	if forceRemove {
+		e = removeAll(dir)
+	} else {
+		e = c.sftp.RemoveDirectory(dir)
+	}
+	if e != nil {
+		return c.toClientError(e, dir)
+	}
+	return nil
+}
+
+func (c *sftpClient) AddUserAgent(_, _ string) {
+}
+
+// String identifies the server of the client in errors.
+func (c *sftpClient) String() string {
+	return fmt.Sprintf("sftp://%s", c.PathURL.Host)
+}
+
+// Select - not implemented.
+func (c *sftpClient) Select(_ context.Context, _ string, _ encrypt.ServerSide, _ SelectObjectOpts) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "Select",
+		APIType: sftpAPI,
+	})
+}
+
+// Watch - not implemented.
+func (c *sftpClient) Watch(_ context.Context, _ WatchOptions) (*WatchObject, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "Watch",
+		APIType: sftpAPI,
+	})
+}
+
+// ShareDownload - not implemented.
+func (c *sftpClient) ShareDownload(_ context.Context, _ string, _ time.Duration) (string, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "ShareDownload",
+		APIType: sftpAPI,
+	})
+}
+
+// ShareUpload - not implemented.
+func (c *sftpClient) ShareUpload(_ context.Context, _ bool, _ time.Duration, _ string) (string, map[string]string, *probe.Error) {
+	return "", nil, probe.NewError(APINotImplemented{
+		API:     "ShareUpload",
+		APIType: sftpAPI,
+	})
+}
+
+// SetObjectLockConfig - not implemented.
+func (c *sftpClient) SetObjectLockConfig(_ context.Context, _ minio.RetentionMode, _ uint64, _ minio.ValidityUnit) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetObjectLockConfig",
+		APIType: sftpAPI,
+	})
+}
+
+// GetObjectLockConfig - not implemented.
+func (c *sftpClient) GetObjectLockConfig(_ context.Context) (status string, mode minio.RetentionMode, validity uint64, unit minio.ValidityUnit, err *probe.Error) {
+	return "", "", 0, "", probe.NewError(APINotImplemented{
+		API:     "GetObjectLockConfig",
+		APIType: sftpAPI,
+	})
+}
+
+// GetAccess - not implemented.
+func (c *sftpClient) GetAccess(_ context.Context) (access, policyJSON string, err *probe.Error) {
+	return "", "", probe.NewError(APINotImplemented{
+		API:     "GetAccess",
+		APIType: sftpAPI,
+	})
+}
+
+// GetAccessRules - not implemented.
+func (c *sftpClient) GetAccessRules(_ context.Context) (map[string]string, *probe.Error) {
+	return map[string]string{}, probe.NewError(APINotImplemented{
+		API:     "GetBucketPolicy",
+		APIType: sftpAPI,
+	})
+}
+
+// SetAccess - not implemented.
+func (c *sftpClient) SetAccess(_ context.Context, _ string, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetAccess",
+		APIType: sftpAPI,
+	})
+}
+
+// PutObjectRetention - not implemented.
+func (c *sftpClient) PutObjectRetention(_ context.Context, _ string, _ minio.RetentionMode, _ time.Time, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectRetention",
+		APIType: sftpAPI,
+	})
+}
+
+// GetObjectRetention - not implemented.
+func (c *sftpClient) GetObjectRetention(_ context.Context, _ string) (minio.RetentionMode, time.Time, *probe.Error) {
+	return "", time.Time{}, probe.NewError(APINotImplemented{
+		API:     "GetObjectRetention",
+		APIType: sftpAPI,
+	})
+}
+
+// PutObjectLegalHold - not implemented.
+func (c *sftpClient) PutObjectLegalHold(_ context.Context, _ string, _ minio.LegalHoldStatus) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "PutObjectLegalHold",
+		APIType: sftpAPI,
+	})
+}
+
+// GetObjectLegalHold - not implemented.
+func (c *sftpClient) GetObjectLegalHold(_ context.Context, _ string) (minio.LegalHoldStatus, *probe.Error) {
+	return "", probe.NewError(APINotImplemented{
+		API:     "GetObjectLegalHold",
+		APIType: sftpAPI,
+	})
+}
+
+// GetTags - not implemented.
+func (c *sftpClient) GetTags(_ context.Context, _ string) (map[string]string, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetObjectTagging",
+		APIType: sftpAPI,
+	})
+}
+
+// SetTags - not implemented.
+func (c *sftpClient) SetTags(_ context.Context, _, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetObjectTagging",
+		APIType: sftpAPI,
+	})
+}
+
+// DeleteTags - not implemented.
+func (c *sftpClient) DeleteTags(_ context.Context, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "DeleteObjectTagging",
+		APIType: sftpAPI,
+	})
+}
+
+// GetLifecycle - not implemented.
+func (c *sftpClient) GetLifecycle(_ context.Context) (*lifecycle.Configuration, time.Time, *probe.Error) {
+	return nil, time.Time{}, probe.NewError(APINotImplemented{
+		API:     "GetLifecycle",
+		APIType: sftpAPI,
+	})
+}
+
+// SetLifecycle - not implemented.
+func (c *sftpClient) SetLifecycle(_ context.Context, _ *lifecycle.Configuration) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetLifecycle",
+		APIType: sftpAPI,
+	})
+}
+
+// GetVersion - not implemented.
+func (c *sftpClient) GetVersion(_ context.Context) (minio.BucketVersioningConfiguration, *probe.Error) {
+	return minio.BucketVersioningConfiguration{}, probe.NewError(APINotImplemented{
+		API:     "GetVersion",
+		APIType: sftpAPI,
+	})
+}
+
+// SetVersion - not implemented.
+func (c *sftpClient) SetVersion(_ context.Context, _ string, _ []string, _ bool) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetVersion",
+		APIType: sftpAPI,
+	})
+}
+
+// GetReplication - not implemented.
+func (c *sftpClient) GetReplication(_ context.Context) (replication.Config, *probe.Error) {
+	return replication.Config{}, probe.NewError(APINotImplemented{
+		API:     "GetReplication",
+		APIType: sftpAPI,
+	})
+}
+
+// SetReplication - not implemented.
+func (c *sftpClient) SetReplication(_ context.Context, _ *replication.Config, _ replication.Options) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetReplication",
+		APIType: sftpAPI,
+	})
+}
+
+// RemoveReplication - not implemented.
+func (c *sftpClient) RemoveReplication(_ context.Context) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "RemoveReplication",
+		APIType: sftpAPI,
+	})
+}
+
+// GetReplicationMetrics - not implemented.
+func (c *sftpClient) GetReplicationMetrics(_ context.Context) (replication.MetricsV2, *probe.Error) {
+	return replication.MetricsV2{}, probe.NewError(APINotImplemented{
+		API:     "GetReplicationMetrics",
+		APIType: sftpAPI,
+	})
+}
+
+// ResetReplication - not implemented.
+func (c *sftpClient) ResetReplication(_ context.Context, _ time.Duration, _ string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	return rinfo, probe.NewError(APINotImplemented{
+		API:     "ResetReplication",
+		APIType: sftpAPI,
+	})
+}
+
+// ReplicationResyncStatus - not implemented.
+func (c *sftpClient) ReplicationResyncStatus(_ context.Context, _ string) (rinfo replication.ResyncTargetsInfo, err *probe.Error) {
+	return rinfo, probe.NewError(APINotImplemented{
+		API:     "ReplicationResyncStatus",
+		APIType: sftpAPI,
+	})
+}
+
+// GetEncryption - not implemented.
+func (c *sftpClient) GetEncryption(_ context.Context) (string, string, *probe.Error) {
+	return "", "", probe.NewError(APINotImplemented{
+		API:     "GetEncryption",
+		APIType: sftpAPI,
+	})
+}
+
+// SetEncryption - not implemented.
+func (c *sftpClient) SetEncryption(_ context.Context, _, _ string) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "SetEncryption",
+		APIType: sftpAPI,
+	})
+}
+
+// DeleteEncryption - not implemented.
+func (c *sftpClient) DeleteEncryption(_ context.Context) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "DeleteEncryption",
+		APIType: sftpAPI,
+	})
+}
+
+// GetBucketInfo - not implemented.
+func (c *sftpClient) GetBucketInfo(_ context.Context) (BucketInfo, *probe.Error) {
+	return BucketInfo{}, probe.NewError(APINotImplemented{
+		API:     "GetBucketInfo",
+		APIType: sftpAPI,
+	})
+}
+
+// Restore - not implemented.
+func (c *sftpClient) Restore(_ context.Context, _ string, _ int) *probe.Error {
+	return probe.NewError(APINotImplemented{
+		API:     "Restore",
+		APIType: sftpAPI,
+	})
+}
+
+// GetPart - not implemented.
+func (c *sftpClient) GetPart(_ context.Context, _ int) (io.ReadCloser, *probe.Error) {
+	return nil, probe.NewError(APINotImplemented{
+		API:     "GetPart",
+		APIType: sftpAPI,
+	})
+}
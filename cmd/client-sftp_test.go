@@ -0,0 +1,164 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/sftp"
+	. "gopkg.in/check.v1"
+)
+
+// newTestSFTPClient returns an sftpClient of a path served by an SFTP
+// server running in process over pipes, and a function stopping it.
+func newTestSFTPClient(c *C, fpath string) (*sftpClient, func()) {
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+	server, e := sftp.NewServer(struct {
+		io.Reader
+		io.WriteCloser
+	}{serverReader, serverWriter})
+	c.Assert(e, IsNil)
+	go server.Serve()
+
+	client, e := sftp.NewClientPipe(clientReader, clientWriter)
+	c.Assert(e, IsNil)
+	return &sftpClient{PathURL: newClientURL("sftp://localhost" + filepath.ToSlash(fpath)), sftp: client}, func() {
+		client.Close()
+		server.Close()
+	}
+}
+
+func (s *TestSuite) TestSFTPStat(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("SFTP paths are not Windows paths")
+	}
+	root := c.MkDir()
+	c.Assert(os.WriteFile(filepath.Join(root, "object.csv"), []byte("hello"), 0o644), IsNil)
+
+	client, stop := newTestSFTPClient(c, filepath.Join(root, "object.csv"))
+	defer stop()
+	content, err := client.Stat(context.Background(), StatOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(content.Size, Equals, int64(5))
+	c.Assert(content.URL.Scheme, Equals, "sftp")
+	c.Assert(content.URL.Path, Equals, filepath.ToSlash(filepath.Join(root, "object.csv")))
+	c.Assert(content.Metadata["Content-Type"], Equals, "text/csv")
+
+	missing, stopMissing := newTestSFTPClient(c, filepath.Join(root, "missing"))
+	defer stopMissing()
+	_, err = missing.Stat(context.Background(), StatOptions{})
+	c.Assert(err, NotNil)
+	_, ok := err.ToGoError().(PathNotFound)
+	c.Assert(ok, Equals, true)
+}
+
+func (s *TestSuite) TestSFTPList(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("SFTP paths are not Windows paths")
+	}
+	root := c.MkDir()
+	for _, name := range []string{"a/1", "a/2", "ab", "b/c/3"} {
+		fpath := filepath.Join(root, name)
+		c.Assert(os.MkdirAll(filepath.Dir(fpath), 0o755), IsNil)
+		c.Assert(os.WriteFile(fpath, []byte(name), 0o644), IsNil)
+	}
+	base := filepath.ToSlash(root)
+
+	testCases := []struct {
+		path      string
+		recursive bool
+		expected  []string
+	}{
+		// A folder lists its entries.
+		{"/", false, []string{"/a", "/ab", "/b"}},
+		{"/", true, []string{"/a/1", "/a/2", "/ab", "/b/c/3"}},
+		// A path which is not a folder is a prefix.
+		{"/a", false, []string{"/a", "/ab"}},
+		{"/a", true, []string{"/a/1", "/a/2", "/ab"}},
+		// A file lists by itself.
+		{"/b/c/3", false, []string{"/b/c/3"}},
+	}
+	for i, testCase := range testCases {
+		client, stop := newTestSFTPClient(c, filepath.Join(root, testCase.path))
+		if strings.HasSuffix(testCase.path, "/") {
+			client.PathURL.Path += "/"
+		}
+		var listed []string
+		for content := range client.List(context.Background(), ListOptions{Recursive: testCase.recursive, ShowDir: DirNone}) {
+			c.Assert(content.Err, IsNil)
+			listed = append(listed, strings.TrimPrefix(content.URL.Path, base))
+		}
+		stop()
+		c.Assert(listed, DeepEquals, testCase.expected, Commentf("Test %d", i+1))
+	}
+
+	client, stop := newTestSFTPClient(c, filepath.Join(root, "missing")+"/")
+	defer stop()
+	for content := range client.List(context.Background(), ListOptions{}) {
+		_, ok := content.Err.ToGoError().(PathNotFound)
+		c.Assert(ok, Equals, true)
+	}
+}
+
+func (s *TestSuite) TestSFTPPutGet(c *C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("SFTP paths are not Windows paths")
+	}
+	root := c.MkDir()
+	client, stop := newTestSFTPClient(c, filepath.Join(root, "dir", "object"))
+	defer stop()
+
+	data := "hello world"
+	n, err := client.Put(context.Background(), bytes.NewReader([]byte(data)), int64(len(data)), nil, PutOptions{})
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(len(data)))
+	_, e := os.Stat(filepath.Join(root, "dir", "object"+partSuffix))
+	c.Assert(os.IsNotExist(e), Equals, true)
+
+	reader, err := client.Get(context.Background(), GetOptions{RangeStart: 6})
+	c.Assert(err, IsNil)
+	got, e := io.ReadAll(reader)
+	c.Assert(e, IsNil)
+	reader.Close()
+	c.Assert(string(got), Equals, "world")
+}
+
+func (s *TestSuite) TestSFTPUnsupported(c *C) {
+	client := &sftpClient{PathURL: newClientURL("sftp://localhost/tmp/object")}
+	_, err := client.GetTags(context.Background(), "")
+	c.Assert(err, NotNil)
+	notImplemented, ok := err.ToGoError().(APINotImplemented)
+	c.Assert(ok, Equals, true)
+	c.Assert(notImplemented.APIType, Equals, sftpAPI)
+
+	for content := range client.List(context.Background(), ListOptions{ListZip: true}) {
+		_, ok := content.Err.ToGoError().(APINotImplemented)
+		c.Assert(ok, Equals, true)
+	}
+
+	// A lost connection without an SSH connection to drop is still an error.
+	err = client.toClientError(sftp.ErrSSHFxConnectionLost, "/tmp/object")
+	c.Assert(err.ToGoError(), Equals, sftp.ErrSSHFxConnectionLost)
+}
@@ -47,6 +47,7 @@ type ClientURLType int
 const (
 	objectStorage = iota // MinIO and S3 compatible cloud storage
 	fileSystem           // POSIX compatible file systems
+	sftpStorage          // Files served over SFTP
 )
 
 // Maybe rawurl is of the form scheme:path. (Scheme must be [a-zA-Z][a-zA-Z0-9+-.]*)
@@ -93,6 +94,15 @@ func getHost(authority string) (host string) {
 	return authority
 }
 
+// splitSFTPUser splits the user from the host of an sftp URL authority.
+func splitSFTPUser(authority string) (user, host string) {
+	i := strings.LastIndex(authority, "@")
+	if i < 0 {
+		return "", authority
+	}
+	return authority[:i], authority[i+1:]
+}
+
 // newClientURL returns an abstracted URL for filesystems and object storage.
 func newClientURL(urlStr string) *ClientURL {
 	scheme, rest := getScheme(urlStr)
@@ -103,6 +113,18 @@ func newClientURL(urlStr string) *ClientURL {
 		if rest == "" {
 			rest = "/"
 		}
+		if scheme == "sftp" {
+			// The user is part of the alias credentials.
+			_, host := splitSFTPUser(authority)
+			return &ClientURL{
+				Scheme:          scheme,
+				Type:            sftpStorage,
+				Host:            host,
+				Path:            rest,
+				SchemeSeparator: "://",
+				Separator:       '/',
+			}
+		}
 		host := getHost(authority)
 		if host != "" && (scheme == "http" || scheme == "https") {
 			return &ClientURL{
@@ -155,7 +177,7 @@ func (u ClientURL) String() string {
 		return u.Path
 	}
 	// if objectStorage convert from any non standard paths to a supported URL path style.
-	if u.Type == objectStorage {
+	if u.Type == objectStorage || u.Type == sftpStorage {
 		buf.WriteString(u.Scheme)
 		buf.WriteByte(':')
 		buf.WriteString("//")
@@ -186,7 +208,7 @@ func urlJoinPath(url1, url2 string) string {
 }
 
 // url2Stat returns stat info for URL - supports bucket, object and a prefixe with or without a trailing slash
-func url2Stat(ctx context.Context, urlStr, versionID string, fileAttr bool, encKeyDB map[string][]prefixSSEPair, timeRef time.Time, isZip bool) (client Client, content *ClientContent, err *probe.Error) {
+func url2Stat(ctx context.Context, urlStr, versionID string, fileAttr bool, encKeyDB encryptionConfig, timeRef time.Time, isZip bool) (client Client, content *ClientContent, err *probe.Error) {
 	client, err = newClient(urlStr)
 	if err != nil {
 		return nil, nil, err.Trace(urlStr)
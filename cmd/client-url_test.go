@@ -30,6 +30,13 @@ func (s *TestSuite) TestURL(c *C) {
 	c.Assert(url.Scheme, Equals, "https")
 	c.Assert(url.Host, Equals, "s3.amazonaws.com")
 	c.Assert(url.Path, Equals, "/mybucket/foo?.go")
+
+	urlStr = "sftp://backup@files.example.com:2222/srv/data"
+	url = newClientURL(urlStr)
+	c.Assert(url.Type, Equals, ClientURLType(sftpStorage))
+	c.Assert(url.Host, Equals, "files.example.com:2222")
+	c.Assert(url.Path, Equals, "/srv/data")
+	c.Assert(url.String(), Equals, "sftp://files.example.com:2222/srv/data")
 }
 
 // TestURLJoinPath - tests joining two different urls.
@@ -52,3 +59,24 @@ func (s *TestSuite) TestURLJoinPath(c *C) {
 	url = urlJoinPath(url1, url2)
 	c.Assert(url, Equals, "http://s3.mycompany.io/dev/mybucket/bin/")
 }
+
+// TestTrashPath - tests mapping object paths into and out of the trash.
+func (s *TestSuite) TestTrashPath(c *C) {
+	prefix, err := normalizeTrashPrefix("/.trash")
+	c.Assert(err, IsNil)
+	c.Assert(prefix, Equals, ".trash/")
+
+	_, err = normalizeTrashPrefix("/")
+	c.Assert(err, NotNil)
+
+	c.Assert(trashPath("/mybucket/bin/zgrep", prefix), Equals, "/mybucket/.trash/bin/zgrep")
+
+	original, ok := untrashPath("/mybucket/.trash/bin/zgrep", prefix)
+	c.Assert(ok, Equals, true)
+	c.Assert(original, Equals, "/mybucket/bin/zgrep")
+
+	_, ok = untrashPath("/mybucket/bin/zgrep", prefix)
+	c.Assert(ok, Equals, false)
+	c.Assert(isTrashed("/mybucket/.trash/", prefix), Equals, false)
+	c.Assert(isTrashed("/mybucket/.trashy/zgrep", prefix), Equals, false)
+}
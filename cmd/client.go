@@ -21,6 +21,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -49,6 +50,24 @@ type GetOptions struct {
 	VersionID  string
 	Zip        bool
 	RangeStart int64
+	// Number of bytes read from RangeStart, up to the end if zero.
+	RangeLength int64
+	LambdaArn   string
+	Conditions  GetConditions
+}
+
+// GetConditions holds the preconditions of a conditional GET, an
+// object which does not satisfy them is not transferred.
+type GetConditions struct {
+	IfMatch           string    `json:"ifMatch,omitempty"`
+	IfNoneMatch       string    `json:"ifNoneMatch,omitempty"`
+	IfModifiedSince   time.Time `json:"ifModifiedSince,omitempty"`
+	IfUnmodifiedSince time.Time `json:"ifUnmodifiedSince,omitempty"`
+}
+
+// IsSet returns true if any precondition is requested.
+func (g GetConditions) IsSet() bool {
+	return g.IfMatch != "" || g.IfNoneMatch != "" || !g.IfModifiedSince.IsZero() || !g.IfUnmodifiedSince.IsZero()
 }
 
 // PutOptions holds options for PUT operation
@@ -61,6 +80,16 @@ type PutOptions struct {
 	multipartSize         uint64
 	multipartThreads      uint
 	concurrentStream      bool
+	checksum              *uploadChecksum
+	sparse                bool
+	attrPreserve          bool
+}
+
+// uploadChecksum requests an additional x-amz-checksum-* on upload,
+// Value is filled in with the base64 encoded checksum of the object.
+type uploadChecksum struct {
+	Algorithm string
+	Value     string
 }
 
 // StatOptions holds options of the HEAD operation
@@ -71,6 +100,11 @@ type StatOptions struct {
 	timeRef    time.Time
 	versionID  string
 	isZip      bool
+	// Extended attributes and symlink targets are added to the
+	// metadata of preserved files.
+	attrPreserve bool
+	// The x-amz-checksum-* of the object are added to its metadata.
+	checksum bool
 }
 
 // ListOptions holds options for listing operation
@@ -80,10 +114,18 @@ type ListOptions struct {
 	WithMetadata      bool
 	WithOlderVersions bool
 	WithDeleteMarkers bool
+	ExcludeDeleted    bool
 	ListZip           bool
 	TimeRef           time.Time
 	ShowDir           DirOpt
 	Count             int
+	// Number of folders of a local tree, or prefixes of a bucket,
+	// listed in parallel, in no particular order, when listing
+	// recursively.
+	WalkWorkers int
+	// Skip the stat of local files when listing in parallel, their
+	// size is -1 and their modification time is unset.
+	DirentOnly bool
 }
 
 // CopyOptions holds options for copying operation
@@ -95,6 +137,7 @@ type CopyOptions struct {
 	disableMultipart bool
 	isPreserve       bool
 	storageClass     string
+	sparse           bool
 }
 
 // Client - client interface
@@ -216,6 +259,7 @@ type ClientContent struct {
 
 // Config - see http://docs.amazonwebservices.com/AmazonS3/latest/dev/index.html?RESTAuthentication.html
 type Config struct {
+	Alias             string
 	AccessKey         string
 	SecretKey         string
 	SessionToken      string
@@ -224,13 +268,19 @@ type Config struct {
 	AppName           string
 	AppVersion        string
 	Debug             bool
+	DebugLite         bool
 	Insecure          bool
 	Lookup            minio.BucketLookupType
 	ConnReadDeadline  time.Duration
 	ConnWriteDeadline time.Duration
 	UploadLimit       int64
 	DownloadLimit     int64
+	OpsLimit          int64
 	Transport         *http.Transport
+	RequestPayer      string
+	CustomHeaders     http.Header
+	CustomQuery       url.Values
+	TLS               tlsSettings
 }
 
 // SelectObjectOpts - opts entered for select API
@@ -35,6 +35,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/hookreader"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/encrypt"
@@ -76,7 +77,7 @@ func parseKey(sseKeys string) (sse string, err *probe.Error) {
 }
 
 // parse and return encryption key pairs per alias.
-func getEncKeys(ctx *cli.Context) (map[string][]prefixSSEPair, *probe.Error) {
+func getEncKeys(ctx *cli.Context) (encryptionConfig, *probe.Error) {
 	sseServer := os.Getenv("MC_ENCRYPT")
 	if prefix := ctx.String("encrypt"); prefix != "" {
 		sseServer = prefix
@@ -97,9 +98,14 @@ func getEncKeys(ctx *cli.Context) (map[string][]prefixSSEPair, *probe.Error) {
 		}
 	}
 
-	encKeyDB, err := parseAndValidateEncryptionKeys(sseKeys, sseServer)
+	sseKMS := os.Getenv("MC_ENC_KMS")
+	if kms := ctx.String("enc-kms"); kms != "" {
+		sseKMS = kms
+	}
+
+	encKeyDB, err := parseAndValidateEncryptionKeys(sseKeys, sseServer, sseKMS)
 	if err != nil {
-		return nil, err.Trace(sseKeys)
+		return nil, err.Trace(sseKeys, sseKMS)
 	}
 
 	return encKeyDB, nil
@@ -108,16 +114,29 @@ func getEncKeys(ctx *cli.Context) (map[string][]prefixSSEPair, *probe.Error) {
 // Check if the passed URL represents a folder. It may or may not exist yet.
 // If it exists, we can easily check if it is a folder, if it doesn't exist,
 // we can guess if the url is a folder from how it looks.
-func isAliasURLDir(ctx context.Context, aliasURL string, keys map[string][]prefixSSEPair, timeRef time.Time) bool {
+func isAliasURLDir(ctx context.Context, aliasURL string, keys encryptionConfig, timeRef time.Time) bool {
+	_, expandedURL, _ := mustExpandAlias(aliasURL)
+
+	// Remote lookups of the current state are cached, local ones are cheap.
+	cacheKey := ""
+	if expandedURL != aliasURL && timeRef.IsZero() {
+		cacheKey = statCacheDirKey(expandedURL)
+		if isDir, ok := getStatCache().Get(cacheKey); ok {
+			return isDir
+		}
+	}
+
 	// If the target url exists, check if it is a directory
 	// and return immediately.
 	_, targetContent, err := url2Stat(ctx, aliasURL, "", false, keys, timeRef, false)
 	if err == nil {
-		return targetContent.Type.IsDir()
+		isDir := targetContent.Type.IsDir()
+		if cacheKey != "" {
+			errorIf(getStatCache().Set(cacheKey, isDir).Trace(aliasURL), "Unable to save lookup to stat cache.")
+		}
+		return isDir
 	}
 
-	_, expandedURL, _ := mustExpandAlias(aliasURL)
-
 	// Check if targetURL is an FS or S3 aliased url
 	if expandedURL == aliasURL {
 		// This is an FS url, check if the url has a separator at the end
@@ -145,7 +164,7 @@ func isAliasURLDir(ctx context.Context, aliasURL string, keys map[string][]prefi
 }
 
 // getSourceStreamMetadataFromURL gets a reader from URL.
-func getSourceStreamMetadataFromURL(ctx context.Context, aliasedURL, versionID string, timeRef time.Time, encKeyDB map[string][]prefixSSEPair, zip bool) (reader io.ReadCloser,
+func getSourceStreamMetadataFromURL(ctx context.Context, aliasedURL, versionID string, timeRef time.Time, encKeyDB encryptionConfig, zip bool) (reader io.ReadCloser,
 	metadata map[string]string, err *probe.Error,
 ) {
 	alias, urlStrFull, _, err := expandAlias(aliasedURL)
@@ -170,12 +189,15 @@ func getSourceStreamMetadataFromURL(ctx context.Context, aliasedURL, versionID s
 
 type getSourceOpts struct {
 	GetOptions
-	fetchStat bool
-	preserve  bool
+	fetchStat    bool
+	preserve     bool
+	attrPreserve bool
+	// Ranges of the object downloaded at the same time, see partsReader.
+	downloadParts int
 }
 
 // getSourceStreamFromURL gets a reader from URL.
-func getSourceStreamFromURL(ctx context.Context, urlStr string, encKeyDB map[string][]prefixSSEPair, opts getSourceOpts) (reader io.ReadCloser, err *probe.Error) {
+func getSourceStreamFromURL(ctx context.Context, urlStr string, encKeyDB encryptionConfig, opts getSourceOpts) (reader io.ReadCloser, err *probe.Error) {
 	alias, urlStrFull, _, err := expandAlias(urlStr)
 	if err != nil {
 		return nil, err.Trace(urlStr)
@@ -263,8 +285,11 @@ func getSourceStream(ctx context.Context, alias, urlStr string, opts getSourceOp
 				st.Metadata[k] = oinfo.Metadata.Get(k)
 			}
 			st.ETag = oinfo.ETag
+			if opts.downloadParts > 1 && opts.RangeStart == 0 && opts.RangeLength == 0 && oinfo.Size > downloadPartSize {
+				reader = newPartsReader(ctx, sourceClnt, opts.GetOptions, mo, oinfo.ETag, oinfo.Size, opts.downloadParts)
+			}
 		} else {
-			st, err = sourceClnt.Stat(ctx, StatOptions{preserve: opts.preserve, sse: opts.SSE})
+			st, err = sourceClnt.Stat(ctx, StatOptions{preserve: opts.preserve, sse: opts.SSE, attrPreserve: opts.attrPreserve})
 			if err != nil {
 				return nil, nil, err.Trace(alias, urlStr)
 			}
@@ -417,10 +442,27 @@ func getAllMetadata(ctx context.Context, sourceAlias, sourceURLStr string, srcSS
 	return filterMetadata(metadata), nil
 }
 
+// isTransformedSource returns true if the source is read through an object lambda.
+func isTransformedSource(urls URLs) bool {
+	return urls.LambdaArn != "" || isObjectLambdaAlias(urls.SourceContent.URL)
+}
+
+// isServerSideCopy returns true if the source is copied by the server,
+// checksummed, compressed and encrypted uploads are streamed so that
+// the checksum, compression and encryption are computed by us and
+// conditional copies are streamed so that the source is checked. Aliases apart are copied by the server
+// only with --server-side, when they share the server and credentials.
+func isServerSideCopy(urls URLs, isZip bool) bool {
+	sameServer := urls.SourceAlias == urls.TargetAlias ||
+		urls.ServerSide && isSameServer(urls.SourceAlias, urls.TargetAlias)
+	return sameServer && !isZip && urls.Checksum == "" && urls.Compress == "" && urls.CSEKey == "" &&
+		!isTransformedSource(urls) && !urls.Conditions.IsSet()
+}
+
 // uploadSourceToTargetURL - uploads to targetURL from source.
 // optionally optimizes copy for object sizes <= 5GiB by using
 // server side copy operation.
-func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader, encKeyDB map[string][]prefixSSEPair, preserve, isZip bool) URLs {
+func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader, encKeyDB encryptionConfig, preserve, isZip bool) URLs {
 	sourceAlias := urls.SourceAlias
 	sourceURL := urls.SourceContent.URL
 	sourceVersion := urls.SourceContent.VersionID
@@ -433,6 +475,10 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 	srcSSE := getSSE(sourcePath, encKeyDB[sourceAlias])
 	tgtSSE := getSSE(targetPath, encKeyDB[targetAlias])
 
+	if urls.ServerSide && !isServerSideCopy(urls, isZip) {
+		return urls.WithError(probe.NewError(ServerSideCopyUnavailable{Source: sourcePath, Target: targetPath}))
+	}
+
 	var err *probe.Error
 	metadata := map[string]string{}
 	var mode, until, legalHold string
@@ -474,6 +520,27 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		legalHold = urls.TargetContent.LegalHold
 	}
 
+	// apply the retention and legal hold of the source object, the target
+	// bucket must have object lock enabled to keep them.
+	if urls.PreserveRetention || urls.PreserveLegalHold {
+		var srcMode, srcUntil, srcLegalHold string
+		srcMode, srcUntil, srcLegalHold, err = getSourceObjectLock(ctx, urls)
+		if err != nil {
+			return urls.WithError(err.Trace(sourceURL.String()))
+		}
+		if srcMode != "" {
+			mode, until = srcMode, srcUntil
+		}
+		if srcLegalHold != "" {
+			legalHold = srcLegalHold
+		}
+		if srcMode != "" || srcLegalHold != "" {
+			if err = checkTargetObjectLock(ctx, targetAlias, targetURL); err != nil {
+				return urls.WithError(err.Trace(targetURL.String()))
+			}
+		}
+	}
+
 	for k, v := range urls.SourceContent.UserMetadata {
 		metadata[http.CanonicalHeaderKey(k)] = v
 	}
@@ -481,8 +548,18 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		metadata[http.CanonicalHeaderKey(k)] = v
 	}
 
+	// Objects read through an object lambda are transformed on the fly,
+	// their size is only known once they are read.
+	if isTransformedSource(urls) {
+		length = -1
+	}
+	// Named pipes and devices are streamed until EOF.
+	if isSpecialFile(urls.SourceContent.Type) {
+		length = -1
+	}
+
 	// Optimize for server side copy if the host is same.
-	if sourceAlias == targetAlias && !isZip {
+	if isServerSideCopy(urls, isZip) {
 		// preserve new metadata and save existing ones.
 		if preserve {
 			currentMetadata, err := getAllMetadata(ctx, sourceAlias, sourceURL.String(), srcSSE, urls)
@@ -517,6 +594,7 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			disableMultipart: urls.DisableMultipart,
 			isPreserve:       preserve,
 			storageClass:     urls.TargetContent.StorageClass,
+			sparse:           urls.Sparse,
 		}
 
 		err = copySourceToTargetURL(ctx, targetAlias, targetURL.String(), sourcePath, sourceVersion, mode, until,
@@ -552,12 +630,16 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 		// Proceed with regular stream copy.
 		reader, metadata, err = getSourceStream(ctx, sourceAlias, sourceURL.String(), getSourceOpts{
 			GetOptions: GetOptions{
-				VersionID: sourceVersion,
-				SSE:       srcSSE,
-				Zip:       isZip,
+				VersionID:  sourceVersion,
+				SSE:        srcSSE,
+				Zip:        isZip,
+				LambdaArn:  urls.LambdaArn,
+				Conditions: urls.Conditions,
 			},
-			fetchStat: true,
-			preserve:  preserve,
+			fetchStat:     true,
+			preserve:      preserve,
+			attrPreserve:  urls.AttrPreserve,
+			downloadParts: urls.DownloadParts,
 		})
 		if err != nil {
 			return urls.WithError(err.Trace(sourceURL.String()))
@@ -574,6 +656,67 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			metadata[http.CanonicalHeaderKey(k)] = v
 		}
 
+		// Objects encrypted with --cse-key are decrypted when
+		// downloaded, progress is then told by the encrypted bytes.
+		var cse *cseKey
+		if urls.CSEKey != "" {
+			if cse, err = loadCSEKey(urls.CSEKey); err != nil {
+				return urls.WithError(err.Trace(urls.CSEKey))
+			}
+		}
+		encrypted := metadata[metadataCSEKey] != ""
+		if encrypted && cse != nil && targetURL.Type == fileSystem {
+			var stream io.Reader
+			stream, err = decryptReader(hookreader.NewHook(reader, progress), cse)
+			if err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+			delete(metadata, metadataCSEKey)
+			delete(metadata, metadataPlaintextSizeKey)
+			reader, length, progress = io.NopCloser(stream), -1, nil
+		}
+
+		// Objects compressed with --compress are decompressed when
+		// downloaded, progress is then told by the compressed bytes.
+		algo := metadata[metadataCompressionKey]
+		switch {
+		case algo != "" && targetURL.Type == fileSystem && (!encrypted || cse != nil):
+			var stream io.ReadCloser
+			stream, err = decompressReader(hookreader.NewHook(reader, progress), algo)
+			if err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+			defer stream.Close()
+			delete(metadata, metadataCompressionKey)
+			delete(metadata, metadataUncompressedSizeKey)
+			reader, length, progress = stream, -1, nil
+		case algo == "" && urls.Compress != "" && targetURL.Type != fileSystem:
+			var source io.Reader = reader
+			if length >= 0 {
+				source = io.LimitReader(reader, length)
+				metadata[metadataUncompressedSizeKey] = strconv.FormatInt(length, 10)
+			}
+			var stream io.ReadCloser
+			stream, err = compressReader(hookreader.NewHook(source, progress), urls.Compress)
+			if err != nil {
+				return urls.WithError(err.Trace(sourceURL.String()))
+			}
+			defer stream.Close()
+			metadata[metadataCompressionKey] = urls.Compress
+			reader, length, progress = stream, -1, nil
+		}
+		if !encrypted && cse != nil && targetURL.Type != fileSystem {
+			var source io.Reader = reader
+			if length >= 0 {
+				source = io.LimitReader(reader, length)
+				metadata[metadataPlaintextSizeKey] = strconv.FormatInt(length, 10)
+			}
+			stream := encryptReader(hookreader.NewHook(source, progress), cse)
+			defer stream.Close()
+			metadata[metadataCSEKey] = cseFormatOpenPGP
+			reader, length, progress = stream, -1, nil
+		}
+
 		var e error
 		var multipartSize uint64
 		if v := env.Get("MC_UPLOAD_MULTIPART_SIZE", ""); v != "" {
@@ -597,15 +740,24 @@ func uploadSourceToTargetURL(ctx context.Context, urls URLs, progress io.Reader,
 			isPreserve:       preserve,
 			multipartSize:    multipartSize,
 			multipartThreads: uint(multipartThreads),
+			sparse:           urls.Sparse,
+			attrPreserve:     urls.AttrPreserve,
+		}
+		if urls.Checksum != "" {
+			putOpts.checksum = &uploadChecksum{Algorithm: urls.Checksum}
 		}
 
-		if isReadAt(reader) {
+		_, seekable := reader.(io.ReadSeeker)
+		if isReadAt(reader) || length < 0 || (putOpts.checksum != nil && seekable) {
 			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
 				legalHold, reader, length, progress, putOpts)
 		} else {
 			_, err = putTargetStream(ctx, targetAlias, targetURL.String(), mode, until,
 				legalHold, io.LimitReader(reader, length), length, progress, putOpts)
 		}
+		if err == nil && putOpts.checksum != nil {
+			urls.ChecksumValue = putOpts.checksum.Value
+		}
 	}
 	if err != nil {
 		return urls.WithError(err.Trace(sourceURL.String()))
@@ -633,7 +785,32 @@ func newClientFromAlias(alias, urlStr string) (Client, *probe.Error) {
 		return fsClient, nil
 	}
 
+	if isGCSAlias(hostCfg) {
+		gcsClient, err := gcsNew(urlStr, hostCfg)
+		if err != nil {
+			return nil, err.Trace(alias, urlStr)
+		}
+		return gcsClient, nil
+	}
+
+	if isAzureAlias(hostCfg) {
+		azureClient, err := azureNew(urlStr, hostCfg)
+		if err != nil {
+			return nil, err.Trace(alias, urlStr)
+		}
+		return azureClient, nil
+	}
+
+	if strings.HasPrefix(hostCfg.URL, "sftp://") {
+		sftpClient, err := sftpNew(urlStr, hostCfg)
+		if err != nil {
+			return nil, err.Trace(alias, urlStr)
+		}
+		return sftpClient, nil
+	}
+
 	s3Config := NewS3Config(urlStr, hostCfg)
+	s3Config.Alias = alias
 
 	s3Client, err := S3New(s3Config)
 	if err != nil {
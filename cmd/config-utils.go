@@ -52,7 +52,7 @@ func trimTrailingSeparator(hostURL string) string {
 func isValidHostURL(hostURL string) (ok bool) {
 	if strings.TrimSpace(hostURL) != "" {
 		url := newClientURL(hostURL)
-		if url.Scheme == "https" || url.Scheme == "http" {
+		if url.Scheme == "https" || url.Scheme == "http" || url.Scheme == "sftp" {
 			if url.Path == "/" {
 				ok = true
 			}
@@ -64,7 +64,7 @@ func isValidHostURL(hostURL string) (ok bool) {
 // isValidAPI - Validates if API signature string of supported type.
 func isValidAPI(api string) (ok bool) {
 	switch strings.ToLower(api) {
-	case "s3v2", "s3v4":
+	case "s3v2", "s3v4", sftpAPI, azureAPI, gcsAPI:
 		ok = true
 	}
 	return ok
@@ -91,3 +91,8 @@ func isValidPath(path string) (ok bool) {
 	}
 	return false
 }
+
+// isValidRequestPayer - validates if request payer is of valid type
+func isValidRequestPayer(payer string) (ok bool) {
+	return strings.ToLower(strings.TrimSpace(payer)) == "requester"
+}
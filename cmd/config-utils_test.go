@@ -46,6 +46,9 @@ func TestValidHostURL(t *testing.T) {
 func TestIsValidAPI(t *testing.T) {
 	equalAssert(isValidAPI("s3V2"), true, t)
 	equalAssert(isValidAPI("S3v2"), true, t)
+	equalAssert(isValidAPI("Azure"), true, t)
+	equalAssert(isValidAPI("gcs"), true, t)
+	equalAssert(isValidAPI("sftp"), true, t)
 	equalAssert(isValidAPI("s3"), false, t)
 }
 
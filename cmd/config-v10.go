@@ -46,12 +46,19 @@ type aliasConfigV10 struct {
 	Path         string `json:"path"`
 	License      string `json:"license,omitempty"`
 	APIKey       string `json:"apiKey,omitempty"`
+	RequestPayer string `json:"requestPayer,omitempty"`
+	// Service account key file of GCS aliases.
+	CredentialsFile string `json:"credentialsFile,omitempty"`
+	// TLS baseline stricter than the default one.
+	TLS *aliasTLSConfigV10 `json:"tls,omitempty"`
 }
 
 // configV10 config version.
 type configV10 struct {
 	Version string                    `json:"version"`
 	Aliases map[string]aliasConfigV10 `json:"aliases"`
+	// Patterns of 'alias/bucket' which rb and rm refuse to remove.
+	ProtectedBuckets []string `json:"protectedBuckets,omitempty"`
 }
 
 // newConfigV10 - new config version.
@@ -311,6 +311,9 @@ func expandAliasFromEnv(envURL string) (*aliasConfigV10, *probe.Error) {
 
 // expandAlias expands aliased URL if any match is found, returns as is otherwise.
 func expandAlias(aliasedURL string) (alias, urlStr string, aliasCfg *aliasConfigV10, err *probe.Error) {
+	// Resolve paths relative to the active context.
+	aliasedURL = resolveContextPath(aliasedURL)
+
 	// Extract alias from the URL.
 	alias, path := url2Alias(aliasedURL)
 
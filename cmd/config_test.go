@@ -142,3 +142,62 @@ func TestParseEnvURLStrInvalid(t *testing.T) {
 		t.Fatalf("Expected failure")
 	}
 }
+
+func TestJoinContextPath(t *testing.T) {
+	testCases := []struct {
+		target, relPath, expected string
+	}{
+		{"prod", "", "prod/"},
+		{"prod/logs", "2024/", "prod/logs/2024/"},
+		{"prod/logs/app/", "2024/01.log", "prod/logs/app/2024/01.log"},
+	}
+	for _, testCase := range testCases {
+		if got := joinContextPath(testCase.target, testCase.relPath); got != testCase.expected {
+			t.Errorf("joinContextPath(%q, %q): expected %q, got %q", testCase.target, testCase.relPath, testCase.expected, got)
+		}
+	}
+}
+
+func TestAliasTempPolicy(t *testing.T) {
+	testCases := []struct {
+		prefixes []string
+		readOnly bool
+		expected string
+	}{
+		{
+			nil, false,
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*/*"]},` +
+				`{"Effect":"Allow","Action":["s3:*"],"Resource":["arn:aws:s3:::*"]}]}`,
+		},
+		{
+			[]string{"mybucket/reports/"}, true,
+			`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:GetObjectVersion","s3:GetObjectTagging","s3:GetObjectRetention","s3:GetObjectLegalHold"],"Resource":["arn:aws:s3:::mybucket/reports/*"]},` +
+				`{"Effect":"Allow","Action":["s3:ListBucket","s3:ListBucketVersions","s3:GetBucketLocation"],"Resource":["arn:aws:s3:::mybucket"],"Condition":{"StringLike":{"s3:prefix":["reports/*"]}}}]}`,
+		},
+	}
+	for i, testCase := range testCases {
+		if got := aliasTempPolicy(testCase.prefixes, testCase.readOnly); got != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestParseAliasTLSConfig(t *testing.T) {
+	testCases := []struct {
+		config  *aliasTLSConfigV10
+		success bool
+	}{
+		{nil, true},
+		{&aliasTLSConfigV10{MinVersion: "1.3", Curves: []string{"X25519", "P-384"}}, true},
+		{&aliasTLSConfigV10{CipherSuites: []string{"tls_ecdhe_ecdsa_with_aes_256_gcm_sha384"}}, true},
+		{&aliasTLSConfigV10{MinVersion: "1.1"}, false},
+		{&aliasTLSConfigV10{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, false},
+		{&aliasTLSConfigV10{Curves: []string{"P224"}}, false},
+	}
+	for i, testCase := range testCases {
+		_, e := parseAliasTLSConfig(testCase.config)
+		if success := e == nil; success != testCase.success {
+			t.Errorf("Test %d: expected success %t, got error %v", i+1, testCase.success, e)
+		}
+	}
+}
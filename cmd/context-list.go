@@ -0,0 +1,76 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/env"
+)
+
+var contextListCmd = cli.Command{
+	Name:            "list",
+	ShortName:       "ls",
+	Usage:           "list contexts, the active one is marked with '*'",
+	Action:          mainContextList,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	OnUsageError:    onUsageError,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}}
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. List all contexts.
+     {{.Prompt}} {{.HelpName}}
+`,
+}
+
+// mainContextList is the handle for "mc context list" command.
+func mainContextList(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...),
+			"Incorrect number of arguments for context list command.")
+	}
+
+	console.SetColor("Context", color.New(color.FgCyan, color.Bold))
+	console.SetColor("ContextTarget", color.New(color.FgYellow))
+
+	contexts, err := loadContexts()
+	fatalIf(err.Trace(), "Unable to load contexts.")
+	active := env.Get(envContext, contexts.Current)
+
+	names := make([]string, 0, len(contexts.Contexts))
+	for name := range contexts.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		printMsg(newContextMessage(name, contexts.Contexts[name], name == active))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var contextSubcommands = []cli.Command{
+	contextSetCmd,
+	contextUseCmd,
+	contextListCmd,
+	contextRemoveCmd,
+}
+
+var contextCmd = cli.Command{
+	Name:            "context",
+	Usage:           "switch between contexts binding an alias, a default bucket and prefix, and output preferences",
+	Action:          mainContext,
+	Before:          setGlobalsFromContext,
+	HideHelpCommand: true,
+	Flags:           globalFlags,
+	Subcommands:     contextSubcommands,
+}
+
+// mainContext is the handle for "mc context" command.
+func mainContext(ctx *cli.Context) error {
+	commandNotFound(ctx, contextSubcommands)
+	return nil
+	// Sub-commands like set, use, list and remove have their own main.
+}
+
+// contextMessage container for context messages
+type contextMessage struct {
+	op            string
+	Status        string `json:"status"`
+	Name          string `json:"name"`
+	Active        bool   `json:"active"`
+	Target        string `json:"target,omitempty"`
+	OutputJSON    bool   `json:"json,omitempty"`
+	OutputQuiet   bool   `json:"quiet,omitempty"`
+	OutputNoColor bool   `json:"noColor,omitempty"`
+}
+
+func newContextMessage(name string, c contextV1, active bool) contextMessage {
+	return contextMessage{
+		Status:        "success",
+		Name:          name,
+		Active:        active,
+		Target:        c.Target,
+		OutputJSON:    c.JSON,
+		OutputQuiet:   c.Quiet,
+		OutputNoColor: c.NoColor,
+	}
+}
+
+func (c contextMessage) String() string {
+	switch c.op {
+	case "set":
+		return console.Colorize("ContextMessage", "Context `"+c.Name+"` set to `"+c.Target+"`.")
+	case "remove":
+		return console.Colorize("ContextMessage", "Context `"+c.Name+"` removed.")
+	case "use":
+		if c.Name == "" {
+			return console.Colorize("ContextMessage", "No context is active.")
+		}
+		return console.Colorize("ContextMessage", "Switched to context `"+c.Name+"`, paths starting with '"+
+			contextRelativePrefix+"' refer to `"+joinContextPath(c.Target, "")+"`.")
+	}
+
+	marker := " "
+	if c.Active {
+		marker = "*"
+	}
+	var prefs []string
+	if c.OutputJSON {
+		prefs = append(prefs, "json")
+	}
+	if c.OutputQuiet {
+		prefs = append(prefs, "quiet")
+	}
+	if c.OutputNoColor {
+		prefs = append(prefs, "no-color")
+	}
+	msg := fmt.Sprintf("%s %s  %s", marker, console.Colorize("Context", c.Name), console.Colorize("ContextTarget", c.Target))
+	if len(prefs) > 0 {
+		msg += "  (" + strings.Join(prefs, ", ") + ")"
+	}
+	return msg
+}
+
+func (c contextMessage) JSON() string {
+	jsonMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var contextRemoveCmd = cli.Command{
+	Name:            "remove",
+	ShortName:       "rm",
+	Usage:           "remove a context",
+	Action:          mainContextRemove,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	OnUsageError:    onUsageError,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} NAME
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Remove the context 'staging', it is deactivated if it is active.
+     {{.Prompt}} {{.HelpName}} staging
+`,
+}
+
+// mainContextRemove is the handle for "mc context remove" command.
+func mainContextRemove(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 1 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for context remove command.")
+	}
+
+	console.SetColor("ContextMessage", color.New(color.FgGreen))
+
+	name := args.Get(0)
+	contexts, err := loadContexts()
+	fatalIf(err.Trace(), "Unable to load contexts.")
+	c, ok := contexts.Contexts[name]
+	if !ok {
+		fatalIf(errInvalidArgument().Trace(name), "No such context `"+name+"` found.")
+	}
+	delete(contexts.Contexts, name)
+	if contexts.Current == name {
+		contexts.Current = ""
+	}
+	fatalIf(contexts.save().Trace(name), "Unable to remove context `"+name+"`.")
+
+	msg := newContextMessage(name, c, false)
+	msg.op = "remove"
+	printMsg(msg)
+	return nil
+}
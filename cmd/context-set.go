@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var contextSetFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "output-json",
+		Usage: "enable JSON lines formatted output while the context is active",
+	},
+	cli.BoolFlag{
+		Name:  "output-quiet",
+		Usage: "disable progress bar display while the context is active",
+	},
+	cli.BoolFlag{
+		Name:  "output-no-color",
+		Usage: "disable color theme while the context is active",
+	},
+}
+
+var contextSetCmd = cli.Command{
+	Name:            "set",
+	Usage:           "create or replace a context",
+	Action:          mainContextSet,
+	Before:          setGlobalsFromContext,
+	Flags:           append(contextSetFlags, globalFlags...),
+	OnUsageError:    onUsageError,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] NAME ALIAS[/BUCKET[/PREFIX]]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Create a context for the logs of the production cluster, with JSON output.
+     {{.Prompt}} {{.HelpName}} --output-json prod-logs prod/logs/app/
+
+  2. Create a context for the staging cluster.
+     {{.Prompt}} {{.HelpName}} staging staging
+`,
+}
+
+// checkContextSetSyntax - verifies input arguments to 'context set'.
+func checkContextSetSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if len(args) != 2 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for context set command.")
+	}
+
+	name := args.Get(0)
+	if !isValidAlias(name) {
+		fatalIf(errInvalidArgument().Trace(name), "Invalid context name `"+name+"`.")
+	}
+
+	target := args.Get(1)
+	alias, _ := url2Alias(target)
+	if mustGetHostConfig(alias) == nil {
+		fatalIf(errInvalidAliasedURL(target).Trace(target), "No such alias `"+alias+"` found.")
+	}
+}
+
+// mainContextSet is the handle for "mc context set" command.
+func mainContextSet(ctx *cli.Context) error {
+	checkContextSetSyntax(ctx)
+
+	console.SetColor("ContextMessage", color.New(color.FgGreen))
+
+	name := ctx.Args().Get(0)
+	c := contextV1{
+		Target:  ctx.Args().Get(1),
+		JSON:    ctx.Bool("output-json"),
+		Quiet:   ctx.Bool("output-quiet"),
+		NoColor: ctx.Bool("output-no-color"),
+	}
+
+	contexts, err := loadContexts()
+	fatalIf(err.Trace(), "Unable to load contexts.")
+	contexts.Contexts[name] = c
+	fatalIf(contexts.save().Trace(name), "Unable to save context `"+name+"`.")
+
+	msg := newContextMessage(name, c, contexts.Current == name)
+	msg.op = "set"
+	printMsg(msg)
+	return nil
+}
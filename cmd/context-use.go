@@ -0,0 +1,98 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var contextUseFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "clear",
+		Usage: "deactivate the active context",
+	},
+}
+
+var contextUseCmd = cli.Command{
+	Name:            "use",
+	Usage:           "activate a context for subsequent commands",
+	Action:          mainContextUse,
+	Before:          setGlobalsFromContext,
+	Flags:           append(contextUseFlags, globalFlags...),
+	OnUsageError:    onUsageError,
+	HideHelpCommand: true,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] [NAME]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_CONTEXT:  name of the context to use instead of the active one, e.g. in a single shell
+
+DESCRIPTION:
+  While a context is active, its output preferences apply to all commands and paths
+  starting with './' refer to its alias, bucket and prefix instead of the local folder.
+  Local paths can still be given without './', e.g. 'file.txt' or 'folder/'.
+
+EXAMPLES:
+  1. Activate the context 'prod-logs' and list the logs of 2024.
+     {{.Prompt}} {{.HelpName}} prod-logs
+     {{.Prompt}} mc ls ./2024/
+
+  2. Deactivate the active context.
+     {{.Prompt}} {{.HelpName}} --clear
+`,
+}
+
+// checkContextUseSyntax - verifies input arguments to 'context use'.
+func checkContextUseSyntax(ctx *cli.Context) {
+	args := ctx.Args()
+	if ctx.Bool("clear") && len(args) != 0 || !ctx.Bool("clear") && len(args) != 1 {
+		fatalIf(errInvalidArgument().Trace(args...),
+			"Incorrect number of arguments for context use command.")
+	}
+}
+
+// mainContextUse is the handle for "mc context use" command.
+func mainContextUse(ctx *cli.Context) error {
+	checkContextUseSyntax(ctx)
+
+	console.SetColor("ContextMessage", color.New(color.FgGreen))
+
+	contexts, err := loadContexts()
+	fatalIf(err.Trace(), "Unable to load contexts.")
+
+	name := ctx.Args().Get(0)
+	c, ok := contexts.Contexts[name]
+	if name != "" && !ok {
+		fatalIf(errInvalidArgument().Trace(name), "No such context `"+name+"` found.")
+	}
+	contexts.Current = name
+	fatalIf(contexts.save().Trace(name), "Unable to save the active context.")
+
+	msg := newContextMessage(name, c, name != "")
+	msg.op = "use"
+	printMsg(msg)
+	return nil
+}
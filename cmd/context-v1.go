@@ -0,0 +1,141 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/env"
+	"github.com/minio/pkg/quick"
+)
+
+// Environment variable selecting the active context, it overrides the
+// context set with 'mc context use'.
+const envContext = "MC_CONTEXT"
+
+// contextRelativePrefix starts the paths resolved against the bucket and
+// prefix of the active context.
+const contextRelativePrefix = "./"
+
+// contextV1 binds an alias, a default bucket and prefix, and output
+// preferences.
+type contextV1 struct {
+	// ALIAS[/BUCKET[/PREFIX]] the relative paths are resolved against.
+	Target  string `json:"target"`
+	JSON    bool   `json:"json,omitempty"`
+	Quiet   bool   `json:"quiet,omitempty"`
+	NoColor bool   `json:"noColor,omitempty"`
+}
+
+// JSON file to persist the contexts and the active one.
+type contextsV1 struct {
+	Version  string               `json:"version"`
+	Current  string               `json:"current,omitempty"`
+	Contexts map[string]contextV1 `json:"contexts"`
+}
+
+// Instantiate a new contexts structure for persistence.
+func newContextsV1() *contextsV1 {
+	return &contextsV1{
+		Version:  "1",
+		Contexts: make(map[string]contextV1),
+	}
+}
+
+// loadContexts loads the contexts file, a missing file has no contexts.
+func loadContexts() (*contextsV1, *probe.Error) {
+	filename := mustGetContextsFile()
+	if _, e := os.Stat(filename); os.IsNotExist(e) {
+		return newContextsV1(), nil
+	}
+	qs, e := quick.NewConfig(newContextsV1(), nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Load(filename); e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	contexts := qs.Data().(*contextsV1)
+	if contexts.Contexts == nil {
+		contexts.Contexts = make(map[string]contextV1)
+	}
+	return contexts, nil
+}
+
+// save persists the contexts to disk.
+func (c *contextsV1) save() *probe.Error {
+	filename := mustGetContextsFile()
+	qs, e := quick.NewConfig(c, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Save(filename); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// Get contexts file name or die. (NOTE: This `Die` approach is only OK for mc like tools.).
+func mustGetContextsFile() string {
+	return filepath.Join(mustGetMcConfigDir(), globalMCContextsFile)
+}
+
+var (
+	activeContextOnce sync.Once
+	activeContext     *contextV1
+)
+
+// getActiveContext returns the context selected by MC_CONTEXT or by
+// 'mc context use', nil if there is none.
+func getActiveContext() *contextV1 {
+	activeContextOnce.Do(func() {
+		contexts, err := loadContexts()
+		// An unreadable contexts file is reported by 'mc context'.
+		if err != nil {
+			return
+		}
+		name := env.Get(envContext, contexts.Current)
+		if c, ok := contexts.Contexts[name]; ok {
+			activeContext = &c
+		}
+	})
+	return activeContext
+}
+
+// resolveContextPath resolves a path starting with './' against the
+// bucket and prefix of the active context, other paths are returned
+// as is.
+func resolveContextPath(aliasedURL string) string {
+	if !strings.HasPrefix(aliasedURL, contextRelativePrefix) {
+		return aliasedURL
+	}
+	c := getActiveContext()
+	if c == nil {
+		return aliasedURL
+	}
+	return joinContextPath(c.Target, strings.TrimPrefix(aliasedURL, contextRelativePrefix))
+}
+
+// joinContextPath joins the target of a context and a relative path.
+func joinContextPath(target, relPath string) string {
+	return strings.TrimSuffix(target, "/") + "/" + relPath
+}
@@ -0,0 +1,336 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var archiveFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "archive",
+		Usage: "pack a local folder into a single object on the fly, as 'tar', 'tar.gz' or 'tar.zst'",
+	},
+	cli.BoolFlag{
+		Name:  "extract",
+		Usage: "unpack a tar object, compressed with gzip or zstd or not, into a local folder",
+	},
+}
+
+// Archive formats of --archive.
+var archiveContentTypes = map[string]string{
+	"tar":     "application/x-tar",
+	"tar.gz":  "application/gzip",
+	"tar.zst": "application/zstd",
+}
+
+// checkArchiveSyntax validates the arguments of cp --archive and --extract.
+func checkArchiveSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 2 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--archive and --extract copy a single source to a single target.")
+	}
+	format := cliCtx.String("archive")
+	if format != "" && cliCtx.Bool("extract") {
+		fatalIf(errInvalidArgument(), "--archive and --extract cannot be used together.")
+	}
+	if _, ok := archiveContentTypes[format]; format != "" && !ok {
+		fatalIf(errInvalidArgument().Trace(format), "Unknown archive format, valid formats are 'tar', 'tar.gz' and 'tar.zst'.")
+	}
+	for _, flag := range []string{"continue", "resume", "files-from", "dry-run", "zip", "rewind"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(flag), "--%s cannot be used with --archive or --extract.", flag)
+		}
+	}
+
+	source, target := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+	local, remote := source, target
+	if cliCtx.Bool("extract") {
+		local, remote = target, source
+	}
+	_, localURL, _ := mustExpandAlias(local)
+	if newClientURL(localURL).Type != fileSystem {
+		fatalIf(errInvalidArgument().Trace(local), "The folder of an archive must be local.")
+	}
+	if strings.HasSuffix(remote, "/") {
+		fatalIf(errInvalidArgument().Trace(remote), "An archive is a single object, not a prefix.")
+	}
+	if format != "" {
+		st, e := os.Stat(localURL)
+		if e != nil || !st.IsDir() {
+			fatalIf(errInvalidArgument().Trace(local), "The source of --archive must be a local folder.")
+		}
+	}
+}
+
+// writeArchive writes the files of a folder to w as a tar stream,
+// compressed as requested by the format. It returns the number of
+// files archived.
+func writeArchive(ctx context.Context, root, format string, w io.Writer) (int64, *probe.Error) {
+	var compressor io.WriteCloser
+	switch format {
+	case "tar.gz":
+		compressor = gzip.NewWriter(w)
+	case "tar.zst":
+		enc, e := zstd.NewWriter(w)
+		if e != nil {
+			return 0, probe.NewError(e)
+		}
+		compressor = enc
+	}
+	if compressor != nil {
+		w = compressor
+	}
+
+	var files int64
+	tw := tar.NewWriter(w)
+	e := filepath.WalkDir(root, func(fpath string, d fs.DirEntry, e error) error {
+		if e != nil {
+			return e
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		name, e := filepath.Rel(root, fpath)
+		if e != nil || name == "." {
+			return e
+		}
+		if isIgnoredFile(name) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		fi, e := d.Info()
+		if e != nil {
+			return e
+		}
+		var link string
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, e = os.Readlink(fpath); e != nil {
+				return e
+			}
+		}
+		hdr, e := tar.FileInfoHeader(fi, link)
+		if e != nil {
+			// Sockets and the like are not archived.
+			return nil
+		}
+		hdr.Name = filepath.ToSlash(name)
+		if fi.IsDir() {
+			hdr.Name += "/"
+		}
+		if e = tw.WriteHeader(hdr); e != nil {
+			return e
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		f, e := os.Open(fpath)
+		if e != nil {
+			return e
+		}
+		defer f.Close()
+		if _, e = io.Copy(tw, f); e != nil {
+			return e
+		}
+		files++
+		return nil
+	})
+	if e == nil {
+		e = tw.Close()
+	}
+	if e == nil && compressor != nil {
+		e = compressor.Close()
+	}
+	if e != nil {
+		return files, probe.NewError(e).Trace(root)
+	}
+	return files, nil
+}
+
+// extractArchive unpacks a tar stream, compressed with gzip or zstd or
+// not, into a folder. Entries are never written outside of the folder.
+// It returns the number of files extracted.
+func extractArchive(ctx context.Context, r io.Reader, root string) (int64, *probe.Error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+	var reader io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, e := gzip.NewReader(br)
+		if e != nil {
+			return 0, probe.NewError(e)
+		}
+		defer gz.Close()
+		reader = gz
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		dec, e := zstd.NewReader(br)
+		if e != nil {
+			return 0, probe.NewError(e)
+		}
+		defer dec.Close()
+		reader = dec
+	}
+
+	root, e := filepath.Abs(root)
+	if e != nil {
+		return 0, probe.NewError(e)
+	}
+	if e = os.MkdirAll(root, 0o777); e != nil {
+		return 0, probe.NewError(e)
+	}
+	// The parent of an entry, symlinks resolved, must be inside the folder.
+	insideRoot := func(fpath string) bool {
+		parent, e := filepath.EvalSymlinks(filepath.Dir(fpath))
+		if e != nil {
+			return false
+		}
+		rootPath, e := filepath.EvalSymlinks(root)
+		if e != nil {
+			return false
+		}
+		return parent == rootPath || strings.HasPrefix(parent, rootPath+string(filepath.Separator))
+	}
+
+	var files int64
+	tr := tar.NewReader(reader)
+	for {
+		if ctx.Err() != nil {
+			return files, probe.NewError(ctx.Err())
+		}
+		hdr, e := tr.Next()
+		if e == io.EOF {
+			return files, nil
+		}
+		if e != nil {
+			return files, probe.NewError(e)
+		}
+		name := filepath.FromSlash(strings.TrimLeft(hdr.Name, "/"))
+		if name == "" || name == "." {
+			continue
+		}
+		fpath := filepath.Join(root, name)
+		if !strings.HasPrefix(fpath, root+string(filepath.Separator)) {
+			return files, probe.NewError(errInvalidArchiveEntry(hdr.Name))
+		}
+		if e = os.MkdirAll(filepath.Dir(fpath), 0o777); e != nil {
+			return files, probe.NewError(e)
+		}
+		if !insideRoot(fpath) {
+			return files, probe.NewError(errInvalidArchiveEntry(hdr.Name))
+		}
+
+		mode := hdr.FileInfo().Mode().Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			e = os.MkdirAll(fpath, mode|0o700)
+		case tar.TypeSymlink:
+			os.Remove(fpath)
+			e = os.Symlink(hdr.Linkname, fpath)
+		case tar.TypeReg:
+			var f *os.File
+			if f, e = os.OpenFile(fpath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode); e != nil {
+				break
+			}
+			if _, e = io.Copy(f, tr); e != nil {
+				f.Close()
+				break
+			}
+			if e = f.Close(); e != nil {
+				break
+			}
+			e = os.Chtimes(fpath, hdr.ModTime, hdr.ModTime)
+			files++
+		}
+		if e != nil {
+			return files, probe.NewError(e).Trace(fpath)
+		}
+	}
+}
+
+// errInvalidArchiveEntry is returned for entries escaping the target folder.
+func errInvalidArchiveEntry(name string) error {
+	return fmt.Errorf("archive entry `%s` is outside of the target folder", name)
+}
+
+// byteCounter counts the bytes written to it.
+type byteCounter int64
+
+func (b *byteCounter) Write(p []byte) (int, error) {
+	*b += byteCounter(len(p))
+	return len(p), nil
+}
+
+// copyArchive runs cp --archive and cp --extract.
+func copyArchive(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) {
+	source, target := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+
+	if format := cliCtx.String("archive"); format != "" {
+		_, root, _ := mustExpandAlias(source)
+		targetAlias, targetURL, _ := mustExpandAlias(target)
+
+		pr, pw := io.Pipe()
+		filesCh := make(chan int64, 1)
+		go func() {
+			files, err := writeArchive(ctx, root, format, pw)
+			filesCh <- files
+			if err != nil {
+				pw.CloseWithError(err.ToGoError())
+				return
+			}
+			pw.Close()
+		}()
+
+		opts := PutOptions{
+			metadata: map[string]string{"Content-Type": archiveContentTypes[format]},
+			sse:      getSSE(target, encKeyDB[targetAlias]),
+		}
+		size, err := putTargetStream(ctx, targetAlias, targetURL, "", "", "", pr, -1, nil, opts)
+		pr.CloseWithError(io.ErrClosedPipe)
+		files := <-filesCh
+		fatalIf(err.Trace(source, target), "Unable to archive `%s` to `%s`.", source, target)
+		printMsg(copyMessage{Source: source, Target: target, Size: size, TotalCount: files, TotalSize: size})
+		return
+	}
+
+	reader, err := getSourceStreamFromURL(ctx, source, encKeyDB, getSourceOpts{
+		GetOptions: GetOptions{VersionID: cliCtx.String("version-id")},
+	})
+	fatalIf(err.Trace(source), "Unable to read `%s`.", source)
+	defer reader.Close()
+
+	var size byteCounter
+	_, root, _ := mustExpandAlias(target)
+	files, err := extractArchive(ctx, io.TeeReader(reader, &size), root)
+	fatalIf(err.Trace(source, target), "Unable to extract `%s` to `%s`.", source, target)
+	printMsg(copyMessage{Source: source, Target: target, Size: int64(size), TotalCount: files, TotalSize: int64(size)})
+}
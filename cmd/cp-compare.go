@@ -0,0 +1,205 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+)
+
+// copyComparator returns true if an existing target of a copy is
+// already a copy of its source, for 'cp --compare'.
+type copyComparator func(ctx context.Context, urls URLs, target *ClientContent, encKeyDB encryptionConfig) (bool, *probe.Error)
+
+// copyComparators are the modes of 'cp --compare'.
+var copyComparators = map[string]copyComparator{
+	"size":     compareSize,
+	"mtime":    compareModTime,
+	"etag":     compareETag,
+	"checksum": compareChecksum,
+}
+
+// parseCopyCompare validates the --compare flag.
+func parseCopyCompare(mode string) (string, *probe.Error) {
+	mode = strings.ToLower(mode)
+	if _, ok := copyComparators[mode]; !ok {
+		modes := make([]string, 0, len(copyComparators))
+		for m := range copyComparators {
+			modes = append(modes, m)
+		}
+		sort.Strings(modes)
+		return "", errInvalidArgument().Trace(mode, strings.Join(modes, ", "))
+	}
+	return mode, nil
+}
+
+func compareSize(_ context.Context, urls URLs, target *ClientContent, _ encryptionConfig) (bool, *probe.Error) {
+	return urls.SourceContent.Size == target.Size, nil
+}
+
+// compareModTime considers a target of the same size, which is not older
+// than its source, as a copy. Modification times preserved with -a are
+// used if present.
+func compareModTime(_ context.Context, urls URLs, target *ClientContent, _ encryptionConfig) (bool, *probe.Error) {
+	if urls.SourceContent.Size != target.Size {
+		return false, nil
+	}
+	return !preservedModTime(target).Before(preservedModTime(urls.SourceContent)), nil
+}
+
+func compareETag(_ context.Context, urls URLs, target *ClientContent, _ encryptionConfig) (bool, *probe.Error) {
+	sourceETag := strings.Trim(urls.SourceContent.ETag, "\"")
+	return sourceETag != "" && sourceETag == strings.Trim(target.ETag, "\""), nil
+}
+
+// compareChecksum compares the checksums of the source and the target,
+// those stored by an S3 server are used when available, the others are
+// computed by reading the object.
+func compareChecksum(ctx context.Context, urls URLs, target *ClientContent, encKeyDB encryptionConfig) (bool, *probe.Error) {
+	if urls.SourceContent.Size != target.Size {
+		return false, nil
+	}
+	sourcePath := filepath.ToSlash(filepath.Join(urls.SourceAlias, urls.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(urls.TargetAlias, urls.TargetContent.URL.Path))
+	sourceOpts := GetOptions{SSE: getSSE(sourcePath, encKeyDB[urls.SourceAlias]), VersionID: urls.SourceContent.VersionID}
+	targetOpts := GetOptions{SSE: getSSE(targetPath, encKeyDB[urls.TargetAlias])}
+
+	sourceChecksums := serverChecksums(ctx, urls.SourceAlias, urls.SourceContent.URL.String(), sourceOpts)
+	targetChecksums := serverChecksums(ctx, urls.TargetAlias, urls.TargetContent.URL.String(), targetOpts)
+	algorithm := urls.Checksum
+	for _, a := range []string{"SHA256", "CRC32C"} {
+		if sourceChecksums[a] != "" && targetChecksums[a] != "" {
+			return sourceChecksums[a] == targetChecksums[a], nil
+		}
+		if algorithm == "" && (sourceChecksums[a] != "" || targetChecksums[a] != "") {
+			algorithm = a
+		}
+	}
+	if algorithm == "" {
+		algorithm = defaultVerifyChecksum
+	}
+
+	var err *probe.Error
+	sourceChecksum := sourceChecksums[algorithm]
+	if sourceChecksum == "" {
+		if sourceChecksum, err = hashURL(ctx, urls.SourceAlias, urls.SourceContent.URL.String(), algorithm, sourceOpts); err != nil {
+			return false, err
+		}
+	}
+	targetChecksum := targetChecksums[algorithm]
+	if targetChecksum == "" {
+		if targetChecksum, err = hashURL(ctx, urls.TargetAlias, urls.TargetContent.URL.String(), algorithm, targetOpts); err != nil {
+			return false, err
+		}
+	}
+	return sourceChecksum == targetChecksum, nil
+}
+
+// serverChecksums returns the checksums of the whole object stored by an
+// S3 server, by algorithm. Checksums of multipart uploads, which are
+// checksums of their parts, are left out.
+func serverChecksums(ctx context.Context, alias, urlStr string, opts GetOptions) map[string]string {
+	checksums := make(map[string]string, 3)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return checksums
+	}
+	s3Clnt, ok := clnt.(*S3Client)
+	if !ok {
+		return checksums
+	}
+	bucket, object := s3Clnt.url2BucketAndObject()
+	statOpts := minio.StatObjectOptions{Checksum: true, VersionID: opts.VersionID, ServerSideEncryption: opts.SSE}
+	info, e := s3Clnt.api.StatObject(ctx, bucket, object, statOpts)
+	if e != nil {
+		return checksums
+	}
+	for algorithm, value := range map[string]string{"SHA256": info.ChecksumSHA256, "SHA1": info.ChecksumSHA1, "CRC32C": info.ChecksumCRC32C} {
+		if value != "" && !strings.Contains(value, "-") {
+			checksums[algorithm] = value
+		}
+	}
+	return checksums
+}
+
+// skipUpToDateTarget returns ObjectUpToDate if the target of a copy
+// exists and is a copy of its source according to 'cp --compare'.
+func skipUpToDateTarget(ctx context.Context, urls URLs, encKeyDB encryptionConfig) *probe.Error {
+	targetPath := filepath.ToSlash(filepath.Join(urls.TargetAlias, urls.TargetContent.URL.Path))
+	clnt, err := newClientFromAlias(urls.TargetAlias, urls.TargetContent.URL.String())
+	if err != nil {
+		return err.Trace(targetPath)
+	}
+	target, err := clnt.Stat(ctx, StatOptions{sse: getSSE(targetPath, encKeyDB[urls.TargetAlias]), preserve: true})
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound, BucketDoesNotExist:
+			return nil
+		}
+		return err.Trace(targetPath)
+	}
+	if target.Type.IsDir() {
+		return nil
+	}
+	upToDate, err := copyComparators[urls.Compare](ctx, urls, target, encKeyDB)
+	if err != nil {
+		return err.Trace(targetPath)
+	}
+	if upToDate {
+		return probe.NewError(ObjectUpToDate{Object: urls.TargetContent.URL.String()})
+	}
+	return nil
+}
+
+// upToDateMessage container for targets left alone by 'cp --compare'
+type upToDateMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// String colorized up to date message
+func (u upToDateMessage) String() string {
+	return console.Colorize("NotModified", fmt.Sprintf("`%s` is up to date, skipping.", u.Target))
+}
+
+// JSON jsonified up to date message
+func (u upToDateMessage) JSON() string {
+	u.Status = "upToDate"
+	upToDateMessageBytes, e := json.MarshalIndent(u, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(upToDateMessageBytes)
+}
+
+// isErrUpToDate returns true if 'cp --compare' left a target alone.
+func isErrUpToDate(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.ToGoError().(ObjectUpToDate)
+	return ok
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var compressFlag = cli.StringFlag{
+	Name:  "compress",
+	Usage: "compress uploaded objects on the fly with 'zstd' or 'gzip', they are decompressed again when downloaded",
+}
+
+const (
+	// Algorithm an object was compressed with by --compress, and
+	// its size before compression.
+	metadataCompressionKey      = "X-Amz-Meta-Mc-Compression"
+	metadataUncompressedSizeKey = "X-Amz-Meta-Mc-Uncompressed-Size"
+)
+
+// checkCompressSyntax validates the algorithm of --compress.
+func checkCompressSyntax(cliCtx *cli.Context) {
+	switch algo := cliCtx.String("compress"); algo {
+	case "", "zstd", "gzip":
+	default:
+		fatalIf(errInvalidArgument().Trace(algo), "Unknown compression, valid values are 'zstd' and 'gzip'.")
+	}
+	if !cliCtx.IsSet("compress") {
+		return
+	}
+	// These compare the target with the source byte for byte, or by
+	// sizes not read from the metadata of the target.
+	for _, flag := range []string{"verify", "state-db", "two-way"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(flag), "--%s cannot be used with --compress.", flag)
+		}
+	}
+}
+
+// compressReader returns a reader of r compressed with algo.
+func compressReader(r io.Reader, algo string) (io.ReadCloser, *probe.Error) {
+	pr, pw := io.Pipe()
+	var compressor io.WriteCloser
+	switch algo {
+	case "gzip":
+		compressor = gzip.NewWriter(pw)
+	case "zstd":
+		enc, e := zstd.NewWriter(pw)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		compressor = enc
+	default:
+		return nil, errInvalidArgument().Trace(algo)
+	}
+	go func() {
+		_, e := io.Copy(compressor, r)
+		if ce := compressor.Close(); e == nil {
+			e = ce
+		}
+		pw.CloseWithError(e)
+	}()
+	return pr, nil
+}
+
+// decompressReader returns a reader of r decompressed with algo.
+func decompressReader(r io.Reader, algo string) (io.ReadCloser, *probe.Error) {
+	switch algo {
+	case "gzip":
+		gz, e := gzip.NewReader(r)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		return gz, nil
+	case "zstd":
+		dec, e := zstd.NewReader(r)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		return dec.IOReadCloser(), nil
+	}
+	return nil, errInvalidArgument().Trace(algo)
+}
+
+// uncompressedSize returns the size of an object before --compress
+// compressed it or --cse-key encrypted it, if they did.
+func uncompressedSize(content *ClientContent) int64 {
+	if content.UserMetadata[metadataCompressionKey] == "" {
+		return plaintextSize(content)
+	}
+	size, e := strconv.ParseInt(content.UserMetadata[metadataUncompressedSizeKey], 10, 64)
+	if e != nil {
+		return content.Size
+	}
+	return size
+}
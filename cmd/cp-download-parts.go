@@ -0,0 +1,150 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Size of the ranges of an object downloaded at the same time with
+// --download-parts, objects up to this size are downloaded at once.
+var downloadPartSize int64 = 16 << 20
+
+var downloadPartsFlag = cli.IntFlag{
+	Name:  "download-parts",
+	Usage: "download large objects from S3 as this many ranges of 16MiB at the same time",
+	Value: 1,
+}
+
+// checkDownloadPartsSyntax - returns why --download-parts cannot be used
+// with the other flags passed, empty if it can.
+func checkDownloadPartsSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.IsSet("download-parts") {
+		return ""
+	}
+	switch {
+	case cliCtx.Int("download-parts") < 1:
+		return "--download-parts must be at least 1."
+	case cliCtx.Bool("zip") || cliCtx.String("lambda-arn") != "":
+		return "--download-parts cannot be used with --zip or --lambda-arn, the objects read are not stored as is."
+	}
+	return ""
+}
+
+// downloadedPart is a range of an object downloaded by a partsReader.
+type downloadedPart struct {
+	data []byte
+	err  *probe.Error
+}
+
+// partsReader reads an object as consecutive ranges of downloadPartSize,
+// the ranges following the one being read are downloaded at the same
+// time in the background.
+type partsReader struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	current io.ReadCloser
+	parts   <-chan chan downloadedPart
+}
+
+// newPartsReader returns a reader of an object of size bytes, reading
+// parts ranges at the same time. The first range is read from first, the
+// GET of the whole object already sent, the other ranges are only read
+// if the object still has the given ETag.
+func newPartsReader(ctx context.Context, clnt Client, opts GetOptions, first io.ReadCloser, etag string, size int64, parts int) io.ReadCloser {
+	ctx, cancel := context.WithCancel(ctx)
+	opts.Conditions = GetConditions{IfMatch: etag}
+	partSize := downloadPartSize
+
+	partsCh := make(chan chan downloadedPart, parts-1)
+	go func() {
+		defer close(partsCh)
+		for start := partSize; start < size; start += partSize {
+			partCh := make(chan downloadedPart, 1)
+			select {
+			case partsCh <- partCh:
+			case <-ctx.Done():
+				return
+			}
+			length := size - start
+			if length > partSize {
+				length = partSize
+			}
+			go func(start, length int64) {
+				partCh <- downloadPart(ctx, clnt, opts, start, length)
+			}(start, length)
+		}
+	}()
+	return &partsReader{
+		ctx:     ctx,
+		cancel:  cancel,
+		current: limitReadCloser(first, partSize),
+		parts:   partsCh,
+	}
+}
+
+// downloadPart downloads length bytes of an object from start.
+func downloadPart(ctx context.Context, clnt Client, opts GetOptions, start, length int64) downloadedPart {
+	opts.RangeStart, opts.RangeLength = start, length
+	reader, err := clnt.Get(ctx, opts)
+	if err != nil {
+		return downloadedPart{err: err.Trace(clnt.GetURL().String())}
+	}
+	defer reader.Close()
+	data := make([]byte, length)
+	if _, e := io.ReadFull(reader, data); e != nil {
+		return downloadedPart{err: probe.NewError(e).Trace(clnt.GetURL().String())}
+	}
+	return downloadedPart{data: data}
+}
+
+func (r *partsReader) Read(p []byte) (int, error) {
+	for {
+		n, e := r.current.Read(p)
+		if e != io.EOF {
+			return n, e
+		}
+		r.current.Close()
+		partCh, ok := <-r.parts
+		if !ok {
+			if e := r.ctx.Err(); e != nil {
+				return n, e
+			}
+			return n, io.EOF
+		}
+		part := <-partCh
+		if part.err != nil {
+			return n, part.err.ToGoError()
+		}
+		r.current = io.NopCloser(bytes.NewReader(part.data))
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+// Close stops the downloads of the ranges not read yet.
+func (r *partsReader) Close() error {
+	r.cancel()
+	return r.current.Close()
+}
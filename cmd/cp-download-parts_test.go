@@ -0,0 +1,90 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckDownloadPartsSyntax(t *testing.T) {
+	flags := []cli.Flag{downloadPartsFlag, cli.BoolFlag{Name: "zip"}, cli.StringFlag{Name: "lambda-arn"}}
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket/object", "/tmp/"}, true},
+		{[]string{"--download-parts", "8", "s3/bucket/object", "/tmp/"}, true},
+		{[]string{"--download-parts", "0", "s3/bucket/object", "/tmp/"}, false},
+		{[]string{"--download-parts", "8", "--zip", "s3/bucket/archive.zip/object", "/tmp/"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkDownloadPartsSyntax(newTestCLIContext(t, flags, testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestPartsReader(t *testing.T) {
+	defer func(size int64) { downloadPartSize = size }(downloadPartSize)
+	downloadPartSize = 10
+
+	fpath := filepath.Join(t.TempDir(), "object")
+	data := []byte("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	if e := os.WriteFile(fpath, data, 0o644); e != nil {
+		t.Fatal(e)
+	}
+	clnt, err := fsNew(fpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, parts := range []int{2, 4, 16} {
+		first, err := clnt.Get(context.Background(), GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader := newPartsReader(context.Background(), clnt, GetOptions{}, first, "", int64(len(data)), parts)
+		got, e := io.ReadAll(reader)
+		reader.Close()
+		if e != nil {
+			t.Fatalf("%d parts: %v", parts, e)
+		}
+		if !bytes.Equal(got, data) {
+			t.Errorf("%d parts: expected %s, got %s", parts, data, got)
+		}
+	}
+
+	// A range missing from the object fails the read.
+	first, err := clnt.Get(context.Background(), GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := newPartsReader(context.Background(), clnt, GetOptions{}, first, "", int64(len(data))+5, 2)
+	defer reader.Close()
+	if _, e := io.ReadAll(reader); e == nil {
+		t.Error("Expected a read past the end of the object to fail")
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/hookreader"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var fanOutFlag = cli.StringSliceFlag{
+	Name:  "targets, target",
+	Usage: "copy a single object to several comma separated targets, reading it only once",
+}
+
+// fanOutTargets returns the targets of --targets, which may be repeated
+// or comma separated.
+func fanOutTargets(cliCtx *cli.Context) (targets []string) {
+	for _, value := range cliCtx.StringSlice("targets") {
+		for _, target := range strings.Split(value, ",") {
+			if target = strings.TrimSpace(target); target != "" {
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets
+}
+
+// checkFanOutSyntax validates the arguments of cp --targets.
+func checkFanOutSyntax(cliCtx *cli.Context) {
+	if len(cliCtx.Args()) != 1 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--targets copies a single source, the targets are not arguments.")
+	}
+	if len(fanOutTargets(cliCtx)) == 0 {
+		fatalIf(errInvalidArgument(), "--targets requires at least one target.")
+	}
+	for _, flag := range []string{"recursive", "continue", "resume", "files-from", "archive", "extract", "zip", "rewind", "dry-run"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(flag), "--%s cannot be used with --targets.", flag)
+		}
+	}
+}
+
+// fanOutWriter writes to the pipes of every target, a target which
+// failed is left out without failing the others.
+type fanOutWriter struct {
+	pipes  []*io.PipeWriter
+	failed []bool
+}
+
+func newFanOutWriter(pipes []*io.PipeWriter) *fanOutWriter {
+	return &fanOutWriter{pipes: pipes, failed: make([]bool, len(pipes))}
+}
+
+// Write implements io.Writer, it only fails once every target failed.
+func (f *fanOutWriter) Write(p []byte) (int, error) {
+	var e error = io.ErrClosedPipe
+	for i, pipe := range f.pipes {
+		if f.failed[i] {
+			continue
+		}
+		if _, we := pipe.Write(p); we != nil {
+			f.failed[i] = true
+			continue
+		}
+		e = nil
+	}
+	if e != nil {
+		return 0, e
+	}
+	return len(p), nil
+}
+
+// CloseWithError closes the pipes of every target, with e if not nil.
+func (f *fanOutWriter) CloseWithError(e error) {
+	for _, pipe := range f.pipes {
+		pipe.CloseWithError(e)
+	}
+}
+
+// fanOutTargetURL returns the object a target of --targets is written
+// to, the source is copied inside targets which are folders.
+func fanOutTargetURL(source, target string) string {
+	if strings.HasSuffix(target, "/") {
+		return target + path.Base(source)
+	}
+	_, targetURL, _ := mustExpandAlias(target)
+	if newClientURL(targetURL).Type == fileSystem {
+		if st, e := os.Stat(targetURL); e == nil && st.IsDir() {
+			return path.Join(target, path.Base(source))
+		}
+	}
+	return target
+}
+
+// copyFanOut runs cp --targets, the source is read once and written to
+// every target in parallel. Every target is reported on its own.
+func copyFanOut(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) error {
+	source := cliCtx.Args().Get(0)
+	versionID := cliCtx.String("version-id")
+
+	_, content, err := url2Stat(ctx, source, versionID, false, encKeyDB, time.Time{}, false)
+	fatalIf(err.Trace(source), "Unable to stat `%s`.", source)
+	if content.Type.IsDir() {
+		fatalIf(errInvalidArgument().Trace(source), "--targets copies a single object, not a folder.")
+	}
+
+	sourceAlias, sourceURL, _ := mustExpandAlias(source)
+	reader, metadata, err := getSourceStream(ctx, sourceAlias, sourceURL, getSourceOpts{
+		GetOptions: GetOptions{
+			VersionID: versionID,
+			SSE:       getSSE(source, encKeyDB[sourceAlias]),
+		},
+		fetchStat: true,
+	})
+	fatalIf(err.Trace(source), "Unable to read `%s`.", source)
+	defer reader.Close()
+
+	var targets []string
+	for _, target := range fanOutTargets(cliCtx) {
+		targets = append(targets, fanOutTargetURL(source, target))
+	}
+
+	var pg ProgressReader
+	if !globalQuiet && !globalJSON {
+		pg = newProgressBar(content.Size)
+	} else {
+		pg = newAccounter(content.Size)
+	}
+
+	pipes := make([]*io.PipeWriter, len(targets))
+	errs := make([]*probe.Error, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		pr, pw := io.Pipe()
+		pipes[i] = pw
+		wg.Add(1)
+		go func(i int, target string, pr *io.PipeReader) {
+			defer wg.Done()
+			targetAlias, targetURL, _ := mustExpandAlias(target)
+			opts := PutOptions{
+				metadata:         filterMetadata(metadata),
+				sse:              getSSE(target, encKeyDB[targetAlias]),
+				storageClass:     cliCtx.String("storage-class"),
+				md5:              cliCtx.Bool("md5"),
+				disableMultipart: cliCtx.Bool("disable-multipart"),
+			}
+			_, errs[i] = putTargetStream(ctx, targetAlias, targetURL, "", "", "", pr, content.Size, nil, opts)
+			if errs[i] != nil {
+				// Leaves this target out of the following writes.
+				pr.CloseWithError(errs[i].ToGoError())
+				return
+			}
+			pr.Close()
+		}(i, target, pr)
+	}
+
+	fw := newFanOutWriter(pipes)
+	_, e := io.Copy(fw, hookreader.NewHook(reader, pg))
+	fw.CloseWithError(e)
+	wg.Wait()
+
+	if bar, ok := pg.(*progressBar); ok {
+		bar.ProgressBar.Finish()
+	}
+
+	var retErr error
+	for i, target := range targets {
+		if errs[i] == nil && e != nil {
+			errs[i] = probe.NewError(e)
+		}
+		if errs[i] != nil {
+			errorIf(errs[i].Trace(source, target), "Unable to copy `%s` to `%s`.", source, target)
+			retErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(copyMessage{
+			Source:     source,
+			Target:     target,
+			Size:       content.Size,
+			TotalCount: int64(len(targets)),
+			TotalSize:  content.Size * int64(len(targets)),
+		})
+	}
+	return retErr
+}
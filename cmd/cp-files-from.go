@@ -0,0 +1,142 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var filesFromFlag = cli.StringFlag{
+	Name:  "files-from",
+	Usage: "copy only the keys listed in a newline or NUL delimited file ('-' for stdin), relative to the source, without listing it",
+}
+
+// manifestSplitter returns a bufio.SplitFunc reading the keys of a
+// --files-from manifest. Keys are delimited by newlines, unless a NUL is
+// found in the first chunk read, then by NULs only so that keys may
+// contain newlines.
+func manifestSplitter() bufio.SplitFunc {
+	var decided, nulDelimited bool
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if !decided && (len(data) > 0 || atEOF) {
+			nulDelimited = bytes.IndexByte(data, 0) >= 0
+			decided = true
+		}
+		delim := byte('\n')
+		if nulDelimited {
+			delim = 0
+		}
+		if i := bytes.IndexByte(data, delim); i >= 0 {
+			return i + 1, data[:i], nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	}
+}
+
+// readManifestKeys sends the keys of a --files-from manifest, blank
+// lines are skipped and the './' prefix printed by find is removed.
+func readManifestKeys(ctx context.Context, r io.Reader, keysCh chan<- string) *probe.Error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	scanner.Split(manifestSplitter())
+	for scanner.Scan() {
+		key := strings.TrimSuffix(scanner.Text(), "\r")
+		key = strings.TrimLeft(strings.TrimPrefix(key, "./"), "/")
+		if strings.TrimSpace(key) == "" {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return probe.NewError(ctx.Err())
+		case keysCh <- key:
+		}
+	}
+	if e := scanner.Err(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// prepareCopyURLsFilesFrom prepares the copy of the keys listed by a
+// --files-from manifest from the source folder to the target folder.
+// The source is not listed, every key is stat'ed unless its size is not
+// needed (see ListOptions.DirentOnly), then by the copy workers. Keys
+// which cannot be stat'ed are left to fail in the copy workers.
+func prepareCopyURLsFilesFrom(ctx context.Context, o prepareCopyURLsOpts) <-chan URLs {
+	copyURLsCh := make(chan URLs)
+	go func() {
+		defer close(copyURLsCh)
+
+		var r io.Reader = os.Stdin
+		if o.filesFrom != "-" {
+			f, e := os.Open(o.filesFrom)
+			if e != nil {
+				copyURLsCh <- URLs{Error: probe.NewError(e).Trace(o.filesFrom)}
+				return
+			}
+			defer f.Close()
+			r = f
+		}
+
+		sourceAlias, sourceURL, _ := mustExpandAlias(o.sourceURLs[0])
+		targetAlias, targetURL, _ := mustExpandAlias(o.targetURL)
+
+		keysCh := make(chan string)
+		errCh := make(chan *probe.Error, 1)
+		go func() {
+			defer close(keysCh)
+			errCh <- readManifestKeys(ctx, r, keysCh)
+		}()
+		for key := range keysCh {
+			// Skip keys not selected by --include and --exclude.
+			if !o.pathFilter.Match(key) {
+				continue
+			}
+			sourcePath := urlJoinPath(sourceURL, key)
+			sourceContent := &ClientContent{URL: *newClientURL(sourcePath), Size: -1}
+			if !o.direntOnly {
+				if clnt, err := newClientFromAlias(sourceAlias, sourcePath); err == nil {
+					if st, err := clnt.Stat(ctx, StatOptions{sse: getSSE(sourcePath, o.encKeyDB[sourceAlias])}); err == nil && st.Type.IsRegular() {
+						sourceContent = st
+					}
+				}
+			}
+			targetKey := o.nameTransform.Apply(key)
+			if targetKey == "" {
+				// The whole key is removed by --strip-components.
+				continue
+			}
+			copyURLsCh <- makeCopyContentTypeA(sourceAlias, sourceContent, targetAlias, urlJoinPath(targetURL, targetKey))
+		}
+		if err := <-errCh; err != nil {
+			copyURLsCh <- URLs{Error: err.Trace(o.filesFrom)}
+		}
+	}()
+	return copyURLsCh
+}
@@ -25,7 +25,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -64,21 +66,34 @@ var (
 			Usage: "set storage class for new object(s) on target",
 		},
 		cli.StringFlag{
-			Name:  "encrypt",
+			Name:  "encrypt, enc-s3",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
 		},
+		encKMSFlag,
 		cli.StringFlag{
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
 		},
+		cli.StringFlag{
+			Name:  "header-map",
+			Usage: "file mapping object name patterns to headers such as Cache-Control, overridden by --attr",
+		},
 		cli.BoolFlag{
 			Name:  "continue, c",
 			Usage: "create or resume copy session",
 		},
+		cli.BoolFlag{
+			Name:  "resume",
+			Usage: "create or resume copy session, skipping every object copied before it was interrupted",
+		},
 		cli.BoolFlag{
 			Name:  "preserve, a",
 			Usage: "preserve filesystem attributes (mode, ownership, timestamps)",
 		},
+		cli.BoolFlag{
+			Name:  "attr-preserve",
+			Usage: "preserve all POSIX attributes, implies --preserve and adds extended attributes and symlink targets",
+		},
 		cli.BoolFlag{
 			Name:  "disable-multipart",
 			Usage: "disable multipart upload feature",
@@ -87,6 +102,34 @@ var (
 			Name:  "md5",
 			Usage: "force all upload(s) to calculate md5sum checksum",
 		},
+		cli.StringFlag{
+			Name:  "checksum",
+			Usage: "upload with an additional checksum, one of CRC32C, SHA1, SHA256 or CRC64NVME",
+		},
+		cli.BoolFlag{
+			Name:  "versions",
+			Usage: "copy every version and delete marker of the objects, from the oldest",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "list the objects to copy with their total size, without copying",
+		},
+		cli.BoolFlag{
+			Name:  "verify",
+			Usage: "read back every copied object and compare its checksum against the source",
+		},
+		cli.StringFlag{
+			Name:  "lambda-arn",
+			Usage: "read sources through the object lambda (transform) function of this ARN (MinIO servers only)",
+		},
+		cli.BoolFlag{
+			Name:  "sparse",
+			Usage: "leave holes for runs of zeros when writing to a local filesystem",
+		},
+		cli.BoolFlag{
+			Name:  "special-files",
+			Usage: "copy a named pipe or a character or block device given as source, streaming it until EOF",
+		},
 		cli.StringFlag{
 			Name:  "tags",
 			Usage: "apply one or more tags to the uploaded objects",
@@ -107,6 +150,31 @@ var (
 			Name:  "zip",
 			Usage: "Extract from remote zip file (MinIO server source only)",
 		},
+		cli.BoolFlag{
+			Name:  "no-clobber, skip-existing",
+			Usage: "never overwrite existing objects on target",
+		},
+		cli.IntFlag{
+			Name:  "list-workers",
+			Usage: "number of folders or prefixes listed in parallel by recursive copies, in no particular order",
+		},
+		maxDepthFlag,
+		cli.StringFlag{
+			Name:  "compare",
+			Usage: "skip objects whose target is already a copy, compared by 'size', 'mtime', 'etag' or 'checksum'",
+		},
+		cli.BoolFlag{
+			Name:  "target-is-dir",
+			Usage: "treat the target as a folder, even if it does not exist or has no trailing slash",
+		},
+		cli.BoolFlag{
+			Name:  "target-is-file",
+			Usage: "treat the target as a file, even if it has a trailing slash",
+		},
+		cli.StringFlag{
+			Name:  "backup-existing",
+			Usage: "copy existing objects on target to a timestamped key ending with this suffix before overwriting them",
+		},
 	}
 )
 
@@ -126,19 +194,27 @@ var cpCmd = cli.Command{
 	Action:       mainCopy,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(cpFlags, ioFlags...), globalFlags...),
+	Flags: joinFlags(cpFlags, getConditionFlags,
+		[]cli.Flag{statsFlag, stallTimeoutFlag, settleDurationFlag, filterFlag, filesFromFlag, compressFlag, cseKeyFlag, fanOutFlag, progressFlag, nameTransformFlag, stripComponentsFlag, serverSideFlag, downloadPartsFlag},
+		pathFilterFlags, objectLockFlags, archiveFlags, retryFlags, referenceFileFlags, ioFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
 USAGE:
   {{.HelpName}} [FLAGS] SOURCE [SOURCE...] TARGET
+  {{.HelpName}} [FLAGS] --targets TARGET[,TARGET...] SOURCE
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT:      list of comma delimited prefixes
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT:              list of comma delimited prefixes
+  MC_ENCRYPT_KEY:          list of comma delimited prefix=secret values
+  MC_ENC_KMS:              list of comma delimited prefix=keyid values
+  MC_STAT_CACHE_TTL:       remember bucket and folder lookups on disk for this long, e.g. "30s"
+  MC_FS_WALK_WORKERS:      number of local folders read in parallel by recursive copies, e.g. "16"
+  MC_SERVER_SIDE_WORKERS:  number of objects copied in parallel by --server-side, "256" by default
+  MC_CSE_PASSPHRASE:       passphrase of the OpenPGP secret keys of --cse-key
 
 EXAMPLES:
   01. Copy a list of objects from local file system to Amazon S3 cloud storage.
@@ -202,6 +278,142 @@ EXAMPLES:
   20. Set tags to the uploaded objects
       {{.Prompt}} {{.HelpName}} -r --tags "category=prod&type=backup" ./data/ play/another-bucket/
 
+  21. Copy a folder recursively from a Requester Pays bucket on Amazon S3 to a local path, paying for the requests.
+      {{.Prompt}} {{.HelpName}} --recursive --request-payer requester s3/datasets/2023/ ~/datasets/
+
+  22. Copy a folder recursively, uploading every object with a SHA256 checksum.
+      {{.Prompt}} {{.HelpName}} --recursive --checksum SHA256 ~/records/ s3/compliance/records/
+
+  23. Download an object transformed by a MinIO object lambda function.
+      {{.Prompt}} {{.HelpName}} --lambda-arn "arn:minio:s3-object-lambda::redact:webhook" myminio/crm/customers.csv /tmp/
+
+  24. Download a virtual machine image as a sparse file, so that runs of zeros take no space on disk.
+      {{.Prompt}} {{.HelpName}} --sparse s3/images/ubuntu-22.04.qcow2 /var/lib/libvirt/images/
+
+  25. Copy a folder recursively and print a summary of objects, bytes, throughput and failures at the end.
+      {{.Prompt}} {{.HelpName}} --recursive --stats ~/photos/ s3/photos/
+
+  26. Download an object only if it was modified in the last 24 hours.
+      {{.Prompt}} {{.HelpName}} --if-modified-since 24h s3/reports/daily.csv /tmp/daily.csv
+
+  27. Copy a folder recursively over a flaky link, retrying objects whose transfer makes no progress for 30 seconds.
+      {{.Prompt}} {{.HelpName}} --recursive --stall-timeout 30s ~/backups/ s3/backups/
+
+  28. Deploy a static site with pre-compressed scripts, using a mapping file with lines such as
+      '*.js Content-Encoding=gzip;Cache-Control=max-age=31536000' and '*.html Cache-Control=no-cache'.
+      {{.Prompt}} {{.HelpName}} --recursive --header-map headers.txt site/ s3/www/
+
+  29. Copy log files larger than 1MiB and older than 30 days to an archive bucket.
+      {{.Prompt}} {{.HelpName}} --recursive --filter 'size > 1MiB && name ~ "*.log" && age > 30d' s3/logs/ s3/archive/
+
+  30. Copy a folder recursively, leaving the objects which already exist on the target untouched.
+      {{.Prompt}} {{.HelpName}} --recursive --no-clobber ~/photos/ s3/photos/
+
+  31. Copy a folder recursively, keeping each overwritten object as e.g. 'config.yaml.20230102T150405Z.bak'.
+      {{.Prompt}} {{.HelpName}} --recursive --backup-existing .bak ~/configs/ s3/configs/
+
+  32. Copy an object into a folder which does not exist yet, 'report.pdf' is uploaded as 'reports/2023/report.pdf'.
+      {{.Prompt}} {{.HelpName}} --target-is-dir report.pdf s3/mybucket/reports/2023
+
+  33. Copy a local project recursively, skipping the files and folders listed in its gitignore-style '.mcignore' files.
+      {{.Prompt}} {{.HelpName}} --recursive ~/myproject/ s3/mybucket/myproject/
+
+  34. Upload a local tree of millions of small files, reading its folders in parallel.
+      {{.Prompt}} MC_FS_WALK_WORKERS=32 {{.HelpName}} --recursive --quiet /data/images/ s3/mybucket/images/
+
+  35. Copy the files of a folder written by an active producer, leaving those modified within the last 30 seconds
+      for the next copy.
+      {{.Prompt}} {{.HelpName}} --recursive --settle-duration 30s /var/spool/exports/ s3/exports/
+
+  36. Copy a large folder, run the same command again after an interruption to copy only the remaining files.
+      {{.Prompt}} {{.HelpName}} --recursive --resume dir/ play/mybucket
+
+  37. Copy the CSV files of a prefix except those under "tmp/", the last pattern matching a path decides.
+      {{.Prompt}} {{.HelpName}} --recursive --include "*.csv" --exclude "tmp/*" play/mybucket/data/ /mnt/data/
+
+  38. Copy a folder and read back every object, failing the copies whose SHA256 checksum differs from the source.
+      {{.Prompt}} {{.HelpName}} --recursive --verify --checksum SHA256 ~/records/ s3/archive/records/
+
+  39. List the objects a filtered copy would transfer, with their count and total size, without copying them.
+      {{.Prompt}} {{.HelpName}} --recursive --dry-run --older-than 30d --exclude "*.tmp" s3/logs/ /mnt/archive/logs/
+
+  40. Copy every version of the objects of a bucket to a versioned bucket, keeping their order and delete markers.
+      {{.Prompt}} {{.HelpName}} --recursive --versions s3/records/ backup/records/
+
+  41. Copy a folder without saturating the office link, all concurrent uploads together stay under 10MiB/s.
+      {{.Prompt}} {{.HelpName}} --recursive --limit-upload 10MiB/s ~/videos/ s3/videos/
+
+  42. Copy the keys listed in a file out of a large bucket, without listing it.
+      {{.Prompt}} {{.HelpName}} --files-from keys.txt s3/datalake/ /mnt/restore/
+
+  43. Copy the photos modified since the last backup, listed NUL delimited by find on stdin.
+      {{.Prompt}} cd ~/photos && find . -name "*.jpg" -newer .last-backup -print0 | {{.HelpName}} --files-from - ./ s3/photos/
+
+  44. Copy a folder again, skipping the files already copied to the target.
+      {{.Prompt}} {{.HelpName}} --recursive --skip-existing ~/photos/ s3/photos/
+
+  45. Copy a folder again, copying only the files changed since, as told by their checksums.
+      {{.Prompt}} {{.HelpName}} --recursive --compare checksum ~/records/ s3/records/
+
+  46. Copy a bucket of millions of objects, listing 16 of its prefixes at a time.
+      {{.Prompt}} {{.HelpName}} --recursive --list-workers 16 s3/datalake/ backup/datalake/
+
+  47. Back up a home folder with its symlinks and extended attributes, then restore it.
+      {{.Prompt}} {{.HelpName}} --recursive --attr-preserve /home/alice/ s3/backup/alice/
+      {{.Prompt}} {{.HelpName}} --recursive --attr-preserve s3/backup/alice/ /home/alice/
+
+  48. Pack a folder of many small files into a single zstd compressed tar object, then unpack it.
+      {{.Prompt}} {{.HelpName}} --archive tar.zst ~/datasets/thumbnails s3/archives/thumbnails.tar.zst
+      {{.Prompt}} {{.HelpName}} --extract s3/archives/thumbnails.tar.zst /mnt/restore/thumbnails
+
+  49. Upload a folder of logs compressed with zstd, then download them decompressed again.
+      {{.Prompt}} {{.HelpName}} --recursive --compress zstd /var/log/nginx/ s3/logs/nginx/
+      {{.Prompt}} {{.HelpName}} --recursive s3/logs/nginx/ /tmp/nginx/
+
+  50. Copy a folder over a flaky link, retrying every failed object up to 5 times, then copy the objects which
+      still failed again.
+      {{.Prompt}} {{.HelpName}} --recursive --retry 5 --retry-delay 2s --failed-log failed.txt ~/scans/ s3/scans/
+      {{.Prompt}} {{.HelpName}} --retry 5 --files-from failed.txt ~/scans/ s3/scans/
+
+  51. Export the files changed since the last export, as told by a marker file touched after every export.
+      {{.Prompt}} {{.HelpName}} --recursive --newer-than-file ~/.last-export ~/reports/ s3/reports/ && touch ~/.last-export
+
+  52. Seed three clusters with a large image, reading it only once.
+      {{.Prompt}} {{.HelpName}} --targets site1/images/,site2/images/,site3/images/ ~/images/rocky-9.iso
+
+  53. Copy a folder from a CI job, printing the progress as JSON lines events for the job to render.
+      {{.Prompt}} {{.HelpName}} --recursive --progress json build/artifacts/ s3/artifacts/
+
+  54. Migrate a bucket, moving its logs under an archive prefix and its dated folders to year/month ones.
+      {{.Prompt}} {{.HelpName}} --recursive --name-transform 's|^logs/|archive/logs/|' --name-transform 's|^([0-9]{4})-([0-9]{2})/|\1/\2/|' old/data/ new/data/
+
+  55. Copy millions of objects between two buckets of the same server, by the server only.
+      {{.Prompt}} {{.HelpName}} --recursive --server-side myminio/raw/ myminio/curated/
+
+  56. Copy the objects of a locked bucket with their retention and legal hold to another locked bucket.
+      {{.Prompt}} {{.HelpName}} --recursive --preserve-retention --preserve-legalhold s3/records/ myminio/records/
+
+  57. Restore a database dump of several GiB over a slow link, downloading 16 ranges of it at the same time.
+      {{.Prompt}} {{.HelpName}} --download-parts 16 s3/mysql-backups/dump-2023-05-01.sql.gz /restore/
+
+  58. Upload the image of a disk and the output of a command written to stdin, streaming them until EOF.
+      {{.Prompt}} {{.HelpName}} --special-files /dev/sdb s3/images/sdb.img
+      {{.Prompt}} tar -cf - /etc | {{.HelpName}} --special-files /dev/stdin s3/backups/etc.tar
+
+  59. Upload a folder encrypted on the client for an OpenPGP public key, then download it decrypted with the secret key.
+      {{.Prompt}} {{.HelpName}} --recursive --cse-key backup-public.asc /srv/records/ s3/records/
+      {{.Prompt}} {{.HelpName}} --recursive --cse-key backup-secret.asc s3/records/ /restore/records/
+
+
+  60. Copy a folder to a bucket, encrypting the objects of a prefix with the KMS key "my-minio-key" and the others
+      with server managed keys.
+      {{.Prompt}} {{.HelpName}} --recursive --enc-kms "s3/records/private/=my-minio-key" --enc-s3 "s3/records/" /srv/records/ s3/records/
+
+  61. Copy a folder recursively with a CRC64NVME checksum of every object, listing the checksums in JSON.
+      {{.Prompt}} {{.HelpName}} --recursive --checksum CRC64NVME --json /srv/records/ s3/records/
+
+  62. Migrate the objects under "backup/2023/data/" of a bucket to the top of another, without the leading "data" folder.
+      {{.Prompt}} {{.HelpName}} --recursive --strip-components 1 s3/old/backup/2023/data s3/new/
 `,
 }
 
@@ -213,10 +425,22 @@ type copyMessage struct {
 	Size       int64  `json:"size"`
 	TotalCount int64  `json:"totalCount"`
 	TotalSize  int64  `json:"totalSize"`
+	Checksum   string `json:"checksum,omitempty"`
+	Verified   bool   `json:"verified,omitempty"`
+	DryRun     bool   `json:"dryRun,omitempty"`
 }
 
 // String colorized copy message
 func (c copyMessage) String() string {
+	if c.DryRun {
+		return "DRYRUN: Copying " + console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s` (%s)", c.Source, c.Target, humanize.IBytes(uint64(c.Size))))
+	}
+	if c.Verified {
+		return console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s` (%s, verified)", c.Source, c.Target, c.Checksum))
+	}
+	if c.Checksum != "" {
+		return console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s` (%s)", c.Source, c.Target, c.Checksum))
+	}
 	return console.Colorize("Copy", fmt.Sprintf("`%s` -> `%s`", c.Source, c.Target))
 }
 
@@ -229,6 +453,136 @@ func (c copyMessage) JSON() string {
 	return string(copyMessageBytes)
 }
 
+// notModifiedMessage container for objects left alone by a conditional read
+type notModifiedMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"`
+}
+
+// String colorized not modified message
+func (n notModifiedMessage) String() string {
+	return console.Colorize("NotModified", fmt.Sprintf("`%s` not modified, skipping.", n.Source))
+}
+
+// JSON jsonified not modified message
+func (n notModifiedMessage) JSON() string {
+	n.Status = "notModified"
+	notModifiedMessageBytes, e := json.MarshalIndent(n, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(notModifiedMessageBytes)
+}
+
+// isErrNotModified returns true if a conditional read found the
+// source object unchanged.
+func isErrNotModified(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.ToGoError().(ObjectNotModified)
+	return ok
+}
+
+// targetExistsMessage container for objects left alone by --no-clobber
+type targetExistsMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// String colorized target exists message
+func (t targetExistsMessage) String() string {
+	return console.Colorize("NotModified", fmt.Sprintf("`%s` already exists, skipping.", t.Target))
+}
+
+// JSON jsonified target exists message
+func (t targetExistsMessage) JSON() string {
+	t.Status = "exists"
+	targetExistsMessageBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(targetExistsMessageBytes)
+}
+
+// isErrTargetExists returns true if --no-clobber left an existing
+// target alone.
+func isErrTargetExists(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.ToGoError().(ObjectAlreadyExists)
+	return ok
+}
+
+// backupKeyTimeFormat is the format of the timestamp in the keys of
+// objects saved by --backup-existing.
+const backupKeyTimeFormat = "20060102T150405Z"
+
+// protectExistingTarget applies --no-clobber and --backup-existing to
+// the target of a copy: an existing target is either left alone, which
+// returns ObjectAlreadyExists, or copied to a timestamped key before
+// it is overwritten.
+func protectExistingTarget(ctx context.Context, urls URLs, encKeyDB encryptionConfig) *probe.Error {
+	targetAlias := urls.TargetAlias
+	targetURL := urls.TargetContent.URL
+	targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
+	tgtSSE := getSSE(targetPath, encKeyDB[targetAlias])
+
+	clnt, err := newClientFromAlias(targetAlias, targetURL.String())
+	if err != nil {
+		return err.Trace(targetURL.String())
+	}
+	st, err := clnt.Stat(ctx, StatOptions{sse: tgtSSE})
+	if err != nil {
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound, BucketDoesNotExist:
+			return nil
+		}
+		return err.Trace(targetURL.String())
+	}
+	if st.Type.IsDir() {
+		return nil
+	}
+	if urls.NoClobber {
+		return probe.NewError(ObjectAlreadyExists{Object: targetURL.String()})
+	}
+
+	backupURL := targetURL.String() + "." + time.Now().UTC().Format(backupKeyTimeFormat) + urls.BackupSuffix
+	backupClnt, err := newClientFromAlias(targetAlias, backupURL)
+	if err != nil {
+		return err.Trace(backupURL)
+	}
+	opts := CopyOptions{
+		size:     st.Size,
+		srcSSE:   tgtSSE,
+		tgtSSE:   tgtSSE,
+		metadata: map[string]string{},
+	}
+	if err = backupClnt.Copy(ctx, targetURL.Path, opts, nil); err != nil {
+		return err.Trace(targetURL.String(), backupURL)
+	}
+	return nil
+}
+
+// statCopySource sets the size and modification time of a source listed
+// without them.
+func statCopySource(ctx context.Context, urls *URLs) *probe.Error {
+	sourceURL := urls.SourceContent.URL.String()
+	clnt, err := newClientFromAlias(urls.SourceAlias, sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	st, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	urls.SourceContent.Size = st.Size
+	urls.SourceContent.Time = st.Time
+	urls.SourceContent.Type = st.Type
+	return nil
+}
+
 // Progress - an interface which describes current amount
 // of data written.
 type Progress interface {
@@ -244,12 +598,31 @@ type ProgressReader interface {
 }
 
 // doCopy - Copy a single file from source to destination
-func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[string][]prefixSSEPair, isMvCmd, preserve, isZip bool) URLs {
+func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB encryptionConfig, isMvCmd, preserve, isZip bool) URLs {
 	if cpURLs.Error != nil {
 		cpURLs.Error = cpURLs.Error.Trace()
 		return cpURLs
 	}
 
+	if cpURLs.SourceContent.IsDeleteMarker {
+		return copyDeleteMarker(ctx, cpURLs)
+	}
+
+	// Local files listed without their size are stat'ed now.
+	if cpURLs.SourceContent.Size < 0 {
+		if err := statCopySource(ctx, &cpURLs); err != nil {
+			return cpURLs.WithError(err)
+		}
+	}
+
+	// Objects which may still be written are left for the next pass.
+	if settleRemaining(cpURLs.SourceContent.Time, cpURLs.SettleDuration) > 0 {
+		return cpURLs.WithError(probe.NewError(ObjectSettling{
+			Object:  cpURLs.SourceContent.URL.String(),
+			ModTime: cpURLs.SourceContent.Time,
+		}))
+	}
+
 	sourceAlias := cpURLs.SourceAlias
 	sourceURL := cpURLs.SourceContent.URL
 	targetAlias := cpURLs.TargetAlias
@@ -257,10 +630,26 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 	length := cpURLs.SourceContent.Size
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 
-	if progressReader, ok := pg.(*progressBar); ok {
+	targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
+	if cpURLs.Compare != "" {
+		if err := skipUpToDateTarget(ctx, cpURLs, encKeyDB); err != nil {
+			return cpURLs.WithError(err)
+		}
+	}
+	if cpURLs.NoClobber || cpURLs.BackupSuffix != "" {
+		if err := protectExistingTarget(ctx, cpURLs, encKeyDB); err != nil {
+			return cpURLs.WithError(err)
+		}
+	}
+
+	progressReader, isProgress := pg.(*progressBar)
+	if isProgress {
 		progressReader.SetCaption(cpURLs.SourceContent.URL.String() + ":")
-	} else {
-		targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
+	} else if events, ok := pg.(*jsonProgress); ok {
+		// Objects are reported by their events only.
+		events.Start(cpURLs)
+		isProgress = true
+	} else if cpURLs.Checksum == "" && !cpURLs.Verify {
 		printMsg(copyMessage{
 			Source:     sourcePath,
 			Target:     targetPath,
@@ -270,7 +659,31 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 		})
 	}
 
-	urls := uploadSourceToTargetURL(ctx, cpURLs, pg, encKeyDB, preserve, isZip)
+	urls := uploadSourceToTargetURLWithRetry(ctx, cpURLs, pg, encKeyDB, preserve, isZip)
+	var verified string
+	if cpURLs.Verify && urls.Error == nil {
+		var err *probe.Error
+		if verified, err = verifyCopy(ctx, urls, encKeyDB); err != nil {
+			return urls.WithError(err.Trace(sourceURL.String()))
+		}
+	}
+	if !isProgress && (cpURLs.Checksum != "" || cpURLs.Verify) && urls.Error == nil {
+		// Checksummed and verified copies are reported once the checksum is known.
+		msg := copyMessage{
+			Source:     sourcePath,
+			Target:     targetPath,
+			Size:       length,
+			TotalCount: cpURLs.TotalCount,
+			TotalSize:  cpURLs.TotalSize,
+		}
+		if verified != "" {
+			msg.Checksum = verified
+			msg.Verified = true
+		} else if urls.ChecksumValue != "" {
+			msg.Checksum = cpURLs.Checksum + ":" + urls.ChecksumValue
+		}
+		printMsg(msg)
+	}
 	if isMvCmd && urls.Error == nil {
 		rmManager.add(ctx, sourceAlias, sourceURL.String())
 	}
@@ -282,11 +695,66 @@ func doCopy(ctx context.Context, cpURLs URLs, pg ProgressReader, encKeyDB map[st
 func doCopyFake(cpURLs URLs, pg Progress) URLs {
 	if progressReader, ok := pg.(*progressBar); ok {
 		progressReader.ProgressBar.Add64(cpURLs.SourceContent.Size)
+	} else if events, ok := pg.(*jsonProgress); ok {
+		events.Add(cpURLs.SourceContent.Size)
 	}
 
 	return cpURLs
 }
 
+// copyDryRunMessage container for the summary of 'cp --dry-run'
+type copyDryRunMessage struct {
+	Status     string `json:"status"`
+	TotalCount int64  `json:"totalCount"`
+	TotalSize  int64  `json:"totalSize"`
+	DryRun     bool   `json:"dryRun"`
+}
+
+// String colorized dry run summary
+func (c copyDryRunMessage) String() string {
+	return console.Colorize("Copy", fmt.Sprintf("DRYRUN: %d object(s) to copy, %s in total.", c.TotalCount, humanize.IBytes(uint64(c.TotalSize))))
+}
+
+// JSON jsonified dry run summary
+func (c copyDryRunMessage) JSON() string {
+	c.Status = "success"
+	c.DryRun = true
+	copyDryRunMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(copyDryRunMessageBytes)
+}
+
+// doCopyDryRun prepares the URLs of a copy like doCopySession, and
+// prints the objects which would be copied with their count and size.
+func doCopyDryRun(ctx context.Context, cli *cli.Context, encKeyDB encryptionConfig) error {
+	var totalObjects, totalBytes int64
+	for cpURLs := range prepareCopyURLs(ctx, copyURLsOptsFromContext(ctx, cli, encKeyDB)) {
+		if cpURLs.Error != nil {
+			if strings.Contains(cpURLs.Error.ToGoError().Error(), " is a folder.") {
+				errorIf(cpURLs.Error.Trace(), "Folder cannot be copied. Please use `...` suffix.")
+			} else {
+				errorIf(cpURLs.Error.Trace(), "Unable to start copying.")
+			}
+			return exitStatus(globalErrorExitStatus)
+		}
+		if cpURLs.SourceContent.Size > 0 {
+			totalBytes += cpURLs.SourceContent.Size
+		}
+		totalObjects++
+		printMsg(copyMessage{
+			Source:     filepath.ToSlash(filepath.Join(cpURLs.SourceAlias, cpURLs.SourceContent.URL.Path)),
+			Target:     filepath.ToSlash(filepath.Join(cpURLs.TargetAlias, cpURLs.TargetContent.URL.Path)),
+			Size:       cpURLs.SourceContent.Size,
+			TotalCount: totalObjects,
+			TotalSize:  totalBytes,
+			DryRun:     true,
+		})
+	}
+	printMsg(copyDryRunMessage{TotalCount: totalObjects, TotalSize: totalBytes})
+	return nil
+}
+
 // doPrepareCopyURLs scans the source URL and prepares a list of objects for copying.
 func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy context.CancelFunc) (totalBytes, totalObjects int64) {
 	// Separate source and target. 'cp' can take only one target,
@@ -300,9 +768,22 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 	versionID := session.Header.CommandStringFlags["version-id"]
 	olderThan := session.Header.CommandStringFlags["older-than"]
 	newerThan := session.Header.CommandStringFlags["newer-than"]
+	filter, err := parseContentFilter(session.Header.CommandStringFlags["filter"])
+	fatalIf(err, "Unable to parse --filter.")
+	pathFilter, err := parsePathFilter(session.Header.CommandStringFlags["path-filter"])
+	fatalIf(err, "Unable to parse the path filter.")
+	listWorkers, _ := strconv.Atoi(session.Header.CommandStringFlags["list-workers"])
+	maxDepth, _ := strconv.Atoi(session.Header.CommandStringFlags["max-depth"])
+	var rules []string
+	if r := session.Header.CommandStringFlags["name-transform"]; r != "" {
+		rules = strings.Split(r, "\n")
+	}
+	stripComponents, _ := strconv.Atoi(session.Header.CommandStringFlags["strip-components"])
+	nameTransform := mustParseNameTransform(rules, stripComponents)
 	encryptKeys := session.Header.CommandStringFlags["encrypt-key"]
 	encrypt := session.Header.CommandStringFlags["encrypt"]
-	encKeyDB, err := parseAndValidateEncryptionKeys(encryptKeys, encrypt)
+	encKMS := session.Header.CommandStringFlags["enc-kms"]
+	encKeyDB, err := parseAndValidateEncryptionKeys(encryptKeys, encrypt, encKMS)
 	fatalIf(err, "Unable to parse encryption keys.")
 
 	// Create a session data file to store the processed URLs.
@@ -320,8 +801,20 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 		encKeyDB:    encKeyDB,
 		olderThan:   olderThan,
 		newerThan:   newerThan,
+		filter:      filter,
 		timeRef:     parseRewindFlag(rewind),
 		versionID:   versionID,
+
+		targetIsDir:  session.Header.CommandBoolFlags["target-is-dir"],
+		targetIsFile: session.Header.CommandBoolFlags["target-is-file"],
+		pathFilter:   pathFilter,
+		withVersions: session.Header.CommandBoolFlags["versions"],
+		filesFrom:    session.Header.CommandStringFlags["files-from"],
+		listWorkers:  listWorkers,
+		maxDepth:     maxDepth,
+		specialFiles: session.Header.CommandBoolFlags["special-files"],
+
+		nameTransform: nameTransform,
 	}
 
 	URLsCh := prepareCopyURLs(ctx, opts)
@@ -377,7 +870,36 @@ func doPrepareCopyURLs(ctx context.Context, session *sessionV8, cancelCopy conte
 	return
 }
 
-func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.Context, session *sessionV8, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) error {
+// copyURLsOptsFromContext returns the options to prepare the URLs of a
+// copy from the command line.
+func copyURLsOptsFromContext(ctx context.Context, cli *cli.Context, encKeyDB encryptionConfig) prepareCopyURLsOpts {
+	olderThan, newerThan, _ := parseOlderNewerThan(ctx, cli)
+	return prepareCopyURLsOpts{
+		sourceURLs:  cli.Args()[:len(cli.Args())-1],
+		targetURL:   cli.Args()[len(cli.Args())-1],
+		isRecursive: cli.Bool("recursive"),
+		encKeyDB:    encKeyDB,
+		olderThan:   olderThan,
+		newerThan:   newerThan,
+		filter:      mustParseContentFilter(cli),
+		timeRef:     parseRewindFlag(cli.String("rewind")),
+		versionID:   cli.String("version-id"),
+		isZip:       cli.Bool("zip"),
+
+		targetIsDir:  cli.Bool("target-is-dir"),
+		targetIsFile: cli.Bool("target-is-file"),
+		pathFilter:   mustParsePathFilter(cli),
+		withVersions: cli.Bool("versions"),
+		filesFrom:    cli.String("files-from"),
+		listWorkers:  cli.Int("list-workers"),
+		maxDepth:     cli.Int("max-depth"),
+		specialFiles: cli.Bool("special-files"),
+
+		nameTransform: mustParseNameTransform(cli.StringSlice("name-transform"), cli.Int("strip-components")),
+	}
+}
+
+func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.Context, session *sessionV8, encKeyDB encryptionConfig, isMvCmd bool) error {
 	var isCopied func(string) bool
 	var totalObjects, totalBytes int64
 
@@ -386,23 +908,40 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 	// Store a progress bar or an accounter
 	var pg ProgressReader
 
+	stats := newBulkStats()
+
 	// Enable progress bar reader only during default mode.
-	if !globalQuiet && !globalJSON { // set up progress bar
+	if cli.String("progress") == "json" {
+		pg = newJSONProgress(totalBytes)
+	} else if !globalQuiet && !globalJSON { // set up progress bar
 		pg = newProgressBar(totalBytes)
 	} else {
 		pg = newAccounter(totalBytes)
 	}
 
-	sourceURLs := cli.Args()[:len(cli.Args())-1]
 	targetURL := cli.Args()[len(cli.Args())-1] // Last one is target
 
+	var failed *failedLog
+	if failedLog := cli.String("failed-log"); failedLog != "" {
+		var err *probe.Error
+		failed, err = newFailedLog(failedLog, cli.Args()[:len(cli.Args())-1])
+		fatalIf(err, "Unable to create the failed objects log.")
+		defer failed.Close()
+	}
+
 	// Check if the target path has object locking enabled
 	withLock, _ := isBucketLockEnabled(ctx, targetURL)
 
+	var checkpoint *sessionCheckpoint
 	if session != nil {
+		checkpoint = session.Checkpoint
 		// isCopied returns true if an object has been already copied
 		// or not. This is useful when we resume from a session.
-		isCopied = isLastFactory(session.Header.LastCopied)
+		if checkpoint != nil {
+			isCopied = checkpoint.isDone
+		} else {
+			isCopied = isLastFactory(session.Header.LastCopied)
+		}
 
 		if !session.HasData() {
 			totalBytes, totalObjects = doPrepareCopyURLs(ctx, session, cancelCopy)
@@ -432,26 +971,13 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 			}
 		}()
 	} else {
-		// Access recursive flag inside the session header.
-		isRecursive := cli.Bool("recursive")
-		olderThan := cli.String("older-than")
-		newerThan := cli.String("newer-than")
-		rewind := cli.String("rewind")
-		versionID := cli.String("version-id")
+		opts := copyURLsOptsFromContext(ctx, cli, encKeyDB)
+		// Local files are stat'ed by the copy workers when
+		// no progress bar total nor filter needs their size.
+		opts.direntOnly = (globalQuiet || globalJSON) && opts.olderThan == "" && opts.newerThan == "" && opts.filter == nil
 
 		go func() {
 			totalBytes := int64(0)
-			opts := prepareCopyURLsOpts{
-				sourceURLs:  sourceURLs,
-				targetURL:   targetURL,
-				isRecursive: isRecursive,
-				encKeyDB:    encKeyDB,
-				olderThan:   olderThan,
-				newerThan:   newerThan,
-				timeRef:     parseRewindFlag(rewind),
-				versionID:   versionID,
-				isZip:       cli.Bool("zip"),
-			}
 			for cpURLs := range prepareCopyURLs(ctx, opts) {
 				if cpURLs.Error != nil {
 					// Print in new line and adjust to top so that we
@@ -469,7 +995,9 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 					}
 					break
 				}
-				totalBytes += cpURLs.SourceContent.Size
+				if cpURLs.SourceContent.Size > 0 {
+					totalBytes += cpURLs.SourceContent.Size
+				}
 				pg.SetTotal(totalBytes)
 				totalObjects++
 				cpURLsCh <- cpURLs
@@ -478,10 +1006,17 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 		}()
 	}
 
+	headers, _ := loadHeaderMap(cli.String("header-map"))
+
 	quitCh := make(chan struct{})
+	stopCh := enableGracefulStop()
 	statusCh := make(chan URLs)
 
 	parallel := newParallelManager(statusCh)
+	if cli.Bool("server-side") {
+		parallel = newServerSideParallelManager(statusCh, serverSideWorkers())
+	}
+	versions := newVersionSequencer()
 
 	go func() {
 		gracefulStop := func() {
@@ -491,7 +1026,19 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 		startContinue := true
 		for {
+			// Stop queueing objects once interrupted, the objects
+			// being copied are finished.
 			select {
+			case <-stopCh:
+				gracefulStop()
+				return
+			default:
+			}
+
+			select {
+			case <-stopCh:
+				gracefulStop()
+				return
 			case <-quitCh:
 				gracefulStop()
 				return
@@ -534,8 +1081,11 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 					cpURLs.TargetContent.Metadata["X-Amz-Tagging"] = tags
 				}
 
-				preserve := cli.Bool("preserve")
+				preserve := cli.Bool("preserve") || cli.Bool("attr-preserve")
 				isZip := cli.Bool("zip")
+				for k, v := range headers.headersFor(cpURLs.TargetContent.URL.Path) {
+					cpURLs.TargetContent.UserMetadata[k] = v
+				}
 				if cli.String("attr") != "" {
 					userMetaMap, _ := getMetaDataEntry(cli.String("attr"))
 					for metadataKey, metaDataVal := range userMetaMap {
@@ -545,15 +1095,38 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 
 				cpURLs.MD5 = cli.Bool("md5") || withLock
 				cpURLs.DisableMultipart = cli.Bool("disable-multipart")
+				if checksum := cli.String("checksum"); checksum != "" {
+					cpURLs.Checksum, _ = parseChecksumAlgorithm(checksum)
+				}
+				cpURLs.Verify = cli.Bool("verify")
+				cpURLs.LambdaArn = cli.String("lambda-arn")
+				cpURLs.Sparse = cli.Bool("sparse")
+				cpURLs.DownloadParts = cli.Int("download-parts")
+				cpURLs.AttrPreserve = cli.Bool("attr-preserve")
+				cpURLs.Compress = cli.String("compress")
+				cpURLs.CSEKey = cli.String("cse-key")
+				cpURLs.ServerSide = cli.Bool("server-side")
+				cpURLs.PreserveRetention = cli.Bool("preserve-retention")
+				cpURLs.PreserveLegalHold = cli.Bool("preserve-legalhold")
+				cpURLs.Conditions, _ = parseGetConditions(cli)
+				cpURLs.StallTimeout, _ = parseStallTimeout(cli)
+				cpURLs.SettleDuration, _ = parseSettleDuration(cli)
+				cpURLs.Retries, cpURLs.RetryDelay, _ = parseRetry(cli)
+				cpURLs.NoClobber = cli.Bool("no-clobber")
+				if compare := cli.String("compare"); compare != "" {
+					cpURLs.Compare, _ = parseCopyCompare(compare)
+				}
+				cpURLs.BackupSuffix = cli.String("backup-existing")
 
 				// Verify if previously copied, notify progress bar.
-				if isCopied != nil && isCopied(cpURLs.SourceContent.URL.String()) {
+				if isCopied != nil && isCopied(copySourceID(cpURLs)) {
 					parallel.queueTask(func() URLs {
+						stats.Skipped()
 						return doCopyFake(cpURLs, pg)
 					}, 0)
 				} else {
 					// Print the copy resume summary once in start
-					if startContinue && cli.Bool("continue") {
+					if startContinue && (cli.Bool("continue") || cli.Bool("resume")) {
 						if pb, ok := pg.(*progressBar); ok {
 							startSize := humanize.IBytes(uint64(pb.Start().Get()))
 							totalSize := humanize.IBytes(uint64(pb.Total))
@@ -561,9 +1134,33 @@ func doCopySession(ctx context.Context, cancelCopy context.CancelFunc, cli *cli.
 						}
 						startContinue = false
 					}
+					// Files listed without their size are not accounted.
+					uploadSize := cpURLs.SourceContent.Size
+					if uploadSize < 0 {
+						uploadSize = 0
+					}
+					// Versions of an object are copied in order.
+					previous, versionDone := versions.next(cpURLs.TargetContent.URL.String())
 					parallel.queueTask(func() URLs {
-						return doCopy(ctx, cpURLs, pg, encKeyDB, isMvCmd, preserve, isZip)
-					}, cpURLs.SourceContent.Size)
+						defer versionDone()
+						<-previous
+						if checkpoint != nil {
+							if err := checkpoint.start(ctx, cpURLs); err != nil {
+								stats.Failed()
+								return cpURLs.WithError(err)
+							}
+						}
+						urls := doCopy(ctx, cpURLs, pg, encKeyDB, isMvCmd, preserve, isZip)
+						switch {
+						case urls.Error == nil:
+							stats.Succeeded(urls.SourceContent.Size)
+						case isErrIgnored(urls.Error), isErrNotModified(urls.Error), isErrSourceSettling(urls.Error), isErrUpToDate(urls.Error):
+							stats.Skipped()
+						default:
+							stats.Failed()
+						}
+						return urls
+					}, uploadSize)
 				}
 			}
 		}
@@ -593,10 +1190,49 @@ loop:
 				break loop
 			}
 			if cpURLs.Error == nil {
+				if events, ok := pg.(*jsonProgress); ok {
+					events.Done(cpURLs)
+				}
 				if session != nil {
-					session.Header.LastCopied = cpURLs.SourceContent.URL.String()
+					session.Header.LastCopied = copySourceID(cpURLs)
 					session.Save()
 				}
+				if checkpoint != nil {
+					errorIf(checkpoint.finish(copySourceID(cpURLs)), "Unable to update session checkpoint.")
+				}
+				cpAllFilesErr = false
+			} else if isErrNotModified(cpURLs.Error) {
+				// Unchanged sources are reported, not failed.
+				doCopyFake(cpURLs, pg)
+				printMsg(notModifiedMessage{
+					Source: cpURLs.SourceContent.URL.String(),
+					Target: cpURLs.TargetContent.URL.String(),
+				})
+				cpAllFilesErr = false
+			} else if isErrSourceSettling(cpURLs.Error) {
+				// Objects still written are reported, not failed.
+				doCopyFake(cpURLs, pg)
+				printMsg(settlingMessage{
+					Source:       cpURLs.SourceContent.URL.String(),
+					Target:       cpURLs.TargetContent.URL.String(),
+					LastModified: cpURLs.SourceContent.Time,
+				})
+				cpAllFilesErr = false
+			} else if isErrUpToDate(cpURLs.Error) {
+				// Targets already copied are reported, not failed.
+				doCopyFake(cpURLs, pg)
+				printMsg(upToDateMessage{
+					Source: cpURLs.SourceContent.URL.String(),
+					Target: cpURLs.TargetContent.URL.String(),
+				})
+				cpAllFilesErr = false
+			} else if cpURLs.NoClobber && isErrTargetExists(cpURLs.Error) {
+				// Existing targets are reported, not failed.
+				doCopyFake(cpURLs, pg)
+				printMsg(targetExistsMessage{
+					Source: cpURLs.SourceContent.URL.String(),
+					Target: cpURLs.TargetContent.URL.String(),
+				})
 				cpAllFilesErr = false
 			} else {
 
@@ -616,6 +1252,10 @@ loop:
 				}
 
 				errSeen = true
+				failed.Add(cpURLs)
+				if events, ok := pg.(*jsonProgress); ok {
+					events.Done(cpURLs)
+				}
 				if progressReader, pgok := pg.(*progressBar); pgok {
 					if progressReader.ProgressBar.Get() > 0 {
 						writeContSize := (int)(cpURLs.SourceContent.Size)
@@ -644,12 +1284,25 @@ loop:
 		} else if progressReader.ProgressBar.Get() > 0 {
 			progressReader.ProgressBar.Finish()
 		}
+	} else if events, ok := pg.(*jsonProgress); ok {
+		events.Finish()
 	} else {
 		if accntReader, ok := pg.(*accounter); ok {
 			printMsg(accntReader.Stat())
 		}
 	}
 
+	if cli.Bool("stats") {
+		printMsg(stats.Message())
+	}
+
+	if isGracefulStopping() {
+		if session != nil {
+			session.CloseAndDie()
+		}
+		return exitStatus(globalCancelExitStatus)
+	}
+
 	return retErr
 }
 
@@ -669,16 +1322,35 @@ func mainCopy(cliCtx *cli.Context) error {
 		fatalIf(err, "Unable to parse attribute %v", cliCtx.String("attr"))
 	}
 
+	// A folder is packed into, or unpacked from, a single object.
+	if cliCtx.IsSet("archive") || cliCtx.Bool("extract") {
+		checkArchiveSyntax(cliCtx)
+		copyArchive(ctx, cliCtx, encKeyDB)
+		return nil
+	}
+
+	// A single object is read once and written to several targets.
+	if cliCtx.IsSet("targets") {
+		checkFanOutSyntax(cliCtx)
+		return copyFanOut(ctx, cliCtx, encKeyDB)
+	}
+
 	// check 'copy' cli arguments.
 	checkCopySyntax(ctx, cliCtx, encKeyDB, false)
 	// Additional command specific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("NotModified", color.New(color.FgYellow))
+	console.SetColor("Settling", color.New(color.FgYellow))
+	console.SetColor("Stats", color.New(color.Bold))
+
+	if cliCtx.Bool("dry-run") {
+		return doCopyDryRun(ctx, cliCtx, encKeyDB)
+	}
 
 	recursive := cliCtx.Bool("recursive")
 	rewind := cliCtx.String("rewind")
 	versionID := cliCtx.String("version-id")
-	olderThan := cliCtx.String("older-than")
-	newerThan := cliCtx.String("newer-than")
+	olderThan, newerThan, _ := parseOlderNewerThan(ctx, cliCtx)
 	storageClass := cliCtx.String("storage-class")
 	retentionMode := cliCtx.String(rmFlag)
 	retentionDuration := cliCtx.String(rdFlag)
@@ -694,10 +1366,14 @@ func mainCopy(cliCtx *cli.Context) error {
 		fatalIf(err, "Unable to parse encryption keys.")
 	}
 	sse := cliCtx.String("encrypt")
+	sseKMS := os.Getenv("MC_ENC_KMS")
+	if kms := cliCtx.String("enc-kms"); kms != "" {
+		sseKMS = kms
+	}
 
 	var session *sessionV8
 
-	if cliCtx.Bool("continue") {
+	if cliCtx.Bool("continue") || cliCtx.Bool("resume") {
 		sessionID := getHash("cp", os.Args[1:])
 		if isSessionExists(sessionID) {
 			session, err = loadSessionV8(sessionID)
@@ -706,6 +1382,12 @@ func mainCopy(cliCtx *cli.Context) error {
 			session = newSessionV8(sessionID)
 			session.Header.CommandType = "cp"
 			session.Header.CommandBoolFlags["recursive"] = recursive
+			session.Header.CommandBoolFlags["versions"] = cliCtx.Bool("versions")
+			session.Header.CommandStringFlags["files-from"] = cliCtx.String("files-from")
+			session.Header.CommandStringFlags["name-transform"] = strings.Join(cliCtx.StringSlice("name-transform"), "\n")
+			session.Header.CommandStringFlags["strip-components"] = strconv.Itoa(cliCtx.Int("strip-components"))
+			session.Header.CommandStringFlags["list-workers"] = strconv.Itoa(cliCtx.Int("list-workers"))
+			session.Header.CommandStringFlags["max-depth"] = strconv.Itoa(cliCtx.Int("max-depth"))
 			session.Header.CommandStringFlags["rewind"] = rewind
 			session.Header.CommandStringFlags["version-id"] = versionID
 			session.Header.CommandStringFlags["older-than"] = olderThan
@@ -717,7 +1399,9 @@ func mainCopy(cliCtx *cli.Context) error {
 			session.Header.CommandStringFlags[lhFlag] = legalHold
 			session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 			session.Header.CommandStringFlags["encrypt"] = sse
-			session.Header.CommandBoolFlags["session"] = cliCtx.Bool("continue")
+			session.Header.CommandStringFlags["enc-kms"] = sseKMS
+			session.Header.CommandBoolFlags["session"] = true
+			session.Header.CommandBoolFlags["resume"] = cliCtx.Bool("resume")
 
 			if cliCtx.Bool("preserve") {
 				session.Header.CommandBoolFlags["preserve"] = cliCtx.Bool("preserve")
@@ -725,6 +1409,34 @@ func mainCopy(cliCtx *cli.Context) error {
 			session.Header.UserMetaData = userMetaMap
 			session.Header.CommandBoolFlags["md5"] = cliCtx.Bool("md5")
 			session.Header.CommandBoolFlags["disable-multipart"] = cliCtx.Bool("disable-multipart")
+			session.Header.CommandBoolFlags["sparse"] = cliCtx.Bool("sparse")
+			session.Header.CommandBoolFlags["special-files"] = cliCtx.Bool("special-files")
+			session.Header.CommandBoolFlags["attr-preserve"] = cliCtx.Bool("attr-preserve")
+			session.Header.CommandBoolFlags["server-side"] = cliCtx.Bool("server-side")
+			session.Header.CommandBoolFlags["preserve-retention"] = cliCtx.Bool("preserve-retention")
+			session.Header.CommandBoolFlags["preserve-legalhold"] = cliCtx.Bool("preserve-legalhold")
+			session.Header.CommandStringFlags["checksum"] = cliCtx.String("checksum")
+			session.Header.CommandStringFlags["compress"] = cliCtx.String("compress")
+			session.Header.CommandStringFlags["cse-key"] = cliCtx.String("cse-key")
+			session.Header.CommandBoolFlags["verify"] = cliCtx.Bool("verify")
+			session.Header.CommandStringFlags["lambda-arn"] = cliCtx.String("lambda-arn")
+			session.Header.CommandStringFlags["stall-timeout"] = cliCtx.String("stall-timeout")
+			session.Header.CommandStringFlags["retry"] = strconv.Itoa(cliCtx.Int("retry"))
+			session.Header.CommandStringFlags["download-parts"] = strconv.Itoa(cliCtx.Int("download-parts"))
+			session.Header.CommandStringFlags["retry-delay"] = cliCtx.String("retry-delay")
+			session.Header.CommandStringFlags["failed-log"] = cliCtx.String("failed-log")
+			session.Header.CommandStringFlags["settle-duration"] = cliCtx.String("settle-duration")
+			session.Header.CommandStringFlags["header-map"] = cliCtx.String("header-map")
+			session.Header.CommandStringFlags["filter"] = cliCtx.String("filter")
+			session.Header.CommandStringFlags["path-filter"] = pathFilterFromContext(cliCtx)
+			session.Header.CommandBoolFlags["no-clobber"] = cliCtx.Bool("no-clobber")
+			session.Header.CommandStringFlags["compare"] = cliCtx.String("compare")
+			session.Header.CommandBoolFlags["target-is-dir"] = cliCtx.Bool("target-is-dir")
+			session.Header.CommandBoolFlags["target-is-file"] = cliCtx.Bool("target-is-file")
+			session.Header.CommandStringFlags["backup-existing"] = cliCtx.String("backup-existing")
+			for _, flag := range []string{"if-match", "if-none-match", "if-modified-since", "if-unmodified-since"} {
+				session.Header.CommandStringFlags[flag] = cliCtx.String(flag)
+			}
 
 			var e error
 			if session.Header.RootPath, e = os.Getwd(); e != nil {
@@ -735,6 +1447,10 @@ func mainCopy(cliCtx *cli.Context) error {
 			// extract URLs.
 			session.Header.CommandArgs = cliCtx.Args()
 		}
+		if session.Header.CommandBoolFlags["resume"] {
+			session.Checkpoint, err = openSessionCheckpoint(session.SessionID)
+			fatalIf(err.Trace(session.SessionID), "Unable to open session checkpoint.")
+		}
 	}
 
 	e := doCopySession(ctx, cancelCopy, cliCtx, session, encKeyDB, false)
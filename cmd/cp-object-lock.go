@@ -0,0 +1,130 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+)
+
+var objectLockFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "preserve-retention",
+		Usage: "apply the retention mode and date of the source objects to the target objects",
+	},
+	cli.BoolFlag{
+		Name:  "preserve-legalhold",
+		Usage: "apply the legal hold of the source objects to the target objects",
+	},
+}
+
+// checkObjectLockSyntax rejects --preserve-retention and
+// --preserve-legalhold used with the flags setting the same lock.
+func checkObjectLockSyntax(cliCtx *cli.Context) {
+	if cliCtx.Bool("preserve-retention") && (cliCtx.IsSet(rmFlag) || cliCtx.IsSet(rdFlag)) {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--preserve-retention cannot be used with --%s or --%s", rmFlag, rdFlag)
+	}
+	if cliCtx.Bool("preserve-legalhold") && cliCtx.IsSet(lhFlag) {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--preserve-legalhold cannot be used with --%s", lhFlag)
+	}
+}
+
+// isObjectLockNotFound returns true if the error tells that the object
+// or its bucket has no object lock.
+func isObjectLockNotFound(err *probe.Error) bool {
+	switch minio.ToErrorResponse(err.ToGoError()).Code {
+	case "NoSuchObjectLockConfiguration", "ObjectLockConfigurationNotFoundError":
+		return true
+	}
+	return false
+}
+
+// preservedRetention returns the mode and retain until date to apply
+// to the target, retentions which already expired cannot be set.
+func preservedRetention(mode minio.RetentionMode, until, now time.Time) (string, string) {
+	if !mode.IsValid() || !until.After(now) {
+		return "", ""
+	}
+	return string(mode), until.UTC().Format(time.RFC3339)
+}
+
+// getSourceObjectLock returns the retention and legal hold of the
+// source object requested by --preserve-retention and
+// --preserve-legalhold, sources out of object storage have none.
+func getSourceObjectLock(ctx context.Context, urls URLs) (mode, until, legalHold string, err *probe.Error) {
+	sourceClnt, err := newClientFromAlias(urls.SourceAlias, urls.SourceContent.URL.String())
+	if err != nil {
+		return "", "", "", err
+	}
+	if _, ok := sourceClnt.(*S3Client); !ok {
+		return "", "", "", nil
+	}
+
+	versionID := urls.SourceContent.VersionID
+	if urls.PreserveRetention {
+		m, t, err := sourceClnt.GetObjectRetention(ctx, versionID)
+		if err != nil && !isObjectLockNotFound(err) {
+			return "", "", "", err
+		}
+		mode, until = preservedRetention(m, t, time.Now())
+	}
+	if urls.PreserveLegalHold {
+		lh, err := sourceClnt.GetObjectLegalHold(ctx, versionID)
+		if err != nil && !isObjectLockNotFound(err) {
+			return "", "", "", err
+		}
+		if lh == minio.LegalHoldEnabled {
+			legalHold = string(lh)
+		}
+	}
+	return mode, until, legalHold, nil
+}
+
+// Object lock status of the target buckets, looked up once per bucket.
+var targetLockStatus sync.Map
+
+// checkTargetObjectLock returns an error unless the bucket of the
+// target has object lock enabled, targets out of object storage never
+// have it.
+func checkTargetObjectLock(ctx context.Context, targetAlias string, targetURL ClientURL) *probe.Error {
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(targetURL.Path, "/"), "/")
+	aliasedBucket := targetAlias + "/" + bucket
+
+	status, ok := targetLockStatus.Load(aliasedBucket)
+	if !ok {
+		lockStatus, err := getBucketLockStatus(ctx, aliasedBucket)
+		if err != nil {
+			switch err.ToGoError() {
+			case errObjectLockConfigNotFound, errObjectLockNotSupported:
+			default:
+				return err.Trace(aliasedBucket)
+			}
+		}
+		status, _ = targetLockStatus.LoadOrStore(aliasedBucket, lockStatus)
+	}
+	if status != "Enabled" {
+		return probe.NewError(ObjectLockNotEnabled{Bucket: aliasedBucket})
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/pkg/env"
+)
+
+var serverSideFlag = cli.BoolFlag{
+	Name:  "server-side",
+	Usage: "fail objects which cannot be copied by the server, and copy with many more objects in parallel",
+}
+
+// Number of objects copied in parallel by --server-side, unless
+// MC_SERVER_SIDE_WORKERS is set.
+const (
+	envServerSideWorkers     = "MC_SERVER_SIDE_WORKERS"
+	defaultServerSideWorkers = 256
+)
+
+// serverSideWorkers returns the number of objects copied in parallel by
+// --server-side.
+func serverSideWorkers() int {
+	workers, e := strconv.Atoi(env.Get(envServerSideWorkers, strconv.Itoa(defaultServerSideWorkers)))
+	if e != nil || workers <= 0 {
+		return defaultServerSideWorkers
+	}
+	if workers > maxServerSideWorkers {
+		return maxServerSideWorkers
+	}
+	return workers
+}
+
+// isSameServer returns true if the objects of both aliases can be
+// copied by the server, they are the same alias or aliases of the
+// same endpoint with the same credentials.
+func isSameServer(sourceAlias, targetAlias string) bool {
+	if sourceAlias == targetAlias {
+		return true
+	}
+	_, _, sourceCfg, err := expandAlias(sourceAlias)
+	if err != nil || sourceCfg == nil {
+		return false
+	}
+	_, _, targetCfg, err := expandAlias(targetAlias)
+	if err != nil || targetCfg == nil {
+		return false
+	}
+	return strings.TrimSuffix(sourceCfg.URL, "/") == strings.TrimSuffix(targetCfg.URL, "/") &&
+		sourceCfg.AccessKey == targetCfg.AccessKey &&
+		sourceCfg.SecretKey == targetCfg.SecretKey &&
+		sourceCfg.SessionToken == targetCfg.SessionToken
+}
+
+// checkServerSideSyntax validates that every source of --server-side
+// is on the server of the target.
+func checkServerSideSyntax(cliCtx *cli.Context) {
+	if !cliCtx.Bool("server-side") {
+		return
+	}
+	args := cliCtx.Args()
+	target := args[len(args)-1]
+	targetAlias, _, targetCfg := mustExpandAlias(target)
+	for _, source := range args[:len(args)-1] {
+		sourceAlias, _, sourceCfg := mustExpandAlias(source)
+		if sourceCfg == nil || targetCfg == nil || !isSameServer(sourceAlias, targetAlias) {
+			fatalIf(errInvalidArgument().Trace(source, target), "--server-side copies objects between buckets of the same server.")
+		}
+	}
+	for _, flag := range []string{"zip", "checksum", "compress", "lambda-arn", "if-match", "if-none-match", "if-modified-since", "if-unmodified-since"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(flag), "--%s cannot be used with --server-side, the objects are read by the client.", flag)
+		}
+	}
+}
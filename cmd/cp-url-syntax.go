@@ -28,7 +28,7 @@ import (
 	"github.com/minio/pkg/console"
 )
 
-func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair, isMvCmd bool) {
+func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig, isMvCmd bool) {
 	if len(cliCtx.Args()) < 2 {
 		if isMvCmd {
 			showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
@@ -57,6 +57,121 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errDummy().Trace(cliCtx.Args()...), "--zip and --rewind cannot be used together")
 	}
 
+	if checksum := cliCtx.String("checksum"); checksum != "" {
+		_, err := parseChecksumAlgorithm(checksum)
+		fatalIf(err.Trace(checksum), "Unable to validate --checksum.")
+	}
+	checkCompressSyntax(cliCtx)
+	checkCSESyntax(cliCtx)
+	checkProgressSyntax(cliCtx)
+	if cliCtx.Int("strip-components") < 0 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--strip-components cannot be negative.")
+	}
+	mustParseNameTransform(cliCtx.StringSlice("name-transform"), cliCtx.Int("strip-components"))
+	checkServerSideSyntax(cliCtx)
+	checkObjectLockSyntax(cliCtx)
+
+	if msg := checkCopyDryRunSyntax(cliCtx); msg != "" {
+		fatalIf(errDummy().Trace(cliCtx.Args()...), msg)
+	}
+	if msg := checkDownloadPartsSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+
+	if cliCtx.IsSet("files-from") {
+		if cliCtx.String("files-from") == "" || len(srcURLs) != 1 {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--files-from requires a manifest and a single source folder the keys are relative to")
+		}
+		if isRecursive || cliCtx.Bool("versions") || versionID != "" || cliCtx.String("rewind") != "" || isZip {
+			fatalIf(errDummy().Trace(cliCtx.Args()...), "--files-from cannot be used with --recursive, --versions, --version-id, --rewind or --zip")
+		}
+		if cliCtx.Bool("continue") || cliCtx.Bool("resume") {
+			if cliCtx.String("files-from") == "-" {
+				fatalIf(errDummy().Trace(cliCtx.Args()...), "--files-from - cannot be used with --continue or --resume, the manifest must be a file")
+			}
+		}
+	}
+
+	if cliCtx.Bool("versions") {
+		if !isRecursive {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--versions requires --recursive, use --version-id to copy a single version")
+		}
+		if versionID != "" || cliCtx.String("rewind") != "" || isZip {
+			fatalIf(errDummy().Trace(cliCtx.Args()...), "--versions cannot be used with --version-id, --rewind or --zip")
+		}
+	}
+
+	if cliCtx.Bool("verify") && isZip {
+		fatalIf(errDummy().Trace(cliCtx.Args()...), "--zip and --verify cannot be used together")
+	}
+
+	// Reject flags the target was probed by 'mc features' not to support.
+	if cliCtx.String("tags") != "" {
+		checkAliasFeature(tgtURL, featureTagging, "--tags")
+	}
+	if cliCtx.String(rmFlag) != "" {
+		checkAliasFeature(tgtURL, featureObjectLock, "--"+rmFlag)
+	}
+
+	if lambdaArn := cliCtx.String("lambda-arn"); lambdaArn != "" {
+		fatalIf(checkLambdaArn(lambdaArn).Trace(lambdaArn), "Unable to validate --lambda-arn.")
+		if isZip {
+			fatalIf(errDummy().Trace(cliCtx.Args()...), "--zip and --lambda-arn cannot be used together")
+		}
+	}
+
+	conditions, err := parseGetConditions(cliCtx)
+	fatalIf(err.Trace(cliCtx.Args()...), "Unable to parse conditional read flags.")
+	if isZip && conditions.IsSet() {
+		fatalIf(errDummy().Trace(cliCtx.Args()...), "--zip and conditional read flags cannot be used together")
+	}
+
+	_, err = loadHeaderMap(cliCtx.String("header-map"))
+	fatalIf(err, "Unable to load --header-map.")
+
+	_, err = parseContentFilter(cliCtx.String("filter"))
+	fatalIf(err.Trace(cliCtx.Args()...), "Unable to parse --filter.")
+
+	mustParsePathFilter(cliCtx)
+
+	if cliCtx.Bool("target-is-dir") && cliCtx.Bool("target-is-file") {
+		fatalIf(errDummy().Trace(cliCtx.Args()...), "--target-is-dir and --target-is-file cannot be used together")
+	}
+	if cliCtx.Bool("target-is-file") && (isRecursive || len(srcURLs) > 1) {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--target-is-file requires a single source file")
+	}
+
+	if msg := checkMaxDepthSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+	if cliCtx.Int("max-depth") > 0 && !isRecursive {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--max-depth can only be used with --recursive.")
+	}
+	if cliCtx.Int("list-workers") < 0 {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--list-workers cannot be negative.")
+	}
+
+	if compare := cliCtx.String("compare"); compare != "" {
+		_, err := parseCopyCompare(compare)
+		fatalIf(err, "Unable to parse --compare, expected 'size', 'mtime', 'etag' or 'checksum'.")
+	}
+
+	if msg := checkClobberSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+
+	_, err = parseStallTimeout(cliCtx)
+	fatalIf(err.Trace(cliCtx.String("stall-timeout")), "Unable to parse --stall-timeout.")
+
+	_, err = parseSettleDuration(cliCtx)
+	fatalIf(err.Trace(cliCtx.String("settle-duration")), "Unable to parse --settle-duration.")
+
+	_, _, err = parseRetry(cliCtx)
+	fatalIf(err, "Unable to parse --retry or --retry-delay.")
+
+	_, _, err = parseOlderNewerThan(ctx, cliCtx)
+	fatalIf(err, "Unable to read the modification time of --older-than-file or --newer-than-file.")
+
 	// Verify if source(s) exists.
 	for _, srcURL := range srcURLs {
 		var err *probe.Error
@@ -91,6 +206,17 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		fatalIf(errInvalidArgument().Trace(), fmt.Sprintf("Both object retention flags `--%s` and `--%s` are required.\n", rdFlag, rmFlag))
 	}
 
+	// Preserve functionality not supported for windows
+	if (cliCtx.Bool("preserve") || cliCtx.Bool("attr-preserve")) && runtime.GOOS == "windows" {
+		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
+	}
+
+	// The source of --files-from is the folder the keys are relative
+	// to, it is copied from without being listed.
+	if cliCtx.IsSet("files-from") {
+		return
+	}
+
 	operation := "copy"
 	if isMvCmd {
 		operation = "move"
@@ -107,6 +233,9 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		timeRef:     timeRef,
 		versionID:   versionID,
 		isZip:       isZip,
+
+		targetIsDir:  cliCtx.Bool("target-is-dir"),
+		targetIsFile: cliCtx.Bool("target-is-file"),
 	}
 	copyURLsType, _, err := guessCopyURLType(ctx, opts)
 	if err != nil {
@@ -119,13 +248,13 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		if len(srcURLs) != 1 {
 			fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
 		}
-		checkCopySyntaxTypeA(ctx, srcURLs[0], versionID, encKeyDB, isZip, timeRef)
+		checkCopySyntaxTypeA(ctx, srcURLs[0], versionID, encKeyDB, isZip, cliCtx.Bool("special-files"), timeRef)
 	case copyURLsTypeB: // File -> Folder.
 		// Check source.
 		if len(srcURLs) != 1 {
 			fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
 		}
-		checkCopySyntaxTypeB(ctx, srcURLs[0], versionID, tgtURL, encKeyDB, isZip, timeRef)
+		checkCopySyntaxTypeB(ctx, srcURLs[0], versionID, tgtURL, encKeyDB, isZip, cliCtx.Bool("special-files"), timeRef)
 	case copyURLsTypeC: // Folder... -> Folder.
 		checkCopySyntaxTypeC(ctx, srcURLs, tgtURL, isRecursive, isZip, encKeyDB, isMvCmd, timeRef)
 	case copyURLsTypeD: // File1...FileN -> Folder.
@@ -133,29 +262,24 @@ func checkCopySyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 	default:
 		fatalIf(errInvalidArgument().Trace(), "Unable to guess the type of "+operation+" operation.")
 	}
-
-	// Preserve functionality not supported for windows
-	if cliCtx.Bool("preserve") && runtime.GOOS == "windows" {
-		fatalIf(errInvalidArgument().Trace(), "Permissions are not preserved on windows platform.")
-	}
 }
 
 // checkCopySyntaxTypeA verifies if the source and target are valid file arguments.
-func checkCopySyntaxTypeA(ctx context.Context, srcURL, versionID string, keys map[string][]prefixSSEPair, isZip bool, timeRef time.Time) {
+func checkCopySyntaxTypeA(ctx context.Context, srcURL, versionID string, keys encryptionConfig, isZip, specialFiles bool, timeRef time.Time) {
 	_, srcContent, err := url2Stat(ctx, srcURL, versionID, false, keys, timeRef, isZip)
 	fatalIf(err.Trace(srcURL), "Unable to stat source `"+srcURL+"`.")
 
-	if !srcContent.Type.IsRegular() {
+	if !isCopySource(srcContent.Type, specialFiles) {
 		fatalIf(errInvalidArgument().Trace(), "Source `"+srcURL+"` is not a file.")
 	}
 }
 
 // checkCopySyntaxTypeB verifies if the source is a valid file and target is a valid folder.
-func checkCopySyntaxTypeB(ctx context.Context, srcURL, versionID, tgtURL string, keys map[string][]prefixSSEPair, isZip bool, timeRef time.Time) {
+func checkCopySyntaxTypeB(ctx context.Context, srcURL, versionID, tgtURL string, keys encryptionConfig, isZip, specialFiles bool, timeRef time.Time) {
 	_, srcContent, err := url2Stat(ctx, srcURL, versionID, false, keys, timeRef, isZip)
 	fatalIf(err.Trace(srcURL), "Unable to stat source `"+srcURL+"`.")
 
-	if !srcContent.Type.IsRegular() {
+	if !isCopySource(srcContent.Type, specialFiles) {
 		fatalIf(errInvalidArgument().Trace(srcURL), "Source `"+srcURL+"` is not a file.")
 	}
 
@@ -168,7 +292,7 @@ func checkCopySyntaxTypeB(ctx context.Context, srcURL, versionID, tgtURL string,
 }
 
 // checkCopySyntaxTypeC verifies if the source is a valid recursive dir and target is a valid folder.
-func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string, isRecursive, isZip bool, keys map[string][]prefixSSEPair, isMvCmd bool, timeRef time.Time) {
+func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string, isRecursive, isZip bool, keys encryptionConfig, isMvCmd bool, timeRef time.Time) {
 	// Check source.
 	if len(srcURLs) != 1 {
 		fatalIf(errInvalidArgument().Trace(), "Invalid number of source arguments.")
@@ -208,7 +332,7 @@ func checkCopySyntaxTypeC(ctx context.Context, srcURLs []string, tgtURL string,
 }
 
 // checkCopySyntaxTypeD verifies if the source is a valid list of files and target is a valid folder.
-func checkCopySyntaxTypeD(ctx context.Context, tgtURL string, keys map[string][]prefixSSEPair, timeRef time.Time) {
+func checkCopySyntaxTypeD(ctx context.Context, tgtURL string, keys encryptionConfig, timeRef time.Time) {
 	// Source can be anything: file, dir, dir...
 	// Check target if it is a dir
 	if _, tgtContent, err := url2Stat(ctx, tgtURL, "", false, keys, timeRef, false); err == nil {
@@ -217,3 +341,26 @@ func checkCopySyntaxTypeD(ctx context.Context, tgtURL string, keys map[string][]
 		}
 	}
 }
+
+// checkCopyDryRunSyntax returns why --dry-run cannot be used as given,
+// or an empty string.
+func checkCopyDryRunSyntax(cliCtx *cli.Context) string {
+	if cliCtx.Bool("dry-run") && (cliCtx.Bool("continue") || cliCtx.Bool("resume")) {
+		return "--dry-run cannot be used with --continue or --resume"
+	}
+	return ""
+}
+
+// checkClobberSyntax returns why --no-clobber and --backup-existing
+// cannot be used as given, or an empty string.
+func checkClobberSyntax(cliCtx *cli.Context) string {
+	switch {
+	case cliCtx.Bool("no-clobber") && cliCtx.String("compare") != "":
+		return "--no-clobber and --compare cannot be used together"
+	case cliCtx.Bool("no-clobber") && cliCtx.IsSet("backup-existing"):
+		return "--no-clobber and --backup-existing cannot be used together"
+	case cliCtx.IsSet("backup-existing") && cliCtx.String("backup-existing") == "":
+		return "--backup-existing requires a suffix, e.g. '.bak'."
+	}
+	return ""
+}
@@ -19,6 +19,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -74,7 +75,7 @@ func guessCopyURLType(ctx context.Context, o prepareCopyURLsOpts) (copyURLsType,
 		}
 
 		// If target is a folder, it is Type B.
-		if isAliasURLDir(ctx, o.targetURL, o.encKeyDB, o.timeRef) {
+		if isCopyTargetDir(ctx, o) {
 			return copyURLsTypeB, sourceContent.VersionID, nil
 		}
 		// else Type A.
@@ -82,16 +83,44 @@ func guessCopyURLType(ctx context.Context, o prepareCopyURLsOpts) (copyURLsType,
 	}
 
 	// Multiple source args and target is a folder. It is Type D.
-	if isAliasURLDir(ctx, o.targetURL, o.encKeyDB, o.timeRef) {
+	if isCopyTargetDir(ctx, o) {
 		return copyURLsTypeD, "", nil
 	}
 
 	return copyURLsTypeInvalid, "", errInvalidArgument().Trace()
 }
 
+// isCopyTargetDir returns true if the target of a copy is a folder, as
+// forced by --target-is-dir or --target-is-file, else as told by a
+// trailing slash, else as found on the target. A target which does not
+// exist is a file unless it is a bucket.
+func isCopyTargetDir(ctx context.Context, o prepareCopyURLsOpts) bool {
+	switch {
+	case o.targetIsDir:
+		return true
+	case o.targetIsFile:
+		return false
+	case strings.HasSuffix(o.targetURL, "/"), strings.HasSuffix(o.targetURL, string(filepath.Separator)):
+		return true
+	}
+	return isAliasURLDir(ctx, o.targetURL, o.encKeyDB, o.timeRef)
+}
+
+// isSpecialFile returns true for named pipes and character or block
+// devices, which are read as streams until EOF.
+func isSpecialFile(mode os.FileMode) bool {
+	return mode&(os.ModeNamedPipe|os.ModeDevice|os.ModeCharDevice) != 0
+}
+
+// isCopySource returns true if a file of the given mode can be copied,
+// special files only when requested with --special-files.
+func isCopySource(mode os.FileMode, specialFiles bool) bool {
+	return mode.IsRegular() || specialFiles && isSpecialFile(mode)
+}
+
 // SINGLE SOURCE - Type A: copy(f, f) -> copy(f, f)
 // prepareCopyURLsTypeA - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeA(ctx context.Context, sourceURL, sourceVersion, targetURL string, encKeyDB map[string][]prefixSSEPair, isZip bool) URLs {
+func prepareCopyURLsTypeA(ctx context.Context, sourceURL, sourceVersion, targetURL string, encKeyDB encryptionConfig, isZip, specialFiles bool) URLs {
 	// Extract alias before fiddling with the clientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
 	// Find alias and expanded clientURL.
@@ -102,7 +131,7 @@ func prepareCopyURLsTypeA(ctx context.Context, sourceURL, sourceVersion, targetU
 		// Source does not exist or insufficient privileges.
 		return URLs{Error: err.Trace(sourceURL)}
 	}
-	if !sourceContent.Type.IsRegular() {
+	if !isCopySource(sourceContent.Type, specialFiles) {
 		// Source is not a regular file
 		return URLs{Error: errInvalidSource(sourceURL).Trace(sourceURL)}
 	}
@@ -124,7 +153,7 @@ func makeCopyContentTypeA(sourceAlias string, sourceContent *ClientContent, targ
 
 // SINGLE SOURCE - Type B: copy(f, d) -> copy(f, d/f) -> A
 // prepareCopyURLsTypeB - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeB(ctx context.Context, sourceURL, sourceVersion, targetURL string, encKeyDB map[string][]prefixSSEPair, isZip bool) URLs {
+func prepareCopyURLsTypeB(ctx context.Context, sourceURL, sourceVersion, targetURL string, encKeyDB encryptionConfig, isZip, specialFiles bool) URLs {
 	// Extract alias before fiddling with the clientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
 	// Find alias and expanded clientURL.
@@ -136,7 +165,7 @@ func prepareCopyURLsTypeB(ctx context.Context, sourceURL, sourceVersion, targetU
 		return URLs{Error: err.Trace(sourceURL)}
 	}
 
-	if !sourceContent.Type.IsRegular() {
+	if !isCopySource(sourceContent.Type, specialFiles) {
 		if sourceContent.Type.IsDir() {
 			return URLs{Error: errSourceIsDir(sourceURL).Trace(sourceURL)}
 		}
@@ -158,7 +187,7 @@ func makeCopyContentTypeB(sourceAlias string, sourceContent *ClientContent, targ
 
 // SINGLE SOURCE - Type C: copy(d1..., d2) -> []copy(d1/f, d1/d2/f) -> []A
 // prepareCopyRecursiveURLTypeC - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRecursive, isZip bool, timeRef time.Time) <-chan URLs {
+func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRecursive, isZip, direntOnly, withVersions bool, listWorkers, maxDepth int, timeRef time.Time, pathFilter *pathFilter, transform *nameTransform) <-chan URLs {
 	// Extract alias before fiddling with the clientURL.
 	sourceAlias, _, _ := mustExpandAlias(sourceURL)
 	// Find alias and expanded clientURL.
@@ -173,27 +202,68 @@ func prepareCopyURLsTypeC(ctx context.Context, sourceURL, targetURL string, isRe
 			return
 		}
 
-		for sourceContent := range sourceClient.List(ctx, ListOptions{Recursive: isRecursive, TimeRef: timeRef, ShowDir: DirNone, ListZip: isZip}) {
+		if listWorkers <= 0 {
+			listWorkers = fsWalkWorkers()
+		}
+		listOpts := ListOptions{
+			Recursive:   isRecursive,
+			TimeRef:     timeRef,
+			ShowDir:     DirNone,
+			ListZip:     isZip,
+			WalkWorkers: listWorkers,
+			DirentOnly:  direntOnly,
+		}
+		list := sourceClient.List
+		if isRecursive && maxDepth > 0 {
+			// Folders below --max-depth are not listed.
+			list = func(ctx context.Context, opts ListOptions) <-chan *ClientContent {
+				return listMaxDepth(ctx, sourceAlias, sourceClient, opts, maxDepth)
+			}
+		}
+		var contentCh <-chan *ClientContent
+		var copyDeleteMarkers bool
+		if withVersions {
+			listOpts.WithOlderVersions = true
+			listOpts.WithDeleteMarkers = true
+			contentCh = oldestVersionsFirst(ctx, list(ctx, listOpts))
+			copyDeleteMarkers = isTargetVersioned(ctx, targetAlias, targetURL)
+		} else {
+			contentCh = list(ctx, listOpts)
+		}
+		for sourceContent := range contentCh {
 			if sourceContent.Err != nil {
 				// Listing failed.
 				copyURLsCh <- URLs{Error: sourceContent.Err.Trace(sourceClient.GetURL().String())}
 				continue
 			}
 
-			if !sourceContent.Type.IsRegular() {
+			if sourceContent.IsDeleteMarker && !copyDeleteMarkers {
+				// Delete markers are only copied to versioned targets.
+				continue
+			}
+
+			if !sourceContent.Type.IsRegular() && !sourceContent.IsDeleteMarker {
 				// Source is not a regular file. Skip it for copy.
 				continue
 			}
 
+			// Skip objects not selected by --include and --exclude.
+			if !pathFilter.Match(strings.TrimPrefix(sourceContent.URL.Path, sourceClient.GetURL().Path)) {
+				continue
+			}
+
 			// All OK.. We can proceed. Type B: source is a file, target is a folder and exists.
-			copyURLsCh <- makeCopyContentTypeC(sourceAlias, sourceClient.GetURL(), sourceContent, targetAlias, targetURL)
+			if cpURLs, ok := makeCopyContentTypeC(sourceAlias, sourceClient.GetURL(), sourceContent, targetAlias, targetURL, transform); ok {
+				copyURLsCh <- cpURLs
+			}
 		}
 	}(sourceURL, targetURL, copyURLsCh)
 	return copyURLsCh
 }
 
-// makeCopyContentTypeC - CopyURLs content for copying.
-func makeCopyContentTypeC(sourceAlias string, sourceURL ClientURL, sourceContent *ClientContent, targetAlias, targetURL string) URLs {
+// makeCopyContentTypeC - CopyURLs content for copying, it returns false
+// for the objects whose whole key is removed by --strip-components.
+func makeCopyContentTypeC(sourceAlias string, sourceURL ClientURL, sourceContent *ClientContent, targetAlias, targetURL string, transform *nameTransform) (URLs, bool) {
 	newSourceURL := sourceContent.URL
 	pathSeparatorIndex := strings.LastIndex(sourceURL.Path, string(sourceURL.Separator))
 	newSourceSuffix := filepath.ToSlash(newSourceURL.Path)
@@ -201,18 +271,26 @@ func makeCopyContentTypeC(sourceAlias string, sourceURL ClientURL, sourceContent
 		sourcePrefix := filepath.ToSlash(sourceURL.Path[:pathSeparatorIndex])
 		newSourceSuffix = strings.TrimPrefix(newSourceSuffix, sourcePrefix)
 	}
+	// Keys are rewritten by --name-transform without their leading slash.
+	if transform != nil {
+		key := transform.Apply(strings.TrimPrefix(newSourceSuffix, "/"))
+		if key == "" {
+			return URLs{}, false
+		}
+		newSourceSuffix = "/" + key
+	}
 	newTargetURL := urlJoinPath(targetURL, newSourceSuffix)
-	return makeCopyContentTypeA(sourceAlias, sourceContent, targetAlias, newTargetURL)
+	return makeCopyContentTypeA(sourceAlias, sourceContent, targetAlias, newTargetURL), true
 }
 
 // MULTI-SOURCE - Type D: copy([](f|d...), d) -> []B
 // prepareCopyURLsTypeE - prepares target and source clientURLs for copying.
-func prepareCopyURLsTypeD(ctx context.Context, sourceURLs []string, targetURL string, isRecursive bool, timeRef time.Time) <-chan URLs {
+func prepareCopyURLsTypeD(ctx context.Context, sourceURLs []string, targetURL string, isRecursive, direntOnly, withVersions bool, listWorkers, maxDepth int, timeRef time.Time, pathFilter *pathFilter, transform *nameTransform) <-chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(sourceURLs []string, targetURL string, copyURLsCh chan URLs) {
 		defer close(copyURLsCh)
 		for _, sourceURL := range sourceURLs {
-			for cpURLs := range prepareCopyURLsTypeC(ctx, sourceURL, targetURL, isRecursive, false, timeRef) {
+			for cpURLs := range prepareCopyURLsTypeC(ctx, sourceURL, targetURL, isRecursive, false, direntOnly, withVersions, listWorkers, maxDepth, timeRef, pathFilter, transform) {
 				copyURLsCh <- cpURLs
 			}
 		}
@@ -224,11 +302,33 @@ type prepareCopyURLsOpts struct {
 	sourceURLs           []string
 	targetURL            string
 	isRecursive          bool
-	encKeyDB             map[string][]prefixSSEPair
+	encKeyDB             encryptionConfig
 	olderThan, newerThan string
+	filter               *contentFilter
 	timeRef              time.Time
 	versionID            string
 	isZip                bool
+	// Override the guess of the target type.
+	targetIsDir, targetIsFile bool
+	// List local files without their size and modification time, see
+	// ListOptions.DirentOnly.
+	direntOnly bool
+	// Paths of recursive copies selected by --include and --exclude.
+	pathFilter *pathFilter
+	// Copy every version and delete marker of the objects.
+	withVersions bool
+	// Manifest of the keys to copy, '-' for stdin.
+	filesFrom string
+	// Rewrites the keys of recursive copies, see --name-transform and
+	// --strip-components.
+	nameTransform *nameTransform
+	// Number of folders or prefixes listed in parallel by recursive
+	// copies, MC_FS_WALK_WORKERS for local folders if unset.
+	listWorkers int
+	// Folder levels copied by recursive copies, without limit if 0.
+	maxDepth int
+	// Copy named pipes and devices given as sources, see --special-files.
+	specialFiles bool
 }
 
 // prepareCopyURLs - prepares target and source clientURLs for copying.
@@ -236,20 +336,26 @@ func prepareCopyURLs(ctx context.Context, o prepareCopyURLsOpts) chan URLs {
 	copyURLsCh := make(chan URLs)
 	go func(o prepareCopyURLsOpts) {
 		defer close(copyURLsCh)
+		if o.filesFrom != "" {
+			for cURLs := range prepareCopyURLsFilesFrom(ctx, o) {
+				copyURLsCh <- cURLs
+			}
+			return
+		}
 		cpType, cpVersion, err := guessCopyURLType(ctx, o)
 		fatalIf(err.Trace(), "Unable to guess the type of copy operation.")
 
 		switch cpType {
 		case copyURLsTypeA:
-			copyURLsCh <- prepareCopyURLsTypeA(ctx, o.sourceURLs[0], cpVersion, o.targetURL, o.encKeyDB, o.isZip)
+			copyURLsCh <- prepareCopyURLsTypeA(ctx, o.sourceURLs[0], cpVersion, o.targetURL, o.encKeyDB, o.isZip, o.specialFiles)
 		case copyURLsTypeB:
-			copyURLsCh <- prepareCopyURLsTypeB(ctx, o.sourceURLs[0], cpVersion, o.targetURL, o.encKeyDB, o.isZip)
+			copyURLsCh <- prepareCopyURLsTypeB(ctx, o.sourceURLs[0], cpVersion, o.targetURL, o.encKeyDB, o.isZip, o.specialFiles)
 		case copyURLsTypeC:
-			for cURLs := range prepareCopyURLsTypeC(ctx, o.sourceURLs[0], o.targetURL, o.isRecursive, o.isZip, o.timeRef) {
+			for cURLs := range prepareCopyURLsTypeC(ctx, o.sourceURLs[0], o.targetURL, o.isRecursive, o.isZip, o.direntOnly, o.withVersions, o.listWorkers, o.maxDepth, o.timeRef, o.pathFilter, o.nameTransform) {
 				copyURLsCh <- cURLs
 			}
 		case copyURLsTypeD:
-			for cURLs := range prepareCopyURLsTypeD(ctx, o.sourceURLs, o.targetURL, o.isRecursive, o.timeRef) {
+			for cURLs := range prepareCopyURLsTypeD(ctx, o.sourceURLs, o.targetURL, o.isRecursive, o.direntOnly, o.withVersions, o.listWorkers, o.maxDepth, o.timeRef, o.pathFilter, o.nameTransform) {
 				copyURLsCh <- cURLs
 			}
 		default:
@@ -271,6 +377,11 @@ func prepareCopyURLs(ctx context.Context, o prepareCopyURLsOpts) chan URLs {
 				continue
 			}
 
+			// Skip objects not matching --filter if specified
+			if cpURLs.Error == nil && !o.filter.Match(cpURLs.SourceContent) {
+				continue
+			}
+
 			finalCopyURLsCh <- cpURLs
 		}
 	}()
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// defaultVerifyChecksum is the algorithm of 'cp --verify' when no
+// --checksum is requested.
+const defaultVerifyChecksum = "CRC32C"
+
+// hashURL reads an object and returns its base64 encoded checksum.
+func hashURL(ctx context.Context, alias, urlStr, algorithm string, opts GetOptions) (string, *probe.Error) {
+	reader, _, err := getSourceStream(ctx, alias, urlStr, getSourceOpts{GetOptions: opts})
+	if err != nil {
+		return "", err.Trace(alias, urlStr)
+	}
+	defer reader.Close()
+
+	hasher := newChecksumHasher(algorithm)
+	if _, e := io.Copy(hasher, reader); e != nil {
+		return "", probe.NewError(e).Trace(alias, urlStr)
+	}
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// verifyCopy reads back the target of a copy and compares its checksum
+// against the source, the checksum computed on a single part upload is
+// used instead of reading the source again. Multipart uploads only have
+// a checksum of their parts, they are always compared by reading both.
+func verifyCopy(ctx context.Context, urls URLs, encKeyDB encryptionConfig) (checksum string, err *probe.Error) {
+	algorithm := urls.Checksum
+	if algorithm == "" {
+		algorithm = defaultVerifyChecksum
+	}
+
+	sourcePath := filepath.ToSlash(filepath.Join(urls.SourceAlias, urls.SourceContent.URL.Path))
+	targetPath := filepath.ToSlash(filepath.Join(urls.TargetAlias, urls.TargetContent.URL.Path))
+
+	expected := urls.ChecksumValue
+	if expected == "" || strings.Contains(expected, "-") {
+		expected, err = hashURL(ctx, urls.SourceAlias, urls.SourceContent.URL.String(), algorithm, GetOptions{
+			SSE:       getSSE(sourcePath, encKeyDB[urls.SourceAlias]),
+			VersionID: urls.SourceContent.VersionID,
+			LambdaArn: urls.LambdaArn,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	actual, err := hashURL(ctx, urls.TargetAlias, urls.TargetContent.URL.String(), algorithm, GetOptions{
+		SSE: getSSE(targetPath, encKeyDB[urls.TargetAlias]),
+	})
+	if err != nil {
+		return "", err
+	}
+	if actual != expected {
+		return "", probe.NewError(ObjectChecksumMismatch{
+			Object:    targetPath,
+			Algorithm: algorithm,
+			Expected:  expected,
+			Actual:    actual,
+		})
+	}
+	return algorithm + ":" + actual, nil
+}
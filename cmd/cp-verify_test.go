@@ -0,0 +1,110 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyCopy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		fpath := filepath.Join(dir, name)
+		if e := os.WriteFile(fpath, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		return fpath
+	}
+	source := write("source", "hello world")
+	same := write("same", "hello world")
+	changed := write("changed", "hello there")
+
+	crc := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	crc.Write([]byte("hello world"))
+	crcSum := base64.StdEncoding.EncodeToString(crc.Sum(nil))
+	shaSum := sha256.Sum256([]byte("hello world"))
+
+	testCases := []struct {
+		target        string
+		algorithm     string
+		checksumValue string
+		expected      string
+		mismatch      bool
+	}{
+		// Without --checksum, both sides are read and hashed with CRC32C.
+		{same, "", "", "CRC32C:" + crcSum, false},
+		{same, "SHA256", "", "SHA256:" + base64.StdEncoding.EncodeToString(shaSum[:]), false},
+		{changed, "", "", "", true},
+		// The checksum of a single part upload is trusted, not the one of a multipart upload.
+		{same, "CRC32C", crcSum, "CRC32C:" + crcSum, false},
+		{same, "CRC32C", "AAAAAA==", "", true},
+		{same, "CRC32C", "AAAAAA==-2", "CRC32C:" + crcSum, false},
+	}
+	for i, testCase := range testCases {
+		urls := URLs{
+			SourceContent: &ClientContent{URL: *newClientURL(source)},
+			TargetContent: &ClientContent{URL: *newClientURL(testCase.target)},
+			Checksum:      testCase.algorithm,
+			ChecksumValue: testCase.checksumValue,
+		}
+		checksum, err := verifyCopy(context.Background(), urls, nil)
+		if testCase.mismatch {
+			if err == nil {
+				t.Errorf("Test %d: expected a checksum mismatch", i+1)
+				continue
+			}
+			if _, ok := err.ToGoError().(ObjectChecksumMismatch); !ok {
+				t.Errorf("Test %d: expected ObjectChecksumMismatch, got %v", i+1, err.ToGoError())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+			continue
+		}
+		if checksum != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, checksum)
+		}
+	}
+
+	// A target which cannot be read back fails the verification.
+	urls := URLs{
+		SourceContent: &ClientContent{URL: *newClientURL(source)},
+		TargetContent: &ClientContent{URL: *newClientURL(filepath.Join(dir, "missing"))},
+	}
+	if _, err := verifyCopy(context.Background(), urls, nil); err == nil {
+		t.Fatal("expected a missing target to fail the verification")
+	}
+}
+
+func TestVerifiedCopyMessage(t *testing.T) {
+	msg := copyMessage{Source: "a", Target: "b", Checksum: "CRC32C:AAAAAA==", Verified: true}
+	if s := msg.String(); !strings.Contains(s, "(CRC32C:AAAAAA==, verified)") {
+		t.Fatalf("expected the verified checksum in %q", s)
+	}
+	if s := msg.JSON(); !strings.Contains(s, "verified") {
+		t.Fatalf("expected verified in %s", s)
+	}
+}
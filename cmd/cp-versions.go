@@ -0,0 +1,148 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+)
+
+// oldestVersionsFirst reorders a versioned listing, which lists the
+// versions of an object from the latest, so that the versions of every
+// object are sent from the oldest and are copied in the order they were
+// written.
+func oldestVersionsFirst(ctx context.Context, contentCh <-chan *ClientContent) <-chan *ClientContent {
+	orderedCh := make(chan *ClientContent)
+	go func() {
+		defer close(orderedCh)
+
+		var versions []*ClientContent
+		flush := func() bool {
+			for i := len(versions) - 1; i >= 0; i-- {
+				select {
+				case <-ctx.Done():
+					return false
+				case orderedCh <- versions[i]:
+				}
+			}
+			versions = versions[:0]
+			return true
+		}
+		for content := range contentCh {
+			if content.Err == nil && len(versions) > 0 && versions[0].URL.String() == content.URL.String() {
+				versions = append(versions, content)
+				continue
+			}
+			if !flush() {
+				return
+			}
+			if content.Err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case orderedCh <- content:
+				}
+				continue
+			}
+			versions = append(versions, content)
+		}
+		flush()
+	}()
+	return orderedCh
+}
+
+// isTargetVersioned returns true if versioning is enabled on the bucket
+// of a target, delete markers can only be copied to such targets.
+func isTargetVersioned(ctx context.Context, alias, urlStr string) bool {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return false
+	}
+	vcfg, err := clnt.GetVersion(ctx)
+	return err == nil && vcfg.Status == "Enabled"
+}
+
+// copySourceID identifies the source of a copy in a session, every
+// version copied by 'cp --versions' is recorded on its own.
+func copySourceID(urls URLs) string {
+	source := urls.SourceContent.URL.String()
+	if urls.SourceContent.VersionID != "" {
+		source += "?versionId=" + urls.SourceContent.VersionID
+	}
+	return source
+}
+
+// copyDeleteMarker copies a delete marker listed by 'cp --versions',
+// removing the target object creates a delete marker on top of the
+// versions copied before it.
+func copyDeleteMarker(ctx context.Context, urls URLs) URLs {
+	targetPath := filepath.ToSlash(filepath.Join(urls.TargetAlias, urls.TargetContent.URL.Path))
+	clnt, err := newClientFromAlias(urls.TargetAlias, urls.TargetContent.URL.String())
+	if err != nil {
+		return urls.WithError(err.Trace(targetPath))
+	}
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: urls.TargetContent.URL}
+	close(contentCh)
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			return urls.WithError(result.Err.Trace(targetPath))
+		}
+	}
+	printMsg(rmMessage{Key: targetPath, DeleteMarker: true})
+	return urls.WithError(nil)
+}
+
+// versionSequencer runs the copies of the versions of an object one
+// after the other, in the order they are queued, while the copies of
+// different objects run in parallel.
+type versionSequencer struct {
+	mutex sync.Mutex
+	last  map[string]chan struct{}
+}
+
+func newVersionSequencer() *versionSequencer {
+	return &versionSequencer{last: make(map[string]chan struct{})}
+}
+
+// next is called when the copy of a version is queued, it returns a
+// channel closed once the previous version of the same object is
+// copied, and a function to call once this version is copied.
+func (s *versionSequencer) next(key string) (previous <-chan struct{}, done func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current := make(chan struct{})
+	previous = s.last[key]
+	if previous == nil {
+		closed := make(chan struct{})
+		close(closed)
+		previous = closed
+	}
+	s.last[key] = current
+	return previous, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		close(current)
+		if s.last[key] == current {
+			delete(s.last, key)
+		}
+	}
+}
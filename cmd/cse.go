@@ -0,0 +1,190 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/env"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/packet"
+	// Hash assumed for OpenPGP keys without hash preferences.
+	_ "golang.org/x/crypto/ripemd160"
+)
+
+var cseKeyFlag = cli.StringFlag{
+	Name:  "cse-key",
+	Usage: "encrypt uploaded objects on the client with the local key or the OpenPGP keys of FILE, they are decrypted again when downloaded",
+}
+
+const (
+	// Format of the objects encrypted by --cse-key, and their size
+	// before encryption.
+	metadataCSEKey           = "X-Amz-Meta-Mc-Cse"
+	metadataPlaintextSizeKey = "X-Amz-Meta-Mc-Plaintext-Size"
+
+	cseFormatOpenPGP = "openpgp"
+)
+
+// cseKey is a key of client-side encryption, either OpenPGP keys or a
+// local key. Objects are OpenPGP messages in both cases, encrypted for
+// the public keys or with the local key as passphrase.
+type cseKey struct {
+	entities openpgp.EntityList
+	secret   []byte
+}
+
+var cseConfig = &packet.Config{DefaultCipher: packet.CipherAES256}
+
+// Keys loaded by loadCSEKey, by the path of their file.
+var cseKeys sync.Map
+
+// parseCSEKey parses the content of a key file, OpenPGP keys armored
+// or not and a local key otherwise. Secret OpenPGP keys protected by a
+// passphrase are unlocked with passphrase.
+func parseCSEKey(data, passphrase []byte) (*cseKey, *probe.Error) {
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("-----BEGIN PGP")) {
+		entities, e := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		return newCSEKeyEntities(entities, passphrase)
+	}
+	if entities, e := openpgp.ReadKeyRing(bytes.NewReader(data)); e == nil {
+		return newCSEKeyEntities(entities, passphrase)
+	}
+	if len(data) < 16 {
+		return nil, probe.NewError(errors.New("a local key must be at least 16 bytes long"))
+	}
+	return &cseKey{secret: data}, nil
+}
+
+func newCSEKeyEntities(entities openpgp.EntityList, passphrase []byte) (*cseKey, *probe.Error) {
+	for _, entity := range entities {
+		keys := []*packet.PrivateKey{entity.PrivateKey}
+		for _, subkey := range entity.Subkeys {
+			keys = append(keys, subkey.PrivateKey)
+		}
+		for _, key := range keys {
+			if key == nil || !key.Encrypted {
+				continue
+			}
+			if len(passphrase) == 0 {
+				return nil, probe.NewError(errors.New("the secret key is protected by a passphrase, set it in MC_CSE_PASSPHRASE"))
+			}
+			if e := key.Decrypt(passphrase); e != nil {
+				return nil, probe.NewError(e)
+			}
+		}
+	}
+	return &cseKey{entities: entities}, nil
+}
+
+// loadCSEKey returns the key of a key file, read once.
+func loadCSEKey(path string) (*cseKey, *probe.Error) {
+	if key, ok := cseKeys.Load(path); ok {
+		return key.(*cseKey), nil
+	}
+	data, e := os.ReadFile(path)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	key, err := parseCSEKey(data, []byte(env.Get("MC_CSE_PASSPHRASE", "")))
+	if err != nil {
+		return nil, err.Trace(path)
+	}
+	cseKeys.Store(path, key)
+	return key, nil
+}
+
+// checkCSESyntax validates the key file of --cse-key.
+func checkCSESyntax(cliCtx *cli.Context) {
+	path := cliCtx.String("cse-key")
+	if path == "" {
+		return
+	}
+	_, err := loadCSEKey(path)
+	fatalIf(err, "Unable to load the client-side encryption key.")
+	// These compare the target with the source byte for byte, or by
+	// sizes not read from the metadata of the target.
+	for _, flag := range []string{"verify", "state-db", "two-way"} {
+		if cliCtx.IsSet(flag) {
+			fatalIf(errInvalidArgument().Trace(flag), "--%s cannot be used with --cse-key.", flag)
+		}
+	}
+}
+
+// encryptReader returns a reader of r encrypted with key.
+func encryptReader(r io.Reader, key *cseKey) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		var w io.WriteCloser
+		var e error
+		// The headers are written right away, only once the pipe
+		// is read.
+		if key.entities != nil {
+			w, e = openpgp.Encrypt(pw, key.entities, nil, nil, cseConfig)
+		} else {
+			w, e = openpgp.SymmetricallyEncrypt(pw, key.secret, nil, cseConfig)
+		}
+		if e == nil {
+			_, e = io.Copy(w, r)
+			if ce := w.Close(); e == nil {
+				e = ce
+			}
+		}
+		pw.CloseWithError(e)
+	}()
+	return pr
+}
+
+// decryptReader returns a reader of r decrypted with key. The
+// integrity of the content is checked when its end is read.
+func decryptReader(r io.Reader, key *cseKey) (io.Reader, *probe.Error) {
+	prompted := false
+	prompt := func(_ []openpgp.Key, symmetric bool) ([]byte, error) {
+		if prompted || !symmetric || key.secret == nil {
+			return nil, errors.New("the content is not encrypted for the client-side encryption key")
+		}
+		prompted = true
+		return key.secret, nil
+	}
+	md, e := openpgp.ReadMessage(r, key.entities, prompt, cseConfig)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	return md.UnverifiedBody, nil
+}
+
+// plaintextSize returns the size of an object before --cse-key
+// encrypted it, if it did.
+func plaintextSize(content *ClientContent) int64 {
+	size, e := strconv.ParseInt(content.UserMetadata[metadataPlaintextSizeKey], 10, 64)
+	if e != nil {
+		return content.Size
+	}
+	return size
+}
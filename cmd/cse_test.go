@@ -0,0 +1,145 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+// newTestCSEKeys returns the armored public and secret keys of a new
+// OpenPGP entity.
+func newTestCSEKeys(t *testing.T) (public, secret []byte) {
+	entity, e := openpgp.NewEntity("mc", "", "mc@example.com", nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var publicBuf, secretBuf bytes.Buffer
+	w, e := armor.Encode(&publicBuf, openpgp.PublicKeyType, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = entity.Serialize(w); e != nil {
+		t.Fatal(e)
+	}
+	w.Close()
+
+	w, e = armor.Encode(&secretBuf, openpgp.PrivateKeyType, nil)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if e = entity.SerializePrivate(w, nil); e != nil {
+		t.Fatal(e)
+	}
+	w.Close()
+	return publicBuf.Bytes(), secretBuf.Bytes()
+}
+
+func TestCSERoundTrip(t *testing.T) {
+	public, secret := newTestCSEKeys(t)
+	other, _ := newTestCSEKeys(t)
+
+	testCases := []struct {
+		encryptKey, decryptKey []byte
+		ok                     bool
+	}{
+		{[]byte("0123456789abcdef0123456789abcdef\n"), []byte("0123456789abcdef0123456789abcdef"), true},
+		{[]byte("0123456789abcdef0123456789abcdef"), []byte("fedcba9876543210fedcba9876543210"), false},
+		{public, secret, true},
+		{other, secret, false},
+		{public, []byte("0123456789abcdef0123456789abcdef"), false},
+	}
+	plaintext := strings.Repeat("client-side encryption ", 10000)
+	for i, testCase := range testCases {
+		encryptKey, err := parseCSEKey(testCase.encryptKey, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		decryptKey, err := parseCSEKey(testCase.decryptKey, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+
+		ciphertext, e := io.ReadAll(encryptReader(strings.NewReader(plaintext), encryptKey))
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if bytes.Contains(ciphertext, []byte("client-side")) {
+			t.Fatalf("Test %d: expected the content to be encrypted", i+1)
+		}
+
+		reader, err := decryptReader(bytes.NewReader(ciphertext), decryptKey)
+		if !testCase.ok {
+			if err == nil {
+				_, e = io.ReadAll(reader)
+			}
+			if err == nil && e == nil {
+				t.Fatalf("Test %d: expected decryption to fail", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		decrypted, e := io.ReadAll(reader)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if string(decrypted) != plaintext {
+			t.Fatalf("Test %d: decrypted content differs", i+1)
+		}
+	}
+}
+
+func TestParseCSEKey(t *testing.T) {
+	if _, err := parseCSEKey([]byte("short"), nil); err == nil {
+		t.Fatal("expected a short local key to be rejected")
+	}
+
+	key, err := parseCSEKey([]byte("0123456789abcdef\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(key.secret) != "0123456789abcdef" || key.entities != nil {
+		t.Fatalf("expected a local key, got %+v", key)
+	}
+
+	_, secret := newTestCSEKeys(t)
+	if key, err = parseCSEKey(secret, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(key.entities) != 1 || key.secret != nil {
+		t.Fatalf("expected an OpenPGP key, got %+v", key)
+	}
+}
+
+func TestPlaintextSize(t *testing.T) {
+	content := &ClientContent{Size: 100}
+	if size := uncompressedSize(content); size != 100 {
+		t.Fatalf("expected 100, got %d", size)
+	}
+	content.UserMetadata = map[string]string{metadataCSEKey: cseFormatOpenPGP, metadataPlaintextSizeKey: "42"}
+	if size := uncompressedSize(content); size != 42 {
+		t.Fatalf("expected the size before encryption, got %d", size)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/signer"
+	"golang.org/x/net/http/httpguts"
+)
+
+// Headers computed by the request signer, they cannot be overridden.
+var reservedCustomHeaders = map[string]bool{
+	"Authorization":        true,
+	"Host":                 true,
+	"Content-Length":       true,
+	"X-Amz-Date":           true,
+	"X-Amz-Content-Sha256": true,
+	"X-Amz-Security-Token": true,
+}
+
+// parseCustomHeaders parses the values of --header, each of the
+// form 'Key: Value'.
+func parseCustomHeaders(values []string) (http.Header, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	headers := make(http.Header)
+	for _, value := range values {
+		k, v, ok := strings.Cut(value, ":")
+		k = strings.TrimSpace(k)
+		if !ok || !httpguts.ValidHeaderFieldName(k) {
+			return nil, fmt.Errorf("invalid header `%s`, expected 'Key: Value'", value)
+		}
+		v = strings.TrimSpace(v)
+		if !httpguts.ValidHeaderFieldValue(v) {
+			return nil, fmt.Errorf("invalid value for header `%s`", k)
+		}
+		if reservedCustomHeaders[http.CanonicalHeaderKey(k)] {
+			return nil, fmt.Errorf("header `%s` is set by mc and cannot be overridden", k)
+		}
+		headers.Add(k, v)
+	}
+	return headers, nil
+}
+
+// parseCustomQuery parses the values of --query, each of the form 'k=v'.
+func parseCustomQuery(values []string) (url.Values, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	query := make(url.Values)
+	for _, value := range values {
+		k, v, _ := strings.Cut(value, "=")
+		if k == "" {
+			return nil, fmt.Errorf("invalid query parameter `%s`, expected 'k=v'", value)
+		}
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-") {
+			return nil, fmt.Errorf("query parameter `%s` is reserved for request signing", k)
+		}
+		query.Add(k, v)
+	}
+	return query, nil
+}
+
+// customRequestTransport adds the headers and query parameters given
+// with --header and --query, and the request payer of Requester Pays
+// buckets, to every request. Requests are signed before they reach the
+// transport, custom headers are sent unsigned while query parameters
+// and x-amz-* headers are part of a V4 signature, so these requests
+// are signed again.
+type customRequestTransport struct {
+	transport    http.RoundTripper
+	headers      http.Header
+	query        url.Values
+	requestPayer string
+
+	accessKey, secretKey, sessionToken string
+}
+
+func (t customRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrip must not modify the caller's request.
+	req = req.Clone(req.Context())
+	for k, v := range t.headers {
+		req.Header[k] = v
+	}
+	resign := false
+	if t.requestPayer != "" && req.Header.Get(amzRequestPayer) == "" {
+		req.Header.Set(amzRequestPayer, t.requestPayer)
+		resign = true
+	}
+	if len(t.query) > 0 {
+		query := req.URL.Query()
+		for k, v := range t.query {
+			query[k] = v
+		}
+		// Keep the encoding of the signer, spaces are '%20' and not '+'.
+		req.URL.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+		resign = true
+	}
+	if !resign {
+		return t.transport.RoundTrip(req)
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, signV4Algorithm) {
+		// Anonymous, presigned and V2 signed requests, V2 only signs
+		// sub-resources which are never custom.
+		return t.transport.RoundTrip(req)
+	}
+	if strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return nil, errors.New("--query and Requester Pays buckets cannot be used with streaming signed uploads, use a TLS endpoint instead")
+	}
+	region := authorizationRegion(auth)
+	req.Header.Del("Authorization")
+	return t.transport.RoundTrip(signer.SignV4Trailer(*req, t.accessKey, t.secretKey, t.sessionToken, region, req.Trailer))
+}
+
+// signV4Algorithm prefixes the Authorization header of V4 signed requests.
+const signV4Algorithm = "AWS4-HMAC-SHA256"
+
+// authorizationRegion returns the region of the credential scope of a V4
+// Authorization header, 'Credential=AK/20230101/us-east-1/s3/aws4_request'.
+func authorizationRegion(auth string) string {
+	_, credential, ok := strings.Cut(auth, "Credential=")
+	if !ok {
+		return ""
+	}
+	credential, _, _ = strings.Cut(credential, ",")
+	scope := strings.Split(credential, "/")
+	if len(scope) < 5 {
+		return ""
+	}
+	// Access keys may contain '/', the scope is always the last four parts.
+	return scope[len(scope)-3]
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// Changes of an object on one side of a three-way diff.
+const (
+	changeNone     = ""
+	changeAdded    = "added"
+	changeModified = "modified"
+	changeDeleted  = "deleted"
+)
+
+// treeEntry is an object of a tree compared by a three-way diff.
+type treeEntry struct {
+	Size int64
+	ETag string
+	Time time.Time
+}
+
+// sameContent returns true if both entries are copies of the same
+// object, by ETag when both have one, otherwise by size and time.
+func (e treeEntry) sameContent(other treeEntry) bool {
+	if e.Size != other.Size {
+		return false
+	}
+	if e.ETag != "" && other.ETag != "" {
+		return e.ETag == other.ETag
+	}
+	return e.Time.Equal(other.Time)
+}
+
+// baseDiffMessage is a key changed since the base on either side of a
+// three-way diff, its JSON form is the plan of a merge.
+type baseDiffMessage struct {
+	Status   string `json:"status"`
+	Key      string `json:"key"`
+	First    string `json:"first,omitempty"`
+	Second   string `json:"second,omitempty"`
+	Conflict bool   `json:"conflict"`
+}
+
+// changeLetters are the letters of the changes in the String form.
+var changeLetters = map[string]string{
+	changeNone:     "-",
+	changeAdded:    "A",
+	changeModified: "M",
+	changeDeleted:  "D",
+}
+
+// String colorized three-way diff message.
+func (d baseDiffMessage) String() string {
+	msg := changeLetters[d.First] + changeLetters[d.Second] + " " + d.Key
+	if d.Conflict {
+		return console.Colorize("DiffConflict", "C "+msg)
+	}
+	return console.Colorize("DiffChange", "  "+msg)
+}
+
+// JSON jsonified three-way diff message.
+func (d baseDiffMessage) JSON() string {
+	d.Status = "success"
+	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal diff message `"+d.Key+"`.")
+	return string(diffJSONBytes)
+}
+
+// checkDiffBaseSyntax returns why --base cannot be used, if so.
+func checkDiffBaseSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.IsSet("base") {
+		return ""
+	}
+	if cliCtx.String("base") == "" {
+		return "--base requires the path of a manifest."
+	}
+	if cliCtx.Bool("content") || cliCtx.Bool("checksum") {
+		return "--base cannot be used with --content or --checksum."
+	}
+	return ""
+}
+
+// readBaseManifest reads the objects of a base snapshot, which is the
+// output of 'mc ls --recursive --json' of the tree at that time.
+func readBaseManifest(r io.Reader) (map[string]treeEntry, *probe.Error) {
+	base := make(map[string]treeEntry)
+	decoder := json.NewDecoder(r)
+	for {
+		var msg contentMessage
+		e := decoder.Decode(&msg)
+		if errors.Is(e, io.EOF) {
+			return base, nil
+		}
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		if msg.Status == "error" || msg.Filetype == "folder" || msg.IsDeleteMarker {
+			continue
+		}
+		base[msg.Key] = treeEntry{Size: msg.Size, ETag: strings.Trim(msg.ETag, "\""), Time: msg.Time}
+	}
+}
+
+// listTree returns the objects of a tree by their key relative to it.
+func listTree(ctx context.Context, clnt Client) (map[string]treeEntry, *probe.Error) {
+	tree := make(map[string]treeEntry)
+	rootURL := clnt.GetURL()
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			switch content.Err.ToGoError().(type) {
+			case ObjectMissing, PathNotFound, BucketDoesNotExist:
+				// A missing tree has no objects.
+				return tree, nil
+			}
+			return nil, content.Err.Trace(rootURL.String())
+		}
+		key := strings.TrimPrefix(content.URL.Path, rootURL.Path)
+		if rootURL.Separator != '/' {
+			key = strings.ReplaceAll(key, string(rootURL.Separator), "/")
+		}
+		tree[strings.TrimPrefix(key, "/")] = treeEntry{
+			Size: uncompressedSize(content),
+			ETag: strings.Trim(content.ETag, "\""),
+			Time: content.Time,
+		}
+	}
+	return tree, nil
+}
+
+// changeSince returns the change of an object on one side since the base.
+func changeSince(base, side treeEntry, inBase, inSide bool) string {
+	switch {
+	case !inBase && inSide:
+		return changeAdded
+	case inBase && !inSide:
+		return changeDeleted
+	case inBase && inSide && !base.sameContent(side):
+		return changeModified
+	}
+	return changeNone
+}
+
+// baseDifference classifies the changes of both trees since the base by
+// key, in sorted order. Keys changed on both sides conflict unless both
+// were deleted or both changed to the same content.
+func baseDifference(base, first, second map[string]treeEntry) []baseDiffMessage {
+	keys := make(map[string]struct{}, len(base))
+	for _, tree := range []map[string]treeEntry{base, first, second} {
+		for key := range tree {
+			keys[key] = struct{}{}
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	var msgs []baseDiffMessage
+	for _, key := range sorted {
+		baseEntry, inBase := base[key]
+		firstEntry, inFirst := first[key]
+		secondEntry, inSecond := second[key]
+		msg := baseDiffMessage{
+			Key:    key,
+			First:  changeSince(baseEntry, firstEntry, inBase, inFirst),
+			Second: changeSince(baseEntry, secondEntry, inBase, inSecond),
+		}
+		if msg.First == changeNone && msg.Second == changeNone {
+			continue
+		}
+		if msg.First != changeNone && msg.Second != changeNone {
+			msg.Conflict = inFirst != inSecond || inFirst && !firstEntry.sameContent(secondEntry)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// doDiffBase runs a three-way diff of two trees against a base manifest.
+func doDiffBase(ctx context.Context, manifest, firstURL, secondURL string) error {
+	f, e := os.Open(manifest)
+	fatalIf(probe.NewError(e).Trace(manifest), "Unable to open the base manifest.")
+	defer f.Close()
+	base, err := readBaseManifest(f)
+	fatalIf(err.Trace(manifest), "Unable to read the base manifest.")
+
+	var trees [2]map[string]treeEntry
+	for i, aliasedURL := range []string{firstURL, secondURL} {
+		separator := string(newClientURL(aliasedURL).Separator)
+		if !strings.HasSuffix(aliasedURL, separator) {
+			aliasedURL += separator
+		}
+		alias, urlStr, _ := mustExpandAlias(aliasedURL)
+		clnt, err := newClientFromAlias(alias, urlStr)
+		fatalIf(err.Trace(alias, urlStr), fmt.Sprintf("Unable to diff '%s'.", urlStr))
+		trees[i], err = listTree(ctx, clnt)
+		fatalIf(err.Trace(alias, urlStr), fmt.Sprintf("Unable to list '%s'.", filepath.ToSlash(urlStr)))
+	}
+
+	for _, msg := range baseDifference(base, trees[0], trees[1]) {
+		printMsg(msg)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckDiffBaseSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"first", "second"}, true},
+		{[]string{"--base", "snapshot.json", "first", "second"}, true},
+		{[]string{"--base", "", "first", "second"}, false},
+		{[]string{"--base", "snapshot.json", "--checksum", "first", "second"}, false},
+		{[]string{"--base", "snapshot.json", "--content", "first", "second"}, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, diffFlags, testCase.args...)
+		if msg := checkDiffBaseSyntax(cliCtx); (msg == "") != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %q", i+1, testCase.valid, msg)
+		}
+	}
+}
+
+func TestReadBaseManifest(t *testing.T) {
+	manifest := `{"status":"success","type":"file","lastModified":"2023-05-01T12:00:00Z","size":5,"key":"a","etag":"\"e1\""}
+{"status":"success","type":"folder","lastModified":"2023-05-01T12:00:00Z","size":0,"key":"dir/","etag":""}
+{"status":"success","type":"file","lastModified":"2023-05-01T12:00:00Z","size":0,"key":"b","etag":"","isDeleteMarker":true}
+{"status":"success","type":"file","lastModified":"2023-05-01T12:00:00Z","size":3,"key":"dir/c","etag":""}
+`
+	base, err := readBaseManifest(strings.NewReader(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	expected := map[string]treeEntry{
+		"a":     {Size: 5, ETag: "e1", Time: modTime},
+		"dir/c": {Size: 3, Time: modTime},
+	}
+	if len(base) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, base)
+	}
+	for key, entry := range expected {
+		if got := base[key]; got.Size != entry.Size || got.ETag != entry.ETag || !got.Time.Equal(entry.Time) {
+			t.Errorf("%s: expected %v, got %v", key, entry, got)
+		}
+	}
+
+	if _, err := readBaseManifest(strings.NewReader("not json")); err == nil {
+		t.Error("expected an error reading an invalid manifest")
+	}
+}
+
+func TestBaseDifference(t *testing.T) {
+	v1 := treeEntry{Size: 1, ETag: "v1"}
+	v2 := treeEntry{Size: 2, ETag: "v2"}
+	v3 := treeEntry{Size: 2, ETag: "v3"}
+	base := map[string]treeEntry{
+		"unchanged": v1, "modified-first": v1, "deleted-second": v1,
+		"deleted-both": v1, "modified-both-same": v1, "modified-both": v1, "modified-deleted": v1,
+	}
+	first := map[string]treeEntry{
+		"unchanged": v1, "modified-first": v2, "deleted-second": v1,
+		"modified-both-same": v2, "modified-both": v2, "modified-deleted": v2,
+		"added-first": v1, "added-both": v2,
+	}
+	second := map[string]treeEntry{
+		"unchanged": v1, "modified-first": v1,
+		"modified-both-same": v2, "modified-both": v3,
+		"added-both": v3,
+	}
+	expected := []baseDiffMessage{
+		{Key: "added-both", First: changeAdded, Second: changeAdded, Conflict: true},
+		{Key: "added-first", First: changeAdded},
+		{Key: "deleted-both", First: changeDeleted, Second: changeDeleted},
+		{Key: "deleted-second", Second: changeDeleted},
+		{Key: "modified-both", First: changeModified, Second: changeModified, Conflict: true},
+		{Key: "modified-both-same", First: changeModified, Second: changeModified},
+		{Key: "modified-deleted", First: changeModified, Second: changeDeleted, Conflict: true},
+		{Key: "modified-first", First: changeModified},
+	}
+	if got := baseDifference(base, first, second); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	// Without ETags, objects are compared by size and time.
+	modTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	base = map[string]treeEntry{"a": {Size: 1, Time: modTime}}
+	first = map[string]treeEntry{"a": {Size: 1, Time: modTime.Add(time.Hour)}}
+	second = map[string]treeEntry{"a": {Size: 1, Time: modTime}}
+	expected = []baseDiffMessage{{Key: "a", First: changeModified}}
+	if got := baseDifference(base, first, second); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestListTree(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a", filepath.Join("dir", "b")} {
+		fpath := filepath.Join(dir, name)
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(name), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	clnt, err := newClientFromAlias("", dir+string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := listTree(context.Background(), clnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree) != 2 || tree["a"].Size != 1 || tree["dir/b"].Size != int64(len(filepath.Join("dir", "b"))) {
+		t.Errorf("unexpected tree %v", tree)
+	}
+}
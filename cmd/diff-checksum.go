@@ -0,0 +1,73 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/minio/cli"
+)
+
+// checkDiffChecksumSyntax returns why --checksum cannot be used, if so.
+func checkDiffChecksumSyntax(cliCtx *cli.Context) string {
+	if cliCtx.Bool("checksum") && cliCtx.Bool("content") {
+		return "--checksum cannot be used with --content."
+	}
+	return ""
+}
+
+// checksumDifference compares the checksums of the objects reported as
+// similar by difference, they are reported as differInChecksum if their
+// contents differ. Similar objects following a difference of the same
+// pair, already reported, are left out as are those of another size.
+func checksumDifference(ctx context.Context, firstAlias, secondAlias string, diffCh <-chan diffMessage, encKeyDB encryptionConfig) chan diffMessage {
+	checksumCh := make(chan diffMessage, 10000)
+	go func() {
+		defer close(checksumCh)
+		var previous diffMessage
+		for diffMsg := range diffCh {
+			if diffMsg.Diff != differInNone {
+				previous = diffMsg
+				checksumCh <- diffMsg
+				continue
+			}
+			if previous.FirstURL == diffMsg.FirstURL && previous.SecondURL == diffMsg.SecondURL {
+				continue
+			}
+			first, second := diffMsg.firstContent, diffMsg.secondContent
+			if first.Size != uncompressedSize(second) {
+				continue
+			}
+			same, err := compareChecksum(ctx, URLs{
+				SourceAlias:   firstAlias,
+				SourceContent: first,
+				TargetAlias:   secondAlias,
+				TargetContent: second,
+			}, second, encKeyDB)
+			if err != nil {
+				checksumCh <- diffMessage{Error: err.Trace(diffMsg.FirstURL, diffMsg.SecondURL)}
+				continue
+			}
+			if !same {
+				diffMsg.Diff = differInChecksum
+				checksumCh <- diffMsg
+			}
+		}
+	}()
+	return checksumCh
+}
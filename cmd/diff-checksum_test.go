@@ -0,0 +1,107 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCheckDiffChecksumSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"first", "second"}, true},
+		{[]string{"--checksum", "first", "second"}, true},
+		{[]string{"--content", "first", "second"}, true},
+		{[]string{"--checksum", "--content", "first", "second"}, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, diffFlags, testCase.args...)
+		if msg := checkDiffChecksumSyntax(cliCtx); (msg == "") != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %q", i+1, testCase.valid, msg)
+		}
+	}
+}
+
+func TestChecksumDifference(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	// Same modification times, objects only differ in size or content.
+	modTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	for dir, objects := range map[string]map[string]string{
+		first:  {"same": "hello world", "changed": "hello world", "resized": "hello", "only-first": "1"},
+		second: {"same": "hello world", "changed": "hello there", "resized": "hello world", "only-second": "2"},
+	} {
+		for name, data := range objects {
+			fpath := filepath.Join(dir, name)
+			if e := os.WriteFile(fpath, []byte(data), 0o644); e != nil {
+				t.Fatal(e)
+			}
+			if e := os.Chtimes(fpath, modTime, modTime); e != nil {
+				t.Fatal(e)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	firstClnt, err := newClientFromAlias("", first+string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondClnt, err := newClientFromAlias("", second+string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := ListOptions{Recursive: true, ShowDir: DirNone}
+	diffCh := difference(firstClnt.GetURL().String(), firstClnt.List(ctx, opts),
+		secondClnt.GetURL().String(), secondClnt.List(ctx, opts), false, true)
+
+	got := make(map[string]differType)
+	for diffMsg := range checksumDifference(ctx, "", "", diffCh, nil) {
+		if diffMsg.Error != nil {
+			t.Fatal(diffMsg.Error)
+		}
+		name := filepath.Base(diffMsg.FirstURL)
+		if diffMsg.FirstURL == "" {
+			name = filepath.Base(diffMsg.SecondURL)
+		}
+		if _, ok := got[name]; ok {
+			t.Errorf("%s is reported twice", name)
+		}
+		got[name] = diffMsg.Diff
+	}
+
+	expected := map[string]differType{
+		"changed":     differInChecksum,
+		"resized":     differInSize,
+		"only-first":  differInFirst,
+		"only-second": differInSecond,
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for name, diff := range expected {
+		if got[name] != diff {
+			t.Errorf("%s: expected %s, got %s", name, diff, got[name])
+		}
+	}
+}
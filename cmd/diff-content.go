@@ -0,0 +1,411 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+const (
+	// Objects larger than this are always compared byte-wise.
+	diffContentMaxTextSize = 4 * humanize.MiByte
+	// Number of leading bytes inspected to decide between text and binary.
+	diffContentSniffSize = 8000
+	// Lines of context printed around every change.
+	diffContentContextLines = 3
+	// Maximum number of differing byte ranges reported for binaries.
+	diffContentMaxRanges = 16
+)
+
+// diffByteRange is a contiguous range of differing bytes.
+type diffByteRange struct {
+	Offset int64 `json:"offset"`
+	Length int64 `json:"length"`
+}
+
+// diffContentMessage json container for content diff messages
+type diffContentMessage struct {
+	Status     string          `json:"status"`
+	FirstURL   string          `json:"first"`
+	SecondURL  string          `json:"second"`
+	FirstSize  int64           `json:"firstSize"`
+	SecondSize int64           `json:"secondSize"`
+	Identical  bool            `json:"identical"`
+	Binary     bool            `json:"binary"`
+	Diff       string          `json:"diff,omitempty"`
+	Ranges     []diffByteRange `json:"ranges,omitempty"`
+	MoreRanges bool            `json:"moreRanges,omitempty"`
+}
+
+// String colorized content diff message
+func (d diffContentMessage) String() string {
+	if d.Identical {
+		return console.Colorize("DiffInNone", "= "+d.FirstURL+" "+d.SecondURL)
+	}
+	if !d.Binary {
+		var b strings.Builder
+		for _, line := range strings.SplitAfter(d.Diff, "\n") {
+			trimmed := strings.TrimSuffix(line, "\n")
+			if trimmed == "" && line == "" {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(trimmed, "---"), strings.HasPrefix(trimmed, "+++"):
+				b.WriteString(console.Colorize("DiffMessage", trimmed))
+			case strings.HasPrefix(trimmed, "@@"):
+				b.WriteString(console.Colorize("DiffHunk", trimmed))
+			case strings.HasPrefix(trimmed, "-"):
+				b.WriteString(console.Colorize("DiffOnlyInFirst", trimmed))
+			case strings.HasPrefix(trimmed, "+"):
+				b.WriteString(console.Colorize("DiffOnlyInSecond", trimmed))
+			default:
+				b.WriteString(trimmed)
+			}
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString(console.Colorize("DiffMessage",
+		fmt.Sprintf("Binary objects `%s` and `%s` differ", d.FirstURL, d.SecondURL)))
+	b.WriteString("\n")
+	for _, r := range d.Ranges {
+		b.WriteString(console.Colorize("DiffSize",
+			fmt.Sprintf("  ! bytes %d-%d (%s)", r.Offset, r.Offset+r.Length-1, humanize.IBytes(uint64(r.Length)))))
+		b.WriteString("\n")
+	}
+	if d.MoreRanges {
+		b.WriteString(fmt.Sprintf("  ... more than %d differing ranges, remaining ranges not shown\n", diffContentMaxRanges))
+	}
+	if d.FirstSize != d.SecondSize {
+		b.WriteString(console.Colorize("DiffSize",
+			fmt.Sprintf("  ! sizes differ: %s vs %s", humanize.IBytes(uint64(d.FirstSize)), humanize.IBytes(uint64(d.SecondSize)))))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// JSON jsonified content diff message
+func (d diffContentMessage) JSON() string {
+	d.Status = "success"
+	diffJSONBytes, e := json.MarshalIndent(d, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal diff message `"+d.FirstURL+"`, `"+d.SecondURL+"`.")
+	return string(diffJSONBytes)
+}
+
+// checkDiffContentSyntax verifies that both arguments are objects and
+// returns their stat information.
+func checkDiffContentSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) (firstContent, secondContent *ClientContent) {
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	for _, arg := range cliCtx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+
+	contents := make([]*ClientContent, 2)
+	for i, url := range cliCtx.Args() {
+		_, content, err := url2Stat(ctx, url, "", false, encKeyDB, time.Time{}, false)
+		fatalIf(err.Trace(url), fmt.Sprintf("Unable to stat '%s'.", url))
+		if content.Type.IsDir() {
+			fatalIf(errInvalidArgument().Trace(url), fmt.Sprintf("`%s` is not an object, --content compares two objects.", url))
+		}
+		contents[i] = content
+	}
+	return contents[0], contents[1]
+}
+
+// doDiffContent streams both objects and prints their differences.
+func doDiffContent(ctx context.Context, firstURL, secondURL string, firstSize, secondSize int64, encKeyDB encryptionConfig) error {
+	firstReader, err := getSourceStreamFromURL(ctx, firstURL, encKeyDB, getSourceOpts{})
+	fatalIf(err.Trace(firstURL), "Unable to read `"+firstURL+"`.")
+	defer firstReader.Close()
+
+	secondReader, err := getSourceStreamFromURL(ctx, secondURL, encKeyDB, getSourceOpts{})
+	fatalIf(err.Trace(secondURL), "Unable to read `"+secondURL+"`.")
+	defer secondReader.Close()
+
+	first := bufio.NewReaderSize(firstReader, diffContentSniffSize)
+	second := bufio.NewReaderSize(secondReader, diffContentSniffSize)
+
+	// Same heuristic as git, a NUL byte in the first few kilobytes marks a binary.
+	firstSniff, _ := first.Peek(diffContentSniffSize)
+	secondSniff, _ := second.Peek(diffContentSniffSize)
+	isText := bytes.IndexByte(firstSniff, 0) < 0 && bytes.IndexByte(secondSniff, 0) < 0 &&
+		firstSize <= diffContentMaxTextSize && secondSize <= diffContentMaxTextSize
+
+	msg := diffContentMessage{
+		FirstURL:   firstURL,
+		SecondURL:  secondURL,
+		FirstSize:  firstSize,
+		SecondSize: secondSize,
+		Binary:     !isText,
+	}
+
+	if isText {
+		firstData, e := io.ReadAll(first)
+		fatalIf(probe.NewError(e).Trace(firstURL), "Unable to read `"+firstURL+"`.")
+		secondData, e := io.ReadAll(second)
+		fatalIf(probe.NewError(e).Trace(secondURL), "Unable to read `"+secondURL+"`.")
+		msg.Diff = unifiedDiff(firstURL, secondURL, string(firstData), string(secondData), diffContentContextLines)
+		msg.Identical = msg.Diff == ""
+	} else {
+		ranges, more, e := byteRangeDifference(first, second, diffContentMaxRanges)
+		fatalIf(probe.NewError(e).Trace(firstURL, secondURL), "Unable to compare `"+firstURL+"` and `"+secondURL+"`.")
+		msg.Ranges, msg.MoreRanges = ranges, more
+		msg.Identical = len(ranges) == 0 && firstSize == secondSize
+	}
+
+	printMsg(msg)
+	return nil
+}
+
+// byteRangeDifference compares both readers byte by byte and returns up to
+// maxRanges ranges of differing bytes within their common length.
+func byteRangeDifference(first, second io.Reader, maxRanges int) (ranges []diffByteRange, more bool, e error) {
+	bufA := make([]byte, 32*humanize.KiByte)
+	bufB := make([]byte, 32*humanize.KiByte)
+	var offset int64
+	var current *diffByteRange
+	for {
+		n, ea := io.ReadFull(first, bufA)
+		m, eb := io.ReadFull(second, bufB[:n])
+		for i := 0; i < m; i++ {
+			if bufA[i] == bufB[i] {
+				current = nil
+				continue
+			}
+			if current == nil {
+				if len(ranges) == maxRanges {
+					return ranges, true, nil
+				}
+				ranges = append(ranges, diffByteRange{Offset: offset + int64(i)})
+				current = &ranges[len(ranges)-1]
+			}
+			current.Length++
+		}
+		offset += int64(m)
+		if ea == io.EOF || ea == io.ErrUnexpectedEOF || eb == io.EOF || eb == io.ErrUnexpectedEOF {
+			return ranges, false, nil
+		}
+		if ea != nil {
+			return nil, false, ea
+		}
+		if eb != nil {
+			return nil, false, eb
+		}
+	}
+}
+
+// lineEdit is a single step of a line based edit script.
+type lineEdit struct {
+	op   byte // ' ', '-' or '+'
+	text string
+}
+
+// diffLines computes the shortest edit script between a and b using
+// the Myers algorithm.
+func diffLines(a, b []string) []lineEdit {
+	// Trim the common prefix and suffix, which is usually most of the input.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]lineEdit, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		edits = append(edits, lineEdit{' ', line})
+	}
+	edits = append(edits, myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, lineEdit{' ', line})
+	}
+	return edits
+}
+
+func myersDiff(a, b []string) []lineEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	v := make([]int, 2*max+2)
+	// trace[d] holds the furthest reaching x of diagonals -d..d before step d.
+	var trace [][]int
+	found := false
+	for d := 0; d <= max && !found; d++ {
+		trace = append(trace, append([]int(nil), v[max-d:max+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[max+k-1] < v[max+k+1]) {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// Walk the trace backwards to recover the edit script.
+	var edits []lineEdit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		vd := trace[d]
+		at := func(k int) int { return vd[k+d] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, lineEdit{' ', a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			edits = append(edits, lineEdit{'+', b[y-1]})
+			y--
+		} else {
+			edits = append(edits, lineEdit{'-', a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, lineEdit{' ', a[x-1]})
+		x--
+		y--
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// unifiedDiff returns the differences between first and second content in
+// unified diff format, an empty string is returned if both are equal.
+func unifiedDiff(firstName, secondName, first, second string, context int) string {
+	splitLines := func(s string) []string {
+		lines := strings.SplitAfter(s, "\n")
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		return lines
+	}
+	edits := diffLines(splitLines(first), splitLines(second))
+
+	// Line numbers in both inputs at the start of every edit.
+	aLine := make([]int, len(edits)+1)
+	bLine := make([]int, len(edits)+1)
+	for i, edit := range edits {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if edit.op != '+' {
+			aLine[i+1]++
+		}
+		if edit.op != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk while changes are separated by at most 2*context lines.
+		end := i
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			run := 0
+			for end+run < len(edits) && edits[end+run].op == ' ' {
+				run++
+			}
+			if end+run == len(edits) || run > 2*context {
+				if run > context {
+					run = context
+				}
+				end += run
+				break
+			}
+			end += run
+		}
+
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", firstName, secondName)
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n",
+			hunkRange(aLine[start], aLine[end]-aLine[start]),
+			hunkRange(bLine[start], bLine[end]-bLine[start]))
+		for _, edit := range edits[start:end] {
+			b.WriteByte(edit.op)
+			b.WriteString(edit.text)
+			if !strings.HasSuffix(edit.text, "\n") {
+				b.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange formats a hunk range the way GNU diff does.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
@@ -32,7 +32,24 @@ import (
 
 // diff specific flags.
 var (
-	diffFlags = []cli.Flag{}
+	diffFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "content",
+			Usage: "compare the contents of two objects, showing a unified diff for text",
+		},
+		cli.BoolFlag{
+			Name:  "checksum",
+			Usage: "also compare the checksums of objects matching in size, to find objects differing in content",
+		},
+		cli.StringFlag{
+			Name:  "base",
+			Usage: "classify the changes of both folders since a base manifest, the output of 'mc ls --recursive --json'",
+		},
+		cli.StringFlag{
+			Name:  "output-plan",
+			Usage: "write the copies and removals making the target match the source to a plan file for 'mc apply'",
+		},
+	}
 )
 
 // Compute differences in object name, size, and date between two buckets.
@@ -48,17 +65,33 @@ var diffCmd = cli.Command{
 
 USAGE:
   {{.HelpName}} [FLAGS] SOURCE TARGET
+  {{.HelpName}} --content [FLAGS] SOURCE-OBJECT TARGET-OBJECT
+  {{.HelpName}} --base MANIFEST [FLAGS] FIRST SECOND
 
 FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 DESCRIPTION:
-  Diff only calculates differences in object name, size and time. It *DOES NOT* compare objects' contents.
+  Diff only calculates differences in object name, size and time. It *DOES NOT* compare objects' contents
+  unless --checksum is given, objects of the same size are then compared by their checksums. Checksums
+  stored by the server (SHA256 or CRC32C) are used when available, others are computed by reading the objects.
+
+  With --content, two objects are streamed and their contents compared instead. Text objects are
+  shown as a unified diff, binary objects (or objects larger than 4MiB) as a list of differing byte ranges.
+
+  With --base, both folders are compared to a base snapshot, the output of 'mc ls --recursive --json' of
+  a folder they were both copied from. The changes of each side are shown as A (added), M (modified),
+  D (deleted) or - (unchanged), keys changed differently on both sides are marked C as conflicts.
+  The --json output is the plan of a merge.
+
+  With --output-plan, the copies and removals making TARGET a copy of SOURCE are also written to a plan
+  file, which can be reviewed and then applied with 'mc apply'.
 
 LEGEND:
   < - object is only in source.
   > - object is only in destination.
   ! - newer object is in source.
+  # - object differs in content (--checksum).
 
 EXAMPLES:
   1. Compare a local folder with a folder on Amazon S3 cloud storage.
@@ -66,6 +99,19 @@ EXAMPLES:
 
   2. Compare two folders on a local filesystem.
      {{.Prompt}} {{.HelpName}} ~/Photos /Media/Backup/Photos
+
+  3. Compare the contents of a configuration file stored in two environments.
+     {{.Prompt}} {{.HelpName}} --content staging/config/app.yaml prod/config/app.yaml
+
+  4. Find the objects of a bucket whose contents differ from their replica, even with the same size and time.
+     {{.Prompt}} {{.HelpName}} --checksum s3/mybucket replica/mybucket
+
+  5. Find the conflicting changes of two copies of a bucket since a snapshot of it.
+     {{.Prompt}} {{.HelpName}} --base snapshot.json site1/mybucket site2/mybucket
+
+  6. Write the changes making a backup match its bucket to a plan, to review it before applying it.
+     {{.Prompt}} {{.HelpName}} --output-plan plan.json s3/mybucket backup/mybucket
+     {{.Prompt}} mc apply plan.json
 `,
 }
 
@@ -96,6 +142,8 @@ func (d diffMessage) String() string {
 		msg = console.Colorize("DiffMetadata", "! "+d.SecondURL)
 	case differInAASourceMTime:
 		msg = console.Colorize("DiffMMSourceMTime", "! "+d.SecondURL)
+	case differInChecksum:
+		msg = console.Colorize("DiffChecksum", "# "+d.SecondURL)
 	case differInNone:
 		msg = console.Colorize("DiffInNone", "= "+d.FirstURL)
 	default:
@@ -114,7 +162,7 @@ func (d diffMessage) JSON() string {
 	return string(diffJSONBytes)
 }
 
-func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) {
 	if len(cliCtx.Args()) != 2 {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
 	}
@@ -155,8 +203,10 @@ func checkDiffSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 	}
 }
 
-// doDiffMain runs the diff.
-func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
+// doDiffMain runs the diff, objects of the same size are compared by
+// their checksums if requested. The changes making the second folder a
+// copy of the first are written to planFile if not empty.
+func doDiffMain(ctx context.Context, firstURL, secondURL string, checksum bool, planFile string, encKeyDB encryptionConfig) error {
 	// Source and targets are always directories
 	sourceSeparator := string(newClientURL(firstURL).Separator)
 	if !strings.HasSuffix(firstURL, sourceSeparator) {
@@ -167,6 +217,8 @@ func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
 		secondURL = secondURL + targetSeparator
 	}
 
+	plan := changePlan{Source: firstURL, Target: secondURL}
+
 	// Expand aliased urls.
 	firstAlias, firstURL, _ := mustExpandAlias(firstURL)
 	secondAlias, secondURL, _ := mustExpandAlias(secondURL)
@@ -184,15 +236,32 @@ func doDiffMain(ctx context.Context, firstURL, secondURL string) error {
 	}
 
 	// Diff first and second urls.
-	for diffMsg := range objectDifference(ctx, firstClient, secondClient, true) {
+	var diffCh chan diffMessage
+	if checksum {
+		diffCh = checksumDifference(ctx, firstAlias, secondAlias,
+			difference(firstClient.GetURL().String(), firstClient.List(ctx, ListOptions{Recursive: true, WithMetadata: true, ShowDir: DirNone}),
+				secondClient.GetURL().String(), secondClient.List(ctx, ListOptions{Recursive: true, WithMetadata: true, ShowDir: DirNone}),
+				true, true),
+			encKeyDB)
+	} else {
+		diffCh = objectDifference(ctx, firstClient, secondClient, true)
+	}
+	for diffMsg := range diffCh {
 		if diffMsg.Error != nil {
 			errorIf(diffMsg.Error, "Unable to calculate objects difference.")
 			// Ignore error and proceed to next object.
 			continue
 		}
 		printMsg(diffMsg)
+		if action, ok := diffPlanAction(diffMsg, firstClient.GetURL().String(), plan.Source,
+			secondClient.GetURL().String(), plan.Target); ok && planFile != "" {
+			plan.Actions = append(plan.Actions, action)
+		}
 	}
 
+	if planFile != "" {
+		fatalIf(writeChangePlan(planFile, plan).Trace(planFile), "Unable to write the change plan.")
+	}
 	return nil
 }
 
@@ -205,9 +274,6 @@ func mainDiff(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
-	// check 'diff' cli arguments.
-	checkDiffSyntax(ctx, cliCtx, encKeyDB)
-
 	// Additional command specific theme customization.
 	console.SetColor("DiffMessage", color.New(color.FgGreen, color.Bold))
 	console.SetColor("DiffOnlyInFirst", color.New(color.FgRed))
@@ -216,10 +282,37 @@ func mainDiff(cliCtx *cli.Context) error {
 	console.SetColor("DiffSize", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffMetadata", color.New(color.FgYellow, color.Bold))
 	console.SetColor("DiffMMSourceMTime", color.New(color.FgYellow, color.Bold))
+	console.SetColor("DiffChecksum", color.New(color.FgRed, color.Bold))
+	console.SetColor("DiffHunk", color.New(color.FgCyan))
+
+	console.SetColor("DiffChange", color.New(color.FgYellow))
+	console.SetColor("DiffConflict", color.New(color.FgRed, color.Bold))
+
+	if msg := checkDiffChecksumSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+	if msg := checkDiffBaseSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+	if msg := checkDiffPlanSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+
+	if cliCtx.Bool("content") {
+		firstContent, secondContent := checkDiffContentSyntax(ctx, cliCtx, encKeyDB)
+		return doDiffContent(ctx, cliCtx.Args().Get(0), cliCtx.Args().Get(1),
+			firstContent.Size, secondContent.Size, encKeyDB)
+	}
+
+	// check 'diff' cli arguments.
+	checkDiffSyntax(ctx, cliCtx, encKeyDB)
 
 	URLs := cliCtx.Args()
 	firstURL := URLs.Get(0)
 	secondURL := URLs.Get(1)
 
-	return doDiffMain(ctx, firstURL, secondURL)
+	if cliCtx.IsSet("base") {
+		return doDiffBase(ctx, cliCtx.String("base"), firstURL, secondURL)
+	}
+	return doDiffMain(ctx, firstURL, secondURL, cliCtx.Bool("checksum"), cliCtx.String("output-plan"), encKeyDB)
 }
@@ -42,6 +42,7 @@ const (
 	differInFirst                    // only in source (FIRST)
 	differInSecond                   // only in target (SECOND)
 	differInAASourceMTime            // differs in active-active source modtime
+	differInChecksum                 // differs in content, same size
 )
 
 func (d differType) String() string {
@@ -54,6 +55,8 @@ func (d differType) String() string {
 		return "metadata"
 	case differInAASourceMTime:
 		return "mm-source-mtime"
+	case differInChecksum:
+		return "checksum"
 	case differInType:
 		return "type"
 	case differInFirst:
@@ -302,7 +305,7 @@ func differenceInternal(sourceURL string, srcCh <-chan *ClientContent, targetURL
 		}
 		if normalizedExpected == normalizedCurrent {
 			srcType, tgtType := srcCtnt.Type, tgtCtnt.Type
-			srcSize, tgtSize := srcCtnt.Size, tgtCtnt.Size
+			srcSize, tgtSize := srcCtnt.Size, uncompressedSize(tgtCtnt)
 			if srcType.IsRegular() && !tgtType.IsRegular() ||
 				!srcType.IsRegular() && tgtType.IsRegular() {
 				// Type differs. Source is never a directory.
@@ -18,7 +18,13 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 var testCases = []struct {
@@ -47,3 +53,128 @@ func TestExcludeOptions(t *testing.T) {
 		}
 	}
 }
+
+func TestUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		first, second string
+		expected      string
+	}{
+		{"a\nb\nc\n", "a\nb\nc\n", ""},
+		{"a\nb\nc\n", "a\nx\nc\n", "--- first\n+++ second\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n"},
+		{"", "a\n", "--- first\n+++ second\n@@ -0,0 +1 @@\n+a\n"},
+		{"a\n", "a", "--- first\n+++ second\n@@ -1 +1 @@\n-a\n+a\n\\ No newline at end of file\n"},
+		{
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "1\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			"--- first\n+++ second\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+	}
+	for i, testCase := range testCases {
+		got := unifiedDiff("first", "second", testCase.first, testCase.second, 3)
+		if got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+}
+
+func TestByteRangeDifference(t *testing.T) {
+	ranges, more, e := byteRangeDifference(strings.NewReader("abcdefgh"), strings.NewReader("aXXdefgY"), 16)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expected := []diffByteRange{{Offset: 1, Length: 2}, {Offset: 7, Length: 1}}
+	if more || !reflect.DeepEqual(ranges, expected) {
+		t.Fatalf("expected %v, got %v (more: %t)", expected, ranges, more)
+	}
+}
+
+func TestReconcileTwoWay(t *testing.T) {
+	now := time.Now().UTC()
+	old := &ClientContent{Size: 10, ETag: "old", Time: now.Add(-time.Hour)}
+	newer := &ClientContent{Size: 12, ETag: "new", Time: now}
+	synced := &twoWayEntry{Source: newTwoWaySignature(old), Target: newTwoWaySignature(old)}
+
+	testCases := []struct {
+		src, tgt *ClientContent
+		last     *twoWayEntry
+		policy   string
+		isRemove bool
+		action   twoWayAction
+	}{
+		// Unchanged on both sides.
+		{old, old, synced, mirrorConflictNewer, false, twoWayInSync},
+		// Changed on one side.
+		{newer, old, synced, mirrorConflictNewer, false, twoWayToTarget},
+		{old, newer, synced, mirrorConflictNewer, false, twoWayToSource},
+		// New on one side.
+		{newer, nil, nil, mirrorConflictNewer, true, twoWayToTarget},
+		{nil, newer, nil, mirrorConflictNewer, true, twoWayToSource},
+		// Removed from one side, propagated only with --remove.
+		{old, nil, synced, mirrorConflictNewer, true, twoWayRemoveSource},
+		{nil, old, synced, mirrorConflictNewer, true, twoWayRemoveTarget},
+		{old, nil, synced, mirrorConflictNewer, false, twoWayToTarget},
+		// Removed from one side but changed on the other.
+		{newer, nil, synced, mirrorConflictNewer, true, twoWayToTarget},
+		// Same object on both sides, never synchronized.
+		{old, old, nil, mirrorConflictSkip, false, twoWayInSync},
+		// Conflicts.
+		{newer, old, nil, mirrorConflictNewer, false, twoWayToTarget},
+		{old, newer, nil, mirrorConflictNewer, false, twoWayToSource},
+		{newer, old, nil, mirrorConflictTarget, false, twoWayToSource},
+		{old, newer, nil, mirrorConflictSource, false, twoWayToTarget},
+		{newer, old, nil, mirrorConflictSkip, false, twoWayConflict},
+		{&ClientContent{Size: 1, ETag: "a", Time: now}, &ClientContent{Size: 2, ETag: "b", Time: now}, nil, mirrorConflictNewer, false, twoWayConflict},
+	}
+
+	for i, testCase := range testCases {
+		action := reconcileTwoWay(testCase.src, testCase.tgt, testCase.last, testCase.policy, testCase.isRemove)
+		if action != testCase.action {
+			t.Errorf("Test %d: expected action %d, got %d", i+1, testCase.action, action)
+		}
+	}
+}
+
+func TestMirrorStateDB(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	file := filepath.Join(t.TempDir(), "state.db")
+	root := "/backup/"
+	s, err := newMirrorStateDB(file, "src", "dst", "flags")
+	if err != nil || s.valid {
+		t.Fatalf("unexpected state %v, %v", s, err)
+	}
+	s.targetRoot = root
+
+	// The target as listed the first time.
+	targetCh := make(chan *ClientContent, 3)
+	for _, name := range []string{"a/b", "a-c", "d"} {
+		targetCh <- &ClientContent{URL: *newClientURL(root + name), Size: int64(len(name)), Type: os.FileMode(0o644)}
+	}
+	close(targetCh)
+	for range s.record(ctx, targetCh) {
+	}
+	s.done(ctx, URLs{TargetContent: &ClientContent{URL: *newClientURL(root + "d")}})
+	if err = s.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = newMirrorStateDB(file, "src", "dst", "flags")
+	if err != nil || !s.valid {
+		t.Fatalf("expected a valid state, got %v, %v", s, err)
+	}
+	s.targetRoot = root
+	var replayed []string
+	for content := range s.replay(ctx) {
+		replayed = append(replayed, strings.TrimPrefix(content.URL.Path, root))
+		if content.Size != int64(len(strings.TrimPrefix(content.URL.Path, root))) || !content.Type.IsRegular() {
+			t.Fatalf("unexpected content %v", content)
+		}
+	}
+	if !reflect.DeepEqual(replayed, []string{"a-c", "a/b"}) {
+		t.Fatalf("expected the remaining objects in lexical order, got %v", replayed)
+	}
+
+	if s, _ = newMirrorStateDB(file, "src", "dst", "other flags"); s.valid {
+		t.Fatal("expected the state to be invalidated by other flags")
+	}
+}
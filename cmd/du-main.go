@@ -44,6 +44,10 @@ var (
 			Name:  "recursive, r",
 			Usage: "recursively print the total for a folder prefix",
 		},
+		cli.IntFlag{
+			Name:  "max-depth",
+			Usage: "count only the objects N or fewer levels below the argument, deeper prefixes are not listed at all",
+		},
 		cli.StringFlag{
 			Name:  "rewind",
 			Usage: "include all object versions no later than specified date",
@@ -52,6 +56,10 @@ var (
 			Name:  "versions",
 			Usage: "include all object versions",
 		},
+		cli.BoolFlag{
+			Name:  "exclude-deleted",
+			Usage: "leave out all versions of objects whose latest version is a delete marker",
+		},
 	}
 )
 
@@ -62,7 +70,7 @@ var duCmd = cli.Command{
 	Action:       mainDu,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(duFlags, ioFlags...), globalFlags...),
+	Flags:        joinFlags(duFlags, duReportFlags, ioFlags, keyOutputFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -87,6 +95,18 @@ EXAMPLES:
 
   4. Summarize disk usage of 'jazz-songs' bucket with all objects versions
      {{.Prompt}} {{.HelpName}} --versions s3/jazz-songs/
+
+  5. Summarize disk usage of all versions of the objects in 'jazz-songs' bucket which have not been deleted
+     {{.Prompt}} {{.HelpName}} --versions --exclude-deleted s3/jazz-songs/
+
+  6. Summarize disk usage of the objects at most one level below each artist of 'jazz-songs', without listing deeper.
+     {{.Prompt}} {{.HelpName}} --depth=2 --max-depth=2 s3/jazz-songs/
+
+  7. Report the 20 largest objects and albums of 'jazz-songs' and the distribution of the object sizes.
+     {{.Prompt}} {{.HelpName}} --top 20 --histogram s3/jazz-songs/
+
+  8. Print the size of every prefix of 'jazz-songs' in human readable form, followed by its name.
+     {{.Prompt}} {{.HelpName}} --depth 2 --format-template '{{"{{bytes .Size}} {{.Prefix}}"}}' s3/jazz-songs/
 `,
 }
 
@@ -111,7 +131,7 @@ func (r duMessage) String() string {
 	}
 	return fmt.Sprintf("%s\t%s\t%s", console.Colorize("Size", humanSize),
 		console.Colorize("Objects", cnt),
-		console.Colorize("Prefix", r.Prefix))
+		console.Colorize("Prefix", quoteOutput(r.Prefix)))
 }
 
 // JSON'ified message for scripting.
@@ -121,7 +141,9 @@ func (r duMessage) JSON() string {
 	return string(msgBytes)
 }
 
-func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool, depth int, encKeyDB map[string][]prefixSSEPair) (sz, objs int64, err error) {
+// du prints the usage of a folder, the objects are also counted in the
+// report, if any, whose root is set by the first call.
+func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions, excludeDeleted bool, depth, maxDepth int, report *duReport, encKeyDB encryptionConfig) (sz, objs int64, err error) {
 	targetAlias, targetURL, _ := mustExpandAlias(urlStr)
 
 	if !strings.HasSuffix(targetURL, "/") {
@@ -139,13 +161,24 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 	recursive := depth == 1
 
 	targetAbsolutePath := path.Clean(clnt.GetURL().String())
+	if report != nil && report.root == "" {
+		report.root = clnt.GetURL().Path
+	}
 
-	contentCh := clnt.List(ctx, ListOptions{
+	listOpts := ListOptions{
 		TimeRef:           timeRef,
 		WithOlderVersions: withVersions,
+		ExcludeDeleted:    excludeDeleted,
 		Recursive:         recursive,
 		ShowDir:           DirFirst,
-	})
+	}
+	var contentCh <-chan *ClientContent
+	if recursive && maxDepth > 0 {
+		// Folders at the last level are listed, not counted.
+		contentCh = listMaxDepth(ctx, targetAlias, clnt, listOpts, maxDepth)
+	} else {
+		contentCh = clnt.List(ctx, listOpts)
+	}
 	size := int64(0)
 	objects := int64(0)
 	for content := range contentCh {
@@ -167,6 +200,14 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 		}
 
 		if content.Type.IsDir() && !recursive {
+			if maxDepth == 1 {
+				// No objects are counted below this level.
+				continue
+			}
+			maxDepth := maxDepth
+			if maxDepth > 0 {
+				maxDepth--
+			}
 			depth := depth
 			if depth > 0 {
 				depth--
@@ -176,7 +217,7 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 			if targetAlias != "" {
 				subDirAlias = targetAlias + "/" + content.URL.Path
 			}
-			used, n, err := du(ctx, subDirAlias, timeRef, withVersions, depth, encKeyDB)
+			used, n, err := du(ctx, subDirAlias, timeRef, withVersions, excludeDeleted, depth, maxDepth, report, encKeyDB)
 			if err != nil {
 				return 0, 0, err
 			}
@@ -186,6 +227,9 @@ func du(ctx context.Context, urlStr string, timeRef time.Time, withVersions bool
 			if !content.IsDeleteMarker && !content.Type.IsDir() {
 				size += content.Size
 				objects++
+				if report != nil {
+					report.add(content.URL.Path, content.Size)
+				}
 			}
 		}
 	}
@@ -219,6 +263,7 @@ func mainDu(cliCtx *cli.Context) error {
 	console.SetColor("Prefix", color.New(color.FgCyan, color.Bold))
 	console.SetColor("Objects", color.New(color.FgGreen))
 	console.SetColor("Size", color.New(color.FgYellow))
+	console.SetColor("Top", color.New(color.FgMagenta))
 
 	ctx, cancelRm := context.WithCancel(globalContext)
 	defer cancelRm()
@@ -239,7 +284,16 @@ func mainDu(cliCtx *cli.Context) error {
 		}
 	}
 
+	if msg := checkMaxDepthSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+	maxDepth := cliCtx.Int("max-depth")
+	if msg := checkDuReportSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+
 	withVersions := cliCtx.Bool("versions")
+	excludeDeleted := cliCtx.Bool("exclude-deleted")
 	timeRef := parseRewindFlag(cliCtx.String("rewind"))
 
 	var duErr error
@@ -248,9 +302,14 @@ func mainDu(cliCtx *cli.Context) error {
 			fatalIf(errInvalidArgument().Trace(urlStr), fmt.Sprintf("Source `%s` is not a folder. Only folders are supported by 'du' command.", urlStr))
 		}
 
-		if _, _, err := du(ctx, urlStr, timeRef, withVersions, depth, encKeyDB); duErr == nil {
+		report := newDuReport(cliCtx.Int("top"), cliCtx.Bool("histogram"))
+		_, _, err := du(ctx, urlStr, timeRef, withVersions, excludeDeleted, depth, maxDepth, report, encKeyDB)
+		if duErr == nil {
 			duErr = err
 		}
+		if err == nil && report != nil {
+			report.print()
+		}
 	}
 
 	return duErr
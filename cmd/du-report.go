@@ -0,0 +1,246 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var duReportFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "top",
+		Usage: "report the N largest objects and top level prefixes",
+	},
+	cli.BoolFlag{
+		Name:  "histogram",
+		Usage: "report the number and size of the objects by size range",
+	},
+}
+
+// checkDuReportSyntax returns why --top cannot be used, if so.
+func checkDuReportSyntax(cliCtx *cli.Context) string {
+	if cliCtx.Int("top") < 0 {
+		return "--top must not be negative."
+	}
+	return ""
+}
+
+// duHistogramBounds are the upper bounds of the size ranges of the
+// histogram, the last range has none.
+var duHistogramBounds = []int64{
+	humanize.KiByte,
+	64 * humanize.KiByte,
+	humanize.MiByte,
+	16 * humanize.MiByte,
+	128 * humanize.MiByte,
+	humanize.GiByte,
+	16 * humanize.GiByte,
+	math.MaxInt64,
+}
+
+// duReport collects the largest objects, the size of the top level
+// prefixes and the histogram of the object sizes below an argument of
+// du, in the same pass as the usage.
+type duReport struct {
+	top       int
+	histogram bool
+	// Path of the argument, the keys are relative to it.
+	root     string
+	objects  *messageHeap
+	prefixes map[string]int64
+	counts   []int64
+	sizes    []int64
+}
+
+// newDuReport returns a report, nil if none is requested.
+func newDuReport(top int, histogram bool) *duReport {
+	if top <= 0 && !histogram {
+		return nil
+	}
+	return &duReport{
+		top:       top,
+		histogram: histogram,
+		objects:   &messageHeap{less: func(a, b contentMessage) bool { return a.Size < b.Size }},
+		prefixes:  make(map[string]int64),
+		counts:    make([]int64, len(duHistogramBounds)),
+		sizes:     make([]int64, len(duHistogramBounds)),
+	}
+}
+
+// add counts an object, its path is below the root.
+func (r *duReport) add(objectPath string, size int64) {
+	key := strings.TrimPrefix(strings.TrimPrefix(objectPath, r.root), "/")
+	if r.top > 0 {
+		heap.Push(r.objects, contentMessage{Key: key, Size: size})
+		if r.objects.Len() > r.top {
+			heap.Pop(r.objects)
+		}
+		r.prefixes[topPrefix(key)] += size
+	}
+	if r.histogram {
+		i := sort.Search(len(duHistogramBounds), func(i int) bool { return size < duHistogramBounds[i] })
+		if i == len(duHistogramBounds) {
+			i--
+		}
+		r.counts[i]++
+		r.sizes[i] += size
+	}
+}
+
+// topMessages returns the largest objects and prefixes, largest first.
+// Objects directly below the root are not a prefix.
+func (r *duReport) topMessages() (msgs []duTopMessage) {
+	objects := append([]contentMessage(nil), r.objects.msgs...)
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Size != objects[j].Size {
+			return objects[i].Size > objects[j].Size
+		}
+		return objects[i].Key < objects[j].Key
+	})
+	for i, object := range objects {
+		msgs = append(msgs, duTopMessage{Type: "object", Rank: i + 1, Key: object.Key, Size: object.Size})
+	}
+
+	prefixes := make([]string, 0, len(r.prefixes))
+	for prefix := range r.prefixes {
+		if prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		if r.prefixes[prefixes[i]] != r.prefixes[prefixes[j]] {
+			return r.prefixes[prefixes[i]] > r.prefixes[prefixes[j]]
+		}
+		return prefixes[i] < prefixes[j]
+	})
+	if len(prefixes) > r.top {
+		prefixes = prefixes[:r.top]
+	}
+	for i, prefix := range prefixes {
+		msgs = append(msgs, duTopMessage{Type: "prefix", Rank: i + 1, Key: prefix, Size: r.prefixes[prefix]})
+	}
+	return msgs
+}
+
+// histogramMessage returns the histogram, empty ranges included.
+func (r *duReport) histogramMessage() duHistogramMessage {
+	var msg duHistogramMessage
+	var lower int64
+	for i, upper := range duHistogramBounds {
+		bucket := duHistogramBucket{Min: lower, Objects: r.counts[i], Size: r.sizes[i]}
+		if upper != math.MaxInt64 {
+			bucket.Max = upper
+		}
+		msg.Buckets = append(msg.Buckets, bucket)
+		lower = upper
+	}
+	return msg
+}
+
+// print prints the report.
+func (r *duReport) print() {
+	if r.top > 0 {
+		for _, msg := range r.topMessages() {
+			printMsg(msg)
+		}
+	}
+	if r.histogram {
+		printMsg(r.histogramMessage())
+	}
+}
+
+// duTopMessage is one of the largest objects or prefixes.
+type duTopMessage struct {
+	Status string `json:"status"`
+	Type   string `json:"type"`
+	Rank   int    `json:"rank"`
+	Key    string `json:"key"`
+	Size   int64  `json:"size"`
+}
+
+// String colorized top message.
+func (t duTopMessage) String() string {
+	humanSize := strings.Join(strings.Fields(humanize.IBytes(uint64(t.Size))), "")
+	return fmt.Sprintf("%s %3d %s\t%s", console.Colorize("Top", fmt.Sprintf("%-6s", t.Type)), t.Rank,
+		console.Colorize("Size", fmt.Sprintf("%7s", humanSize)), console.Colorize("Prefix", quoteOutput(t.Key)))
+}
+
+// JSON jsonified top message.
+func (t duTopMessage) JSON() string {
+	t.Status = "success"
+	msgBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// duHistogramBucket is a size range of the histogram, Max is 0 for the
+// last range.
+type duHistogramBucket struct {
+	Min     int64 `json:"min"`
+	Max     int64 `json:"max,omitempty"`
+	Objects int64 `json:"objects"`
+	Size    int64 `json:"size"`
+}
+
+// duHistogramMessage is the histogram of the object sizes.
+type duHistogramMessage struct {
+	Status  string              `json:"status"`
+	Buckets []duHistogramBucket `json:"histogram"`
+}
+
+// String colorized histogram message, a bar per size range.
+func (h duHistogramMessage) String() string {
+	var most int64
+	for _, bucket := range h.Buckets {
+		if bucket.Objects > most {
+			most = bucket.Objects
+		}
+	}
+	lines := make([]string, 0, len(h.Buckets))
+	for _, bucket := range h.Buckets {
+		bounds := ">= " + strings.ReplaceAll(humanize.IBytes(uint64(bucket.Min)), " ", "")
+		if bucket.Max > 0 {
+			bounds = "< " + strings.ReplaceAll(humanize.IBytes(uint64(bucket.Max)), " ", "")
+		}
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("#", int(bucket.Objects*40/most))
+		}
+		lines = append(lines, fmt.Sprintf("%10s %10d %8s %s", bounds, bucket.Objects,
+			strings.ReplaceAll(humanize.IBytes(uint64(bucket.Size)), " ", ""), console.Colorize("Top", bar)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JSON jsonified histogram message.
+func (h duHistogramMessage) JSON() string {
+	h.Status = "success"
+	msgBytes, e := json.MarshalIndent(h, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+)
+
+func TestDuReport(t *testing.T) {
+	if newDuReport(0, false) != nil {
+		t.Fatal("expected no report")
+	}
+
+	r := newDuReport(2, true)
+	r.root = "/bucket/"
+	for _, object := range []struct {
+		path string
+		size int64
+	}{
+		{"/bucket/a", 10},
+		{"/bucket/logs/1", 2 * humanize.MiByte},
+		{"/bucket/logs/2", 3 * humanize.MiByte},
+		{"/bucket/media/video", 20 * humanize.GiByte},
+		{"/bucket/media/photo/1", 100 * humanize.KiByte},
+		{"/bucket/tmp/x", 0},
+	} {
+		r.add(object.path, object.size)
+	}
+
+	expected := []duTopMessage{
+		{Type: "object", Rank: 1, Key: "media/video", Size: 20 * humanize.GiByte},
+		{Type: "object", Rank: 2, Key: "logs/2", Size: 3 * humanize.MiByte},
+		{Type: "prefix", Rank: 1, Key: "media/", Size: 20*humanize.GiByte + 100*humanize.KiByte},
+		{Type: "prefix", Rank: 2, Key: "logs/", Size: 5 * humanize.MiByte},
+	}
+	if got := r.topMessages(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	histogram := r.histogramMessage()
+	if len(histogram.Buckets) != len(duHistogramBounds) {
+		t.Fatalf("expected %d ranges, got %d", len(duHistogramBounds), len(histogram.Buckets))
+	}
+	counts := make([]int64, 0, len(histogram.Buckets))
+	for _, bucket := range histogram.Buckets {
+		counts = append(counts, bucket.Objects)
+	}
+	// < 1KiB, < 64KiB, < 1MiB, < 16MiB, < 128MiB, < 1GiB, < 16GiB, >= 16GiB
+	if expectedCounts := []int64{2, 0, 1, 2, 0, 0, 0, 1}; !reflect.DeepEqual(counts, expectedCounts) {
+		t.Errorf("expected %v, got %v", expectedCounts, counts)
+	}
+	last := histogram.Buckets[len(histogram.Buckets)-1]
+	if last.Min != 16*humanize.GiByte || last.Max != 0 || last.Size != 20*humanize.GiByte {
+		t.Errorf("unexpected last range %+v", last)
+	}
+	if histogram.Buckets[0].Min != 0 || histogram.Buckets[0].Max != humanize.KiByte || histogram.Buckets[0].Size != 10 {
+		t.Errorf("unexpected first range %+v", histogram.Buckets[0])
+	}
+}
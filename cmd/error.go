@@ -54,6 +54,8 @@ func fatalIf(err *probe.Error, msg string, data ...interface{}) {
 }
 
 func fatal(err *probe.Error, msg string, data ...interface{}) {
+	runExitHooks()
+
 	if globalJSON {
 		errorMsg := errorMessage{
 			Message: msg,
@@ -0,0 +1,109 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+var eventExportCmd = cli.Command{
+	Name:         "export",
+	Usage:        "export bucket notifications in JSON format",
+	Action:       mainEventExport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} TARGET
+
+DESCRIPTION:
+  Exports the notification configuration of a bucket in JSON format to STDOUT,
+  in the same format as the notification configuration saved by 'mc bucket export'.
+
+EXAMPLES:
+  1. Export the notification configuration of 'mybucket' to 'events.json'.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket > events.json
+
+  2. Print the notification configuration of 'mybucket' to STDOUT.
+     {{.Prompt}} {{.HelpName}} play/mybucket
+`,
+}
+
+type eventExportMessage struct {
+	Status string                      `json:"status"`
+	Target string                      `json:"target"`
+	Config *notification.Configuration `json:"config"`
+}
+
+func (e eventExportMessage) String() string {
+	msgBytes, err := json.MarshalIndent(e.Config, "", " ")
+	fatalIf(probe.NewError(err), "Unable to export notification configuration.")
+	return string(msgBytes)
+}
+
+func (e eventExportMessage) JSON() string {
+	msgBytes, err := json.MarshalIndent(e, "", " ")
+	fatalIf(probe.NewError(err), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// checkEventExportSyntax - validate arguments passed by user
+func checkEventExportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+}
+
+func mainEventExport(cliCtx *cli.Context) error {
+	ctx, cancelEventExport := context.WithCancel(globalContext)
+	defer cancelEventExport()
+
+	checkEventExportSyntax(cliCtx)
+
+	urlStr := cliCtx.Args().Get(0)
+	client, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize client for `"+urlStr+"`.")
+
+	s3Client, ok := client.(*S3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
+	}
+
+	config, err := s3Client.GetNotificationConfig(ctx)
+	fatalIf(err.Trace(urlStr), "Unable to get notification configuration.")
+	if len(config.TopicConfigs)+len(config.QueueConfigs)+len(config.LambdaConfigs) == 0 {
+		fatalIf(probe.NewError(errors.New("notification configuration not set")).Trace(urlStr),
+			"Unable to export notification configuration.")
+	}
+
+	printMsg(eventExportMessage{
+		Status: "success",
+		Target: urlStr,
+		Config: &config,
+	})
+	return nil
+}
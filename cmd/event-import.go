@@ -0,0 +1,237 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/notification"
+	"github.com/minio/pkg/console"
+)
+
+// eventArnMapFlag is shared by the commands importing notification
+// configurations.
+var eventArnMapFlag = cli.StringSliceFlag{
+	Name:  "arn-map",
+	Usage: "replace the ARNs of notification targets, 'OLD=NEW' where '*' in OLD matches any part and keeps it in NEW",
+}
+
+var eventImportCmd = cli.Command{
+	Name:         "import",
+	Usage:        "import bucket notifications in JSON format",
+	Action:       mainEventImport,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append([]cli.Flag{eventArnMapFlag}, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Replaces the notification configuration of a bucket with the one read from STDIN,
+  as exported by 'mc event export'. Targets often have other ARNs on another cluster,
+  '--arn-map' rewrites the ARNs which match 'OLD' to 'NEW'. A '*' in a part of 'OLD'
+  matches any value, the same part given as '*' in 'NEW' keeps the original value.
+
+EXAMPLES:
+  1. Import the notification configuration of 'mybucket' from 'events.json'.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket < events.json
+
+  2. Copy the notification configuration of 'mybucket' to a DR cluster, where the webhook target has another ID.
+     {{.Prompt}} mc event export primary/mybucket | {{.HelpName}} --arn-map 'arn:minio:sqs::primary:webhook=arn:minio:sqs::dr:webhook' dr/mybucket
+
+  3. Copy the notification configuration of 'mybucket' to a DR cluster in another region, keeping the targets.
+     {{.Prompt}} mc event export primary/mybucket | {{.HelpName}} --arn-map 'arn:*:*:us-east-1:*:*=arn:*:*:us-west-2:*:*' dr/mybucket
+`,
+}
+
+// eventImportMessage container
+type eventImportMessage struct {
+	Status   string            `json:"status"`
+	Target   string            `json:"target"`
+	Remapped map[string]string `json:"remapped,omitempty"`
+}
+
+func (e eventImportMessage) String() string {
+	msg := console.Colorize("EventImport", "Notification configuration imported successfully to `"+e.Target+"`.")
+	from := make([]string, 0, len(e.Remapped))
+	for arn := range e.Remapped {
+		from = append(from, arn)
+	}
+	sort.Strings(from)
+	for _, arn := range from {
+		msg += "\n" + console.Colorize("EventImportArn", fmt.Sprintf("  %s -> %s", arn, e.Remapped[arn]))
+	}
+	return msg
+}
+
+func (e eventImportMessage) JSON() string {
+	msgBytes, err := json.MarshalIndent(e, "", " ")
+	fatalIf(probe.NewError(err), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// eventArnRule rewrites the ARNs matching from to to.
+type eventArnRule struct {
+	from, to []string
+}
+
+// parseEventArnMap parses the values of --arn-map.
+func parseEventArnMap(values []string) ([]eventArnRule, *probe.Error) {
+	var rules []eventArnRule
+	for _, value := range values {
+		from, to, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, probe.NewError(fmt.Errorf("invalid ARN mapping `%s`, expected 'OLD=NEW'", value))
+		}
+		rule := eventArnRule{from: strings.Split(from, ":"), to: strings.Split(to, ":")}
+		for _, arn := range [][]string{rule.from, rule.to} {
+			if len(arn) != 6 || arn[0] != "arn" {
+				return nil, probe.NewError(fmt.Errorf("invalid ARN mapping `%s`, expected 'arn:partition:service:region:account-id:resource'", value))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// remapEventArn returns the ARN rewritten by the first matching rule.
+func remapEventArn(arn string, rules []eventArnRule) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) != 6 {
+		return arn
+	}
+	for _, rule := range rules {
+		matched := true
+		for i := range parts {
+			if rule.from[i] != "*" && rule.from[i] != parts[i] {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		remapped := make([]string, len(parts))
+		for i := range parts {
+			remapped[i] = rule.to[i]
+			if remapped[i] == "*" {
+				remapped[i] = parts[i]
+			}
+		}
+		return strings.Join(remapped, ":")
+	}
+	return arn
+}
+
+// remapNotificationConfig rewrites the ARNs of all targets of a
+// notification configuration, it returns the rewritten ARNs.
+func remapNotificationConfig(config *notification.Configuration, rules []eventArnRule) (map[string]string, *probe.Error) {
+	remapped := make(map[string]string)
+	remap := func(c *notification.Config, arn *string) *probe.Error {
+		newArn := remapEventArn(*arn, rules)
+		parsed, e := notification.NewArnFromString(newArn)
+		if e != nil {
+			return probe.NewError(e).Trace(newArn)
+		}
+		if newArn != *arn {
+			remapped[*arn] = newArn
+		}
+		*arn = newArn
+		c.Arn = parsed
+		return nil
+	}
+	for i := range config.TopicConfigs {
+		if err := remap(&config.TopicConfigs[i].Config, &config.TopicConfigs[i].Topic); err != nil {
+			return nil, err
+		}
+	}
+	for i := range config.QueueConfigs {
+		if err := remap(&config.QueueConfigs[i].Config, &config.QueueConfigs[i].Queue); err != nil {
+			return nil, err
+		}
+	}
+	for i := range config.LambdaConfigs {
+		if err := remap(&config.LambdaConfigs[i].Config, &config.LambdaConfigs[i].Lambda); err != nil {
+			return nil, err
+		}
+	}
+	return remapped, nil
+}
+
+// checkEventImportSyntax - validate arguments passed by user
+func checkEventImportSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		showCommandHelpAndExit(ctx, globalErrorExitStatus)
+	}
+}
+
+func mainEventImport(cliCtx *cli.Context) error {
+	ctx, cancelEventImport := context.WithCancel(globalContext)
+	defer cancelEventImport()
+
+	checkEventImportSyntax(cliCtx)
+	console.SetColor("EventImport", color.New(color.FgGreen))
+	console.SetColor("EventImportArn", color.New(color.FgCyan))
+
+	urlStr := cliCtx.Args().Get(0)
+	rules, err := parseEventArnMap(cliCtx.StringSlice("arn-map"))
+	fatalIf(err, "Unable to parse --arn-map.")
+
+	client, err := newClient(urlStr)
+	fatalIf(err.Trace(urlStr), "Unable to initialize client for `"+urlStr+"`.")
+
+	s3Client, ok := client.(*S3Client)
+	if !ok {
+		fatalIf(errDummy().Trace(), "The provided url doesn't point to a S3 server.")
+	}
+
+	var config notification.Configuration
+	if e := json.NewDecoder(os.Stdin).Decode(&config); e != nil {
+		fatalIf(probe.NewError(e), "Unable to read notification configuration.")
+	}
+	if len(config.TopicConfigs)+len(config.QueueConfigs)+len(config.LambdaConfigs) == 0 {
+		// Abort here, otherwise all notifications of the bucket are removed.
+		fatalIf(errDummy(), "The provided notification configuration does not contain any target, aborting.")
+	}
+
+	remapped, err := remapNotificationConfig(&config, rules)
+	fatalIf(err, "Unable to rewrite the ARNs of the notification configuration.")
+
+	fatalIf(s3Client.SetNotificationConfig(ctx, config).Trace(urlStr), "Unable to set notification configuration.")
+
+	printMsg(eventImportMessage{
+		Status:   "success",
+		Target:   urlStr,
+		Remapped: remapped,
+	})
+	return nil
+}
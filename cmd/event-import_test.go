@@ -0,0 +1,115 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7/pkg/notification"
+)
+
+func TestParseEventArnMap(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		rules int
+		valid bool
+	}{
+		{nil, 0, true},
+		{[]string{"--arn-map", "arn:minio:sqs::primary:webhook=arn:minio:sqs::dr:webhook"}, 1, true},
+		{[]string{"--arn-map", "arn:minio:sqs::*:*=arn:minio:sqs::dr:*", "--arn-map", "arn:minio:sqs:us-east-1:1:kafka=arn:minio:sqs:us-west-2:1:kafka"}, 2, true},
+		{[]string{"--arn-map", "arn:minio:sqs::primary:webhook"}, 0, false},
+		{[]string{"--arn-map", "arn:minio:sqs::primary=arn:minio:sqs::dr"}, 0, false},
+		{[]string{"--arn-map", "urn:minio:sqs::primary:webhook=arn:minio:sqs::dr:webhook"}, 0, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, []cli.Flag{eventArnMapFlag}, testCase.args...)
+		rules, err := parseEventArnMap(cliCtx.StringSlice("arn-map"))
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+		if len(rules) != testCase.rules {
+			t.Errorf("Test %d: expected %d rules, got %d", i+1, testCase.rules, len(rules))
+		}
+	}
+}
+
+func TestRemapEventArn(t *testing.T) {
+	rules, err := parseEventArnMap([]string{
+		"arn:minio:sqs::primary:webhook=arn:minio:sqs::dr:webhook",
+		"arn:minio:sqs::*:kafka=arn:minio:sqs::dr-kafka:*",
+		"arn:minio:sqs:*:*:*=arn:minio:sqs:us-west-2:*:*",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		arn      string
+		expected string
+	}{
+		{"arn:minio:sqs::primary:webhook", "arn:minio:sqs::dr:webhook"},
+		// Wildcards of the target keep the part of the source.
+		{"arn:minio:sqs::primary:kafka", "arn:minio:sqs::dr-kafka:kafka"},
+		// The first matching rule wins.
+		{"arn:minio:sqs:us-east-1:1:amqp", "arn:minio:sqs:us-west-2:1:amqp"},
+		{"arn:aws:sns:us-east-1:1:topic", "arn:aws:sns:us-east-1:1:topic"},
+		{"not-an-arn", "not-an-arn"},
+	}
+	for i, testCase := range testCases {
+		if arn := remapEventArn(testCase.arn, rules); arn != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, arn)
+		}
+	}
+}
+
+func TestRemapNotificationConfig(t *testing.T) {
+	exported := `{
+ "QueueConfigs": [{"ID": "1", "Queue": "arn:minio:sqs::primary:webhook", "Events": ["s3:ObjectCreated:*"]}],
+ "TopicConfigs": [{"ID": "2", "Topic": "arn:aws:sns:us-east-1:1:topic", "Events": ["s3:ObjectRemoved:*"]}]
+}`
+	var config notification.Configuration
+	if e := json.Unmarshal([]byte(exported), &config); e != nil {
+		t.Fatal(e)
+	}
+	rules, err := parseEventArnMap([]string{"arn:minio:sqs::primary:*=arn:minio:sqs::dr:*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	remapped, err := remapNotificationConfig(&config, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]string{"arn:minio:sqs::primary:webhook": "arn:minio:sqs::dr:webhook"}; !reflect.DeepEqual(remapped, expected) {
+		t.Fatalf("expected %v remapped, got %v", expected, remapped)
+	}
+	if queue := config.QueueConfigs[0]; queue.Queue != "arn:minio:sqs::dr:webhook" || queue.Arn.AccountID != "dr" {
+		t.Fatalf("unexpected queue %+v", queue)
+	}
+	if topic := config.TopicConfigs[0]; topic.Topic != "arn:aws:sns:us-east-1:1:topic" {
+		t.Fatalf("unexpected topic %+v", topic)
+	}
+
+	// Targets must have valid ARNs.
+	config.QueueConfigs[0].Queue = "webhook"
+	if _, err = remapNotificationConfig(&config, rules); err == nil {
+		t.Fatal("expected an invalid ARN to be rejected")
+	}
+}
@@ -25,6 +25,8 @@ var eventSubcommands = []cli.Command{
 	eventAddCmd,
 	eventRemoveCmd,
 	eventListCmd,
+	eventExportCmd,
+	eventImportCmd,
 }
 
 var eventCmd = cli.Command{
@@ -0,0 +1,281 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
+)
+
+// featuresProbeObject is the object looked up by the probes needing
+// one, it is never created.
+const featuresProbeObject = ".mc-features-probe"
+
+// S3 multipart upload limits, no endpoint advertises others.
+var s3MultipartLimits = multipartLimitsV1{
+	MinPartSize:   5 * humanize.MiByte,
+	MaxPartSize:   5 * humanize.GiByte,
+	MaxParts:      10000,
+	MaxObjectSize: 5 * humanize.TiByte,
+}
+
+var featuresFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "refresh",
+		Usage: "probe again even if the features of the alias are cached",
+	},
+}
+
+var featuresCmd = cli.Command{
+	Name:         "features",
+	Usage:        "probe the S3 features supported by an alias",
+	Action:       mainFeatures,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(featuresFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] ALIAS[/BUCKET]
+
+  The bucket level features are probed on BUCKET, or on the first bucket
+  of the alias. Nothing is written. The outcome is cached for 7 days, and
+  used by other commands to reject flags requiring an unsupported feature
+  before starting, e.g. 'mc cp --tags' or 'mc sql'.
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+EXAMPLES:
+  1. Show the features supported by "myminio", probed on its first bucket.
+     {{.Prompt}} {{.HelpName}} myminio
+
+  2. Probe the features again on the bucket "mybucket" of "s3".
+     {{.Prompt}} {{.HelpName}} --refresh s3/mybucket
+`,
+}
+
+// featuresMessage container for the features of an alias.
+type featuresMessage struct {
+	Status string `json:"status"`
+	Alias  string `json:"alias"`
+	Cached bool   `json:"cached"`
+	aliasFeaturesV1
+}
+
+// String colorized features message
+func (f featuresMessage) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", console.Colorize("FeaturesAlias", f.Alias+":"), f.URL)
+	if f.Bucket != "" {
+		fmt.Fprintf(&b, "  %-16s %s\n", "Probed on:", f.Bucket)
+	}
+	probed := "Probed:"
+	if f.Cached {
+		probed = "Cached:"
+	}
+	fmt.Fprintf(&b, "  %-16s %s ago\n", probed, timeDurationToHumanizedDuration(time.Since(f.ProbedAt)).StringShort())
+
+	names := make([]string, 0, len(f.Features))
+	for name := range f.Features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		status := f.Features[name]
+		tag := "FeaturesUnknown"
+		switch status {
+		case featureSupported:
+			tag = "FeaturesSupported"
+		case featureUnsupported:
+			tag = "FeaturesUnsupported"
+		}
+		fmt.Fprintf(&b, "  %-16s %s\n", name+":", console.Colorize(tag, status))
+	}
+
+	fmt.Fprintf(&b, "  %-16s %s - %s per part, %d parts, %s per object",
+		"Multipart:", humanize.IBytes(uint64(f.Limits.MinPartSize)), humanize.IBytes(uint64(f.Limits.MaxPartSize)),
+		f.Limits.MaxParts, humanize.IBytes(uint64(f.Limits.MaxObjectSize)))
+	return b.String()
+}
+
+// JSON jsonified features message
+func (f featuresMessage) JSON() string {
+	f.Status = "success"
+	featuresMessageBytes, e := json.MarshalIndent(f, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(featuresMessageBytes)
+}
+
+// featureProbeStatus tells from the error of a probe whether a feature
+// is supported, codes stating the feature is merely not configured
+// mean it is supported.
+func featureProbeStatus(e error, supportedCodes ...string) string {
+	if e == nil {
+		return featureSupported
+	}
+	code := minio.ToErrorResponse(e).Code
+	switch code {
+	case "NotImplemented", "XNotImplemented", "MethodNotAllowed":
+		return featureUnsupported
+	}
+	for _, supportedCode := range supportedCodes {
+		if code == supportedCode {
+			return featureSupported
+		}
+	}
+	return featureUnknown
+}
+
+// probeChecksums returns supported if an object of the bucket has a
+// checksum, it is unknown otherwise as objects may just not have one.
+func probeChecksums(ctx context.Context, api *minio.Client, bucket string) string {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for object := range api.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true, MaxKeys: 1}) {
+		if object.Err != nil {
+			return featureUnknown
+		}
+		info, e := api.StatObject(ctx, bucket, object.Key, minio.StatObjectOptions{Checksum: true})
+		if e != nil {
+			return featureUnknown
+		}
+		if info.ChecksumCRC32 != "" || info.ChecksumCRC32C != "" || info.ChecksumSHA1 != "" || info.ChecksumSHA256 != "" {
+			return featureSupported
+		}
+		return featureUnknown
+	}
+	return featureUnknown
+}
+
+// probeFeatures probes the features of an S3 endpoint, only reading.
+func probeFeatures(ctx context.Context, api *minio.Client, bucket string) map[string]string {
+	features := map[string]string{
+		featureVersioning: featureUnknown,
+		featureObjectLock: featureUnknown,
+		featureTagging:    featureUnknown,
+		featureSelect:     featureUnknown,
+		featureChecksums:  featureUnknown,
+		// Renaming needs a write to probe, which is not done here.
+		featureRename: featureUnknown,
+	}
+	if bucket == "" {
+		return features
+	}
+
+	_, e := api.GetBucketVersioning(ctx, bucket)
+	features[featureVersioning] = featureProbeStatus(e)
+
+	_, _, _, _, e = api.GetObjectLockConfig(ctx, bucket)
+	features[featureObjectLock] = featureProbeStatus(e, "ObjectLockConfigurationNotFoundError")
+
+	_, e = api.GetBucketTagging(ctx, bucket)
+	features[featureTagging] = featureProbeStatus(e, "NoSuchTagSet")
+
+	results, e := api.SelectObjectContent(ctx, bucket, featuresProbeObject, minio.SelectObjectOptions{
+		Expression:     "select * from S3Object",
+		ExpressionType: minio.QueryExpressionTypeSQL,
+		InputSerialization: minio.SelectObjectInputSerialization{
+			CompressionType: minio.SelectCompressionNONE,
+			CSV:             &minio.CSVInputOptions{},
+		},
+		OutputSerialization: minio.SelectObjectOutputSerialization{
+			CSV: &minio.CSVOutputOptions{},
+		},
+	})
+	if e == nil {
+		results.Close()
+	}
+	features[featureSelect] = featureProbeStatus(e, "NoSuchKey")
+
+	features[featureChecksums] = probeChecksums(ctx, api, bucket)
+	return features
+}
+
+// mainFeatures is the handle for "mc features" command.
+func mainFeatures(cliCtx *cli.Context) error {
+	if len(cliCtx.Args()) != 1 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
+	}
+
+	console.SetColor("FeaturesAlias", color.New(color.Bold))
+	console.SetColor("FeaturesSupported", color.New(color.FgGreen))
+	console.SetColor("FeaturesUnsupported", color.New(color.FgRed))
+	console.SetColor("FeaturesUnknown", color.New(color.FgYellow))
+
+	aliasedURL := cliCtx.Args().Get(0)
+	alias, path := url2Alias(aliasedURL)
+	bucket := strings.Split(strings.TrimPrefix(path, "/"), "/")[0]
+
+	if !cliCtx.Bool("refresh") {
+		if f, ok := getCachedFeatures(alias); ok && (bucket == "" || bucket == f.Bucket) {
+			printMsg(featuresMessage{Alias: alias, Cached: true, aliasFeaturesV1: f})
+			return nil
+		}
+	}
+
+	aliasCfg, err := getAliasConfig(alias)
+	fatalIf(err.Trace(alias), "Unable to get the alias `"+alias+"`.")
+
+	clnt, err := newClient(alias + "/" + bucket)
+	fatalIf(err.Trace(aliasedURL), "Unable to initialize target `"+aliasedURL+"`.")
+	s3Client, ok := clnt.(*S3Client)
+	if !ok {
+		fatalIf(errInvalidArgument().Trace(aliasedURL), "Features can only be probed on S3 aliases.")
+	}
+
+	ctx, cancel := context.WithTimeout(globalContext, time.Minute)
+	defer cancel()
+
+	if bucket == "" {
+		buckets, e := s3Client.api.ListBuckets(ctx)
+		fatalIf(probe.NewError(e).Trace(alias), "Unable to list buckets of `"+alias+"`.")
+		if len(buckets) > 0 {
+			bucket = buckets[0].Name
+		}
+	}
+
+	f := aliasFeaturesV1{
+		URL:      aliasCfg.URL,
+		Bucket:   bucket,
+		ProbedAt: time.Now().UTC(),
+		Features: probeFeatures(ctx, s3Client.api, bucket),
+		Limits:   s3MultipartLimits,
+	}
+
+	features, err := loadFeatures()
+	fatalIf(err, "Unable to load the cached features.")
+	features.Aliases[alias] = f
+	fatalIf(features.save(), "Unable to save the probed features.")
+
+	printMsg(featuresMessage{Alias: alias, aliasFeaturesV1: f})
+	return nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/quick"
+)
+
+// Feature names reported by 'mc features'.
+const (
+	featureVersioning = "versioning"
+	featureObjectLock = "object-lock"
+	featureTagging    = "tagging"
+	featureSelect     = "select"
+	featureChecksums  = "checksums"
+	featureRename     = "rename"
+)
+
+// Outcome of probing a feature.
+const (
+	featureSupported   = "supported"
+	featureUnsupported = "unsupported"
+	featureUnknown     = "unknown"
+)
+
+// featuresCacheTTL is how long probed features are used by other
+// commands before they need to be probed again.
+const featuresCacheTTL = 7 * 24 * time.Hour
+
+// multipartLimitsV1 are the multipart upload limits of an endpoint.
+type multipartLimitsV1 struct {
+	MinPartSize   int64 `json:"minPartSize"`
+	MaxPartSize   int64 `json:"maxPartSize"`
+	MaxParts      int   `json:"maxParts"`
+	MaxObjectSize int64 `json:"maxObjectSize"`
+}
+
+// aliasFeaturesV1 are the features probed on the endpoint of an alias.
+type aliasFeaturesV1 struct {
+	// URL of the alias when probed, a changed URL is probed again.
+	URL      string            `json:"url"`
+	Bucket   string            `json:"bucket,omitempty"`
+	ProbedAt time.Time         `json:"probedAt"`
+	Features map[string]string `json:"features"`
+	Limits   multipartLimitsV1 `json:"multipartLimits"`
+}
+
+// supports returns false only if a feature was probed as unsupported.
+func (f aliasFeaturesV1) supports(feature string) bool {
+	return f.Features[feature] != featureUnsupported
+}
+
+// JSON file to persist the features probed by 'mc features'.
+type featuresV1 struct {
+	Version string                     `json:"version"`
+	Aliases map[string]aliasFeaturesV1 `json:"aliases"`
+}
+
+// Instantiate a new features structure for persistence.
+func newFeaturesV1() *featuresV1 {
+	return &featuresV1{
+		Version: "1",
+		Aliases: make(map[string]aliasFeaturesV1),
+	}
+}
+
+// loadFeatures loads the features file, a missing file has no entries.
+func loadFeatures() (*featuresV1, *probe.Error) {
+	filename := mustGetFeaturesFile()
+	if _, e := os.Stat(filename); os.IsNotExist(e) {
+		return newFeaturesV1(), nil
+	}
+	qs, e := quick.NewConfig(newFeaturesV1(), nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Load(filename); e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	features := qs.Data().(*featuresV1)
+	if features.Aliases == nil {
+		features.Aliases = make(map[string]aliasFeaturesV1)
+	}
+	return features, nil
+}
+
+// save persists the features to disk.
+func (f *featuresV1) save() *probe.Error {
+	filename := mustGetFeaturesFile()
+	qs, e := quick.NewConfig(f, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e = qs.Save(filename); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// Get features file name or die. (NOTE: This `Die` approach is only OK for mc like tools.).
+func mustGetFeaturesFile() string {
+	return filepath.Join(mustGetMcConfigDir(), globalMCFeaturesFile)
+}
+
+// getCachedFeatures returns the features of an alias probed by
+// 'mc features', if they are still fresh and the alias URL did not
+// change since. Nothing is probed here.
+func getCachedFeatures(alias string) (aliasFeaturesV1, bool) {
+	aliasCfg, err := getAliasConfig(alias)
+	if err != nil {
+		return aliasFeaturesV1{}, false
+	}
+	features, err := loadFeatures()
+	if err != nil {
+		return aliasFeaturesV1{}, false
+	}
+	f, ok := features.Aliases[alias]
+	if !ok || f.URL != aliasCfg.URL || time.Since(f.ProbedAt) > featuresCacheTTL {
+		return aliasFeaturesV1{}, false
+	}
+	return f, true
+}
+
+// checkAliasFeature fails before a long operation if the target alias
+// was probed as not supporting a feature a flag requires.
+func checkAliasFeature(aliasedURL, feature, flag string) {
+	alias, _ := url2Alias(aliasedURL)
+	f, ok := getCachedFeatures(alias)
+	if !ok || f.supports(feature) {
+		return
+	}
+	fatalIf(errInvalidArgument().Trace(aliasedURL),
+		"`"+alias+"` does not support "+feature+", required by "+flag+
+			" (probed "+timeDurationToHumanizedDuration(time.Since(f.ProbedAt)).StringShort()+
+			" ago, run 'mc features --refresh "+alias+"' if it changed).")
+}
@@ -0,0 +1,475 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/wildcard"
+)
+
+// filterFlag is shared by all commands filtering the objects they list.
+var filterFlag = cli.StringFlag{
+	Name:  "filter",
+	Usage: "only include objects matching a filter expression, e.g. 'size > 1MiB && name ~ \"*.log\" && age > 30d'",
+}
+
+// Fields of a filter expression and the kind of values they compare with.
+const (
+	filterKindSize = iota
+	filterKindAge
+	filterKindTime
+	filterKindString
+)
+
+var filterFields = map[string]int{
+	"size":  filterKindSize,
+	"age":   filterKindAge,
+	"time":  filterKindTime,
+	"name":  filterKindString,
+	"path":  filterKindString,
+	"class": filterKindString,
+	"etag":  filterKindString,
+	"type":  filterKindString,
+}
+
+// Operators allowed per kind of field, '~' and '!~' match shell style
+// wildcards, '=~' matches an RE2 regex.
+var filterOperators = map[int][]string{
+	filterKindSize:   {"==", "!=", "<", "<=", ">", ">="},
+	filterKindAge:    {"==", "!=", "<", "<=", ">", ">="},
+	filterKindTime:   {"==", "!=", "<", "<=", ">", ">="},
+	filterKindString: {"==", "!=", "~", "!~", "=~"},
+}
+
+// Keywords which may be used in place of the boolean operators.
+var filterKeywords = map[string]string{
+	"and": "&&",
+	"or":  "||",
+	"not": "!",
+}
+
+// contentFilter is a parsed filter expression.
+type contentFilter struct {
+	expr filterNode
+}
+
+type filterNode interface {
+	match(c *ClientContent, now time.Time) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+
+func (f filterAnd) match(c *ClientContent, now time.Time) bool {
+	return f.left.match(c, now) && f.right.match(c, now)
+}
+
+type filterOr struct{ left, right filterNode }
+
+func (f filterOr) match(c *ClientContent, now time.Time) bool {
+	return f.left.match(c, now) || f.right.match(c, now)
+}
+
+type filterNot struct{ node filterNode }
+
+func (f filterNot) match(c *ClientContent, now time.Time) bool {
+	return !f.node.match(c, now)
+}
+
+// filterCompare compares one field of an object with a value.
+type filterCompare struct {
+	field string
+	op    string
+	num   int64
+	t     time.Time
+	str   string
+	re    *regexp.Regexp
+}
+
+func (f filterCompare) match(c *ClientContent, now time.Time) bool {
+	switch filterFields[f.field] {
+	case filterKindSize:
+		return compareFilterInt(c.Size, f.op, f.num)
+	case filterKindAge:
+		return compareFilterInt(int64(now.Sub(c.Time)), f.op, f.num)
+	case filterKindTime:
+		return compareFilterInt(c.Time.UnixNano(), f.op, f.t.UnixNano())
+	}
+
+	var value string
+	switch f.field {
+	case "name":
+		value = path.Base(strings.TrimSuffix(strings.ReplaceAll(c.URL.Path, string(c.URL.Separator), "/"), "/"))
+	case "path":
+		value = strings.ReplaceAll(c.URL.Path, string(c.URL.Separator), "/")
+	case "class":
+		value = c.StorageClass
+	case "etag":
+		value = strings.Trim(c.ETag, "\"")
+	case "type":
+		value = "file"
+		if c.Type.IsDir() {
+			value = "dir"
+		}
+	}
+	switch f.op {
+	case "==":
+		return value == f.str
+	case "!=":
+		return value != f.str
+	case "~":
+		return wildcard.Match(f.str, value)
+	case "!~":
+		return !wildcard.Match(f.str, value)
+	case "=~":
+		return f.re.MatchString(value)
+	}
+	return false
+}
+
+func compareFilterInt(a int64, op string, b int64) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+// and returns a filter matching the objects matched by both f and g,
+// either of which may be nil.
+func (f *contentFilter) and(g *contentFilter) *contentFilter {
+	switch {
+	case f == nil:
+		return g
+	case g == nil:
+		return f
+	}
+	return &contentFilter{expr: filterAnd{f.expr, g.expr}}
+}
+
+// Match returns true if the object matches the filter, a nil filter
+// matches everything.
+func (f *contentFilter) Match(c *ClientContent) bool {
+	if f == nil || c == nil {
+		return true
+	}
+	return f.expr.match(c, time.Now())
+}
+
+// filterToken is a token of a filter expression, quoted strings are
+// never operators.
+type filterToken struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+// tokenizeFilter splits a filter expression into tokens.
+func tokenizeFilter(s string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, filterToken{text: s[i+1 : i+1+end], quoted: true, pos: i + 1})
+			i += end + 2
+		case strings.HasPrefix(s[i:], "&&"), strings.HasPrefix(s[i:], "||"),
+			strings.HasPrefix(s[i:], "=="), strings.HasPrefix(s[i:], "!="),
+			strings.HasPrefix(s[i:], "<="), strings.HasPrefix(s[i:], ">="),
+			strings.HasPrefix(s[i:], "!~"), strings.HasPrefix(s[i:], "=~"):
+			tokens = append(tokens, filterToken{text: s[i : i+2], pos: i + 1})
+			i += 2
+		case strings.IndexByte("()!<>~", c) >= 0:
+			tokens = append(tokens, filterToken{text: s[i : i+1], pos: i + 1})
+			i++
+		default:
+			start := i
+			for i < len(s) && !unicode.IsSpace(rune(s[i])) && strings.IndexByte("()!<>=~&|\"'", s[i]) < 0 {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected `%c` at position %d", c, i+1)
+			}
+			tokens = append(tokens, filterToken{text: s[start:i], pos: start + 1})
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser of the grammar
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = field operator value
+//
+// where "and", "or" and "not" may be written for "&&", "||" and "!".
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *filterParser) accept(op string) bool {
+	if t, ok := p.peek(); ok && !t.quoted && (t.text == op || filterKeywords[strings.ToLower(t.text)] == op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) next(what string) (filterToken, error) {
+	t, ok := p.peek()
+	if !ok {
+		return t, fmt.Errorf("expected %s at end of filter", what)
+	}
+	p.pos++
+	return t, nil
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, e := p.parseAnd()
+	if e != nil {
+		return nil, e
+	}
+	for p.accept("||") {
+		right, e := p.parseAnd()
+		if e != nil {
+			return nil, e
+		}
+		left = filterOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, e := p.parseUnary()
+	if e != nil {
+		return nil, e
+	}
+	for p.accept("&&") {
+		right, e := p.parseUnary()
+		if e != nil {
+			return nil, e
+		}
+		left = filterAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.accept("!") {
+		node, e := p.parseUnary()
+		if e != nil {
+			return nil, e
+		}
+		return filterNot{node}, nil
+	}
+	if p.accept("(") {
+		node, e := p.parseOr()
+		if e != nil {
+			return nil, e
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("expected `)` at position %d", p.position())
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) position() int {
+	if t, ok := p.peek(); ok {
+		return t.pos
+	}
+	return -1
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	fieldTok, e := p.next("a field")
+	if e != nil {
+		return nil, e
+	}
+	field := strings.ToLower(fieldTok.text)
+	kind, ok := filterFields[field]
+	if !ok || fieldTok.quoted {
+		return nil, fmt.Errorf("unknown field `%s` at position %d, expected one of size, age, time, name, path, class, etag, type", fieldTok.text, fieldTok.pos)
+	}
+
+	opTok, e := p.next("an operator")
+	if e != nil {
+		return nil, e
+	}
+	var valid bool
+	for _, op := range filterOperators[kind] {
+		valid = valid || (!opTok.quoted && opTok.text == op)
+	}
+	if !valid {
+		return nil, fmt.Errorf("operator `%s` at position %d cannot be used with `%s`, expected one of %s",
+			opTok.text, opTok.pos, field, strings.Join(filterOperators[kind], " "))
+	}
+
+	valueTok, e := p.next("a value")
+	if e != nil {
+		return nil, e
+	}
+	cmp := filterCompare{field: field, op: opTok.text}
+	switch kind {
+	case filterKindSize:
+		size, e := humanize.ParseBytes(valueTok.text)
+		if e != nil {
+			return nil, fmt.Errorf("invalid size `%s` at position %d", valueTok.text, valueTok.pos)
+		}
+		cmp.num = int64(size)
+	case filterKindAge:
+		age, e := ParseDuration(valueTok.text)
+		if e != nil {
+			return nil, fmt.Errorf("invalid age `%s` at position %d", valueTok.text, valueTok.pos)
+		}
+		cmp.num = int64(age)
+	case filterKindTime:
+		t, e := parseFilterTime(valueTok.text)
+		if e != nil {
+			return nil, fmt.Errorf("invalid time `%s` at position %d", valueTok.text, valueTok.pos)
+		}
+		cmp.t = t
+	default:
+		cmp.str = valueTok.text
+		if cmp.op == "=~" {
+			re, e := regexp.Compile(valueTok.text)
+			if e != nil {
+				return nil, fmt.Errorf("invalid regex `%s` at position %d: %v", valueTok.text, valueTok.pos, e)
+			}
+			cmp.re = re
+		}
+	}
+	return cmp, nil
+}
+
+// parseFilterTime parses an RFC3339 time, a date or a unix timestamp.
+func parseFilterTime(s string) (time.Time, error) {
+	if t, e := time.Parse(time.RFC3339, s); e == nil {
+		return t, nil
+	}
+	if t, e := time.Parse("2006-01-02", s); e == nil {
+		return t, nil
+	}
+	secs, e := strconv.ParseInt(s, 10, 64)
+	if e != nil {
+		return time.Time{}, e
+	}
+	return time.Unix(secs, 0), nil
+}
+
+// parseContentFilter parses a filter expression, an empty expression
+// returns a nil filter which matches all objects.
+func parseContentFilter(s string) (*contentFilter, *probe.Error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	tokens, e := tokenizeFilter(s)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(s)
+	}
+	p := &filterParser{tokens: tokens}
+	expr, e := p.parseOr()
+	if e == nil && p.pos < len(p.tokens) {
+		e = fmt.Errorf("unexpected `%s` at position %d", p.tokens[p.pos].text, p.tokens[p.pos].pos)
+	}
+	if e != nil {
+		return nil, probe.NewError(e).Trace(s)
+	}
+	return &contentFilter{expr: expr}, nil
+}
+
+// parseSizeRange parses a size range in the style of find(1), "+N"
+// matches objects larger than N, "-N" smaller than N and "N" exactly N.
+func parseSizeRange(s string) (filterNode, *probe.Error) {
+	op := "=="
+	value := s
+	switch {
+	case strings.HasPrefix(s, "+"):
+		op, value = ">", s[1:]
+	case strings.HasPrefix(s, "-"):
+		op, value = "<", s[1:]
+	}
+	size, e := humanize.ParseBytes(value)
+	if e != nil {
+		return nil, probe.NewError(fmt.Errorf("invalid size range `%s`, expected +N, -N or N with an optional unit", s))
+	}
+	return filterCompare{field: "size", op: op, num: int64(size)}, nil
+}
+
+// parseSizeRanges parses the size ranges all of which objects must
+// match, no ranges return a nil filter which matches all objects.
+func parseSizeRanges(ranges []string) (*contentFilter, *probe.Error) {
+	var expr filterNode
+	for _, r := range ranges {
+		node, err := parseSizeRange(r)
+		if err != nil {
+			return nil, err.Trace(r)
+		}
+		if expr == nil {
+			expr = node
+		} else {
+			expr = filterAnd{expr, node}
+		}
+	}
+	if expr == nil {
+		return nil, nil
+	}
+	return &contentFilter{expr: expr}, nil
+}
+
+// mustParseContentFilter parses the --filter flag or dies.
+func mustParseContentFilter(cliCtx *cli.Context) *contentFilter {
+	f, err := parseContentFilter(cliCtx.String("filter"))
+	fatalIf(err, "Unable to parse --filter.")
+	return f
+}
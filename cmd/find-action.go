@@ -0,0 +1,226 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var findActionFlags = append([]cli.Flag{
+	cli.BoolFlag{
+		Name:  "delete",
+		Usage: "remove the objects found",
+	},
+	cli.StringFlag{
+		Name:  "copy-to",
+		Usage: "copy the objects found to a target, keeping their path below the searched path",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "print the objects --delete or --copy-to would act on without acting",
+	},
+	cli.BoolFlag{
+		Name:  "force",
+		Usage: "remove the objects found with --delete without asking for confirmation",
+	},
+}, retryFlags[:2]...)
+
+// checkFindActionSyntax - returns why --delete and --copy-to cannot be
+// used with the other flags passed, empty if they can.
+func checkFindActionSyntax(cliCtx *cli.Context) string {
+	isDelete, copyTo := cliCtx.Bool("delete"), cliCtx.String("copy-to")
+	switch {
+	case isDelete && copyTo != "":
+		return "--delete and --copy-to cannot be used together."
+	case !isDelete && copyTo == "":
+		if cliCtx.Bool("dry-run") || cliCtx.Bool("force") {
+			return "--dry-run and --force require --delete or --copy-to."
+		}
+		return ""
+	case cliCtx.String("exec") != "" || cliCtx.String("print") != "":
+		return "--delete and --copy-to cannot be used with --exec or --print."
+	case cliCtx.Bool("watch"):
+		return "--delete and --copy-to cannot be used with --watch."
+	case cliCtx.Bool("empty-dirs"):
+		return "--delete and --copy-to cannot be used with --empty-dirs, use --remove to remove empty folders."
+	}
+	return ""
+}
+
+// confirmFindDelete asks the user to confirm removing the objects
+// found under target, --delete without a terminal requires --force.
+func confirmFindDelete(target string) bool {
+	if !isTerminal() {
+		fatalIf(errInvalidArgument().Trace(target), "--delete requires --force when not run in a terminal.")
+	}
+	fmt.Printf("You are about to remove all objects found under `%s`, please confirm [y/N]: ", target)
+	answer, e := bufio.NewReader(os.Stdin).ReadString('\n')
+	fatalIf(probe.NewError(e), "Unable to parse user input.")
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// findCopyMessage container for an object copied by find --copy-to.
+type findCopyMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// String colorized find copy message.
+func (f findCopyMessage) String() string {
+	if f.DryRun {
+		return console.Colorize("Find", fmt.Sprintf("DRYRUN: Copying `%s` to `%s`.", f.Source, f.Target))
+	}
+	return console.Colorize("Find", fmt.Sprintf("Copied `%s` to `%s`.", f.Source, f.Target))
+}
+
+// JSON jsonified find copy message.
+func (f findCopyMessage) JSON() string {
+	f.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(f, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// findAction removes or copies the objects found. Removals are passed
+// to a single Remove call of the searched client, which batches them
+// like rm does, copies are retried like cp does.
+type findAction struct {
+	ctx        context.Context
+	fctx       *findContext
+	copyTo     string
+	dryRun     bool
+	retries    int
+	retryDelay time.Duration
+	encKeyDB   encryptionConfig
+
+	removeCh chan *ClientContent
+	done     chan struct{}
+
+	mu     sync.Mutex
+	failed int
+}
+
+// newFindAction returns the action requested by --delete or --copy-to,
+// nil if there is none.
+func newFindAction(ctx context.Context, cliCtx *cli.Context, fctx *findContext, encKeyDB encryptionConfig) (*findAction, *probe.Error) {
+	if !cliCtx.Bool("delete") && cliCtx.String("copy-to") == "" {
+		return nil, nil
+	}
+	retries, retryDelay, err := parseRetry(cliCtx)
+	if err != nil {
+		return nil, err
+	}
+	a := &findAction{
+		ctx:        ctx,
+		fctx:       fctx,
+		copyTo:     cliCtx.String("copy-to"),
+		dryRun:     cliCtx.Bool("dry-run"),
+		retries:    retries,
+		retryDelay: retryDelay,
+		encKeyDB:   encKeyDB,
+	}
+	if a.copyTo == "" && !a.dryRun {
+		a.removeCh = make(chan *ClientContent)
+		a.done = make(chan struct{})
+		resultCh := fctx.clnt.Remove(ctx, false, false, false, false, a.removeCh)
+		go func() {
+			defer close(a.done)
+			for result := range resultCh {
+				key := path.Join(fctx.targetAlias, result.BucketName, result.ObjectName)
+				if result.Err != nil {
+					errorIf(result.Err.Trace(key), "Unable to remove `%s`.", key)
+					a.fail()
+					continue
+				}
+				msg := rmMessage{Key: key, VersionID: result.ObjectVersionID}
+				if result.DeleteMarker {
+					msg.DeleteMarker = true
+					msg.VersionID = result.DeleteMarkerVersionID
+				}
+				printMsg(msg)
+			}
+		}()
+	}
+	return a, nil
+}
+
+func (a *findAction) fail() {
+	a.mu.Lock()
+	a.failed++
+	a.mu.Unlock()
+}
+
+// do removes or copies an object found, folders are skipped.
+func (a *findAction) do(content *ClientContent, fileContent contentMessage) {
+	if content.Type.IsDir() {
+		return
+	}
+	if a.copyTo == "" {
+		if a.dryRun {
+			printMsg(rmMessage{Key: fileContent.Key, VersionID: fileContent.VersionID, DryRun: true})
+			return
+		}
+		a.removeCh <- content
+		return
+	}
+
+	clntURL := a.fctx.clnt.GetURL()
+	relPath := strings.TrimPrefix(strings.TrimPrefix(content.URL.Path, clntURL.Path), string(clntURL.Separator))
+	targetAliasedURL := urlJoinPath(a.copyTo, relPath)
+	if a.dryRun {
+		printMsg(findCopyMessage{Source: fileContent.Key, Target: targetAliasedURL, DryRun: true})
+		return
+	}
+	targetAlias, targetURL, _ := mustExpandAlias(targetAliasedURL)
+	urls := makeCopyContentTypeA(a.fctx.targetAlias, content, targetAlias, targetURL)
+	urls.Retries, urls.RetryDelay = a.retries, a.retryDelay
+	if err := uploadSourceToTargetURLWithRetry(a.ctx, urls, nil, a.encKeyDB, false, false).Error; err != nil {
+		errorIf(err.Trace(fileContent.Key), "Unable to copy `%s`.", fileContent.Key)
+		a.fail()
+		return
+	}
+	printMsg(findCopyMessage{Source: fileContent.Key, Target: targetAliasedURL})
+}
+
+// wait returns when all removals are done, with an error if any
+// object could not be removed or copied.
+func (a *findAction) wait() error {
+	if a.removeCh != nil {
+		close(a.removeCh)
+		<-a.done
+	}
+	if a.failed > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/minio/cli"
+)
+
+var execWorkersFlag = cli.IntFlag{
+	Name:  "exec-workers",
+	Usage: "number of --exec commands to run at the same time",
+	Value: 1,
+}
+
+// checkExecWorkersSyntax - returns why --exec-workers cannot be used
+// with the other flags passed, empty if it can.
+func checkExecWorkersSyntax(cliCtx *cli.Context) string {
+	switch {
+	case !cliCtx.IsSet("exec-workers"):
+		return ""
+	case cliCtx.Int("exec-workers") < 1:
+		return "--exec-workers must be at least 1."
+	case cliCtx.String("exec") == "":
+		return "--exec-workers requires --exec."
+	}
+	return ""
+}
+
+// findExecutor runs the --exec command of each object found, up to
+// workers commands at a time. A failing command still ends find with
+// its exit status.
+type findExecutor struct {
+	ctx  context.Context
+	cmd  string
+	jobs chan contentMessage
+	wg   sync.WaitGroup
+}
+
+// newFindExecutor starts the workers running cmd, a single worker runs
+// the commands in order as they are found.
+func newFindExecutor(ctx context.Context, cmd string, workers int) *findExecutor {
+	e := &findExecutor{ctx: ctx, cmd: cmd}
+	if workers <= 1 {
+		return e
+	}
+	e.jobs = make(chan contentMessage, workers)
+	for i := 0; i < workers; i++ {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			for fileContent := range e.jobs {
+				execFind(e.ctx, e.cmd, fileContent)
+			}
+		}()
+	}
+	return e
+}
+
+// exec runs the command of an object, waiting for a free worker.
+func (e *findExecutor) exec(fileContent contentMessage) {
+	if e.jobs == nil {
+		execFind(e.ctx, e.cmd, fileContent)
+		return
+	}
+	e.jobs <- fileContent
+}
+
+// wait returns when all commands passed to exec are done.
+func (e *findExecutor) wait() {
+	if e.jobs != nil {
+		close(e.jobs)
+		e.wg.Wait()
+	}
+}
@@ -77,9 +77,13 @@ var (
 			Name:  "smaller",
 			Usage: "match all objects smaller than specified size in units (see UNITS)",
 		},
+		cli.StringSliceFlag{
+			Name:  "size",
+			Usage: "match object sizes in a range, +N is larger and -N smaller than N in units (see UNITS)",
+		},
 		cli.UintFlag{
-			Name:  "maxdepth",
-			Usage: "limit directory navigation to specified depth",
+			Name:  "maxdepth, max-depth",
+			Usage: "limit directory navigation to specified depth, deeper prefixes are not listed at all",
 		},
 		cli.BoolFlag{
 			Name:  "watch",
@@ -93,6 +97,19 @@ var (
 			Name:  "tags",
 			Usage: "match tags with RE2 regex pattern. Specify each with key=regex. MinIO server only.",
 		},
+		cli.BoolFlag{
+			Name:  "empty-dirs",
+			Usage: "find zero byte directory markers and folders without any objects",
+		},
+		cli.BoolFlag{
+			Name:  "remove",
+			Usage: "remove the empty folders found with --empty-dirs",
+		},
+		cli.StringFlag{
+			Name:  "storage-class, sc",
+			Usage: "match objects of storage classes matching wildcard pattern",
+		},
+		tierFlag,
 	}
 )
 
@@ -102,7 +119,7 @@ var findCmd = cli.Command{
 	Action:       mainFind,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(findFlags, globalFlags...),
+	Flags:        joinFlags(findFlags, []cli.Flag{requestPayerFlag, filterFlag, execWorkersFlag}, findActionFlags, keyOutputFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -113,7 +130,7 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 UNITS
-  --smaller, --larger flags accept human-readable case-insensitive number
+  --smaller, --larger, --size flags accept human-readable case-insensitive number
   suffixes such as "k", "m", "g" and "t" referring to the metric units KB,
   MB, GB and TB respectively. Adding an "i" to these prefixes, uses the IEC
   units, so that "gi" refers to "gibibyte" or "GiB". A "b" at the end is
@@ -126,16 +143,20 @@ FORMAT
   Support string substitutions with special interpretations for following keywords.
   Keywords supported if target is filesystem or object storage:
 
-     {}        --> Substitutes to full path.
-     {base}    --> Substitutes to basename of path.
-     {dir}     --> Substitutes to dirname of the path.
-     {size}    --> Substitutes to object size of the path.
-     {time}    --> Substitutes to object modified time of the path.
-     {version} --> Substitutes to object version identifier.
+     {}           --> Substitutes to full path.
+     {base}       --> Substitutes to basename of path.
+     {dir}        --> Substitutes to dirname of the path.
+     {size}       --> Substitutes to object size of the path.
+     {time}       --> Substitutes to object modified time of the path.
+     {version}    --> Substitutes to object version identifier.
+     {version-id} --> Same as {version}.
 
   Keywords supported if target is object storage:
 
-     {url} --> Substitutes to a shareable URL of the path.
+     {url}        --> Substitutes to a shareable URL of the path.
+
+  Keywords prefixed with "q" such as {q}, {qbase}, {qdir} and {qurl} substitute
+  shell escaped values, safe to use within "sh -c" commands.
 
 EXAMPLES:
   01. Find all "foo.jpg" in all buckets under "s3" account.
@@ -171,11 +192,62 @@ EXAMPLES:
 
   11. Copy all versions of all objects in bucket in the local machine
       {{.Prompt}} {{.HelpName}} s3/bucket --versions --exec "mc cp --version-id {version} {} /tmp/dir/{}.{version}"
+
+  12. Find all objects under "s3/bucket" and print them NUL terminated for consumption by "xargs -0".
+      {{.Prompt}} {{.HelpName}} s3/bucket --print0 | xargs -0 -n1 echo
+
+  13. Find directory markers and folders without any objects under "s3/bucket", deepest first.
+      {{.Prompt}} {{.HelpName}} s3/bucket --empty-dirs
+
+  14. Remove all empty folders and directory markers left behind by a migration under "s3/bucket".
+      {{.Prompt}} {{.HelpName}} s3/bucket --empty-dirs --remove
+
+  15. Find objects larger than 1MiB and older than 30 days which are not in the GLACIER storage class.
+      {{.Prompt}} {{.HelpName}} s3/bucket --filter 'size > 1MiB && age > 30d && !(class == GLACIER)'
+
+  16. Restore all objects transitioned to the remote tier "WARM" which are not restored yet.
+      {{.Prompt}} {{.HelpName}} myminio/bucket --storage-class WARM --tier remote --exec "mc ilm restore {}"
+
+  17. Find all objects with a restored copy available, to rewrite them in the local storage class.
+      {{.Prompt}} {{.HelpName}} myminio/bucket --tier restored
+
+  18. Find all objects between 10KiB and 1GiB in size under "s3/bucket".
+      {{.Prompt}} {{.HelpName}} s3/bucket --size +10KiB --size=-1GiB
+
+  19. Find large old logs outside of the "archive/" prefix, combining regexes with "and", "or" and "not".
+      {{.Prompt}} {{.HelpName}} s3/logs --filter 'size > 1GiB and age > 90d and not path =~ "/archive/" and (name ~ "*.log" or name =~ "\.log\.[0-9]+$")'
+
+  20. Remove all versions of all objects under "s3/bucket" older than 1 year, running 16 removals at a time.
+      {{.Prompt}} {{.HelpName}} s3/bucket --versions --older-than 365d --exec-workers 16 --exec "mc rm --version-id {version-id} {}"
+
+  21. Print the logs older than 90 days under "s3/logs" which would be removed, then remove them.
+      {{.Prompt}} {{.HelpName}} s3/logs --name "*.log" --older-than 90d --delete --dry-run
+      {{.Prompt}} {{.HelpName}} s3/logs --name "*.log" --older-than 90d --delete
+
+  22. Copy all PDF documents under "s3/docs" to "backup/docs", keeping their paths and retrying failed copies.
+      {{.Prompt}} {{.HelpName}} s3/docs --name "*.pdf" --copy-to backup/docs --retry 3
+
+  23. Print the name and the size of all objects larger than 1GiB under "s3/backups", separated by a tab.
+      {{.Prompt}} {{.HelpName}} s3/backups --larger 1GiB --format-template '{{"{{.Key}}\\t{{bytes .Size}}"}}'
 `,
 }
 
+// checkEmptyDirsSyntax - returns why --empty-dirs and --remove cannot
+// be used with the other flags passed, empty if they can.
+func checkEmptyDirsSyntax(cliCtx *cli.Context) string {
+	switch {
+	case cliCtx.Bool("remove") && !cliCtx.Bool("empty-dirs"):
+		return "--remove can only be used with --empty-dirs."
+	case cliCtx.Bool("empty-dirs") && (cliCtx.Bool("watch") || cliCtx.Bool("versions")):
+		return "--empty-dirs cannot be used with --watch or --versions."
+	case cliCtx.Bool("empty-dirs") && (cliCtx.String("storage-class") != "" || cliCtx.String("tier") != ""):
+		return "--empty-dirs cannot be used with --storage-class or --tier."
+	}
+	return ""
+}
+
 // checkFindSyntax - validate the passed arguments
-func checkFindSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+func checkFindSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) {
 	args := cliCtx.Args()
 	if !args.Present() {
 		args = []string{"./"} // No args just default to present directory.
@@ -189,6 +261,16 @@ func checkFindSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[stri
 		}
 	}
 
+	if msg := checkEmptyDirsSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(args...), msg)
+	}
+	if msg := checkExecWorkersSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(args...), msg)
+	}
+	if msg := checkFindActionSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(args...), msg)
+	}
+
 	// Extract input URLs and validate.
 	for _, url := range args {
 		_, _, err := url2Stat(ctx, url, "", false, encKeyDB, time.Time{}, false)
@@ -218,10 +300,17 @@ type findContext struct {
 	newerThan         string
 	largerSize        uint64
 	smallerSize       uint64
+	sizeRange         *contentFilter
+	filter            *contentFilter
+	executor          *findExecutor
+	action            *findAction
+	tier              tierResidencyFilter
 	watch             bool
 	withOlderVersions bool
 	matchMeta         map[string]*regexp.Regexp
 	matchTags         map[string]*regexp.Regexp
+	emptyDirs         bool
+	removeEmptyDirs   bool
 
 	// Internal values
 	targetAlias   string
@@ -238,6 +327,7 @@ func mainFind(cliCtx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("Find", color.New(color.FgGreen, color.Bold))
 	console.SetColor("FindExecErr", color.New(color.FgRed, color.Italic, color.Bold))
+	console.SetColor("Removed", color.New(color.FgGreen, color.Bold))
 
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(cliCtx)
@@ -279,6 +369,9 @@ func mainFind(cliCtx *cli.Context) error {
 		fatalIf(probe.NewError(e).Trace(cliCtx.String("smaller")), "Unable to parse input bytes.")
 	}
 
+	sizeRange, err := parseSizeRanges(cliCtx.StringSlice("size"))
+	fatalIf(err, "Unable to parse --size.")
+
 	// Get --versions flag
 	withVersions := cliCtx.Bool("versions")
 
@@ -289,15 +382,20 @@ func mainFind(cliCtx *cli.Context) error {
 	if hostCfg != nil {
 		targetFullURL = hostCfg.URL
 	}
+	tier, err := parseTierResidencyFilter(cliCtx.String("storage-class"), cliCtx.String("tier"))
+	fatalIf(err.Trace(args...), "Unable to parse --tier.")
+	tier.alias = targetAlias
+
 	var regMatch *regexp.Regexp
 	if cliCtx.String("regex") != "" {
 		regMatch = regexp.MustCompile(cliCtx.String("regex"))
 	}
 
-	return doFind(ctx, &findContext{
+	fctx := &findContext{
 		Context:           cliCtx,
 		maxDepth:          cliCtx.Uint("maxdepth"),
 		execCmd:           cliCtx.String("exec"),
+		executor:          newFindExecutor(ctx, cliCtx.String("exec"), cliCtx.Int("exec-workers")),
 		printFmt:          cliCtx.String("print"),
 		namePattern:       cliCtx.String("name"),
 		pathPattern:       cliCtx.String("path"),
@@ -308,6 +406,9 @@ func mainFind(cliCtx *cli.Context) error {
 		newerThan:         newerThan,
 		largerSize:        largerSize,
 		smallerSize:       smallerSize,
+		sizeRange:         sizeRange,
+		filter:            mustParseContentFilter(cliCtx),
+		tier:              tier,
 		watch:             cliCtx.Bool("watch"),
 		targetAlias:       targetAlias,
 		targetURL:         args[0],
@@ -315,5 +416,19 @@ func mainFind(cliCtx *cli.Context) error {
 		clnt:              clnt,
 		matchMeta:         getRegexMap(cliCtx, "metadata"),
 		matchTags:         getRegexMap(cliCtx, "tags"),
-	})
+		emptyDirs:         cliCtx.Bool("empty-dirs"),
+		removeEmptyDirs:   cliCtx.Bool("remove"),
+	}
+	fctx.action, err = newFindAction(ctx, cliCtx, fctx, encKeyDB)
+	fatalIf(err.Trace(args...), "Unable to parse --retry.")
+	if cliCtx.Bool("delete") && !cliCtx.Bool("dry-run") && !cliCtx.Bool("force") && !confirmFindDelete(args[0]) {
+		return nil
+	}
+
+	// Commands still running in the background are waited for.
+	defer fctx.executor.wait()
+	if fctx.emptyDirs {
+		return doFindEmptyDirs(ctx, fctx)
+	}
+	return doFind(ctx, fctx)
 }
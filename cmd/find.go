@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -253,11 +255,13 @@ func find(ctxCtx context.Context, ctx *findContext, fileContent contentMessage)
 
 	// proceed to either exec, format the output string.
 	if ctx.execCmd != "" {
-		execFind(ctxCtx, ctx.execCmd, fileContent)
+		ctx.executor.exec(fileContent)
 		return
 	}
 	if ctx.printFmt != "" {
 		fileContent.Key = stringsReplace(ctxCtx, ctx.printFmt, fileContent)
+	} else {
+		fileContent.Key = quoteOutput(fileContent.Key)
 	}
 	printMsg(findMessage{fileContent})
 }
@@ -278,8 +282,16 @@ func doFind(ctxCtx context.Context, ctx *findContext) error {
 		WithMetadata:      len(ctx.matchMeta) > 0 || len(ctx.matchTags) > 0,
 	}
 
+	// Folders below --maxdepth are not listed.
+	var contentCh <-chan *ClientContent
+	if ctx.maxDepth > 0 {
+		contentCh = listMaxDepth(globalContext, ctx.targetAlias, ctx.clnt, lstOptions, int(ctx.maxDepth))
+	} else {
+		contentCh = ctx.clnt.List(globalContext, lstOptions)
+	}
+
 	// iterate over all content which is within the given directory
-	for content := range ctx.clnt.List(globalContext, lstOptions) {
+	for content := range filterTierResidency(ctxCtx, ctx.tier, contentCh) {
 		if content.Err != nil {
 			switch content.Err.ToGoError().(type) {
 			// handle this specifically for filesystem related errors.
@@ -299,7 +311,8 @@ func doFind(ctxCtx context.Context, ctx *findContext) error {
 			fatalIf(content.Err.Trace(ctx.clnt.GetURL().String()), "Unable to list folder.")
 			continue
 		}
-		if content.StorageClass == s3StorageClassGlacier {
+		// Archived objects are only found when asked for explicitly.
+		if content.StorageClass == s3StorageClassGlacier && !ctx.tier.enabled() {
 			continue
 		}
 
@@ -318,23 +331,137 @@ func doFind(ctxCtx context.Context, ctx *findContext) error {
 			continue
 		} // For all matching content
 
-		// proceed to either exec, format the output string.
+		// proceed to either act, exec or format the output string.
+		if ctx.action != nil {
+			ctx.action.do(content, fileContent)
+			continue
+		}
 		if ctx.execCmd != "" {
-			execFind(ctxCtx, ctx.execCmd, fileContent)
+			ctx.executor.exec(fileContent)
 			continue
 		}
 		if ctx.printFmt != "" {
 			fileContent.Key = stringsReplace(ctxCtx, ctx.printFmt, fileContent)
+		} else {
+			fileContent.Key = quoteOutput(fileContent.Key)
 		}
 
 		printMsg(findMessage{fileContent})
 	}
 
+	if ctx.action != nil {
+		return ctx.action.wait()
+	}
+
 	// Success, notice watch will execute in defer only if enabled and this call
 	// will return after watch is canceled.
 	return nil
 }
 
+// doFindEmptyDirs - finds zero byte directory markers and folders
+// which hold no objects (only other empty folders), deepest first,
+// removing them if requested.
+func doFindEmptyDirs(ctxCtx context.Context, ctx *findContext) error {
+	clntURL := ctx.clnt.GetURL()
+	separator := string(clntURL.Separator)
+	rootDir := strings.TrimSuffix(clntURL.String(), separator) + separator
+	if clntURL.Type == fileSystem {
+		rootDir = strings.TrimSuffix(filepath.Clean(clntURL.Path), separator) + separator
+	}
+
+	var dirs []*ClientContent
+	nonEmpty := make(map[string]bool)
+	for content := range ctx.clnt.List(ctxCtx, ListOptions{Recursive: true, ShowDir: DirFirst}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(clntURL.String()), "Unable to list folder.")
+			continue
+		}
+		urlStr := content.URL.String()
+		// Local directories always report a size, folders on object
+		// storage are either implied by prefixes or zero byte markers.
+		if content.Type.IsDir() && (content.Size == 0 || clntURL.Type == fileSystem) {
+			// Buckets are never reported, only folders inside them.
+			if isBucket := content.BucketName != "" && strings.TrimSuffix(content.URL.Path, separator) == separator+content.BucketName; !isBucket {
+				dir := strings.TrimSuffix(urlStr, separator) + separator
+				if clntURL.Type == fileSystem {
+					dir = strings.TrimSuffix(filepath.Clean(content.URL.Path), separator) + separator
+				}
+				if dir != rootDir {
+					dirs = append(dirs, content)
+				}
+			}
+			continue
+		}
+		// Every folder above an object is not empty.
+		for dir := strings.TrimSuffix(urlStr, separator); ; {
+			i := strings.LastIndex(dir, separator)
+			if i < 0 {
+				break
+			}
+			dir = dir[:i]
+			if nonEmpty[dir+separator] {
+				break
+			}
+			nonEmpty[dir+separator] = true
+		}
+	}
+
+	// Deepest folders first, so that parents are empty by the time they are removed.
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return strings.Count(dirs[i].URL.String(), separator) > strings.Count(dirs[j].URL.String(), separator)
+	})
+
+	var empty []*ClientContent
+	for _, content := range dirs {
+		if nonEmpty[strings.TrimSuffix(content.URL.String(), separator)+separator] {
+			continue
+		}
+		fileContent := contentMessage{
+			Key:  getAliasedPath(ctx, content.URL.String()),
+			Time: content.Time.Local(),
+			Size: content.Size,
+		}
+		if !matchFind(ctx, fileContent) {
+			continue
+		}
+		if ctx.removeEmptyDirs {
+			empty = append(empty, content)
+			continue
+		}
+		if ctx.execCmd != "" {
+			ctx.executor.exec(fileContent)
+			continue
+		}
+		if ctx.printFmt != "" {
+			fileContent.Key = stringsReplace(ctxCtx, ctx.printFmt, fileContent)
+		} else {
+			fileContent.Key = quoteOutput(fileContent.Key)
+		}
+		printMsg(findMessage{fileContent})
+	}
+
+	if len(empty) == 0 {
+		return nil
+	}
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for _, content := range empty {
+			contentCh <- content
+		}
+	}()
+	var retErr error
+	for result := range ctx.clnt.Remove(ctxCtx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			errorIf(result.Err.Trace(clntURL.String()), "Unable to remove empty folder.")
+			retErr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(rmMessage{Key: path.Join(ctx.targetAlias, result.BucketName, result.ObjectName)})
+	}
+	return retErr
+}
+
 // stringsReplace - formats the string to remove {} and replace each
 // with the appropriate argument
 func stringsReplace(ctx context.Context, args string, fileContent contentMessage) string {
@@ -349,15 +476,24 @@ func stringsReplace(ctx context.Context, args string, fileContent contentMessage
 	// replace all instances of {base}
 	str = strings.ReplaceAll(str, "{base}", filepath.Base(fileContent.Key))
 
+	// replace all instances of {q}
+	str = strings.ReplaceAll(str, `{q}`, shellQuote(fileContent.Key))
+
 	// replace all instances of {"base"}
 	str = strings.ReplaceAll(str, `{"base"}`, strconv.Quote(filepath.Base(fileContent.Key)))
 
+	// replace all instances of {qbase}
+	str = strings.ReplaceAll(str, `{qbase}`, shellQuote(filepath.Base(fileContent.Key)))
+
 	// replace all instances of {dir}
 	str = strings.ReplaceAll(str, "{dir}", filepath.Dir(fileContent.Key))
 
 	// replace all instances of {"dir"}
 	str = strings.ReplaceAll(str, `{"dir"}`, strconv.Quote(filepath.Dir(fileContent.Key)))
 
+	// replace all instances of {qdir}
+	str = strings.ReplaceAll(str, `{qdir}`, shellQuote(filepath.Dir(fileContent.Key)))
+
 	// replace all instances of {size}
 	str = strings.ReplaceAll(str, "{size}", humanize.IBytes(uint64(fileContent.Size)))
 
@@ -380,12 +516,23 @@ func stringsReplace(ctx context.Context, args string, fileContent contentMessage
 		str = strings.ReplaceAll(str, `{"url"}`, strconv.Quote(getShareURL(ctx, fileContent.Key)))
 	}
 
+	// replace all instances of {qurl}
+	if strings.Contains(str, `{qurl}`) {
+		str = strings.ReplaceAll(str, `{qurl}`, shellQuote(getShareURL(ctx, fileContent.Key)))
+	}
+
 	// replace all instances of {version}
 	str = strings.ReplaceAll(str, `{version}`, fileContent.VersionID)
 
 	// replace all instances of {"version"}
 	str = strings.ReplaceAll(str, `{"version"}`, strconv.Quote(fileContent.VersionID))
 
+	// replace all instances of {version-id}
+	str = strings.ReplaceAll(str, `{version-id}`, fileContent.VersionID)
+
+	// replace all instances of {"version-id"}
+	str = strings.ReplaceAll(str, `{"version-id"}`, strconv.Quote(fileContent.VersionID))
+
 	return str
 }
 
@@ -431,6 +578,19 @@ func matchFind(ctx *findContext, fileContent contentMessage) (match bool) {
 	if match && len(ctx.matchTags) > 0 {
 		match = matchRegexMaps(ctx.matchTags, fileContent.Tags)
 	}
+	if match && (ctx.sizeRange != nil || ctx.filter != nil) {
+		content := &ClientContent{
+			URL:          *newClientURL(fileContent.Key),
+			Time:         fileContent.Time,
+			Size:         fileContent.Size,
+			ETag:         fileContent.ETag,
+			StorageClass: fileContent.StorageClass,
+		}
+		if fileContent.Filetype == "folder" {
+			content.Type = os.ModeDir
+		}
+		match = ctx.sizeRange.Match(content) && ctx.filter.Match(content)
+	}
 	return match
 }
 
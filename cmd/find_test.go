@@ -19,12 +19,17 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/minio/minio-go/v7"
 )
 
 // Tests match find function with all supported inputs on
@@ -329,6 +334,135 @@ func TestFindMatch(t *testing.T) {
 	}
 }
 
+// Tests parsing and matching of --filter expressions.
+func TestContentFilter(t *testing.T) {
+	content := &ClientContent{
+		URL:          *newClientURL("/logs/2023/app.log"),
+		Size:         2 << 20,
+		Time:         time.Now().Add(-40 * 24 * time.Hour),
+		StorageClass: "STANDARD",
+		ETag:         `"abc"`,
+	}
+
+	testCases := []struct {
+		filter  string
+		match   bool
+		invalid bool
+	}{
+		{"", true, false},
+		{`size > 1MiB && name ~ "*.log" && age > 30d`, true, false},
+		{"size > 1MiB && age < 30d", false, false},
+		{"size < 1MiB || class == STANDARD", true, false},
+		{`!(path ~ "*/2023/*")`, false, false},
+		{`name !~ '*.txt' && etag == abc`, true, false},
+		{"type == file && time < 2100-01-01", true, false},
+		{"size > 1MiB && (age < 1d || class == GLACIER)", false, false},
+		{"size ~ 1MiB", false, true},
+		{"name > foo", false, true},
+		{"color == red", false, true},
+		{"size > ", false, true},
+		{"(size > 1MiB", false, true},
+		{`name ~ "*.log`, false, true},
+		{"age > forever", false, true},
+		{"size > 1MiB size < 2MiB", false, true},
+		{`size > 1MiB and not path =~ "^/archive/" and (name ~ "*.txt" or name =~ "\.log$")`, true, false},
+		{`NOT (path =~ "/20[0-9]{2}/")`, false, false},
+		{`class =~ "^STANDARD"`, true, false},
+		{`size =~ "1"`, false, true},
+		{`name =~ "(unclosed"`, false, true},
+		{"size > 1MiB and", false, true},
+	}
+
+	for i, testCase := range testCases {
+		filter, err := parseContentFilter(testCase.filter)
+		if testCase.invalid {
+			if err == nil {
+				t.Fatalf("Test %d: expected %q to be rejected", i+1, testCase.filter)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error parsing %q: %v", i+1, testCase.filter, err)
+		}
+		if match := filter.Match(content); match != testCase.match {
+			t.Fatalf("Test %d: expected match %t for %q, got %t", i+1, testCase.match, testCase.filter, match)
+		}
+	}
+}
+
+func TestParseSizeRanges(t *testing.T) {
+	testCases := []struct {
+		ranges  []string
+		size    int64
+		match   bool
+		invalid bool
+	}{
+		{nil, 0, true, false},
+		{[]string{"+1GiB"}, 2 << 30, true, false},
+		{[]string{"+1GiB"}, 1 << 30, false, false},
+		{[]string{"-10KiB"}, 10<<10 - 1, true, false},
+		{[]string{"-10KiB"}, 10 << 10, false, false},
+		{[]string{"512"}, 512, true, false},
+		{[]string{"512"}, 513, false, false},
+		{[]string{"+1KiB", "-1MiB"}, 4096, true, false},
+		{[]string{"+1KiB", "-1MiB"}, 2 << 20, false, false},
+		{[]string{"+"}, 0, false, true},
+		{[]string{"+1KiB", "-lots"}, 0, false, true},
+	}
+
+	for i, testCase := range testCases {
+		filter, err := parseSizeRanges(testCase.ranges)
+		if testCase.invalid {
+			if err == nil {
+				t.Fatalf("Test %d: expected %q to be rejected", i+1, testCase.ranges)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error parsing %q: %v", i+1, testCase.ranges, err)
+		}
+		content := &ClientContent{URL: *newClientURL("/bucket/object"), Size: testCase.size}
+		if match := filter.Match(content); match != testCase.match {
+			t.Fatalf("Test %d: expected match %t for %q and size %d, got %t", i+1, testCase.match, testCase.ranges, testCase.size, match)
+		}
+	}
+}
+
+func TestTierResidencyFilter(t *testing.T) {
+	object := func(class string, restore *minio.RestoreInfo) *ClientContent {
+		return &ClientContent{URL: *newClientURL("/bucket/object"), StorageClass: class, Restore: restore}
+	}
+	testCases := []struct {
+		content      *ClientContent
+		storageClass string
+		residency    string
+		match        bool
+	}{
+		{object("", nil), "", residencyLocal, true},
+		{object("STANDARD", nil), "", residencyRemote, false},
+		{object("WARM-TIER", nil), "", residencyRemote, true},
+		{object("WARM-TIER", nil), "warm-*", "", true},
+		{object("WARM-TIER", nil), "COLD-*", "", false},
+		{object("GLACIER", &minio.RestoreInfo{OngoingRestore: true}), "", residencyRestoring, true},
+		{object("GLACIER", &minio.RestoreInfo{ExpiryTime: time.Now().Add(time.Hour)}), "GLACIER", residencyRestored, true},
+		// An expired restore leaves the object remote.
+		{object("GLACIER", &minio.RestoreInfo{ExpiryTime: time.Now().Add(-time.Hour)}), "", residencyRemote, true},
+	}
+	for i, testCase := range testCases {
+		f, err := parseTierResidencyFilter(testCase.storageClass, testCase.residency)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error: %v", i+1, err)
+		}
+		if match := f.match(testCase.content); match != testCase.match {
+			t.Fatalf("Test %d: expected match %t, got %t", i+1, testCase.match, match)
+		}
+	}
+
+	if _, err := parseTierResidencyFilter("", "frozen"); err == nil {
+		t.Fatal("expected an unknown tier residency to be rejected")
+	}
+}
+
 // Tests string substitution function.
 func TestStringReplace(t *testing.T) {
 	testCases := []struct {
@@ -378,6 +512,12 @@ func TestStringReplace(t *testing.T) {
 			expectedStr: `"0 B"`,
 			content:     contentMessage{Size: 0},
 		},
+		// Tests string replace {version-id}
+		{
+			str:         `{version-id}`,
+			expectedStr: `v1`,
+			content:     contentMessage{VersionID: "v1"},
+		},
 		// Tests string replace {"time"} with quotes.
 		{
 			str:         `{"time"}`,
@@ -400,6 +540,24 @@ func TestStringReplace(t *testing.T) {
 				Time: time.Unix(2147483647, 0).UTC(),
 			},
 		},
+		// Tests string replace {q} with shell escaping.
+		{
+			str:         `{q}`,
+			expectedStr: `'my dir/it'\''s 1'`,
+			content:     contentMessage{Key: "my dir/it's 1"},
+		},
+		// Tests string replace {qbase} with shell escaping.
+		{
+			str:         `{qbase}`,
+			expectedStr: `'it'\''s 1'`,
+			content:     contentMessage{Key: "my dir/it's 1"},
+		},
+		// Tests string replace {qdir} with shell escaping.
+		{
+			str:         `{qdir}`,
+			expectedStr: `'my dir'`,
+			content:     contentMessage{Key: "my dir/it's 1"},
+		},
 	}
 	for i, testCase := range testCases {
 		gotStr := stringsReplace(context.Background(), testCase.str, testCase.content)
@@ -448,3 +606,190 @@ func TestGetExitStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckEmptyDirsSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket"}, true},
+		{[]string{"--empty-dirs", "s3/bucket"}, true},
+		{[]string{"--empty-dirs", "--remove", "s3/bucket"}, true},
+		{[]string{"--empty-dirs", "--name", "*.tmp", "s3/bucket"}, true},
+		{[]string{"--remove", "s3/bucket"}, false},
+		{[]string{"--empty-dirs", "--watch", "s3/bucket"}, false},
+		{[]string{"--empty-dirs", "--versions", "s3/bucket"}, false},
+		{[]string{"--empty-dirs", "--storage-class", "GLACIER", "s3/bucket"}, false},
+		{[]string{"--empty-dirs", "--tier", "remote", "s3/bucket"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkEmptyDirsSyntax(newTestCLIContext(t, findFlags, testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestCheckExecWorkersSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket"}, true},
+		{[]string{"--exec", "true", "s3/bucket"}, true},
+		{[]string{"--exec", "true", "--exec-workers", "8", "s3/bucket"}, true},
+		{[]string{"--exec-workers", "8", "s3/bucket"}, false},
+		{[]string{"--exec", "true", "--exec-workers", "0", "s3/bucket"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkExecWorkersSyntax(newTestCLIContext(t, append(findFlags, execWorkersFlag), testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestFindExecutor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping on windows")
+	}
+	for _, workers := range []int{1, 4} {
+		dir := t.TempDir()
+		executor := newFindExecutor(context.Background(), "touch "+dir+"/{base}.{version-id}", workers)
+		for i := 0; i < 20; i++ {
+			executor.exec(contentMessage{Key: "s3/bucket/object" + strconv.Itoa(i), VersionID: "v1"})
+		}
+		executor.wait()
+		for i := 0; i < 20; i++ {
+			if _, e := os.Stat(filepath.Join(dir, "object"+strconv.Itoa(i)+".v1")); e != nil {
+				t.Errorf("%d workers: expected the command of object%d to have run: %v", workers, i, e)
+			}
+		}
+	}
+}
+
+func TestCheckFindActionSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket"}, true},
+		{[]string{"--delete", "s3/bucket"}, true},
+		{[]string{"--delete", "--dry-run", "--force", "s3/bucket"}, true},
+		{[]string{"--copy-to", "backup/bucket", "--retry", "3", "s3/bucket"}, true},
+		{[]string{"--delete", "--copy-to", "backup/bucket", "s3/bucket"}, false},
+		{[]string{"--dry-run", "s3/bucket"}, false},
+		{[]string{"--force", "s3/bucket"}, false},
+		{[]string{"--delete", "--exec", "true", "s3/bucket"}, false},
+		{[]string{"--copy-to", "backup/bucket", "--print", "{}", "s3/bucket"}, false},
+		{[]string{"--delete", "--watch", "s3/bucket"}, false},
+		{[]string{"--delete", "--empty-dirs", "s3/bucket"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkFindActionSyntax(newTestCLIContext(t, joinFlags(findFlags, findActionFlags), testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestFindActions(t *testing.T) {
+	root, backup := t.TempDir(), t.TempDir()
+	for _, file := range []string{"a.log", "b.txt", "dir/c.log"} {
+		fpath := filepath.Join(root, file)
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(file), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	exists := func(fpath string) bool {
+		_, e := os.Stat(fpath)
+		return e == nil
+	}
+
+	testCases := []struct {
+		args     []string
+		expected map[string]bool
+	}{
+		// Nothing is copied or removed by a dry run.
+		{[]string{"--copy-to", backup, "--dry-run"}, map[string]bool{"dir/c.log": false}},
+		{[]string{"--delete", "--dry-run"}, map[string]bool{"a.log": true, "dir/c.log": true}},
+		// Objects found are copied keeping their path below the searched path.
+		{[]string{"--copy-to", backup}, map[string]bool{"a.log": true, "dir/c.log": true, "b.txt": false}},
+		// Objects found are removed, the others are kept.
+		{[]string{"--delete", "--force"}, map[string]bool{"a.log": false, "dir/c.log": false, "b.txt": true}},
+	}
+	for i, testCase := range testCases {
+		clnt, err := fsNew(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cliCtx := newTestCLIContext(t, joinFlags(findFlags, findActionFlags), append(testCase.args, root)...)
+		ctx := &findContext{Context: cliCtx, clnt: clnt, targetURL: root, namePattern: "*.log"}
+		ctx.action, err = newFindAction(context.Background(), cliCtx, ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := doFind(context.Background(), ctx); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		dir := root
+		if cliCtx.String("copy-to") != "" {
+			dir = backup
+		}
+		for file, expected := range testCase.expected {
+			if got := exists(filepath.Join(dir, file)); got != expected {
+				t.Errorf("Test %d: expected %s to exist %t, got %t", i+1, file, expected, got)
+			}
+		}
+	}
+}
+
+func TestFindEmptyDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"a/b", "c/d", "e"} {
+		if e := os.MkdirAll(filepath.Join(root, dir), 0o755); e != nil {
+			t.Fatal(e)
+		}
+	}
+	for _, file := range []string{"keep.txt", "c/object"} {
+		if e := os.WriteFile(filepath.Join(root, file), []byte("data"), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	exists := func(dir string) bool {
+		_, e := os.Stat(filepath.Join(root, dir))
+		return e == nil
+	}
+
+	testCases := []struct {
+		remove   bool
+		expected map[string]bool
+	}{
+		// Without --remove empty folders are only listed.
+		{false, map[string]bool{"a": true, "a/b": true, "c": true, "c/d": true, "e": true}},
+		// Folders holding only empty folders are removed deepest first,
+		// folders holding objects are kept.
+		{true, map[string]bool{"a": false, "a/b": false, "c": true, "c/d": false, "e": false}},
+	}
+	for i, testCase := range testCases {
+		clnt, err := fsNew(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx := &findContext{clnt: clnt, targetURL: root, emptyDirs: true, removeEmptyDirs: testCase.remove}
+		if e := doFindEmptyDirs(context.Background(), ctx); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		for dir, expected := range testCase.expected {
+			if exists(dir) != expected {
+				t.Errorf("Test %d: expected %s to exist %t", i+1, dir, expected)
+			}
+		}
+		if !exists("keep.txt") || !exists("c/object") {
+			t.Fatalf("Test %d: objects were removed", i+1)
+		}
+	}
+}
@@ -18,11 +18,23 @@
 package cmd
 
 import (
+	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
 )
 
+// joinFlags returns the flags of several sets in their order.
+func joinFlags(flagSets ...[]cli.Flag) []cli.Flag {
+	var flags []cli.Flag
+	for _, set := range flagSets {
+		flags = append(flags, set...)
+	}
+	return flags
+}
+
 // Collection of mc flags currently supported
 var globalFlags = []cli.Flag{
 	cli.StringFlag{
@@ -42,21 +54,45 @@ var globalFlags = []cli.Flag{
 		Name:  "json",
 		Usage: "enable JSON lines formatted output",
 	},
+	cli.StringFlag{
+		Name:  "format-template",
+		Usage: "print each result through a Go template of the fields printed by --json, e.g. '{{.Key}}\\t{{.Size}}'",
+	},
 	cli.BoolFlag{
 		Name:  "debug",
 		Usage: "enable debug output",
 	},
+	cli.BoolFlag{
+		Name:  "debug-lite",
+		Usage: "print the TLS version, cipher and certificates of every endpoint, without dumping traffic",
+	},
 	cli.BoolFlag{
 		Name:  "insecure",
 		Usage: "disable SSL certificate verification",
 	},
 	cli.StringFlag{
 		Name:  "limit-upload",
-		Usage: "limits uploads to a maximum rate in KiB/s, MiB/s, GiB/s. (default: unlimited)",
+		Usage: "limits uploads of all concurrent transfers to a maximum rate in KiB/s, MiB/s, GiB/s, e.g. 10MiB/s (default: unlimited)",
 	},
 	cli.StringFlag{
 		Name:  "limit-download",
-		Usage: "limits downloads to a maximum rate in KiB/s, MiB/s, GiB/s. (default: unlimited)",
+		Usage: "limits downloads of all concurrent transfers to a maximum rate in KiB/s, MiB/s, GiB/s, e.g. 10MiB/s (default: unlimited)",
+	},
+	cli.StringFlag{
+		Name:  "ops-limit",
+		Usage: "limits the requests of all concurrent operations, such as removals and listings, to a maximum rate per second, e.g. 500/s (default: unlimited)",
+	},
+	cli.StringSliceFlag{
+		Name:  "header",
+		Usage: "add a custom header to all S3 requests, e.g. 'x-tenant: acme' (repeatable)",
+	},
+	cli.StringSliceFlag{
+		Name:  "query",
+		Usage: "add a custom query parameter to all S3 requests, e.g. 'k=v' (repeatable)",
+	},
+	cli.StringFlag{
+		Name:  "output-target",
+		Usage: "also deliver the JSON results to an object, e.g. 'myminio/reports/run-{date}.json', or POST them to a webhook URL",
 	},
 	cli.DurationFlag{
 		Name:   "conn-read-deadline",
@@ -72,10 +108,108 @@ var globalFlags = []cli.Flag{
 	},
 }
 
+// excludeFlags returns the flags without those of the given names, for
+// commands defining a flag of the same name with a different meaning.
+func excludeFlags(flags []cli.Flag, names ...string) []cli.Flag {
+	filtered := make([]cli.Flag, 0, len(flags))
+	for _, flag := range flags {
+		var excluded bool
+		for _, name := range names {
+			excluded = excluded || flag.GetName() == name
+		}
+		if !excluded {
+			filtered = append(filtered, flag)
+		}
+	}
+	return filtered
+}
+
 // Flags common across all I/O commands such as cp, mirror, stat, pipe etc.
 var ioFlags = []cli.Flag{
 	cli.StringFlag{
 		Name:  "encrypt-key",
 		Usage: "encrypt/decrypt objects (using server-side encryption with customer provided keys)",
 	},
+	requestPayerFlag,
+}
+
+// SSE-KMS flag, common across the commands writing objects.
+var encKMSFlag = cli.StringFlag{
+	Name:  "enc-kms",
+	Usage: "encrypt objects (using server-side encryption with KMS managed keys), as comma delimited prefix=keyid values",
+}
+
+// Requester Pays flag, common across all commands reading from S3 buckets.
+var requestPayerFlag = cli.StringFlag{
+	Name:  "request-payer",
+	Usage: "confirm paying for requests to Requester Pays buckets, valid option is '[requester]'",
+}
+
+// Flags common across commands downloading objects such as cat and cp.
+var getConditionFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "if-match",
+		Usage: "only read objects whose ETag matches",
+	},
+	cli.StringFlag{
+		Name:  "if-none-match",
+		Usage: "only read objects whose ETag does not match",
+	},
+	cli.StringFlag{
+		Name:  "if-modified-since",
+		Usage: "only read objects modified after this date or duration",
+	},
+	cli.StringFlag{
+		Name:  "if-unmodified-since",
+		Usage: "only read objects not modified after this date or duration",
+	},
+}
+
+// parseGetConditions validates the conditional GET flags.
+func parseGetConditions(cliCtx *cli.Context) (GetConditions, *probe.Error) {
+	conditions := GetConditions{
+		IfMatch:     cliCtx.String("if-match"),
+		IfNoneMatch: cliCtx.String("if-none-match"),
+	}
+	var err *probe.Error
+	if v := cliCtx.String("if-modified-since"); v != "" {
+		if conditions.IfModifiedSince, err = parseConditionTime(v); err != nil {
+			return conditions, err.Trace("if-modified-since", v)
+		}
+	}
+	if v := cliCtx.String("if-unmodified-since"); v != "" {
+		if conditions.IfUnmodifiedSince, err = parseConditionTime(v); err != nil {
+			return conditions, err.Trace("if-unmodified-since", v)
+		}
+	}
+	return conditions, nil
+}
+
+// parseConditionTime accepts the --rewind date formats, HTTP dates
+// and durations which are counted back from now.
+func parseConditionTime(value string) (time.Time, *probe.Error) {
+	for _, format := range rewindSupportedFormat {
+		if t, e := time.ParseInLocation(format, value, time.Local); e == nil {
+			return t, nil
+		}
+	}
+	if t, e := time.Parse(http.TimeFormat, value); e == nil {
+		return t, nil
+	}
+	if duration, e := ParseDuration(value); e == nil && duration >= 0 {
+		return time.Now().Add(-time.Duration(duration)), nil
+	}
+	return time.Time{}, probe.NewError(fmt.Errorf("unknown date or duration `%s`", value))
+}
+
+// Flags common across commands printing object keys or paths such as ls, find, du etc.
+var keyOutputFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "raw",
+		Usage: "print keys and paths verbatim, without shell quoting",
+	},
+	cli.BoolFlag{
+		Name:  "print0",
+		Usage: "terminate each printed entry with a NUL character instead of a newline",
+	},
 }
@@ -21,7 +21,12 @@ package cmd
 import (
 	"context"
 	"crypto/x509"
+	"fmt"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/dustin/go-humanize"
@@ -37,6 +42,24 @@ const (
 	globalMCCertsDir   = "certs"
 	globalMCCAsDir     = "CAs"
 
+	// bucket region cache file, populated by automatic region discovery.
+	globalMCRegionCacheFile = "regions.json"
+
+	// bucket and directory lookup cache file, used if MC_STAT_CACHE_TTL is set.
+	globalMCStatCacheFile = "stat-cache.json"
+
+	// request accounting per alias, used if MC_ALIAS_STATS contains 'save'.
+	globalMCAliasStatsFile = "alias-stats.json"
+
+	// contexts set with 'mc context set' and the active one.
+	globalMCContextsFile = "contexts.json"
+
+	// S3 features probed per alias by 'mc features'.
+	globalMCFeaturesFile = "features.json"
+
+	// objects synchronized by 'mirror --two-way', per source and target.
+	globalMCMirrorStateDir = "mirror-state"
+
 	// session config and shared urls related constants
 	globalSessionDir           = "session"
 	globalSharedURLsDataDir    = "share"
@@ -63,18 +86,28 @@ var (
 	globalJSON           = false               // Json flag set via command line
 	globalJSONLine       = false               // Print json as single line.
 	globalDebug          = false               // Debug flag set via command line
+	globalDebugLite      = false               // Debug lite flag set via command line
 	globalNoColor        = false               // No Color flag set via command line
 	globalInsecure       = false               // Insecure flag set via command line
 	globalDevMode        = false               // dev flag set via command line
 	globalAirgapped      = false               // Airgapped flag set via command line
 	globalSubnetProxyURL *url.URL              // Proxy to be used for communication with subnet
 	globalSubnetConfig   []madmin.SubsysConfig // Subnet config
+	globalRawOutput      = false               // Raw flag set via command line
+	globalPrint0         = false               // Print0 flag set via command line
+	globalFormatTemplate *template.Template    // Template set via --format-template
 
 	globalConnReadDeadline  time.Duration
 	globalConnWriteDeadline time.Duration
 
 	globalLimitUpload   uint64
 	globalLimitDownload uint64
+	globalOpsLimit      uint64
+
+	globalRequestPayer string
+
+	globalCustomHeaders http.Header
+	globalCustomQuery   url.Values
 
 	globalContext, globalCancel = context.WithCancel(context.Background())
 )
@@ -93,26 +126,54 @@ var (
 func setGlobalsFromContext(ctx *cli.Context) error {
 	quiet := ctx.IsSet("quiet") || ctx.GlobalIsSet("quiet")
 	debug := ctx.IsSet("debug") || ctx.GlobalIsSet("debug")
+	debugLite := ctx.IsSet("debug-lite") || ctx.GlobalIsSet("debug-lite")
 	json := ctx.IsSet("json") || ctx.GlobalIsSet("json")
 	noColor := ctx.IsSet("no-color") || ctx.GlobalIsSet("no-color")
 	insecure := ctx.IsSet("insecure") || ctx.GlobalIsSet("insecure")
 	devMode := ctx.IsSet("dev") || ctx.GlobalIsSet("dev")
 	airgapped := ctx.IsSet("airgap") || ctx.GlobalIsSet("airgap")
+	rawOutput := ctx.IsSet("raw") || ctx.GlobalIsSet("raw")
+	print0 := ctx.IsSet("print0") || ctx.GlobalIsSet("print0")
+
+	// The output preferences of the active context apply to all commands.
+	if c := getActiveContext(); c != nil {
+		quiet = quiet || c.Quiet
+		json = json || c.JSON
+		noColor = noColor || c.NoColor
+	}
 
 	globalQuiet = globalQuiet || quiet
 	globalDebug = globalDebug || debug
+	globalDebugLite = globalDebugLite || debugLite
 	globalJSONLine = !isTerminal() && json
 	globalJSON = globalJSON || json
 	globalNoColor = globalNoColor || noColor || globalJSONLine
 	globalInsecure = globalInsecure || insecure
 	globalDevMode = globalDevMode || devMode
 	globalAirgapped = globalAirgapped || airgapped
+	globalRawOutput = globalRawOutput || rawOutput
+	globalPrint0 = globalPrint0 || print0
 
 	// Disable colorified messages if requested.
 	if globalNoColor || globalQuiet {
 		console.SetColorOff()
 	}
 
+	formatTemplate := ctx.String("format-template")
+	if formatTemplate == "" {
+		formatTemplate = ctx.GlobalString("format-template")
+	}
+	if formatTemplate != "" {
+		if globalJSON {
+			return fmt.Errorf("--format-template cannot be used with --json")
+		}
+		var e error
+		globalFormatTemplate, e = parseFormatTemplate(formatTemplate)
+		if e != nil {
+			return e
+		}
+	}
+
 	globalConnReadDeadline = ctx.Duration("conn-read-deadline")
 	if globalConnReadDeadline <= 0 {
 		globalConnReadDeadline = ctx.GlobalDuration("conn-read-deadline")
@@ -129,7 +190,19 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 	}
 	if limitUploadStr != "" {
 		var e error
-		globalLimitUpload, e = humanize.ParseBytes(limitUploadStr)
+		globalLimitUpload, e = parseRateLimit(limitUploadStr)
+		if e != nil {
+			return e
+		}
+	}
+
+	opsLimitStr := ctx.String("ops-limit")
+	if opsLimitStr == "" {
+		opsLimitStr = ctx.GlobalString("ops-limit")
+	}
+	if opsLimitStr != "" {
+		var e error
+		globalOpsLimit, e = parseOpsLimit(opsLimitStr)
 		if e != nil {
 			return e
 		}
@@ -142,11 +215,80 @@ func setGlobalsFromContext(ctx *cli.Context) error {
 
 	if limitDownloadStr != "" {
 		var e error
-		globalLimitDownload, e = humanize.ParseBytes(limitDownloadStr)
+		globalLimitDownload, e = parseRateLimit(limitDownloadStr)
 		if e != nil {
 			return e
 		}
 	}
 
+	requestPayer := ctx.String("request-payer")
+	if requestPayer == "" {
+		requestPayer = ctx.GlobalString("request-payer")
+	}
+	if requestPayer != "" {
+		if !isValidRequestPayer(requestPayer) {
+			return fmt.Errorf("invalid request payer `%s`, valid option is `requester`", requestPayer)
+		}
+		globalRequestPayer = strings.ToLower(requestPayer)
+	}
+
+	outputTarget := ctx.String("output-target")
+	if outputTarget == "" {
+		outputTarget = ctx.GlobalString("output-target")
+	}
+	if outputTarget != "" && globalOutputTarget == nil {
+		var e error
+		if globalOutputTarget, e = newOutputTarget(outputTarget, time.Now()); e != nil {
+			return e
+		}
+	}
+
+	customHeaders := ctx.StringSlice("header")
+	if len(customHeaders) == 0 {
+		customHeaders = ctx.GlobalStringSlice("header")
+	}
+	headers, e := parseCustomHeaders(customHeaders)
+	if e != nil {
+		return e
+	}
+	if headers != nil {
+		globalCustomHeaders = headers
+	}
+
+	// 'mc sql' has its own --query flag, there custom query parameters
+	// can only be given before the command name.
+	customQuery := ctx.GlobalStringSlice("query")
+	if ctx.Command.Name != "sql" && len(ctx.StringSlice("query")) > 0 {
+		customQuery = ctx.StringSlice("query")
+	}
+	query, e := parseCustomQuery(customQuery)
+	if e != nil {
+		return e
+	}
+	if query != nil {
+		globalCustomQuery = query
+	}
+
 	return nil
 }
+
+// parseRateLimit parses a --limit-upload or --limit-download rate, in
+// bytes per second with an optional '/s' suffix, e.g. '10MiB/s'.
+func parseRateLimit(rate string) (uint64, error) {
+	rate = strings.TrimSpace(rate)
+	if strings.HasSuffix(strings.ToLower(rate), "/s") {
+		rate = rate[:len(rate)-2]
+	}
+	return humanize.ParseBytes(rate)
+}
+
+// parseOpsLimit parses an --ops-limit rate, in requests per second
+// with an optional '/s' suffix, e.g. '500/s'.
+func parseOpsLimit(rate string) (uint64, error) {
+	rate = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(rate)), "/s")
+	ops, e := strconv.ParseUint(rate, 10, 64)
+	if e != nil {
+		return 0, fmt.Errorf("invalid --ops-limit `%s`, expected a number of requests per second", rate)
+	}
+	return ops, nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestParseRateLimit(t *testing.T) {
+	testCases := []struct {
+		rate    string
+		limit   uint64
+		success bool
+	}{
+		{"10MiB/s", 10 << 20, true},
+		{"10MiB", 10 << 20, true},
+		{"512KiB/S", 512 << 10, true},
+		{"1000", 1000, true},
+		{"fast/s", 0, false},
+	}
+	for i, testCase := range testCases {
+		limit, e := parseRateLimit(testCase.rate)
+		if (e == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got error %v", i+1, testCase.success, e)
+		}
+		if limit != testCase.limit {
+			t.Fatalf("Test %d: expected %d, got %d", i+1, testCase.limit, limit)
+		}
+	}
+}
+
+func TestParseOpsLimit(t *testing.T) {
+	testCases := []struct {
+		rate    string
+		limit   uint64
+		success bool
+	}{
+		{"500/s", 500, true},
+		{"500", 500, true},
+		{" 20/S ", 20, true},
+		{"1k/s", 0, false},
+		{"-1", 0, false},
+	}
+	for i, testCase := range testCases {
+		limit, e := parseOpsLimit(testCase.rate)
+		if (e == nil) != testCase.success {
+			t.Fatalf("Test %d: expected success %t, got error %v", i+1, testCase.success, e)
+		}
+		if limit != testCase.limit {
+			t.Fatalf("Test %d: expected %d, got %d", i+1, testCase.limit, limit)
+		}
+	}
+}
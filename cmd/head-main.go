@@ -22,6 +22,7 @@ import (
 	"compress/bzip2"
 	"compress/gzip"
 	"context"
+	"errors"
 	"io"
 	"os"
 	"strings"
@@ -38,6 +39,10 @@ var headFlags = []cli.Flag{
 		Usage: "print the first 'n' lines",
 		Value: 10,
 	},
+	cli.Int64Flag{
+		Name:  "c,bytes",
+		Usage: "print the first 'c' bytes instead of lines",
+	},
 	cli.StringFlag{
 		Name:  "rewind",
 		Usage: "select an object version at specified time",
@@ -50,6 +55,7 @@ var headFlags = []cli.Flag{
 		Name:  "zip",
 		Usage: "extract from remote zip file (MinIO server source only)",
 	},
+	prettyTableFlag,
 }
 
 // Display contents of a file.
@@ -88,12 +94,19 @@ EXAMPLES:
 
   4. Display the first lines of a specific object version.
      {{.Prompt}} {{.HelpName}} --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" s3/json-data/population.json
+
+  5. Display the first 20 records of a CSV, JSON lines or Parquet object as a table, with the column names inferred from the data.
+     {{.Prompt}} {{.HelpName}} -n 20 --pretty-table s3/data-lake/2023/trips.parquet
+
+  6. Display the first 512 bytes of an object, decompressed if it is compressed.
+     {{.Prompt}} {{.HelpName}} -c 512 s3/logs/2023-05-01.log.gz | xxd
 `,
 }
 
 // headURL displays contents of a URL to stdout.
-func headURL(sourceURL, sourceVersion string, timeRef time.Time, encKeyDB map[string][]prefixSSEPair, nlines int64, zip bool) *probe.Error {
+func headURL(sourceURL, sourceVersion string, timeRef time.Time, encKeyDB encryptionConfig, nlines, nbytes int64, zip, prettyTable bool) *probe.Error {
 	var reader io.ReadCloser
+	var format string
 	switch sourceURL {
 	case "-":
 		reader = os.Stdin
@@ -104,6 +117,13 @@ func headURL(sourceURL, sourceVersion string, timeRef time.Time, encKeyDB map[st
 			return err.Trace(sourceURL)
 		}
 		ctype := metadata["Content-Type"]
+		if format = tableFormatOf(sourceURL, ctype); prettyTable && format == tableFormatParquet {
+			reader.Close()
+			if sourceVersion != "" || !timeRef.IsZero() {
+				return probe.NewError(errors.New("Parquet objects can only be previewed at their latest version")).Trace(sourceURL)
+			}
+			return previewParquetTable(context.Background(), sourceURL, encKeyDB, nlines).Trace(sourceURL)
+		}
 		if strings.Contains(ctype, "gzip") {
 			var e error
 			reader, e = gzip.NewReader(reader)
@@ -118,9 +138,28 @@ func headURL(sourceURL, sourceVersion string, timeRef time.Time, encKeyDB map[st
 			defer reader.Close()
 		}
 	}
+	if prettyTable {
+		return headTableOut(reader, format, nlines).Trace(sourceURL)
+	}
+	if nbytes > 0 {
+		return catOut(io.LimitReader(reader, nbytes), -1).Trace(sourceURL)
+	}
 	return headOut(reader, nlines).Trace(sourceURL)
 }
 
+// headTableOut displays the first records of tabular data as a table,
+// other content is displayed as is.
+func headTableOut(r io.Reader, format string, nrecords int64) *probe.Error {
+	if nrecords < 0 {
+		nrecords = 10
+	}
+	br := bufio.NewReader(r)
+	if ok, err := previewTable(br, format, nrecords); ok || err != nil {
+		return err
+	}
+	return headOut(br, nrecords)
+}
+
 // headOut reads from reader stream and writes to stdout. Also check the length of the
 // read bytes against size parameter (if not -1) and return the appropriate error
 func headOut(r io.Reader, nlines int64) *probe.Error {
@@ -180,6 +219,13 @@ func parseHeadSyntax(ctx *cli.Context) (args []string, versionID string, timeRef
 		fatalIf(errInvalidArgument().Trace(), "You need to pass at least one argument if --version-id is specified")
 	}
 
+	if ctx.Int64("bytes") < 0 {
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --bytes")
+	}
+	if ctx.IsSet("bytes") && (ctx.IsSet("lines") || ctx.Bool("pretty-table")) {
+		fatalIf(errInvalidArgument().Trace(), "You cannot combine --bytes with --lines or --pretty-table")
+	}
+
 	timeRef = parseRewindFlag(rewind)
 	return
 }
@@ -191,18 +237,27 @@ func mainHead(ctx *cli.Context) error {
 	fatalIf(err, "Unable to parse encryption keys.")
 
 	args, versionID, timeRef := parseHeadSyntax(ctx)
+	setTablePreviewColors()
 
 	stdinMode := len(args) == 0
 
 	// handle std input data.
 	if stdinMode {
+		if ctx.Bool("pretty-table") {
+			fatalIf(headTableOut(os.Stdin, "", ctx.Int64("lines")).Trace(), "Unable to read from standard input.")
+			return nil
+		}
+		if nbytes := ctx.Int64("bytes"); nbytes > 0 {
+			fatalIf(catOut(io.LimitReader(os.Stdin, nbytes), -1).Trace(), "Unable to read from standard input.")
+			return nil
+		}
 		fatalIf(headOut(os.Stdin, ctx.Int64("lines")).Trace(), "Unable to read from standard input.")
 		return nil
 	}
 
 	// Convert arguments to URLs: expand alias, fix format.
 	for _, url := range ctx.Args() {
-		fatalIf(headURL(url, versionID, timeRef, encKeyDB, ctx.Int64("lines"), ctx.Bool("zip")).Trace(url), "Unable to read from `"+url+"`.")
+		fatalIf(headURL(url, versionID, timeRef, encKeyDB, ctx.Int64("lines"), ctx.Int64("bytes"), ctx.Bool("zip"), ctx.Bool("pretty-table")).Trace(url), "Unable to read from `"+url+"`.")
 	}
 
 	return nil
@@ -0,0 +1,109 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// headerMapRule sets headers on all objects whose name matches pattern.
+type headerMapRule struct {
+	pattern string
+	headers map[string]string
+}
+
+// headerMap is an ordered list of rules, headers of later rules
+// override those of earlier rules matching the same object.
+type headerMap []headerMapRule
+
+// loadHeaderMap reads a header mapping file, an empty filename
+// returns an empty mapping.
+func loadHeaderMap(filename string) (headerMap, *probe.Error) {
+	if filename == "" {
+		return nil, nil
+	}
+	f, e := os.Open(filename)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(filename)
+	}
+	defer f.Close()
+	m, err := parseHeaderMap(f)
+	if err != nil {
+		return nil, err.Trace(filename)
+	}
+	return m, nil
+}
+
+// parseHeaderMap parses one rule per line of the form
+//
+//	PATTERN KEY=VALUE[;KEY=VALUE...]
+//
+// where PATTERN is a shell pattern matched against the object name, and
+// the headers use the syntax of --attr. Empty lines and lines starting
+// with '#' are ignored.
+func parseHeaderMap(r io.Reader) (headerMap, *probe.Error) {
+	var m headerMap
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern, headers, ok := strings.Cut(line, " ")
+		if !ok {
+			pattern, headers, ok = strings.Cut(line, "\t")
+		}
+		if !ok || strings.TrimSpace(headers) == "" {
+			return nil, probe.NewError(fmt.Errorf("line %d: expected a pattern followed by headers", lineNum))
+		}
+		if _, e := path.Match(pattern, ""); e != nil {
+			return nil, probe.NewError(fmt.Errorf("line %d: invalid pattern `%s`: %w", lineNum, pattern, e))
+		}
+		values, err := getMetaDataEntry(strings.TrimSpace(headers))
+		if err != nil {
+			return nil, err.Trace(fmt.Sprintf("line %d", lineNum))
+		}
+		m = append(m, headerMapRule{pattern: pattern, headers: values})
+	}
+	if e := scanner.Err(); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return m, nil
+}
+
+// headersFor returns the headers of all rules matching the object name.
+func (m headerMap) headersFor(objectPath string) map[string]string {
+	name := path.Base(strings.ReplaceAll(objectPath, "\\", "/"))
+	headers := make(map[string]string)
+	for _, rule := range m {
+		if matched, _ := path.Match(rule.pattern, name); !matched {
+			continue
+		}
+		for k, v := range rule.headers {
+			headers[k] = v
+		}
+	}
+	return headers
+}
@@ -156,7 +156,7 @@ func sendRestoreRequests(ctx context.Context, targetAlias, targetURL, targetVers
 }
 
 // Wait until an object which receives restore request is completely restored in the fast tier
-func waitRestoreObject(ctx context.Context, targetAlias, targetURL, versionID string, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+func waitRestoreObject(ctx context.Context, targetAlias, targetURL, versionID string, encKeyDB encryptionConfig) *probe.Error {
 	clnt, err := newClientFromAlias(targetAlias, targetURL)
 	if err != nil {
 		return err
@@ -183,7 +183,7 @@ func waitRestoreObject(ctx context.Context, targetAlias, targetURL, versionID st
 }
 
 // Check and wait the restore status of one or more objects one by one.
-func checkRestoreStatus(ctx context.Context, targetAlias, targetURL, targetVersionID string, recursive, applyOnVersions bool, encKeyDB map[string][]prefixSSEPair, restoreStatus chan *probe.Error) {
+func checkRestoreStatus(ctx context.Context, targetAlias, targetURL, targetVersionID string, recursive, applyOnVersions bool, encKeyDB encryptionConfig, restoreStatus chan *probe.Error) {
 	defer close(restoreStatus)
 
 	client, err := newClientFromAlias(targetAlias, targetURL)
@@ -0,0 +1,354 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// keycheck specific flags.
+var (
+	keycheckFlags = []cli.Flag{
+		cli.BoolFlag{
+			Name:  "fix",
+			Usage: "rename problematic keys with a server side copy",
+		},
+		cli.StringFlag{
+			Name:  "replacement",
+			Usage: "replace unsafe characters with this string when renaming",
+			Value: "_",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "show the new names without renaming anything",
+		},
+	}
+)
+
+// Find objects with key names which break downstream tools.
+var keycheckCmd = cli.Command{
+	Name:         "keycheck",
+	Usage:        "find and fix objects with invalid or problematic key names",
+	Action:       mainKeycheck,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(keycheckFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Keycheck lists all objects under TARGET recursively and flags keys which contain control
+  characters, invalid UTF-8, characters or sequences which cannot be represented in XML,
+  path segments with leading or trailing spaces, trailing dots, or segments which are
+  empty, '.' or '..'. Such keys are accepted by S3 but break listings, filesystems and
+  most downstream tools.
+
+  With --fix, every flagged object is copied server side to a sanitized key and the
+  original is removed. Objects whose sanitized key already exists are left untouched.
+
+  Exits with a non-zero status if problematic keys are left in place.
+
+EXAMPLES:
+  1. Find objects with problematic key names in a bucket.
+     {{.Prompt}} {{.HelpName}} myminio/mybucket
+
+  2. Show how the problematic keys of a bucket would be renamed.
+     {{.Prompt}} {{.HelpName}} --fix --dry-run myminio/mybucket
+
+  3. Rename problematic keys, replacing unsafe characters with '-'.
+     {{.Prompt}} {{.HelpName}} --fix --replacement "-" myminio/mybucket/uploads/
+`,
+}
+
+// keycheckMessage container for a problematic key.
+type keycheckMessage struct {
+	Status   string   `json:"status"`
+	Key      string   `json:"key"`
+	Problems []string `json:"problems"`
+	NewKey   string   `json:"newKey,omitempty"`
+	Renamed  bool     `json:"renamed"`
+	dryRun   bool
+}
+
+// String colorized keycheck message.
+func (k keycheckMessage) String() string {
+	problems := console.Colorize("KeyProblem", strings.Join(k.Problems, ", "))
+	switch {
+	case k.Renamed:
+		return fmt.Sprintf("Renamed %s to %s (%s)", console.Colorize("Key", fmt.Sprintf("%q", k.Key)),
+			console.Colorize("Key", fmt.Sprintf("%q", k.NewKey)), problems)
+	case k.dryRun && k.NewKey != "":
+		return fmt.Sprintf("Would rename %s to %s (%s)", console.Colorize("Key", fmt.Sprintf("%q", k.Key)),
+			console.Colorize("Key", fmt.Sprintf("%q", k.NewKey)), problems)
+	}
+	return fmt.Sprintf("%s: %s", console.Colorize("Key", fmt.Sprintf("%q", k.Key)), problems)
+}
+
+// JSON jsonified keycheck message.
+func (k keycheckMessage) JSON() string {
+	k.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(k, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// isXMLUnsafeRune returns true for characters which are not allowed in XML 1.0
+// documents, S3 listings of such keys cannot be parsed by most clients.
+func isXMLUnsafeRune(r rune) bool {
+	switch {
+	case r == '\t', r == '\n', r == '\r':
+		return false
+	case r < 0x20, r == 0xFFFE, r == 0xFFFF:
+		return true
+	case r >= 0xD800 && r <= 0xDFFF:
+		return true
+	}
+	return false
+}
+
+// keyNameProblems returns a description of everything wrong with a key name.
+func keyNameProblems(key string) (problems []string) {
+	seen := make(map[string]bool)
+	add := func(problem string) {
+		if !seen[problem] {
+			seen[problem] = true
+			problems = append(problems, problem)
+		}
+	}
+
+	if !utf8.ValidString(key) {
+		add("invalid UTF-8")
+	}
+	for _, r := range key {
+		if r == utf8.RuneError {
+			continue
+		}
+		if unicode.IsControl(r) {
+			add("control character")
+		}
+		if isXMLUnsafeRune(r) {
+			add("XML-unsafe character")
+		}
+	}
+	if strings.Contains(key, "]]>") {
+		add("XML-unsafe sequence ']]>'")
+	}
+
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		// A trailing slash leaves an empty last segment, that is a folder.
+		if segment == "" && i == len(segments)-1 && i > 0 {
+			continue
+		}
+		switch {
+		case segment == "":
+			add("empty path segment")
+		case segment == "." || segment == "..":
+			add("relative path segment")
+		case strings.HasSuffix(segment, "."):
+			add("trailing dot")
+		}
+		if strings.HasPrefix(segment, " ") {
+			add("leading space")
+		}
+		if strings.HasSuffix(segment, " ") {
+			add("trailing space")
+		}
+	}
+	return problems
+}
+
+// sanitizeKey returns the key with all problems reported by
+// keyNameProblems removed, unsafe characters are replaced.
+func sanitizeKey(key, replacement string) string {
+	key = strings.ToValidUTF8(key, replacement)
+
+	var b strings.Builder
+	for _, r := range key {
+		if unicode.IsControl(r) || isXMLUnsafeRune(r) {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	key = strings.ReplaceAll(b.String(), "]]>", "]]"+replacement)
+
+	segments := strings.Split(key, "/")
+	sanitized := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		if segment == "" && i == len(segments)-1 && i > 0 {
+			sanitized = append(sanitized, "")
+			continue
+		}
+		if segment == "." || segment == ".." {
+			segment = replacement
+		} else {
+			segment = strings.TrimRight(strings.TrimLeft(segment, " "), " .")
+		}
+		if segment == "" {
+			continue
+		}
+		sanitized = append(sanitized, segment)
+	}
+	return strings.Join(sanitized, "/")
+}
+
+// checkKeycheckSyntax - validate all the passed arguments
+func checkKeycheckSyntax(cliCtx *cli.Context) {
+	if !cliCtx.Args().Present() {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	for _, arg := range cliCtx.Args() {
+		if strings.TrimSpace(arg) == "" {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "Unable to validate empty argument.")
+		}
+	}
+	if cliCtx.Bool("dry-run") && !cliCtx.Bool("fix") {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), "--dry-run can only be used with --fix.")
+	}
+	if cliCtx.Bool("fix") && keyNameProblems("a"+cliCtx.String("replacement")+"a") != nil {
+		fatalIf(errInvalidArgument().Trace(cliCtx.String("replacement")), "The replacement is not safe to use in keys.")
+	}
+}
+
+// renameKey copies an object to its new key on the server and returns
+// true if it was copied, the caller removes the original.
+func renameKey(ctx context.Context, alias string, content *ClientContent, newURL ClientURL) (bool, *probe.Error) {
+	targetClnt, err := newClientFromAlias(alias, newURL.String())
+	if err != nil {
+		return false, err.Trace(newURL.String())
+	}
+	if _, err = targetClnt.Stat(ctx, StatOptions{}); err == nil {
+		return false, errTargetExists(newURL.String()).Trace(content.URL.String())
+	}
+	if err = targetClnt.Copy(ctx, content.URL.Path, CopyOptions{size: content.Size}, nil); err != nil {
+		return false, err.Trace(content.URL.String(), newURL.String())
+	}
+	return true, nil
+}
+
+// doKeycheck - check the keys of all objects below targetURL.
+func doKeycheck(ctx context.Context, targetURL string, fix, dryRun bool, replacement string) (found bool, cErr error) {
+	alias, urlStr, _ := mustExpandAlias(targetURL)
+	clnt, err := newClientFromAlias(alias, urlStr)
+	fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+	if clnt.GetURL().Type != objectStorage {
+		fatalIf(errInvalidArgument().Trace(targetURL), "Keys can only be checked on object storage.")
+	}
+
+	var listErr error
+	removeCh := make(chan *ClientContent)
+	go func() {
+		defer close(removeCh)
+		for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(targetURL), "Unable to list folder.")
+				listErr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			bucket, key := url2BucketAndObject(&content.URL)
+			problems := keyNameProblems(key)
+			if len(problems) == 0 {
+				continue
+			}
+			msg := keycheckMessage{Key: key, Problems: problems, dryRun: dryRun}
+			if !fix {
+				found = true
+				printMsg(msg)
+				continue
+			}
+
+			msg.NewKey = sanitizeKey(key, replacement)
+			if msg.NewKey == "" || msg.NewKey == key {
+				found = true
+				msg.NewKey = ""
+				printMsg(msg)
+				continue
+			}
+			if dryRun {
+				found = true
+				printMsg(msg)
+				continue
+			}
+			newURL := content.URL
+			newURL.Path = string(newURL.Separator) + bucket + string(newURL.Separator) + msg.NewKey
+			renamed, err := renameKey(ctx, alias, content, newURL)
+			if err != nil {
+				found = true
+				errorIf(err, "Unable to rename `"+key+"`.")
+				continue
+			}
+			msg.Renamed = renamed
+			printMsg(msg)
+			removeCh <- content
+		}
+	}()
+
+	for result := range clnt.Remove(ctx, false, false, false, false, removeCh) {
+		if result.Err != nil {
+			errorIf(result.Err.Trace(targetURL), "Unable to remove the original of a renamed key.")
+			cErr = exitStatus(globalErrorExitStatus)
+		}
+	}
+	if listErr != nil {
+		cErr = listErr
+	}
+	return found, cErr
+}
+
+// mainKeycheck - is a handler for mc keycheck command
+func mainKeycheck(cliCtx *cli.Context) error {
+	ctx, cancelKeycheck := context.WithCancel(globalContext)
+	defer cancelKeycheck()
+
+	console.SetColor("Key", color.New(color.Bold))
+	console.SetColor("KeyProblem", color.New(color.FgYellow))
+
+	checkKeycheckSyntax(cliCtx)
+
+	var cErr error
+	var found bool
+	for _, targetURL := range cliCtx.Args() {
+		f, e := doKeycheck(ctx, targetURL, cliCtx.Bool("fix"), cliCtx.Bool("dry-run"), cliCtx.String("replacement"))
+		if e != nil {
+			cErr = e
+		}
+		found = found || f
+	}
+	if cErr == nil && found {
+		cErr = exitStatus(globalErrorExitStatus)
+	}
+	return cErr
+}
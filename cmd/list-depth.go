@@ -0,0 +1,88 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/minio/cli"
+)
+
+var maxDepthFlag = cli.IntFlag{
+	Name:  "max-depth",
+	Usage: "list no deeper than N folder levels below the argument, deeper prefixes are not listed at all",
+}
+
+// checkMaxDepthSyntax returns why --max-depth cannot be used, if so.
+func checkMaxDepthSyntax(cliCtx *cli.Context) string {
+	if cliCtx.Int("max-depth") < 0 {
+		return "--max-depth must not be negative."
+	}
+	return ""
+}
+
+// listMaxDepth lists the entries of a client up to maxDepth folder
+// levels below its URL. Only the folders above maxDepth are listed, one
+// level at a time, those at maxDepth are sent as they are, so that the
+// cost of a listing depends on the folders visited only.
+func listMaxDepth(ctx context.Context, alias string, clnt Client, opts ListOptions, maxDepth int) <-chan *ClientContent {
+	contentCh := make(chan *ClientContent)
+	opts.Recursive = false
+	go func() {
+		defer close(contentCh)
+		walkMaxDepth(ctx, alias, clnt, opts, maxDepth, contentCh)
+	}()
+	return contentCh
+}
+
+// walkMaxDepth sends the entries of a folder, the folders above the
+// last level are walked in place of being sent. It returns false once
+// the context is done.
+func walkMaxDepth(ctx context.Context, alias string, clnt Client, opts ListOptions, depth int, contentCh chan<- *ClientContent) bool {
+	folderURL := clnt.GetURL()
+	folderPath := strings.TrimSuffix(folderURL.Path, string(folderURL.Separator))
+	for content := range clnt.List(ctx, opts) {
+		if content.Err == nil && content.Type.IsDir() {
+			// Some listings include the folder itself.
+			if strings.TrimSuffix(content.URL.Path, string(content.URL.Separator)) == folderPath {
+				continue
+			}
+			if depth > 1 {
+				subURL := content.URL.String()
+				if !strings.HasSuffix(subURL, string(content.URL.Separator)) {
+					subURL += string(content.URL.Separator)
+				}
+				subClnt, err := newClientFromAlias(alias, subURL)
+				if err == nil {
+					if !walkMaxDepth(ctx, alias, subClnt, opts, depth-1, contentCh) {
+						return false
+					}
+					continue
+				}
+				content = &ClientContent{Err: err.Trace(subURL)}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case contentCh <- content:
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckMaxDepthSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket"}, true},
+		{[]string{"--max-depth", "2", "s3/bucket"}, true},
+		{[]string{"--max-depth", "-1", "s3/bucket"}, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, []cli.Flag{maxDepthFlag}, testCase.args...)
+		if msg := checkMaxDepthSyntax(cliCtx); (msg == "") != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %q", i+1, testCase.valid, msg)
+		}
+	}
+}
+
+func TestListMaxDepth(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a", "dir/b", "dir/sub/c", "dir/sub/deep/d", "other/e"} {
+		fpath := filepath.Join(root, filepath.FromSlash(name))
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(name), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	clnt, err := newClientFromAlias("", root+string(filepath.Separator))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		maxDepth int
+		expected []string
+	}{
+		{1, []string{"a", "dir/", "other/"}},
+		{2, []string{"a", "dir/b", "dir/sub/", "other/e"}},
+		{3, []string{"a", "dir/b", "dir/sub/c", "dir/sub/deep/", "other/e"}},
+		{10, []string{"a", "dir/b", "dir/sub/c", "dir/sub/deep/d", "other/e"}},
+	}
+	for i, testCase := range testCases {
+		var got []string
+		for content := range listMaxDepth(context.Background(), "", clnt, ListOptions{ShowDir: DirNone}, testCase.maxDepth) {
+			if content.Err != nil {
+				t.Fatalf("Test %d: %v", i+1, content.Err)
+			}
+			key := filepath.ToSlash(strings.TrimPrefix(content.URL.Path, root+string(filepath.Separator)))
+			if content.Type.IsDir() && !strings.HasSuffix(key, "/") {
+				key += "/"
+			}
+			got = append(got, key)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var lsFormatFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "format",
+		Usage: "print the listing as 'table', 'csv', 'tsv' or 'jsonl' with the --columns",
+	},
+	cli.StringFlag{
+		Name:  "columns",
+		Usage: "comma separated columns of --format, out of " + strings.Join(lsColumns, ","),
+		Value: strings.Join(lsColumns, ","),
+	},
+}
+
+// lsColumns are the columns of 'ls --format', in their default order.
+var lsColumns = []string{"name", "size", "etag", "storage-class", "version-id", "mtime"}
+
+// lsFormats are the formats of 'ls --format'.
+var lsFormats = []string{"table", "csv", "tsv", "jsonl"}
+
+// checkListFormatSyntax returns why --format or --columns cannot be
+// used, if so.
+func checkListFormatSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.IsSet("format") && !cliCtx.IsSet("columns") {
+		return ""
+	}
+	switch {
+	case globalJSON:
+		return "--format cannot be used with --json."
+	case cliCtx.Bool("summarize") || cliCtx.Bool("usage"):
+		return "--format cannot be used with --summarize or --usage."
+	}
+	return ""
+}
+
+// listFormatter prints the objects of a listing in the columns and
+// format of 'ls --format', a header comes first except for jsonl.
+type listFormatter struct {
+	format  string
+	columns []string
+	out     io.Writer
+	table   *tabwriter.Writer
+	csv     *csv.Writer
+	started bool
+}
+
+// newListFormatter validates the format and columns of 'ls --format',
+// the format defaults to a table when only columns are given.
+func newListFormatter(format, columns string, out io.Writer) (*listFormatter, *probe.Error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = "table"
+	}
+	known := false
+	for _, f := range lsFormats {
+		known = known || f == format
+	}
+	if !known {
+		return nil, probe.NewError(fmt.Errorf("unknown format `%s`, valid values are %s", format, strings.Join(lsFormats, ", ")))
+	}
+
+	f := &listFormatter{format: format, out: out}
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		valid := false
+		for _, c := range lsColumns {
+			valid = valid || c == column
+		}
+		if !valid {
+			return nil, probe.NewError(fmt.Errorf("unknown column `%s`, valid values are %s", column, strings.Join(lsColumns, ", ")))
+		}
+		f.columns = append(f.columns, column)
+	}
+
+	switch format {
+	case "table":
+		f.table = tabwriter.NewWriter(out, 1, 8, 2, ' ', 0)
+	case "csv":
+		f.csv = csv.NewWriter(out)
+	case "tsv":
+		f.csv = csv.NewWriter(out)
+		f.csv.Comma = '\t'
+	}
+	return f, nil
+}
+
+// value returns a column of an object as printed, sizes in bytes and
+// times in RFC3339 so that exports can be processed further.
+func (f *listFormatter) value(msg contentMessage, column string) string {
+	switch column {
+	case "name":
+		return msg.Key
+	case "size":
+		return strconv.FormatInt(msg.Size, 10)
+	case "etag":
+		return msg.ETag
+	case "storage-class":
+		return msg.StorageClass
+	case "version-id":
+		return msg.VersionID
+	case "mtime":
+		return msg.Time.UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+// print prints an object.
+func (f *listFormatter) print(msg contentMessage) {
+	if f.format == "jsonl" {
+		fields := make([]string, 0, len(f.columns))
+		for _, column := range f.columns {
+			var value interface{} = f.value(msg, column)
+			if column == "size" {
+				value = msg.Size
+			}
+			b, _ := json.Marshal(value)
+			name, _ := json.Marshal(column)
+			fields = append(fields, string(name)+":"+string(b))
+		}
+		fmt.Fprintln(f.out, "{"+strings.Join(fields, ",")+"}")
+		return
+	}
+
+	if !f.started {
+		f.started = true
+		f.row(f.columns)
+	}
+	values := make([]string, 0, len(f.columns))
+	for _, column := range f.columns {
+		values = append(values, f.value(msg, column))
+	}
+	f.row(values)
+}
+
+func (f *listFormatter) row(values []string) {
+	if f.table != nil {
+		fmt.Fprintln(f.table, strings.Join(values, "\t"))
+		return
+	}
+	f.csv.Write(values)
+}
+
+// flush prints the buffered rows, tables are aligned on all of them.
+func (f *listFormatter) flush() {
+	switch {
+	case f.table != nil:
+		f.table.Flush()
+	case f.csv != nil:
+		f.csv.Flush()
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckListFormatSyntax(t *testing.T) {
+	flags := append([]cli.Flag{cli.BoolFlag{Name: "summarize"}, cli.BoolFlag{Name: "usage"}}, lsFormatFlags...)
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket"}, true},
+		{[]string{"--format", "csv", "s3/bucket"}, true},
+		{[]string{"--columns", "name", "s3/bucket"}, true},
+		{[]string{"--format", "csv", "--summarize", "s3/bucket"}, false},
+		{[]string{"--columns", "name", "--usage", "s3"}, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, flags, testCase.args...)
+		if msg := checkListFormatSyntax(cliCtx); (msg == "") != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %q", i+1, testCase.valid, msg)
+		}
+	}
+}
+
+func TestListFormatter(t *testing.T) {
+	modTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	msgs := []contentMessage{
+		{Key: "a.txt", Size: 5, ETag: "e1", StorageClass: "STANDARD", Time: modTime},
+		{Key: "dir/b, c", Size: 1024, ETag: "e2", VersionID: "v2", Time: modTime},
+	}
+	testCases := []struct {
+		format, columns string
+		expected        string
+	}{
+		{"csv", "name,size", "name,size\na.txt,5\n\"dir/b, c\",1024\n"},
+		{"tsv", "size,etag", "size\tetag\n5\te1\n1024\te2\n"},
+		{"jsonl", "name,size,version-id", `{"name":"a.txt","size":5,"version-id":""}` + "\n" + `{"name":"dir/b, c","size":1024,"version-id":"v2"}` + "\n"},
+		{"", "storage-class,mtime", "storage-class  mtime\nSTANDARD       2023-05-01T12:00:00Z\n               2023-05-01T12:00:00Z\n"},
+		{"TABLE", "Name", "name\na.txt\ndir/b, c\n"},
+	}
+	for i, testCase := range testCases {
+		var out bytes.Buffer
+		f, err := newListFormatter(testCase.format, testCase.columns, &out)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		for _, msg := range msgs {
+			f.print(msg)
+		}
+		f.flush()
+		if out.String() != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, out.String())
+		}
+	}
+
+	for _, testCase := range []struct{ format, columns string }{{"xml", "name"}, {"csv", "name,owner"}, {"csv", ""}} {
+		if _, err := newListFormatter(testCase.format, testCase.columns, &bytes.Buffer{}); err == nil {
+			t.Errorf("expected an error for format %q and columns %q", testCase.format, testCase.columns)
+		}
+	}
+}
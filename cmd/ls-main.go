@@ -20,6 +20,8 @@ package cmd
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -56,10 +58,20 @@ var (
 			Name:  "storage-class, sc",
 			Usage: "filter to specified storage class",
 		},
+		tierFlag,
 		cli.BoolFlag{
 			Name:  "zip",
 			Usage: "list files inside zip archive (MinIO servers only)",
 		},
+		cli.BoolFlag{
+			Name:  "exclude-deleted",
+			Usage: "leave out keys whose latest version is a delete marker",
+		},
+		cli.BoolFlag{
+			Name:  "usage",
+			Usage: "annotate the buckets of an alias with their usage, object count, quota and versioning status",
+		},
+		maxDepthFlag,
 	}
 )
 
@@ -70,7 +82,7 @@ var lsCmd = cli.Command{
 	Action:       mainList,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(lsFlags, globalFlags...),
+	Flags:        joinFlags(lsFlags, []cli.Flag{requestPayerFlag}, lsFormatFlags, lsSortFlags, keyOutputFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -112,6 +124,36 @@ EXAMPLES:
   
   10. List all objects on mybucket, for the GLACIER storage class
      {{.Prompt}} {{.HelpName}} --storage-class 'GLACIER' s3/mybucket 
+
+  11. List all contents of a Requester Pays bucket on Amazon S3, paying for the requests.
+     {{.Prompt}} {{.HelpName}} --request-payer requester s3/datasets/
+
+  12. List all versions of the objects which have not been deleted.
+     {{.Prompt}} {{.HelpName}} --versions --exclude-deleted s3/mybucket
+
+  13. List all buckets with their usage, object count, quota and versioning status.
+     {{.Prompt}} {{.HelpName}} --usage myminio
+
+  14. List a bucket through a gateway which expects a tenant header and a vendor query parameter.
+     {{.Prompt}} {{.HelpName}} --header "x-tenant: acme" --query "vendor=ext" s3/mybucket
+
+  15. List all objects currently transitioned to a remote tier and not restored, to plan a restore campaign.
+     {{.Prompt}} {{.HelpName}} --recursive --tier remote myminio/mybucket
+
+  16. Export an inventory of a bucket as CSV, with the name, size and ETag of every object.
+     {{.Prompt}} {{.HelpName}} --recursive --format csv --columns name,size,etag s3/mybucket > inventory.csv
+
+  17. List the 100 largest objects below a prefix.
+     {{.Prompt}} {{.HelpName}} --recursive --sort size --reverse --limit 100 s3/mybucket/logs/
+
+  18. List the 10 most recently modified objects of a bucket.
+     {{.Prompt}} {{.HelpName}} --recursive --sort mtime --tail 10 s3/mybucket
+
+  19. List the objects and folders up to two levels below the top of a huge bucket.
+     {{.Prompt}} {{.HelpName}} --max-depth 2 s3/mybucket
+
+  20. Print the key and the size of every object of mybucket, separated by a tab.
+      {{.Prompt}} {{.HelpName}} --recursive --format-template '{{"{{.Key}}\\t{{.Size}}"}}' s3/mybucket
 `,
 }
 
@@ -173,21 +215,62 @@ func checkListSyntax(cliCtx *cli.Context) ([]string, doListOptions) {
 	withOlderVersions := cliCtx.Bool("versions")
 	isSummary := cliCtx.Bool("summarize")
 	listZip := cliCtx.Bool("zip")
+	excludeDeleted := cliCtx.Bool("exclude-deleted")
+	withUsage := cliCtx.Bool("usage")
 
 	timeRef := parseRewindFlag(cliCtx.String("rewind"))
 
 	if listZip && (withOlderVersions || !timeRef.IsZero()) {
 		fatalIf(errInvalidArgument().Trace(args...), "Zip file listing can only be performed on the latest version")
 	}
+	if withUsage {
+		for _, arg := range args {
+			if _, path := url2Alias(arg); strings.Trim(filepath.ToSlash(path), "/") != "" {
+				fatalIf(errInvalidArgument().Trace(arg), "--usage can only be used to list the buckets of an alias.")
+			}
+		}
+	}
 	storageClasss := cliCtx.String("storage-class")
+	// The storage class keeps its exact match, only the residency
+	// is filtered by the tier filter.
+	tier, err := parseTierResidencyFilter("", cliCtx.String("tier"))
+	fatalIf(err.Trace(args...), "Unable to parse --tier.")
+	if msg := checkListFormatSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(args...), msg)
+	}
+	if msg := checkMaxDepthSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(args...), msg)
+	}
+	if cliCtx.Int("max-depth") > 0 && (listZip || withUsage || isIncomplete) {
+		fatalIf(errInvalidArgument().Trace(args...), "--max-depth cannot be used with --zip, --usage or --incomplete.")
+	}
+	if msg := checkListOrderSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(args...), msg)
+	}
+	var formatter *listFormatter
+	if cliCtx.IsSet("format") || cliCtx.IsSet("columns") {
+		formatter, err = newListFormatter(cliCtx.String("format"), cliCtx.String("columns"), os.Stdout)
+		fatalIf(err.Trace(args...), "Unable to parse --format.")
+	}
 	opts := doListOptions{
 		timeRef:           timeRef,
 		isRecursive:       isRecursive,
 		isIncomplete:      isIncomplete,
 		isSummary:         isSummary,
 		withOlderVersions: withOlderVersions,
+		excludeDeleted:    excludeDeleted,
+		withUsage:         withUsage,
 		listZip:           listZip,
 		filter:            storageClasss,
+		tier:              tier,
+		formatter:         formatter,
+		maxDepth:          cliCtx.Int("max-depth"),
+		order: listOrder{
+			by:      strings.ToLower(cliCtx.String("sort")),
+			reverse: cliCtx.Bool("reverse"),
+			limit:   cliCtx.Int("limit"),
+			tail:    cliCtx.Int("tail"),
+		},
 	}
 	return args, opts
 }
@@ -216,6 +299,15 @@ func mainList(cliCtx *cli.Context) error {
 	for _, targetURL := range args {
 		clnt, err := newClient(targetURL)
 		fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+		if opts.withUsage {
+			if _, ok := clnt.(*S3Client); !ok {
+				fatalIf(errInvalidArgument().Trace(targetURL), "--usage can only be used to list the buckets of an alias.")
+			}
+			if e := doListBucketUsage(ctx, clnt, targetURL); e != nil {
+				cErr = e
+			}
+			continue
+		}
 		if !strings.HasSuffix(targetURL, string(clnt.GetURL().Separator)) {
 			var st *ClientContent
 			st, err = clnt.Stat(ctx, StatOptions{incomplete: opts.isIncomplete})
@@ -225,9 +317,14 @@ func mainList(cliCtx *cli.Context) error {
 				fatalIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
 			}
 		}
+		opts.tier.alias, _, _ = mustExpandAlias(targetURL)
+		opts.alias = opts.tier.alias
 		if e := doList(ctx, clnt, opts); e != nil {
 			cErr = e
 		}
 	}
+	if opts.formatter != nil {
+		opts.formatter.flush()
+	}
 	return cErr
 }
@@ -0,0 +1,182 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"container/heap"
+	"sort"
+	"strings"
+
+	"github.com/minio/cli"
+)
+
+var lsSortFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "sort",
+		Usage: "sort the listing by 'name', 'size' or 'mtime'",
+	},
+	cli.BoolFlag{
+		Name:  "reverse",
+		Usage: "reverse the order of the listing",
+	},
+	cli.IntFlag{
+		Name:  "limit",
+		Usage: "print only the first N entries of the listing",
+	},
+	cli.IntFlag{
+		Name:  "tail",
+		Usage: "print only the last N entries of the listing",
+	},
+}
+
+// listOrder is the order and truncation of a listing requested with
+// 'ls --sort', '--reverse', '--limit' and '--tail'.
+type listOrder struct {
+	by      string
+	reverse bool
+	limit   int
+	tail    int
+}
+
+// checkListOrderSyntax returns why the order of a listing cannot be
+// used, if so.
+func checkListOrderSyntax(cliCtx *cli.Context) string {
+	switch strings.ToLower(cliCtx.String("sort")) {
+	case "", "name", "size", "mtime":
+	default:
+		return "--sort must be one of name, size or mtime."
+	}
+	switch {
+	case cliCtx.Int("limit") < 0 || cliCtx.Int("tail") < 0:
+		return "--limit and --tail must not be negative."
+	case cliCtx.IsSet("limit") && cliCtx.IsSet("tail"):
+		return "--limit cannot be used with --tail."
+	case cliCtx.Bool("usage") && (cliCtx.IsSet("sort") || cliCtx.Bool("reverse") || cliCtx.IsSet("limit") || cliCtx.IsSet("tail")):
+		return "--sort, --reverse, --limit and --tail cannot be used with --usage."
+	}
+	return ""
+}
+
+// listSorter orders the entries of a listing. Only the entries which
+// may be printed are kept: a bounded heap of --limit or --tail entries
+// when sorting, the first or last entries otherwise.
+type listSorter struct {
+	order listOrder
+	heap  *messageHeap
+	msgs  []contentMessage
+}
+
+// newListSorter returns a sorter for the order of a listing, nil if
+// the listing is printed as it comes.
+func newListSorter(order listOrder) *listSorter {
+	if order.by == "" && !order.reverse && order.limit == 0 && order.tail == 0 {
+		return nil
+	}
+	s := &listSorter{order: order}
+	if order.by != "" && (order.limit > 0 || order.tail > 0) {
+		less := s.less
+		if order.limit > 0 {
+			// Keep the first entries, the last one is evicted first.
+			less = func(a, b contentMessage) bool { return s.less(b, a) }
+		}
+		s.heap = &messageHeap{less: less}
+	}
+	return s
+}
+
+// less returns true if a is listed before b.
+func (s *listSorter) less(a, b contentMessage) bool {
+	if s.order.reverse {
+		a, b = b, a
+	}
+	switch s.order.by {
+	case "size":
+		if a.Size != b.Size {
+			return a.Size < b.Size
+		}
+	case "mtime":
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+	}
+	return a.Key < b.Key
+}
+
+// add adds an entry of the listing.
+func (s *listSorter) add(msg contentMessage) {
+	switch {
+	case s.heap != nil:
+		heap.Push(s.heap, msg)
+		if s.heap.Len() > s.order.limit+s.order.tail {
+			heap.Pop(s.heap)
+		}
+	case s.order.by == "" && !s.order.reverse && s.order.limit > 0:
+		if len(s.msgs) < s.order.limit {
+			s.msgs = append(s.msgs, msg)
+		}
+	case s.order.by == "" && !s.order.reverse && s.order.tail > 0:
+		s.msgs = append(s.msgs, msg)
+		if len(s.msgs) > s.order.tail {
+			s.msgs = append(s.msgs[:0], s.msgs[1:]...)
+		}
+	default:
+		s.msgs = append(s.msgs, msg)
+	}
+}
+
+// done returns true once no further entry can be printed, the listing
+// can then stop early.
+func (s *listSorter) done() bool {
+	return s.order.by == "" && !s.order.reverse && s.order.limit > 0 && len(s.msgs) >= s.order.limit
+}
+
+// sorted returns the entries to print, in order.
+func (s *listSorter) sorted() []contentMessage {
+	msgs := s.msgs
+	if s.heap != nil {
+		msgs = s.heap.msgs
+	}
+	if s.order.by != "" || s.order.reverse {
+		sort.SliceStable(msgs, func(i, j int) bool { return s.less(msgs[i], msgs[j]) })
+	}
+	switch {
+	case s.order.limit > 0 && len(msgs) > s.order.limit:
+		msgs = msgs[:s.order.limit]
+	case s.order.tail > 0 && len(msgs) > s.order.tail:
+		msgs = msgs[len(msgs)-s.order.tail:]
+	}
+	return msgs
+}
+
+// messageHeap is a heap of listing entries, the least one on top.
+type messageHeap struct {
+	msgs []contentMessage
+	less func(a, b contentMessage) bool
+}
+
+func (h messageHeap) Len() int            { return len(h.msgs) }
+func (h messageHeap) Less(i, j int) bool  { return h.less(h.msgs[i], h.msgs[j]) }
+func (h messageHeap) Swap(i, j int)       { h.msgs[i], h.msgs[j] = h.msgs[j], h.msgs[i] }
+func (h *messageHeap) Push(x interface{}) { h.msgs = append(h.msgs, x.(contentMessage)) }
+
+func (h *messageHeap) Pop() interface{} {
+	n := len(h.msgs)
+	x := h.msgs[n-1]
+	h.msgs = h.msgs[:n-1]
+	return x
+}
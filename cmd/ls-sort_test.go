@@ -0,0 +1,99 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckListOrderSyntax(t *testing.T) {
+	flags := append([]cli.Flag{cli.BoolFlag{Name: "usage"}}, lsSortFlags...)
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket"}, true},
+		{[]string{"--sort", "Size", "--reverse", "--limit", "10", "s3/bucket"}, true},
+		{[]string{"--tail", "10", "s3/bucket"}, true},
+		{[]string{"--sort", "owner", "s3/bucket"}, false},
+		{[]string{"--limit", "-1", "s3/bucket"}, false},
+		{[]string{"--limit", "1", "--tail", "1", "s3/bucket"}, false},
+		{[]string{"--sort", "size", "--usage", "s3"}, false},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, flags, testCase.args...)
+		if msg := checkListOrderSyntax(cliCtx); (msg == "") != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %q", i+1, testCase.valid, msg)
+		}
+	}
+}
+
+func TestListSorter(t *testing.T) {
+	modTime := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Entries come in the order of the keys, as listed.
+	msgs := []contentMessage{
+		{Key: "a", Size: 30, Time: modTime.Add(2 * time.Hour)},
+		{Key: "b", Size: 10, Time: modTime},
+		{Key: "c", Size: 50, Time: modTime.Add(time.Hour)},
+		{Key: "d", Size: 20, Time: modTime.Add(3 * time.Hour)},
+		{Key: "e", Size: 10, Time: modTime.Add(4 * time.Hour)},
+	}
+	testCases := []struct {
+		order    listOrder
+		expected []string
+		done     bool
+	}{
+		{listOrder{by: "size"}, []string{"b", "e", "d", "a", "c"}, false},
+		{listOrder{by: "size", reverse: true}, []string{"c", "a", "d", "e", "b"}, false},
+		{listOrder{by: "size", reverse: true, limit: 2}, []string{"c", "a"}, false},
+		{listOrder{by: "size", limit: 3}, []string{"b", "e", "d"}, false},
+		{listOrder{by: "mtime", tail: 2}, []string{"d", "e"}, false},
+		{listOrder{by: "mtime", reverse: true, tail: 2}, []string{"c", "b"}, false},
+		{listOrder{by: "name", reverse: true}, []string{"e", "d", "c", "b", "a"}, false},
+		{listOrder{limit: 2}, []string{"a", "b"}, true},
+		{listOrder{tail: 2}, []string{"d", "e"}, false},
+		{listOrder{reverse: true, limit: 2}, []string{"e", "d"}, false},
+	}
+	for i, testCase := range testCases {
+		s := newListSorter(testCase.order)
+		for _, msg := range msgs {
+			s.add(msg)
+		}
+		var got []string
+		for _, msg := range s.sorted() {
+			got = append(got, msg.Key)
+		}
+		if !reflect.DeepEqual(got, testCase.expected) {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.expected, got)
+		}
+		if s.done() != testCase.done {
+			t.Errorf("Test %d: expected done %t", i+1, testCase.done)
+		}
+		if s.heap != nil && s.heap.Len() > testCase.order.limit+testCase.order.tail {
+			t.Errorf("Test %d: the heap holds %d entries", i+1, s.heap.Len())
+		}
+	}
+
+	if newListSorter(listOrder{}) != nil {
+		t.Error("expected no sorter without an order")
+	}
+}
@@ -23,10 +23,12 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v3"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/console"
 )
@@ -75,7 +77,7 @@ func (c contentMessage) String() string {
 		}
 	}
 
-	fileDesc += " " + c.Key
+	fileDesc += " " + quoteOutput(c.Key)
 
 	if c.Filetype == "folder" {
 		message += console.Colorize("Dir", fileDesc)
@@ -197,13 +199,28 @@ func (s summaryMessage) JSON() string {
 	return string(jsonMessageBytes)
 }
 
-// Pretty print the list of versions belonging to one object
-func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions bool) {
+// Pretty print the list of versions belonging to one object, in the
+// columns of 'ls --format' if a formatter is given. Entries are left to
+// the sorter, if any, to be printed in order.
+func printObjectVersions(clntURL ClientURL, ctntVersions []*ClientContent, printAllVersions bool, formatter *listFormatter, sorter *listSorter) {
 	sortObjectVersions(ctntVersions)
 	msgs := generateContentMessages(clntURL, ctntVersions, printAllVersions)
 	for _, msg := range msgs {
-		printMsg(msg)
+		if sorter != nil {
+			sorter.add(msg)
+			continue
+		}
+		printListed(msg, formatter)
+	}
+}
+
+// printListed prints an entry of a listing.
+func printListed(msg contentMessage, formatter *listFormatter) {
+	if formatter != nil {
+		formatter.print(msg)
+		return
 	}
+	printMsg(msg)
 }
 
 type doListOptions struct {
@@ -212,8 +229,16 @@ type doListOptions struct {
 	isIncomplete      bool
 	isSummary         bool
 	withOlderVersions bool
+	excludeDeleted    bool
 	listZip           bool
+	withUsage         bool
 	filter            string
+	tier              tierResidencyFilter
+	formatter         *listFormatter
+	order             listOrder
+	// Folder levels listed below the target, without limit if 0.
+	maxDepth int
+	alias    string
 }
 
 // doList - list all entities inside a folder.
@@ -226,15 +251,29 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 		totalObjects      int64
 	)
 
-	for content := range clnt.List(ctx, ListOptions{
+	// A listing truncated by --limit stops early.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sorter := newListSorter(o.order)
+
+	listOpts := ListOptions{
 		Recursive:         o.isRecursive,
 		Incomplete:        o.isIncomplete,
 		TimeRef:           o.timeRef,
 		WithOlderVersions: o.withOlderVersions || !o.timeRef.IsZero(),
 		WithDeleteMarkers: true,
+		ExcludeDeleted:    o.excludeDeleted,
 		ShowDir:           DirNone,
 		ListZip:           o.listZip,
-	}) {
+	}
+	var contentCh <-chan *ClientContent
+	if o.maxDepth > 0 {
+		contentCh = listMaxDepth(ctx, o.alias, clnt, listOpts, o.maxDepth)
+	} else {
+		contentCh = clnt.List(ctx, listOpts)
+	}
+
+	for content := range filterTierResidency(ctx, o.tier, contentCh) {
 		if content.Err != nil {
 			errorIf(content.Err.Trace(clnt.GetURL().String()), "Unable to list folder.")
 			cErr = exitStatus(globalErrorExitStatus) // Set the exit status.
@@ -247,7 +286,11 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 
 		if lastPath != content.URL.Path {
 			// Print any object in the current list before reinitializing it
-			printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+			printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions, o.formatter, sorter)
+			if sorter != nil && sorter.done() {
+				perObjectVersions = nil
+				break
+			}
 			lastPath = content.URL.Path
 			perObjectVersions = []*ClientContent{}
 		}
@@ -257,7 +300,12 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 		totalObjects++
 	}
 
-	printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions)
+	printObjectVersions(clnt.GetURL(), perObjectVersions, o.withOlderVersions, o.formatter, sorter)
+	if sorter != nil {
+		for _, msg := range sorter.sorted() {
+			printListed(msg, o.formatter)
+		}
+	}
 
 	if o.isSummary {
 		printMsg(summaryMessage{
@@ -268,3 +316,111 @@ func doList(ctx context.Context, clnt Client, o doListOptions) error {
 
 	return cErr
 }
+
+// Number of buckets whose quota and versioning status are fetched concurrently.
+const bucketUsageConcurrency = 16
+
+// bucketUsageMessage container for a bucket annotated with its usage.
+type bucketUsageMessage struct {
+	Status     string    `json:"status"`
+	Time       time.Time `json:"lastModified"`
+	Key        string    `json:"key"`
+	Size       uint64    `json:"size"`
+	Objects    uint64    `json:"objects"`
+	Quota      uint64    `json:"quota,omitempty"`
+	QuotaType  string    `json:"quotaType,omitempty"`
+	Versioning string    `json:"versioning,omitempty"`
+}
+
+// String colorized string message.
+func (b bucketUsageMessage) String() string {
+	message := console.Colorize("Time", fmt.Sprintf("[%s]", b.Time.Format(printDate)))
+	message += console.Colorize("Size", fmt.Sprintf("%7s", strings.Join(strings.Fields(humanize.IBytes(b.Size)), "")))
+	message += console.Colorize("Dir", " "+quoteOutput(b.Key))
+	message += fmt.Sprintf(" objects: %s", humanize.Comma(int64(b.Objects)))
+	if b.Quota > 0 {
+		message += fmt.Sprintf(", quota: %s (%s, %.0f%% used)", humanize.IBytes(b.Quota), b.QuotaType,
+			float64(b.Size)*100/float64(b.Quota))
+	}
+	versioning := b.Versioning
+	if versioning == "" {
+		versioning = "Unversioned"
+	}
+	message += ", versioning: " + versioning
+	return message
+}
+
+// JSON jsonified bucket usage message.
+func (b bucketUsageMessage) JSON() string {
+	b.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(b, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// doListBucketUsage - list all buckets of an alias annotated with their
+// usage, object count, quota and versioning status.
+func doListBucketUsage(ctx context.Context, clnt Client, targetURL string) error {
+	buckets, err := clnt.ListBuckets(ctx)
+	if err != nil {
+		errorIf(err.Trace(targetURL), "Unable to list buckets.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	// Usage and quotas are only known to MinIO, other servers
+	// are listed with their versioning status only.
+	var usage madmin.DataUsageInfo
+	adminClient, _ := newAdminClient(targetURL)
+	if adminClient != nil {
+		usage, _ = adminClient.DataUsageInfo(ctx)
+	}
+
+	alias, _ := url2Alias(targetURL)
+	msgs := make([]bucketUsageMessage, len(buckets))
+	sem := make(chan struct{}, bucketUsageConcurrency)
+	var wg sync.WaitGroup
+	for i, bucket := range buckets {
+		bucketName := bucket.BucketName
+		bu := usage.BucketsUsage[bucketName]
+		msgs[i] = bucketUsageMessage{
+			Time:    bucket.Time,
+			Key:     bucketName + "/",
+			Size:    bu.Size,
+			Objects: bu.ObjectsCount,
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(msg *bucketUsageMessage, bucketName string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if adminClient != nil {
+				if q, e := adminClient.GetBucketQuota(ctx, bucketName); e == nil {
+					msg.Quota = q.Size
+					if msg.Quota == 0 {
+						msg.Quota = q.Quota
+					}
+					if msg.Quota > 0 {
+						msg.QuotaType = string(q.Type)
+					}
+				}
+			}
+			bucketClnt, err := newClient(alias + "/" + bucketName)
+			if err != nil {
+				return
+			}
+			if vcfg, err := bucketClnt.GetVersion(ctx); err == nil {
+				msg.Versioning = vcfg.Status
+			}
+		}(&msgs[i], bucketName)
+	}
+	wg.Wait()
+
+	for _, msg := range msgs {
+		printMsg(msg)
+	}
+	return nil
+}
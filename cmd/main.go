@@ -137,6 +137,15 @@ func Main(args []string) error {
 	// Wait until the user quits the pager
 	defer globalHelpPager.WaitForExit()
 
+	// Account the requests of aliases and deliver the results to
+	// --output-target however mc exits
+	registerExitHook(flushAliasStats)
+	registerExitHook(flushOutputTarget)
+	defer runExitHooks()
+
+	// Report the requests and transfers to Prometheus if MC_METRICS_ADDRESS is set
+	startMetricsServer()
+
 	// Run the app
 	return registerApp(appName).Run(args)
 }
@@ -420,19 +429,24 @@ func checkUpdate(ctx *cli.Context) {
 
 var appCmds = []cli.Command{
 	aliasCmd,
+	contextCmd,
 	lsCmd,
 	mbCmd,
 	rbCmd,
+	bucketCmd,
 	cpCmd,
 	mvCmd,
 	rmCmd,
 	mirrorCmd,
+	websiteCmd,
 	catCmd,
 	headCmd,
+	tailCmd,
 	pipeCmd,
 	findCmd,
 	sqlCmd,
 	statCmd,
+	checksumCmd,
 	treeCmd,
 	duCmd,
 	retentionCmd,
@@ -447,17 +461,22 @@ var appCmds = []cli.Command{
 	eventCmd,
 	watchCmd,
 	undoCmd,
+	trashCmd,
 	anonymousCmd,
 	policyCmd,
 	tagCmd,
 	diffCmd,
+	applyCmd,
+	keycheckCmd,
 	replicateCmd,
 	adminCmd,
+	accesskeyCmd,
 	idpCmd,
 	configCmd,
 	updateCmd,
 	readyCmd,
 	pingCmd,
+	featuresCmd,
 	odCmd,
 	batchCmd,
 }
@@ -0,0 +1,178 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// mcIgnoreFile lists gitignore-style patterns of the files and folders
+// skipped when a local folder is listed recursively, e.g. by cp and
+// mirror. Patterns apply to the folder of the file and its subfolders.
+const mcIgnoreFile = ".mcignore"
+
+// mcIgnorePattern is a pattern of a .mcignore file.
+type mcIgnorePattern struct {
+	// Pattern split at '/', '**' matches any number of folders.
+	segments []string
+	// Match the path relative to the folder of the .mcignore file,
+	// otherwise the name of the entry at any depth.
+	anchored bool
+	negate   bool
+	dirOnly  bool
+}
+
+// parseMCIgnorePattern parses a line of a .mcignore file, ok is false
+// for blank lines and comments.
+func parseMCIgnorePattern(line string) (p mcIgnorePattern, ok bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return p, false
+	}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\") {
+		// '\#' and '\!' start patterns with a literal character.
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return p, false
+	}
+	p.segments = strings.Split(line, "/")
+	return p, true
+}
+
+// match reports whether the pattern matches relPath, a slash separated
+// path relative to the folder of the .mcignore file.
+func (p mcIgnorePattern) match(relPath string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if !p.anchored {
+		matched, e := path.Match(p.segments[0], path.Base(relPath))
+		return e == nil && matched
+	}
+	return matchMCIgnoreSegments(p.segments, strings.Split(relPath, "/"))
+}
+
+// matchMCIgnoreSegments matches path segments against pattern segments.
+func matchMCIgnoreSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchMCIgnoreSegments(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(names) == 0 {
+			return false
+		}
+		if matched, e := path.Match(patterns[0], names[0]); e != nil || !matched {
+			return false
+		}
+		patterns, names = patterns[1:], names[1:]
+	}
+	return len(names) == 0
+}
+
+// mcIgnoreRules holds the .mcignore files found in a folder tree, it
+// is safe for concurrent use.
+type mcIgnoreRules struct {
+	mu sync.RWMutex
+	// key is a cleaned folder path.
+	patterns map[string][]mcIgnorePattern
+}
+
+func newMCIgnoreRules() *mcIgnoreRules {
+	return &mcIgnoreRules{patterns: make(map[string][]mcIgnorePattern)}
+}
+
+// load reads the .mcignore file of dir, if any, a file which cannot be
+// read ignores nothing.
+func (r *mcIgnoreRules) load(dir string) {
+	dir = filepath.Clean(dir)
+	r.mu.RLock()
+	_, ok := r.patterns[dir]
+	r.mu.RUnlock()
+	if ok {
+		return
+	}
+	var patterns []mcIgnorePattern
+	if f, e := os.Open(filepath.Join(dir, mcIgnoreFile)); e == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if p, ok := parseMCIgnorePattern(scanner.Text()); ok {
+				patterns = append(patterns, p)
+			}
+		}
+		f.Close()
+	}
+	r.mu.Lock()
+	r.patterns[dir] = patterns
+	r.mu.Unlock()
+}
+
+// isIgnored reports whether fp is excluded by the .mcignore files loaded
+// for its parent folders, the last matching pattern wins and patterns of
+// deeper folders come last.
+func (r *mcIgnoreRules) isIgnored(fp string, isDir bool) bool {
+	var dirs []string
+	for dir := filepath.Dir(fp); ; dir = filepath.Dir(dir) {
+		dirs = append(dirs, dir)
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ignored := false
+	for i := len(dirs) - 1; i >= 0; i-- {
+		patterns := r.patterns[dirs[i]]
+		if len(patterns) == 0 {
+			continue
+		}
+		relPath, e := filepath.Rel(dirs[i], fp)
+		if e != nil {
+			continue
+		}
+		relPath = filepath.ToSlash(relPath)
+		for _, p := range patterns {
+			if p.match(relPath, isDir) {
+				ignored = !p.negate
+			}
+		}
+	}
+	return ignored
+}
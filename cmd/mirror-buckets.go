@@ -0,0 +1,248 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// mirrorBucketFlags select the buckets of an alias mirrored to another.
+var mirrorBucketFlags = []string{"include-bucket", "exclude-bucket", "exclude-bucket-region", "exclude-bucket-tag"}
+
+// mirrorBucketFilter selects the buckets mirrored when mirroring all
+// the buckets of an alias.
+type mirrorBucketFilter struct {
+	include, exclude []string // Glob patterns of bucket names.
+	excludeRegions   []string
+	excludeTags      map[string]string
+
+	// Source buckets excluded by their region or tags.
+	excluded map[string]bool
+}
+
+// newMirrorBucketFilter returns the bucket filter of the mirror flags,
+// nil if none was passed.
+func newMirrorBucketFilter(cliCtx *cli.Context) (*mirrorBucketFilter, *probe.Error) {
+	f := &mirrorBucketFilter{
+		include:        cliCtx.StringSlice("include-bucket"),
+		exclude:        cliCtx.StringSlice("exclude-bucket"),
+		excludeRegions: cliCtx.StringSlice("exclude-bucket-region"),
+		excluded:       make(map[string]bool),
+	}
+	for _, tag := range cliCtx.StringSlice("exclude-bucket-tag") {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return nil, errInvalidArgument().Trace(tag)
+		}
+		if f.excludeTags == nil {
+			f.excludeTags = make(map[string]string)
+		}
+		f.excludeTags[key] = value
+	}
+	if len(f.include) == 0 && len(f.exclude) == 0 && len(f.excludeRegions) == 0 && len(f.excludeTags) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// skip tells if a bucket is left out of the mirror.
+func (f *mirrorBucketFilter) skip(bucket string) bool {
+	if f == nil {
+		return false
+	}
+	if f.excluded[bucket] || matchExcludeOptions(f.exclude, bucket) {
+		return true
+	}
+	return len(f.include) > 0 && !matchExcludeOptions(f.include, bucket)
+}
+
+// skipSuffix tells if the object at a path relative to the alias is in
+// a bucket left out of the mirror.
+func (f *mirrorBucketFilter) skipSuffix(suffix, separator string) bool {
+	if f == nil {
+		return false
+	}
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(suffix, separator), separator)
+	return f.skip(bucket)
+}
+
+// excludeByConfig looks up the region and the tags of the buckets of
+// the source alias to exclude those matching --exclude-bucket-region or
+// --exclude-bucket-tag.
+func (f *mirrorBucketFilter) excludeByConfig(ctx context.Context, srcURL string, srcClnt Client) *probe.Error {
+	if f == nil || len(f.excludeRegions) == 0 && len(f.excludeTags) == 0 {
+		return nil
+	}
+	buckets, err := srcClnt.ListBuckets(ctx)
+	if err != nil {
+		return err.Trace(srcURL)
+	}
+	for _, b := range buckets {
+		bucket := strings.Trim(b.URL.Path, string(b.URL.Separator))
+		if f.skip(bucket) {
+			continue
+		}
+		bucketURL := path.Join(srcURL, bucket)
+		clnt, err := newClient(bucketURL)
+		if err != nil {
+			return err.Trace(bucketURL)
+		}
+		info, err := clnt.GetBucketInfo(ctx)
+		if err != nil {
+			return err.Trace(bucketURL)
+		}
+		f.excluded[bucket] = bucketConfigExcluded(info.Location, info.Tagging, f.excludeRegions, f.excludeTags)
+	}
+	return nil
+}
+
+// bucketConfigExcluded tells if a bucket of a region and with tags is
+// in one of the excluded regions or has one of the excluded tags.
+func bucketConfigExcluded(region string, tags map[string]string, excludeRegions []string, excludeTags map[string]string) bool {
+	for _, r := range excludeRegions {
+		if strings.EqualFold(r, region) {
+			return true
+		}
+	}
+	for key, value := range excludeTags {
+		if v, ok := tags[key]; ok && v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// copyBucketVersioning enables or suspends the versioning of a bucket
+// as it is on the source bucket, it returns false if the source bucket
+// was never versioned.
+func copyBucketVersioning(ctx context.Context, srcClnt, dstClnt Client) (bool, *probe.Error) {
+	config, err := srcClnt.GetVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case config.Enabled():
+		return true, dstClnt.SetVersion(ctx, "enable", nil, false)
+	case config.Suspended():
+		return true, dstClnt.SetVersion(ctx, "suspend", nil, false)
+	}
+	return false, nil
+}
+
+// mirrorBucketConfigFiles are the configurations copied by mirror
+// --with-metadata, replication is left out as it points to the remote
+// targets of the source.
+var mirrorBucketConfigFiles = []string{
+	bucketPolicyFile, bucketTagsFile, bucketEncryptionFile,
+	bucketLifecycleFile, bucketNotificationFile,
+}
+
+// copyBucketConfigs copies the versioning and the configurations of a
+// bucket to another, it returns the names of the configurations copied.
+func copyBucketConfigs(ctx context.Context, srcBucketURL, dstBucketURL string) ([]string, *probe.Error) {
+	srcClnt, err := newClient(srcBucketURL)
+	if err != nil {
+		return nil, err.Trace(srcBucketURL)
+	}
+	dstClnt, err := newClient(dstBucketURL)
+	if err != nil {
+		return nil, err.Trace(dstBucketURL)
+	}
+	var copied []string
+	versioned, err := copyBucketVersioning(ctx, srcClnt, dstClnt)
+	if err != nil && !isBucketConfigNotSet(err) {
+		return nil, err.Trace(srcBucketURL, dstBucketURL)
+	}
+	if err == nil && versioned {
+		copied = append(copied, "versioning")
+	}
+
+	configs, err := getBucketConfigs(ctx, srcBucketURL)
+	if err != nil {
+		return copied, err
+	}
+	for name := range configs {
+		if !mirrorBucketConfigs(name) {
+			delete(configs, name)
+		}
+	}
+	applied, err := setBucketConfigs(ctx, dstBucketURL, func(name string, config interface{}) (bool, *probe.Error) {
+		c, ok := configs[name]
+		if !ok {
+			return false, nil
+		}
+		// Configurations are copied through their exported form.
+		data, e := json.Marshal(c)
+		if e != nil {
+			return false, probe.NewError(e)
+		}
+		if e = json.Unmarshal(data, config); e != nil {
+			return false, probe.NewError(e)
+		}
+		return true, nil
+	}, nil)
+	return append(copied, applied...), err
+}
+
+// mirrorBucketConfigs tells if a configuration is copied by mirror
+// --with-metadata.
+func mirrorBucketConfigs(name string) bool {
+	for _, n := range mirrorBucketConfigFiles {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mirrorBucketURLs returns the URLs of the buckets of the source alias
+// mirrored to the target alias, as pairs of source and target URLs.
+func mirrorBucketURLs(ctx context.Context, srcURL, dstURL string, srcClnt Client, f *mirrorBucketFilter) ([][2]string, *probe.Error) {
+	buckets, err := srcClnt.ListBuckets(ctx)
+	if err != nil {
+		return nil, err.Trace(srcURL)
+	}
+	var urls [][2]string
+	for _, b := range buckets {
+		bucket := strings.Trim(b.URL.Path, string(b.URL.Separator))
+		if !f.skip(bucket) {
+			urls = append(urls, [2]string{path.Join(srcURL, bucket), path.Join(dstURL, bucket)})
+		}
+	}
+	return urls, nil
+}
+
+// isMirrorBucketRoots reports whether the source and target of a mirror
+// are both aliases or both buckets, whose configurations can be copied.
+func isMirrorBucketRoots(srcURL, dstURL *ClientURL) bool {
+	if srcURL.Type != objectStorage || dstURL.Type != objectStorage {
+		return false
+	}
+	srcBucket, srcObject := url2BucketAndObject(srcURL)
+	dstBucket, dstObject := url2BucketAndObject(dstURL)
+	if srcObject != "" || dstObject != "" {
+		return false
+	}
+	return (srcBucket == "") == (dstBucket == "")
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestMirrorBucketFilter(t *testing.T) {
+	var none *mirrorBucketFilter
+	if none.skip("bucket") || none.skipSuffix("bucket/object", "/") {
+		t.Fatal("expected no bucket to be skipped without a filter")
+	}
+
+	f := &mirrorBucketFilter{
+		include:  []string{"logs-*", "data"},
+		exclude:  []string{"logs-tmp*"},
+		excluded: map[string]bool{"logs-eu": true},
+	}
+	testCases := []struct {
+		suffix string
+		skip   bool
+	}{
+		{"logs-us/2023/a.log", false},
+		{"/logs-us/", false},
+		{"data/object", false},
+		{"data2/object", true},
+		{"logs-tmp1/a.log", true},
+		{"logs-eu/a.log", true},
+		{"other", true},
+	}
+	for i, testCase := range testCases {
+		if skip := f.skipSuffix(testCase.suffix, "/"); skip != testCase.skip {
+			t.Fatalf("Test %d: %s: expected skip %t, got %t", i+1, testCase.suffix, testCase.skip, skip)
+		}
+	}
+}
+
+func TestBucketConfigExcluded(t *testing.T) {
+	excludeRegions := []string{"eu-west-1"}
+	excludeTags := map[string]string{"env": "dev", "temporary": ""}
+	testCases := []struct {
+		region   string
+		tags     map[string]string
+		expected bool
+	}{
+		{"us-east-1", nil, false},
+		{"EU-WEST-1", nil, true},
+		{"us-east-1", map[string]string{"env": "prod"}, false},
+		{"us-east-1", map[string]string{"env": "dev"}, true},
+		{"us-east-1", map[string]string{"temporary": ""}, true},
+		{"us-east-1", map[string]string{"temporary": "no"}, false},
+	}
+	for i, testCase := range testCases {
+		if excluded := bucketConfigExcluded(testCase.region, testCase.tags, excludeRegions, excludeTags); excluded != testCase.expected {
+			t.Fatalf("Test %d: expected %t, got %t", i+1, testCase.expected, excluded)
+		}
+	}
+}
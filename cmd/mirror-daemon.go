@@ -0,0 +1,209 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// mirrorDaemonFlags run 'mirror' as a long running replication job.
+var mirrorDaemonFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "daemon",
+		Usage: "run indefinitely, mirroring again every --interval, or on events with --watch",
+	},
+	cli.StringFlag{
+		Name:  "interval",
+		Usage: "with --daemon, duration between the end of a mirror and the start of the next one",
+		Value: "5m",
+	},
+}
+
+// globalMirrorDaemon is the state of 'mirror --daemon' reported to
+// Prometheus and by the health endpoint.
+var globalMirrorDaemon = &mirrorDaemon{}
+
+var (
+	mirrorCopiedObjects = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "mc_mirror_copied_objects_total",
+		Help: "The total number of objects copied by mirror",
+	})
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "mc_mirror_pending_objects",
+		Help: "The number of objects queued to mirror and not yet copied or removed",
+	}, func() float64 {
+		return float64(globalMirrorDaemon.pending())
+	})
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "mc_mirror_lag_seconds",
+		Help: "The number of seconds since the target was last in sync with the source, with --daemon",
+	}, func() float64 {
+		return globalMirrorDaemon.lag(time.Now()).Seconds()
+	})
+)
+
+// parseMirrorInterval parses the --interval flag of 'mirror --daemon'.
+func parseMirrorInterval(cliCtx *cli.Context) (time.Duration, *probe.Error) {
+	value := cliCtx.String("interval")
+	d, e := time.ParseDuration(value)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(value)
+	}
+	if d <= 0 {
+		return 0, errInvalidArgument().Trace(value)
+	}
+	return d, nil
+}
+
+// checkMirrorDaemonSyntax returns why --daemon and --interval cannot be
+// used with the other flags passed, empty if they can.
+func checkMirrorDaemonSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.Bool("daemon") {
+		if cliCtx.IsSet("interval") {
+			return "--interval can only be used with --daemon."
+		}
+		return ""
+	}
+	if cliCtx.Bool("dry-run") || cliCtx.Bool("fake") {
+		return "--daemon cannot be used with --dry-run."
+	}
+	if _, err := parseMirrorInterval(cliCtx); err != nil {
+		return "Unable to parse --interval, expected a positive duration, e.g. '5m'."
+	}
+	return ""
+}
+
+// mirrorDaemon keeps track of the runs of 'mirror --daemon': the job
+// mirroring now, and when the target was last in sync with the source.
+type mirrorDaemon struct {
+	mu         sync.Mutex
+	enabled    bool
+	started    time.Time
+	job        *mirrorJob
+	lastSync   time.Time
+	lastRun    time.Time
+	lastFailed bool
+}
+
+// start enables the reports of the daemon.
+func (d *mirrorDaemon) start(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.enabled = true
+	d.started = now
+}
+
+// setJob sets the job mirroring now.
+func (d *mirrorDaemon) setJob(mj *mirrorJob) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.job = mj
+}
+
+// runDone records the end of a mirror started at start, the target is
+// in sync as of the start of a run without failures.
+func (d *mirrorDaemon) runDone(start, now time.Time, failed bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastRun = now
+	d.lastFailed = failed
+	if !failed {
+		d.lastSync = start
+	}
+}
+
+// pending returns the number of objects queued by the current job.
+func (d *mirrorDaemon) pending() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.job == nil {
+		return 0
+	}
+	return d.job.parallel.pendingTasks()
+}
+
+// lag returns how long ago the target was last in sync with the
+// source. A watching job is in sync once it mirrored every object of
+// its first scan and has no event left to mirror.
+func (d *mirrorDaemon) lag(now time.Time) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.enabled {
+		return 0
+	}
+	if mj := d.job; mj != nil && mj.opts.isWatch && atomic.LoadUint32(&mj.scanned) == 1 && mj.parallel.pendingTasks() == 0 {
+		d.lastSync = now
+	}
+	since := d.lastSync
+	if since.IsZero() {
+		since = d.started
+	}
+	return now.Sub(since)
+}
+
+// mirrorHealthMessage is the reply of the health endpoint of 'mirror
+// --daemon'.
+type mirrorHealthMessage struct {
+	Status     string    `json:"status"`
+	LastSync   time.Time `json:"lastSync,omitempty"`
+	LastRun    time.Time `json:"lastRun,omitempty"`
+	LagSeconds float64   `json:"lagSeconds"`
+	Pending    int64     `json:"pending"`
+}
+
+// health returns the state of the daemon, unhealthy after a failed run.
+func (d *mirrorDaemon) health(now time.Time) mirrorHealthMessage {
+	msg := mirrorHealthMessage{
+		Status:     "ok",
+		LagSeconds: d.lag(now).Seconds(),
+		Pending:    d.pending(),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	msg.LastSync = d.lastSync
+	msg.LastRun = d.lastRun
+	if d.lastFailed {
+		msg.Status = "failed"
+	}
+	return msg
+}
+
+// ServeHTTP replies to health checks with the state of the daemon as
+// JSON, with 503 Service Unavailable after a failed run.
+func (d *mirrorDaemon) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	msg := d.health(time.Now())
+	data, e := json.Marshal(msg)
+	if e != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if msg.Status != "ok" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(data)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckMirrorDaemonSyntax(t *testing.T) {
+	flags := append(append([]cli.Flag{}, mirrorFlags...), mirrorDaemonFlags...)
+	testCases := []struct {
+		args     []string
+		expected string
+		interval time.Duration
+	}{
+		{nil, "", 5 * time.Minute},
+		{[]string{"--daemon"}, "", 5 * time.Minute},
+		{[]string{"--daemon", "--interval", "30s"}, "", 30 * time.Second},
+		{[]string{"--daemon", "--watch"}, "", 5 * time.Minute},
+		{[]string{"--interval", "30s"}, "--interval can only be used with --daemon.", 0},
+		{[]string{"--daemon", "--dry-run"}, "--daemon cannot be used with --dry-run.", 0},
+		{[]string{"--daemon", "--interval", "often"}, "Unable to parse --interval, expected a positive duration, e.g. '5m'.", 0},
+		{[]string{"--daemon", "--interval", "0s"}, "Unable to parse --interval, expected a positive duration, e.g. '5m'.", 0},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, flags, testCase.args...)
+		if msg := checkMirrorDaemonSyntax(cliCtx); msg != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, msg)
+			continue
+		}
+		if testCase.expected != "" {
+			continue
+		}
+		if interval, err := parseMirrorInterval(cliCtx); err != nil || interval != testCase.interval {
+			t.Errorf("Test %d: expected an interval of %s, got %s (%v)", i+1, testCase.interval, interval, err)
+		}
+	}
+}
+
+func TestMirrorDaemonLag(t *testing.T) {
+	now := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	d := &mirrorDaemon{}
+	if d.lag(now) != 0 {
+		t.Fatal("expected no lag before the daemon starts")
+	}
+	d.start(now)
+
+	// The lag counts from the start until a run succeeds.
+	if lag := d.lag(now.Add(time.Minute)); lag != time.Minute {
+		t.Fatalf("expected a lag of 1m, got %s", lag)
+	}
+	d.runDone(now.Add(time.Minute), now.Add(2*time.Minute), true)
+	if lag := d.lag(now.Add(3 * time.Minute)); lag != 3*time.Minute {
+		t.Fatalf("expected a failed run to keep the lag, got %s", lag)
+	}
+	d.runDone(now.Add(4*time.Minute), now.Add(5*time.Minute), false)
+	if lag := d.lag(now.Add(6 * time.Minute)); lag != 2*time.Minute {
+		t.Fatalf("expected the lag to count from the start of the last run, got %s", lag)
+	}
+
+	// A watching job is in sync once its first scan was mirrored.
+	mj := &mirrorJob{parallel: &ParallelManager{}, opts: mirrorOptions{isWatch: true}}
+	d.setJob(mj)
+	atomic.StoreInt64(&mj.parallel.pending, 2)
+	if d.pending() != 2 {
+		t.Fatalf("expected 2 pending objects, got %d", d.pending())
+	}
+	if lag := d.lag(now.Add(7 * time.Minute)); lag != 3*time.Minute {
+		t.Fatalf("expected a lag until the first scan is done, got %s", lag)
+	}
+	atomic.StoreUint32(&mj.scanned, 1)
+	atomic.StoreInt64(&mj.parallel.pending, 0)
+	if lag := d.lag(now.Add(8 * time.Minute)); lag != 0 {
+		t.Fatalf("expected no lag without pending objects, got %s", lag)
+	}
+}
+
+func TestMirrorDaemonHealth(t *testing.T) {
+	d := &mirrorDaemon{}
+	d.start(time.Now())
+
+	check := func(code int, status string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+		if w.Code != code {
+			t.Fatalf("expected %d, got %d", code, w.Code)
+		}
+		var msg mirrorHealthMessage
+		if e := json.Unmarshal(w.Body.Bytes(), &msg); e != nil {
+			t.Fatal(e)
+		}
+		if msg.Status != status {
+			t.Fatalf("expected %s, got %s", status, msg.Status)
+		}
+	}
+	check(http.StatusOK, "ok")
+	d.runDone(time.Now(), time.Now(), true)
+	check(http.StatusServiceUnavailable, "failed")
+	d.runDone(time.Now(), time.Now(), false)
+	check(http.StatusOK, "ok")
+}
@@ -27,6 +27,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fatih/color"
@@ -84,6 +85,10 @@ var (
 			Name:  "md5",
 			Usage: "force all upload(s) to calculate md5sum checksum",
 		},
+		cli.StringFlag{
+			Name:  "checksum",
+			Usage: "upload with an additional checksum, one of CRC32C, SHA1, SHA256 or CRC64NVME",
+		},
 		cli.BoolFlag{
 			Name:   "multi-master",
 			Usage:  "enable multi-master multi-site setup",
@@ -93,6 +98,19 @@ var (
 			Name:  "active-active",
 			Usage: "enable active-active multi-site setup",
 		},
+		cli.BoolFlag{
+			Name:  "two-way",
+			Usage: "synchronize both directions, copying objects changed on either side since the last run",
+		},
+		cli.StringFlag{
+			Name:  "conflict",
+			Value: mirrorConflictNewer,
+			Usage: "with --two-way, side kept for objects changed on both sides: 'newer', 'source', 'target' or 'skip'",
+		},
+		cli.StringFlag{
+			Name:  "state-db",
+			Usage: "save the objects of the target to a local file at PATH, later runs read it instead of listing the target",
+		},
 		cli.BoolFlag{
 			Name:  "disable-multipart",
 			Usage: "disable multipart upload feature",
@@ -101,6 +119,30 @@ var (
 			Name:  "exclude",
 			Usage: "exclude object(s) that match specified object name pattern",
 		},
+		cli.StringSliceFlag{
+			Name:  "include-bucket",
+			Usage: "when mirroring all buckets of an alias, only mirror the bucket(s) that match specified bucket name pattern",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-bucket",
+			Usage: "when mirroring all buckets of an alias, exclude the bucket(s) that match specified bucket name pattern",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-bucket-region",
+			Usage: "when mirroring all buckets of an alias, exclude the bucket(s) of specified region",
+		},
+		cli.StringSliceFlag{
+			Name:  "exclude-bucket-tag",
+			Usage: "when mirroring all buckets of an alias, exclude the bucket(s) tagged with specified KEY=VALUE",
+		},
+		cli.BoolFlag{
+			Name:  "with-metadata",
+			Usage: "copy the versioning, policy, tags, encryption, lifecycle and notification configuration(s) of the bucket(s) to the target",
+		},
+		cli.BoolFlag{
+			Name:  "preserve-bucket-config",
+			Usage: "create missing target bucket(s) with the versioning and locking configuration of their source",
+		},
 		cli.StringFlag{
 			Name:  "older-than",
 			Usage: "filter object(s) older than value in duration string (e.g. 7d10h31s)",
@@ -114,9 +156,10 @@ var (
 			Usage: "specify storage class for new object(s) on target",
 		},
 		cli.StringFlag{
-			Name:  "encrypt",
+			Name:  "encrypt, enc-s3",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
 		},
+		encKMSFlag,
 		cli.StringFlag{
 			Name:  "attr",
 			Usage: "add custom metadata for all objects",
@@ -125,6 +168,15 @@ var (
 			Name:  "monitoring-address",
 			Usage: "if specified, a new prometheus endpoint will be created to report mirroring activity. (eg: localhost:8081)",
 		},
+		cli.BoolFlag{
+			Name:  "session-report",
+			Usage: "report every object synchronized and deleted when a --watch session ends",
+		},
+		cli.IntFlag{
+			Name:  "session-report-max",
+			Usage: "number of objects listed in the session report, the others are saved to a file",
+			Value: 1000,
+		},
 	}
 )
 
@@ -135,7 +187,9 @@ var mirrorCmd = cli.Command{
 	Action:       mainMirror,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(mirrorFlags, ioFlags...), globalFlags...),
+	Flags: joinFlags(mirrorFlags,
+		[]cli.Flag{statsFlag, stallTimeoutFlag, settleDurationFlag, filterFlag, compressFlag, cseKeyFlag, serverSideFlag},
+		mirrorWatchFlags, mirrorDaemonFlags, sizedWorkersFlags, objectLockFlags, retryFlags, referenceFileFlags, ioFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -146,8 +200,13 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}
 ENVIRONMENT VARIABLES:
-   MC_ENCRYPT:      list of comma delimited prefixes
-   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+   MC_ENCRYPT:              list of comma delimited prefixes
+   MC_ENCRYPT_KEY:          list of comma delimited prefix=secret values
+   MC_ENC_KMS:              list of comma delimited prefix=keyid values
+   MC_METRICS_ADDRESS:      address of a Prometheus endpoint reporting requests, latencies, bytes, retries
+                            and queued transfers, e.g. "localhost:8081"
+   MC_SERVER_SIDE_WORKERS:  number of objects copied in parallel by --server-side, "256" by default
+   MC_CSE_PASSPHRASE:       passphrase of the OpenPGP secret keys of --cse-key
 
 EXAMPLES:
   01. Mirror a bucket recursively from MinIO cloud storage to a bucket on Amazon S3 cloud storage.
@@ -202,6 +261,86 @@ EXAMPLES:
   16. Cross mirror between sites in a active-active deployment.
       Site-A: {{.Prompt}} {{.HelpName}} --active-active siteA siteB
       Site-B: {{.Prompt}} {{.HelpName}} --active-active siteB siteA
+
+  17. Mirror a local folder to Amazon S3 cloud storage, uploading every object with a CRC32C checksum.
+      {{.Prompt}} {{.HelpName}} --checksum CRC32C backup/ s3/archive
+
+  18. Mirror a local folder to Amazon S3 cloud storage and print a summary of the run at the end.
+      {{.Prompt}} {{.HelpName}} --stats backup/ s3/archive
+
+  19. Mirror a local folder to Amazon S3 cloud storage, retrying objects whose upload makes no progress for a minute.
+      {{.Prompt}} {{.HelpName}} --stall-timeout 1m backup/ s3/archive
+
+  20. Mirror only images smaller than 10MiB from a local folder to Amazon S3 cloud storage.
+      {{.Prompt}} {{.HelpName}} --filter '(name ~ "*.jpg" || name ~ "*.png") && size < 10MiB' photos/ s3/photos
+
+  21. Continuously mirror a bucket to a DR site and, once stopped, report every object synchronized and deleted
+      during the replication window as JSON, listing at most 10000 objects and saving the others to a file.
+      {{.Prompt}} {{.HelpName}} --watch --remove --session-report --session-report-max 10000 --json play/photos s3/dr-photos > window.json
+
+  22. Continuously mirror a bucket and report its throughput, request latencies and retries to Prometheus.
+      {{.Prompt}} MC_METRICS_ADDRESS=localhost:8081 {{.HelpName}} --watch play/photos s3/backup-photos
+
+  23. Mirror a local project, skipping the files and folders listed in its gitignore-style '.mcignore' files.
+      {{.Prompt}} {{.HelpName}} ~/myproject/ s3/mybucket/myproject/
+
+  24. Continuously mirror a folder written by an active producer, copying files once unmodified for 30 seconds.
+      {{.Prompt}} {{.HelpName}} --watch --settle-duration 30s /var/spool/exports/ s3/exports/
+
+  25. Synchronize a local folder and a bucket both ways, propagating removals and keeping the bucket's version of conflicts.
+      {{.Prompt}} {{.HelpName}} --two-way --remove --conflict target ~/Documents/ s3/documents/
+
+  26. Mirror a bucket to a local folder, limiting the downloads of all objects together to 50MiB/s.
+      {{.Prompt}} {{.HelpName}} --limit-download 50MiB/s s3/media/ /mnt/media/
+
+  27. Mirror a local folder to a large bucket every night, reading the objects of the bucket from a local state
+      database saved by the previous run instead of listing the bucket.
+      {{.Prompt}} {{.HelpName}} --state-db ~/.mc/archive.db backup/ s3/archive
+
+  28. Mirror a local folder of CSV exports to a bucket, compressing the uploaded objects with gzip.
+      {{.Prompt}} {{.HelpName}} --compress gzip exports/ s3/exports
+
+  29. Mirror a bucket to a remote site, retrying every failed object up to 3 times and logging those which
+      still failed.
+      {{.Prompt}} {{.HelpName}} --retry 3 --failed-log failed.txt s3/photos dr/photos
+
+  30. Mirror the files of a folder not modified since a marker object was uploaded.
+      {{.Prompt}} {{.HelpName}} --older-than-file s3/exports/.marker exports/ s3/exports
+
+  31. Mirror a bucket to another bucket of the same server, copying the objects by the server only.
+      {{.Prompt}} {{.HelpName}} --server-side myminio/photos myminio/photos-backup
+
+  32. Mirror a locked bucket to another locked bucket, keeping the retention and legal hold of the objects.
+      {{.Prompt}} {{.HelpName}} --preserve-retention --preserve-legalhold s3/records myminio/records
+
+  33. Continuously mirror the log files of a folder written by active services, copying a file once it was not
+      written for 10 seconds.
+      {{.Prompt}} {{.HelpName}} --watch --watch-suffix .log --debounce 10s /var/log/services/ s3/logs/
+
+  34. Mirror a bucket to a DR site every 10 minutes, reporting the objects pending, copied and failed and the
+      replication lag to Prometheus, and the health of the job, on localhost:8081.
+      {{.Prompt}} {{.HelpName}} --daemon --interval 10m --monitoring-address localhost:8081 s3/photos dr/photos
+
+  35. Mirror a dataset mixing millions of small files with large archives, copying the small files with 64 workers
+      and the files of 256MiB or more with 4 workers.
+      {{.Prompt}} {{.HelpName}} --small-object-workers 64 --large-object-workers 4 --large-object-size 256MiB /data/ s3/data/
+
+  36. Mirror a local folder to a bucket, encrypting the objects on the client with a local key.
+      {{.Prompt}} {{.HelpName}} --cse-key ~/.mc/backup.key /home/user/documents/ s3/documents
+
+  37. Mirror a local folder to a bucket, encrypting the objects with the KMS key "my-minio-key" on the server.
+      {{.Prompt}} {{.HelpName}} --enc-kms "s3/documents=my-minio-key" /home/user/documents/ s3/documents
+
+  38. Mirror all buckets of an alias except the temporary ones and those tagged "env=dev", creating the missing
+      buckets with the versioning and locking configuration of their source.
+      {{.Prompt}} {{.HelpName}} --exclude-bucket "tmp-*" --exclude-bucket-tag "env=dev" --preserve-bucket-config s3 minio
+
+  39. Mirror the buckets of an alias whose name starts with "logs-", leaving out those in region "eu-west-1".
+      {{.Prompt}} {{.HelpName}} --include-bucket "logs-*" --exclude-bucket-region eu-west-1 s3 minio
+
+  40. Duplicate an environment, mirroring the objects of all buckets along with their versioning, policy, tags,
+      encryption, lifecycle and notification configurations.
+      {{.Prompt}} {{.HelpName}} --with-metadata s3 minio
 `,
 }
 
@@ -249,12 +388,30 @@ type mirrorJob struct {
 	// channel for status messages
 	statusCh chan URLs
 
+	// Outcome of every object, printed with --stats
+	stats *bulkStats
+
+	// Objects skipped by --settle-duration in watch mode, sent to
+	// settledCh once they settled.
+	settling  sync.Map
+	settledCh chan URLs
+
+	// Objects written again within --debounce in watch mode.
+	debouncer *watchDebouncer
+
+	// Set to 1 once every object of the first scan was queued.
+	scanned uint32
+
 	TotalObjects int64
 	TotalBytes   int64
 
 	sourceURL string
 	targetURL string
 
+	// Path of the source, whose top level prefixes balance the tasks
+	// of --small-object-workers and --large-object-workers.
+	sourceRoot string
+
 	opts mirrorOptions
 }
 
@@ -266,10 +423,14 @@ type mirrorMessage struct {
 	Size       int64  `json:"size"`
 	TotalCount int64  `json:"totalCount"`
 	TotalSize  int64  `json:"totalSize"`
+	Checksum   string `json:"checksum,omitempty"`
 }
 
 // String colorized mirror message
 func (m mirrorMessage) String() string {
+	if m.Checksum != "" {
+		return console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s` (%s)", m.Source, m.Target, m.Checksum))
+	}
 	return console.Colorize("Mirror", fmt.Sprintf("`%s` -> `%s`", m.Source, m.Target))
 }
 
@@ -426,6 +587,17 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 		return sURLs.WithError(nil)
 	}
 
+	// Objects which may still be written are left for the next pass.
+	if settleRemaining(sURLs.SourceContent.Time, mj.opts.settleDuration) > 0 {
+		if mj.opts.isWatch {
+			mj.retryOnceSettled(ctx, sURLs)
+		}
+		return sURLs.WithError(probe.NewError(ObjectSettling{
+			Object:  sURLs.SourceContent.URL.String(),
+			ModTime: sURLs.SourceContent.Time,
+		}))
+	}
+
 	sourceAlias := sURLs.SourceAlias
 	sourceURL := sURLs.SourceContent.URL
 	targetAlias := sURLs.TargetAlias
@@ -457,21 +629,41 @@ func (mj *mirrorJob) doMirror(ctx context.Context, sURLs URLs) URLs {
 
 	sourcePath := filepath.ToSlash(filepath.Join(sourceAlias, sourceURL.Path))
 	targetPath := filepath.ToSlash(filepath.Join(targetAlias, targetURL.Path))
-	mj.status.PrintMsg(mirrorMessage{
+	msg := mirrorMessage{
 		Source:     sourcePath,
 		Target:     targetPath,
 		Size:       length,
 		TotalCount: sURLs.TotalCount,
 		TotalSize:  sURLs.TotalSize,
-	})
+	}
+	if mj.opts.checksum == "" {
+		mj.status.PrintMsg(msg)
+	}
 	sURLs.MD5 = mj.opts.md5
 	sURLs.DisableMultipart = mj.opts.disableMultipart
+	sURLs.Checksum = mj.opts.checksum
+	sURLs.Compress = mj.opts.compress
+	sURLs.CSEKey = mj.opts.cseKey
+	sURLs.ServerSide = mj.opts.serverSide
+	sURLs.PreserveRetention = mj.opts.preserveRetention
+	sURLs.PreserveLegalHold = mj.opts.preserveLegalHold
+	sURLs.StallTimeout = mj.opts.stallTimeout
+	sURLs.Retries = mj.opts.retries
+	sURLs.RetryDelay = mj.opts.retryDelay
 
 	now := time.Now()
-	ret := uploadSourceToTargetURL(ctx, sURLs, mj.status, mj.opts.encKeyDB, mj.opts.isMetadata, false)
+	ret := uploadSourceToTargetURLWithRetry(ctx, sURLs, mj.status, mj.opts.encKeyDB, mj.opts.isMetadata, false)
 	if ret.Error == nil {
 		durationMs := time.Since(now).Milliseconds()
 		mirrorReplicationDurations.With(prometheus.Labels{"object_size": convertSizeToTag(sURLs.SourceContent.Size)}).Observe(float64(durationMs))
+
+		// Checksummed uploads are reported once the checksum is known.
+		if mj.opts.checksum != "" {
+			if ret.ChecksumValue != "" {
+				msg.Checksum = mj.opts.checksum + ":" + ret.ChecksumValue
+			}
+			mj.status.PrintMsg(msg)
+		}
 	}
 	return ret
 }
@@ -500,11 +692,25 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 
 			switch {
 			case sURLs.SourceContent != nil:
-				if isErrIgnored(sURLs.Error) {
+				if isErrSourceSettling(sURLs.Error) {
+					printMsg(settlingMessage{
+						Source:       sURLs.SourceContent.URL.String(),
+						Target:       sURLs.TargetContent.URL.String(),
+						LastModified: sURLs.SourceContent.Time,
+					})
+					ignoreErr = true
+				} else if isErrConflict(sURLs.Error) {
+					printMsg(conflictMessage{
+						Source: sURLs.SourceContent.URL.String(),
+						Target: sURLs.TargetContent.URL.String(),
+					})
+					ignoreErr = true
+				} else if isErrIgnored(sURLs.Error) {
 					ignoreErr = true
 				} else {
 					errorIf(sURLs.Error.Trace(sURLs.SourceContent.URL.String()),
 						fmt.Sprintf("Failed to copy `%s`.", sURLs.SourceContent.URL.String()))
+					mj.opts.failed.Add(sURLs)
 				}
 			case sURLs.TargetContent != nil:
 				// When sURLs.SourceContent is nil, we know that we have an error related to removing
@@ -522,7 +728,10 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 				}
 			}
 
-			if !ignoreErr {
+			if ignoreErr {
+				mj.stats.Skipped()
+			} else {
+				mj.stats.Failed()
 				mirrorFailedOps.Inc()
 				errDuringMirror = true
 				// Quit mirroring if --watch and --active-active are not passed
@@ -537,10 +746,18 @@ func (mj *mirrorJob) monitorMirrorStatus(cancel context.CancelFunc) (errDuringMi
 
 		if sURLs.SourceContent != nil {
 			mirrorTotalUploadedBytes.Add(float64(sURLs.SourceContent.Size))
+			mirrorCopiedObjects.Inc()
+			mj.stats.Succeeded(sURLs.SourceContent.Size)
+			if sURLs.TargetContent != nil {
+				targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
+				mj.opts.report.Add(mirrorReportSynced, targetPath, sURLs.SourceContent.Size)
+			}
 		} else if sURLs.TargetContent != nil {
+			mj.stats.Succeeded(0)
 			// Construct user facing message and path.
 			targetPath := filepath.ToSlash(filepath.Join(sURLs.TargetAlias, sURLs.TargetContent.URL.Path))
 			mj.status.PrintMsg(rmMessage{Key: targetPath})
+			mj.opts.report.Add(mirrorReportDeleted, targetPath, 0)
 		}
 	}
 
@@ -580,6 +797,13 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 		if matchExcludeOptions(mj.opts.excludeOptions, sourceSuffix) {
 			continue
 		}
+		if mj.opts.buckets.skipSuffix(sourceSuffix, string(sourceURL.Separator)) {
+			continue
+		}
+		isObjectEvent := strings.HasPrefix(string(event.Type), "s3:ObjectCreated:") || event.Type == notification.ObjectRemovedDelete
+		if isObjectEvent && !mj.opts.watchFilter.Match(sourceSuffix) {
+			continue
+		}
 
 		targetPath := urlJoinPath(mj.targetURL, sourceSuffix)
 
@@ -614,14 +838,26 @@ func (mj *mirrorJob) watchMirrorEvents(ctx context.Context, events []EventInfo)
 				// to avoid copying it.
 				continue
 			}
-			mj.parallel.queueTask(func() URLs {
-				return mj.doMirrorWatch(ctx, targetPath, tgtSSE, mirrorURL)
-			}, mirrorURL.SourceContent.Size)
+			queueCopy := func() {
+				mj.parallel.queueTask(func() URLs {
+					return mj.doMirrorWatch(ctx, targetPath, tgtSSE, mirrorURL)
+				}, mirrorURL.SourceContent.Size)
+			}
+			if mj.debouncer != nil {
+				// Only the last event of an object written again
+				// within the quiet period is mirrored.
+				mj.debouncer.Add(sourceURL.String(), queueCopy)
+				continue
+			}
+			queueCopy()
 		} else if event.Type == notification.ObjectRemovedDelete {
 			if targetAlias != "" && strings.Contains(event.UserAgent, uaMirrorAppName+":"+targetAlias) {
 				// Ignore delete cascading delete events if cyclical.
 				continue
 			}
+			if mj.debouncer != nil {
+				mj.debouncer.Cancel(sourceURL.String())
+			}
 			mirrorURL := URLs{
 				SourceAlias:      sourceAlias,
 				SourceContent:    nil,
@@ -672,6 +908,10 @@ func (mj *mirrorJob) watchMirror(ctx context.Context) {
 				return
 			}
 			mj.watchMirrorEvents(ctx, events)
+		case sURLs := <-mj.settledCh:
+			mj.queueSettled(ctx, sURLs)
+		case queueCopy := <-mj.debouncer.Fired():
+			queueCopy()
 		case err, ok := <-mj.watcher.Errors():
 			if !ok {
 				return
@@ -689,10 +929,51 @@ func (mj *mirrorJob) watchMirror(ctx context.Context) {
 			}
 		case <-ctx.Done():
 			return
+		case <-mj.stopCh:
+			return
 		}
 	}
 }
 
+// retryOnceSettled sends an object skipped by --settle-duration to
+// settledCh once it settled, an object skipped again meanwhile is sent
+// only once.
+func (mj *mirrorJob) retryOnceSettled(ctx context.Context, sURLs URLs) {
+	if _, pending := mj.settling.LoadOrStore(sURLs.SourceContent.URL.String(), true); pending {
+		return
+	}
+	time.AfterFunc(settleRemaining(sURLs.SourceContent.Time, mj.opts.settleDuration), func() {
+		select {
+		case mj.settledCh <- sURLs:
+		case <-ctx.Done():
+		case <-mj.stopCh:
+		}
+	})
+}
+
+// queueSettled mirrors an object which settled, with its current size
+// and modification time, it is skipped again if it was modified since.
+func (mj *mirrorJob) queueSettled(ctx context.Context, sURLs URLs) {
+	mj.settling.Delete(sURLs.SourceContent.URL.String())
+
+	sourceContent := *sURLs.SourceContent
+	sURLs.SourceContent = &sourceContent
+	if err := statCopySource(ctx, &sURLs); err != nil {
+		switch err.ToGoError().(type) {
+		case ObjectMissing, PathNotFound:
+			// Removed meanwhile, e.g. a temporary file.
+			return
+		}
+		mj.parallel.queueTask(func() URLs {
+			return sURLs.WithError(err)
+		}, 0)
+		return
+	}
+	mj.parallel.queueTask(func() URLs {
+		return mj.doMirror(ctx, sURLs)
+	}, sURLs.SourceContent.Size)
+}
+
 func (mj *mirrorJob) watchURL(ctx context.Context, sourceClient Client) *probe.Error {
 	return mj.watcher.Join(ctx, sourceClient, true)
 }
@@ -719,6 +1000,9 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 				if isNewer(sURLs.SourceContent.Time, mj.opts.newerThan) {
 					continue
 				}
+				if !mj.opts.filter.Match(sURLs.SourceContent) {
+					continue
+				}
 			}
 
 			if sURLs.SourceContent != nil {
@@ -734,12 +1018,13 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 			sURLs.TotalSize = mj.status.Get()
 
 			if sURLs.SourceContent != nil {
-				mj.parallel.queueTask(func() URLs {
-					return mj.doMirror(ctx, sURLs)
+				prefix := topPrefix(strings.TrimPrefix(sURLs.SourceContent.URL.Path, mj.sourceRoot))
+				mj.parallel.queuePrefixedTask(prefix, func() URLs {
+					return mj.syncDone(ctx, mj.doMirror(ctx, sURLs))
 				}, sURLs.SourceContent.Size)
 			} else if sURLs.TargetContent != nil && mj.opts.isRemove {
 				mj.parallel.queueTask(func() URLs {
-					return mj.doRemove(ctx, sURLs)
+					return mj.syncDone(ctx, mj.doRemove(ctx, sURLs))
 				}, 0)
 			}
 		case <-ctx.Done():
@@ -750,6 +1035,21 @@ func (mj *mirrorJob) startMirror(ctx context.Context) {
 	}
 }
 
+// syncDone records an object copied or removed by 'mirror --two-way'
+// as synchronized, or in the --state-db.
+func (mj *mirrorJob) syncDone(ctx context.Context, sURLs URLs) URLs {
+	if sURLs.Error != nil || mj.opts.isFake {
+		return sURLs
+	}
+	if mj.opts.twoWay != nil {
+		mj.opts.twoWay.done(ctx, sURLs)
+	}
+	if mj.opts.stateDB != nil {
+		mj.opts.stateDB.done(ctx, sURLs)
+	}
+	return sURLs
+}
+
 // when using a struct for copying, we could save a lot of passing of variables
 func (mj *mirrorJob) mirror(ctx context.Context) bool {
 	var wg sync.WaitGroup
@@ -770,6 +1070,19 @@ func (mj *mirrorJob) mirror(ctx context.Context) bool {
 		defer wg.Done()
 		// startMirror locks and blocks itself.
 		mj.startMirror(ctx)
+		atomic.StoreUint32(&mj.scanned, 1)
+	}()
+
+	// Stop queueing objects once interrupted, the objects being
+	// mirrored are finished.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-enableGracefulStop():
+			close(mj.stopCh)
+		case <-done:
+		}
 	}()
 
 	// Close statusCh when both watch & mirror quits
@@ -791,9 +1104,24 @@ func newMirrorJob(srcURL, dstURL string, opts mirrorOptions) *mirrorJob {
 		opts:      opts,
 		statusCh:  make(chan URLs),
 		watcher:   NewWatcher(UTCNow()),
+		stats:     newBulkStats(),
+		settledCh: make(chan URLs),
 	}
 
-	mj.parallel = newParallelManager(mj.statusCh)
+	if opts.debounce > 0 {
+		mj.debouncer = newWatchDebouncer(opts.debounce, mj.stopCh)
+	}
+
+	switch {
+	case opts.serverSide:
+		mj.parallel = newServerSideParallelManager(mj.statusCh, serverSideWorkers())
+	case opts.workers.small > 0:
+		mj.parallel = newSizedParallelManager(mj.statusCh, opts.workers.small, opts.workers.large, opts.workers.largeSize)
+		_, sourceRoot, _ := mustExpandAlias(srcURL)
+		mj.sourceRoot = newClientURL(sourceRoot).Path
+	default:
+		mj.parallel = newParallelManager(mj.statusCh)
+	}
 
 	// we'll define the status to use here,
 	// do we want the quiet status? or the progressbar
@@ -860,7 +1188,7 @@ func getEventPathURLWin(srcURL, eventPath string) string {
 }
 
 // runMirror - mirrors all buckets to another S3 server
-func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, encKeyDB map[string][]prefixSSEPair) bool {
+func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, encKeyDB encryptionConfig, report *mirrorReport, failed *failedLog) bool {
 	// Parse metadata.
 	userMetadata := make(map[string]string)
 	if cli.String("attr") != "" {
@@ -875,6 +1203,10 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 	dstClt, err := newClient(dstURL)
 	fatalIf(err, "Unable to initialize `"+dstURL+"`.")
 
+	buckets, err := newMirrorBucketFilter(cli)
+	fatalIf(err, "Unable to parse --exclude-bucket-tag, expected KEY=VALUE.")
+	fatalIf(buckets.excludeByConfig(ctx, srcURL, srcClt), "Unable to read the region and tags of the source buckets.")
+
 	// This is kept for backward compatibility, `--force` means --overwrite.
 	isOverwrite := cli.Bool("force")
 	if !isOverwrite {
@@ -885,31 +1217,74 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 	isRemove := cli.Bool("remove")
 
 	// preserve is also expected to be overwritten if necessary
-	isMetadata := cli.Bool("a") || isWatch || len(userMetadata) > 0
+	// two-way compares the modification times of both sides, keep them.
+	isTwoWay := cli.Bool("two-way")
+	isMetadata := cli.Bool("a") || isWatch || isTwoWay || len(userMetadata) > 0
 	isOverwrite = isOverwrite || isMetadata
 	isFake := cli.Bool("fake") || cli.Bool("dry-run")
 
+	var checksum string
+	if v := cli.String("checksum"); v != "" {
+		checksum, _ = parseChecksumAlgorithm(v)
+	}
+	stallTimeout, _ := parseStallTimeout(cli)
+	settleDuration, _ := parseSettleDuration(cli)
+	debounce, _ := parseDebounce(cli)
+	workers, _ := parseSizedWorkers(cli)
+	retries, retryDelay, _ := parseRetry(cli)
+	olderThan, newerThan, _ := parseOlderNewerThan(ctx, cli)
+
 	mopts := mirrorOptions{
-		isFake:           isFake,
-		isRemove:         isRemove,
-		isOverwrite:      isOverwrite,
-		isWatch:          isWatch,
-		isMetadata:       isMetadata,
-		md5:              cli.Bool("md5"),
-		disableMultipart: cli.Bool("disable-multipart"),
-		excludeOptions:   cli.StringSlice("exclude"),
-		olderThan:        cli.String("older-than"),
-		newerThan:        cli.String("newer-than"),
-		storageClass:     cli.String("storage-class"),
-		userMetadata:     userMetadata,
-		encKeyDB:         encKeyDB,
-		activeActive:     isWatch,
+		isFake:            isFake,
+		isRemove:          isRemove,
+		isOverwrite:       isOverwrite,
+		isWatch:           isWatch,
+		isMetadata:        isMetadata,
+		md5:               cli.Bool("md5"),
+		disableMultipart:  cli.Bool("disable-multipart"),
+		checksum:          checksum,
+		compress:          cli.String("compress"),
+		cseKey:            cli.String("cse-key"),
+		serverSide:        cli.Bool("server-side"),
+		preserveRetention: cli.Bool("preserve-retention"),
+		preserveLegalHold: cli.Bool("preserve-legalhold"),
+		stallTimeout:      stallTimeout,
+		retries:           retries,
+		retryDelay:        retryDelay,
+		failed:            failed,
+		settleDuration:    settleDuration,
+		debounce:          debounce,
+		workers:           workers,
+		watchFilter:       newWatchEventFilter(cli.StringSlice("watch-prefix"), cli.StringSlice("watch-suffix")),
+		excludeOptions:    cli.StringSlice("exclude"),
+		buckets:           buckets,
+		olderThan:         olderThan,
+		newerThan:         newerThan,
+		filter:            mustParseContentFilter(cli),
+		storageClass:      cli.String("storage-class"),
+		userMetadata:      userMetadata,
+		encKeyDB:          encKeyDB,
+		activeActive:      isWatch,
+		report:            report,
+	}
+	if isTwoWay {
+		policy, _ := parseMirrorConflict(cli.String("conflict"))
+		mopts.twoWay, err = newTwoWaySync(srcURL, dstURL, policy, isRemove)
+		fatalIf(err.Trace(srcURL, dstURL), "Unable to load the state of the last two-way mirror.")
+	}
+	if stateDB := cli.String("state-db"); stateDB != "" {
+		mopts.stateDB, err = newMirrorStateDB(stateDB, srcURL, dstURL, mirrorStateDBSignature(cli))
+		fatalIf(err.Trace(stateDB), "Unable to load the mirror state database.")
 	}
 
 	// Create a new mirror job and execute it
 	mj := newMirrorJob(srcURL, dstURL, mopts)
+	if cli.Bool("daemon") {
+		globalMirrorDaemon.setJob(mj)
+	}
 
 	preserve := cli.Bool("preserve")
+	preserveBucketConfig := cli.Bool("preserve-bucket-config")
 
 	createDstBuckets := dstClt.GetURL().Type == objectStorage && dstClt.GetURL().Path == string(dstClt.GetURL().Separator)
 	mirrorSrcBuckets := srcClt.GetURL().Type == objectStorage && srcClt.GetURL().Path == string(srcClt.GetURL().Separator)
@@ -928,6 +1303,9 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 
 			if d.Diff == differInSecond {
 				diffBucket := strings.TrimPrefix(d.SecondURL, dstClt.GetURL().String())
+				if buckets.skipSuffix(diffBucket, string(dstClt.GetURL().Separator)) {
+					continue
+				}
 				if !isFake && isRemove {
 					aliasedDstBucket := path.Join(dstURL, diffBucket)
 					err := deleteBucket(ctx, aliasedDstBucket, false)
@@ -937,6 +1315,9 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 			}
 
 			sourceSuffix := strings.TrimPrefix(d.FirstURL, srcClt.GetURL().String())
+			if buckets.skipSuffix(sourceSuffix, string(srcClt.GetURL().Separator)) {
+				continue
+			}
 
 			newSrcURL := path.Join(srcURL, sourceSuffix)
 			newTgtURL := path.Join(dstURL, sourceSuffix)
@@ -952,7 +1333,7 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 					unit     minio.ValidityUnit
 					err      *probe.Error
 				)
-				if preserve && mirrorBucketsToBuckets {
+				if (preserve || preserveBucketConfig) && mirrorBucketsToBuckets {
 					_, mode, validity, unit, err = newSrcClt.GetObjectLockConfig(ctx)
 					if err == nil {
 						withLock = true
@@ -973,7 +1354,7 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 					errorIf(err, "Unable to create bucket at `"+newTgtURL+"`.")
 					continue
 				}
-				if preserve && mirrorBucketsToBuckets {
+				if (preserve || preserveBucketConfig) && mirrorBucketsToBuckets {
 					// object lock configuration set on bucket
 					if mode != "" {
 						err = newDstClt.SetObjectLockConfig(ctx, mode, validity, unit)
@@ -985,6 +1366,13 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 							mj.opts.md5 = true
 						}
 					}
+				}
+				if preserveBucketConfig && mirrorBucketsToBuckets && !withLock {
+					// Buckets with object lock are versioned already.
+					_, err = copyBucketVersioning(ctx, newSrcClt, newDstClt)
+					errorIf(err, "Unable to copy bucket versioning to `"+newDstClt.GetURL().String()+"`.")
+				}
+				if preserve && mirrorBucketsToBuckets {
 					errorIf(copyBucketPolicies(ctx, newSrcClt, newDstClt, isOverwrite),
 						"Unable to copy bucket policies to `"+newDstClt.GetURL().String()+"`.")
 				}
@@ -992,6 +1380,22 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		}
 	}
 
+	if cli.Bool("with-metadata") && !isFake {
+		bucketURLs := [][2]string{{srcURL, dstURL}}
+		if mirrorBucketsToBuckets {
+			bucketURLs, err = mirrorBucketURLs(ctx, srcURL, dstURL, srcClt, buckets)
+			errorIf(err, "Unable to list the buckets of `"+srcURL+"`.")
+		}
+		for _, urls := range bucketURLs {
+			copied, err := copyBucketConfigs(ctx, urls[0], urls[1])
+			if err != nil {
+				errorIf(err, "Unable to copy the bucket configurations of `"+urls[0]+"` to `"+urls[1]+"`.")
+				continue
+			}
+			mj.status.PrintMsg(bucketConfigMessage{Op: "mirror", Source: urls[0], URL: urls[1], Configs: copied})
+		}
+	}
+
 	if mj.opts.isWatch {
 		// monitor mode will watch the source folders for changes,
 		// and queue them for copying.
@@ -1004,13 +1408,27 @@ func runMirror(ctx context.Context, srcURL, dstURL string, cli *cli.Context, enc
 		}
 	}
 
-	return mj.mirror(ctx)
+	errDuringMirror := mj.mirror(ctx)
+	if mj.opts.twoWay != nil && !isFake {
+		errorIf(mj.opts.twoWay.save().Trace(srcURL, dstURL), "Unable to save the state of the two-way mirror.")
+	}
+	if mj.opts.stateDB != nil && !isFake {
+		errorIf(mj.opts.stateDB.save().Trace(srcURL, dstURL), "Unable to save the mirror state database.")
+	}
+	if cli.Bool("stats") {
+		printMsg(mj.stats.Message())
+	}
+	return errDuringMirror
 }
 
 // Main entry point for mirror command.
 func mainMirror(cliCtx *cli.Context) error {
 	// Additional command specific theme customization.
 	console.SetColor("Mirror", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Settling", color.New(color.FgYellow))
+	console.SetColor("Conflict", color.New(color.FgYellow, color.Bold))
+	console.SetColor("Stats", color.New(color.Bold))
+	console.SetColor("BucketConfig", color.New(color.FgGreen))
 
 	ctx, cancelMirror := context.WithCancel(globalContext)
 	defer cancelMirror()
@@ -1022,8 +1440,17 @@ func mainMirror(cliCtx *cli.Context) error {
 	// check 'mirror' cli arguments.
 	srcURL, tgtURL := checkMirrorSyntax(ctx, cliCtx, encKeyDB)
 
+	isDaemon := cliCtx.Bool("daemon")
+	interval, _ := parseMirrorInterval(cliCtx)
+	if isDaemon {
+		globalMirrorDaemon.start(time.Now())
+	}
+
 	if prometheusAddress := cliCtx.String("monitoring-address"); prometheusAddress != "" {
 		http.Handle("/metrics", promhttp.Handler())
+		if isDaemon {
+			http.Handle("/health", globalMirrorDaemon)
+		}
 		go func() {
 			if e := http.ListenAndServe(prometheusAddress, nil); e != nil {
 				fatalIf(probe.NewError(e), "Unable to setup monitoring endpoint.")
@@ -1031,18 +1458,53 @@ func mainMirror(cliCtx *cli.Context) error {
 		}()
 	}
 
+	// The report of a session is printed once it is stopped, the
+	// watch itself never ends.
+	var report *mirrorReport
+	if cliCtx.Bool("session-report") {
+		report = newMirrorReport(cliCtx.Int("session-report-max"))
+		registerExitHook(func() {
+			printMsg(report.Message())
+		})
+	}
+
+	var failed *failedLog
+	if failedLog := cliCtx.String("failed-log"); failedLog != "" {
+		failed, err = newFailedLog(failedLog, []string{srcURL})
+		fatalIf(err, "Unable to create the failed objects log.")
+		defer failed.Close()
+	}
+
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	for {
 		select {
 		case <-ctx.Done():
 			return exitStatus(globalErrorExitStatus)
 		default:
-			errorDetected := runMirror(ctx, srcURL, tgtURL, cliCtx, encKeyDB)
+			start := time.Now()
+			errorDetected := runMirror(ctx, srcURL, tgtURL, cliCtx, encKeyDB, report, failed)
+			if isGracefulStopping() {
+				// The report is printed by the exit hook.
+				return exitStatus(globalCancelExitStatus)
+			}
 			if cliCtx.Bool("watch") || cliCtx.Bool("multi-master") || cliCtx.Bool("active-active") {
 				mirrorRestarts.Inc()
 				time.Sleep(time.Duration(r.Float64() * float64(2*time.Second)))
 				continue
 			}
+			if isDaemon {
+				// Mirror again after the interval, until interrupted.
+				globalMirrorDaemon.runDone(start, time.Now(), errorDetected)
+				select {
+				case <-time.After(interval):
+				case <-enableGracefulStop():
+					return exitStatus(globalCancelExitStatus)
+				case <-ctx.Done():
+					return exitStatus(globalErrorExitStatus)
+				}
+				mirrorRestarts.Inc()
+				continue
+			}
 			if errorDetected {
 				return exitStatus(globalErrorExitStatus)
 			}
@@ -0,0 +1,189 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// Operations recorded in a mirror session report.
+const (
+	mirrorReportSynced  = "synced"
+	mirrorReportDeleted = "deleted"
+)
+
+// mirrorReportEntry is one synchronized or deleted object.
+type mirrorReportEntry struct {
+	Time time.Time `json:"time"`
+	Op   string    `json:"op"`
+	Key  string    `json:"key"`
+	Size int64     `json:"size,omitempty"`
+}
+
+// mirrorReport records every object synchronized or deleted during a
+// mirror session, across restarts of the watch. Only the first entries
+// are kept in memory, the others spill to a file. It is safe for
+// concurrent use.
+type mirrorReport struct {
+	mu sync.Mutex
+
+	start      time.Time
+	maxEntries int
+	entries    []mirrorReportEntry
+	synced     int64
+	deleted    int64
+	bytes      int64
+
+	spillFile *os.File
+	spillBuf  *bufio.Writer
+	spilled   int64
+	spillErr  error
+}
+
+func newMirrorReport(maxEntries int) *mirrorReport {
+	return &mirrorReport{start: time.Now(), maxEntries: maxEntries}
+}
+
+// Add records an object, a nil report records nothing.
+func (r *mirrorReport) Add(op, key string, size int64) {
+	if r == nil {
+		return
+	}
+	entry := mirrorReportEntry{Time: time.Now().UTC(), Op: op, Key: key, Size: size}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if op == mirrorReportSynced {
+		r.synced++
+		r.bytes += size
+	} else {
+		r.deleted++
+	}
+	if len(r.entries) < r.maxEntries {
+		r.entries = append(r.entries, entry)
+		return
+	}
+	r.spill(entry)
+}
+
+// spill writes an entry to the spill file as a line of JSON, the file
+// is created with the first entry over the limit.
+func (r *mirrorReport) spill(entry mirrorReportEntry) {
+	if r.spillErr != nil {
+		r.spilled++
+		return
+	}
+	if r.spillFile == nil {
+		if r.spillFile, r.spillErr = os.CreateTemp("", "mc-mirror-session-*.jsonl"); r.spillErr != nil {
+			r.spilled++
+			return
+		}
+		r.spillBuf = bufio.NewWriter(r.spillFile)
+	}
+	line, e := json.Marshal(entry)
+	if e == nil {
+		_, e = r.spillBuf.Write(append(line, '\n'))
+	}
+	r.spillErr = e
+	r.spilled++
+}
+
+// Message closes the spill file and returns the report of the session
+// so far.
+func (r *mirrorReport) Message() mirrorReportMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	msg := mirrorReportMessage{
+		Start:   r.start,
+		End:     time.Now(),
+		Synced:  r.synced,
+		Deleted: r.deleted,
+		Bytes:   r.bytes,
+		Entries: r.entries,
+		Spilled: r.spilled,
+	}
+	if r.spillFile != nil {
+		if r.spillErr == nil {
+			r.spillErr = r.spillBuf.Flush()
+		}
+		if e := r.spillFile.Close(); r.spillErr == nil {
+			r.spillErr = e
+		}
+		msg.SpillFile = r.spillFile.Name()
+	}
+	if r.spillErr != nil {
+		msg.SpillError = r.spillErr.Error()
+	}
+	return msg
+}
+
+// mirrorReportMessage container for the report of a mirror session.
+type mirrorReportMessage struct {
+	Status     string              `json:"status"`
+	Type       string              `json:"type"`
+	Start      time.Time           `json:"start"`
+	End        time.Time           `json:"end"`
+	Synced     int64               `json:"synced"`
+	Deleted    int64               `json:"deleted"`
+	Bytes      int64               `json:"bytes"`
+	Entries    []mirrorReportEntry `json:"entries"`
+	Spilled    int64               `json:"spilled,omitempty"`
+	SpillFile  string              `json:"spillFile,omitempty"`
+	SpillError string              `json:"spillError,omitempty"`
+}
+
+func (m mirrorReportMessage) String() string {
+	var b strings.Builder
+	b.WriteString(console.Colorize("Stats", fmt.Sprintf("Session from %s to %s: %d synchronized (%s), %d deleted",
+		m.Start.Format(printDate), m.End.Format(printDate), m.Synced, humanize.IBytes(uint64(m.Bytes)), m.Deleted)))
+	for _, entry := range m.Entries {
+		fmt.Fprintf(&b, "\n[%s] %-8s %s", entry.Time.Local().Format(printDate), entry.Op, entry.Key)
+		if entry.Op == mirrorReportSynced {
+			fmt.Fprintf(&b, " (%s)", humanize.IBytes(uint64(entry.Size)))
+		}
+	}
+	if m.Spilled > 0 {
+		fmt.Fprintf(&b, "\n... %d more entries", m.Spilled)
+		if m.SpillFile != "" {
+			fmt.Fprintf(&b, " in `%s`", m.SpillFile)
+		}
+	}
+	if m.SpillError != "" {
+		fmt.Fprintf(&b, "\nUnable to save all entries: %s", m.SpillError)
+	}
+	return b.String()
+}
+
+func (m mirrorReportMessage) JSON() string {
+	m.Status = "success"
+	m.Type = "report"
+	msgBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckSessionReportSyntax(t *testing.T) {
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"src/", "dst/"}, true},
+		{[]string{"--watch", "--session-report", "src/", "dst/"}, true},
+		{[]string{"-w", "--session-report", "--session-report-max", "10", "src/", "dst/"}, true},
+		{[]string{"--active-active", "--session-report", "src/", "dst/"}, true},
+		{[]string{"--session-report", "src/", "dst/"}, false},
+		{[]string{"--watch", "--session-report", "--session-report-max", "-1", "src/", "dst/"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkSessionReportSyntax(newTestCLIContext(t, mirrorFlags, testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestMirrorReport(t *testing.T) {
+	// A nil report records nothing.
+	var disabled *mirrorReport
+	disabled.Add(mirrorReportSynced, "dst/object", 1)
+
+	report := newMirrorReport(2)
+	report.Add(mirrorReportSynced, "dst/a", 10)
+	report.Add(mirrorReportDeleted, "dst/b", 0)
+	report.Add(mirrorReportSynced, "dst/c", 20)
+	report.Add(mirrorReportDeleted, "dst/d", 0)
+
+	msg := report.Message()
+	if msg.Synced != 2 || msg.Deleted != 2 || msg.Bytes != 30 {
+		t.Fatalf("unexpected counts %+v", msg)
+	}
+	if len(msg.Entries) != 2 || msg.Entries[0].Key != "dst/a" || msg.Entries[1].Op != mirrorReportDeleted {
+		t.Fatalf("unexpected entries %+v", msg.Entries)
+	}
+	if msg.SpillFile == "" || msg.SpillError != "" || msg.Spilled != 2 {
+		t.Fatalf("expected 2 entries to spill, got %+v", msg)
+	}
+	defer os.Remove(msg.SpillFile)
+
+	// Entries over the limit are saved as JSON lines.
+	f, e := os.Open(msg.SpillFile)
+	if e != nil {
+		t.Fatal(e)
+	}
+	defer f.Close()
+	var spilled []mirrorReportEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry mirrorReportEntry
+		if e = json.Unmarshal(scanner.Bytes(), &entry); e != nil {
+			t.Fatal(e)
+		}
+		spilled = append(spilled, entry)
+	}
+	if len(spilled) != 2 || spilled[0].Key != "dst/c" || spilled[0].Size != 20 || spilled[1].Key != "dst/d" {
+		t.Fatalf("unexpected spilled entries %+v", spilled)
+	}
+	if !strings.Contains(msg.String(), "2 more entries") {
+		t.Fatalf("expected the spilled entries to be mentioned, got %s", msg.String())
+	}
+}
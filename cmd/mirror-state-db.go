@@ -0,0 +1,242 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/quick"
+)
+
+// Flags changing which objects 'mirror' writes to the target, a state
+// database saved with other values is not trusted.
+var mirrorStateDBFlags = []string{
+	"exclude", "older-than", "newer-than", "older-than-file", "newer-than-file", "filter", "remove", "overwrite",
+	"storage-class", "encrypt", "encrypt-key", "enc-kms", "checksum", "md5",
+}
+
+// mirrorStateDBSignature identifies the values of mirrorStateDBFlags.
+func mirrorStateDBSignature(cliCtx *cli.Context) string {
+	values := make([]string, 0, len(mirrorStateDBFlags))
+	for _, name := range mirrorStateDBFlags {
+		values = append(values, name+"="+cliCtx.String(name)+"\n")
+	}
+	return getHash("mirror-state-db", values)
+}
+
+// JSON file of 'mirror --state-db', the objects of the target as of
+// the last run.
+type mirrorStateDBV1 struct {
+	Version string                     `json:"version"`
+	Source  string                     `json:"source"`
+	Target  string                     `json:"target"`
+	Flags   string                     `json:"flags"`
+	Objects map[string]twoWaySignature `json:"objects"`
+}
+
+// mirrorStateDB replays the objects of the target saved by the last
+// 'mirror --state-db' instead of listing the target, and keeps them up
+// to date with the objects copied and removed.
+type mirrorStateDB struct {
+	mutex sync.Mutex
+	file  string
+	state *mirrorStateDBV1
+	// valid is true if the state was saved by a run with the same
+	// source, target and flags.
+	valid bool
+	// complete is true once the target was entirely replayed or listed,
+	// a partial state is discarded rather than saved.
+	complete   bool
+	targetRoot string
+}
+
+// newMirrorStateDB loads the state database of the last run, it is not
+// trusted if the source, target or flags changed since.
+func newMirrorStateDB(file, sourceURL, targetURL, flags string) (*mirrorStateDB, *probe.Error) {
+	s := &mirrorStateDB{
+		file: file,
+		state: &mirrorStateDBV1{
+			Version: "1",
+			Source:  sourceURL,
+			Target:  targetURL,
+			Flags:   flags,
+			Objects: make(map[string]twoWaySignature),
+		},
+	}
+	if _, e := os.Stat(file); os.IsNotExist(e) {
+		return s, nil
+	}
+	qs, e := quick.NewConfig(&mirrorStateDBV1{Version: "1"}, nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(file)
+	}
+	if e = qs.Load(file); e != nil {
+		return nil, probe.NewError(e).Trace(file)
+	}
+	last := qs.Data().(*mirrorStateDBV1)
+	if last.Source == sourceURL && last.Target == targetURL && last.Flags == flags && last.Objects != nil {
+		s.state, s.valid = last, true
+	}
+	return s, nil
+}
+
+// save persists the objects of the target, or removes the state
+// database if the target was not entirely listed.
+func (s *mirrorStateDB) save() *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.complete {
+		if e := os.Remove(s.file); e != nil && !os.IsNotExist(e) {
+			return probe.NewError(e).Trace(s.file)
+		}
+		return nil
+	}
+	if e := os.MkdirAll(filepath.Dir(s.file), 0o700); e != nil {
+		return probe.NewError(e).Trace(s.file)
+	}
+	qs, e := quick.NewConfig(s.state, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(s.file)
+	}
+	if e = qs.Save(s.file); e != nil {
+		return probe.NewError(e).Trace(s.file)
+	}
+	return nil
+}
+
+func (s *mirrorStateDB) key(urlStr string) string {
+	return strings.TrimPrefix(filepath.ToSlash(strings.TrimPrefix(urlStr, s.targetRoot)), "/")
+}
+
+func (s *mirrorStateDB) set(urlStr string, c *ClientContent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if c == nil {
+		delete(s.state.Objects, s.key(urlStr))
+	} else {
+		s.state.Objects[s.key(urlStr)] = newTwoWaySignature(c)
+	}
+}
+
+// difference compares the source with the target objects, which are
+// replayed from a valid state or else listed and recorded.
+func (s *mirrorStateDB) difference(ctx context.Context, sourceClnt, targetClnt Client) chan diffMessage {
+	sourceURL := sourceClnt.GetURL().String()
+	sourceCh := sourceClnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone})
+
+	s.targetRoot = targetClnt.GetURL().String()
+	if s.valid {
+		return difference(sourceURL, sourceCh, s.targetRoot, s.replay(ctx), false, false)
+	}
+	return difference(sourceURL, sourceCh, s.targetRoot, s.record(ctx, targetClnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone})), false, false)
+}
+
+// replay sends the objects of the state in lexical order, as listed.
+func (s *mirrorStateDB) replay(ctx context.Context) <-chan *ClientContent {
+	s.mutex.Lock()
+	keys := make([]string, 0, len(s.state.Objects))
+	for key := range s.state.Objects {
+		keys = append(keys, key)
+	}
+	s.complete = true
+	s.mutex.Unlock()
+	sort.Strings(keys)
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for _, key := range keys {
+			s.mutex.Lock()
+			signature := s.state.Objects[key]
+			s.mutex.Unlock()
+			content := &ClientContent{
+				URL:  *newClientURL(urlJoinPath(s.targetRoot, key)),
+				Size: signature.Size,
+				ETag: signature.ETag,
+				Time: signature.ModTime,
+				Type: os.FileMode(0o644),
+			}
+			select {
+			case contentCh <- content:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return contentCh
+}
+
+// record saves the objects of the target as they are listed.
+func (s *mirrorStateDB) record(ctx context.Context, targetCh <-chan *ClientContent) <-chan *ClientContent {
+	s.mutex.Lock()
+	s.state.Objects = make(map[string]twoWaySignature)
+	s.mutex.Unlock()
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		complete := true
+		for content := range targetCh {
+			if content.Err != nil {
+				complete = false
+			} else if content.Type.IsRegular() {
+				s.set(content.URL.String(), content)
+			}
+			select {
+			case contentCh <- content:
+			case <-ctx.Done():
+				return
+			}
+		}
+		s.mutex.Lock()
+		s.complete = complete
+		s.mutex.Unlock()
+	}()
+	return contentCh
+}
+
+// done records an object copied or removed successfully, the copy is
+// stat'ed for its size, ETag and modification time on the target.
+func (s *mirrorStateDB) done(ctx context.Context, sURLs URLs) {
+	targetURL := sURLs.TargetContent.URL.String()
+	if sURLs.SourceContent == nil {
+		s.set(targetURL, nil)
+		return
+	}
+	clnt, err := newClientFromAlias(sURLs.TargetAlias, targetURL)
+	if err != nil {
+		s.set(targetURL, nil)
+		return
+	}
+	copied, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		// Left out of the state, copied again on the next run.
+		s.set(targetURL, nil)
+		return
+	}
+	s.set(targetURL, copied)
+}
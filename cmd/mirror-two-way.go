@@ -0,0 +1,452 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/quick"
+)
+
+// Policies of 'mirror --two-way --conflict', deciding which side wins
+// when an object was changed on both sides.
+const (
+	mirrorConflictNewer  = "newer"
+	mirrorConflictSource = "source"
+	mirrorConflictTarget = "target"
+	mirrorConflictSkip   = "skip"
+)
+
+// parseMirrorConflict validates the --conflict flag.
+func parseMirrorConflict(policy string) (string, *probe.Error) {
+	switch policy {
+	case mirrorConflictNewer, mirrorConflictSource, mirrorConflictTarget, mirrorConflictSkip:
+		return policy, nil
+	}
+	return "", errInvalidArgument().Trace(policy)
+}
+
+// twoWaySignature identifies the version of an object on one side.
+type twoWaySignature struct {
+	Size    int64     `json:"size"`
+	ETag    string    `json:"etag,omitempty"`
+	ModTime time.Time `json:"modTime"`
+}
+
+func newTwoWaySignature(c *ClientContent) twoWaySignature {
+	return twoWaySignature{Size: c.Size, ETag: c.ETag, ModTime: c.Time.UTC()}
+}
+
+// matches returns true if an object is still the version of a
+// signature, modification times are compared if there is no ETag.
+func (s twoWaySignature) matches(c *ClientContent) bool {
+	if s.Size != c.Size {
+		return false
+	}
+	if s.ETag != "" && c.ETag != "" {
+		return s.ETag == c.ETag
+	}
+	return s.ModTime.Truncate(time.Second).Equal(c.Time.Truncate(time.Second))
+}
+
+// twoWayEntry is an object as both sides held it when last synchronized.
+type twoWayEntry struct {
+	Source twoWaySignature `json:"source"`
+	Target twoWaySignature `json:"target"`
+}
+
+// JSON file to persist the objects synchronized by 'mirror --two-way'
+// between a source and a target.
+type twoWayStateV1 struct {
+	Version string                 `json:"version"`
+	Source  string                 `json:"source"`
+	Target  string                 `json:"target"`
+	Objects map[string]twoWayEntry `json:"objects"`
+}
+
+// preservedModTime returns the modification time of an object preserved
+// with -a, or else the time it was written.
+func preservedModTime(c *ClientContent) time.Time {
+	for _, metadata := range []map[string]string{c.Metadata, c.UserMetadata} {
+		attrs, e := parseAttribute(metadata)
+		if e != nil {
+			continue
+		}
+		if _, mtime, err := parseAtimeMtime(attrs); err == nil && !mtime.IsZero() {
+			return mtime
+		}
+	}
+	return c.Time
+}
+
+// twoWayAction is what reconciling an object decides.
+type twoWayAction int
+
+const (
+	twoWayInSync twoWayAction = iota
+	twoWayToTarget
+	twoWayToSource
+	twoWayRemoveSource
+	twoWayRemoveTarget
+	twoWayConflict
+)
+
+// reconcileTwoWay decides how to reconcile an object from its source and
+// target versions, either may be nil, and its last synchronized state.
+func reconcileTwoWay(src, tgt *ClientContent, last *twoWayEntry, policy string, isRemove bool) twoWayAction {
+	switch {
+	case src == nil && tgt == nil:
+		return twoWayInSync
+	case tgt == nil:
+		// Removed from the target if the source did not change since.
+		if last != nil && last.Source.matches(src) && isRemove {
+			return twoWayRemoveSource
+		}
+		return twoWayToTarget
+	case src == nil:
+		if last != nil && last.Target.matches(tgt) && isRemove {
+			return twoWayRemoveTarget
+		}
+		return twoWayToSource
+	}
+
+	if last != nil {
+		srcChanged, tgtChanged := !last.Source.matches(src), !last.Target.matches(tgt)
+		switch {
+		case !srcChanged && !tgtChanged:
+			return twoWayInSync
+		case srcChanged && !tgtChanged:
+			return twoWayToTarget
+		case !srcChanged && tgtChanged:
+			return twoWayToSource
+		}
+	}
+
+	// Never synchronized, or changed on both sides.
+	srcTime, tgtTime := preservedModTime(src).Truncate(time.Second), preservedModTime(tgt).Truncate(time.Second)
+	if src.ETag != "" && src.ETag == tgt.ETag || src.Size == tgt.Size && srcTime.Equal(tgtTime) {
+		return twoWayInSync
+	}
+	switch policy {
+	case mirrorConflictSource:
+		return twoWayToTarget
+	case mirrorConflictTarget:
+		return twoWayToSource
+	case mirrorConflictNewer:
+		if srcTime.After(tgtTime) {
+			return twoWayToTarget
+		}
+		if tgtTime.After(srcTime) {
+			return twoWayToSource
+		}
+	}
+	return twoWayConflict
+}
+
+// twoWaySync reconciles the objects of a source and a target in both
+// directions for 'mirror --two-way'. The state of every synchronized
+// object is saved so that the side which changed is known on the next
+// run.
+type twoWaySync struct {
+	policy   string
+	isRemove bool
+
+	mutex     sync.Mutex
+	stateFile string
+	state     *twoWayStateV1
+	// Keys of the objects queued to be copied or removed, by the URL
+	// of the object copied or removed.
+	pending map[string]string
+	// Root URLs of the source and target, to tell which side an object
+	// is on.
+	sourceRoot, targetRoot string
+}
+
+// mustGetMirrorStateDir returns the folder of the 'mirror --two-way'
+// states or dies.
+func mustGetMirrorStateDir() string {
+	return filepath.Join(mustGetMcConfigDir(), globalMCMirrorStateDir)
+}
+
+// newTwoWaySync loads the state of the last 'mirror --two-way' between a
+// source and a target, if any.
+func newTwoWaySync(sourceURL, targetURL, policy string, isRemove bool) (*twoWaySync, *probe.Error) {
+	stateDir := mustGetMirrorStateDir()
+	if e := os.MkdirAll(stateDir, 0o700); e != nil {
+		return nil, probe.NewError(e).Trace(stateDir)
+	}
+	t := &twoWaySync{
+		policy:    policy,
+		isRemove:  isRemove,
+		stateFile: filepath.Join(stateDir, getHash("mirror", []string{sourceURL, targetURL})+".json"),
+		state: &twoWayStateV1{
+			Version: "1",
+			Source:  sourceURL,
+			Target:  targetURL,
+			Objects: make(map[string]twoWayEntry),
+		},
+		pending: make(map[string]string),
+	}
+	if _, e := os.Stat(t.stateFile); os.IsNotExist(e) {
+		return t, nil
+	}
+	qs, e := quick.NewConfig(t.state, nil)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(t.stateFile)
+	}
+	if e = qs.Load(t.stateFile); e != nil {
+		return nil, probe.NewError(e).Trace(t.stateFile)
+	}
+	t.state = qs.Data().(*twoWayStateV1)
+	if t.state.Objects == nil {
+		t.state.Objects = make(map[string]twoWayEntry)
+	}
+	return t, nil
+}
+
+// save persists the state of the synchronized objects.
+func (t *twoWaySync) save() *probe.Error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	qs, e := quick.NewConfig(t.state, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(t.stateFile)
+	}
+	if e = qs.Save(t.stateFile); e != nil {
+		return probe.NewError(e).Trace(t.stateFile)
+	}
+	return nil
+}
+
+func (t *twoWaySync) lastState(key string) *twoWayEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if entry, ok := t.state.Objects[key]; ok {
+		return &entry
+	}
+	return nil
+}
+
+func (t *twoWaySync) setState(key string, entry *twoWayEntry) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if entry == nil {
+		delete(t.state.Objects, key)
+	} else {
+		t.state.Objects[key] = *entry
+	}
+}
+
+func (t *twoWaySync) queue(urlStr, key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.pending[urlStr] = key
+}
+
+func (t *twoWaySync) dequeue(urlStr string) (string, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key, ok := t.pending[urlStr]
+	delete(t.pending, urlStr)
+	return key, ok
+}
+
+// reconcile lists the source and the target, and sends the objects to
+// copy in either direction or to remove from either side.
+func (t *twoWaySync) reconcile(ctx context.Context, sourceURL, targetURL string, opts mirrorOptions, URLsCh chan<- URLs) {
+	defer close(URLsCh)
+
+	sourceSeparator := string(newClientURL(sourceURL).Separator)
+	if !strings.HasSuffix(sourceURL, sourceSeparator) {
+		sourceURL = sourceURL + sourceSeparator
+	}
+	targetSeparator := string(newClientURL(targetURL).Separator)
+	if !strings.HasSuffix(targetURL, targetSeparator) {
+		targetURL = targetURL + targetSeparator
+	}
+	sourceAlias, sourceURL, _ := mustExpandAlias(sourceURL)
+	targetAlias, targetURL, _ := mustExpandAlias(targetURL)
+
+	sourceClnt, err := newClientFromAlias(sourceAlias, sourceURL)
+	if err != nil {
+		URLsCh <- URLs{Error: err.Trace(sourceAlias, sourceURL)}
+		return
+	}
+	targetClnt, err := newClientFromAlias(targetAlias, targetURL)
+	if err != nil {
+		URLsCh <- URLs{Error: err.Trace(targetAlias, targetURL)}
+		return
+	}
+	t.sourceRoot, t.targetRoot = sourceClnt.GetURL().String(), targetClnt.GetURL().String()
+
+	listOpts := ListOptions{Recursive: true, WithMetadata: true, ShowDir: DirNone}
+	diffCh := difference(t.sourceRoot, sourceClnt.List(ctx, listOpts), t.targetRoot, targetClnt.List(ctx, listOpts), false, true)
+
+	var lastPair string
+	for diffMsg := range diffCh {
+		if diffMsg.Error != nil {
+			URLsCh <- URLs{Error: diffMsg.Error, ErrorCond: differInUnknown}
+			continue
+		}
+		// Objects differing are sent twice, as differing and as similar.
+		pair := diffMsg.FirstURL + "\x00" + diffMsg.SecondURL
+		if diffMsg.Diff == differInNone && pair == lastPair {
+			continue
+		}
+		lastPair = pair
+
+		if diffMsg.Diff == differInType {
+			URLsCh <- URLs{Error: errInvalidTarget(diffMsg.SecondURL)}
+			continue
+		}
+
+		src, tgt := diffMsg.firstContent, diffMsg.secondContent
+		var key string
+		if src != nil {
+			key = strings.TrimPrefix(diffMsg.FirstURL, t.sourceRoot)
+		} else {
+			key = strings.TrimPrefix(diffMsg.SecondURL, t.targetRoot)
+		}
+		key = strings.TrimPrefix(filepath.ToSlash(key), "/")
+		if matchExcludeOptions(opts.excludeOptions, key) {
+			continue
+		}
+
+		sourcePath, targetPath := urlJoinPath(sourceURL, key), urlJoinPath(targetURL, key)
+		last := t.lastState(key)
+		switch reconcileTwoWay(src, tgt, last, t.policy, t.isRemove) {
+		case twoWayInSync:
+			if src != nil && tgt != nil && (last == nil || !last.Source.matches(src) || !last.Target.matches(tgt)) {
+				t.setState(key, &twoWayEntry{Source: newTwoWaySignature(src), Target: newTwoWaySignature(tgt)})
+			}
+		case twoWayToTarget:
+			t.queue(src.URL.String(), key)
+			URLsCh <- URLs{
+				SourceAlias:   sourceAlias,
+				SourceContent: src,
+				TargetAlias:   targetAlias,
+				TargetContent: &ClientContent{URL: *newClientURL(targetPath)},
+			}
+		case twoWayToSource:
+			t.queue(tgt.URL.String(), key)
+			URLsCh <- URLs{
+				SourceAlias:   targetAlias,
+				SourceContent: tgt,
+				TargetAlias:   sourceAlias,
+				TargetContent: &ClientContent{URL: *newClientURL(sourcePath)},
+			}
+		case twoWayRemoveSource:
+			t.queue(src.URL.String(), key)
+			URLsCh <- URLs{
+				TargetAlias:   sourceAlias,
+				TargetContent: src,
+			}
+		case twoWayRemoveTarget:
+			t.queue(tgt.URL.String(), key)
+			URLsCh <- URLs{
+				TargetAlias:   targetAlias,
+				TargetContent: tgt,
+			}
+		case twoWayConflict:
+			URLsCh <- URLs{
+				SourceAlias:   sourceAlias,
+				SourceContent: src,
+				TargetAlias:   targetAlias,
+				TargetContent: tgt,
+				Error:         probe.NewError(ObjectConflict{Source: src.URL.String(), Target: tgt.URL.String()}),
+			}
+		}
+	}
+}
+
+// done records the state of an object copied or removed successfully,
+// the copy is stat'ed for the signature it got on its side.
+func (t *twoWaySync) done(ctx context.Context, sURLs URLs) {
+	if sURLs.SourceContent == nil {
+		// Removed, forget the object.
+		if key, ok := t.dequeue(sURLs.TargetContent.URL.String()); ok {
+			t.setState(key, nil)
+		}
+		return
+	}
+	key, ok := t.dequeue(sURLs.SourceContent.URL.String())
+	if !ok {
+		return
+	}
+	clnt, err := newClientFromAlias(sURLs.TargetAlias, sURLs.TargetContent.URL.String())
+	if err != nil {
+		return
+	}
+	copied, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		// Left out of the state, reconciled as never synchronized.
+		t.setState(key, nil)
+		return
+	}
+	entry := twoWayEntry{Source: newTwoWaySignature(sURLs.SourceContent), Target: newTwoWaySignature(copied)}
+	if strings.HasPrefix(sURLs.SourceContent.URL.String(), t.targetRoot) {
+		entry.Source, entry.Target = entry.Target, entry.Source
+	}
+	t.setState(key, &entry)
+}
+
+// conflictMessage container for objects skipped by 'mirror --two-way'
+// as they were changed on both sides.
+type conflictMessage struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// String colorized conflict message
+func (c conflictMessage) String() string {
+	return console.Colorize("Conflict", fmt.Sprintf("`%s` and `%s` were both changed, skipping.", c.Source, c.Target))
+}
+
+// JSON jsonified conflict message
+func (c conflictMessage) JSON() string {
+	c.Status = "conflict"
+	conflictMessageBytes, e := json.MarshalIndent(c, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(conflictMessageBytes)
+}
+
+// isErrConflict returns true if an object was skipped as changed on both
+// sides of a 'mirror --two-way'.
+func isErrConflict(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.ToGoError().(ObjectConflict)
+	return ok
+}
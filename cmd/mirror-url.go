@@ -35,7 +35,7 @@ import (
 //   mirror(d1..., d2) -> []mirror(d1/f, d2/d1/f)
 
 // checkMirrorSyntax(URLs []string)
-func checkMirrorSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) (srcURL, tgtURL string) {
+func checkMirrorSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) (srcURL, tgtURL string) {
 	if len(cliCtx.Args()) != 2 {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
 	}
@@ -64,6 +64,78 @@ func checkMirrorSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[st
 		}
 	}
 
+	if checksum := cliCtx.String("checksum"); checksum != "" {
+		_, err := parseChecksumAlgorithm(checksum)
+		fatalIf(err.Trace(checksum), "Unable to validate --checksum.")
+	}
+	checkCompressSyntax(cliCtx)
+	checkCSESyntax(cliCtx)
+	checkServerSideSyntax(cliCtx)
+	checkObjectLockSyntax(cliCtx)
+
+	_, err := parseStallTimeout(cliCtx)
+	fatalIf(err.Trace(cliCtx.String("stall-timeout")), "Unable to parse --stall-timeout.")
+
+	_, err = parseSettleDuration(cliCtx)
+	fatalIf(err.Trace(cliCtx.String("settle-duration")), "Unable to parse --settle-duration.")
+
+	_, _, err = parseRetry(cliCtx)
+	fatalIf(err, "Unable to parse --retry or --retry-delay.")
+
+	_, _, err = parseOlderNewerThan(ctx, cliCtx)
+	fatalIf(err, "Unable to read the modification time of --older-than-file or --newer-than-file.")
+
+	_, err = parseContentFilter(cliCtx.String("filter"))
+	fatalIf(err.Trace(cliCtx.Args()...), "Unable to parse --filter.")
+
+	if msg := checkSessionReportSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(URLs...), msg)
+	}
+	if msg := checkMirrorWatchSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(URLs...), msg)
+	}
+	if msg := checkMirrorDaemonSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(URLs...), msg)
+	}
+	if msg := checkSizedWorkersSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(URLs...), msg)
+	}
+
+	if cliCtx.Bool("two-way") {
+		if cliCtx.Bool("watch") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master") {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--two-way cannot be used with --watch or --active-active.")
+		}
+		_, err = parseMirrorConflict(cliCtx.String("conflict"))
+		fatalIf(err.Trace(URLs...), "Unable to parse --conflict, expected 'newer', 'source', 'target' or 'skip'.")
+		if (srcClient.Type == objectStorage && srcClient.Path == string(srcClient.Separator)) ||
+			(destClient.Type == objectStorage && destClient.Path == string(destClient.Separator)) {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--two-way requires a bucket or a folder on both sides, not an alias.")
+		}
+	} else if cliCtx.IsSet("conflict") {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--conflict can only be used with --two-way.")
+	}
+
+	for _, flag := range mirrorBucketFlags {
+		if cliCtx.IsSet(flag) && (srcClient.Type != objectStorage || srcClient.Path != string(srcClient.Separator)) {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--"+flag+" requires mirroring all buckets of an alias.")
+		}
+	}
+	if cliCtx.Bool("with-metadata") && !isMirrorBucketRoots(srcClient, destClient) {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--with-metadata requires mirroring all buckets of an alias to an alias, or a bucket to a bucket.")
+	}
+	if cliCtx.Bool("preserve-bucket-config") && (destClient.Type != objectStorage || destClient.Path != string(destClient.Separator)) {
+		fatalIf(errInvalidArgument().Trace(URLs...), "--preserve-bucket-config requires mirroring to an alias.")
+	}
+
+	if cliCtx.String("state-db") != "" {
+		if cliCtx.Bool("watch") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master") || cliCtx.Bool("two-way") {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--state-db cannot be used with --watch, --active-active or --two-way.")
+		}
+		if cliCtx.Bool("a") || cliCtx.String("attr") != "" {
+			fatalIf(errInvalidArgument().Trace(URLs...), "--state-db cannot be used with --preserve or --attr, which compare the metadata of the target.")
+		}
+	}
+
 	/****** Generic rules *******/
 	if !cliCtx.Bool("watch") && !cliCtx.Bool("active-active") && !cliCtx.Bool("multi-master") {
 		_, srcContent, err := url2Stat(ctx, srcURL, "", false, encKeyDB, time.Time{}, false)
@@ -128,7 +200,21 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 	}
 
 	// List both source and target, compare and return values through channel.
-	for diffMsg := range objectDifference(ctx, sourceClnt, targetClnt, opts.isMetadata) {
+	var diffCh chan diffMessage
+	if opts.stateDB != nil {
+		diffCh = opts.stateDB.difference(ctx, sourceClnt, targetClnt)
+	} else if opts.compress != "" || opts.cseKey != "" {
+		// Compressed and encrypted objects are compared by the size they
+		// had before, which is kept in their metadata.
+		diffCh = difference(sourceClnt.GetURL().String(),
+			sourceClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: opts.isMetadata, ShowDir: DirNone}),
+			targetClnt.GetURL().String(),
+			targetClnt.List(ctx, ListOptions{Recursive: true, WithMetadata: true, ShowDir: DirNone}),
+			opts.isMetadata, false)
+	} else {
+		diffCh = objectDifference(ctx, sourceClnt, targetClnt, opts.isMetadata)
+	}
+	for diffMsg := range diffCh {
 		if diffMsg.Error != nil {
 			// Send all errors through the channel
 			URLsCh <- URLs{Error: diffMsg.Error, ErrorCond: differInUnknown}
@@ -147,6 +233,12 @@ func deltaSourceTarget(ctx context.Context, sourceURL, targetURL string, opts mi
 			continue
 		}
 
+		// Skip the objects of the buckets left out of the mirror.
+		if diffMsg.FirstURL != "" && opts.buckets.skipSuffix(srcSuffix, sourceSeparator) ||
+			diffMsg.SecondURL != "" && opts.buckets.skipSuffix(tgtSuffix, targetSeparator) {
+			continue
+		}
+
 		switch diffMsg.Diff {
 		case differInNone:
 			// No difference, continue.
@@ -206,16 +298,54 @@ type mirrorOptions struct {
 	isFake, isOverwrite, activeActive bool
 	isWatch, isRemove, isMetadata     bool
 	excludeOptions                    []string
-	encKeyDB                          map[string][]prefixSSEPair
+	buckets                           *mirrorBucketFilter
+	encKeyDB                          encryptionConfig
 	md5, disableMultipart             bool
+	checksum                          string
+	compress                          string
+	cseKey                            string
+	serverSide                        bool
+	preserveRetention                 bool
+	preserveLegalHold                 bool
+	stallTimeout                      time.Duration
+	retries                           int
+	retryDelay                        time.Duration
+	failed                            *failedLog
+	settleDuration                    time.Duration
+	debounce                          time.Duration
+	workers                           sizedWorkers
+	watchFilter                       *watchEventFilter
 	olderThan, newerThan              string
+	filter                            *contentFilter
 	storageClass                      string
 	userMetadata                      map[string]string
+	report                            *mirrorReport
+	twoWay                            *twoWaySync
+	stateDB                           *mirrorStateDB
+}
+
+// checkSessionReportSyntax - returns why --session-report cannot be
+// used with the other flags passed, empty if it can.
+func checkSessionReportSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.Bool("session-report") {
+		return ""
+	}
+	if !cliCtx.Bool("watch") && !cliCtx.Bool("active-active") && !cliCtx.Bool("multi-master") {
+		return "--session-report can only be used with --watch."
+	}
+	if cliCtx.Int("session-report-max") < 0 {
+		return "--session-report-max cannot be negative."
+	}
+	return ""
 }
 
 // Prepares urls that need to be copied or removed based on requested options.
 func prepareMirrorURLs(ctx context.Context, sourceURL, targetURL string, opts mirrorOptions) <-chan URLs {
 	URLsCh := make(chan URLs)
+	if opts.twoWay != nil {
+		go opts.twoWay.reconcile(ctx, sourceURL, targetURL, opts, URLsCh)
+		return URLsCh
+	}
 	go deltaSourceTarget(ctx, sourceURL, targetURL, opts, URLsCh)
 	return URLsCh
 }
@@ -0,0 +1,198 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// mirrorWatchFlags select and delay the events mirrored by --watch.
+var mirrorWatchFlags = []cli.Flag{
+	cli.StringSliceFlag{
+		Name:  "watch-prefix",
+		Usage: "with --watch, only mirror events of objects under this prefix of the source, e.g. 'app/'",
+	},
+	cli.StringSliceFlag{
+		Name:  "watch-suffix",
+		Usage: "with --watch, only mirror events of objects ending with this suffix, e.g. '.log'",
+	},
+	cli.StringFlag{
+		Name:  "debounce",
+		Usage: "with --watch, copy an object once it was not written again for this duration, e.g. '10s'",
+	},
+}
+
+// watchEventFilter selects the events of 'mirror --watch' by the path
+// of their object relative to the source.
+type watchEventFilter struct {
+	prefixes []string
+	suffixes []string
+}
+
+// newWatchEventFilter returns the filter of --watch-prefix and
+// --watch-suffix, nil if neither is set.
+func newWatchEventFilter(prefixes, suffixes []string) *watchEventFilter {
+	if len(prefixes) == 0 && len(suffixes) == 0 {
+		return nil
+	}
+	return &watchEventFilter{prefixes: prefixes, suffixes: suffixes}
+}
+
+// Match returns true if an object is under one of the prefixes and ends
+// with one of the suffixes, a nil filter matches every object.
+func (f *watchEventFilter) Match(relPath string) bool {
+	if f == nil {
+		return true
+	}
+	relPath = strings.TrimPrefix(relPath, "/")
+	return matchAny(f.prefixes, relPath, strings.HasPrefix) && matchAny(f.suffixes, relPath, strings.HasSuffix)
+}
+
+func matchAny(patterns []string, s string, match func(s, pattern string) bool) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if match(s, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDebounce parses the --debounce flag, zero copies objects on
+// every event.
+func parseDebounce(cliCtx *cli.Context) (time.Duration, *probe.Error) {
+	value := cliCtx.String("debounce")
+	if value == "" {
+		return 0, nil
+	}
+	d, e := time.ParseDuration(value)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(value)
+	}
+	if d <= 0 {
+		return 0, errInvalidArgument().Trace(value)
+	}
+	return d, nil
+}
+
+// checkMirrorWatchSyntax returns why --watch-prefix, --watch-suffix and
+// --debounce cannot be used with the other flags passed, empty if they
+// can.
+func checkMirrorWatchSyntax(cliCtx *cli.Context) string {
+	isWatch := cliCtx.Bool("watch") || cliCtx.Bool("active-active") || cliCtx.Bool("multi-master")
+	for _, name := range []string{"watch-prefix", "watch-suffix", "debounce"} {
+		if cliCtx.IsSet(name) && !isWatch {
+			return "--" + name + " can only be used with --watch."
+		}
+	}
+	for _, prefix := range cliCtx.StringSlice("watch-prefix") {
+		if strings.TrimPrefix(prefix, "/") == "" {
+			return "--watch-prefix cannot be empty."
+		}
+	}
+	for _, suffix := range cliCtx.StringSlice("watch-suffix") {
+		if suffix == "" {
+			return "--watch-suffix cannot be empty."
+		}
+	}
+	if _, err := parseDebounce(cliCtx); err != nil {
+		return "Unable to parse --debounce, expected a positive duration, e.g. '10s'."
+	}
+	return ""
+}
+
+// watchDebouncer delays the copy of an object written by successive
+// events until no event was received for the object during the quiet
+// period, only its last event is mirrored.
+type watchDebouncer struct {
+	quiet time.Duration
+	fired chan func()
+	stop  <-chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+func newWatchDebouncer(quiet time.Duration, stop <-chan struct{}) *watchDebouncer {
+	return &watchDebouncer{
+		quiet:   quiet,
+		fired:   make(chan func()),
+		stop:    stop,
+		pending: make(map[string]*time.Timer),
+	}
+}
+
+// Add replaces the pending task of a key, which is sent to Fired once
+// the key was quiet.
+func (d *watchDebouncer) Add(key string, task func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if timer, ok := d.pending[key]; ok {
+		timer.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(d.quiet, func() {
+		d.mu.Lock()
+		if d.pending[key] != timer {
+			// Replaced or canceled meanwhile.
+			d.mu.Unlock()
+			return
+		}
+		delete(d.pending, key)
+		d.mu.Unlock()
+
+		select {
+		case d.fired <- task:
+		case <-d.stop:
+		}
+	})
+	d.pending[key] = timer
+}
+
+// Cancel drops the pending task of a key, e.g. once it was removed.
+func (d *watchDebouncer) Cancel(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if timer, ok := d.pending[key]; ok {
+		timer.Stop()
+		delete(d.pending, key)
+	}
+}
+
+// Pending returns the number of keys waiting for their quiet period.
+func (d *watchDebouncer) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.pending)
+}
+
+// Fired receives the tasks of the keys which were quiet, a nil
+// debouncer never fires.
+func (d *watchDebouncer) Fired() <-chan func() {
+	if d == nil {
+		return nil
+	}
+	return d.fired
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestWatchEventFilter(t *testing.T) {
+	testCases := []struct {
+		prefixes, suffixes []string
+		relPath            string
+		expected           bool
+	}{
+		{nil, nil, "/any/object", true},
+		{[]string{"app/"}, nil, "/app/server.log", true},
+		{[]string{"app/"}, nil, "/db/server.log", false},
+		{nil, []string{".log"}, "/app/server.log", true},
+		{nil, []string{".log", ".txt"}, "/app/notes.txt", true},
+		{nil, []string{".log"}, "/app/server.log.tmp", false},
+		{[]string{"app/", "web/"}, []string{".log"}, "/web/access.log", true},
+		{[]string{"app/"}, []string{".log"}, "/app/server.tmp", false},
+	}
+	for i, testCase := range testCases {
+		filter := newWatchEventFilter(testCase.prefixes, testCase.suffixes)
+		if match := filter.Match(testCase.relPath); match != testCase.expected {
+			t.Errorf("Test %d: expected %v for %s, got %v", i+1, testCase.expected, testCase.relPath, match)
+		}
+	}
+}
+
+func TestCheckMirrorWatchSyntax(t *testing.T) {
+	flags := append(append([]cli.Flag{}, mirrorFlags...), mirrorWatchFlags...)
+	testCases := []struct {
+		args     []string
+		expected string
+		debounce time.Duration
+	}{
+		{[]string{"--watch"}, "", 0},
+		{[]string{"--watch", "--watch-prefix", "app/", "--watch-suffix", ".log", "--debounce", "10s"}, "", 10 * time.Second},
+		{[]string{"--active-active", "--debounce", "1m"}, "", time.Minute},
+		{[]string{"--watch-suffix", ".log"}, "--watch-suffix can only be used with --watch.", 0},
+		{[]string{"--debounce", "10s"}, "--debounce can only be used with --watch.", 0},
+		{[]string{"--watch", "--watch-prefix", "/"}, "--watch-prefix cannot be empty.", 0},
+		{[]string{"--watch", "--watch-suffix", ""}, "--watch-suffix cannot be empty.", 0},
+		{[]string{"--watch", "--debounce", "soon"}, "Unable to parse --debounce, expected a positive duration, e.g. '10s'.", 0},
+		{[]string{"--watch", "--debounce", "-1s"}, "Unable to parse --debounce, expected a positive duration, e.g. '10s'.", 0},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, flags, testCase.args...)
+		if msg := checkMirrorWatchSyntax(cliCtx); msg != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, msg)
+			continue
+		}
+		if testCase.expected != "" {
+			continue
+		}
+		if debounce, err := parseDebounce(cliCtx); err != nil || debounce != testCase.debounce {
+			t.Errorf("Test %d: expected a debounce of %s, got %s (%v)", i+1, testCase.debounce, debounce, err)
+		}
+	}
+}
+
+func TestWatchDebouncer(t *testing.T) {
+	stop := make(chan struct{})
+	defer close(stop)
+	d := newWatchDebouncer(50*time.Millisecond, stop)
+
+	// Successive events of a key only fire the last one.
+	var fired []string
+	for _, event := range []string{"1", "2", "3"} {
+		event := event
+		d.Add("a.log", func() { fired = append(fired, "a.log:"+event) })
+		time.Sleep(10 * time.Millisecond)
+	}
+	d.Add("b.log", func() { fired = append(fired, "b.log") })
+	d.Add("c.log", func() { fired = append(fired, "c.log") })
+	d.Cancel("c.log")
+	if d.Pending() != 2 {
+		t.Fatalf("expected 2 pending keys, got %d", d.Pending())
+	}
+
+	for len(fired) < 2 {
+		select {
+		case task := <-d.Fired():
+			task()
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected two tasks to fire, got %v", fired)
+		}
+	}
+	select {
+	case task := <-d.Fired():
+		task()
+		t.Fatalf("expected no other task to fire, got %v", fired)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if len(fired) != 2 || !(fired[0] == "a.log:3" || fired[1] == "a.log:3") {
+		t.Fatalf("expected the last event of a.log and b.log, got %v", fired)
+	}
+	if d.Pending() != 0 {
+		t.Fatalf("expected no pending keys, got %d", d.Pending())
+	}
+
+	// A nil debouncer never fires.
+	var none *watchDebouncer
+	if none.Fired() != nil {
+		t.Fatal("expected a nil channel")
+	}
+}
@@ -49,9 +49,10 @@ var (
 			Usage: "set storage class for new object(s) on target",
 		},
 		cli.StringFlag{
-			Name:  "encrypt",
+			Name:  "encrypt, enc-s3",
 			Usage: "encrypt/decrypt objects (using server-side encryption with server managed keys)",
 		},
+		encKMSFlag,
 		cli.StringFlag{
 			Name:  "attr",
 			Usage: "add custom metadata for the object",
@@ -78,7 +79,7 @@ var mvCmd = cli.Command{
 	Action:       mainMove,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(mvFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(append(mvFlags, statsFlag), ioFlags...), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -91,6 +92,7 @@ FLAGS:
 ENVIRONMENT VARIABLES:
   MC_ENCRYPT:      list of comma delimited prefixes
   MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENC_KMS:      list of comma delimited prefix=keyid values
 
 EXAMPLES:
   01. Move a list of objects from local file system to Amazon S3 cloud storage.
@@ -141,6 +143,9 @@ EXAMPLES:
 
   16. Move a text file to an object storage and disable multipart upload feature.
       {{.Prompt}} {{.HelpName}} --disable-multipart myobject.txt play/mybucket
+
+  17. Move a folder to a bucket, encrypting the objects with the KMS key "my-minio-key" on the server.
+      {{.Prompt}} {{.HelpName}} --recursive --enc-kms "play/mybucket=my-minio-key" /tmp/reports/ play/mybucket
 `,
 }
 
@@ -240,6 +245,7 @@ func mainMove(cliCtx *cli.Context) error {
 
 	// Additional command speific theme customization.
 	console.SetColor("Copy", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Stats", color.New(color.Bold))
 
 	recursive := cliCtx.Bool("recursive")
 	olderThan := cliCtx.String("older-than")
@@ -255,6 +261,10 @@ func mainMove(cliCtx *cli.Context) error {
 		fatalIf(err, "Unable to parse encryption keys.")
 	}
 	sse := cliCtx.String("encrypt")
+	sseKMS := os.Getenv("MC_ENC_KMS")
+	if kms := cliCtx.String("enc-kms"); kms != "" {
+		sseKMS = kms
+	}
 
 	var session *sessionV8
 
@@ -272,6 +282,7 @@ func mainMove(cliCtx *cli.Context) error {
 			session.Header.CommandStringFlags["storage-class"] = storageClass
 			session.Header.CommandStringFlags["encrypt-key"] = sseKeys
 			session.Header.CommandStringFlags["encrypt"] = sse
+			session.Header.CommandStringFlags["enc-kms"] = sseKMS
 			session.Header.CommandBoolFlags["session"] = cliCtx.Bool("continue")
 
 			if cliCtx.Bool("preserve") {
@@ -0,0 +1,164 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+var nameTransformFlag = cli.StringSliceFlag{
+	Name:  "name-transform",
+	Usage: "rewrite the keys of a recursive copy with sed-like rules applied in order, e.g. 's|^logs/|archive/logs/|'",
+}
+
+var stripComponentsFlag = cli.IntFlag{
+	Name:  "strip-components",
+	Usage: "remove the given number of leading path elements from the keys of a recursive copy, like tar",
+}
+
+// nameTransformRule is a single 's/regex/replacement/flags' rule.
+type nameTransformRule struct {
+	re          *regexp.Regexp
+	replacement string
+	global      bool
+}
+
+// nameTransform rewrites the keys of the objects of a recursive copy,
+// relative to the source, before they are joined to the target.
+type nameTransform struct {
+	// Number of leading path elements removed, see --strip-components.
+	strip int
+	rules []nameTransformRule
+}
+
+// Apply returns key without its stripped path elements, rewritten by
+// every rule in order. Keys with no more elements than stripped are
+// empty, a nil transform keeps keys as they are.
+func (t *nameTransform) Apply(key string) string {
+	if t == nil {
+		return key
+	}
+	for i := 0; i < t.strip; i++ {
+		_, rest, found := strings.Cut(key, "/")
+		if !found {
+			return ""
+		}
+		key = rest
+	}
+	for _, rule := range t.rules {
+		if rule.global {
+			key = rule.re.ReplaceAllString(key, rule.replacement)
+			continue
+		}
+		match := rule.re.FindStringSubmatchIndex(key)
+		if match == nil {
+			continue
+		}
+		var replaced []byte
+		replaced = rule.re.ExpandString(replaced, rule.replacement, key, match)
+		key = key[:match[0]] + string(replaced) + key[match[1]:]
+	}
+	return key
+}
+
+// splitSedExpr splits a sed expression on its unescaped delimiters.
+func splitSedExpr(s string, delim byte) []string {
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			part.WriteString(s[i : i+2])
+			i++
+		case s[i] == delim:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(s[i])
+		}
+	}
+	return append(parts, part.String())
+}
+
+// sedReplacement converts the replacement of a sed expression, where
+// '\1' and '&' refer to the groups, to the template of regexp.Expand.
+func sedReplacement(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			b.WriteString("${" + s[i+1:i+2] + "}")
+			i++
+		case s[i] == '\\' && i+1 < len(s):
+			if s[i+1] == '$' {
+				b.WriteString("$$")
+			} else {
+				b.WriteByte(s[i+1])
+			}
+			i++
+		case s[i] == '&':
+			b.WriteString("${0}")
+		case s[i] == '$':
+			b.WriteString("$$")
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// mustParseNameTransform parses the --name-transform rules.
+func mustParseNameTransform(exprs []string, strip int) *nameTransform {
+	t, err := parseNameTransform(exprs, strip)
+	fatalIf(err, "Unable to parse --name-transform, expected 's/regex/replacement/' or 's/regex/replacement/g'.")
+	return t
+}
+
+// parseNameTransform parses the --name-transform rules applied after
+// stripping strip path elements, it returns nil without any.
+func parseNameTransform(exprs []string, strip int) (*nameTransform, *probe.Error) {
+	if len(exprs) == 0 && strip <= 0 {
+		return nil, nil
+	}
+	t := &nameTransform{strip: strip}
+	for _, expr := range exprs {
+		if len(expr) < 2 || expr[0] != 's' {
+			return nil, errInvalidArgument().Trace(expr)
+		}
+		parts := splitSedExpr(expr[2:], expr[1])
+		if len(parts) != 3 || strings.Trim(parts[2], "g") != "" {
+			return nil, errInvalidArgument().Trace(expr)
+		}
+		// An escaped delimiter is a literal one.
+		pattern := strings.ReplaceAll(parts[0], `\`+expr[1:2], regexp.QuoteMeta(expr[1:2]))
+		re, e := regexp.Compile(pattern)
+		if e != nil {
+			return nil, probe.NewError(e).Trace(expr)
+		}
+		t.rules = append(t.rules, nameTransformRule{
+			re:          re,
+			replacement: sedReplacement(parts[1]),
+			global:      parts[2] == "g",
+		})
+	}
+	return t, nil
+}
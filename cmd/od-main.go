@@ -128,7 +128,7 @@ func prepareOdUrls(ctx context.Context, sourceURL, sourceVersion, targetURL stri
 	targetAlias, targetURL, _ := mustExpandAlias(targetURL)
 
 	// Placeholder encryption key database
-	var encKeyDB map[string][]prefixSSEPair
+	var encKeyDB encryptionConfig
 
 	_, sourceContent, err := url2Stat(ctx, sourceURL, sourceVersion, false, encKeyDB, time.Time{}, false)
 	if err != nil {
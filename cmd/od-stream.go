@@ -132,7 +132,7 @@ func odCopy(ctx context.Context, odURLs URLs, args argKVS, odType string) (odMes
 	}
 
 	// Placeholder encryption key database.
-	var encKeyDB map[string][]prefixSSEPair
+	var encKeyDB encryptionConfig
 
 	// Create reader from source.
 	reader, err := getSourceStreamFromURL(ctx, sourcePath, encKeyDB, getSourceOpts{GetOptions: getOpts})
@@ -0,0 +1,160 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Timeout of the delivery of the results to a webhook.
+const outputTargetWebhookTimeout = 30 * time.Second
+
+// outputTarget collects the JSON results of a command, to deliver them
+// as JSON lines to an object or a webhook when mc exits.
+type outputTarget struct {
+	// Object URL or webhook URL, with its placeholders expanded.
+	target    string
+	isWebhook bool
+
+	mu        sync.Mutex
+	spoolFile *os.File
+	spoolErr  error
+}
+
+// globalOutputTarget is set by --output-target.
+var globalOutputTarget *outputTarget
+
+// newOutputTarget parses the value of --output-target, '{date}' and
+// '{time}' are replaced by the UTC date and time of the run.
+func newOutputTarget(target string, now time.Time) (*outputTarget, error) {
+	now = now.UTC()
+	target = strings.NewReplacer(
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+	).Replace(target)
+
+	t := &outputTarget{target: target, isWebhook: urlRgx.MatchString(target)}
+	if !t.isWebhook {
+		_, _, hostCfg, err := expandAlias(target)
+		if err != nil {
+			return nil, err.ToGoError()
+		}
+		if hostCfg == nil {
+			return nil, fmt.Errorf("invalid output target `%s`, expected an object of an alias or a webhook URL", target)
+		}
+		if strings.HasSuffix(target, "/") {
+			return nil, fmt.Errorf("invalid output target `%s`, expected an object name", target)
+		}
+	}
+	return t, nil
+}
+
+// record adds the JSON result of a message, a nil target records
+// nothing.
+func (t *outputTarget) record(jsonStr string) {
+	if t == nil {
+		return
+	}
+	var line bytes.Buffer
+	if e := json.Compact(&line, []byte(jsonStr)); e != nil {
+		line.Reset()
+		line.WriteString(strings.TrimSpace(jsonStr))
+	}
+	line.WriteByte('\n')
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.spoolErr != nil {
+		return
+	}
+	if t.spoolFile == nil {
+		if t.spoolFile, t.spoolErr = os.CreateTemp("", "mc-output-*.jsonl"); t.spoolErr != nil {
+			return
+		}
+	}
+	_, t.spoolErr = t.spoolFile.Write(line.Bytes())
+}
+
+// deliver sends the recorded results to the target.
+func (t *outputTarget) deliver() *probe.Error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.spoolErr != nil {
+		return probe.NewError(t.spoolErr)
+	}
+	var body io.Reader = bytes.NewReader(nil)
+	var size int64
+	if t.spoolFile != nil {
+		defer os.Remove(t.spoolFile.Name())
+		defer t.spoolFile.Close()
+		offset, e := t.spoolFile.Seek(0, io.SeekCurrent)
+		if e != nil {
+			return probe.NewError(e)
+		}
+		if _, e = t.spoolFile.Seek(0, io.SeekStart); e != nil {
+			return probe.NewError(e)
+		}
+		body, size = t.spoolFile, offset
+	}
+
+	if !t.isWebhook {
+		_, err := putTargetStreamWithURL(t.target, body, size, PutOptions{})
+		return err.Trace(t.target)
+	}
+
+	// The global context may already be canceled by an interrupt.
+	ctx, cancel := context.WithTimeout(context.Background(), outputTargetWebhookTimeout)
+	defer cancel()
+	req, e := http.NewRequestWithContext(ctx, http.MethodPost, t.target, body)
+	if e != nil {
+		return probe.NewError(e).Trace(t.target)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, e := httpClient(outputTargetWebhookTimeout).Do(req)
+	if e != nil {
+		return probe.NewError(e).Trace(t.target)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return probe.NewError(fmt.Errorf("webhook responded with %s", resp.Status)).Trace(t.target)
+	}
+	return nil
+}
+
+// flushOutputTarget delivers the results to --output-target, it is
+// registered as an exit hook.
+func flushOutputTarget() {
+	if globalOutputTarget == nil {
+		return
+	}
+	errorIf(globalOutputTarget.deliver(), "Unable to deliver the results to `"+globalOutputTarget.target+"`.")
+}
@@ -33,6 +33,10 @@ const (
 	// Maximum number of parallel workers
 	maxParallelWorkers = 128
 
+	// Maximum number of parallel workers of server side copies, which
+	// transfer no data through the client.
+	maxServerSideWorkers = 1024
+
 	// Monitor tick to decide to add new workers
 	monitorPeriod = 4 * time.Second
 )
@@ -49,6 +53,9 @@ type task struct {
 	barrier bool
 	// The total size of the information that we need to upload
 	uploadSize int64
+	// The top level prefix of the object, tasks of a sized parallel
+	// manager are balanced across prefixes.
+	prefix string
 }
 
 // ParallelManager - helps manage parallel workers to run tasks
@@ -59,6 +66,9 @@ type ParallelManager struct {
 	// aligned at 64bit. See https://github.com/golang/go/issues/599
 	sentBytes int64
 
+	// Tasks queued or in progress, kept 64bit aligned as well.
+	pending int64
+
 	// Synchronize workers
 	wg          *sync.WaitGroup
 	barrierSync sync.RWMutex
@@ -66,6 +76,9 @@ type ParallelManager struct {
 	// Current threads number
 	workersNum uint32
 
+	// Maximum threads number
+	maxWorkers uint32
+
 	// Channel to receive tasks to run
 	queueCh chan task
 
@@ -76,11 +89,15 @@ type ParallelManager struct {
 
 	// The maximum memory to use
 	maxMem uint64
+
+	// Queues of the small and large object workers, nil when all
+	// workers read queueCh.
+	scheduler *taskScheduler
 }
 
 // addWorker creates a new worker to process tasks
 func (p *ParallelManager) addWorker() {
-	if atomic.LoadUint32(&p.workersNum) >= maxParallelWorkers {
+	if atomic.LoadUint32(&p.workersNum) >= p.maxWorkers {
 		// Number of maximum workers is reached, no need to
 		// to create a new one.
 		return
@@ -88,6 +105,7 @@ func (p *ParallelManager) addWorker() {
 
 	// Update number of threads
 	atomic.AddUint32(&p.workersNum, 1)
+	transferWorkers.Inc()
 
 	// Start a new worker
 	p.wg.Add(1)
@@ -97,22 +115,31 @@ func (p *ParallelManager) addWorker() {
 			t, ok := <-p.queueCh
 			if !ok {
 				// No more tasks, quit
+				transferWorkers.Dec()
 				p.wg.Done()
 				return
 			}
 
-			// Execute the task and send the result to channel.
-			p.resultCh <- t.fn()
-
-			if t.barrier {
-				p.barrierSync.Unlock()
-			} else {
-				p.barrierSync.RUnlock()
-			}
+			p.runTask(t)
 		}
 	}()
 }
 
+// runTask executes a task and sends its result to the result channel.
+func (p *ParallelManager) runTask(t task) {
+	// Execute the task and send the result to channel.
+	urls := t.fn()
+	transferQueuedTasks.Dec()
+	atomic.AddInt64(&p.pending, -1)
+	p.resultCh <- urls
+
+	if t.barrier {
+		p.barrierSync.Unlock()
+	} else {
+		p.barrierSync.RUnlock()
+	}
+}
+
 func (p *ParallelManager) Read(b []byte) (n int, err error) {
 	atomic.AddInt64(&p.sentBytes, int64(len(b)))
 	return len(b), nil
@@ -167,6 +194,12 @@ func (p *ParallelManager) queueTask(fn func() URLs, uploadSize int64) {
 	p.doQueueTask(task{fn: fn, uploadSize: uploadSize})
 }
 
+// Queue task of an object under a top level prefix, a sized parallel
+// manager balances the tasks across prefixes.
+func (p *ParallelManager) queuePrefixedTask(prefix string, fn func() URLs, uploadSize int64) {
+	p.doQueueTask(task{fn: fn, uploadSize: uploadSize, prefix: prefix})
+}
+
 // Queue task but ensures that no tasks is running at parallel,
 // which also means wait until all concurrent tasks finish before
 // queueing this and execute it solely.
@@ -205,6 +238,8 @@ func (p *ParallelManager) enoughMemForUpload(uploadSize int64) bool {
 }
 
 func (p *ParallelManager) doQueueTask(t task) {
+	transferQueuedTasks.Inc()
+	atomic.AddInt64(&p.pending, 1)
 	// Check if we have enough memory to perform next task,
 	// if not, wait to finish all currents tasks to continue
 	if !p.enoughMemForUpload(t.uploadSize) {
@@ -215,12 +250,25 @@ func (p *ParallelManager) doQueueTask(t task) {
 	} else {
 		p.barrierSync.RLock()
 	}
+	if p.scheduler != nil {
+		p.scheduler.push(t)
+		return
+	}
 	p.queueCh <- t
 }
 
+// pendingTasks returns the number of tasks queued or in progress.
+func (p *ParallelManager) pendingTasks() int64 {
+	return atomic.LoadInt64(&p.pending)
+}
+
 // Wait for all workers to finish tasks before shutting down Parallel
 func (p *ParallelManager) stopAndWait() {
-	close(p.queueCh)
+	if p.scheduler != nil {
+		p.scheduler.close()
+	} else {
+		close(p.queueCh)
+	}
 	p.wg.Wait()
 	close(p.stopMonitorCh)
 }
@@ -270,6 +318,7 @@ func newParallelManager(resultCh chan URLs) *ParallelManager {
 	p := &ParallelManager{
 		wg:            &sync.WaitGroup{},
 		workersNum:    0,
+		maxWorkers:    maxParallelWorkers,
 		stopMonitorCh: make(chan struct{}),
 		queueCh:       make(chan task),
 		resultCh:      resultCh,
@@ -286,3 +335,20 @@ func newParallelManager(resultCh chan URLs) *ParallelManager {
 
 	return p
 }
+
+// newServerSideParallelManager starts all the workers of server side
+// copies at once, their transfer speed tells nothing as no data goes
+// through the client.
+func newServerSideParallelManager(resultCh chan URLs, workers int) *ParallelManager {
+	p := &ParallelManager{
+		wg:            &sync.WaitGroup{},
+		maxWorkers:    maxServerSideWorkers,
+		stopMonitorCh: make(chan struct{}),
+		queueCh:       make(chan task),
+		resultCh:      resultCh,
+	}
+	for i := 0; i < workers; i++ {
+		p.addWorker()
+	}
+	return p
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Workers of 'mirror' when only one of --small-object-workers and
+// --large-object-workers is set.
+const (
+	defaultSmallObjectWorkers = 32
+	defaultLargeObjectWorkers = 4
+)
+
+// sizedWorkersFlags split the workers of 'mirror' between small and
+// large objects.
+var sizedWorkersFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "small-object-workers",
+		Usage: "number of workers copying objects smaller than --large-object-size, balanced across the top level prefixes",
+	},
+	cli.IntFlag{
+		Name:  "large-object-workers",
+		Usage: "number of workers copying objects of --large-object-size or more, and small objects when idle",
+	},
+	cli.StringFlag{
+		Name:  "large-object-size",
+		Usage: "size from which objects are copied by the large object workers",
+		Value: "64MiB",
+	},
+}
+
+// sizedWorkers holds the workers requested by --small-object-workers
+// and --large-object-workers, zero workers keep the default pool.
+type sizedWorkers struct {
+	small, large int
+	largeSize    int64
+}
+
+// parseSizedWorkers parses --small-object-workers,
+// --large-object-workers and --large-object-size.
+func parseSizedWorkers(cliCtx *cli.Context) (sizedWorkers, *probe.Error) {
+	if !cliCtx.IsSet("small-object-workers") && !cliCtx.IsSet("large-object-workers") {
+		return sizedWorkers{}, nil
+	}
+	w := sizedWorkers{small: defaultSmallObjectWorkers, large: defaultLargeObjectWorkers}
+	if cliCtx.IsSet("small-object-workers") {
+		w.small = cliCtx.Int("small-object-workers")
+	}
+	if cliCtx.IsSet("large-object-workers") {
+		w.large = cliCtx.Int("large-object-workers")
+	}
+	value := cliCtx.String("large-object-size")
+	size, e := humanize.ParseBytes(value)
+	if e != nil {
+		return sizedWorkers{}, probe.NewError(e).Trace(value)
+	}
+	if size == 0 {
+		return sizedWorkers{}, errInvalidArgument().Trace(value)
+	}
+	w.largeSize = int64(size)
+	return w, nil
+}
+
+// checkSizedWorkersSyntax returns why --small-object-workers,
+// --large-object-workers and --large-object-size cannot be used with
+// the other flags passed, empty if they can.
+func checkSizedWorkersSyntax(cliCtx *cli.Context) string {
+	isSized := cliCtx.IsSet("small-object-workers") || cliCtx.IsSet("large-object-workers")
+	if !isSized {
+		if cliCtx.IsSet("large-object-size") {
+			return "--large-object-size can only be used with --small-object-workers or --large-object-workers."
+		}
+		return ""
+	}
+	if cliCtx.Bool("server-side") {
+		return "--small-object-workers and --large-object-workers cannot be used with --server-side."
+	}
+	w, err := parseSizedWorkers(cliCtx)
+	if err != nil {
+		return "Unable to parse --large-object-size, expected a size, e.g. '64MiB'."
+	}
+	if w.small <= 0 || w.large <= 0 {
+		return "--small-object-workers and --large-object-workers must be positive."
+	}
+	if w.small+w.large > maxParallelWorkers {
+		return fmt.Sprintf("--small-object-workers and --large-object-workers cannot add up to more than %d workers.", maxParallelWorkers)
+	}
+	return ""
+}
+
+// Maximum number of tasks queued by a sized parallel manager, queueing
+// more tasks blocks like the workers of the other managers do.
+const maxScheduledTasks = 10000
+
+// prefixQueues queues tasks per top level prefix, and pops them round
+// robin across prefixes so that a prefix holding millions of objects
+// does not hold back the others.
+type prefixQueues struct {
+	order []string
+	next  int
+	tasks map[string][]task
+}
+
+func (q *prefixQueues) push(t task) {
+	if q.tasks == nil {
+		q.tasks = make(map[string][]task)
+	}
+	if len(q.tasks[t.prefix]) == 0 {
+		q.order = append(q.order, t.prefix)
+	}
+	q.tasks[t.prefix] = append(q.tasks[t.prefix], t)
+}
+
+func (q *prefixQueues) pop() (task, bool) {
+	if len(q.order) == 0 {
+		return task{}, false
+	}
+	if q.next >= len(q.order) {
+		q.next = 0
+	}
+	prefix := q.order[q.next]
+	tasks := q.tasks[prefix]
+	t := tasks[0]
+	tasks[0] = task{}
+	if tasks = tasks[1:]; len(tasks) == 0 {
+		delete(q.tasks, prefix)
+		q.order = append(q.order[:q.next], q.order[q.next+1:]...)
+	} else {
+		q.tasks[prefix] = tasks
+		q.next++
+	}
+	return t, true
+}
+
+// taskScheduler queues the tasks of small and large objects apart. The
+// small object workers only copy small objects, so that the number of
+// large objects copied at once is bounded, while the large object
+// workers copy small objects when no large object is queued.
+type taskScheduler struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	small     prefixQueues
+	large     prefixQueues
+	largeSize int64
+	queued    int
+	maxQueued int
+	closed    bool
+}
+
+func newTaskScheduler(largeSize int64, maxQueued int) *taskScheduler {
+	s := &taskScheduler{largeSize: largeSize, maxQueued: maxQueued}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+// push queues a task, it blocks while the queues are full.
+func (s *taskScheduler) push(t task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.queued >= s.maxQueued {
+		s.cond.Wait()
+	}
+	if t.uploadSize >= s.largeSize {
+		s.large.push(t)
+	} else {
+		s.small.push(t)
+	}
+	s.queued++
+	s.cond.Broadcast()
+}
+
+// next returns the next task of a small or large object worker, it
+// returns false once the scheduler is closed and its queues are empty.
+func (s *taskScheduler) next(large bool) (task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		t, ok := task{}, false
+		if large {
+			t, ok = s.large.pop()
+		}
+		if !ok {
+			t, ok = s.small.pop()
+		}
+		if ok {
+			s.queued--
+			s.cond.Broadcast()
+			return t, true
+		}
+		if s.closed {
+			return task{}, false
+		}
+		s.cond.Wait()
+	}
+}
+
+// close stops the workers once the queued tasks are done.
+func (s *taskScheduler) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.cond.Broadcast()
+}
+
+// newSizedParallelManager starts a fixed number of small and large
+// object workers, objects of at least largeSize are large.
+func newSizedParallelManager(resultCh chan URLs, smallWorkers, largeWorkers int, largeSize int64) *ParallelManager {
+	p := &ParallelManager{
+		wg:            &sync.WaitGroup{},
+		maxWorkers:    uint32(smallWorkers + largeWorkers),
+		stopMonitorCh: make(chan struct{}),
+		resultCh:      resultCh,
+		maxMem:        availableMemory(),
+		scheduler:     newTaskScheduler(largeSize, maxScheduledTasks),
+	}
+	for i := 0; i < smallWorkers; i++ {
+		p.addSizedWorker(false)
+	}
+	for i := 0; i < largeWorkers; i++ {
+		p.addSizedWorker(true)
+	}
+	return p
+}
+
+// addSizedWorker creates a new small or large object worker.
+func (p *ParallelManager) addSizedWorker(large bool) {
+	atomic.AddUint32(&p.workersNum, 1)
+	transferWorkers.Inc()
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer transferWorkers.Dec()
+		for {
+			t, ok := p.scheduler.next(large)
+			if !ok {
+				return
+			}
+			p.runTask(t)
+		}
+	}()
+}
+
+// topPrefix returns the top level prefix of a path relative to the
+// source of a bulk copy, empty for the objects at the top level.
+func topPrefix(relPath string) string {
+	relPath = strings.TrimPrefix(relPath, "/")
+	if i := strings.Index(relPath, "/"); i >= 0 {
+		return relPath[:i+1]
+	}
+	return ""
+}
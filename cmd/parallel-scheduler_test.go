@@ -0,0 +1,166 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestTopPrefix(t *testing.T) {
+	testCases := map[string]string{
+		"":               "",
+		"object":         "",
+		"/object":        "",
+		"/dir/object":    "dir/",
+		"dir/sub/object": "dir/",
+	}
+	for relPath, expected := range testCases {
+		if prefix := topPrefix(relPath); prefix != expected {
+			t.Errorf("%q: expected %q, got %q", relPath, expected, prefix)
+		}
+	}
+}
+
+func TestPrefixQueues(t *testing.T) {
+	var q prefixQueues
+	for _, name := range []string{"a/1", "a/2", "a/3", "b/1", "c/1", "c/2"} {
+		q.push(task{prefix: topPrefix(name), uploadSize: int64(len(name)), fn: func(name string) func() URLs {
+			return func() URLs { return URLs{TargetAlias: name} }
+		}(name)})
+	}
+
+	// Tasks are popped round robin across prefixes, in order per prefix.
+	var popped []string
+	for {
+		t, ok := q.pop()
+		if !ok {
+			break
+		}
+		popped = append(popped, t.fn().TargetAlias)
+	}
+	expected := []string{"a/1", "b/1", "c/1", "a/2", "c/2", "a/3"}
+	if !reflect.DeepEqual(popped, expected) {
+		t.Fatalf("expected %v, got %v", expected, popped)
+	}
+}
+
+func TestTaskScheduler(t *testing.T) {
+	s := newTaskScheduler(100, 10)
+	s.push(task{prefix: "a/", uploadSize: 10})
+	s.push(task{prefix: "b/", uploadSize: 1000})
+	s.push(task{prefix: "a/", uploadSize: 20})
+
+	// Large object workers take large objects first, then small ones.
+	if t1, _ := s.next(true); t1.uploadSize != 1000 {
+		t.Fatalf("expected the large object, got %d", t1.uploadSize)
+	}
+	if t2, _ := s.next(true); t2.uploadSize != 10 {
+		t.Fatalf("expected a small object to be taken by an idle large object worker, got %d", t2.uploadSize)
+	}
+
+	// Small object workers never take large objects.
+	s.push(task{prefix: "c/", uploadSize: 500})
+	if t3, _ := s.next(false); t3.uploadSize != 20 {
+		t.Fatalf("expected the small object, got %d", t3.uploadSize)
+	}
+	s.close()
+	if _, ok := s.next(false); ok {
+		t.Fatal("expected a small object worker to stop without small objects")
+	}
+	if t4, ok := s.next(true); !ok || t4.uploadSize != 500 {
+		t.Fatal("expected the queued large object to be taken once closed")
+	}
+	if _, ok := s.next(true); ok {
+		t.Fatal("expected the workers to stop once closed and empty")
+	}
+}
+
+func TestSizedParallelManager(t *testing.T) {
+	resultCh := make(chan URLs)
+	p := newSizedParallelManager(resultCh, 3, 1, 100)
+	if p.workersNum != 4 {
+		t.Fatalf("expected 4 workers, got %d", p.workersNum)
+	}
+
+	var results []string
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for urls := range resultCh {
+			results = append(results, urls.TargetAlias)
+		}
+	}()
+	var expected []string
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("dir%d/object%d", i%5, i)
+		expected = append(expected, name)
+		p.queuePrefixedTask(topPrefix(name), func() URLs {
+			return URLs{TargetAlias: name}
+		}, int64(i*10))
+	}
+	p.stopAndWait()
+	close(resultCh)
+	wg.Wait()
+
+	sort.Strings(results)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(results, expected) {
+		t.Fatalf("expected every task to run once, got %v", results)
+	}
+	if p.pendingTasks() != 0 {
+		t.Fatalf("expected no pending tasks, got %d", p.pendingTasks())
+	}
+}
+
+func TestCheckSizedWorkersSyntax(t *testing.T) {
+	flags := append(append([]cli.Flag{}, sizedWorkersFlags...), serverSideFlag)
+	testCases := []struct {
+		args     []string
+		expected string
+		workers  sizedWorkers
+	}{
+		{nil, "", sizedWorkers{}},
+		{[]string{"--small-object-workers", "64"}, "", sizedWorkers{64, defaultLargeObjectWorkers, 64 << 20}},
+		{[]string{"--large-object-workers", "2", "--large-object-size", "1GiB"}, "", sizedWorkers{defaultSmallObjectWorkers, 2, 1 << 30}},
+		{[]string{"--large-object-size", "1GiB"}, "--large-object-size can only be used with --small-object-workers or --large-object-workers.", sizedWorkers{}},
+		{[]string{"--small-object-workers", "0"}, "--small-object-workers and --large-object-workers must be positive.", sizedWorkers{}},
+		{[]string{"--small-object-workers", "100", "--large-object-workers", "100"}, "--small-object-workers and --large-object-workers cannot add up to more than 128 workers.", sizedWorkers{}},
+		{[]string{"--small-object-workers", "8", "--large-object-size", "big"}, "Unable to parse --large-object-size, expected a size, e.g. '64MiB'.", sizedWorkers{}},
+		{[]string{"--small-object-workers", "8", "--server-side"}, "--small-object-workers and --large-object-workers cannot be used with --server-side.", sizedWorkers{}},
+	}
+	for i, testCase := range testCases {
+		cliCtx := newTestCLIContext(t, flags, testCase.args...)
+		if msg := checkSizedWorkersSyntax(cliCtx); msg != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, msg)
+			continue
+		}
+		if testCase.expected != "" {
+			continue
+		}
+		if workers, err := parseSizedWorkers(cliCtx); err != nil || workers != testCase.workers {
+			t.Errorf("Test %d: expected %+v, got %+v (%v)", i+1, testCase.workers, workers, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/wildcard"
+)
+
+// pathFilterRules holds the path filter flags of the command line in
+// their order, which a slice flag per flag name would not keep.
+var pathFilterRules []string
+
+// pathFilterFlags select the objects of a recursive copy by their path
+// relative to the source.
+var pathFilterFlags = newPathFilterFlags("copy", &pathFilterRules)
+
+// rmPathFilterRules holds the path filter flags of 'rm'.
+var rmPathFilterRules []string
+
+// rmPathFilterFlags select the objects of a recursive removal by their
+// path relative to the target.
+var rmPathFilterFlags = newPathFilterFlags("remove", &rmPathFilterRules)
+
+// newPathFilterFlags returns the path filter flags of a command which
+// verb the objects selected.
+func newPathFilterFlags(verb string, rules *[]string) []cli.Flag {
+	return []cli.Flag{
+		cli.GenericFlag{
+			Name:  "include",
+			Usage: verb + " objects matching a glob pattern, e.g. '*.csv'",
+			Value: &pathFilterValue{prefix: pathFilterInclude, rules: rules},
+		},
+		cli.GenericFlag{
+			Name:  "exclude",
+			Usage: "skip objects matching a glob pattern, e.g. 'tmp/*'",
+			Value: &pathFilterValue{prefix: pathFilterExclude, rules: rules},
+		},
+		cli.GenericFlag{
+			Name:  "exclude-regex",
+			Usage: "skip objects matching a regular expression, e.g. '\\.v[0-9]+$'",
+			Value: &pathFilterValue{prefix: pathFilterExcludeRegex, rules: rules},
+		},
+	}
+}
+
+// pathFilterValue is the value of one path filter flag, every value
+// set is appended with the prefix of the flag to the shared rules.
+type pathFilterValue struct {
+	prefix string
+	rules  *[]string
+}
+
+func (v *pathFilterValue) Set(value string) error {
+	*v.rules = append(*v.rules, v.prefix+value)
+	return nil
+}
+
+func (v *pathFilterValue) String() string {
+	return ""
+}
+
+// Prefixes of the serialized rules of a path filter.
+const (
+	pathFilterInclude      = "+"
+	pathFilterExclude      = "-"
+	pathFilterExcludeRegex = "~"
+)
+
+// pathFilterRule is a single --include, --exclude or --exclude-regex.
+type pathFilterRule struct {
+	include bool
+	glob    string
+	re      *regexp.Regexp
+}
+
+func (r pathFilterRule) match(relPath string) bool {
+	if r.re != nil {
+		return r.re.MatchString(relPath)
+	}
+	// Patterns without a slash also match the base name at any depth.
+	if !strings.Contains(r.glob, "/") && wildcard.Match(r.glob, path.Base(relPath)) {
+		return true
+	}
+	return wildcard.Match(r.glob, relPath)
+}
+
+// pathFilter selects objects by their path relative to the source with
+// --include, --exclude and --exclude-regex. The last pattern matching
+// a path decides. Paths matching no pattern are copied, unless the
+// first pattern is an --include.
+type pathFilter struct {
+	rules []pathFilterRule
+}
+
+// Match returns true if an object is selected, a nil filter selects
+// every object.
+func (f *pathFilter) Match(relPath string) bool {
+	if f == nil || len(f.rules) == 0 {
+		return true
+	}
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "/")
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].match(relPath) {
+			return f.rules[i].include
+		}
+	}
+	return !f.rules[0].include
+}
+
+// pathFilterFromContext serializes the path filter flags of a command
+// one per line in their order.
+func pathFilterFromContext(ctx *cli.Context) string {
+	v, ok := ctx.Generic("include").(*pathFilterValue)
+	if !ok {
+		return ""
+	}
+	return strings.Join(*v.rules, "\n")
+}
+
+// mustParsePathFilter parses the path filter flags of a command.
+func mustParsePathFilter(ctx *cli.Context) *pathFilter {
+	f, err := parsePathFilter(pathFilterFromContext(ctx))
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse the path filter.")
+	return f
+}
+
+// parsePathFilter parses a path filter serialized by pathFilterFromContext,
+// it returns nil for an empty one.
+func parsePathFilter(s string) (*pathFilter, *probe.Error) {
+	if s == "" {
+		return nil, nil
+	}
+	f := &pathFilter{}
+	for _, line := range strings.Split(s, "\n") {
+		if line == "" {
+			continue
+		}
+		prefix, pattern := line[:1], line[1:]
+		switch prefix {
+		case pathFilterInclude, pathFilterExclude:
+			f.rules = append(f.rules, pathFilterRule{include: prefix == pathFilterInclude, glob: pattern})
+		case pathFilterExcludeRegex:
+			re, e := regexp.Compile(pattern)
+			if e != nil {
+				return nil, probe.NewError(fmt.Errorf("invalid --exclude-regex `%s`: %w", pattern, e))
+			}
+			f.rules = append(f.rules, pathFilterRule{re: re})
+		default:
+			return nil, errInvalidArgument().Trace(line)
+		}
+	}
+	return f, nil
+}
@@ -18,15 +18,19 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"runtime/debug"
 	"syscall"
 
 	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
 	"github.com/minio/cli"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7"
+	"github.com/minio/pkg/console"
 )
 
 func defaultPartSize() string {
@@ -36,9 +40,10 @@ func defaultPartSize() string {
 
 var pipeFlags = []cli.Flag{
 	cli.StringFlag{
-		Name:  "encrypt",
+		Name:  "encrypt, enc-s3",
 		Usage: "encrypt objects (using server-side encryption with server managed keys)",
 	},
+	encKMSFlag,
 	cli.StringFlag{
 		Name:  "storage-class, sc",
 		Usage: "set storage class for new object(s) on target",
@@ -52,7 +57,7 @@ var pipeFlags = []cli.Flag{
 		Usage: "apply one or more tags to the uploaded objects",
 	},
 	cli.IntFlag{
-		Name:  "concurrent",
+		Name:  "concurrent, concurrency",
 		Value: 1,
 		Usage: "allow N concurrent uploads [WARNING: will use more memory use it with caution]",
 	},
@@ -61,6 +66,15 @@ var pipeFlags = []cli.Flag{
 		Value: defaultPartSize(),
 		Usage: "customize chunk size for each concurrent upload",
 	},
+	cli.StringFlag{
+		Name:  "checksum",
+		Usage: "upload with an additional checksum, one of CRC32C, SHA1, SHA256 or CRC64NVME",
+	},
+	cseKeyFlag,
+	cli.StringFlag{
+		Name:  "resume-id",
+		Usage: "keep the multipart upload of an S3 target if the stream breaks, run again with the same ID and stream to resume it",
+	},
 	cli.IntFlag{
 		Name:   "pipe-max-size",
 		Usage:  "increase the pipe buffer size to a custom value",
@@ -86,8 +100,10 @@ FLAGS:
   {{range .VisibleFlags}}{{.}}
   {{end}}{{end}}
 ENVIRONMENT VARIABLES:
-  MC_ENCRYPT:      list of comma delimited prefix values
-  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+  MC_ENCRYPT:         list of comma delimited prefix values
+  MC_ENCRYPT_KEY:     list of comma delimited prefix=secret values
+  MC_ENC_KMS:         list of comma delimited prefix=keyid values
+  MC_CSE_PASSPHRASE:  passphrase of the OpenPGP secret keys of --cse-key
 
 EXAMPLES:
   1. Write contents of stdin to a file on local filesystem.
@@ -110,10 +126,44 @@ EXAMPLES:
 
   7. Set tags to the uploaded objects
       {{.Prompt}} tar cvf - . | {{.HelpName}} --tags "category=prod&type=backup" play/mybucket/backup.tar
+
+  8. Stream a large database dump with 128MiB parts uploaded 4 at a time, resuming the upload if the stream breaks
+     by running the same command again.
+      {{.Prompt}} pg_dump --no-sync accountsdb | {{.HelpName}} --part-size 128MiB --concurrency 4 --resume-id accountsdb-dump play/sql-backups/accountsdb.sql
+
+  9. Stream a database dump encrypted on the client for an OpenPGP public key.
+      {{.Prompt}} mysqldump -u root -p ******* accountsdb | {{.HelpName}} --cse-key backup-public.asc s3/sql-backups/accountsdb.sql.gpg
+
+  10. Stream a database dump encrypted with the KMS key "my-minio-key" on the server.
+      {{.Prompt}} mysqldump -u root -p ******* accountsdb | {{.HelpName}} --enc-kms "s3/sql-backups=my-minio-key" s3/sql-backups/accountsdb.sql
+
+  11. Stream a database dump with a SHA256 checksum of every part sent as it is uploaded, printing the checksum of the object.
+      {{.Prompt}} mysqldump -u root -p ******* accountsdb | {{.HelpName}} --checksum SHA256 --json s3/sql-backups/accountsdb.sql
 `,
 }
 
-func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPair, meta map[string]string) *probe.Error {
+// pipeMessage is printed once a stream uploaded with a checksum.
+type pipeMessage struct {
+	Status   string `json:"status"`
+	Target   string `json:"target"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"checksum"`
+}
+
+// String colorized pipe message
+func (p pipeMessage) String() string {
+	return console.Colorize("Pipe", fmt.Sprintf("`%s` (%s, %s)", p.Target, humanize.IBytes(uint64(p.Size)), p.Checksum))
+}
+
+// JSON jsonified pipe message
+func (p pipeMessage) JSON() string {
+	p.Status = "success"
+	pipeMessageBytes, e := json.MarshalIndent(p, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(pipeMessageBytes)
+}
+
+func pipe(ctx *cli.Context, targetURL string, encKeyDB encryptionConfig, meta map[string]string) *probe.Error {
 	// If possible increase the pipe buffer size
 	if e := increasePipeBufferSize(os.Stdin, ctx.Int("pipe-max-size")); e != nil {
 		fatalIf(probe.NewError(e), "Unable to increase custom pipe-max-size")
@@ -154,10 +204,38 @@ func pipe(ctx *cli.Context, targetURL string, encKeyDB map[string][]prefixSSEPai
 		multipartThreads: uint(multipartThreads),
 		concurrentStream: ctx.IsSet("concurrent"),
 	}
+	if checksum := ctx.String("checksum"); checksum != "" {
+		algorithm, err := parseChecksumAlgorithm(checksum)
+		if err != nil {
+			return err.Trace(checksum)
+		}
+		opts.checksum = &uploadChecksum{Algorithm: algorithm}
+	}
 
 	pg := newProgressBar(0)
 
-	_, err := putTargetStreamWithURL(targetURL, io.TeeReader(os.Stdin, pg), -1, opts)
+	var stdin io.Reader = os.Stdin
+	if keyFile := ctx.String("cse-key"); keyFile != "" {
+		key, err := loadCSEKey(keyFile)
+		if err != nil {
+			return err.Trace(keyFile)
+		}
+		stdin = encryptReader(os.Stdin, key)
+		opts.metadata[metadataCSEKey] = cseFormatOpenPGP
+	}
+
+	if resumeID := ctx.String("resume-id"); resumeID != "" {
+		return pipeResumable(globalContext, targetURL, resumeID, io.TeeReader(stdin, pg), opts).Trace(targetURL)
+	}
+
+	n, err := putTargetStreamWithURL(targetURL, io.TeeReader(stdin, pg), -1, opts)
+	if err == nil && opts.checksum != nil {
+		printMsg(pipeMessage{
+			Target:   targetURL,
+			Size:     n,
+			Checksum: opts.checksum.Algorithm + ":" + opts.checksum.Value,
+		})
+	}
 	// TODO: See if this check is necessary.
 	switch e := err.ToGoError().(type) {
 	case *os.PathError:
@@ -174,12 +252,29 @@ func checkPipeSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code.
 	}
+	if ctx.IsSet("resume-id") {
+		if msg := checkPipeResumeID(ctx.String("resume-id")); msg != "" {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), msg)
+		}
+		// Every run encrypts the stream with a new session key.
+		if ctx.IsSet("cse-key") {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--resume-id cannot be used with --cse-key")
+		}
+		if ctx.IsSet("checksum") {
+			fatalIf(errInvalidArgument().Trace(ctx.Args()...), "--resume-id cannot be used with --checksum")
+		}
+	}
+	if checksum := ctx.String("checksum"); checksum != "" {
+		_, err := parseChecksumAlgorithm(checksum)
+		fatalIf(err.Trace(checksum), "Unable to validate --checksum.")
+	}
 }
 
 // mainPipe is the main entry point for pipe command.
 func mainPipe(ctx *cli.Context) error {
 	// validate pipe input arguments.
 	checkPipeSyntax(ctx)
+	console.SetColor("Pipe", color.New(color.FgGreen, color.Bold))
 	// Parse encryption keys per command.
 	encKeyDB, err := getEncKeys(ctx)
 	fatalIf(err, "Unable to parse encryption keys.")
@@ -202,6 +297,9 @@ func mainPipe(ctx *cli.Context) error {
 		// extract URLs.
 		URLs := ctx.Args()
 		err = pipe(ctx, URLs[0], encKeyDB, meta)
+		if resumeID := ctx.String("resume-id"); resumeID != "" {
+			fatalIf(err.Trace(URLs[0]), "Unable to write to one or more targets, run again with `--resume-id %s` to resume the upload.", resumeID)
+		}
 		fatalIf(err.Trace(URLs[0]), "Unable to write to one or more targets.")
 	}
 
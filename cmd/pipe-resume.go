@@ -0,0 +1,289 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// pipeResumeState is saved in the session folder while a resumable
+// pipe is in progress, it names the multipart upload to continue.
+type pipeResumeState struct {
+	Target   string `json:"target"`
+	UploadID string `json:"uploadId"`
+	PartSize int64  `json:"partSize"`
+}
+
+// checkPipeResumeID returns an error message if a resume ID cannot
+// name a file of the session folder.
+func checkPipeResumeID(resumeID string) string {
+	if resumeID == "" || resumeID == "." || resumeID == ".." || strings.ContainsAny(resumeID, `/\`) {
+		return fmt.Sprintf("invalid --resume-id `%s`, it cannot be empty or contain path separators", resumeID)
+	}
+	return ""
+}
+
+// getPipeResumeFile - get the state file of a resumable pipe.
+func getPipeResumeFile(resumeID string) (string, *probe.Error) {
+	sessionDir, err := getSessionDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+	return filepath.Join(sessionDir, resumeID+".pipe"), nil
+}
+
+// loadPipeResumeState returns the state saved by a previous run of a
+// resumable pipe, nil if there is none.
+func loadPipeResumeState(resumeID string) (*pipeResumeState, *probe.Error) {
+	stateFile, err := getPipeResumeFile(resumeID)
+	if err != nil {
+		return nil, err.Trace(resumeID)
+	}
+	data, e := os.ReadFile(stateFile)
+	if os.IsNotExist(e) {
+		return nil, nil
+	}
+	if e != nil {
+		return nil, probe.NewError(e).Trace(stateFile)
+	}
+	state := &pipeResumeState{}
+	if e = json.Unmarshal(data, state); e != nil {
+		return nil, probe.NewError(e).Trace(stateFile)
+	}
+	return state, nil
+}
+
+// savePipeResumeState saves the state of a resumable pipe.
+func savePipeResumeState(resumeID string, state *pipeResumeState) *probe.Error {
+	if err := createSessionDir(); err != nil {
+		return err.Trace()
+	}
+	stateFile, err := getPipeResumeFile(resumeID)
+	if err != nil {
+		return err.Trace(resumeID)
+	}
+	data, e := json.Marshal(state)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e = os.WriteFile(stateFile, data, 0o600); e != nil {
+		return probe.NewError(e).Trace(stateFile)
+	}
+	return nil
+}
+
+// removePipeResumeState removes the state of a completed pipe.
+func removePipeResumeState(resumeID string) *probe.Error {
+	stateFile, err := getPipeResumeFile(resumeID)
+	if err != nil {
+		return err.Trace(resumeID)
+	}
+	if e := os.Remove(stateFile); e != nil && !os.IsNotExist(e) {
+		return probe.NewError(e).Trace(stateFile)
+	}
+	return nil
+}
+
+// resumePartsOffset returns the parts of an upload which can be kept
+// and the offset of the stream where the upload continues. Only parts
+// of the full part size numbered without gaps from 1 can be kept, the
+// stream is consumed in order.
+func resumePartsOffset(parts map[int]minio.ObjectPart, partSize int64) ([]minio.CompletePart, int64) {
+	var kept []minio.CompletePart
+	for partNumber := 1; ; partNumber++ {
+		part, ok := parts[partNumber]
+		if !ok || part.Size != partSize {
+			return kept, int64(len(kept)) * partSize
+		}
+		kept = append(kept, minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag})
+	}
+}
+
+// listUploadedParts returns the parts uploaded so far by a multipart
+// upload.
+func listUploadedParts(ctx context.Context, core *minio.Core, bucket, object, uploadID string) (map[int]minio.ObjectPart, *probe.Error) {
+	parts := make(map[int]minio.ObjectPart)
+	marker := 0
+	for {
+		result, e := core.ListObjectParts(ctx, bucket, object, uploadID, marker, 1000)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		for _, part := range result.ObjectParts {
+			parts[part.PartNumber] = part
+		}
+		if !result.IsTruncated {
+			return parts, nil
+		}
+		marker = result.NextPartNumberMarker
+	}
+}
+
+// pipeResumable streams a reader to an S3 target with a multipart
+// upload which is kept if the stream breaks. Running it again with the
+// same resume ID and the same stream skips the bytes of the parts
+// uploaded already and continues the upload.
+func pipeResumable(ctx context.Context, targetURL, resumeID string, reader io.Reader, opts PutOptions) *probe.Error {
+	alias, _ := url2Alias(targetURL)
+	clnt, err := newClientFromAlias(alias, targetURL)
+	if err != nil {
+		return err.Trace(targetURL)
+	}
+	s3Client, ok := clnt.(*S3Client)
+	if !ok {
+		return probe.NewError(errors.New("--resume-id requires an S3 target"))
+	}
+	bucket, object := s3Client.url2BucketAndObject()
+	core := &minio.Core{Client: s3Client.api}
+
+	state, err := loadPipeResumeState(resumeID)
+	if err != nil {
+		return err.Trace(resumeID)
+	}
+	if state != nil && state.Target != targetURL {
+		return probe.NewError(fmt.Errorf("resume ID `%s` belongs to a pipe to `%s`", resumeID, state.Target))
+	}
+
+	var completed []minio.CompletePart
+	if state == nil {
+		putOpts := minio.PutObjectOptions{
+			UserMetadata:         opts.metadata,
+			StorageClass:         strings.ToUpper(opts.storageClass),
+			ServerSideEncryption: opts.sse,
+		}
+		if tagsHdr, ok := opts.metadata["X-Amz-Tagging"]; ok {
+			tagsSet, e := tags.Parse(tagsHdr, true)
+			if e != nil {
+				return probe.NewError(e)
+			}
+			putOpts.UserTags = tagsSet.ToMap()
+			putOpts.UserMetadata = make(map[string]string, len(opts.metadata))
+			for k, v := range opts.metadata {
+				if k != "X-Amz-Tagging" {
+					putOpts.UserMetadata[k] = v
+				}
+			}
+		}
+		uploadID, e := core.NewMultipartUpload(ctx, bucket, object, putOpts)
+		if e != nil {
+			return probe.NewError(e).Trace(targetURL)
+		}
+		state = &pipeResumeState{Target: targetURL, UploadID: uploadID, PartSize: int64(opts.multipartSize)}
+		if err = savePipeResumeState(resumeID, state); err != nil {
+			return err.Trace(resumeID)
+		}
+	} else {
+		parts, err := listUploadedParts(ctx, core, bucket, object, state.UploadID)
+		if err != nil {
+			return err.Trace(targetURL)
+		}
+		var offset int64
+		completed, offset = resumePartsOffset(parts, state.PartSize)
+		n, e := io.CopyN(io.Discard, reader, offset)
+		if e != nil {
+			return probe.NewError(fmt.Errorf("the stream ended after %d bytes, before the %d bytes uploaded already", n, offset))
+		}
+	}
+
+	// Parts of SSE-C encrypted uploads are sent with the customer key.
+	var partOpts minio.PutObjectPartOptions
+	if opts.sse != nil && opts.sse.Type() == encrypt.SSEC {
+		partOpts.SSE = opts.sse
+	}
+
+	threads := int(opts.multipartThreads)
+	if threads < 1 {
+		threads = 1
+	}
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, threads)
+	for partNumber := len(completed) + 1; ; partNumber++ {
+		buf := make([]byte, state.PartSize)
+		n, e := io.ReadFull(reader, buf)
+		if e != nil && e != io.EOF && e != io.ErrUnexpectedEOF {
+			mutex.Lock()
+			if firstErr == nil {
+				firstErr = e
+			}
+			mutex.Unlock()
+			break
+		}
+		// An empty stream is uploaded as a single empty part.
+		if n == 0 && partNumber > 1 {
+			break
+		}
+
+		sem <- struct{}{}
+		mutex.Lock()
+		failed := firstErr != nil
+		mutex.Unlock()
+		if failed {
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(partNumber int, data []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			part, e := core.PutObjectPart(ctx, bucket, object, state.UploadID, partNumber,
+				bytes.NewReader(data), int64(len(data)), partOpts)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if e != nil {
+				if firstErr == nil {
+					firstErr = e
+				}
+				return
+			}
+			completed = append(completed, minio.CompletePart{PartNumber: partNumber, ETag: part.ETag})
+		}(partNumber, buf[:n])
+
+		if n < len(buf) {
+			break
+		}
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return probe.NewError(firstErr).Trace(targetURL)
+	}
+
+	sort.Slice(completed, func(i, j int) bool { return completed[i].PartNumber < completed[j].PartNumber })
+	if _, e := core.CompleteMultipartUpload(ctx, bucket, object, state.UploadID, completed, minio.PutObjectOptions{}); e != nil {
+		return probe.NewError(e).Trace(targetURL)
+	}
+	return removePipeResumeState(resumeID).Trace(resumeID)
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestCheckPipeResumeID(t *testing.T) {
+	testCases := []struct {
+		resumeID string
+		valid    bool
+	}{
+		{"accountsdb-dump", true},
+		{"dump.2026-10-16", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"../config", false},
+		{`dumps\accountsdb`, false},
+	}
+	for i, testCase := range testCases {
+		if valid := checkPipeResumeID(testCase.resumeID) == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected %q to be valid: %v", i+1, testCase.resumeID, testCase.valid)
+		}
+	}
+}
+
+func TestResumePartsOffset(t *testing.T) {
+	const partSize = 5
+	testCases := []struct {
+		parts    []minio.ObjectPart
+		kept     []minio.CompletePart
+		expected int64
+	}{
+		{nil, nil, 0},
+		{
+			[]minio.ObjectPart{{PartNumber: 1, ETag: "a", Size: 5}, {PartNumber: 2, ETag: "b", Size: 5}},
+			[]minio.CompletePart{{PartNumber: 1, ETag: "a"}, {PartNumber: 2, ETag: "b"}},
+			10,
+		},
+		// Parts uploaded concurrently after a missing part are uploaded again.
+		{
+			[]minio.ObjectPart{{PartNumber: 1, ETag: "a", Size: 5}, {PartNumber: 3, ETag: "c", Size: 5}},
+			[]minio.CompletePart{{PartNumber: 1, ETag: "a"}},
+			5,
+		},
+		// A short part is the end of a stream, it is uploaded again.
+		{
+			[]minio.ObjectPart{{PartNumber: 1, ETag: "a", Size: 5}, {PartNumber: 2, ETag: "b", Size: 2}},
+			[]minio.CompletePart{{PartNumber: 1, ETag: "a"}},
+			5,
+		},
+		{[]minio.ObjectPart{{PartNumber: 2, ETag: "b", Size: 5}}, nil, 0},
+	}
+	for i, testCase := range testCases {
+		parts := make(map[int]minio.ObjectPart)
+		for _, part := range testCase.parts {
+			parts[part.PartNumber] = part
+		}
+		kept, offset := resumePartsOffset(parts, partSize)
+		if offset != testCase.expected {
+			t.Errorf("Test %d: expected offset %d, got %d", i+1, testCase.expected, offset)
+		}
+		if !reflect.DeepEqual(kept, testCase.kept) {
+			t.Errorf("Test %d: expected parts %v, got %v", i+1, testCase.kept, kept)
+		}
+	}
+}
+
+func TestPipeResumeState(t *testing.T) {
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(t.TempDir())
+
+	state, err := loadPipeResumeState("dump")
+	if err != nil || state != nil {
+		t.Fatalf("expected no state, got %v (%v)", state, err)
+	}
+
+	saved := &pipeResumeState{Target: "play/bucket/dump.sql", UploadID: "upload", PartSize: 5}
+	if err = savePipeResumeState("dump", saved); err != nil {
+		t.Fatal(err)
+	}
+	state, err = loadPipeResumeState("dump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(state, saved) {
+		t.Fatalf("expected %v, got %v", saved, state)
+	}
+
+	if err = removePipeResumeState("dump"); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ = loadPipeResumeState("dump"); state != nil {
+		t.Fatalf("expected the state to be removed, got %v", state)
+	}
+	// Removing a missing state is not an error.
+	if err = removePipeResumeState("dump"); err != nil {
+		t.Fatal(err)
+	}
+}
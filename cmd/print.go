@@ -21,7 +21,10 @@ import (
 	"bytes"
 	"encoding/json"
 	"strings"
+	"text/template"
 
+	"github.com/dustin/go-humanize"
+	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/pkg/console"
 )
 
@@ -35,9 +38,17 @@ type message interface {
 func printMsg(msg message) {
 	var msgStr string
 	if !globalJSON {
-		msgStr = msg.String()
+		if globalFormatTemplate != nil {
+			msgStr = formatMsg(globalFormatTemplate, msg)
+		} else {
+			msgStr = msg.String()
+		}
+		if globalOutputTarget != nil {
+			globalOutputTarget.record(msg.JSON())
+		}
 	} else {
 		msgStr = msg.JSON()
+		globalOutputTarget.record(msgStr)
 		if globalJSONLine && strings.ContainsRune(msgStr, '\n') {
 			// Reformat.
 			var dst bytes.Buffer
@@ -47,5 +58,37 @@ func printMsg(msg message) {
 		}
 	}
 	msgStr = strings.TrimSuffix(msgStr, "\n")
+	if globalPrint0 && !globalJSON {
+		// NUL terminated entries are safe to consume with `xargs -0`.
+		console.Print(msgStr + "\x00")
+		return
+	}
 	console.Println(msgStr)
 }
+
+// formatTemplateEscapes are the escapes expanded in a --format-template,
+// which is usually passed in single quotes.
+var formatTemplateEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+// parseFormatTemplate parses a --format-template. It is executed on the
+// message of every result, the fields printed by --json are those of
+// the message.
+func parseFormatTemplate(text string) (*template.Template, error) {
+	return template.New("format-template").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, e := json.Marshal(v)
+			return string(b), e
+		},
+		"bytes": func(size int64) string {
+			return humanize.IBytes(uint64(size))
+		},
+	}).Parse(formatTemplateEscapes.Replace(text))
+}
+
+// formatMsg returns a message formatted with a --format-template.
+func formatMsg(tmpl *template.Template, msg message) string {
+	var b strings.Builder
+	e := tmpl.Execute(&b, msg)
+	fatalIf(probe.NewError(e), "Unable to print the result with --format-template.")
+	return b.String()
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import "testing"
+
+func TestFormatMsg(t *testing.T) {
+	testCases := []struct {
+		template string
+		msg      message
+		expected string
+	}{
+		{`{{.Key}}\t{{.Size}}`, contentMessage{Key: "dir/object", Size: 2048}, "dir/object\t2048"},
+		{`{{.Key}}\n{{bytes .Size}}`, contentMessage{Key: "object", Size: 2048}, "object\n2.0 KiB"},
+		{`{{.Prefix}}: {{.Objects}}`, duMessage{Prefix: "bucket/", Objects: 3}, "bucket/: 3"},
+		{`{{json .Checksums}}`, statMessage{Checksums: map[string]string{"CRC32C": "yZRlqg=="}}, `{"CRC32C":"yZRlqg=="}`},
+	}
+	for i, testCase := range testCases {
+		tmpl, e := parseFormatTemplate(testCase.template)
+		if e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		if got := formatMsg(tmpl, testCase.msg); got != testCase.expected {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.expected, got)
+		}
+	}
+
+	if _, e := parseFormatTemplate("{{.Key"); e == nil {
+		t.Error("Expected an invalid template to fail")
+	}
+}
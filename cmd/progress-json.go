@@ -0,0 +1,182 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var progressFlag = cli.StringFlag{
+	Name:  "progress",
+	Usage: "print the progress as JSON lines events instead of a progress bar, only 'json' is supported",
+}
+
+// Interval between two 'progress' events.
+const progressEventInterval = time.Second
+
+// progressEvent is a single JSON line printed by --progress json.
+type progressEvent struct {
+	Event       string  `json:"event"`
+	Time        string  `json:"time"`
+	Source      string  `json:"source,omitempty"`
+	Target      string  `json:"target,omitempty"`
+	Size        int64   `json:"size,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	Objects     int64   `json:"objects,omitempty"`
+	Transferred int64   `json:"transferred"`
+	Total       int64   `json:"total"`
+	Speed       float64 `json:"speed"`
+	ETA         float64 `json:"eta,omitempty"`
+}
+
+// jsonProgress accounts the transferred bytes like an accounter, and
+// prints them as events: 'start' and 'finish' for every object,
+// 'progress' every progressEventInterval and 'done' once finished.
+type jsonProgress struct {
+	// Keep these as first elements of struct because it guarantees
+	// 64bit alignment on 32 bit machines.
+	current int64
+	total   int64
+	objects int64
+
+	startTime  time.Time
+	finishOnce sync.Once
+	isFinished chan struct{}
+}
+
+func newJSONProgress(total int64) *jsonProgress {
+	p := &jsonProgress{
+		total:      total,
+		startTime:  time.Now(),
+		isFinished: make(chan struct{}),
+	}
+	go p.writer()
+	return p
+}
+
+// checkProgressSyntax validates the value of --progress.
+func checkProgressSyntax(cliCtx *cli.Context) {
+	if value := cliCtx.String("progress"); value != "" && value != "json" {
+		fatalIf(errInvalidArgument().Trace(value), "Unknown --progress, only 'json' is supported.")
+	}
+}
+
+// Read implements io.Reader, accounting the bytes read from the source.
+func (p *jsonProgress) Read(b []byte) (int, error) {
+	atomic.AddInt64(&p.current, int64(len(b)))
+	return len(b), nil
+}
+
+// Get returns the bytes transferred so far.
+func (p *jsonProgress) Get() int64 {
+	return atomic.LoadInt64(&p.current)
+}
+
+// Add adds n to the bytes transferred, n is negative for aborted
+// transfers.
+func (p *jsonProgress) Add(n int64) {
+	atomic.AddInt64(&p.current, n)
+}
+
+// SetTotal sets the bytes to transfer.
+func (p *jsonProgress) SetTotal(total int64) {
+	atomic.StoreInt64(&p.total, total)
+}
+
+// Start prints the 'start' event of the object of urls.
+func (p *jsonProgress) Start(urls URLs) {
+	p.print(objectEvent("start", urls))
+}
+
+// Done prints the 'finish' event of the object of urls, with its error
+// if it failed.
+func (p *jsonProgress) Done(urls URLs) {
+	event := objectEvent("finish", urls)
+	if urls.Error != nil {
+		event.Error = urls.Error.ToGoError().Error()
+	} else {
+		atomic.AddInt64(&p.objects, 1)
+	}
+	p.print(event)
+}
+
+func objectEvent(event string, urls URLs) progressEvent {
+	e := progressEvent{Event: event}
+	if urls.SourceContent != nil {
+		e.Source = filepath.ToSlash(filepath.Join(urls.SourceAlias, urls.SourceContent.URL.Path))
+		e.Size = urls.SourceContent.Size
+	}
+	if urls.TargetContent != nil {
+		e.Target = filepath.ToSlash(filepath.Join(urls.TargetAlias, urls.TargetContent.URL.Path))
+	}
+	return e
+}
+
+// Finish stops the 'progress' events and prints the 'done' event.
+func (p *jsonProgress) Finish() {
+	p.finishOnce.Do(func() {
+		close(p.isFinished)
+		p.print(p.stat("done"))
+	})
+}
+
+func (p *jsonProgress) writer() {
+	ticker := time.NewTicker(progressEventInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.isFinished:
+			return
+		case <-ticker.C:
+			p.print(p.stat("progress"))
+		}
+	}
+}
+
+// stat returns the totals and the speed since the start, the ETA is
+// estimated from the speed.
+func (p *jsonProgress) stat(event string) progressEvent {
+	e := progressEvent{
+		Event:       event,
+		Objects:     atomic.LoadInt64(&p.objects),
+		Transferred: atomic.LoadInt64(&p.current),
+		Total:       atomic.LoadInt64(&p.total),
+	}
+	if elapsed := time.Since(p.startTime).Seconds(); elapsed > 0 {
+		e.Speed = float64(e.Transferred) / elapsed
+	}
+	if e.Speed > 0 && e.Total > e.Transferred {
+		e.ETA = float64(e.Total-e.Transferred) / e.Speed
+	}
+	return e
+}
+
+func (p *jsonProgress) print(event progressEvent) {
+	event.Time = time.Now().UTC().Format(time.RFC3339Nano)
+	b, e := json.Marshal(event)
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	console.Println(string(b))
+}
@@ -50,7 +50,7 @@ var rbCmd = cli.Command{
 	Action:       mainRemoveBucket,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(rbFlags, globalFlags...),
+	Flags:        append(append(rbFlags, overrideProtectionFlag), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -72,6 +72,9 @@ EXAMPLES:
 
   4. Remove all buckets and objects recursively from S3 host
      {{.Prompt}} {{.HelpName}} --force --dangerous s3
+
+  5. Remove bucket 'staging' even though it is protected with 'mc admin cluster bucket protect'.
+     {{.Prompt}} {{.HelpName}} --force --override-protection s3/staging
 `,
 }
 
@@ -298,6 +301,9 @@ func mainRemoveBucket(cliCtx *cli.Context) error {
 			bucketsURL = []string{targetURL}
 		}
 
+		for _, bucketURL := range bucketsURL {
+			checkDeleteProtection(cliCtx, bucketURL)
+		}
 		for _, bucketURL := range bucketsURL {
 			e := deleteBucket(ctx, bucketURL, isForce)
 			fatalIf(e.Trace(bucketURL), "Failed to remove `"+bucketURL+"`.")
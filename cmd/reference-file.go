@@ -0,0 +1,75 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// referenceFileFlags are shared by the commands selecting objects by
+// their age.
+var referenceFileFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "newer-than-file",
+		Usage: "copy objects modified after this file or object, like find -newer",
+	},
+	cli.StringFlag{
+		Name:  "older-than-file",
+		Usage: "copy objects not modified after this file or object",
+	},
+}
+
+// parseOlderNewerThan returns the values of --older-than and
+// --newer-than, --older-than-file and --newer-than-file are replaced by
+// the modification time of their file.
+func parseOlderNewerThan(ctx context.Context, cliCtx *cli.Context) (olderThan, newerThan string, err *probe.Error) {
+	olderThan, err = referenceTime(ctx, cliCtx, "older-than")
+	if err != nil {
+		return "", "", err
+	}
+	newerThan, err = referenceTime(ctx, cliCtx, "newer-than")
+	if err != nil {
+		return "", "", err
+	}
+	return olderThan, newerThan, nil
+}
+
+// referenceTime returns the value of the flag named name, or the
+// modification time of the file of its '-file' variant.
+func referenceTime(ctx context.Context, cliCtx *cli.Context, name string) (string, *probe.Error) {
+	file := cliCtx.String(name + "-file")
+	if file == "" {
+		return cliCtx.String(name), nil
+	}
+	if cliCtx.String(name) != "" {
+		return "", errInvalidArgument().Trace("--"+name, "--"+name+"-file")
+	}
+	clnt, err := newClient(file)
+	if err != nil {
+		return "", err.Trace(file)
+	}
+	content, err := clnt.Stat(ctx, StatOptions{})
+	if err != nil {
+		return "", err.Trace(file)
+	}
+	return content.Time.UTC().Format(time.RFC3339Nano), nil
+}
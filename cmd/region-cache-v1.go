@@ -0,0 +1,178 @@
+// Copyright (c) 2015-2022 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/quick"
+)
+
+// JSON file to persist discovered bucket regions across runs.
+type regionCacheV1 struct {
+	Version string `json:"version"`
+	mutex   *sync.Mutex
+
+	// key is host/bucket, value is the bucket region.
+	Regions map[string]string `json:"regions"`
+
+	// key is host/bucket, value is when discovering the bucket
+	// region failed, it is not discovered again until regionUnknownTTL.
+	Unknown map[string]time.Time `json:"unknown,omitempty"`
+}
+
+// Duration for which buckets whose region could not be discovered,
+// e.g. without GetBucketLocation permission, are not looked up again.
+const regionUnknownTTL = time.Hour
+
+// Instantiate a new region cache structure for persistence.
+func newRegionCacheV1() *regionCacheV1 {
+	r := &regionCacheV1{
+		Version: "1",
+	}
+	r.Regions = make(map[string]string)
+	r.Unknown = make(map[string]time.Time)
+	r.mutex = &sync.Mutex{}
+	return r
+}
+
+// Get returns the cached region of a bucket, empty if unknown.
+func (r *regionCacheV1) Get(host, bucket string) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.Regions[host+"/"+bucket]
+}
+
+// Lookup returns the cached region of a bucket, ok is false unless the
+// region is cached or discovering it failed recently.
+func (r *regionCacheV1) Lookup(host, bucket string) (region string, ok bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if region, ok = r.Regions[host+"/"+bucket]; ok {
+		return region, true
+	}
+	failed, ok := r.Unknown[host+"/"+bucket]
+	return "", ok && time.Since(failed) < regionUnknownTTL
+}
+
+// SetUnknown remembers that discovering the region of a bucket failed.
+func (r *regionCacheV1) SetUnknown(host, bucket string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.Unknown[host+"/"+bucket] = UTCNow()
+	return r.save(mustGetRegionCacheFile())
+}
+
+// Set region of a bucket and persist it.
+func (r *regionCacheV1) Set(host, bucket, region string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if current, ok := r.Regions[host+"/"+bucket]; ok && current == region {
+		return nil
+	}
+	r.Regions[host+"/"+bucket] = region
+	delete(r.Unknown, host+"/"+bucket)
+	return r.save(mustGetRegionCacheFile())
+}
+
+// Delete region of a bucket if it exists and persist the change.
+func (r *regionCacheV1) Delete(host, bucket string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, known := r.Regions[host+"/"+bucket]
+	_, unknown := r.Unknown[host+"/"+bucket]
+	if !known && !unknown {
+		return nil
+	}
+	delete(r.Regions, host+"/"+bucket)
+	delete(r.Unknown, host+"/"+bucket)
+	return r.save(mustGetRegionCacheFile())
+}
+
+// Load region cache entries from disk. Any entries held in memory are reset.
+func (r *regionCacheV1) Load(filename string) *probe.Error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// Check if the cache file exist.
+	if _, e := os.Stat(filename); e != nil {
+		return probe.NewError(e)
+	}
+
+	// Initialize and load using quick package.
+	qs, e := quick.NewConfig(newRegionCacheV1(), nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	e = qs.Load(filename)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+
+	r.Regions = make(map[string]string)
+	for k, v := range qs.Data().(*regionCacheV1).Regions {
+		r.Regions[k] = v
+	}
+	r.Unknown = make(map[string]time.Time)
+	for k, v := range qs.Data().(*regionCacheV1).Unknown {
+		r.Unknown[k] = v
+	}
+	return nil
+}
+
+// Persist region cache to disk.
+func (r regionCacheV1) save(filename string) *probe.Error {
+	// Initialize a new quick file.
+	qs, e := quick.NewConfig(r, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e := qs.Save(filename); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// Get region cache file name or die. (NOTE: This `Die` approach is only OK for mc like tools.).
+func mustGetRegionCacheFile() string {
+	return filepath.Join(mustGetMcConfigDir(), globalMCRegionCacheFile)
+}
+
+var (
+	regionCacheOnce sync.Once
+	regionCache     *regionCacheV1
+)
+
+// getRegionCache returns the region cache, loaded once from disk.
+func getRegionCache() *regionCacheV1 {
+	regionCacheOnce.Do(func() {
+		regionCache = newRegionCacheV1()
+		// A missing or unreadable cache is not fatal, regions are discovered again.
+		regionCache.Load(mustGetRegionCacheFile())
+	})
+	return regionCache
+}
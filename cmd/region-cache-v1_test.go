@@ -0,0 +1,80 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegionCache(t *testing.T) {
+	defer setMcConfigDir(mcCustomConfigDir)
+	setMcConfigDir(t.TempDir())
+
+	const host = "s3.amazonaws.com"
+	r := newRegionCacheV1()
+	if _, ok := r.Lookup(host, "photos"); ok {
+		t.Fatal("expected an empty cache to know no region")
+	}
+
+	if err := r.Set(host, "photos", "eu-west-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetUnknown(host, "private"); err != nil {
+		t.Fatal(err)
+	}
+	if region, ok := r.Lookup(host, "photos"); !ok || region != "eu-west-1" {
+		t.Fatalf("expected eu-west-1, got %q (%v)", region, ok)
+	}
+	if region, ok := r.Lookup(host, "private"); !ok || region != "" {
+		t.Fatalf("expected a failed discovery to be remembered, got %q (%v)", region, ok)
+	}
+
+	// Entries are persisted, failed discoveries included.
+	loaded := newRegionCacheV1()
+	if err := loaded.Load(mustGetRegionCacheFile()); err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Get(host, "photos"); got != "eu-west-1" {
+		t.Fatalf("expected eu-west-1 to be loaded, got %q", got)
+	}
+	if _, ok := loaded.Lookup(host, "private"); !ok {
+		t.Fatal("expected the failed discovery to be loaded")
+	}
+
+	// Failed discoveries are retried once they expire.
+	loaded.Unknown[host+"/private"] = UTCNow().Add(-regionUnknownTTL - time.Minute)
+	if _, ok := loaded.Lookup(host, "private"); ok {
+		t.Fatal("expected an expired failed discovery to be looked up again")
+	}
+
+	// A discovered region replaces a failed discovery.
+	if err := r.Set(host, "private", "us-west-2"); err != nil {
+		t.Fatal(err)
+	}
+	if region, ok := r.Lookup(host, "private"); !ok || region != "us-west-2" {
+		t.Fatalf("expected us-west-2, got %q (%v)", region, ok)
+	}
+
+	if err := r.Delete(host, "photos"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.Lookup(host, "photos"); ok {
+		t.Fatal("expected a deleted region to be unknown")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Multi-object delete requests sent at the same time by default.
+const defaultRemoveWorkers = 4
+
+var rmFilesFromFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "files-from",
+		Usage: "remove only the keys listed in a newline or NUL delimited file ('-' for stdin), relative to the target, without listing it",
+	},
+	cli.IntFlag{
+		Name:  "remove-workers",
+		Usage: "number of multi-object delete requests, of up to 1000 keys each, sent at the same time with --files-from",
+		Value: defaultRemoveWorkers,
+	},
+	cli.StringFlag{
+		Name:  "failed-log",
+		Usage: "write the keys which could not be removed to this file, to be removed again with --files-from",
+	},
+}
+
+// checkRmFilesFromSyntax - returns why --files-from, --remove-workers
+// and --failed-log cannot be used with the other flags passed, empty if
+// they can.
+func checkRmFilesFromSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.IsSet("files-from") {
+		if cliCtx.IsSet("remove-workers") || cliCtx.IsSet("failed-log") {
+			return "--remove-workers and --failed-log require --files-from."
+		}
+		return ""
+	}
+	switch {
+	case len(cliCtx.Args()) != 1:
+		return "--files-from requires exactly one target, the folder the keys are relative to."
+	case !cliCtx.Bool("force"):
+		return "Removal requires --force flag. This operation is *IRREVERSIBLE*. Please review carefully before performing this *DANGEROUS* operation."
+	case cliCtx.Int("remove-workers") < 1:
+		return "--remove-workers must be at least 1."
+	}
+	for _, flag := range []string{"recursive", "versions", "version-id", "rewind", "stdin", "incomplete", "trash", "purge", "non-current", "older-than", "newer-than", "filter", "size", "include", "exclude", "exclude-regex"} {
+		if cliCtx.IsSet(flag) {
+			return "--files-from cannot be used with --" + flag + ", the listed keys are removed without looking them up."
+		}
+	}
+	return ""
+}
+
+// removeFilesFrom removes the keys of a --files-from manifest below
+// targetURL. The keys are passed to workers Remove calls of the target
+// client at the same time, each sending multi-object delete requests of
+// up to 1000 keys. Keys which cannot be removed are written to failed.
+func removeFilesFrom(ctx context.Context, targetURL string, r io.Reader, workers int, failed *failedLog, opts removeOpts) error {
+	targetAlias, urlStr, _ := mustExpandAlias(targetURL)
+	clnt, err := newClientFromAlias(targetAlias, urlStr)
+	if err != nil {
+		errorIf(err.Trace(targetURL), "Unable to initialize target `"+targetURL+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keysCh := make(chan string)
+	errCh := make(chan *probe.Error, 1)
+	go func() {
+		defer close(keysCh)
+		errCh <- readManifestKeys(ctx, r, keysCh)
+	}()
+
+	if opts.isFake {
+		for key := range keysCh {
+			printMsg(rmMessage{Key: urlJoinPath(targetURL, key), DryRun: true})
+			opts.stats.Succeeded(0)
+		}
+		if err := <-errCh; err != nil {
+			errorIf(err.Trace(targetURL), "Unable to read the keys to remove.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		return nil
+	}
+
+	var mu sync.Mutex
+	var failures int
+	contentCh := make(chan *ClientContent)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range clnt.Remove(ctx, false, false, opts.isBypass, false, contentCh) {
+				key := path.Join(targetAlias, result.BucketName, result.ObjectName)
+				if result.Err != nil {
+					errorIf(result.Err.Trace(key), "Failed to remove `"+key+"`.")
+					opts.stats.Failed()
+					failed.Add(URLs{SourceContent: &ClientContent{URL: *newClientURL(path.Join("/", result.BucketName, result.ObjectName))}})
+					mu.Lock()
+					failures++
+					mu.Unlock()
+					continue
+				}
+				msg := rmMessage{Key: key, VersionID: result.ObjectVersionID}
+				if result.DeleteMarker {
+					msg.DeleteMarker = true
+					msg.VersionID = result.DeleteMarkerVersionID
+				}
+				opts.stats.Succeeded(0)
+				printMsg(msg)
+			}
+		}()
+	}
+	workersDone := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(workersDone)
+	}()
+
+	// Workers stop early on errors they cannot go on after, the keys
+	// left are not read then.
+	stopped := false
+	for key := range keysCh {
+		select {
+		case contentCh <- &ClientContent{URL: *newClientURL(urlJoinPath(urlStr, key))}:
+			continue
+		case <-workersDone:
+			stopped = true
+		}
+		break
+	}
+	close(contentCh)
+	<-workersDone
+	cancel()
+
+	if err := <-errCh; err != nil && !stopped {
+		errorIf(err.Trace(targetURL), "Unable to read the keys to remove.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	if failures > 0 || stopped {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
+
+// mainRmFilesFrom removes the keys of the --files-from manifest.
+func mainRmFilesFrom(ctx context.Context, cliCtx *cli.Context, opts removeOpts) error {
+	filesFrom := cliCtx.String("files-from")
+	var r io.Reader = os.Stdin
+	if filesFrom != "-" {
+		f, e := os.Open(filesFrom)
+		fatalIf(probe.NewError(e).Trace(filesFrom), "Unable to open the keys to remove.")
+		defer f.Close()
+		r = f
+	}
+
+	targetURL := cliCtx.Args().First()
+	var failed *failedLog
+	if logFile := cliCtx.String("failed-log"); logFile != "" {
+		var err *probe.Error
+		failed, err = newFailedLog(logFile, []string{targetURL})
+		fatalIf(err, "Unable to create the failed log.")
+		defer failed.Close()
+	}
+	return removeFilesFrom(ctx, targetURL, r, cliCtx.Int("remove-workers"), failed, opts)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minio/cli"
+)
+
+func TestCheckRmFilesFromSyntax(t *testing.T) {
+	flags := joinFlags(rmFlags, []cli.Flag{filterFlag}, rmFilesFromFlags)
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket/object"}, true},
+		{[]string{"--force", "--files-from", "keys.txt", "s3/bucket/"}, true},
+		{[]string{"--force", "--files-from", "-", "--remove-workers", "16", "--failed-log", "failed.txt", "--dry-run", "s3/bucket/"}, true},
+		{[]string{"--files-from", "keys.txt", "s3/bucket/"}, false},
+		{[]string{"--force", "--files-from", "keys.txt"}, false},
+		{[]string{"--force", "--files-from", "keys.txt", "s3/a/", "s3/b/"}, false},
+		{[]string{"--force", "--files-from", "keys.txt", "--remove-workers", "0", "s3/bucket/"}, false},
+		{[]string{"--force", "--files-from", "keys.txt", "--recursive", "s3/bucket/"}, false},
+		{[]string{"--force", "--files-from", "keys.txt", "--older-than", "1d", "s3/bucket/"}, false},
+		{[]string{"--force", "--remove-workers", "8", "s3/bucket/"}, false},
+		{[]string{"--force", "--failed-log", "failed.txt", "s3/bucket/"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkRmFilesFromSyntax(newTestCLIContext(t, flags, testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestRemoveFilesFrom(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"a", "dir/b", "dir/c", "d"} {
+		fpath := filepath.Join(root, file)
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(file), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	exists := func(file string) bool {
+		_, e := os.Stat(filepath.Join(root, file))
+		return e == nil
+	}
+	manifest := "./a\ndir/b\n\nmissing\nd\n"
+
+	testCases := []struct {
+		dryRun   bool
+		workers  int
+		expected map[string]bool
+	}{
+		// Nothing is removed by a dry run.
+		{true, 1, map[string]bool{"a": true, "dir/b": true, "d": true}},
+		// The listed keys are removed, missing keys are ignored.
+		{false, 3, map[string]bool{"a": false, "dir/b": false, "dir/c": true, "d": false}},
+	}
+	for i, testCase := range testCases {
+		opts := removeOpts{isFake: testCase.dryRun, stats: newBulkStats()}
+		if e := removeFilesFrom(context.Background(), root+string(os.PathSeparator), strings.NewReader(manifest), testCase.workers, nil, opts); e != nil {
+			t.Fatalf("Test %d: %v", i+1, e)
+		}
+		for file, expected := range testCase.expected {
+			if got := exists(file); got != expected {
+				t.Errorf("Test %d: expected %s to exist %t, got %t", i+1, file, expected, got)
+			}
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
@@ -101,6 +102,15 @@ var (
 			Usage:  "attempt a prefix purge, requires confirmation please use with caution - only works with '--force'",
 			Hidden: true,
 		},
+		cli.BoolFlag{
+			Name:  "trash",
+			Usage: "move object(s) into the trash of their bucket instead of removing them",
+		},
+		trashPrefixFlag,
+		cli.StringSliceFlag{
+			Name:  "size",
+			Usage: "remove only objects with sizes in a range, +N is larger and -N smaller than N, e.g. +1GiB",
+		},
 	}
 )
 
@@ -111,7 +121,7 @@ var rmCmd = cli.Command{
 	Action:       mainRm,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(rmFlags, ioFlags...), globalFlags...),
+	Flags:        joinFlags(rmFlags, []cli.Flag{statsFlag, filterFlag, overrideProtectionFlag}, rmPathFilterFlags, rmFilesFromFlags, ioFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -128,7 +138,7 @@ EXAMPLES:
   01. Remove a file.
       {{.Prompt}} {{.HelpName}} 1999/old-backup.tgz
 
-  02. Perform a fake remove operation.
+  02. Perform a fake remove operation, printing the number of objects and bytes it would reclaim.
       {{.Prompt}} {{.HelpName}} --dry-run 1999/old-backup.tgz
 
   03. Remove all objects recursively from bucket 'jazz-songs' matching the prefix 'louis'.
@@ -167,6 +177,25 @@ EXAMPLES:
   14. Perform a fake removal of object(s) versions that are non-current and older than 10 days. If top-level version is a delete 
   marker, this will also be deleted when --non-current flag is specified.
       {{.Prompt}} {{.HelpName}} s3/docs/ --recursive --force --versions --non-current --older-than 10d --dry-run
+
+  15. Remove all objects older than 90 days recursively and print a summary of the run at the end.
+      {{.Prompt}} {{.HelpName}} --recursive --force --older-than 90d --stats s3/logs/
+
+  16. Remove temporary files larger than 100MiB recursively, except those in the keep/ folder.
+      {{.Prompt}} {{.HelpName}} --recursive --force --filter 'size > 100MiB && name ~ "*.tmp" && path !~ "*/keep/*"' s3/scratch/
+
+  17. Move all objects below the prefix 'louis' into the trash of bucket 'jazz-songs', see 'mc trash' to restore them.
+      {{.Prompt}} {{.HelpName}} --recursive --force --trash s3/jazz-songs/louis/
+
+  18. Remove the keys listed in "expired.txt" below 's3/logs/', sending 16 multi-object delete requests at a time
+      and writing the keys which could not be removed to "failed.txt".
+      {{.Prompt}} {{.HelpName}} --force --files-from expired.txt --remove-workers 16 --failed-log failed.txt s3/logs/
+
+  19. Remove only the "*.tmp" files larger than 1GiB recursively below 's3/scratch/', except those in the keep/ folder.
+      {{.Prompt}} {{.HelpName}} --recursive --force --include "*.tmp" --exclude "keep/*" --size +1GiB s3/scratch/
+
+  20. Remove all objects below the prefix 'louis' sending at most 100 requests per second, to spare a shared cluster.
+      {{.Prompt}} {{.HelpName}} --recursive --force --ops-limit 100/s s3/jazz-songs/louis/
 `,
 }
 
@@ -210,8 +239,43 @@ func (r rmMessage) JSON() string {
 	return string(msgBytes)
 }
 
+// rmReclaimMessage container for the objects a dry run would remove
+// and the bytes their removal would reclaim.
+type rmReclaimMessage struct {
+	Status    string `json:"status"`
+	Type      string `json:"type"`
+	Objects   int64  `json:"objects"`
+	Versions  bool   `json:"versions,omitempty"`
+	Reclaimed int64  `json:"reclaimed"`
+}
+
+// newRmReclaimMessage returns the totals of a dry run from its stats,
+// objects are counted per version with --versions.
+func newRmReclaimMessage(stats *bulkStats, withVersions bool) rmReclaimMessage {
+	msg := stats.Message()
+	return rmReclaimMessage{Objects: msg.Objects, Versions: withVersions, Reclaimed: msg.Bytes}
+}
+
+// Colorized message for console printing.
+func (r rmReclaimMessage) String() string {
+	unit := "objects"
+	if r.Versions {
+		unit = "object versions"
+	}
+	return console.Colorize("Stats", fmt.Sprintf("DRYRUN: Would remove %d %s, reclaiming %s.", r.Objects, unit, humanize.IBytes(uint64(r.Reclaimed))))
+}
+
+// JSON'ified message for scripting.
+func (r rmReclaimMessage) JSON() string {
+	r.Status = "success"
+	r.Type = "reclaim"
+	msgBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
 // Validate command line arguments.
-func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) {
 	// Set command flags from context.
 	isForce := cliCtx.Bool("force")
 	isRecursive := cliCtx.Bool("recursive")
@@ -222,8 +286,17 @@ func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string
 	isForceDel := cliCtx.Bool("purge")
 	versionID := cliCtx.String("version-id")
 	rewind := cliCtx.String("rewind")
+	isTrash := cliCtx.Bool("trash")
 	isNamespaceRemoval := false
 
+	// The keys of --files-from are removed without looking them up.
+	if cliCtx.IsSet("files-from") || cliCtx.IsSet("remove-workers") || cliCtx.IsSet("failed-log") {
+		if msg := checkRmFilesFromSyntax(cliCtx); msg != "" {
+			fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+		}
+		return
+	}
+
 	if versionID != "" && (isRecursive || isVersions || rewind != "") {
 		fatalIf(errDummy().Trace(),
 			"You cannot specify --version-id with any of --versions, --rewind and --recursive flags.")
@@ -244,14 +317,48 @@ func checkRmSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string
 			"You cannot specify --purge with --recursive.")
 	}
 
-	if isForceDel && (isNoncurrentVersion || isVersions || cliCtx.IsSet("older-than") || cliCtx.IsSet("newer-than") || versionID != "") {
+	if isForceDel && (isNoncurrentVersion || isVersions || cliCtx.IsSet("older-than") || cliCtx.IsSet("newer-than") || cliCtx.IsSet("filter") || versionID != "") {
 		fatalIf(errDummy().Trace(),
 			"You cannot specify --purge flag with any flag(s) other than --force.")
 	}
+	if isTrash && (isVersions || isNoncurrentVersion || isForceDel || versionID != "" || rewind != "" || cliCtx.Bool("incomplete")) {
+		fatalIf(errDummy().Trace(),
+			"You cannot specify --trash with any of --versions, --non-current, --version-id, --rewind, --incomplete and --purge flags.")
+	}
+	if isTrash {
+		_, err := normalizeTrashPrefix(cliCtx.String("trash-prefix"))
+		fatalIf(err.Trace(cliCtx.String("trash-prefix")), "Invalid --trash-prefix.")
+	}
+
+	_, err := parseContentFilter(cliCtx.String("filter"))
+	fatalIf(err.Trace(cliCtx.Args()...), "Unable to parse --filter.")
+	_, err = parseSizeRanges(cliCtx.StringSlice("size"))
+	fatalIf(err.Trace(cliCtx.Args()...), "Unable to parse --size.")
+	_, err = parsePathFilter(pathFilterFromContext(cliCtx))
+	fatalIf(err.Trace(cliCtx.Args()...), "Unable to parse the path filter.")
+	if pathFilterFromContext(cliCtx) != "" && !isRecursive {
+		fatalIf(errDummy().Trace(),
+			"You cannot specify --include, --exclude or --exclude-regex without --recursive.")
+	}
+
 	for _, url := range cliCtx.Args() {
 		// clean path for aliases like s3/.
 		// Note: UNC path using / works properly in go 1.9.2 even though it breaks the UNC specification.
 		url = filepath.ToSlash(filepath.Clean(url))
+		if isTrash {
+			clnt, err := newClient(url)
+			fatalIf(err.Trace(url), "Unable to initialize target `"+url+"`.")
+			if clnt.GetURL().Type != objectStorage {
+				fatalIf(errDummy().Trace(url), "You cannot specify --trash for `"+url+"`, the trash is only supported on object storage.")
+			}
+		}
+		// Emptying whole buckets is subject to their delete protection.
+		if isRecursive {
+			_, urlPath := url2Alias(url)
+			if _, object, _ := strings.Cut(strings.Trim(urlPath, "/"), "/"); object == "" {
+				checkDeleteProtection(cliCtx, url)
+			}
+		}
 		// namespace removal applies only for non FS. So filter out if passed url represents a directory
 		dir := isAliasURLDir(ctx, url, encKeyDB, time.Time{})
 		if dir {
@@ -312,6 +419,7 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 			ignoreStatError = (st == http.StatusServiceUnavailable || ok || st == http.StatusNotFound) && (opts.isForce && opts.isForceDel)
 			if !ignoreStatError {
 				errorIf(pErr.Trace(url), "Failed to remove `"+url+"`.")
+				opts.stats.Failed()
 				return exitStatus(globalErrorExitStatus)
 			}
 		}
@@ -328,15 +436,37 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 
 	// Skip objects older than older--than parameter if specified
 	if opts.olderThan != "" && isOlder(modTime, opts.olderThan) {
+		opts.stats.Skipped()
 		return nil
 	}
 
 	// Skip objects older than older--than parameter if specified
 	if opts.newerThan != "" && isNewer(modTime, opts.newerThan) {
+		opts.stats.Skipped()
 		return nil
 	}
 
+	// Skip objects not matching --filter if specified
+	if opts.filter != nil {
+		if pErr != nil {
+			errorIf(pErr.Trace(url), "Unable to stat `"+url+"`.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		if !opts.filter.Match(content) {
+			opts.stats.Skipped()
+			return nil
+		}
+	}
+
 	targetAlias, targetURL, _ := mustExpandAlias(url)
+	if opts.trashPrefix != "" {
+		if pErr != nil {
+			errorIf(pErr.Trace(url), "Unable to stat `"+url+"`.")
+			opts.stats.Failed()
+			return exitStatus(globalErrorExitStatus)
+		}
+		return trashContent(ctx, targetAlias, content, opts)
+	}
 	if !opts.isFake {
 		clnt, pErr := newClientFromAlias(targetAlias, targetURL)
 		if pErr != nil {
@@ -357,6 +487,7 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 		for result := range resultCh {
 			if result.Err != nil {
 				errorIf(result.Err.Trace(url), "Failed to remove `"+url+"`.")
+				opts.stats.Failed()
 				switch result.Err.ToGoError().(type) {
 				case PathInsufficientPermission:
 					// Ignore Permission error.
@@ -372,10 +503,18 @@ func removeSingle(url, versionID string, opts removeOpts) error {
 				msg.DeleteMarker = true
 				msg.VersionID = result.DeleteMarkerVersionID
 			}
+			if content != nil {
+				opts.stats.Succeeded(content.Size)
+			} else {
+				opts.stats.Succeeded(0)
+			}
 			printMsg(msg)
 		}
 	} else {
 		printDryRunMsg(targetAlias, content, opts.withVersions)
+		if content != nil {
+			opts.stats.Succeeded(content.Size)
+		}
 	}
 	return nil
 }
@@ -392,7 +531,11 @@ type removeOpts struct {
 	isForceDel        bool
 	olderThan         string
 	newerThan         string
-	encKeyDB          map[string][]prefixSSEPair
+	filter            *contentFilter
+	pathFilter        *pathFilter
+	trashPrefix       string
+	encKeyDB          encryptionConfig
+	stats             *bulkStats
 }
 
 func printDryRunMsg(targetAlias string, content *ClientContent, printModTime bool) {
@@ -478,11 +621,25 @@ func listAndRemove(url string, opts removeOpts) error {
 					if !content.Time.IsZero() {
 						// Skip objects older than --older-than parameter, if specified
 						if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
+							opts.stats.Skipped()
 							continue
 						}
 
 						// Skip objects newer than --newer-than parameter if specified
 						if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
+							opts.stats.Skipped()
+							continue
+						}
+
+						// Skip objects not matching --filter if specified
+						if !opts.filter.Match(content) {
+							opts.stats.Skipped()
+							continue
+						}
+
+						// Skip objects not selected by --include and --exclude if specified
+						if !opts.pathFilter.Match(strings.TrimPrefix(content.URL.Path, clnt.GetURL().Path)) {
+							opts.stats.Skipped()
 							continue
 						}
 					} else {
@@ -492,6 +649,7 @@ func listAndRemove(url string, opts removeOpts) error {
 
 					if opts.isFake {
 						printDryRunMsg(targetAlias, content, true)
+						opts.stats.Succeeded(content.Size)
 						continue
 					}
 
@@ -500,11 +658,13 @@ func listAndRemove(url string, opts removeOpts) error {
 						select {
 						case contentCh <- content:
 							sent = true
+							opts.stats.AddBytes(content.Size)
 						case result := <-resultCh:
 							path := path.Join(targetAlias, result.BucketName, result.ObjectName)
 							if result.Err != nil {
 								errorIf(result.Err.Trace(path),
 									"Failed to remove `"+path+"`.")
+								opts.stats.Failed()
 								switch result.Err.ToGoError().(type) {
 								case PathInsufficientPermission:
 									// Ignore Permission error.
@@ -521,6 +681,7 @@ func listAndRemove(url string, opts removeOpts) error {
 								msg.DeleteMarker = true
 								msg.VersionID = result.DeleteMarkerVersionID
 							}
+							opts.stats.Succeeded(0)
 							printMsg(msg)
 						}
 					}
@@ -532,6 +693,11 @@ func listAndRemove(url string, opts removeOpts) error {
 			continue
 		}
 
+		// Objects already in the trash are not trashed again.
+		if opts.trashPrefix != "" && isTrashed(urlString, opts.trashPrefix) {
+			continue
+		}
+
 		// This will mark that we found at least one target object
 		// even that it could be ineligible for deletion. So we can
 		// inform the user that he was searching in an empty area
@@ -540,11 +706,25 @@ func listAndRemove(url string, opts removeOpts) error {
 		if !content.Time.IsZero() {
 			// Skip objects older than --older-than parameter, if specified
 			if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
+				opts.stats.Skipped()
 				continue
 			}
 
 			// Skip objects newer than --newer-than parameter if specified
 			if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
+				opts.stats.Skipped()
+				continue
+			}
+
+			// Skip objects not matching --filter if specified
+			if !opts.filter.Match(content) {
+				opts.stats.Skipped()
+				continue
+			}
+
+			// Skip objects not selected by --include and --exclude if specified
+			if !opts.pathFilter.Match(strings.TrimPrefix(content.URL.Path, clnt.GetURL().Path)) {
+				opts.stats.Skipped()
 				continue
 			}
 		} else {
@@ -552,17 +732,27 @@ func listAndRemove(url string, opts removeOpts) error {
 			continue
 		}
 
+		if opts.trashPrefix != "" {
+			if e := trashContent(ctx, targetAlias, content, opts); e != nil {
+				close(contentCh)
+				return e
+			}
+			continue
+		}
+
 		if !opts.isFake {
 			sent := false
 			for !sent {
 				select {
 				case contentCh <- content:
 					sent = true
+					opts.stats.AddBytes(content.Size)
 				case result := <-resultCh:
 					path := path.Join(targetAlias, result.BucketName, result.ObjectName)
 					if result.Err != nil {
 						errorIf(result.Err.Trace(path),
 							"Failed to remove `"+path+"`.")
+						opts.stats.Failed()
 						switch e := result.Err.ToGoError().(type) {
 						case PathInsufficientPermission:
 							// Ignore Permission error.
@@ -583,11 +773,18 @@ func listAndRemove(url string, opts removeOpts) error {
 						msg.DeleteMarker = true
 						msg.VersionID = result.DeleteMarkerVersionID
 					}
+					opts.stats.Succeeded(0)
 					printMsg(msg)
 				}
 			}
 		} else {
 			printDryRunMsg(targetAlias, content, opts.withVersions)
+			// Folders reclaim no space of their own.
+			if content.Type.IsDir() {
+				opts.stats.Succeeded(0)
+			} else {
+				opts.stats.Succeeded(content.Size)
+			}
 		}
 	}
 
@@ -599,11 +796,25 @@ func listAndRemove(url string, opts removeOpts) error {
 			if !content.Time.IsZero() {
 				// Skip objects older than --older-than parameter, if specified
 				if opts.olderThan != "" && isOlder(content.Time, opts.olderThan) {
+					opts.stats.Skipped()
 					continue
 				}
 
 				// Skip objects newer than --newer-than parameter if specified
 				if opts.newerThan != "" && isNewer(content.Time, opts.newerThan) {
+					opts.stats.Skipped()
+					continue
+				}
+
+				// Skip objects not matching --filter if specified
+				if !opts.filter.Match(content) {
+					opts.stats.Skipped()
+					continue
+				}
+
+				// Skip objects not selected by --include and --exclude if specified
+				if !opts.pathFilter.Match(strings.TrimPrefix(content.URL.Path, clnt.GetURL().Path)) {
+					opts.stats.Skipped()
 					continue
 				}
 			} else {
@@ -613,6 +824,7 @@ func listAndRemove(url string, opts removeOpts) error {
 
 			if opts.isFake {
 				printDryRunMsg(targetAlias, content, true)
+				opts.stats.Succeeded(content.Size)
 				continue
 			}
 
@@ -621,11 +833,13 @@ func listAndRemove(url string, opts removeOpts) error {
 				select {
 				case contentCh <- content:
 					sent = true
+					opts.stats.AddBytes(content.Size)
 				case result := <-resultCh:
 					path := path.Join(targetAlias, result.BucketName, result.ObjectName)
 					if result.Err != nil {
 						errorIf(result.Err.Trace(path),
 							"Failed to remove `"+path+"`.")
+						opts.stats.Failed()
 						switch result.Err.ToGoError().(type) {
 						case PathInsufficientPermission:
 							// Ignore Permission error.
@@ -642,6 +856,7 @@ func listAndRemove(url string, opts removeOpts) error {
 						msg.DeleteMarker = true
 						msg.VersionID = result.DeleteMarkerVersionID
 					}
+					opts.stats.Succeeded(0)
 					printMsg(msg)
 				}
 			}
@@ -656,6 +871,7 @@ func listAndRemove(url string, opts removeOpts) error {
 		path := path.Join(targetAlias, result.BucketName, result.ObjectName)
 		if result.Err != nil {
 			errorIf(result.Err.Trace(path), "Failed to remove `"+path+"` recursively.")
+			opts.stats.Failed()
 			switch result.Err.ToGoError().(type) {
 			case PathInsufficientPermission:
 				// Ignore Permission error.
@@ -671,6 +887,7 @@ func listAndRemove(url string, opts removeOpts) error {
 			msg.DeleteMarker = true
 			msg.VersionID = result.DeleteMarkerVersionID
 		}
+		opts.stats.Succeeded(0)
 		printMsg(msg)
 	}
 
@@ -707,12 +924,19 @@ func mainRm(cliCtx *cli.Context) error {
 	isBypass := cliCtx.Bool("bypass")
 	olderThan := cliCtx.String("older-than")
 	newerThan := cliCtx.String("newer-than")
+	sizeRange, _ := parseSizeRanges(cliCtx.StringSlice("size"))
+	filter := mustParseContentFilter(cliCtx).and(sizeRange)
+	pathFilter := mustParsePathFilter(cliCtx)
 	isForce := cliCtx.Bool("force")
 	isForceDel := cliCtx.Bool("purge")
 	withNoncurrentVersion := cliCtx.Bool("non-current")
 	withVersions := cliCtx.Bool("versions")
 	versionID := cliCtx.String("version-id")
 	rewind := parseRewindFlag(cliCtx.String("rewind"))
+	var trashPrefix string
+	if cliCtx.Bool("trash") {
+		trashPrefix, _ = normalizeTrashPrefix(cliCtx.String("trash-prefix"))
+	}
 
 	if withVersions && rewind.IsZero() {
 		rewind = time.Now().UTC()
@@ -720,6 +944,24 @@ func mainRm(cliCtx *cli.Context) error {
 
 	// Set color.
 	console.SetColor("Removed", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Trash", color.New(color.FgGreen, color.Bold))
+	console.SetColor("Stats", color.New(color.Bold))
+
+	stats := newBulkStats()
+	if cliCtx.Bool("stats") {
+		defer func() { printMsg(stats.Message()) }()
+	}
+	if isFake {
+		defer func() { printMsg(newRmReclaimMessage(stats, withVersions)) }()
+	}
+
+	if cliCtx.IsSet("files-from") {
+		return mainRmFilesFrom(ctx, cliCtx, removeOpts{
+			isFake:   isFake,
+			isBypass: isBypass,
+			stats:    stats,
+		})
+	}
 
 	var rerr error
 	var e error
@@ -737,7 +979,11 @@ func mainRm(cliCtx *cli.Context) error {
 				isBypass:          isBypass,
 				olderThan:         olderThan,
 				newerThan:         newerThan,
+				filter:            filter,
+				pathFilter:        pathFilter,
+				trashPrefix:       trashPrefix,
 				encKeyDB:          encKeyDB,
+				stats:             stats,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
@@ -748,7 +994,10 @@ func mainRm(cliCtx *cli.Context) error {
 				isBypass:     isBypass,
 				olderThan:    olderThan,
 				newerThan:    newerThan,
+				filter:       filter,
+				trashPrefix:  trashPrefix,
 				encKeyDB:     encKeyDB,
+				stats:        stats,
 			})
 		}
 		if rerr == nil {
@@ -775,7 +1024,11 @@ func mainRm(cliCtx *cli.Context) error {
 				isBypass:          isBypass,
 				olderThan:         olderThan,
 				newerThan:         newerThan,
+				filter:            filter,
+				pathFilter:        pathFilter,
+				trashPrefix:       trashPrefix,
 				encKeyDB:          encKeyDB,
+				stats:             stats,
 			})
 		} else {
 			e = removeSingle(url, versionID, removeOpts{
@@ -786,7 +1039,10 @@ func mainRm(cliCtx *cli.Context) error {
 				isBypass:     isBypass,
 				olderThan:    olderThan,
 				newerThan:    newerThan,
+				filter:       filter,
+				trashPrefix:  trashPrefix,
 				encKeyDB:     encKeyDB,
+				stats:        stats,
 			})
 		}
 		if rerr == nil {
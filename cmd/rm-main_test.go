@@ -0,0 +1,102 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListAndRemoveFilters(t *testing.T) {
+	files := map[string]int{
+		"a.tmp":      2048,
+		"small.tmp":  10,
+		"b.txt":      2048,
+		"dir/c.tmp":  4096,
+		"keep/d.tmp": 4096,
+	}
+	root := t.TempDir()
+	for file, size := range files {
+		fpath := filepath.Join(root, file)
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(strings.Repeat("x", size)), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	pathFilter, err := parsePathFilter("+*.tmp\n-keep/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizeRange, err := parseSizeRanges([]string{"+1KiB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := removeOpts{
+		isRecursive: true,
+		isForce:     true,
+		filter:      sizeRange,
+		pathFilter:  pathFilter,
+		stats:       newBulkStats(),
+	}
+	if e := listAndRemove(root+string(os.PathSeparator), opts); e != nil {
+		t.Fatal(e)
+	}
+
+	expected := map[string]bool{
+		"a.tmp":      false,
+		"small.tmp":  true,
+		"b.txt":      true,
+		"dir/c.tmp":  false,
+		"keep/d.tmp": true,
+	}
+	for file, exists := range expected {
+		if _, e := os.Stat(filepath.Join(root, file)); (e == nil) != exists {
+			t.Errorf("expected %s to exist %t", file, exists)
+		}
+	}
+}
+
+func TestRemoveDryRunReclaim(t *testing.T) {
+	root := t.TempDir()
+	for file, size := range map[string]int{"a": 1024, "b": 2048, "c": 1} {
+		if e := os.WriteFile(filepath.Join(root, file), []byte(strings.Repeat("x", size)), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	stats := newBulkStats()
+	opts := removeOpts{isRecursive: true, isForce: true, isFake: true, stats: stats}
+	if e := listAndRemove(root+string(os.PathSeparator), opts); e != nil {
+		t.Fatal(e)
+	}
+	msg := newRmReclaimMessage(stats, false)
+	if msg.Objects != 3 || msg.Reclaimed != 3073 {
+		t.Fatalf("expected 3 objects of 3073 bytes, got %d objects of %d bytes", msg.Objects, msg.Reclaimed)
+	}
+	if got := msg.String(); !strings.Contains(got, "Would remove 3 objects, reclaiming 3.0 KiB.") {
+		t.Fatalf("unexpected message %q", got)
+	}
+	if _, e := os.Stat(filepath.Join(root, "b")); e != nil {
+		t.Fatalf("expected a dry run to keep the objects: %v", e)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// checkpointRecord is a line of a session checkpoint file, an object is
+// recorded once when its copy starts and once when it completes.
+type checkpointRecord struct {
+	Source string `json:"source"`
+	Target string `json:"target,omitempty"`
+	Done   bool   `json:"done,omitempty"`
+}
+
+// sessionCheckpoint records the objects of a 'cp --resume' session
+// which were started and completed, so that an interrupted copy is
+// restarted without transferring the completed objects again.
+type sessionCheckpoint struct {
+	mutex sync.Mutex
+	file  *os.File
+	done  map[string]bool
+	// Targets of the objects started but not completed, their partial
+	// multipart uploads are aborted before they are copied again.
+	unfinished map[string]string
+}
+
+// getSessionCheckpointFile - get checkpoint file for a given session.
+func getSessionCheckpointFile(sid string) (string, *probe.Error) {
+	sessionDir, err := getSessionDir()
+	if err != nil {
+		return "", err.Trace()
+	}
+
+	return filepath.Join(sessionDir, sid+".checkpoint"), nil
+}
+
+// openSessionCheckpoint opens the checkpoint file of a session, and
+// loads the objects recorded by a previous run.
+func openSessionCheckpoint(sid string) (*sessionCheckpoint, *probe.Error) {
+	checkpointFile, err := getSessionCheckpointFile(sid)
+	if err != nil {
+		return nil, err.Trace(sid)
+	}
+	f, e := os.OpenFile(checkpointFile, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(checkpointFile)
+	}
+
+	c := &sessionCheckpoint{
+		file:       f,
+		done:       make(map[string]bool),
+		unfinished: make(map[string]string),
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record checkpointRecord
+		// The last line is partial if the previous run was killed
+		// while writing it.
+		if json.Unmarshal(scanner.Bytes(), &record) != nil {
+			continue
+		}
+		if record.Done {
+			c.done[record.Source] = true
+			delete(c.unfinished, record.Source)
+		} else {
+			c.unfinished[record.Source] = record.Target
+		}
+	}
+	if e = scanner.Err(); e != nil {
+		f.Close()
+		return nil, probe.NewError(e).Trace(checkpointFile)
+	}
+	return c, nil
+}
+
+func (c *sessionCheckpoint) write(record checkpointRecord) *probe.Error {
+	data, e := json.Marshal(record)
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if _, e = c.file.Write(append(data, '\n')); e != nil {
+		return probe.NewError(e).Trace(c.file.Name())
+	}
+	return nil
+}
+
+// isDone returns true if a source was copied by a previous run.
+func (c *sessionCheckpoint) isDone(source string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.done[source]
+}
+
+// start records that the copy of a source starts. A partial multipart
+// upload left by a previous run interrupted while copying the same
+// source is aborted first, as it cannot be continued.
+func (c *sessionCheckpoint) start(ctx context.Context, cpURLs URLs) *probe.Error {
+	source := copySourceID(cpURLs)
+	target := cpURLs.TargetContent.URL.String()
+
+	c.mutex.Lock()
+	previousTarget, unfinished := c.unfinished[source]
+	c.mutex.Unlock()
+
+	if unfinished {
+		if err := abortIncompleteUpload(ctx, cpURLs.TargetAlias, previousTarget); err != nil {
+			return err.Trace(previousTarget)
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.unfinished[source] = target
+	return c.write(checkpointRecord{Source: source, Target: target})
+}
+
+// finish records that a source was copied.
+func (c *sessionCheckpoint) finish(source string) *probe.Error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Objects skipped as copied by a previous run are recorded already.
+	if c.done[source] {
+		return nil
+	}
+	c.done[source] = true
+	delete(c.unfinished, source)
+	return c.write(checkpointRecord{Source: source, Done: true})
+}
+
+// Close closes the checkpoint file, which is kept for the next run.
+func (c *sessionCheckpoint) Close() *probe.Error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if e := c.file.Close(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// abortIncompleteUpload removes the parts uploaded to an S3 target by
+// an interrupted multipart upload.
+func abortIncompleteUpload(ctx context.Context, alias, urlStr string) *probe.Error {
+	clnt, err := newClientFromAlias(alias, urlStr)
+	if err != nil {
+		return err.Trace(alias, urlStr)
+	}
+	s3Client, ok := clnt.(*S3Client)
+	if !ok {
+		return nil
+	}
+	bucket, object := s3Client.url2BucketAndObject()
+	if e := s3Client.api.RemoveIncompleteUpload(ctx, bucket, object); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
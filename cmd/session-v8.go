@@ -69,6 +69,8 @@ type sessionV8 struct {
 	SessionID string
 	mutex     *sync.Mutex
 	DataFP    *sessionDataFP
+	// Checkpoint of the objects copied by 'cp --resume', nil otherwise.
+	Checkpoint *sessionCheckpoint
 }
 
 // sessionDataFP data file pointer.
@@ -319,6 +321,9 @@ func (s *sessionV8) Close() *probe.Error {
 	if err := s.DataFP.Close(); err != nil {
 		return probe.NewError(err)
 	}
+	if s.Checkpoint != nil {
+		s.Checkpoint.Close()
+	}
 
 	// Attempt to save the header if modified.
 	return s.save()
@@ -356,6 +361,14 @@ func (s *sessionV8) Delete() *probe.Error {
 	// Remove session backup file if any, ignore any error.
 	os.Remove(sessionFile + ".old")
 
+	// Remove the checkpoint file of 'cp --resume' if any, ignore any error.
+	if s.Checkpoint != nil {
+		s.Checkpoint.Close()
+	}
+	if checkpointFile, err := getSessionCheckpointFile(s.SessionID); err == nil {
+		os.Remove(checkpointFile)
+	}
+
 	return nil
 }
 
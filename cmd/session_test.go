@@ -18,6 +18,7 @@
 package cmd
 
 import (
+	"context"
 	"math/rand"
 	"os"
 	"regexp"
@@ -72,3 +73,35 @@ func (s *TestSuite) TestSession(c *C) {
 	_, e = os.Stat(session.DataFP.Name())
 	c.Assert(e, NotNil)
 }
+
+func (s *TestSuite) TestSessionCheckpoint(c *C) {
+	err := createSessionDir()
+	c.Assert(err, IsNil)
+
+	root := c.MkDir()
+	cpURLs := URLs{
+		SourceContent: &ClientContent{URL: *newClientURL(root + "/source/a")},
+		TargetContent: &ClientContent{URL: *newClientURL(root + "/target/a")},
+	}
+	source := cpURLs.SourceContent.URL.String()
+
+	session := newSessionV8(getHash("cp", []string{"--resume", root}))
+	session.Checkpoint, err = openSessionCheckpoint(session.SessionID)
+	c.Assert(err, IsNil)
+	c.Assert(session.Checkpoint.isDone(source), Equals, false)
+	c.Assert(session.Checkpoint.start(context.Background(), cpURLs), IsNil)
+	c.Assert(session.Checkpoint.finish(source), IsNil)
+	c.Assert(session.Close(), IsNil)
+
+	checkpoint, err := openSessionCheckpoint(session.SessionID)
+	c.Assert(err, IsNil)
+	c.Assert(checkpoint.isDone(source), Equals, true)
+	c.Assert(checkpoint.isDone(root+"/source/b"), Equals, false)
+	c.Assert(checkpoint.Close(), IsNil)
+
+	c.Assert(session.Delete(), IsNil)
+	checkpointFile, err := getSessionCheckpointFile(session.SessionID)
+	c.Assert(err, IsNil)
+	_, e := os.Stat(checkpointFile)
+	c.Assert(os.IsNotExist(e), Equals, true)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// settleDurationFlag is shared by the commands copying objects in bulk.
+var settleDurationFlag = cli.StringFlag{
+	Name:  "settle-duration",
+	Usage: "skip objects modified within this duration, as they may still be written, e.g. '30s'",
+}
+
+// parseSettleDuration parses the --settle-duration flag, zero copies
+// objects regardless of their age.
+func parseSettleDuration(cliCtx *cli.Context) (time.Duration, *probe.Error) {
+	value := cliCtx.String("settle-duration")
+	if value == "" {
+		return 0, nil
+	}
+	d, e := time.ParseDuration(value)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(value)
+	}
+	if d <= 0 {
+		return 0, errInvalidArgument().Trace(value)
+	}
+	return d, nil
+}
+
+// settleRemaining returns how long an object modified at modTime is
+// still settling, zero once it is settled.
+func settleRemaining(modTime time.Time, settleDuration time.Duration) time.Duration {
+	if settleDuration <= 0 || modTime.IsZero() {
+		return 0
+	}
+	if remaining := settleDuration - time.Since(modTime); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// isErrSourceSettling returns true if an object was skipped by
+// --settle-duration.
+func isErrSourceSettling(err *probe.Error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.ToGoError().(ObjectSettling)
+	return ok
+}
+
+// settlingMessage container for objects skipped by --settle-duration,
+// they are copied by the next pass.
+type settlingMessage struct {
+	Status       string    `json:"status"`
+	Source       string    `json:"source"`
+	Target       string    `json:"target,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// String colorized settling message
+func (s settlingMessage) String() string {
+	return console.Colorize("Settling", fmt.Sprintf("`%s` modified %s ago, skipping until it settles.",
+		s.Source, time.Since(s.LastModified).Round(time.Second)))
+}
+
+// JSON jsonified settling message
+func (s settlingMessage) JSON() string {
+	s.Status = "settling"
+	settlingMessageBytes, e := json.MarshalIndent(s, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(settlingMessageBytes)
+}
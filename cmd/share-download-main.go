@@ -71,7 +71,7 @@ EXAMPLES:
 }
 
 // checkShareDownloadSyntax - validate command-line args.
-func checkShareDownloadSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) {
+func checkShareDownloadSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) {
 	args := cliCtx.Args()
 	if !args.Present() {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code.
@@ -20,7 +20,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
 	"time"
 
@@ -69,12 +68,6 @@ EXAMPLES:
 `,
 }
 
-var shellQuoteRegex = regexp.MustCompile("([&;#$` \t\n<>()|'\"])")
-
-func shellQuote(s string) string {
-	return shellQuoteRegex.ReplaceAllString(s, "\\$1")
-}
-
 // checkShareUploadSyntax - validate command-line args.
 func checkShareUploadSyntax(ctx *cli.Context) {
 	args := ctx.Args()
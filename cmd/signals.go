@@ -20,8 +20,76 @@ package cmd
 import (
 	"os"
 	"os/signal"
+	"sync"
+
+	"github.com/minio/pkg/console"
 )
 
+var (
+	exitHooksMu sync.Mutex
+	exitHooks   []func()
+
+	// gracefulStopCh is closed by the first interrupt, once a bulk
+	// operation enabled graceful stops.
+	gracefulStopMu     sync.Mutex
+	gracefulStopCh     chan struct{}
+	gracefulStopClosed bool
+)
+
+// enableGracefulStop makes the first interrupt stop a bulk operation
+// gracefully instead of terminating mc: the returned channel is closed,
+// the operation stops scheduling new transfers and finishes the ones
+// in progress. A second interrupt terminates mc.
+func enableGracefulStop() <-chan struct{} {
+	gracefulStopMu.Lock()
+	defer gracefulStopMu.Unlock()
+	if gracefulStopCh == nil {
+		gracefulStopCh = make(chan struct{})
+	}
+	return gracefulStopCh
+}
+
+// isGracefulStopping returns true once the first interrupt asked a
+// bulk operation to stop.
+func isGracefulStopping() bool {
+	gracefulStopMu.Lock()
+	defer gracefulStopMu.Unlock()
+	return gracefulStopClosed
+}
+
+// startGracefulStop closes the graceful stop channel, it returns false
+// if graceful stops are not enabled or already started.
+func startGracefulStop() bool {
+	gracefulStopMu.Lock()
+	defer gracefulStopMu.Unlock()
+	if gracefulStopCh == nil || gracefulStopClosed {
+		return false
+	}
+	gracefulStopClosed = true
+	close(gracefulStopCh)
+	return true
+}
+
+// registerExitHook registers a function to run when mc exits, after
+// the global context is canceled if a trapped signal stops mc.
+func registerExitHook(fn func()) {
+	exitHooksMu.Lock()
+	defer exitHooksMu.Unlock()
+	exitHooks = append(exitHooks, fn)
+}
+
+// runExitHooks runs the registered exit hooks, only once however mc
+// exits.
+func runExitHooks() {
+	exitHooksMu.Lock()
+	hooks := exitHooks
+	exitHooks = nil
+	exitHooksMu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
 // trapSignals traps the registered signals and cancel the global context.
 func trapSignals(sig ...os.Signal) {
 	// channel to receive signals.
@@ -32,8 +100,16 @@ func trapSignals(sig ...os.Signal) {
 	// receive notifications of the specified signals.
 	signal.Notify(sigCh, sig...)
 
-	// Wait for the signal.
+	// Wait for the signal, the first interrupt only stops a bulk
+	// operation gracefully.
 	s := <-sigCh
+	for s == os.Interrupt && startGracefulStop() {
+		if !globalQuiet && !globalJSON {
+			console.Eraseline()
+			console.Infoln("Finishing transfers in progress, press Ctrl+C again to abort.")
+		}
+		s = <-sigCh
+	}
 
 	// Once signal has been received stop signal Notify handler.
 	signal.Stop(sigCh)
@@ -45,6 +121,8 @@ func trapSignals(sig ...os.Signal) {
 	// Cancel the global context
 	globalCancel()
 
+	runExitHooks()
+
 	var exitCode int
 	switch s.String() {
 	case "interrupt":
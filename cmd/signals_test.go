@@ -0,0 +1,76 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExitHooks(t *testing.T) {
+	var calls []int
+	for i := 1; i <= 3; i++ {
+		i := i
+		registerExitHook(func() { calls = append(calls, i) })
+	}
+
+	// Hooks run in order, only once.
+	runExitHooks()
+	runExitHooks()
+	if expected := []int{1, 2, 3}; !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestGracefulStop(t *testing.T) {
+	reset := func() {
+		gracefulStopMu.Lock()
+		gracefulStopCh, gracefulStopClosed = nil, false
+		gracefulStopMu.Unlock()
+	}
+	reset()
+	defer reset()
+
+	// Interrupts exit right away until a bulk operation enables it.
+	if startGracefulStop() {
+		t.Fatal("expected no graceful stop before it is enabled")
+	}
+	stopCh := enableGracefulStop()
+	if enableGracefulStop() != stopCh {
+		t.Fatal("expected the graceful stop channel to be shared")
+	}
+	if isGracefulStopping() {
+		t.Fatal("expected no graceful stop before an interrupt")
+	}
+
+	// Only the first interrupt stops gracefully.
+	if !startGracefulStop() {
+		t.Fatal("expected the first interrupt to stop gracefully")
+	}
+	select {
+	case <-stopCh:
+	default:
+		t.Fatal("expected the graceful stop channel to be closed")
+	}
+	if !isGracefulStopping() {
+		t.Fatal("expected a graceful stop after the first interrupt")
+	}
+	if startGracefulStop() {
+		t.Fatal("expected the second interrupt to exit right away")
+	}
+}
@@ -74,6 +74,7 @@ func (p PerfTestType) Name() string {
 type PerfTestResult struct {
 	Type                  PerfTestType                  `json:"type"`
 	ObjectResult          *madmin.SpeedTestResult       `json:"object,omitempty"`
+	ObjectProfile         *ObjProfileResults            `json:"objectProfile,omitempty"`
 	NetResult             *madmin.NetperfResult         `json:"network,omitempty"`
 	SiteReplicationResult *madmin.SiteNetPerfResult     `json:"siteReplication,omitempty"`
 	ClientResult          *madmin.ClientPerfResult      `json:"client,omitempty"`
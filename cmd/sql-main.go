@@ -80,7 +80,7 @@ var sqlCmd = cli.Command{
 	Action:       mainSQL,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(sqlFlags, ioFlags...), globalFlags...),
+	Flags:        append(append(sqlFlags, ioFlags...), excludeFlags(globalFlags, "query")...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -301,7 +301,7 @@ func getOutputSerializationOpts(ctx *cli.Context, csvHdrs []string) (opts map[st
 }
 
 // getCSVHeader fetches the first line of csv query object
-func getCSVHeader(sourceURL string, encKeyDB map[string][]prefixSSEPair) ([]string, *probe.Error) {
+func getCSVHeader(sourceURL string, encKeyDB encryptionConfig) ([]string, *probe.Error) {
 	var r io.ReadCloser
 	switch sourceURL {
 	case "-":
@@ -343,7 +343,7 @@ func isSelectAll(query string) bool {
 
 // if csv-output-header is set to a comma delimited string use it, othjerwise attempt to get the header from
 // query object
-func getCSVOutputHeaders(ctx *cli.Context, url string, encKeyDB map[string][]prefixSSEPair, query string) (hdrs []string) {
+func getCSVOutputHeaders(ctx *cli.Context, url string, encKeyDB encryptionConfig, query string) (hdrs []string) {
 	if !ctx.IsSet("csv-output-header") {
 		return
 	}
@@ -381,7 +381,7 @@ func isCSVOrJSON(inOpts map[string]map[string]string) bool {
 	return false
 }
 
-func sqlSelect(targetURL, expression string, encKeyDB map[string][]prefixSSEPair, selOpts SelectObjectOpts, csvHdrs []string, writeHdr bool) *probe.Error {
+func sqlSelect(targetURL, expression string, encKeyDB encryptionConfig, selOpts SelectObjectOpts, csvHdrs []string, writeHdr bool) *probe.Error {
 	ctx, cancelSelect := context.WithCancel(globalContext)
 	defer cancelSelect()
 
@@ -418,7 +418,7 @@ func validateOpts(selOpts SelectObjectOpts, url string) {
 }
 
 // validate args and optionally fetch the csv header of query object
-func getAndValidateArgs(ctx *cli.Context, encKeyDB map[string][]prefixSSEPair, url string) (query string, csvHdrs []string, selOpts SelectObjectOpts) {
+func getAndValidateArgs(ctx *cli.Context, encKeyDB encryptionConfig, url string) (query string, csvHdrs []string, selOpts SelectObjectOpts) {
 	query = ctx.String("query")
 	csvHdrs = getCSVOutputHeaders(ctx, url, encKeyDB, query)
 	selOpts = getSQLOpts(ctx, csvHdrs)
@@ -431,6 +431,9 @@ func checkSQLSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) == 0 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code.
 	}
+	for _, url := range ctx.Args() {
+		checkAliasFeature(url, featureSelect, "mc sql")
+	}
 }
 
 // mainSQL is the main entry point for sql command.
@@ -0,0 +1,224 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/env"
+	"github.com/minio/pkg/quick"
+)
+
+// Number of lookups kept by the stat cache, least recently used
+// lookups are evicted first.
+const statCacheSize = 1024
+
+// Environment variable enabling the on disk stat cache, its value is
+// how long persisted lookups stay valid, e.g. "30s".
+const envStatCacheTTL = "MC_STAT_CACHE_TTL"
+
+// statCacheEntry is a cached lookup result.
+type statCacheEntry struct {
+	Value  bool      `json:"value"`
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+// JSON file to persist recent bucket existence and directory lookups
+// across runs, so that scripts calling mc repeatedly save round trips.
+type statCacheV1 struct {
+	Version string `json:"version"`
+	mutex   *sync.Mutex
+
+	// ttl of new entries, zero keeps them in memory for the lifetime
+	// of the process only.
+	ttl time.Duration
+
+	// key is the kind of lookup and its URL.
+	Entries map[string]statCacheEntry `json:"entries"`
+
+	// keys in least recently used order, most recent first.
+	lru   *list.List
+	elems map[string]*list.Element
+}
+
+// Instantiate a new stat cache structure.
+func newStatCacheV1(ttl time.Duration) *statCacheV1 {
+	return &statCacheV1{
+		Version: "1",
+		mutex:   &sync.Mutex{},
+		ttl:     ttl,
+		Entries: make(map[string]statCacheEntry),
+		lru:     list.New(),
+		elems:   make(map[string]*list.Element),
+	}
+}
+
+// Get returns a cached lookup result and whether it was found.
+func (s *statCacheV1) Get(key string) (value, ok bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entry, ok := s.Entries[key]
+	if !ok {
+		return false, false
+	}
+	if !entry.Expiry.IsZero() && time.Now().After(entry.Expiry) {
+		s.remove(key)
+		return false, false
+	}
+	s.lru.MoveToFront(s.elems[key])
+	return entry.Value, true
+}
+
+// Set caches a lookup result, persisting it if the on disk cache is enabled.
+func (s *statCacheV1) Set(key string, value bool) *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.add(key, value, s.ttl)
+	if s.ttl == 0 {
+		return nil
+	}
+	return s.save(mustGetStatCacheFile())
+}
+
+// Delete a cached lookup result if it exists.
+func (s *statCacheV1) Delete(key string) *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.Entries[key]; !ok {
+		return nil
+	}
+	s.remove(key)
+	if s.ttl == 0 {
+		return nil
+	}
+	return s.save(mustGetStatCacheFile())
+}
+
+func (s *statCacheV1) add(key string, value bool, ttl time.Duration) {
+	entry := statCacheEntry{Value: value}
+	if ttl > 0 {
+		entry.Expiry = time.Now().Add(ttl)
+	}
+	if elem, ok := s.elems[key]; ok {
+		s.lru.MoveToFront(elem)
+	} else {
+		s.elems[key] = s.lru.PushFront(key)
+	}
+	s.Entries[key] = entry
+	for s.lru.Len() > statCacheSize {
+		s.remove(s.lru.Back().Value.(string))
+	}
+}
+
+func (s *statCacheV1) remove(key string) {
+	if elem, ok := s.elems[key]; ok {
+		s.lru.Remove(elem)
+		delete(s.elems, key)
+	}
+	delete(s.Entries, key)
+}
+
+// Load stat cache entries from disk, expired entries are dropped.
+func (s *statCacheV1) Load(filename string) *probe.Error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Check if the cache file exist.
+	if _, e := os.Stat(filename); e != nil {
+		return probe.NewError(e)
+	}
+
+	// Initialize and load using quick package.
+	qs, e := quick.NewConfig(newStatCacheV1(0), nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	e = qs.Load(filename)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+
+	now := time.Now()
+	for k, v := range qs.Data().(*statCacheV1).Entries {
+		if v.Expiry.IsZero() || now.After(v.Expiry) {
+			continue
+		}
+		s.add(k, v.Value, v.Expiry.Sub(now))
+	}
+	return nil
+}
+
+// Persist stat cache to disk.
+func (s statCacheV1) save(filename string) *probe.Error {
+	// Initialize a new quick file.
+	qs, e := quick.NewConfig(s, nil)
+	if e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	if e := qs.Save(filename); e != nil {
+		return probe.NewError(e).Trace(filename)
+	}
+	return nil
+}
+
+// Get stat cache file name or die. (NOTE: This `Die` approach is only OK for mc like tools.).
+func mustGetStatCacheFile() string {
+	return filepath.Join(mustGetMcConfigDir(), globalMCStatCacheFile)
+}
+
+var (
+	statCacheOnce sync.Once
+	statCache     *statCacheV1
+)
+
+// getStatCache returns the stat cache, loaded once from disk if
+// MC_STAT_CACHE_TTL is set.
+func getStatCache() *statCacheV1 {
+	statCacheOnce.Do(func() {
+		ttl, e := time.ParseDuration(env.Get(envStatCacheTTL, "0s"))
+		errorIf(probe.NewError(e).Trace(env.Get(envStatCacheTTL, "")),
+			"Invalid value for "+envStatCacheTTL+", the stat cache is not persisted.")
+		if e != nil || ttl < 0 {
+			ttl = 0
+		}
+		statCache = newStatCacheV1(ttl)
+		if ttl > 0 {
+			// A missing or unreadable cache is not fatal, lookups are done again.
+			statCache.Load(mustGetStatCacheFile())
+		}
+	})
+	return statCache
+}
+
+// statCacheBucketKey is the stat cache key of a bucket existence lookup.
+func statCacheBucketKey(host, bucket string) string {
+	return "bucket:" + host + "/" + bucket
+}
+
+// statCacheDirKey is the stat cache key of a directory lookup.
+func statCacheDirKey(urlStr string) string {
+	return "dir:" + urlStr
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Objects stat'ed at the same time with --files-from by default.
+const defaultStatWorkers = 16
+
+var statFilesFromFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "files-from",
+		Usage: "stat only the keys listed in a newline or NUL delimited file ('-' for stdin), relative to the target, without listing it",
+	},
+	cli.IntFlag{
+		Name:  "stat-workers",
+		Usage: "number of objects stat'ed at the same time with --files-from",
+		Value: defaultStatWorkers,
+	},
+}
+
+// Checksum algorithms an object can be stored with.
+var objectChecksumAlgorithms = []string{"CRC32", "CRC32C", "SHA1", "SHA256"}
+
+// checkStatFilesFromSyntax - returns why --files-from and --stat-workers
+// cannot be used with the other flags passed, empty if they can.
+func checkStatFilesFromSyntax(cliCtx *cli.Context) string {
+	if !cliCtx.IsSet("files-from") {
+		if cliCtx.IsSet("stat-workers") {
+			return "--stat-workers requires --files-from."
+		}
+		return ""
+	}
+	switch {
+	case len(cliCtx.Args()) != 1:
+		return "--files-from requires exactly one target, the folder the keys are relative to."
+	case cliCtx.Int("stat-workers") < 1:
+		return "--stat-workers must be at least 1."
+	}
+	for _, flag := range []string{"recursive", "versions", "version-id", "rewind"} {
+		if cliCtx.IsSet(flag) {
+			return "--files-from cannot be used with --" + flag + ", the listed keys are stat'ed without looking them up."
+		}
+	}
+	return ""
+}
+
+// setObjectDetails moves the checksums, retention and legal hold of an
+// object out of the headers of its HEAD response into their own fields
+// of its stat message. Tags are not in the headers, they are fetched
+// when the object has some.
+func setObjectDetails(ctx context.Context, clnt Client, versionID string, msg *statMessage) *probe.Error {
+	for _, algorithm := range objectChecksumAlgorithms {
+		header := http.CanonicalHeaderKey("X-Amz-Checksum-" + algorithm)
+		if value := msg.Metadata[header]; value != "" {
+			if msg.Checksums == nil {
+				msg.Checksums = make(map[string]string)
+			}
+			msg.Checksums[algorithm] = value
+			delete(msg.Metadata, header)
+		}
+	}
+
+	if mode := msg.Metadata["X-Amz-Object-Lock-Mode"]; mode != "" {
+		until, e := time.Parse(time.RFC3339, msg.Metadata["X-Amz-Object-Lock-Retain-Until-Date"])
+		if e == nil {
+			msg.RetentionMode = mode
+			msg.RetainUntilDate = &until
+			delete(msg.Metadata, "X-Amz-Object-Lock-Mode")
+			delete(msg.Metadata, "X-Amz-Object-Lock-Retain-Until-Date")
+		}
+	}
+	if legalHold := msg.Metadata["X-Amz-Object-Lock-Legal-Hold"]; legalHold != "" {
+		msg.LegalHold = legalHold
+		delete(msg.Metadata, "X-Amz-Object-Lock-Legal-Hold")
+	}
+
+	if count, _ := strconv.Atoi(msg.Metadata["X-Amz-Tagging-Count"]); count > 0 {
+		tags, err := clnt.GetTags(ctx, versionID)
+		if err != nil {
+			return err
+		}
+		msg.Tags = tags
+		delete(msg.Metadata, "X-Amz-Tagging-Count")
+	}
+	return nil
+}
+
+// statKey returns the stat message of a key below the folder urlStr of
+// an alias, with its checksums, tags, retention and legal hold.
+func statKey(ctx context.Context, alias, urlStr, key string, encKeyDB encryptionConfig) (statMessage, *probe.Error) {
+	clnt, err := newClientFromAlias(alias, urlJoinPath(urlStr, key))
+	if err != nil {
+		return statMessage{}, err
+	}
+	sse := getSSE(path.Join(alias, clnt.GetURL().Path), encKeyDB[alias])
+	content, err := clnt.Stat(ctx, StatOptions{sse: sse, checksum: true})
+	if err != nil {
+		return statMessage{}, err
+	}
+	msg := parseStat(content)
+	if err := setObjectDetails(ctx, clnt, content.VersionID, &msg); err != nil {
+		return statMessage{}, err
+	}
+	return msg, nil
+}
+
+// statFilesFrom prints the stat of the keys of a --files-from manifest
+// below targetURL, workers keys are stat'ed at the same time. The keys
+// are printed in the order their stat completes.
+func statFilesFrom(ctx context.Context, targetURL string, r io.Reader, workers int, encKeyDB encryptionConfig) error {
+	targetAlias, urlStr, _ := mustExpandAlias(targetURL)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keysCh := make(chan string)
+	errCh := make(chan *probe.Error, 1)
+	go func() {
+		defer close(keysCh)
+		errCh <- readManifestKeys(ctx, r, keysCh)
+	}()
+
+	var mu sync.Mutex
+	var failures int
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keysCh {
+				keyURL := urlJoinPath(targetURL, key)
+				msg, err := statKey(ctx, targetAlias, urlStr, key, encKeyDB)
+				if err != nil {
+					errorIf(err.Trace(keyURL), "Unable to stat `"+keyURL+"`.")
+					mu.Lock()
+					failures++
+					mu.Unlock()
+					continue
+				}
+				msg.Key = keyURL
+				printMsg(msg)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := <-errCh; err != nil {
+		errorIf(err.Trace(targetURL), "Unable to read the keys to stat.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	if failures > 0 {
+		return exitStatus(globalErrorExitStatus)
+	}
+	return nil
+}
+
+// mainStatFilesFrom prints the stat of the keys of the --files-from
+// manifest.
+func mainStatFilesFrom(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) error {
+	filesFrom := cliCtx.String("files-from")
+	var r io.Reader = os.Stdin
+	if filesFrom != "-" {
+		f, e := os.Open(filesFrom)
+		fatalIf(probe.NewError(e).Trace(filesFrom), "Unable to open the keys to stat.")
+		defer f.Close()
+		r = f
+	}
+	return statFilesFrom(ctx, cliCtx.Args().First(), r, cliCtx.Int("stat-workers"), encKeyDB)
+}
@@ -56,7 +56,7 @@ var statCmd = cli.Command{
 	Action:       mainStat,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(append(statFlags, ioFlags...), globalFlags...),
+	Flags:        joinFlags(statFlags, statFilesFromFlags, ioFlags, globalFlags),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -91,11 +91,17 @@ EXAMPLES:
 
   7. Stat all objects versions recursively created before 1st January 2020.
      {{.Prompt}} {{.HelpName}} --versions --rewind 2020.01.01T00:00 s3/personal-docs/
+
+  8. Stat the objects listed in keys.txt, 32 at a time, printing their checksums, tags and retention as JSON.
+     {{.Prompt}} {{.HelpName}} --json --files-from keys.txt --stat-workers 32 s3/personal-docs/
+
+  9. Print the checksums of the objects listed in keys.txt as JSON, keyed by their names.
+     {{.Prompt}} {{.HelpName}} --files-from keys.txt --format-template '{{"{{.Key}}: {{json .Checksums}}"}}' s3/personal-docs/
 `,
 }
 
 // parseAndCheckStatSyntax - parse and validate all the passed arguments
-func parseAndCheckStatSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB map[string][]prefixSSEPair) ([]string, bool, string, time.Time, bool) {
+func parseAndCheckStatSyntax(ctx context.Context, cliCtx *cli.Context, encKeyDB encryptionConfig) ([]string, bool, string, time.Time, bool) {
 	if !cliCtx.Args().Present() {
 		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
 	}
@@ -157,6 +163,13 @@ func mainStat(cliCtx *cli.Context) error {
 	encKeyDB, err := getEncKeys(cliCtx)
 	fatalIf(err, "Unable to parse encryption keys.")
 
+	if msg := checkStatFilesFromSyntax(cliCtx); msg != "" {
+		fatalIf(errInvalidArgument().Trace(cliCtx.Args()...), msg)
+	}
+	if cliCtx.IsSet("files-from") {
+		return mainStatFilesFrom(ctx, cliCtx, encKeyDB)
+	}
+
 	// check 'stat' cli arguments.
 	args, isRecursive, versionID, rewind, withVersions := parseAndCheckStatSyntax(ctx, cliCtx, encKeyDB)
 	// mimic operating system tool behavior.
@@ -54,6 +54,11 @@ type statMessage struct {
 	VersionID         string             `json:"versionID,omitempty"`
 	DeleteMarker      bool               `json:"deleteMarker,omitempty"`
 	Restore           *minio.RestoreInfo `json:"restore,omitempty"`
+	Checksums         map[string]string  `json:"checksums,omitempty"`
+	Tags              map[string]string  `json:"tags,omitempty"`
+	RetentionMode     string             `json:"retentionMode,omitempty"`
+	RetainUntilDate   *time.Time         `json:"retainUntilDate,omitempty"`
+	LegalHold         string             `json:"legalHold,omitempty"`
 }
 
 func (stat statMessage) String() (msg string) {
@@ -128,6 +133,22 @@ func (stat statMessage) String() (msg string) {
 		}
 	}
 
+	for _, algorithm := range sortedKeys(stat.Checksums) {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s (%s)", "Checksum", stat.Checksums[algorithm], algorithm) + "\n")
+	}
+	if len(stat.Tags) > 0 {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s:", "Tags") + "\n")
+		for _, k := range sortedKeys(stat.Tags) {
+			msgBuilder.WriteString(fmt.Sprintf("  %s: %s", k, stat.Tags[k]) + "\n")
+		}
+	}
+	if stat.RetentionMode != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s until %s", "Retention", stat.RetentionMode, stat.RetainUntilDate.Format(printDate)) + "\n")
+	}
+	if stat.LegalHold != "" {
+		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s", "LegalHold", stat.LegalHold) + "\n")
+	}
+
 	if stat.ReplicationStatus != "" {
 		msgBuilder.WriteString(fmt.Sprintf("%-10s: %s ", "Replication Status", stat.ReplicationStatus))
 	}
@@ -137,6 +158,16 @@ func (stat statMessage) String() (msg string) {
 	return msgBuilder.String()
 }
 
+// sortedKeys returns the keys of a map in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // JSON jsonified content message.
 func (stat statMessage) JSON() string {
 	stat.Status = "success"
@@ -183,7 +214,7 @@ func getStandardizedURL(targetURL string) string {
 // statURL - uses combination of GET listing and HEAD to fetch information of one or more objects
 // HEAD can fail with 400 with an SSE-C encrypted object but we still return information gathered
 // from GET listing.
-func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive bool, encKeyDB map[string][]prefixSSEPair) *probe.Error {
+func statURL(ctx context.Context, targetURL, versionID string, timeRef time.Time, includeOlderVersions, isIncomplete, isRecursive bool, encKeyDB encryptionConfig) *probe.Error {
 	clnt, err := newClient(targetURL)
 	if err != nil {
 		return err
@@ -18,7 +18,9 @@
 package cmd
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -68,3 +70,80 @@ func TestParseStat(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckStatFilesFromSyntax(t *testing.T) {
+	flags := joinFlags(statFlags, statFilesFromFlags)
+	testCases := []struct {
+		args  []string
+		valid bool
+	}{
+		{[]string{"s3/bucket/object"}, true},
+		{[]string{"--files-from", "keys.txt", "s3/bucket/"}, true},
+		{[]string{"--files-from", "-", "--stat-workers", "64", "s3/bucket/"}, true},
+		{[]string{"--files-from", "keys.txt"}, false},
+		{[]string{"--files-from", "keys.txt", "s3/a/", "s3/b/"}, false},
+		{[]string{"--files-from", "keys.txt", "--stat-workers", "0", "s3/bucket/"}, false},
+		{[]string{"--files-from", "keys.txt", "--recursive", "s3/bucket/"}, false},
+		{[]string{"--files-from", "keys.txt", "--version-id", "v1", "s3/bucket/"}, false},
+		{[]string{"--stat-workers", "8", "s3/bucket/"}, false},
+	}
+	for i, testCase := range testCases {
+		msg := checkStatFilesFromSyntax(newTestCLIContext(t, flags, testCase.args...))
+		if valid := msg == ""; valid != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %t (%s)", i+1, testCase.valid, valid, msg)
+		}
+	}
+}
+
+func TestSetObjectDetails(t *testing.T) {
+	until := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	msg := statMessage{Metadata: map[string]string{
+		"Content-Type":                        "text/plain",
+		"X-Amz-Checksum-Crc32c":               "yZRlqg==",
+		"X-Amz-Checksum-Sha256":               "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=",
+		"X-Amz-Object-Lock-Mode":              "GOVERNANCE",
+		"X-Amz-Object-Lock-Retain-Until-Date": until.Format(time.RFC3339),
+		"X-Amz-Object-Lock-Legal-Hold":        "ON",
+		"X-Amz-Tagging-Count":                 "0",
+	}}
+	// Objects without tags are not asked for them.
+	if err := setObjectDetails(context.Background(), nil, "", &msg); err != nil {
+		t.Fatal(err)
+	}
+	expectedChecksums := map[string]string{"CRC32C": "yZRlqg==", "SHA256": "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek="}
+	if !reflect.DeepEqual(msg.Checksums, expectedChecksums) {
+		t.Errorf("Expecting checksums %v, got %v", expectedChecksums, msg.Checksums)
+	}
+	if msg.RetentionMode != "GOVERNANCE" || msg.RetainUntilDate == nil || !msg.RetainUntilDate.Equal(until) {
+		t.Errorf("Expecting GOVERNANCE retention until %s, got %s until %v", until, msg.RetentionMode, msg.RetainUntilDate)
+	}
+	if msg.LegalHold != "ON" {
+		t.Errorf("Expecting legal hold ON, got %s", msg.LegalHold)
+	}
+	expectedMetadata := map[string]string{"Content-Type": "text/plain", "X-Amz-Tagging-Count": "0"}
+	if !reflect.DeepEqual(msg.Metadata, expectedMetadata) {
+		t.Errorf("Expecting metadata %v, got %v", expectedMetadata, msg.Metadata)
+	}
+}
+
+func TestStatFilesFrom(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"a", "dir/b"} {
+		fpath := filepath.Join(root, file)
+		if e := os.MkdirAll(filepath.Dir(fpath), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(fpath, []byte(file), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	target := root + string(os.PathSeparator)
+
+	if e := statFilesFrom(context.Background(), target, strings.NewReader("./a\n\ndir/b\n"), 2, nil); e != nil {
+		t.Errorf("Expecting the listed keys to be stat'ed, got %v", e)
+	}
+	// Missing keys fail the command, the other keys are still stat'ed.
+	if e := statFilesFrom(context.Background(), target, strings.NewReader("a\nmissing\n"), 2, nil); e == nil {
+		t.Error("Expecting an error for a missing key")
+	}
+}
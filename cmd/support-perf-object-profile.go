@@ -0,0 +1,293 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/minio/madmin-go/v3"
+	"github.com/minio/pkg/console"
+)
+
+// perfObjectSize is one entry of the --size distribution, weight is the
+// share of the test duration spent on objects of this size.
+type perfObjectSize struct {
+	size   uint64
+	weight int
+}
+
+// parsePerfObjectSizes parses a comma separated list of object sizes,
+// each optionally followed by ':' and a weight, e.g. '4KiB:20,1MiB:80'.
+func parsePerfObjectSizes(s string) ([]perfObjectSize, error) {
+	var sizes []perfObjectSize
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		sizeStr, weightStr, hasWeight := strings.Cut(entry, ":")
+		size, e := humanize.ParseBytes(sizeStr)
+		if e != nil {
+			return nil, e
+		}
+		if size == 0 {
+			return nil, errors.New("size is expected to be more than 0 bytes")
+		}
+		weight := 1
+		if hasWeight {
+			weight, e = strconv.Atoi(weightStr)
+			if e != nil {
+				return nil, fmt.Errorf("invalid weight `%s` for size `%s`", weightStr, sizeStr)
+			}
+			if weight <= 0 {
+				return nil, fmt.Errorf("weight for size `%s` must be positive", sizeStr)
+			}
+		}
+		sizes = append(sizes, perfObjectSize{size: size, weight: weight})
+	}
+	if len(sizes) == 0 {
+		return nil, errors.New("at least one object size is required")
+	}
+	return sizes, nil
+}
+
+// parsePerfMix parses a 'read:write' ratio such as '70:30'.
+func parsePerfMix(s string) (read, write int, e error) {
+	readStr, writeStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("mix `%s` is not of the form read:write", s)
+	}
+	if read, e = strconv.Atoi(readStr); e != nil || read < 0 {
+		return 0, 0, fmt.Errorf("invalid read share `%s`", readStr)
+	}
+	if write, e = strconv.Atoi(writeStr); e != nil || write < 0 {
+		return 0, 0, fmt.Errorf("invalid write share `%s`", writeStr)
+	}
+	if read+write == 0 {
+		return 0, 0, errors.New("mix needs a non zero read or write share")
+	}
+	return read, write, nil
+}
+
+// ObjProfileStats - aggregated stats of one operation over all runs of
+// an object perf profile. Latency percentiles of the individual runs
+// are weighted by the number of objects of each run.
+type ObjProfileStats struct {
+	Throughput    uint64         `json:"throughput"`
+	ObjectsPerSec uint64         `json:"objectsPerSec"`
+	Response      madmin.Timings `json:"responseTime"`
+}
+
+// ObjProfileResults - results of an object perf test run with a size
+// distribution, a read/write mix or for a sustained duration.
+type ObjProfileResults struct {
+	Duration   time.Duration    `json:"duration"`
+	ReadShare  int              `json:"readShare,omitempty"`
+	WriteShare int              `json:"writeShare,omitempty"`
+	Runs       []ObjTestResults `json:"runs"`
+	PUT        ObjProfileStats  `json:"PUT"`
+	GET        ObjProfileStats  `json:"GET"`
+	// Mixed is the throughput expected for a workload doing reads and
+	// writes in the given ratio, derived from the PUT and GET results.
+	Mixed *ObjProfileStats `json:"mixed,omitempty"`
+}
+
+// perfProfileOpts - options of an object perf profile run.
+type perfProfileOpts struct {
+	sizes       []perfObjectSize
+	duration    time.Duration
+	sustain     time.Duration
+	readShare   int
+	writeShare  int
+	concurrency int
+	autotune    bool
+	bucket      string
+	noClear     bool
+}
+
+// isProfile returns true if more than a single plain object perf test
+// was asked for.
+func (o perfProfileOpts) isProfile() bool {
+	return len(o.sizes) > 1 || o.sustain > 0 || o.readShare+o.writeShare > 0
+}
+
+// runObjectPerfProfile runs one speedtest per object size, repeating
+// the whole distribution until the sustained duration has passed.
+func runObjectPerfProfile(ctx context.Context, client *madmin.AdminClient, opts perfProfileOpts) (*ObjProfileResults, error) {
+	totalWeight := 0
+	for _, s := range opts.sizes {
+		totalWeight += s.weight
+	}
+
+	start := time.Now()
+	profile := &ObjProfileResults{
+		ReadShare:  opts.readShare,
+		WriteShare: opts.writeShare,
+	}
+	for pass := 1; ; pass++ {
+		for _, s := range opts.sizes {
+			// Every size gets its share of one test duration per size.
+			duration := opts.duration * time.Duration(s.weight*len(opts.sizes)) / time.Duration(totalWeight)
+			if duration < time.Second {
+				duration = time.Second
+			}
+			resultCh, e := client.Speedtest(ctx, madmin.SpeedtestOpts{
+				Size:        int(s.size),
+				Duration:    duration,
+				Concurrency: opts.concurrency,
+				Autotune:    opts.autotune,
+				Bucket:      opts.bucket,
+				NoClear:     opts.noClear,
+			})
+			if e != nil {
+				return nil, e
+			}
+			var result madmin.SpeedTestResult
+			for r := range resultCh {
+				if r.Version != "" {
+					result = r
+				}
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if result.Version == "" {
+				return nil, fmt.Errorf("no results for %s objects", humanize.IBytes(s.size))
+			}
+			if !globalJSON {
+				console.Infof("Pass %d: %s, PUT %s/s %s objs/s, GET %s/s %s objs/s\n", pass,
+					objectTestShortResult(&result),
+					humanize.IBytes(result.PUTStats.ThroughputPerSec), humanize.Comma(int64(result.PUTStats.ObjectsPerSec)),
+					humanize.IBytes(result.GETStats.ThroughputPerSec), humanize.Comma(int64(result.GETStats.ObjectsPerSec)))
+			}
+			profile.Runs = append(profile.Runs, *convertObjTestResults(&result))
+		}
+		if time.Since(start) >= opts.sustain {
+			break
+		}
+	}
+	profile.Duration = time.Since(start)
+
+	put := make([]ObjPUTStats, 0, len(profile.Runs))
+	get := make([]ObjPUTStats, 0, len(profile.Runs))
+	for _, run := range profile.Runs {
+		put = append(put, run.PUTResults.Perf)
+		get = append(get, run.GETResults.Perf.ObjPUTStats)
+	}
+	profile.PUT = aggregateObjProfileStats(put)
+	profile.GET = aggregateObjProfileStats(get)
+	if opts.readShare+opts.writeShare > 0 {
+		profile.Mixed = mixObjProfileStats(profile.GET, profile.PUT, opts.readShare, opts.writeShare)
+	}
+	return profile, nil
+}
+
+// aggregateObjProfileStats averages the stats of all runs, each run is
+// weighted by its objects per second.
+func aggregateObjProfileStats(runs []ObjPUTStats) (out ObjProfileStats) {
+	var totalObjs uint64
+	for _, r := range runs {
+		totalObjs += r.ObjectsPerSec
+	}
+	if len(runs) == 0 || totalObjs == 0 {
+		return out
+	}
+
+	var throughput, objs uint64
+	weighted := func(get func(madmin.Timings) time.Duration) time.Duration {
+		var sum float64
+		for _, r := range runs {
+			sum += float64(get(r.Response)) * float64(r.ObjectsPerSec)
+		}
+		return time.Duration(sum / float64(totalObjs))
+	}
+	out.Response.Min = runs[0].Response.Min
+	for _, r := range runs {
+		throughput += r.Throughput
+		objs += r.ObjectsPerSec
+		if r.Response.Min < out.Response.Min {
+			out.Response.Min = r.Response.Min
+		}
+		if r.Response.Max > out.Response.Max {
+			out.Response.Max = r.Response.Max
+		}
+	}
+	out.Throughput = throughput / uint64(len(runs))
+	out.ObjectsPerSec = objs / uint64(len(runs))
+	out.Response.Avg = weighted(func(t madmin.Timings) time.Duration { return t.Avg })
+	out.Response.P50 = weighted(func(t madmin.Timings) time.Duration { return t.P50 })
+	out.Response.P75 = weighted(func(t madmin.Timings) time.Duration { return t.P75 })
+	out.Response.P95 = weighted(func(t madmin.Timings) time.Duration { return t.P95 })
+	out.Response.P99 = weighted(func(t madmin.Timings) time.Duration { return t.P99 })
+	out.Response.P999 = weighted(func(t madmin.Timings) time.Duration { return t.P999 })
+	out.Response.Long5p = weighted(func(t madmin.Timings) time.Duration { return t.Long5p })
+	out.Response.Short5p = weighted(func(t madmin.Timings) time.Duration { return t.Short5p })
+	out.Response.StdDev = weighted(func(t madmin.Timings) time.Duration { return t.StdDev })
+	out.Response.Range = out.Response.Max - out.Response.Min
+	return out
+}
+
+// mixObjProfileStats derives the stats of a workload reading and
+// writing in the given ratio: the time spent per byte (or object) is
+// the weighted sum of the time spent reading and writing it.
+func mixObjProfileStats(get, put ObjProfileStats, read, write int) *ObjProfileStats {
+	mix := func(getRate, putRate uint64) uint64 {
+		var perUnit float64
+		if read > 0 {
+			if getRate == 0 {
+				return 0
+			}
+			perUnit += float64(read) / float64(getRate)
+		}
+		if write > 0 {
+			if putRate == 0 {
+				return 0
+			}
+			perUnit += float64(write) / float64(putRate)
+		}
+		return uint64(float64(read+write) / perUnit)
+	}
+	return &ObjProfileStats{
+		Throughput:    mix(get.Throughput, put.Throughput),
+		ObjectsPerSec: mix(get.ObjectsPerSec, put.ObjectsPerSec),
+	}
+}
+
+// objectProfileResult returns a printable summary of a profile run.
+func objectProfileResult(p *ObjProfileResults) string {
+	line := func(op string, s ObjProfileStats) string {
+		return fmt.Sprintf("%-5s %s/s %s objs/s, latency p50 %s p99 %s p99.9 %s\n", op,
+			humanize.IBytes(s.Throughput), humanize.Comma(int64(s.ObjectsPerSec)),
+			s.Response.P50.Round(time.Microsecond), s.Response.P99.Round(time.Microsecond),
+			s.Response.P999.Round(time.Microsecond))
+	}
+	msg := fmt.Sprintf("%d runs in %s\n", len(p.Runs), p.Duration.Round(time.Second))
+	msg += line("PUT:", p.PUT)
+	msg += line("GET:", p.GET)
+	if p.Mixed != nil {
+		msg += fmt.Sprintf("Mixed %d:%d read:write: %s/s %s objs/s\n", p.ReadShare, p.WriteShare,
+			humanize.IBytes(p.Mixed.Throughput), humanize.Comma(int64(p.Mixed.ObjectsPerSec)))
+	}
+	return msg
+}
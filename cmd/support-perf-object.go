@@ -23,10 +23,10 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/dustin/go-humanize"
 	"github.com/minio/cli"
 	"github.com/minio/madmin-go/v3"
 	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
 )
 
 var adminSpeedtestCmd = cli.Command{
@@ -64,15 +64,11 @@ func mainAdminSpeedTestObject(ctx *cli.Context, aliasedURL string, outCh chan<-
 		fatalIf(errInvalidArgument(), "duration cannot be 0 or negative")
 		return nil
 	}
-	size, e := humanize.ParseBytes(ctx.String("size"))
+	sizes, e := parsePerfObjectSizes(ctx.String("size"))
 	if e != nil {
 		fatalIf(probe.NewError(e), "Unable to parse object size")
 		return nil
 	}
-	if size <= 0 {
-		fatalIf(errInvalidArgument(), "size is expected to be more than 0 bytes")
-		return nil
-	}
 	concurrent := ctx.Int("concurrent")
 	if concurrent <= 0 {
 		fatalIf(errInvalidArgument(), "concurrency cannot be '0' or negative")
@@ -84,8 +80,31 @@ func mainAdminSpeedTestObject(ctx *cli.Context, aliasedURL string, outCh chan<-
 	// in all other scenarios keep auto-tuning on.
 	autotune := !ctx.IsSet("concurrent")
 
+	opts := perfProfileOpts{
+		sizes:       sizes,
+		duration:    duration,
+		concurrency: concurrent,
+		autotune:    autotune,
+		bucket:      ctx.String("bucket"),
+		noClear:     ctx.Bool("noclear"),
+	}
+	if mix := ctx.String("mix"); mix != "" {
+		opts.readShare, opts.writeShare, e = parsePerfMix(mix)
+		fatalIf(probe.NewError(e), "Unable to parse read/write mix")
+	}
+	if sustain := ctx.String("sustain"); sustain != "" {
+		opts.sustain, e = time.ParseDuration(sustain)
+		fatalIf(probe.NewError(e), "Unable to parse sustained duration")
+		if opts.sustain <= 0 {
+			fatalIf(errInvalidArgument(), "sustained duration cannot be 0 or negative")
+		}
+	}
+	if opts.isProfile() {
+		return runAdminSpeedTestObjectProfile(ctxt, client, opts, outCh)
+	}
+
 	resultCh, e := client.Speedtest(ctxt, madmin.SpeedtestOpts{
-		Size:        int(size),
+		Size:        int(sizes[0].size),
 		Duration:    duration,
 		Concurrency: concurrent,
 		Autotune:    autotune,
@@ -165,3 +184,32 @@ func mainAdminSpeedTestObject(ctx *cli.Context, aliasedURL string, outCh chan<-
 
 	return nil
 }
+
+// runAdminSpeedTestObjectProfile runs an object perf test made of
+// several speedtests and reports their aggregated results.
+func runAdminSpeedTestObjectProfile(ctx context.Context, client *madmin.AdminClient, opts perfProfileOpts, outCh chan<- PerfTestResult) error {
+	r := PerfTestResult{
+		Type:  ObjectPerfTest,
+		Final: true,
+	}
+	profile, e := runObjectPerfProfile(ctx, client, opts)
+	if e != nil {
+		r.Err = e.Error()
+	} else {
+		r.ObjectProfile = profile
+	}
+
+	switch {
+	case globalJSON:
+		printMsg(convertPerfResult(r))
+		return nil
+	case r.Err != "":
+		console.Errorln("Object perf test failed:", r.Err)
+	default:
+		console.Println(objectProfileResult(profile))
+	}
+	if outCh != nil {
+		outCh <- r
+	}
+	return nil
+}
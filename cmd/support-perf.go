@@ -36,9 +36,17 @@ import (
 var supportPerfFlags = append([]cli.Flag{
 	cli.StringFlag{
 		Name:  "size",
-		Usage: "size of the object used for uploads/downloads",
+		Usage: "size of the object used for uploads/downloads, or a comma separated 'size:weight' distribution",
 		Value: "64MiB",
 	},
+	cli.StringFlag{
+		Name:  "mix",
+		Usage: "read:write ratio used to report the throughput of a mixed object workload, e.g. '70:30'",
+	},
+	cli.StringFlag{
+		Name:  "sustain",
+		Usage: "repeat the object perf test for this long, e.g. '1h'",
+	},
 	cli.BoolFlag{
 		Name:  "verbose, v",
 		Usage: "display per-server stats",
@@ -108,12 +116,16 @@ EXAMPLES:
 
   2. Run object storage, network, and drive performance tests on cluster with alias 'myminio', save and upload to SUBNET manually
      {{.Prompt}} {{.HelpName}} myminio --airgap
+
+  3. Run object performance tests with a mix of small and large objects for an hour, reporting latency percentiles in JSON
+     {{.Prompt}} {{.HelpName}} object myminio --size 4KiB:60,1MiB:30,64MiB:10 --mix 70:30 --sustain 1h --json
 `,
 }
 
 // PerfTestOutput - stores the final output of performance test(s)
 type PerfTestOutput struct {
 	ObjectResults          *ObjTestResults             `json:"object,omitempty"`
+	ObjectProfile          *ObjProfileResults          `json:"objectProfile,omitempty"`
 	NetResults             *NetTestResults             `json:"network,omitempty"`
 	SiteReplicationResults *SiteReplicationTestResults `json:"siteReplication,omitempty"`
 	DriveResults           *DriveTestResults           `json:"drive,omitempty"`
@@ -435,6 +447,7 @@ func updatePerfOutput(r PerfTestResult, out *PerfTestOutput) {
 		out.DriveResults = convertDriveTestResults(r.DriveResult)
 	case ObjectPerfTest:
 		out.ObjectResults = convertObjTestResults(r.ObjectResult)
+		out.ObjectProfile = r.ObjectProfile
 	case NetPerfTest:
 		out.NetResults = convertNetTestResults(r.NetResult)
 	case SiteReplicationPerfTest:
@@ -0,0 +1,338 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// prettyTableFlag is shared by head and cat.
+var prettyTableFlag = cli.BoolFlag{
+	Name:  "pretty-table",
+	Usage: "render the first records of CSV, JSON lines or Parquet objects as an aligned table",
+}
+
+// Formats of tabular data recognized by the preview.
+const (
+	tableFormatCSV     = "csv"
+	tableFormatTSV     = "tsv"
+	tableFormatJSON    = "json"
+	tableFormatParquet = "parquet"
+)
+
+// Columns wider than this are cut in the preview.
+const tablePreviewMaxColumnWidth = 40
+
+// tableFormatOf guesses the format of an object from its name and
+// content type, an empty format means the content has to be sniffed.
+func tableFormatOf(name, contentType string) string {
+	switch strings.ToLower(filepath.Ext(trimCompressionFileExts(name))) {
+	case ".csv":
+		return tableFormatCSV
+	case ".tsv", ".tab":
+		return tableFormatTSV
+	case ".json", ".jsonl", ".ndjson":
+		return tableFormatJSON
+	case ".parquet":
+		return tableFormatParquet
+	}
+	switch {
+	case strings.Contains(contentType, "csv"):
+		return tableFormatCSV
+	case strings.Contains(contentType, "tab-separated"):
+		return tableFormatTSV
+	case strings.Contains(contentType, "json"):
+		return tableFormatJSON
+	case strings.Contains(contentType, "parquet"):
+		return tableFormatParquet
+	}
+	return ""
+}
+
+// sniffTableFormat guesses the format from the first bytes of the
+// content: JSON lines start with an object, CSV and TSV have the same
+// number of delimiters on their first lines.
+func sniffTableFormat(head []byte) string {
+	trimmed := bytes.TrimLeft(head, " \t\r\n")
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		// A JSON document spanning several lines is not a record.
+		if line, _, _ := bytes.Cut(trimmed, []byte("\n")); json.Valid(line) {
+			return tableFormatJSON
+		}
+		return ""
+	}
+	lines := bytes.Split(head, []byte("\n"))
+	// The last line may be cut.
+	if len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) < 2 {
+		return ""
+	}
+	for _, delim := range []struct {
+		c      byte
+		format string
+	}{{'\t', tableFormatTSV}, {',', tableFormatCSV}, {';', tableFormatCSV}} {
+		n := bytes.Count(lines[0], []byte{delim.c})
+		if n == 0 {
+			continue
+		}
+		consistent := true
+		for _, line := range lines[1:] {
+			if len(bytes.TrimSpace(line)) > 0 && bytes.Count(line, []byte{delim.c}) != n {
+				consistent = false
+				break
+			}
+		}
+		if consistent {
+			return delim.format
+		}
+	}
+	return ""
+}
+
+// readCSVTable reads the header and up to n records of delimited data.
+func readCSVTable(r io.Reader, delim rune, n int64) (columns []string, rows [][]string, e error) {
+	cr := csv.NewReader(r)
+	cr.Comma = delim
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	if columns, e = cr.Read(); e != nil {
+		return nil, nil, e
+	}
+	for int64(len(rows)) < n {
+		record, e := cr.Read()
+		if e == io.EOF {
+			break
+		}
+		if e != nil {
+			return nil, nil, e
+		}
+		rows = append(rows, record)
+	}
+	return columns, rows, nil
+}
+
+// readJSONTable reads up to n JSON objects, one per line. The columns
+// are the keys of all records in the order they are first seen.
+func readJSONTable(r io.Reader, n int64) (columns []string, rows [][]string, e error) {
+	var records []map[string]string
+	seen := make(map[string]bool)
+	scn := bufio.NewScanner(r)
+	scn.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for int64(len(records)) < n && scn.Scan() {
+		line := bytes.TrimSpace(scn.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		keys, record, e := parseJSONRecord(line)
+		if e != nil {
+			return nil, nil, e
+		}
+		for _, k := range keys {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+		records = append(records, record)
+	}
+	if e = scn.Err(); e != nil {
+		return nil, nil, e
+	}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, k := range columns {
+			row[i] = record[k]
+		}
+		rows = append(rows, row)
+	}
+	return columns, rows, nil
+}
+
+// parseJSONRecord returns the keys of a JSON object in document order
+// and its values, nested values are kept as compact JSON.
+func parseJSONRecord(line []byte) (keys []string, record map[string]string, e error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if t, e := dec.Token(); e != nil || t != json.Delim('{') {
+		return nil, nil, fmt.Errorf("record is not a JSON object")
+	}
+	record = make(map[string]string)
+	for dec.More() {
+		t, e := dec.Token()
+		if e != nil {
+			return nil, nil, e
+		}
+		key, _ := t.(string)
+		var value json.RawMessage
+		if e = dec.Decode(&value); e != nil {
+			return nil, nil, e
+		}
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			s = string(value)
+		}
+		if _, ok := record[key]; !ok {
+			keys = append(keys, key)
+		}
+		record[key] = s
+	}
+	return keys, record, nil
+}
+
+// renderTable writes the rows aligned under their column names, cells
+// wider than tablePreviewMaxColumnWidth are cut.
+func renderTable(w io.Writer, columns []string, rows [][]string) {
+	widths := make([]int, len(columns))
+	for i, c := range columns {
+		widths[i] = utf8.RuneCountInString(c)
+		for _, row := range rows {
+			if i < len(row) {
+				if l := utf8.RuneCountInString(tableCell(row[i])); l > widths[i] {
+					widths[i] = l
+				}
+			}
+		}
+		if widths[i] > tablePreviewMaxColumnWidth {
+			widths[i] = tablePreviewMaxColumnWidth
+		}
+	}
+
+	writeRow := func(theme string, cells []string) {
+		line := make([]string, len(widths))
+		for i, width := range widths {
+			var cell string
+			if i < len(cells) {
+				cell = tableCell(cells[i])
+			}
+			if runes := []rune(cell); len(runes) > width {
+				cell = string(runes[:width-3]) + "..."
+			}
+			if i < len(widths)-1 {
+				cell += strings.Repeat(" ", width-utf8.RuneCountInString(cell))
+			}
+			line[i] = console.Colorize(theme, cell)
+		}
+		fmt.Fprintln(w, strings.TrimRight(strings.Join(line, "  "), " "))
+	}
+
+	sep := make([]string, len(widths))
+	for i, width := range widths {
+		sep[i] = strings.Repeat("-", width)
+	}
+	writeRow("TablePreviewHeader", columns)
+	writeRow("TablePreview", sep)
+	for _, row := range rows {
+		writeRow("TablePreview", row)
+	}
+}
+
+// tableCell keeps a value on a single line of the table, control
+// characters which could corrupt the terminal are replaced by spaces.
+func tableCell(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// previewTable renders the first n records of r as a table. It returns
+// false, leaving the content to the caller, when r is not tabular.
+func previewTable(r *bufio.Reader, format string, n int64) (bool, *probe.Error) {
+	if format == "" {
+		head, _ := r.Peek(64 * 1024)
+		if format = sniffTableFormat(head); format == "" {
+			return false, nil
+		}
+	}
+
+	var columns []string
+	var rows [][]string
+	var e error
+	switch format {
+	case tableFormatCSV:
+		delim := ','
+		if first, _ := r.Peek(4096); bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+			delim = ';'
+		}
+		columns, rows, e = readCSVTable(r, delim, n)
+	case tableFormatTSV:
+		columns, rows, e = readCSVTable(r, '\t', n)
+	case tableFormatJSON:
+		columns, rows, e = readJSONTable(r, n)
+	default:
+		return false, nil
+	}
+	if e != nil {
+		return false, probe.NewError(e)
+	}
+
+	renderTable(os.Stdout, columns, rows)
+	return true, nil
+}
+
+// previewParquetTable renders the first n records of a Parquet object,
+// which is read with S3 Select as JSON lines.
+func previewParquetTable(ctx context.Context, sourceURL string, encKeyDB encryptionConfig, n int64) *probe.Error {
+	alias, _, _, err := expandAlias(sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	clnt, err := newClient(sourceURL)
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	if clnt.GetURL().Type != objectStorage {
+		return probe.NewError(fmt.Errorf("previewing Parquet requires S3 Select, `%s` is not an object", sourceURL))
+	}
+	reader, err := clnt.Select(ctx, fmt.Sprintf("SELECT * FROM S3Object LIMIT %d", n), getSSE(sourceURL, encKeyDB[alias]), SelectObjectOpts{
+		InputSerOpts:  map[string]map[string]string{tableFormatParquet: {}},
+		OutputSerOpts: map[string]map[string]string{tableFormatJSON: {}},
+	})
+	if err != nil {
+		return err.Trace(sourceURL)
+	}
+	defer reader.Close()
+	_, err = previewTable(bufio.NewReader(reader), tableFormatJSON, n)
+	return err
+}
+
+// setTablePreviewColors sets the colors of the table preview.
+func setTablePreviewColors() {
+	console.SetColor("TablePreviewHeader", color.New(color.FgGreen, color.Bold))
+	console.SetColor("TablePreview", color.New())
+}
@@ -52,7 +52,7 @@ var tagSetCmd = cli.Command{
 	Action:       mainSetTag,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(tagSetFlags, globalFlags...),
+	Flags:        append(append(tagSetFlags, statsFlag), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -165,6 +165,7 @@ func mainSetTag(cliCtx *cli.Context) error {
 	defer cancelSetTag()
 
 	console.SetColor("List", color.New(color.FgGreen))
+	console.SetColor("Stats", color.New(color.Bold))
 
 	targetURL, versionID, timeRef, withVersions, tags, recursive := parseSetTagSyntax(cliCtx)
 	if timeRef.IsZero() && withVersions {
@@ -174,10 +175,16 @@ func mainSetTag(cliCtx *cli.Context) error {
 	clnt, err := newClient(targetURL)
 	fatalIf(err.Trace(cliCtx.Args()...), "Unable to initialize target "+targetURL)
 
+	stats := newBulkStats()
+	if cliCtx.Bool("stats") {
+		defer func() { printMsg(stats.Message()) }()
+	}
+
 	alias, urlStr, _ := mustExpandAlias(targetURL)
 	if timeRef.IsZero() && !withVersions && !recursive {
 		err := setTagsSingle(ctx, alias, urlStr, versionID, tags)
 		fatalIf(err.Trace(), "Unable to set tags on `%s`", targetURL)
+		stats.Succeeded(0)
 		return nil
 	}
 	for content := range clnt.List(ctx, ListOptions{TimeRef: timeRef, WithOlderVersions: withVersions, Recursive: recursive}) {
@@ -188,6 +195,7 @@ func mainSetTag(cliCtx *cli.Context) error {
 
 		// Dont set tag for the delete marker
 		if content.IsDeleteMarker {
+			stats.Skipped()
 			continue
 		}
 
@@ -198,8 +206,10 @@ func mainSetTag(cliCtx *cli.Context) error {
 		err := setTagsSingle(ctx, alias, content.URL.String(), content.VersionID, tags)
 		if err != nil {
 			errorIf(err.Trace(clnt.GetURL().String()), "Invalid URL")
+			stats.Failed()
 			continue
 		}
+		stats.Succeeded(content.Size)
 	}
 
 	return nil
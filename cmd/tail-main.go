@@ -0,0 +1,279 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Size of the ranges read backwards from the end of an object to find
+// its last lines.
+var tailChunkSize int64 = 64 << 10
+
+var tailFlags = []cli.Flag{
+	cli.Int64Flag{
+		Name:  "n,lines",
+		Usage: "print the last 'n' lines",
+		Value: 10,
+	},
+	cli.Int64Flag{
+		Name:  "c,bytes",
+		Usage: "print the last 'c' bytes instead of lines",
+	},
+	cli.BoolFlag{
+		Name:  "follow, f",
+		Usage: "keep printing what is appended to the object every time it is written, until interrupted",
+	},
+	cli.StringFlag{
+		Name:  "rewind",
+		Usage: "select an object version at specified time",
+	},
+	cli.StringFlag{
+		Name:  "version-id, vid",
+		Usage: "select an object version to display",
+	},
+}
+
+// Display the end of an object.
+var tailCmd = cli.Command{
+	Name:         "tail",
+	Usage:        "display last 'n' lines of an object",
+	Action:       mainTail,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        joinFlags(tailFlags, ioFlags, globalFlags),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+ENVIRONMENT VARIABLES:
+  MC_ENCRYPT_KEY:  list of comma delimited prefix=secret values
+
+NOTE:
+  '{{.HelpName}}' only downloads the end of the objects, which are displayed as stored.
+
+EXAMPLES:
+  1. Display the last 10 lines of a log object on Amazon S3.
+     {{.Prompt}} {{.HelpName}} s3/logs/app/server.log
+
+  2. Display the last 4KiB of an object.
+     {{.Prompt}} {{.HelpName}} -c 4096 s3/logs/app/server.log
+
+  3. Display the last 100 lines of a log object, then what is appended to it as it is written.
+     {{.Prompt}} {{.HelpName}} -n 100 --follow myminio/logs/app/server.log
+
+  4. Display the last lines of a specific object version.
+     {{.Prompt}} {{.HelpName}} --version-id "3ddac055-89a7-40fa-8cd3-530a5581b6b8" s3/logs/app/server.log
+`,
+}
+
+// tailOpts holds the options of the tail command.
+type tailOpts struct {
+	versionID string
+	timeRef   time.Time
+	nlines    int64
+	nbytes    int64 // Lines are printed if negative.
+	follow    bool
+}
+
+// parseTailSyntax performs command-line input validation for tail command.
+func parseTailSyntax(ctx *cli.Context) tailOpts {
+	if !ctx.Args().Present() {
+		showCommandHelpAndExit(ctx, 1) // last argument is exit code
+	}
+
+	o := tailOpts{
+		versionID: ctx.String("version-id"),
+		nlines:    ctx.Int64("lines"),
+		nbytes:    ctx.Int64("bytes"),
+		follow:    ctx.Bool("follow"),
+	}
+	rewind := ctx.String("rewind")
+	switch {
+	case o.versionID != "" && rewind != "":
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify --version-id and --rewind at the same time")
+	case o.nlines < 0 || o.nbytes < 0:
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify negative --lines or --bytes")
+	case ctx.IsSet("bytes") && ctx.IsSet("lines"):
+		fatalIf(errInvalidArgument().Trace(), "You cannot specify both --lines and --bytes")
+	case o.follow && (o.versionID != "" || rewind != ""):
+		fatalIf(errInvalidArgument().Trace(), "You cannot --follow an object version, only the latest version is written")
+	case o.follow && len(ctx.Args()) != 1:
+		fatalIf(errInvalidArgument().Trace(ctx.Args()...), "You can only --follow one object")
+	}
+	if !ctx.IsSet("bytes") {
+		o.nbytes = -1
+	}
+	o.timeRef = parseRewindFlag(rewind)
+	return o
+}
+
+// getObjectRange returns length bytes of an object from start.
+func getObjectRange(ctx context.Context, clnt Client, opts GetOptions, start, length int64) ([]byte, *probe.Error) {
+	opts.RangeStart, opts.RangeLength = start, length
+	reader, err := clnt.Get(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	data := make([]byte, length)
+	if _, e := io.ReadFull(reader, data); e != nil {
+		return nil, probe.NewError(e)
+	}
+	return data, nil
+}
+
+// tailLinesOffset returns the offset of the last nlines lines of an
+// object of size bytes, read backwards by ranges of tailChunkSize. A
+// newline ending the object does not start a line.
+func tailLinesOffset(ctx context.Context, clnt Client, opts GetOptions, size, nlines int64) (int64, *probe.Error) {
+	if nlines == 0 {
+		return size, nil
+	}
+	for end := size; end > 0; {
+		start := end - tailChunkSize
+		if start < 0 {
+			start = 0
+		}
+		data, err := getObjectRange(ctx, clnt, opts, start, end-start)
+		if err != nil {
+			return 0, err
+		}
+		for i := bytes.LastIndexByte(data, '\n'); i >= 0; i = bytes.LastIndexByte(data[:i], '\n') {
+			if start+int64(i) == size-1 {
+				continue
+			}
+			if nlines--; nlines == 0 {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
+
+// tailOut prints the bytes of an object from start to end.
+func tailOut(ctx context.Context, clnt Client, opts GetOptions, start, end int64) *probe.Error {
+	if start >= end {
+		return nil
+	}
+	opts.RangeStart, opts.RangeLength = start, end-start
+	reader, err := clnt.Get(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return catOut(reader, end-start)
+}
+
+// tailFollow prints what is appended to an object from offset, every
+// time the object is written, until ctx is canceled. An object replaced
+// by a shorter one is printed again from its start.
+func tailFollow(ctx context.Context, clnt Client, opts GetOptions, offset int64) *probe.Error {
+	wo, err := clnt.Watch(ctx, WatchOptions{Events: []string{"put"}})
+	if err != nil {
+		return err
+	}
+	defer close(wo.DoneChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-wo.Events():
+			if !ok {
+				return nil
+			}
+			content, err := clnt.Stat(ctx, StatOptions{sse: opts.SSE})
+			if err != nil {
+				return err
+			}
+			if content.Size < offset {
+				offset = 0
+			}
+			if err = tailOut(ctx, clnt, opts, offset, content.Size); err != nil {
+				return err
+			}
+			offset = content.Size
+		case err, ok := <-wo.Errors():
+			if !ok {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// tailURL displays the end of an object, and with --follow what is
+// appended to it afterwards.
+func tailURL(ctx context.Context, sourceURL string, encKeyDB encryptionConfig, o tailOpts) *probe.Error {
+	clnt, content, err := url2Stat(ctx, sourceURL, o.versionID, false, encKeyDB, o.timeRef, false)
+	if err != nil {
+		return err
+	}
+	if content.Type.IsDir() {
+		return errInvalidArgument().Trace(sourceURL)
+	}
+	alias, _ := url2Alias(sourceURL)
+	opts := GetOptions{VersionID: content.VersionID, SSE: getSSE(sourceURL, encKeyDB[alias])}
+
+	offset := content.Size - o.nbytes
+	if o.nbytes < 0 {
+		if offset, err = tailLinesOffset(ctx, clnt, opts, content.Size, o.nlines); err != nil {
+			return err
+		}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if err = tailOut(ctx, clnt, opts, offset, content.Size); err != nil {
+		return err
+	}
+	if !o.follow {
+		return nil
+	}
+	opts.VersionID = ""
+	return tailFollow(ctx, clnt, opts, content.Size)
+}
+
+// mainTail is the main entry point for tail command.
+func mainTail(cliCtx *cli.Context) error {
+	ctx, cancelTail := context.WithCancel(globalContext)
+	defer cancelTail()
+
+	// Parse encryption keys per command.
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	o := parseTailSyntax(cliCtx)
+	for _, url := range cliCtx.Args() {
+		fatalIf(tailURL(ctx, url, encKeyDB, o).Trace(url), "Unable to read from `"+url+"`.")
+	}
+	return nil
+}
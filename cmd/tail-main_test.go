@@ -0,0 +1,64 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTailLinesOffset(t *testing.T) {
+	defer func(size int64) { tailChunkSize = size }(tailChunkSize)
+	tailChunkSize = 4
+
+	root := t.TempDir()
+	testCases := []struct {
+		data     string
+		nlines   int64
+		expected int64
+	}{
+		{"one\ntwo\nthree\n", 1, 8},
+		{"one\ntwo\nthree\n", 2, 4},
+		{"one\ntwo\nthree\n", 3, 0},
+		{"one\ntwo\nthree\n", 10, 0},
+		{"one\ntwo\nthree\n", 0, 14},
+		// The last line is printed without a newline ending it.
+		{"one\ntwo\nthree", 1, 8},
+		{"one\n\n\nfour\n", 2, 5},
+		{"", 10, 0},
+	}
+	for i, testCase := range testCases {
+		fpath := filepath.Join(root, "object")
+		if e := os.WriteFile(fpath, []byte(testCase.data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+		clnt, err := fsNew(fpath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset, err := tailLinesOffset(context.Background(), clnt, GetOptions{}, int64(len(testCase.data)), testCase.nlines)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if offset != testCase.expected {
+			t.Errorf("Test %d: expected offset %d, got %d", i+1, testCase.expected, offset)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/wildcard"
+)
+
+// tierFlag is shared by ls and find.
+var tierFlag = cli.StringFlag{
+	Name:  "tier",
+	Usage: "filter by tier residency: 'local', 'remote' (transitioned or archived), 'restoring' or 'restored'",
+}
+
+// Tier residency of an object.
+const (
+	residencyLocal     = "local"
+	residencyRemote    = "remote"
+	residencyRestoring = "restoring"
+	residencyRestored  = "restored"
+)
+
+// Storage classes whose data is readable without a restore, any other
+// class is an archive or the name of a MinIO remote tier.
+var localStorageClasses = map[string]bool{
+	"":                    true,
+	"STANDARD":            true,
+	"REDUCED_REDUNDANCY":  true,
+	"STANDARD_IA":         true,
+	"ONEZONE_IA":          true,
+	"INTELLIGENT_TIERING": true,
+	"GLACIER_IR":          true,
+	"OUTPOSTS":            true,
+}
+
+// Number of objects whose restore status is fetched together, and the
+// number of concurrent requests per batch.
+const (
+	tierStatBatchSize   = 100
+	tierStatConcurrency = 16
+)
+
+// contentResidency returns the tier residency of an object.
+func contentResidency(c *ClientContent) string {
+	if localStorageClasses[strings.ToUpper(c.StorageClass)] {
+		return residencyLocal
+	}
+	switch {
+	case c.Restore == nil:
+		return residencyRemote
+	case c.Restore.OngoingRestore:
+		return residencyRestoring
+	case c.Restore.ExpiryTime.After(time.Now()):
+		return residencyRestored
+	}
+	return residencyRemote
+}
+
+// tierResidencyFilter filters listed objects by storage class and by
+// tier residency.
+type tierResidencyFilter struct {
+	// Wildcard pattern of storage classes, case insensitive.
+	storageClass string
+	residency    string
+	// Alias of the listed objects, to fetch their restore status.
+	alias string
+}
+
+// parseTierResidencyFilter validates a storage class pattern and a
+// tier residency.
+func parseTierResidencyFilter(storageClass, residency string) (tierResidencyFilter, *probe.Error) {
+	f := tierResidencyFilter{
+		storageClass: strings.ToUpper(storageClass),
+		residency:    strings.ToLower(residency),
+	}
+	switch f.residency {
+	case "", residencyLocal, residencyRemote, residencyRestoring, residencyRestored:
+	default:
+		return f, probe.NewError(fmt.Errorf("unknown tier residency `%s`, expected one of local, remote, restoring or restored", f.residency))
+	}
+	return f, nil
+}
+
+func (f tierResidencyFilter) enabled() bool {
+	return f.storageClass != "" || f.residency != ""
+}
+
+// needsRestoreStatus returns true if the residency of an object cannot
+// be told from its listing, which mostly lacks the restore status.
+func (f tierResidencyFilter) needsRestoreStatus(c *ClientContent) bool {
+	return f.residency != "" && f.residency != residencyLocal &&
+		c.Restore == nil && c.URL.Type == objectStorage && !c.Type.IsDir() &&
+		contentResidency(c) == residencyRemote
+}
+
+func (f tierResidencyFilter) match(c *ClientContent) bool {
+	if c.Type.IsDir() {
+		return f.residency == ""
+	}
+	if f.storageClass != "" && f.storageClass != "*" && !wildcard.Match(f.storageClass, strings.ToUpper(c.StorageClass)) {
+		return false
+	}
+	return f.residency == "" || contentResidency(c) == f.residency
+}
+
+// filterTierResidency passes on the listed objects matching the filter,
+// listing errors are passed on as is. Objects of remote tiers are
+// stat'ed in batches to learn whether they are restored.
+func filterTierResidency(ctx context.Context, f tierResidencyFilter, in <-chan *ClientContent) <-chan *ClientContent {
+	if !f.enabled() {
+		return in
+	}
+	out := make(chan *ClientContent)
+	go func() {
+		defer close(out)
+		batch := make([]*ClientContent, 0, tierStatBatchSize)
+		flush := func() bool {
+			f.fetchRestoreStatus(ctx, batch)
+			for _, c := range batch {
+				if c.Err != nil || f.match(c) {
+					select {
+					case out <- c:
+					case <-ctx.Done():
+						return false
+					}
+				}
+			}
+			batch = batch[:0]
+			return true
+		}
+		for c := range in {
+			if batch = append(batch, c); len(batch) == tierStatBatchSize && !flush() {
+				return
+			}
+		}
+		flush()
+	}()
+	return out
+}
+
+// fetchRestoreStatus stats the objects of a batch which need their
+// restore status, concurrently.
+func (f tierResidencyFilter) fetchRestoreStatus(ctx context.Context, batch []*ClientContent) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, tierStatConcurrency)
+	for _, c := range batch {
+		if c.Err != nil || !f.needsRestoreStatus(c) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c *ClientContent) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			urlStr := c.URL.String()
+			clnt, err := newClientFromAlias(f.alias, urlStr)
+			if err == nil {
+				var st *ClientContent
+				if st, err = clnt.Stat(ctx, StatOptions{versionID: c.VersionID}); err == nil {
+					c.Restore = st.Restore
+					return
+				}
+			}
+			errorIf(err.Trace(urlStr), "Unable to get the restore status of `"+urlStr+"`.")
+		}(c)
+	}
+	wg.Wait()
+}
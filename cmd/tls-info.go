@@ -0,0 +1,142 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+// tlsVersionNames names the TLS versions, tls.VersionName needs go1.21.
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// tlsCertificateInfo describes one certificate of a chain.
+type tlsCertificateInfo struct {
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	Fingerprint string    `json:"sha256Fingerprint"`
+	NotAfter    time.Time `json:"notAfter"`
+}
+
+// tlsInfoMessage container for the transport security of an endpoint.
+type tlsInfoMessage struct {
+	Status       string               `json:"status"`
+	Endpoint     string               `json:"endpoint"`
+	Encrypted    bool                 `json:"encrypted"`
+	Version      string               `json:"tlsVersion,omitempty"`
+	CipherSuite  string               `json:"cipherSuite,omitempty"`
+	Protocol     string               `json:"protocol,omitempty"`
+	ServerName   string               `json:"serverName,omitempty"`
+	Certificates []tlsCertificateInfo `json:"certificates,omitempty"`
+}
+
+func (m tlsInfoMessage) String() string {
+	if !m.Encrypted {
+		return fmt.Sprintf("TLS %s: not encrypted (plain HTTP)", m.Endpoint)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "TLS %s: %s, %s", m.Endpoint, m.Version, m.CipherSuite)
+	if m.Protocol != "" {
+		fmt.Fprintf(&b, ", %s", m.Protocol)
+	}
+	for i, cert := range m.Certificates {
+		expires := "expired"
+		if left := time.Until(cert.NotAfter); left > 0 {
+			expires = "in " + timeDurationToHumanizedDuration(left).StringShort()
+		}
+		fmt.Fprintf(&b, "\n  [%d] %s (issuer: %s)\n      sha256: %s\n      expires: %s (%s)",
+			i, cert.Subject, cert.Issuer, cert.Fingerprint, cert.NotAfter.Format(time.RFC3339), expires)
+	}
+	return b.String()
+}
+
+func (m tlsInfoMessage) JSON() string {
+	m.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(m, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// newTLSInfoMessage describes the connection to an endpoint.
+func newTLSInfoMessage(endpoint string, state *tls.ConnectionState) tlsInfoMessage {
+	msg := tlsInfoMessage{Endpoint: endpoint, Encrypted: state != nil}
+	if state == nil {
+		return msg
+	}
+	msg.Version = tlsVersionNames[state.Version]
+	if msg.Version == "" {
+		msg.Version = fmt.Sprintf("0x%04x", state.Version)
+	}
+	msg.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	msg.Protocol = state.NegotiatedProtocol
+	msg.ServerName = state.ServerName
+	for _, cert := range state.PeerCertificates {
+		sum := sha256.Sum256(cert.Raw)
+		msg.Certificates = append(msg.Certificates, tlsCertificateInfo{
+			Subject:     cert.Subject.String(),
+			Issuer:      cert.Issuer.String(),
+			Fingerprint: hex.EncodeToString(sum[:]),
+			NotAfter:    cert.NotAfter,
+		})
+	}
+	return msg
+}
+
+// tlsInfoEndpoints remembers the endpoints already reported, across
+// all clients.
+var tlsInfoEndpoints sync.Map
+
+// tlsInfoTransport prints the transport security of every endpoint
+// once, without dumping any request or response as --debug does.
+type tlsInfoTransport struct {
+	transport http.RoundTripper
+}
+
+func (t tlsInfoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, e := t.transport.RoundTrip(req)
+	if e != nil {
+		return resp, e
+	}
+	endpoint := req.URL.Scheme + "://" + req.URL.Host
+	if _, reported := tlsInfoEndpoints.LoadOrStore(endpoint, true); !reported {
+		msg := newTLSInfoMessage(endpoint, resp.TLS)
+		// Reports go to stderr so that they never mix with the output
+		// of the command, such as the content of 'mc cat'.
+		if globalJSON {
+			console.Debugln(msg.JSON())
+		} else {
+			console.Debugln(msg.String())
+		}
+	}
+	return resp, e
+}
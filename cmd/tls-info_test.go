@@ -0,0 +1,106 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewTLSInfoMessage(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resp, e := server.Client().Get(server.URL)
+	if e != nil {
+		t.Fatal(e)
+	}
+	resp.Body.Close()
+
+	msg := newTLSInfoMessage(server.URL, resp.TLS)
+	if !msg.Encrypted || !strings.HasPrefix(msg.Version, "TLS 1.") || msg.CipherSuite == "" {
+		t.Fatalf("unexpected TLS info %+v", msg)
+	}
+	sum := sha256.Sum256(server.Certificate().Raw)
+	if len(msg.Certificates) == 0 || msg.Certificates[0].Fingerprint != hex.EncodeToString(sum[:]) {
+		t.Fatalf("expected the fingerprint of the server certificate, got %+v", msg.Certificates)
+	}
+	if !strings.Contains(msg.String(), msg.Certificates[0].Fingerprint) || !strings.Contains(msg.JSON(), `"encrypted": true`) {
+		t.Fatalf("expected the certificate to be printed, got %s", msg.String())
+	}
+
+	// Unknown versions are printed in hex.
+	if msg = newTLSInfoMessage(server.URL, &tls.ConnectionState{Version: 0x0305}); msg.Version != "0x0305" {
+		t.Fatalf("unexpected version %s", msg.Version)
+	}
+
+	msg = newTLSInfoMessage("http://localhost:9000", nil)
+	if msg.Encrypted || !strings.Contains(msg.String(), "not encrypted") {
+		t.Fatalf("unexpected plain HTTP info %+v", msg)
+	}
+}
+
+func TestTLSInfoTransport(t *testing.T) {
+	var requests int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: tlsInfoTransport{transport: server.Client().Transport}}
+	for i := 0; i < 3; i++ {
+		resp, e := client.Get(server.URL + "/bucket/object")
+		if e != nil {
+			t.Fatal(e)
+		}
+		resp.Body.Close()
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Fatalf("expected all requests to be forwarded, got %d", n)
+	}
+	// Every endpoint is reported once, whatever the path.
+	if _, reported := tlsInfoEndpoints.Load(server.URL); !reported {
+		t.Fatalf("expected %s to be reported", server.URL)
+	}
+	var endpoints int
+	tlsInfoEndpoints.Range(func(key, _ interface{}) bool {
+		if strings.HasPrefix(key.(string), server.URL) {
+			endpoints++
+		}
+		return true
+	})
+	if endpoints != 1 {
+		t.Fatalf("expected one report, got %d", endpoints)
+	}
+}
+
+func TestDebugLiteConfig(t *testing.T) {
+	defer func(debugLite bool) { globalDebugLite = debugLite }(globalDebugLite)
+	for _, debugLite := range []bool{false, true} {
+		globalDebugLite = debugLite
+		if config := NewS3Config("https://s3.example.com/bucket", &aliasConfigV10{}); config.DebugLite != debugLite {
+			t.Errorf("expected DebugLite %t, got %t", debugLite, config.DebugLite)
+		}
+	}
+}
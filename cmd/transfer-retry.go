@@ -0,0 +1,166 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Longest wait between two retries of a failed object.
+const maxRetryDelay = 5 * time.Minute
+
+// retryFlags are shared by the commands transferring objects.
+var retryFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "retry",
+		Usage: "retry a failed object up to this many times, waiting twice as long before every retry",
+	},
+	cli.StringFlag{
+		Name:  "retry-delay",
+		Value: "1s",
+		Usage: "wait this long, with some jitter, before the first retry of a failed object",
+	},
+	cli.StringFlag{
+		Name:  "failed-log",
+		Usage: "write the keys of the objects which could not be copied to this file, to be copied again with --files-from",
+	},
+}
+
+// parseRetry parses the --retry and --retry-delay flags.
+func parseRetry(cliCtx *cli.Context) (int, time.Duration, *probe.Error) {
+	retries := cliCtx.Int("retry")
+	if retries < 0 {
+		return 0, 0, errInvalidArgument().Trace(cliCtx.String("retry"))
+	}
+	value := cliCtx.String("retry-delay")
+	if value == "" {
+		return retries, 0, nil
+	}
+	delay, e := time.ParseDuration(value)
+	if e != nil {
+		return 0, 0, probe.NewError(e).Trace(value)
+	}
+	if delay < 0 {
+		return 0, 0, errInvalidArgument().Trace(value)
+	}
+	return retries, delay, nil
+}
+
+// retryBackoff returns how long to wait before the retry following
+// attempt, doubling delay for every attempt. Half of the wait is random
+// so that objects failed together are not retried together.
+func retryBackoff(delay time.Duration, attempt int) time.Duration {
+	backoff := delay
+	for i := 0; i < attempt && backoff < maxRetryDelay; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryDelay {
+		backoff = maxRetryDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// uploadSourceToTargetURLWithRetry uploads like uploadSourceToTargetURLWithWatchdog,
+// failed uploads are retried up to urls.Retries times.
+func uploadSourceToTargetURLWithRetry(ctx context.Context, urls URLs, progress io.Reader, encKeyDB encryptionConfig, preserve, isZip bool) URLs {
+	if urls.Retries <= 0 {
+		return uploadSourceToTargetURLWithWatchdog(ctx, urls, progress, encKeyDB, preserve, isZip)
+	}
+
+	for attempt := 0; ; attempt++ {
+		w := newTransferWatchdog(progress)
+		ret := uploadSourceToTargetURLWithWatchdog(ctx, urls, w, encKeyDB, preserve, isZip)
+		if ret.Error == nil || attempt == urls.Retries || ctx.Err() != nil {
+			return ret
+		}
+		w.rewind()
+		select {
+		case <-ctx.Done():
+			return ret
+		case <-time.After(retryBackoff(urls.RetryDelay, attempt)):
+		}
+	}
+}
+
+// failedLog writes the keys of the objects which could not be copied,
+// relative to their source folder as read by --files-from.
+type failedLog struct {
+	mu    sync.Mutex
+	f     *os.File
+	roots []string
+}
+
+func newFailedLog(path string, sourceURLs []string) (*failedLog, *probe.Error) {
+	f, e := os.Create(path)
+	if e != nil {
+		return nil, probe.NewError(e).Trace(path)
+	}
+	l := &failedLog{f: f}
+	for _, sourceURL := range sourceURLs {
+		_, urlStr, _ := mustExpandAlias(sourceURL)
+		l.roots = append(l.roots, filepath.ToSlash(newClientURL(urlStr).Path))
+	}
+	return l, nil
+}
+
+// key returns the key of an object relative to the longest source
+// folder it was found in.
+func (l *failedLog) key(urls URLs) string {
+	objectPath := filepath.ToSlash(urls.SourceContent.URL.Path)
+	key := objectPath
+	for _, root := range l.roots {
+		if rel := strings.TrimPrefix(objectPath, root); rel != objectPath && rel != "" && len(rel) < len(key) {
+			key = rel
+		}
+	}
+	return strings.TrimLeft(key, "/")
+}
+
+// Add records the object of urls as failed, a nil log records nothing.
+func (l *failedLog) Add(urls URLs) {
+	if l == nil || urls.SourceContent == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.f.WriteString(l.key(urls) + "\n")
+}
+
+// Close closes the log, a nil log is not closed.
+func (l *failedLog) Close() *probe.Error {
+	if l == nil {
+		return nil
+	}
+	if e := l.f.Close(); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+)
+
+// Number of times a stalled transfer is retried before giving up.
+const stallRetries = 3
+
+// stallTimeoutFlag is shared by the commands transferring objects.
+var stallTimeoutFlag = cli.StringFlag{
+	Name:  "stall-timeout",
+	Usage: "abort and retry a transfer which makes no progress for this long, e.g. '30s'",
+}
+
+// transferWatchdog forwards the progress of a single transfer and
+// cancels it if no bytes were transferred for a while.
+type transferWatchdog struct {
+	progress   io.Reader
+	lastActive int64 // unix nano, updated atomically
+	written    int64 // updated atomically
+	stalled    int32 // updated atomically
+}
+
+func newTransferWatchdog(progress io.Reader) *transferWatchdog {
+	return &transferWatchdog{progress: progress, lastActive: time.Now().UnixNano()}
+}
+
+// Read records the activity before forwarding it to the wrapped progress.
+func (w *transferWatchdog) Read(p []byte) (n int, e error) {
+	atomic.StoreInt64(&w.lastActive, time.Now().UnixNano())
+	atomic.AddInt64(&w.written, int64(len(p)))
+	if w.progress == nil {
+		return len(p), nil
+	}
+	return w.progress.Read(p)
+}
+
+// watch cancels the transfer once it has been idle for timeout.
+func (w *transferWatchdog) watch(ctx context.Context, cancel context.CancelFunc, timeout time.Duration) {
+	interval := timeout / 4
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&w.lastActive))) >= timeout {
+				atomic.StoreInt32(&w.stalled, 1)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// rewind takes the bytes of an aborted attempt back from the progress.
+func (w *transferWatchdog) rewind() {
+	rewindProgress(w.progress, atomic.LoadInt64(&w.written))
+}
+
+// rewindProgress takes n bytes back from progress.
+func rewindProgress(progress io.Reader, n int64) {
+	switch p := progress.(type) {
+	case *transferWatchdog:
+		atomic.AddInt64(&p.written, -n)
+		rewindProgress(p.progress, n)
+	case Status:
+		p.Add(-n)
+	case *progressBar:
+		p.ProgressBar.Add64(-n)
+	case *accounter:
+		p.Add(-n)
+	case *jsonProgress:
+		p.Add(-n)
+	}
+}
+
+// uploadSourceToTargetURLWithWatchdog uploads like uploadSourceToTargetURL,
+// transfers stalling longer than urls.StallTimeout are aborted and retried.
+func uploadSourceToTargetURLWithWatchdog(ctx context.Context, urls URLs, progress io.Reader, encKeyDB encryptionConfig, preserve, isZip bool) URLs {
+	// Server side copies report no progress until they are done.
+	if urls.StallTimeout <= 0 || isServerSideCopy(urls, isZip) {
+		return uploadSourceToTargetURL(ctx, urls, progress, encKeyDB, preserve, isZip)
+	}
+
+	for attempt := 0; ; attempt++ {
+		wctx, cancel := context.WithCancel(ctx)
+		w := newTransferWatchdog(progress)
+		go w.watch(wctx, cancel, urls.StallTimeout)
+		ret := uploadSourceToTargetURL(wctx, urls, w, encKeyDB, preserve, isZip)
+		cancel()
+		if atomic.LoadInt32(&w.stalled) == 0 || ctx.Err() != nil {
+			return ret
+		}
+		w.rewind()
+		if attempt == stallRetries {
+			return ret.WithError(errTransferStalled(urls.SourceContent.URL.String(), urls.StallTimeout))
+		}
+	}
+}
+
+// parseStallTimeout parses the --stall-timeout flag, zero disables the watchdog.
+func parseStallTimeout(cliCtx *cli.Context) (time.Duration, *probe.Error) {
+	value := cliCtx.String("stall-timeout")
+	if value == "" {
+		return 0, nil
+	}
+	timeout, e := time.ParseDuration(value)
+	if e != nil {
+		return 0, probe.NewError(e).Trace(value)
+	}
+	if timeout <= 0 {
+		return 0, errInvalidArgument().Trace(value)
+	}
+	return timeout, nil
+}
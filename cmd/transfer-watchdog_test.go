@@ -0,0 +1,113 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/cli"
+)
+
+func TestParseStallTimeout(t *testing.T) {
+	testCases := []struct {
+		args     []string
+		expected time.Duration
+		valid    bool
+	}{
+		{nil, 0, true},
+		{[]string{"--stall-timeout", "30s"}, 30 * time.Second, true},
+		{[]string{"--stall-timeout", "1m30s"}, 90 * time.Second, true},
+		{[]string{"--stall-timeout", "0s"}, 0, false},
+		{[]string{"--stall-timeout", "-5s"}, 0, false},
+		{[]string{"--stall-timeout", "30"}, 0, false},
+	}
+	for i, testCase := range testCases {
+		timeout, err := parseStallTimeout(newTestCLIContext(t, []cli.Flag{stallTimeoutFlag}, testCase.args...))
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+		if timeout != testCase.expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.expected, timeout)
+		}
+	}
+}
+
+func TestTransferWatchdog(t *testing.T) {
+	testCases := []struct {
+		// Interval between progress updates, zero if there are none.
+		interval time.Duration
+		stalled  bool
+	}{
+		{0, true},
+		{20 * time.Millisecond, false},
+	}
+	for i, testCase := range testCases {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := newTransferWatchdog(nil)
+		done := make(chan struct{})
+		go func() {
+			w.watch(ctx, cancel, 200*time.Millisecond)
+			close(done)
+		}()
+		deadline := time.After(600 * time.Millisecond)
+	progress:
+		for testCase.interval > 0 {
+			select {
+			case <-deadline:
+				break progress
+			case <-time.After(testCase.interval):
+				w.Read(make([]byte, 10))
+			}
+		}
+		if !testCase.stalled {
+			cancel()
+		}
+		<-done
+		if stalled := atomic.LoadInt32(&w.stalled) == 1; stalled != testCase.stalled {
+			t.Errorf("Test %d: expected stalled %t, got %t", i+1, testCase.stalled, stalled)
+		}
+		if testCase.stalled && ctx.Err() == nil {
+			t.Errorf("Test %d: expected the stalled transfer to be canceled", i+1)
+		}
+	}
+}
+
+func TestTransferWatchdogRewind(t *testing.T) {
+	acct := &accounter{}
+	outer := newTransferWatchdog(acct)
+	inner := newTransferWatchdog(outer)
+
+	// Progress goes through both watchdogs to the accounter.
+	for _, n := range []int{100, 50} {
+		if read, e := inner.Read(make([]byte, n)); e != nil || read != n {
+			t.Fatalf("unexpected read of %d bytes: %d, %v", n, read, e)
+		}
+	}
+	if acct.Get() != 150 || atomic.LoadInt64(&outer.written) != 150 {
+		t.Fatalf("expected 150 bytes, got %d and %d", acct.Get(), atomic.LoadInt64(&outer.written))
+	}
+
+	// An aborted attempt is taken back from every level.
+	inner.rewind()
+	if acct.Get() != 0 || atomic.LoadInt64(&outer.written) != 0 {
+		t.Fatalf("expected no bytes after rewind, got %d and %d", acct.Get(), atomic.LoadInt64(&outer.written))
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+const (
+	// Objects removed with `rm --trash` keep their key below this
+	// prefix of their bucket until they are restored or purged.
+	defaultTrashPrefix = ".trash/"
+
+	// Records when an object was moved into the trash.
+	trashedAtMetadataKey = "X-Amz-Meta-Mc-Trashed-At"
+)
+
+var trashPrefixFlag = cli.StringFlag{
+	Name:  "trash-prefix",
+	Usage: "prefix of the trash inside each bucket",
+	Value: defaultTrashPrefix,
+}
+
+var trashSubcommands = []cli.Command{
+	trashRestoreCmd,
+	trashPurgeCmd,
+}
+
+var trashCmd = cli.Command{
+	Name:            "trash",
+	Usage:           "restore or purge objects removed with 'rm --trash'",
+	Action:          mainTrash,
+	Before:          setGlobalsFromContext,
+	Flags:           globalFlags,
+	HideHelpCommand: true,
+	Subcommands:     trashSubcommands,
+}
+
+func mainTrash(ctx *cli.Context) error {
+	commandNotFound(ctx, trashSubcommands)
+	return nil
+}
+
+// trashMessage reports an object moved into, restored from or purged
+// from the trash.
+type trashMessage struct {
+	Status string `json:"status"`
+	Op     string `json:"op"`
+	Key    string `json:"key,omitempty"`
+	Trash  string `json:"trash"`
+	DryRun bool   `json:"dryRun,omitempty"`
+}
+
+// Colorized message for console printing.
+func (t trashMessage) String() string {
+	var msg string
+	if t.DryRun {
+		msg = "DRYRUN: "
+	}
+	switch t.Op {
+	case "restore":
+		msg += "Restored " + console.Colorize("Trash", "`"+t.Key+"`") + " from `" + t.Trash + "`."
+	case "purge":
+		msg += "Purged " + console.Colorize("Trash", "`"+t.Trash+"`") + "."
+	default:
+		msg += "Moved " + console.Colorize("Trash", "`"+t.Key+"`") + " to `" + t.Trash + "`."
+	}
+	return msg
+}
+
+// JSON'ified message for scripting.
+func (t trashMessage) JSON() string {
+	t.Status = "success"
+	msgBytes, e := json.MarshalIndent(t, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(msgBytes)
+}
+
+// normalizeTrashPrefix makes sure the trash prefix names a folder.
+func normalizeTrashPrefix(prefix string) (string, *probe.Error) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return "", probe.NewError(InvalidArgument{}).Trace(prefix)
+	}
+	return prefix + "/", nil
+}
+
+// splitBucketKey splits an object storage URL path into bucket and key.
+func splitBucketKey(urlPath string) (bucket, key string) {
+	bucket, key, _ = strings.Cut(strings.TrimPrefix(filepath.ToSlash(urlPath), "/"), "/")
+	return bucket, key
+}
+
+// trashPath returns the URL path an object is moved to by `rm --trash`.
+func trashPath(urlPath, prefix string) string {
+	bucket, key := splitBucketKey(urlPath)
+	return "/" + bucket + "/" + prefix + key
+}
+
+// untrashPath returns the URL path a trashed object is restored to,
+// it returns false if the path is not inside the trash.
+func untrashPath(urlPath, prefix string) (string, bool) {
+	bucket, key := splitBucketKey(urlPath)
+	if !strings.HasPrefix(key, prefix) || key == prefix {
+		return "", false
+	}
+	return "/" + bucket + "/" + strings.TrimPrefix(key, prefix), true
+}
+
+// isTrashed returns true if the URL path is inside the trash.
+func isTrashed(urlPath, prefix string) bool {
+	_, ok := untrashPath(urlPath, prefix)
+	return ok
+}
+
+// moveObject copies an object to another key of the same alias with
+// its metadata, as changed by editMetadata, and removes the original.
+func moveObject(ctx context.Context, alias string, content *ClientContent, targetPath string, editMetadata func(map[string]string), encKeyDB encryptionConfig) *probe.Error {
+	sourceURL := content.URL
+	targetURL := content.URL
+	targetURL.Path = targetPath
+
+	srcSSE := getSSE(path.Join(alias, sourceURL.Path), encKeyDB[alias])
+	tgtSSE := getSSE(path.Join(alias, targetURL.Path), encKeyDB[alias])
+
+	sourceClnt, err := newClientFromAlias(alias, sourceURL.String())
+	if err != nil {
+		return err.Trace(alias, sourceURL.String())
+	}
+	st, err := sourceClnt.Stat(ctx, StatOptions{preserve: true, sse: srcSSE})
+	if err != nil {
+		return err.Trace(alias, sourceURL.String())
+	}
+
+	metadata := make(map[string]string, len(st.Metadata)+1)
+	for k, v := range st.Metadata {
+		metadata[http.CanonicalHeaderKey(k)] = v
+	}
+	editMetadata(metadata)
+
+	opts := CopyOptions{
+		srcSSE:   srcSSE,
+		tgtSSE:   tgtSSE,
+		metadata: filterMetadata(metadata),
+	}
+	err = copySourceToTargetURL(ctx, alias, targetURL.String(), sourceURL.Path, "", "", "", "", st.Size, nil, opts)
+	if err != nil {
+		return err.Trace(alias, targetURL.String())
+	}
+
+	contentCh := make(chan *ClientContent, 1)
+	contentCh <- &ClientContent{URL: sourceURL}
+	close(contentCh)
+	for result := range sourceClnt.Remove(ctx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			return result.Err.Trace(alias, sourceURL.String())
+		}
+	}
+	return nil
+}
+
+// trashContent moves an object into the trash of its bucket for `rm --trash`.
+func trashContent(ctx context.Context, alias string, content *ClientContent, opts removeOpts) error {
+	msg := trashMessage{
+		Op:     "trash",
+		Key:    path.Join(alias, content.URL.Path),
+		Trash:  path.Join(alias, trashPath(content.URL.Path, opts.trashPrefix)),
+		DryRun: opts.isFake,
+	}
+	if !opts.isFake {
+		markTrashed := func(metadata map[string]string) {
+			metadata[trashedAtMetadataKey] = time.Now().UTC().Format(time.RFC3339)
+		}
+		err := moveObject(ctx, alias, content, trashPath(content.URL.Path, opts.trashPrefix), markTrashed, opts.encKeyDB)
+		if err != nil {
+			errorIf(err.Trace(msg.Key), "Unable to move `"+msg.Key+"` to the trash.")
+			opts.stats.Failed()
+			return exitStatus(globalErrorExitStatus)
+		}
+	}
+	opts.stats.Succeeded(content.Size)
+	printMsg(msg)
+	return nil
+}
+
+// trashClient returns a client listing the trash for an original object
+// or prefix URL, and the trash URL path matching it.
+func trashClient(urlStr, prefix string) (alias string, clnt Client, trashKey string, err *probe.Error) {
+	alias, urlStrFull, _, err := expandAlias(urlStr)
+	if err != nil {
+		return "", nil, "", err.Trace(urlStr)
+	}
+	clnt, err = newClientFromAlias(alias, urlStrFull)
+	if err != nil {
+		return "", nil, "", err.Trace(urlStr)
+	}
+	clntURL := clnt.GetURL()
+	if clntURL.Type != objectStorage {
+		return "", nil, "", probe.NewError(APINotImplemented{API: "trash", APIType: "filesystem"})
+	}
+	if bucket, _ := splitBucketKey(clntURL.Path); bucket == "" {
+		return "", nil, "", probe.NewError(BucketNameEmpty{})
+	}
+	clntURL.Path = trashPath(clntURL.Path, prefix)
+	clnt, err = newClientFromAlias(alias, clntURL.String())
+	if err != nil {
+		return "", nil, "", err.Trace(urlStr)
+	}
+	return alias, clnt, clntURL.Path, nil
+}
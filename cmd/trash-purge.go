@@ -0,0 +1,158 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"path"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/pkg/console"
+)
+
+var trashPurgeFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "force",
+		Usage: "allow the purge operation",
+	},
+	cli.StringFlag{
+		Name:  "older-than",
+		Usage: "purge objects trashed more than value in duration string ago (e.g. 7d10h31s)",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "perform a fake purge operation",
+	},
+	trashPrefixFlag,
+}
+
+var trashPurgeCmd = cli.Command{
+	Name:         "purge",
+	Usage:        "permanently remove trashed object(s)",
+	Action:       mainTrashPurge,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(trashPurgeFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET ...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  TARGET is the original location of the object(s) removed with 'rm --trash',
+  everything trashed below it is removed.
+
+EXAMPLES:
+  1. Empty the trash of the bucket 'jazz-songs'.
+     {{.Prompt}} {{.HelpName}} --force s3/jazz-songs
+
+  2. Purge objects trashed more than 30 days ago below the prefix 'louis'.
+     {{.Prompt}} {{.HelpName}} --force --older-than 30d s3/jazz-songs/louis/
+
+  3. List the trashed objects that would be purged.
+     {{.Prompt}} {{.HelpName}} --force --dry-run s3/jazz-songs
+`,
+}
+
+// purgeTrash removes the trashed objects below urlStr for good.
+func purgeTrash(ctx context.Context, urlStr string, cliCtx *cli.Context, prefix string) error {
+	isFake := cliCtx.Bool("dry-run")
+	olderThan := cliCtx.String("older-than")
+
+	alias, clnt, _, err := trashClient(urlStr, prefix)
+	if err != nil {
+		errorIf(err.Trace(urlStr), "Unable to open the trash of `"+urlStr+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	var listErr error
+	contentCh := make(chan *ClientContent)
+	resultCh := clnt.Remove(ctx, false, false, false, false, contentCh)
+	go func() {
+		defer close(contentCh)
+		for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+			if content.Err != nil {
+				errorIf(content.Err.Trace(urlStr), "Unable to list the trash of `"+urlStr+"`.")
+				listErr = exitStatus(globalErrorExitStatus)
+				return
+			}
+			if content.Type.IsDir() || !isTrashed(content.URL.Path, prefix) {
+				continue
+			}
+			if olderThan != "" && isOlder(content.Time, olderThan) {
+				continue
+			}
+			if isFake {
+				printMsg(trashMessage{Op: "purge", Trash: path.Join(alias, content.URL.Path), DryRun: true})
+				continue
+			}
+			select {
+			case contentCh <- content:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var rerr error
+	for result := range resultCh {
+		trash := path.Join(alias, result.BucketName, result.ObjectName)
+		if result.Err != nil {
+			errorIf(result.Err.Trace(trash), "Unable to purge `"+trash+"`.")
+			rerr = exitStatus(globalErrorExitStatus)
+			continue
+		}
+		printMsg(trashMessage{Op: "purge", Trash: trash})
+	}
+	if rerr == nil {
+		rerr = listErr
+	}
+	return rerr
+}
+
+// main for trash purge command.
+func mainTrashPurge(cliCtx *cli.Context) error {
+	ctx, cancelPurge := context.WithCancel(globalContext)
+	defer cancelPurge()
+
+	if !cliCtx.Args().Present() {
+		showCommandHelpAndExit(cliCtx, globalErrorExitStatus)
+	}
+	if !cliCtx.Bool("force") {
+		fatalIf(errDummy().Trace(),
+			"Purging requires --force flag. This operation is *IRREVERSIBLE*. Please review carefully before performing this *DANGEROUS* operation.")
+	}
+
+	prefix, err := normalizeTrashPrefix(cliCtx.String("trash-prefix"))
+	fatalIf(err.Trace(cliCtx.String("trash-prefix")), "Invalid --trash-prefix.")
+
+	console.SetColor("Trash", color.New(color.FgRed, color.Bold))
+
+	var rerr error
+	for _, urlStr := range cliCtx.Args() {
+		if e := purgeTrash(ctx, urlStr, cliCtx, prefix); rerr == nil {
+			rerr = e
+		}
+	}
+	return rerr
+}
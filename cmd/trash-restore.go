@@ -0,0 +1,181 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"path"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var trashRestoreFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "recursive, r",
+		Usage: "restore all trashed objects below the prefix",
+	},
+	cli.BoolFlag{
+		Name:  "overwrite",
+		Usage: "replace objects recreated since they were trashed",
+	},
+	cli.StringFlag{
+		Name:  "newer-than",
+		Usage: "restore objects trashed less than value in duration string ago (e.g. 7d10h31s)",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "perform a fake restore operation",
+	},
+	trashPrefixFlag,
+}
+
+var trashRestoreCmd = cli.Command{
+	Name:         "restore",
+	Usage:        "move trashed object(s) back to their original location",
+	Action:       mainTrashRestore,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(append(trashRestoreFlags, ioFlags...), globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] TARGET [TARGET ...]
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  TARGET is the original location of the object(s) removed with 'rm --trash'.
+
+EXAMPLES:
+  1. Restore an object removed with 'rm --trash'.
+     {{.Prompt}} {{.HelpName}} s3/jazz-songs/louis/summertime.mp3
+
+  2. Restore all objects trashed below the prefix 'louis' during the last hour.
+     {{.Prompt}} {{.HelpName}} --recursive --newer-than 1h s3/jazz-songs/louis/
+
+  3. Restore all trashed objects of a bucket, replacing objects recreated in the meantime.
+     {{.Prompt}} {{.HelpName}} --recursive --overwrite s3/jazz-songs
+`,
+}
+
+// restoreFromTrash moves the trashed objects matching urlStr back to
+// their original keys.
+func restoreFromTrash(ctx context.Context, urlStr string, cliCtx *cli.Context, prefix string, encKeyDB encryptionConfig) error {
+	isRecursive := cliCtx.Bool("recursive")
+	isOverwrite := cliCtx.Bool("overwrite")
+	isFake := cliCtx.Bool("dry-run")
+	newerThan := cliCtx.String("newer-than")
+
+	alias, clnt, trashKey, err := trashClient(urlStr, prefix)
+	if err != nil {
+		errorIf(err.Trace(urlStr), "Unable to open the trash of `"+urlStr+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+
+	var rerr error
+	found := false
+	for content := range clnt.List(ctx, ListOptions{Recursive: isRecursive, ShowDir: DirNone}) {
+		if content.Err != nil {
+			errorIf(content.Err.Trace(urlStr), "Unable to list the trash of `"+urlStr+"`.")
+			return exitStatus(globalErrorExitStatus)
+		}
+		if content.Type.IsDir() || (!isRecursive && content.URL.Path != trashKey) {
+			continue
+		}
+		found = true
+		if newerThan != "" && isNewer(content.Time, newerThan) {
+			continue
+		}
+		originalPath, ok := untrashPath(content.URL.Path, prefix)
+		if !ok {
+			continue
+		}
+
+		msg := trashMessage{
+			Op:     "restore",
+			Key:    path.Join(alias, originalPath),
+			Trash:  path.Join(alias, content.URL.Path),
+			DryRun: isFake,
+		}
+		if !isOverwrite {
+			originalURL := content.URL
+			originalURL.Path = originalPath
+			originalClnt, err := newClientFromAlias(alias, originalURL.String())
+			if err != nil {
+				errorIf(err.Trace(msg.Key), "Unable to restore `"+msg.Trash+"`.")
+				rerr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+			sse := getSSE(msg.Key, encKeyDB[alias])
+			if _, err := originalClnt.Stat(ctx, StatOptions{sse: sse}); err == nil {
+				errorIf(probe.NewError(ObjectAlreadyExists{Object: msg.Key}).Trace(msg.Trash),
+					"Unable to restore `"+msg.Trash+"`, retry with --overwrite to replace the existing object.")
+				rerr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+		}
+		if !isFake {
+			unmarkTrashed := func(metadata map[string]string) {
+				delete(metadata, trashedAtMetadataKey)
+			}
+			if err := moveObject(ctx, alias, content, originalPath, unmarkTrashed, encKeyDB); err != nil {
+				errorIf(err.Trace(msg.Trash), "Unable to restore `"+msg.Trash+"`.")
+				rerr = exitStatus(globalErrorExitStatus)
+				continue
+			}
+		}
+		printMsg(msg)
+	}
+
+	if !found {
+		errorIf(errDummy().Trace(urlStr), "No trashed object found for `"+urlStr+"`.")
+		return exitStatus(globalErrorExitStatus)
+	}
+	return rerr
+}
+
+// main for trash restore command.
+func mainTrashRestore(cliCtx *cli.Context) error {
+	ctx, cancelRestore := context.WithCancel(globalContext)
+	defer cancelRestore()
+
+	if !cliCtx.Args().Present() {
+		showCommandHelpAndExit(cliCtx, globalErrorExitStatus)
+	}
+
+	encKeyDB, err := getEncKeys(cliCtx)
+	fatalIf(err, "Unable to parse encryption keys.")
+
+	prefix, err := normalizeTrashPrefix(cliCtx.String("trash-prefix"))
+	fatalIf(err.Trace(cliCtx.String("trash-prefix")), "Invalid --trash-prefix.")
+
+	console.SetColor("Trash", color.New(color.FgGreen, color.Bold))
+
+	var rerr error
+	for _, urlStr := range cliCtx.Args() {
+		if e := restoreFromTrash(ctx, urlStr, cliCtx, prefix, encKeyDB); rerr == nil {
+			rerr = e
+		}
+	}
+	return rerr
+}
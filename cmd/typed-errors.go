@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/minio/mc/pkg/probe"
 )
@@ -104,6 +105,13 @@ var errTargetNotFound = func(URL string) *probe.Error {
 	return probe.NewError(targetNotFoundErr(errors.New(msg))).Untrace()
 }
 
+type targetExistsErr error
+
+var errTargetExists = func(URL string) *probe.Error {
+	msg := "Target `" + URL + "` already exists."
+	return probe.NewError(targetExistsErr(errors.New(msg))).Untrace()
+}
+
 type overwriteNotAllowedErr struct {
 	error
 }
@@ -126,3 +134,10 @@ var errConflictSSE = func(sseServer, sseKeys string) *probe.Error {
 	err := fmt.Errorf("SSE alias '%s' overlaps with SSE-C aliases '%s'", sseServer, sseKeys)
 	return probe.NewError(conflictSSEErr(err)).Untrace()
 }
+
+type transferStalledErr error
+
+var errTransferStalled = func(URL string, timeout time.Duration) *probe.Error {
+	msg := fmt.Sprintf("Transfer of `%s` made no progress for %s.", URL, timeout)
+	return probe.NewError(transferStalledErr(errors.New(msg))).Untrace()
+}
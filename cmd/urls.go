@@ -18,22 +18,44 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/minio/mc/pkg/probe"
 )
 
 // URLs contains source and target urls
 type URLs struct {
-	SourceAlias      string
-	SourceContent    *ClientContent
-	TargetAlias      string
-	TargetContent    *ClientContent
-	TotalCount       int64
-	TotalSize        int64
-	MD5              bool
-	DisableMultipart bool
-	encKeyDB         map[string][]prefixSSEPair
-	Error            *probe.Error `json:"-"`
-	ErrorCond        differType   `json:"-"`
+	SourceAlias       string
+	SourceContent     *ClientContent
+	TargetAlias       string
+	TargetContent     *ClientContent
+	TotalCount        int64
+	TotalSize         int64
+	MD5               bool
+	DisableMultipart  bool
+	Checksum          string
+	ChecksumValue     string
+	Verify            bool
+	LambdaArn         string
+	Conditions        GetConditions
+	Sparse            bool
+	DownloadParts     int
+	AttrPreserve      bool
+	Compress          string
+	CSEKey            string
+	ServerSide        bool
+	PreserveRetention bool
+	PreserveLegalHold bool
+	StallTimeout      time.Duration
+	SettleDuration    time.Duration
+	Retries           int
+	RetryDelay        time.Duration
+	NoClobber         bool
+	Compare           string
+	BackupSuffix      string
+	encKeyDB          encryptionConfig
+	Error             *probe.Error `json:"-"`
+	ErrorCond         differType   `json:"-"`
 }
 
 // WithError sets the error and returns object
@@ -19,9 +19,15 @@ package cmd
 
 import (
 	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
 	"math"
 	"math/rand"
 	"net"
@@ -138,11 +144,15 @@ func NewS3Config(urlStr string, aliasCfg *aliasConfigV10) *Config {
 	s3Config.AppName = filepath.Base(os.Args[0])
 	s3Config.AppVersion = ReleaseTag
 	s3Config.Debug = globalDebug
+	s3Config.DebugLite = globalDebugLite
 	s3Config.Insecure = globalInsecure
 	s3Config.ConnReadDeadline = globalConnReadDeadline
 	s3Config.ConnWriteDeadline = globalConnWriteDeadline
 	s3Config.UploadLimit = int64(globalLimitUpload)
 	s3Config.DownloadLimit = int64(globalLimitDownload)
+	s3Config.OpsLimit = int64(globalOpsLimit)
+	s3Config.CustomHeaders = globalCustomHeaders
+	s3Config.CustomQuery = globalCustomQuery
 
 	s3Config.HostURL = urlStr
 	if aliasCfg != nil {
@@ -151,6 +161,15 @@ func NewS3Config(urlStr string, aliasCfg *aliasConfigV10) *Config {
 		s3Config.SessionToken = aliasCfg.SessionToken
 		s3Config.Signature = aliasCfg.API
 		s3Config.Lookup = getLookupType(aliasCfg.Path)
+		s3Config.RequestPayer = aliasCfg.RequestPayer
+
+		var e error
+		s3Config.TLS, e = parseAliasTLSConfig(aliasCfg.TLS)
+		fatalIf(probe.NewError(e), "Invalid TLS settings of alias with URL `"+aliasCfg.URL+"`.")
+	}
+	// Request payer set on command line overrides the alias default.
+	if globalRequestPayer != "" {
+		s3Config.RequestPayer = globalRequestPayer
 	}
 	return s3Config
 }
@@ -174,10 +193,9 @@ func isOlder(ti time.Time, olderRef string) bool {
 	if olderRef == "" {
 		return false
 	}
-	objectAge := time.Since(ti)
-	olderThan, e := ParseDuration(olderRef)
+	olderThan, e := parseTimeRef(olderRef)
 	fatalIf(probe.NewError(e), "Unable to parse olderThan=`"+olderRef+"`.")
-	return objectAge < time.Duration(olderThan)
+	return ti.After(olderThan)
 }
 
 // isNewer returns true if the passed object is newer than newerRef
@@ -186,10 +204,22 @@ func isNewer(ti time.Time, newerRef string) bool {
 		return false
 	}
 
-	objectAge := time.Since(ti)
-	newerThan, e := ParseDuration(newerRef)
+	newerThan, e := parseTimeRef(newerRef)
 	fatalIf(probe.NewError(e), "Unable to parse newerThan=`"+newerRef+"`.")
-	return objectAge >= time.Duration(newerThan)
+	return !ti.After(newerThan)
+}
+
+// parseTimeRef returns the time an --older-than or --newer-than value
+// refers to, a duration before now or the timestamp of a reference file.
+func parseTimeRef(ref string) (time.Time, error) {
+	if t, e := time.Parse(time.RFC3339Nano, ref); e == nil {
+		return t, nil
+	}
+	d, e := ParseDuration(ref)
+	if e != nil {
+		return time.Time{}, e
+	}
+	return time.Now().Add(-time.Duration(d)), nil
 }
 
 // getLookupType returns the minio.BucketLookupType for lookup
@@ -211,8 +241,14 @@ type prefixSSEPair struct {
 	SSE    encrypt.ServerSide
 }
 
-// parse and validate encryption keys entered on command line
-func parseAndValidateEncryptionKeys(sseKeys, sse string) (encMap map[string][]prefixSSEPair, err *probe.Error) {
+// encryptionConfig associates the prefixes of each alias with their
+// server-side encryption, SSE-C, SSE-S3 or SSE-KMS, longest prefixes
+// first.
+type encryptionConfig map[string][]prefixSSEPair
+
+// parse and validate encryption keys entered on command line, SSE-C
+// keys, SSE-S3 prefixes and SSE-KMS key IDs.
+func parseAndValidateEncryptionKeys(sseKeys, sse, kms string) (encMap encryptionConfig, err *probe.Error) {
 	encMap, err = parseEncryptionKeys(sseKeys)
 	if err != nil {
 		return nil, err
@@ -226,20 +262,69 @@ func parseAndValidateEncryptionKeys(sseKeys, sse string) (encMap map[string][]pr
 			})
 		}
 	}
+	kmsMap, err := parseEncryptionKMS(kms)
+	if err != nil {
+		return nil, err
+	}
+	for alias, ps := range kmsMap {
+		encMap[alias] = append(encMap[alias], ps...)
+	}
 	for alias, ps := range encMap {
 		if hostCfg := mustGetHostConfig(alias); hostCfg == nil {
 			for _, p := range ps {
 				return nil, probe.NewError(errors.New("SSE prefix " + p.Prefix + " has invalid alias"))
 			}
 		}
+		if err = checkEncryptionPrefixes(ps); err != nil {
+			return nil, err
+		}
+		sort.Stable(byPrefixLength(ps))
+	}
+	return encMap, nil
+}
+
+// checkEncryptionPrefixes returns an error if a prefix is given more
+// than one server-side encryption.
+func checkEncryptionPrefixes(ps []prefixSSEPair) *probe.Error {
+	seen := make(map[string]bool, len(ps))
+	for _, p := range ps {
+		if seen[p.Prefix] {
+			return probe.NewError(errors.New("SSE prefix " + p.Prefix + " is given more than one encryption"))
+		}
+		seen[p.Prefix] = true
+	}
+	return nil
+}
+
+// parse list of comma separated alias/prefix=keyid values entered on
+// command line, encrypted with SSE-KMS with the key of keyid.
+func parseEncryptionKMS(kms string) (encMap encryptionConfig, err *probe.Error) {
+	encMap = make(encryptionConfig)
+	if kms == "" {
+		return encMap, nil
+	}
+	for _, pair := range strings.Split(kms, ",") {
+		prefix, keyID, ok := strings.Cut(pair, "=")
+		if !ok || prefix == "" || keyID == "" {
+			return nil, probe.NewError(errors.New("SSE-KMS prefix should be of the form prefix1=keyid1,... "))
+		}
+		sse, e := encrypt.NewSSEKMS(keyID, nil)
+		if e != nil {
+			return nil, probe.NewError(e)
+		}
+		alias, _ := url2Alias(prefix)
+		encMap[alias] = append(encMap[alias], prefixSSEPair{
+			Prefix: prefix,
+			SSE:    sse,
+		})
 	}
 	return encMap, nil
 }
 
 // parse list of comma separated alias/prefix=sse key values entered on command line and
 // construct a map of alias to prefix and sse pairs.
-func parseEncryptionKeys(sseKeys string) (encMap map[string][]prefixSSEPair, err *probe.Error) {
-	encMap = make(map[string][]prefixSSEPair)
+func parseEncryptionKeys(sseKeys string) (encMap encryptionConfig, err *probe.Error) {
+	encMap = make(encryptionConfig)
 	if sseKeys == "" {
 		return
 	}
@@ -461,3 +546,82 @@ func getPrometheusToken(hostConfig *aliasConfigV10) (string, error) {
 	}
 	return token, nil
 }
+
+// shellQuoteRegex - matches characters interpreted specially by a POSIX shell.
+var shellQuoteRegex = regexp.MustCompile("[&;#$`\\\\ \t\n<>()|'\"*?\\[\\]!{}~]")
+
+// shellQuote single quotes the input if it has characters interpreted
+// specially by a POSIX shell, closing and reopening the quotes around
+// embedded single quotes.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if !shellQuoteRegex.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteOutput shell quotes keys and paths emitted on the console, unless
+// quoting was disabled with --raw or the output is not meant for a shell.
+func quoteOutput(s string) string {
+	if globalRawOutput || globalPrint0 || globalJSON {
+		return s
+	}
+	return shellQuote(s)
+}
+
+// crc64NVMETable is the table of the CRC64NVME upload checksum.
+var crc64NVMETable = crc64.MakeTable(0x9a6c9329ac4bc9b5)
+
+// parseChecksumAlgorithm validates an upload checksum algorithm and
+// returns its canonical name.
+func parseChecksumAlgorithm(algorithm string) (string, *probe.Error) {
+	switch strings.ToUpper(algorithm) {
+	case "CRC32C":
+		return "CRC32C", nil
+	case "SHA1":
+		return "SHA1", nil
+	case "SHA256":
+		return "SHA256", nil
+	case "CRC64NVME":
+		return "CRC64NVME", nil
+	}
+	return "", probe.NewError(fmt.Errorf("unsupported checksum algorithm `%s`, valid values are CRC32C, SHA1, SHA256 and CRC64NVME", algorithm))
+}
+
+// newChecksumHasher returns a hash for the upload checksum algorithm.
+func newChecksumHasher(algorithm string) hash.Hash {
+	switch algorithm {
+	case "SHA1":
+		return sha1.New()
+	case "SHA256":
+		return sha256.New()
+	case "CRC64NVME":
+		return crc64.New(crc64NVMETable)
+	}
+	return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+}
+
+// checksumHeaderSuffix returns the x-amz-checksum-* suffix of an algorithm.
+func checksumHeaderSuffix(algorithm string) string {
+	switch algorithm {
+	case "SHA1":
+		return "Sha1"
+	case "SHA256":
+		return "Sha256"
+	case "CRC64NVME":
+		return "Crc64nvme"
+	}
+	return "Crc32c"
+}
+
+// limitReadCloser returns a ReadCloser reading at most n bytes of rc,
+// closing rc once closed.
+func limitReadCloser(rc io.ReadCloser, n int64) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, n), rc}
+}
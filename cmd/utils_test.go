@@ -18,8 +18,14 @@
 package cmd
 
 import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/minio/minio-go/v7/pkg/encrypt"
 )
@@ -43,12 +49,12 @@ func TestParseEncryptionKeys(t *testing.T) {
 	}
 	testCases := []struct {
 		encryptionKey  string
-		expectedEncMap map[string][]prefixSSEPair
+		expectedEncMap encryptionConfig
 		success        bool
 	}{
 		{
 			encryptionKey: "myminio1/test2=32byteslongsecretkeymustbegiven2",
-			expectedEncMap: map[string][]prefixSSEPair{"myminio1": {{
+			expectedEncMap: encryptionConfig{"myminio1": {{
 				Prefix: "myminio1/test2",
 				SSE:    sseKey1,
 			}}},
@@ -61,7 +67,7 @@ func TestParseEncryptionKeys(t *testing.T) {
 		},
 		{
 			encryptionKey: "myminio1/test2=32byteslongsecretkey,ustbegiven1",
-			expectedEncMap: map[string][]prefixSSEPair{"myminio1": {{
+			expectedEncMap: encryptionConfig{"myminio1": {{
 				Prefix: "myminio1/test2",
 				SSE:    sseCommaKey1,
 			}}},
@@ -69,7 +75,7 @@ func TestParseEncryptionKeys(t *testing.T) {
 		},
 		{
 			encryptionKey: "myminio1/test2=32byteslongsecret   mustbegiven1",
-			expectedEncMap: map[string][]prefixSSEPair{"myminio1": {{
+			expectedEncMap: encryptionConfig{"myminio1": {{
 				Prefix: "myminio1/test2",
 				SSE:    sseSpaceKey1,
 			}}},
@@ -77,7 +83,7 @@ func TestParseEncryptionKeys(t *testing.T) {
 		},
 		{
 			encryptionKey: "myminio1/test2=32byteslongsecretkeymustbegiven2,myminio1/test1/a=32byteslongsecretkeymustbegiven1",
-			expectedEncMap: map[string][]prefixSSEPair{"myminio1": {{
+			expectedEncMap: encryptionConfig{"myminio1": {{
 				Prefix: "myminio1/test1/a",
 				SSE:    sseKey2,
 			}, {
@@ -101,6 +107,66 @@ func TestParseEncryptionKeys(t *testing.T) {
 	}
 }
 
+func TestParseEncryptionKMS(t *testing.T) {
+	testCases := []struct {
+		kms      string
+		expected map[string][]string
+		success  bool
+	}{
+		{"", map[string][]string{}, true},
+		{"myminio1/bucket=key1", map[string][]string{"myminio1": {"myminio1/bucket"}}, true},
+		{
+			"myminio1/bucket=key1,myminio2/bucket/dir=key2,myminio1/other=key3",
+			map[string][]string{"myminio1": {"myminio1/bucket", "myminio1/other"}, "myminio2": {"myminio2/bucket/dir"}},
+			true,
+		},
+		{"myminio1/bucket", nil, false},
+		{"myminio1/bucket=", nil, false},
+		{"=key1", nil, false},
+		{"myminio1/bucket=key1,", nil, false},
+	}
+	for i, testCase := range testCases {
+		encMap, err := parseEncryptionKMS(testCase.kms)
+		if err != nil && testCase.success {
+			t.Fatalf("Test %d: Expected success, got %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Fatalf("Test %d: Expected error, got success", i+1)
+		}
+		if !testCase.success {
+			continue
+		}
+		prefixes := make(map[string][]string)
+		for alias, ps := range encMap {
+			prefixes[alias] = []string{}
+			for _, p := range ps {
+				if p.SSE.Type() != encrypt.KMS {
+					t.Fatalf("Test %d: Expected SSE-KMS, got %s", i+1, p.SSE.Type())
+				}
+				prefixes[alias] = append(prefixes[alias], p.Prefix)
+			}
+		}
+		if !reflect.DeepEqual(prefixes, testCase.expected) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, prefixes)
+		}
+	}
+}
+
+func TestCheckEncryptionPrefixes(t *testing.T) {
+	sseKey, err := encrypt.NewSSEC([]byte("32byteslongsecretkeymustbegiven2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := []prefixSSEPair{{Prefix: "myminio1/bucket/dir", SSE: sseKey}, {Prefix: "myminio1/bucket", SSE: encrypt.NewSSE()}}
+	if err := checkEncryptionPrefixes(ps); err != nil {
+		t.Fatal(err)
+	}
+	ps = append(ps, prefixSSEPair{Prefix: "myminio1/bucket", SSE: sseKey})
+	if err := checkEncryptionPrefixes(ps); err == nil {
+		t.Fatal("Expected an error for a prefix given two encryptions")
+	}
+}
+
 func TestParseAttribute(t *testing.T) {
 	metaDataCases := []struct {
 		input  string
@@ -160,3 +226,124 @@ func TestParseAttribute(t *testing.T) {
 
 	}
 }
+
+func TestOutputTargetWebhook(t *testing.T) {
+	var body, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body, contentType = string(data), r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	now := time.Date(2023, 1, 2, 15, 4, 5, 0, time.UTC)
+	target, e := newOutputTarget(server.URL+"/runs/{date}/{time}", now)
+	if e != nil {
+		t.Fatal(e)
+	}
+	if want := server.URL + "/runs/2023-01-02/150405"; target.target != want {
+		t.Fatalf("expected target %s, got %s", want, target.target)
+	}
+
+	target.record("{\n \"status\": \"success\",\n \"key\": \"a\"\n}")
+	target.record(`{"status":"success","key":"b"}`)
+	if err := target.deliver(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"status\":\"success\",\"key\":\"a\"}\n{\"status\":\"success\",\"key\":\"b\"}\n"; body != want {
+		t.Fatalf("expected body %q, got %q", want, body)
+	}
+	if contentType != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %s", contentType)
+	}
+}
+
+func TestIsOlderNewerTimeRef(t *testing.T) {
+	ref := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	refStr := ref.Format(time.RFC3339Nano)
+	testCases := []struct {
+		modTime          time.Time
+		isOlder, isNewer bool
+	}{
+		{ref.Add(-time.Second), false, true},
+		{ref, false, true},
+		{ref.Add(time.Second), true, false},
+	}
+	for i, testCase := range testCases {
+		if got := isOlder(testCase.modTime, refStr); got != testCase.isOlder {
+			t.Fatalf("Test %d: expected isOlder %t, got %t", i+1, testCase.isOlder, got)
+		}
+		if got := isNewer(testCase.modTime, refStr); got != testCase.isNewer {
+			t.Fatalf("Test %d: expected isNewer %t, got %t", i+1, testCase.isNewer, got)
+		}
+	}
+
+	// Durations are relative to now.
+	if !isOlder(time.Now().Add(-time.Hour), "2h") || isOlder(time.Now().Add(-3*time.Hour), "2h") {
+		t.Fatal("unexpected isOlder for a duration")
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	testCases := []struct {
+		input, expected string
+	}{
+		{"photos/2023/a.jpg", "photos/2023/a.jpg"},
+		{"", "''"},
+		{"my dir/a.jpg", "'my dir/a.jpg'"},
+		{"it's", `'it'\''s'`},
+		{"line\nbreak", "'line\nbreak'"},
+		{"a*b?[c]", "'a*b?[c]'"},
+		{"https://play.min.io/bucket/a.jpg?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Expires=604800", "'https://play.min.io/bucket/a.jpg?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Expires=604800'"},
+	}
+	for _, testCase := range testCases {
+		if got := shellQuote(testCase.input); got != testCase.expected {
+			t.Fatalf("%q: expected %q, got %q", testCase.input, testCase.expected, got)
+		}
+	}
+}
+
+func TestShareMessageURLNotQuoted(t *testing.T) {
+	shareURL := "https://play.min.io/bucket/a%20b.jpg?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Expires=604800"
+	msg := shareMesssage{ObjectURL: "play/bucket/a b.jpg", ShareURL: shareURL}.String()
+	if !strings.Contains(msg, "Share: "+shareURL+"\n") || !strings.Contains(msg, "URL: play/bucket/a b.jpg\n") {
+		t.Fatalf("expected share URLs to be printed verbatim, got %q", msg)
+	}
+}
+
+func TestChecksumAlgorithms(t *testing.T) {
+	testCases := []struct {
+		algorithm, canonical, suffix string
+		// Checksum of "123456789".
+		expected string
+	}{
+		{"crc32c", "CRC32C", "Crc32c", "4waSgw=="},
+		{"sha1", "SHA1", "Sha1", "98O8HYCOBHMq32eZZczDTKeuNEE="},
+		{"Sha256", "SHA256", "Sha256", "FeKw08M4keuw8e9gnsQZQgwg4yDOlMZfvIwzEkSOsiU="},
+		{"CRC64NVME", "CRC64NVME", "Crc64nvme", "rosUhgp5mIg="},
+		{"md5", "", "", ""},
+		{"crc32", "", "", ""},
+	}
+	for _, testCase := range testCases {
+		algorithm, err := parseChecksumAlgorithm(testCase.algorithm)
+		if testCase.canonical == "" {
+			if err == nil {
+				t.Fatalf("%s: expected an error", testCase.algorithm)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.algorithm, err)
+		}
+		if algorithm != testCase.canonical {
+			t.Fatalf("%s: expected %s, got %s", testCase.algorithm, testCase.canonical, algorithm)
+		}
+		if suffix := checksumHeaderSuffix(algorithm); suffix != testCase.suffix {
+			t.Fatalf("%s: expected header suffix %s, got %s", algorithm, testCase.suffix, suffix)
+		}
+		hasher := newChecksumHasher(algorithm)
+		io.WriteString(hasher, "123456789")
+		if got := base64.StdEncoding.EncodeToString(hasher.Sum(nil)); got != testCase.expected {
+			t.Fatalf("%s: expected checksum %s, got %s", algorithm, testCase.expected, got)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	humanize "github.com/dustin/go-humanize"
 	"github.com/fatih/color"
@@ -58,7 +59,7 @@ var watchCmd = cli.Command{
 	Action:       mainWatch,
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
-	Flags:        append(watchFlags, globalFlags...),
+	Flags:        append(append(watchFlags, filterFlag), globalFlags...),
 	CustomHelpTemplate: `NAME:
   {{.HelpName}} - {{.Usage}}
 
@@ -86,6 +87,9 @@ EXAMPLES:
 
   6. Watch for events on local directory.
      {{.Prompt}} {{.HelpName}} /usr/share
+
+  7. Watch for uploads of log files larger than 100MiB on MinIO server.
+     {{.Prompt}} {{.HelpName}} --events put --filter 'name ~ "*.log" && size > 100MiB' play/testbucket
 `,
 }
 
@@ -94,6 +98,21 @@ func checkWatchSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		showCommandHelpAndExit(ctx, 1) // last argument is exit code
 	}
+	_, err := parseContentFilter(ctx.String("filter"))
+	fatalIf(err.Trace(ctx.Args()...), "Unable to parse --filter.")
+}
+
+// watchEventContent returns the object of an event for matching with --filter.
+func watchEventContent(event EventInfo) *ClientContent {
+	content := &ClientContent{
+		URL:  *newClientURL(event.Path),
+		Size: event.Size,
+		Time: time.Now(),
+	}
+	if t, e := time.Parse(time.RFC3339Nano, event.Time); e == nil {
+		content.Time = t
+	}
+	return content
 }
 
 // watchMessage container to hold one event notification
@@ -146,6 +165,7 @@ func mainWatch(cliCtx *cli.Context) error {
 	suffix := cliCtx.String("suffix")
 	events := strings.Split(cliCtx.String("events"), ",")
 	recursive := cliCtx.Bool("recursive")
+	filter := mustParseContentFilter(cliCtx)
 
 	s3Client, pErr := newClient(path)
 	if pErr != nil {
@@ -188,6 +208,9 @@ func mainWatch(cliCtx *cli.Context) error {
 					return
 				}
 				for _, event := range events {
+					if !filter.Match(watchEventContent(event)) {
+						continue
+					}
 					msg := watchMessage{}
 					msg.Event.Path = event.Path
 					msg.Event.Size = event.Size
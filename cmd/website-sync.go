@@ -0,0 +1,501 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/klauspost/compress/gzip"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+	"github.com/minio/pkg/mimedb"
+)
+
+var websiteSyncFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "cache-control",
+		Usage: "Cache-Control header of assets",
+		Value: "public, max-age=3600",
+	},
+	cli.StringFlag{
+		Name:  "html-cache-control",
+		Usage: "Cache-Control header of HTML pages",
+		Value: "no-cache",
+	},
+	cli.StringFlag{
+		Name:  "header-map",
+		Usage: "file mapping object name patterns to headers, overrides the defaults above",
+	},
+	cli.StringFlag{
+		Name:  "precompress",
+		Usage: "also upload pre-compressed variants of text assets, only 'gzip' is supported",
+	},
+	cli.BoolFlag{
+		Name:  "remove",
+		Usage: "remove objects whose files were removed from the source",
+	},
+	cli.StringFlag{
+		Name:  "manifest",
+		Usage: "write the paths of all changed objects to this JSON file, e.g. for CDN invalidation",
+	},
+	cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "show what would be changed without changing anything",
+	},
+}
+
+var websiteSyncCmd = cli.Command{
+	Name:         "sync",
+	Usage:        "sync a local folder to a bucket serving a static website",
+	Action:       mainWebsiteSync,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(websiteSyncFlags, globalFlags...),
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS] SOURCE TARGET
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}
+DESCRIPTION:
+  Uploads every file of the local SOURCE folder whose content differs from the object
+  at TARGET, with a Content-Type derived from its extension and a Cache-Control header.
+  HTML pages get --html-cache-control so that new deployments are picked up at once,
+  all other assets get --cache-control.
+
+  With --precompress gzip, text assets such as HTML, CSS, JavaScript, JSON and SVG are
+  also uploaded gzip compressed as '<name>.gz', with the Content-Type of the original and
+  'Content-Encoding: gzip', for web servers and CDNs serving pre-compressed variants.
+
+  The manifest lists the paths of all uploaded and removed objects, relative to TARGET
+  and starting with '/', as expected by most CDN invalidation APIs.
+
+EXAMPLES:
+  1. Deploy a static site to a bucket.
+     {{.Prompt}} {{.HelpName}} public/ myminio/www
+
+  2. Deploy a static site with gzip variants, removing pages which were deleted.
+     {{.Prompt}} {{.HelpName}} --precompress gzip --remove public/ myminio/www
+
+  3. Deploy a static site and write the paths to invalidate on the CDN.
+     {{.Prompt}} {{.HelpName}} --remove --manifest invalidate.json public/ s3/example.com
+
+  4. Show what a deployment would change.
+     {{.Prompt}} {{.HelpName}} --remove --dry-run public/ myminio/www
+`,
+}
+
+// websiteSyncMessage container for a changed object.
+type websiteSyncMessage struct {
+	Status          string `json:"status"`
+	Action          string `json:"action"`
+	Key             string `json:"key"`
+	Size            int64  `json:"size,omitempty"`
+	ContentType     string `json:"contentType,omitempty"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	dryRun          bool
+}
+
+// String colorized website sync message.
+func (w websiteSyncMessage) String() string {
+	prefix := ""
+	if w.dryRun {
+		prefix = "Would "
+	}
+	switch w.Action {
+	case "remove":
+		return console.Colorize("WebsiteRemove", fmt.Sprintf("%sremove `%s`", prefix, w.Key))
+	default:
+		msg := fmt.Sprintf("%supload `%s` (%s", prefix, w.Key, w.ContentType)
+		if w.ContentEncoding != "" {
+			msg += ", " + w.ContentEncoding
+		}
+		return console.Colorize("WebsiteUpload", msg+")")
+	}
+}
+
+// JSON jsonified website sync message.
+func (w websiteSyncMessage) JSON() string {
+	w.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(w, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// websiteSyncSummaryMessage container for the summary of a sync.
+type websiteSyncSummaryMessage struct {
+	Status   string `json:"status"`
+	Uploaded int    `json:"uploaded"`
+	Skipped  int    `json:"skipped"`
+	Removed  int    `json:"removed"`
+	Manifest string `json:"manifest,omitempty"`
+}
+
+// String colorized website sync summary message.
+func (w websiteSyncSummaryMessage) String() string {
+	msg := fmt.Sprintf("Uploaded %d, unchanged %d, removed %d objects.", w.Uploaded, w.Skipped, w.Removed)
+	if w.Manifest != "" {
+		msg += " Invalidation manifest written to `" + w.Manifest + "`."
+	}
+	return console.Colorize("WebsiteSummary", msg)
+}
+
+// JSON jsonified website sync summary message.
+func (w websiteSyncSummaryMessage) JSON() string {
+	w.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(w, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+
+	return string(jsonMessageBytes)
+}
+
+// websiteManifest is the invalidation manifest written by --manifest.
+type websiteManifest struct {
+	Time  time.Time `json:"time"`
+	Paths []string  `json:"paths"`
+}
+
+// websiteSyncOpts - options of a website sync.
+type websiteSyncOpts struct {
+	cacheControl     string
+	htmlCacheControl string
+	headers          headerMap
+	gzip             bool
+	remove           bool
+	dryRun           bool
+}
+
+// websiteObject is an object to upload.
+type websiteObject struct {
+	key      string
+	reader   io.ReadSeeker
+	size     int64
+	md5      string
+	metadata map[string]string
+}
+
+// isCompressibleContentType returns true for text based content which
+// benefits from being served compressed.
+func isCompressibleContentType(contentType string) bool {
+	contentType, _, _ = strings.Cut(contentType, ";")
+	if strings.HasPrefix(contentType, "text/") {
+		return true
+	}
+	switch contentType {
+	case "application/javascript", "application/x-javascript", "application/json",
+		"application/manifest+json", "application/xml", "application/wasm", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// websiteHeaders returns the headers of a file of the website.
+func websiteHeaders(key string, opts websiteSyncOpts) map[string]string {
+	contentType := mimedb.TypeByExtension(filepath.Ext(key))
+	metadata := map[string]string{
+		"Content-Type":  contentType,
+		"Cache-Control": opts.cacheControl,
+	}
+	if strings.HasPrefix(contentType, "text/html") {
+		metadata["Cache-Control"] = opts.htmlCacheControl
+	}
+	for k, v := range opts.headers.headersFor(key) {
+		metadata[k] = v
+	}
+	return metadata
+}
+
+// md5Hex returns the hex encoded md5 sum of all remaining bytes of r
+// and rewinds it.
+func md5Hex(r io.ReadSeeker) (string, error) {
+	h := md5.New()
+	if _, e := io.Copy(h, r); e != nil {
+		return "", e
+	}
+	if _, e := r.Seek(0, io.SeekStart); e != nil {
+		return "", e
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// isWebsiteObjectUnchanged returns true if the remote object has the
+// content of the local one. Multipart uploads have no md5 ETag, they
+// are compared by size.
+func isWebsiteObjectUnchanged(remote *ClientContent, obj websiteObject) bool {
+	if remote == nil || remote.Size != obj.size {
+		return false
+	}
+	etag := strings.Trim(remote.ETag, "\"")
+	if strings.Contains(etag, "-") {
+		return true
+	}
+	return etag == obj.md5
+}
+
+// gzipVariant compresses data, the variant is only worth serving if it
+// is smaller than the original.
+func gzipVariant(data []byte) ([]byte, bool) {
+	var buf bytes.Buffer
+	w, e := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if e != nil {
+		return nil, false
+	}
+	if _, e = w.Write(data); e != nil {
+		return nil, false
+	}
+	if e = w.Close(); e != nil {
+		return nil, false
+	}
+	return buf.Bytes(), buf.Len() < len(data)
+}
+
+// listWebsiteObjects lists the objects below the target, keyed by their
+// path relative to it.
+func listWebsiteObjects(ctx context.Context, clnt Client) (map[string]*ClientContent, *probe.Error) {
+	objects := make(map[string]*ClientContent)
+	prefix := clnt.GetURL().Path
+	for content := range clnt.List(ctx, ListOptions{Recursive: true, ShowDir: DirNone}) {
+		if content.Err != nil {
+			if _, ok := content.Err.ToGoError().(ObjectMissing); ok {
+				continue
+			}
+			return nil, content.Err
+		}
+		objects[strings.TrimPrefix(content.URL.Path, prefix)] = content
+	}
+	return objects, nil
+}
+
+// doWebsiteSync uploads all changed files of sourceDir to targetURL.
+func doWebsiteSync(ctx context.Context, sourceDir, targetURL string, opts websiteSyncOpts) (websiteSyncSummaryMessage, []string, *probe.Error) {
+	var summary websiteSyncSummaryMessage
+	var changed []string
+
+	if !strings.HasSuffix(targetURL, "/") {
+		targetURL += "/"
+	}
+	alias, targetURLFull, _ := mustExpandAlias(targetURL)
+	clnt, err := newClientFromAlias(alias, targetURLFull)
+	if err != nil {
+		return summary, nil, err.Trace(targetURL)
+	}
+	remote, err := listWebsiteObjects(ctx, clnt)
+	if err != nil {
+		return summary, nil, err.Trace(targetURL)
+	}
+
+	upload := func(obj websiteObject) *probe.Error {
+		if isWebsiteObjectUnchanged(remote[obj.key], obj) {
+			summary.Skipped++
+			return nil
+		}
+		printMsg(websiteSyncMessage{
+			Action:          "upload",
+			Key:             obj.key,
+			Size:            obj.size,
+			ContentType:     obj.metadata["Content-Type"],
+			ContentEncoding: obj.metadata["Content-Encoding"],
+			dryRun:          opts.dryRun,
+		})
+		summary.Uploaded++
+		changed = append(changed, "/"+obj.key)
+		if opts.dryRun {
+			return nil
+		}
+		_, err := putTargetStream(ctx, alias, targetURLFull+obj.key, "", "", "", obj.reader, obj.size, nil,
+			PutOptions{metadata: obj.metadata})
+		return err
+	}
+
+	expected := make(map[string]bool)
+	e := filepath.WalkDir(sourceDir, func(path string, d fs.DirEntry, e error) error {
+		if e != nil || d.IsDir() {
+			return e
+		}
+		rel, e := filepath.Rel(sourceDir, path)
+		if e != nil {
+			return e
+		}
+		key := filepath.ToSlash(rel)
+		data, e := os.ReadFile(path)
+		if e != nil {
+			return e
+		}
+		obj := websiteObject{
+			key:      key,
+			reader:   bytes.NewReader(data),
+			size:     int64(len(data)),
+			metadata: websiteHeaders(key, opts),
+		}
+		if obj.md5, e = md5Hex(obj.reader); e != nil {
+			return e
+		}
+		expected[key] = true
+		if err := upload(obj); err != nil {
+			return err.ToGoError()
+		}
+
+		if !opts.gzip || !isCompressibleContentType(obj.metadata["Content-Type"]) {
+			return nil
+		}
+		compressed, ok := gzipVariant(data)
+		if !ok {
+			return nil
+		}
+		variant := websiteObject{
+			key:      key + ".gz",
+			reader:   bytes.NewReader(compressed),
+			size:     int64(len(compressed)),
+			metadata: websiteHeaders(key, opts),
+		}
+		variant.metadata["Content-Encoding"] = "gzip"
+		if variant.md5, e = md5Hex(variant.reader); e != nil {
+			return e
+		}
+		expected[variant.key] = true
+		if err := upload(variant); err != nil {
+			return err.ToGoError()
+		}
+		return nil
+	})
+	if e != nil {
+		return summary, changed, probe.NewError(e).Trace(sourceDir, targetURL)
+	}
+
+	if !opts.remove {
+		return summary, changed, nil
+	}
+
+	var stale []string
+	for key := range remote {
+		if !expected[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+
+	contentCh := make(chan *ClientContent)
+	go func() {
+		defer close(contentCh)
+		for _, key := range stale {
+			printMsg(websiteSyncMessage{Action: "remove", Key: key, dryRun: opts.dryRun})
+			summary.Removed++
+			changed = append(changed, "/"+key)
+			if !opts.dryRun {
+				contentCh <- remote[key]
+			}
+		}
+	}()
+	for result := range clnt.Remove(ctx, false, false, false, false, contentCh) {
+		if result.Err != nil {
+			err = result.Err.Trace(targetURL)
+		}
+	}
+	return summary, changed, err
+}
+
+// checkWebsiteSyncSyntax - validate all the passed arguments
+func checkWebsiteSyncSyntax(cliCtx *cli.Context) websiteSyncOpts {
+	if len(cliCtx.Args()) != 2 {
+		showCommandHelpAndExit(cliCtx, 1) // last argument is exit code
+	}
+	sourceDir, targetURL := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+
+	if st, e := os.Stat(sourceDir); e != nil || !st.IsDir() {
+		if e == nil {
+			e = errors.New("not a folder")
+		}
+		fatalIf(probe.NewError(e).Trace(sourceDir), "Unable to use `"+sourceDir+"` as source.")
+	}
+	if _, _, hostCfg := mustExpandAlias(targetURL); hostCfg == nil {
+		fatalIf(errInvalidAliasedURL(targetURL).Trace(targetURL), "Target must be a bucket of an alias.")
+	}
+
+	headers, err := loadHeaderMap(cliCtx.String("header-map"))
+	fatalIf(err, "Unable to load --header-map.")
+
+	opts := websiteSyncOpts{
+		cacheControl:     cliCtx.String("cache-control"),
+		htmlCacheControl: cliCtx.String("html-cache-control"),
+		headers:          headers,
+		remove:           cliCtx.Bool("remove"),
+		dryRun:           cliCtx.Bool("dry-run"),
+	}
+	opts.gzip, err = parseWebsitePrecompress(cliCtx.String("precompress"))
+	fatalIf(err, "Unable to use --precompress.")
+	return opts
+}
+
+// parseWebsitePrecompress returns true if the pre-compressed variants
+// passed to --precompress are gzip ones.
+func parseWebsitePrecompress(precompress string) (bool, *probe.Error) {
+	switch precompress {
+	case "":
+		return false, nil
+	case "gzip":
+		return true, nil
+	case "br", "brotli":
+		return false, probe.NewError(errors.New("brotli pre-compression is not supported yet, use 'gzip'")).Trace(precompress)
+	}
+	return false, probe.NewError(errors.New("unknown encoding, only 'gzip' is supported")).Trace(precompress)
+}
+
+// mainWebsiteSync is the handler for "mc website sync" command.
+func mainWebsiteSync(cliCtx *cli.Context) error {
+	ctx, cancelSync := context.WithCancel(globalContext)
+	defer cancelSync()
+
+	console.SetColor("WebsiteUpload", color.New(color.FgGreen))
+	console.SetColor("WebsiteRemove", color.New(color.FgRed))
+	console.SetColor("WebsiteSummary", color.New(color.Bold))
+
+	opts := checkWebsiteSyncSyntax(cliCtx)
+	sourceDir, targetURL := cliCtx.Args().Get(0), cliCtx.Args().Get(1)
+
+	summary, changed, err := doWebsiteSync(ctx, sourceDir, targetURL, opts)
+	fatalIf(err, "Unable to sync `"+sourceDir+"` to `"+targetURL+"`.")
+
+	if manifestFile := cliCtx.String("manifest"); manifestFile != "" {
+		manifestBytes, e := json.MarshalIndent(websiteManifest{Time: time.Now().UTC(), Paths: changed}, "", " ")
+		fatalIf(probe.NewError(e), "Unable to marshal the invalidation manifest.")
+		e = os.WriteFile(manifestFile, manifestBytes, 0o644)
+		fatalIf(probe.NewError(e).Trace(manifestFile), "Unable to write the invalidation manifest.")
+		summary.Manifest = manifestFile
+	}
+	printMsg(summary)
+	return nil
+}
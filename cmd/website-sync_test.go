@@ -0,0 +1,198 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestParseWebsitePrecompress(t *testing.T) {
+	testCases := []struct {
+		precompress string
+		gzip        bool
+		valid       bool
+	}{
+		{"", false, true},
+		{"gzip", true, true},
+		{"br", false, false},
+		{"brotli", false, false},
+		{"zstd", false, false},
+	}
+	for i, testCase := range testCases {
+		gzip, err := parseWebsitePrecompress(testCase.precompress)
+		if valid := err == nil; valid != testCase.valid {
+			t.Fatalf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+		}
+		if gzip != testCase.gzip {
+			t.Errorf("Test %d: expected gzip %t, got %t", i+1, testCase.gzip, gzip)
+		}
+	}
+}
+
+func TestWebsiteHeaders(t *testing.T) {
+	opts := websiteSyncOpts{
+		cacheControl:     "public, max-age=3600",
+		htmlCacheControl: "no-cache",
+		headers: headerMap{
+			{pattern: "*.woff2", headers: map[string]string{"Cache-Control": "public, max-age=31536000, immutable"}},
+		},
+	}
+	testCases := []struct {
+		key          string
+		contentType  string
+		cacheControl string
+		compressible bool
+	}{
+		{"index.html", "text/html", "no-cache", true},
+		{"css/site.css", "text/css", "public, max-age=3600", true},
+		{"js/app.js", "application/javascript", "public, max-age=3600", true},
+		{"img/logo.svg", "image/svg+xml", "public, max-age=3600", true},
+		{"img/photo.png", "image/png", "public, max-age=3600", false},
+		{"fonts/inter.woff2", "font/woff2", "public, max-age=31536000, immutable", false},
+	}
+	for i, testCase := range testCases {
+		headers := websiteHeaders(testCase.key, opts)
+		contentType, _, _ := strings.Cut(headers["Content-Type"], ";")
+		if contentType != testCase.contentType {
+			t.Errorf("Test %d: expected Content-Type %s, got %s", i+1, testCase.contentType, headers["Content-Type"])
+		}
+		if headers["Cache-Control"] != testCase.cacheControl {
+			t.Errorf("Test %d: expected Cache-Control %s, got %s", i+1, testCase.cacheControl, headers["Cache-Control"])
+		}
+		if compressible := isCompressibleContentType(headers["Content-Type"]); compressible != testCase.compressible {
+			t.Errorf("Test %d: expected compressible %t, got %t", i+1, testCase.compressible, compressible)
+		}
+	}
+}
+
+func TestIsWebsiteObjectUnchanged(t *testing.T) {
+	md5, e := md5Hex(bytes.NewReader([]byte("hello")))
+	if e != nil {
+		t.Fatal(e)
+	}
+	obj := websiteObject{key: "index.html", size: 5, md5: md5}
+	testCases := []struct {
+		remote    *ClientContent
+		unchanged bool
+	}{
+		{nil, false},
+		{&ClientContent{Size: 5, ETag: `"` + md5 + `"`}, true},
+		{&ClientContent{Size: 5, ETag: "0123456789abcdef0123456789abcdef"}, false},
+		{&ClientContent{Size: 6, ETag: md5}, false},
+		// Multipart uploads are compared by size.
+		{&ClientContent{Size: 5, ETag: "0123456789abcdef0123456789abcdef-2"}, true},
+	}
+	for i, testCase := range testCases {
+		if unchanged := isWebsiteObjectUnchanged(testCase.remote, obj); unchanged != testCase.unchanged {
+			t.Errorf("Test %d: expected unchanged %t, got %t", i+1, testCase.unchanged, unchanged)
+		}
+	}
+}
+
+func TestGzipVariant(t *testing.T) {
+	data := []byte(strings.Repeat("<p>hello world</p>\n", 100))
+	compressed, ok := gzipVariant(data)
+	if !ok {
+		t.Fatal("expected repetitive data to compress")
+	}
+	r, e := gzip.NewReader(bytes.NewReader(compressed))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if decompressed, e := io.ReadAll(r); e != nil || !bytes.Equal(decompressed, data) {
+		t.Fatalf("unexpected gzip variant: %v", e)
+	}
+
+	if _, ok = gzipVariant([]byte("x")); ok {
+		t.Fatal("expected a variant larger than the data to be dropped")
+	}
+}
+
+func TestWebsiteSync(t *testing.T) {
+	source, target := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		"index.html":   strings.Repeat("<p>hello world</p>\n", 100),
+		"css/site.css": "body{}",
+		"img/logo.png": "png",
+	}
+	for name, data := range files {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		if e := os.MkdirAll(filepath.Dir(path), 0o755); e != nil {
+			t.Fatal(e)
+		}
+		if e := os.WriteFile(path, []byte(data), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+	if e := os.WriteFile(filepath.Join(target, "old.html"), []byte("old"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+	targetFiles := func() []string {
+		var names []string
+		filepath.WalkDir(target, func(path string, d os.DirEntry, e error) error {
+			if e == nil && !d.IsDir() {
+				rel, _ := filepath.Rel(target, path)
+				names = append(names, filepath.ToSlash(rel))
+			}
+			return e
+		})
+		sort.Strings(names)
+		return names
+	}
+
+	// A dry run changes nothing.
+	opts := websiteSyncOpts{gzip: true, remove: true, dryRun: true}
+	summary, changed, err := doWebsiteSync(context.Background(), source, target, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(targetFiles(), []string{"old.html"}) {
+		t.Fatalf("expected a dry run to change nothing, got %v", targetFiles())
+	}
+	expected := []string{"/css/site.css", "/img/logo.png", "/index.html", "/index.html.gz", "/old.html"}
+	sort.Strings(changed)
+	if !reflect.DeepEqual(changed, expected) {
+		t.Fatalf("expected %v changed, got %v", expected, changed)
+	}
+
+	// Only compressible files which shrink get a gzip variant, files
+	// removed from the source are removed from the target.
+	opts.dryRun = false
+	summary, _, err = doWebsiteSync(context.Background(), source, target, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Uploaded != 4 || summary.Removed != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if expected := []string{"css/site.css", "img/logo.png", "index.html", "index.html.gz"}; !reflect.DeepEqual(targetFiles(), expected) {
+		t.Fatalf("expected %v, got %v", expected, targetFiles())
+	}
+	if data, e := os.ReadFile(filepath.Join(target, "index.html")); e != nil || string(data) != files["index.html"] {
+		t.Fatalf("unexpected index.html: %v", e)
+	}
+}
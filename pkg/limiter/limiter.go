@@ -15,13 +15,16 @@
 // You should have received a copy of the GNU Affero General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
-// Package limiter implements throughput upload and download limits via http.RoundTripper
+// Package limiter implements throughput upload and download limits and
+// request rate limits via http.RoundTripper
 package limiter
 
 import (
 	"errors"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/juju/ratelimit"
 )
@@ -68,28 +71,80 @@ func (l limiter) RoundTrip(req *http.Request) (res *http.Response, err error) {
 	return res, err
 }
 
-// New return a ratelimited transport
+// Token buckets by rate, shared by all the transports of the process.
+var (
+	bucketsMu       sync.Mutex
+	uploadBuckets   = make(map[int64]*ratelimit.Bucket)
+	downloadBuckets = make(map[int64]*ratelimit.Bucket)
+	opsBuckets      = make(map[int64]*ratelimit.Bucket)
+)
+
+func sharedBucket(buckets map[int64]*ratelimit.Bucket, limit int64) *ratelimit.Bucket {
+	if limit <= 0 {
+		return nil
+	}
+	bucketsMu.Lock()
+	defer bucketsMu.Unlock()
+
+	b, ok := buckets[limit]
+	if !ok {
+		b = ratelimit.NewBucketWithRate(float64(limit), limit)
+		buckets[limit] = b
+	}
+	return b
+}
+
+// New return a ratelimited transport, the limits are shared by all the
+// transports created with the same limits so that they apply to all
+// the concurrent transfers rather than per connection or per client.
 func New(uploadLimit, downloadLimit int64, transport http.RoundTripper) http.RoundTripper {
 	if uploadLimit == 0 && downloadLimit == 0 {
 		return transport
 	}
 
-	var (
-		uploadBucket   *ratelimit.Bucket
-		downloadBucket *ratelimit.Bucket
-	)
+	uploadBucket := sharedBucket(uploadBuckets, uploadLimit)
+	downloadBucket := sharedBucket(downloadBuckets, downloadLimit)
 
-	if uploadLimit > 0 {
-		uploadBucket = ratelimit.NewBucketWithRate(float64(uploadLimit), uploadLimit)
+	return &limiter{
+		upload:    uploadBucket,
+		download:  downloadBucket,
+		transport: transport,
 	}
+}
 
-	if downloadLimit > 0 {
-		downloadBucket = ratelimit.NewBucketWithRate(float64(downloadLimit), downloadLimit)
+// opsLimiter delays requests to keep to a maximum number of requests
+// per second.
+type opsLimiter struct {
+	ops       *ratelimit.Bucket
+	transport http.RoundTripper // HTTP transport that needs to be intercepted
+}
+
+// RoundTrip waits for the turn of the request before executing it.
+func (l opsLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l.transport == nil {
+		return nil, errors.New("Invalid Argument")
+	}
+	if wait := l.ops.Take(1); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
 	}
+	return l.transport.RoundTrip(req)
+}
 
-	return &limiter{
-		upload:    uploadBucket,
-		download:  downloadBucket,
+// NewOps returns a transport sending at most opsLimit requests per
+// second, the limit is shared by all the transports created with the
+// same limit like the limits of New.
+func NewOps(opsLimit int64, transport http.RoundTripper) http.RoundTripper {
+	if opsLimit <= 0 {
+		return transport
+	}
+	return &opsLimiter{
+		ops:       sharedBucket(opsBuckets, opsLimit),
 		transport: transport,
 	}
 }
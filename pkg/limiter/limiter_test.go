@@ -0,0 +1,91 @@
+// Copyright (c) 2015-2023 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package limiter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewSharesBuckets(t *testing.T) {
+	transport := http.DefaultTransport
+	if New(0, 0, transport) != transport {
+		t.Fatal("expected no limits to return the transport unchanged")
+	}
+
+	first := New(1<<20, 2<<20, transport).(*limiter)
+	second := New(1<<20, 2<<20, &http.Transport{}).(*limiter)
+	if first.upload != second.upload || first.download != second.download {
+		t.Fatal("expected transports with the same limits to share their buckets")
+	}
+
+	other := New(3<<20, 0, transport).(*limiter)
+	if other.upload == first.upload {
+		t.Fatal("expected transports with different limits to use different buckets")
+	}
+	if other.download != nil {
+		t.Fatal("expected no download bucket without a download limit")
+	}
+	if first.upload == first.download {
+		t.Fatal("expected upload and download to use different buckets")
+	}
+}
+
+func TestNewOps(t *testing.T) {
+	transport := http.DefaultTransport
+	if NewOps(0, transport) != transport {
+		t.Fatal("expected no limit to return the transport unchanged")
+	}
+	if NewOps(7, transport).(*opsLimiter).ops != NewOps(7, &http.Transport{}).(*opsLimiter).ops {
+		t.Fatal("expected transports with the same limit to share their bucket")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: NewOps(10, http.DefaultTransport)}
+
+	// The first second of requests is sent at once, the following at
+	// the limited rate.
+	start := time.Now()
+	for i := 0; i < 15; i++ {
+		res, e := client.Get(server.URL)
+		if e != nil {
+			t.Fatal(e)
+		}
+		res.Body.Close()
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("expected 15 requests at 10 per second to take about 500ms, took %s", elapsed)
+	}
+
+	// Waiting requests give up with their context.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 10; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		res, e := client.Do(req)
+		if e != nil {
+			return
+		}
+		res.Body.Close()
+	}
+	t.Fatal("expected a request waiting past its deadline to fail")
+}